@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func consume(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("consume", flag.ExitOnError)
+	var (
+		topics = fs.String("topics", "", "comma delimited list of topics to consume")
+		regex  = fs.Bool("regex", false, "if true, -topics are regular expressions")
+		group  = fs.String("group", "", "if non-empty, group to consume in (committing offsets as records are printed)")
+		format = fs.String("format", "%v\n", "format to print records with; see kgo.NewRecordFormatter (i.e., '%t[%p]@%o %k %v\\n')")
+		offset = fs.String("offset", "start", "where to start consuming if there is no commit: start, end, an exact offset, or @<unix millis>")
+		num    = fs.Int("n", 0, "if positive, quit after printing this many records")
+	)
+	parse(fs, args, "consume -topics <topics> [flags]")
+
+	if *topics == "" {
+		die("missing -topics")
+	}
+	opts := []kgo.Opt{
+		kgo.ConsumeTopics(strings.Split(*topics, ",")...),
+		kgo.ConsumeResetOffset(parseOffset(*offset)),
+	}
+	if *regex {
+		opts = append(opts, kgo.ConsumeRegex())
+	}
+	if *group != "" {
+		opts = append(opts, kgo.ConsumerGroup(*group), kgo.AutoCommitMarks())
+	}
+	cl := newClient(opts...)
+	defer cl.Close()
+
+	formatter, err := kgo.NewRecordFormatter(*format)
+	if err != nil {
+		die("invalid -format: %v", err)
+	}
+
+	var (
+		buf     []byte
+		printed int
+	)
+	for *num <= 0 || printed < *num {
+		fetches := cl.PollFetches(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		fetches.EachError(func(t string, p int32, err error) {
+			if errors.Is(err, kgo.ErrClientClosed) || errors.Is(err, context.Canceled) {
+				return
+			}
+			die("topic %s partition %d had error: %v", t, p, err)
+		})
+		fetches.EachRecord(func(r *kgo.Record) {
+			if *num > 0 && printed >= *num {
+				return
+			}
+			buf = formatter.AppendRecord(buf[:0], r)
+			os.Stdout.Write(buf)
+			printed++
+			if *group != "" {
+				cl.MarkCommitRecords(r)
+			}
+		})
+	}
+	if *group != "" {
+		if err := cl.CommitMarkedOffsets(context.Background()); err != nil {
+			die("unable to commit printed records: %v", err)
+		}
+	}
+}
+
+// parseOffset parses start, end, an exact offset, or @<unix millis>.
+func parseOffset(s string) kgo.Offset {
+	switch {
+	case s == "start":
+		return kgo.NewOffset().AtStart()
+	case s == "end":
+		return kgo.NewOffset().AtEnd()
+	case strings.HasPrefix(s, "@"):
+		millis, err := strconv.ParseInt(s[1:], 10, 64)
+		if err != nil {
+			die("invalid timestamp offset %q: %v", s, err)
+		}
+		return kgo.NewOffset().AfterMilli(millis)
+	default:
+		at, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			die("invalid offset %q: expected start, end, an exact offset, or @<unix millis>", s)
+		}
+		return kgo.NewOffset().At(at)
+	}
+}
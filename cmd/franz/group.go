@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+)
+
+func group(ctx context.Context, args []string) {
+	sub, args := subcommand("group", args, "list", "describe", "reset")
+	switch sub {
+	case "list":
+		groupList(ctx, args)
+	case "describe":
+		groupDescribe(ctx, args)
+	case "reset":
+		groupReset(ctx, args)
+	default:
+		die("unknown group subcommand %q", sub)
+	}
+}
+
+func groupList(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("group list", flag.ExitOnError)
+	states := fs.String("states", "", "if non-empty, comma delimited list of group states to filter for (i.e., Stable,Empty)")
+	parse(fs, args, "group list [flags]")
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	var filter []string
+	if *states != "" {
+		filter = strings.Split(*states, ",")
+	}
+	groups, err := adm.ListGroups(ctx, filter...)
+	if err != nil {
+		die("unable to list groups: %v", err)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintf(tw, "GROUP\tCOORDINATOR\tPROTOCOL-TYPE\tSTATE\n")
+	for _, g := range groups.Sorted() {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", g.Group, g.Coordinator, g.ProtocolType, g.State)
+	}
+}
+
+func groupDescribe(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("group describe", flag.ExitOnError)
+	parse(fs, args, "group describe <groups...>")
+	if fs.NArg() == 0 {
+		die("missing groups to describe")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	lags, err := adm.Lag(ctx, fs.Args()...)
+	if err != nil {
+		die("unable to describe groups: %v", err)
+	}
+	for i, l := range lags.Sorted() {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("GROUP        %s\n", l.Group)
+		if err := l.Error(); err != nil {
+			fmt.Printf("ERROR        %v\n", err)
+			continue
+		}
+		fmt.Printf("COORDINATOR  %d\n", l.Coordinator.NodeID)
+		fmt.Printf("STATE        %s\n", l.State)
+		fmt.Printf("BALANCER     %s\n", l.Protocol)
+		fmt.Printf("MEMBERS      %d\n", len(l.Members))
+		fmt.Printf("TOTAL-LAG    %d\n\n", l.Lag.Total())
+
+		tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "TOPIC\tPARTITION\tCURRENT-OFFSET\tLOG-END-OFFSET\tLAG\tMEMBER-ID\tCLIENT-ID\tHOST\tERROR\n")
+		for _, m := range l.Lag.Sorted() {
+			var memberID, clientID, host string
+			if !m.IsEmpty() {
+				memberID, clientID, host = m.Member.MemberID, m.Member.ClientID, m.Member.ClientHost
+			}
+			var errStr string
+			if m.Err != nil {
+				errStr = m.Err.Error()
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%s\t%s\t%s\t%s\n",
+				m.Topic, m.Partition, m.Commit.At, m.End.Offset, m.Lag, memberID, clientID, host, errStr)
+		}
+		tw.Flush()
+	}
+}
+
+func groupReset(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("group reset", flag.ExitOnError)
+	var (
+		group  = fs.String("group", "", "group to reset offsets for")
+		topics = fs.String("topics", "", "comma delimited list of topics to reset")
+		to     = fs.String("to", "", "where to reset to: start, end, an exact offset, or @<unix millis>")
+		dry    = fs.Bool("dry-run", false, "if true, print the offsets that would be committed without committing")
+	)
+	parse(fs, args, "group reset -group <group> -topics <topics> -to <start|end|offset|@millis> [flags]")
+	if *group == "" || *topics == "" || *to == "" {
+		die("all of -group, -topics, and -to are required")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	ts := strings.Split(*topics, ",")
+	var (
+		listed kadm.ListedOffsets
+		exact  int64 = -1
+		err    error
+	)
+	switch {
+	case *to == "start":
+		listed, err = adm.ListStartOffsets(ctx, ts...)
+	case *to == "end":
+		listed, err = adm.ListEndOffsets(ctx, ts...)
+	case strings.HasPrefix(*to, "@"):
+		millis, perr := strconv.ParseInt((*to)[1:], 10, 64)
+		if perr != nil {
+			die("invalid -to timestamp %q: %v", *to, perr)
+		}
+		listed, err = adm.ListOffsetsAfterMilli(ctx, millis, ts...)
+	default:
+		exact, err = strconv.ParseInt(*to, 10, 64)
+		if err != nil || exact < 0 {
+			die("invalid -to %q: expected start, end, an exact offset, or @<unix millis>", *to)
+		}
+		listed, err = adm.ListEndOffsets(ctx, ts...)
+	}
+	if err != nil {
+		die("unable to list offsets: %v", err)
+	}
+	if err := listed.Error(); err != nil {
+		die("unable to list offsets: %v", err)
+	}
+
+	offsets := listed.Offsets()
+	if exact >= 0 {
+		// The end offsets are only used to know the partitions of each
+		// topic; we reset every partition to the exact offset.
+		offsets.Each(func(o kadm.Offset) {
+			offsets.AddOffset(o.Topic, o.Partition, exact, -1)
+		})
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "TOPIC\tPARTITION\tNEW-OFFSET\n")
+	for _, o := range offsets.Sorted() {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", o.Topic, o.Partition, o.At)
+	}
+	tw.Flush()
+	if *dry {
+		return
+	}
+	if err := adm.CommitAllOffsets(ctx, *group, offsets); err != nil {
+		die("unable to commit offsets: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+// Command franz is a small command line client for Kafka built on kgo, kadm,
+// and kmsg. It can produce and consume records, describe and reset consumer
+// groups, manage topics, and issue any raw Kafka request from JSON.
+//
+// Run franz -help for usage.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+const usage = `franz is a command line client for Kafka.
+
+Usage:
+
+	franz [global flags] <command> [command flags] [args]
+
+Commands:
+
+	produce            produce records read from stdin
+	consume            consume records and print them to stdout
+	group list         list groups
+	group describe     describe groups and their lag
+	group reset        reset a group's committed offsets
+	topic list         list topics
+	topic describe     describe topics and their partitions
+	topic create       create topics
+	topic delete       delete topics
+	topic alter        alter topic configs
+	topic partitions   add partitions to topics
+	request            issue a raw Kafka request read as JSON from stdin
+
+Run franz <command> -help for command flags.
+
+Global flags:
+`
+
+var (
+	seedBrokers = flag.String("brokers", envOr("FRANZ_BROKERS", "localhost:9092"), "comma delimited list of seed brokers (or $FRANZ_BROKERS)")
+	dialTLS     = flag.Bool("tls", false, "if true, use tls for connecting (if using well-known TLS certs)")
+	saslMethod  = flag.String("sasl-method", "", "if non-empty, sasl method to use (must specify all options; supports plain, scram-sha-256, scram-sha-512)")
+	saslUser    = flag.String("sasl-user", "", "if non-empty, username to use for sasl (must specify all options)")
+	saslPass    = flag.String("sasl-pass", "", "if non-empty, password to use for sasl (must specify all options)")
+	logLevel    = flag.String("log-level", "", "if non-empty, use a basic logger with this log level (debug, info, warn, error)")
+)
+
+func envOr(env, def string) string {
+	if v := os.Getenv(env); v != "" {
+		return v
+	}
+	return def
+}
+
+func die(msg string, args ...any) {
+	fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "produce":
+		produce(ctx, args)
+	case "consume":
+		consume(ctx, args)
+	case "group":
+		group(ctx, args)
+	case "topic":
+		topic(ctx, args)
+	case "request":
+		request(ctx, args)
+	default:
+		die("unknown command %q; run franz -help for usage", cmd)
+	}
+}
+
+// commonOpts returns client options from the global flags.
+func commonOpts() []kgo.Opt {
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(strings.Split(*seedBrokers, ",")...),
+	}
+	if *dialTLS {
+		opts = append(opts, kgo.DialTLSConfig(new(tls.Config)))
+	}
+
+	switch strings.ToLower(*logLevel) {
+	case "":
+	case "debug":
+		opts = append(opts, kgo.WithLogger(kgo.BasicLogger(os.Stderr, kgo.LogLevelDebug, nil)))
+	case "info":
+		opts = append(opts, kgo.WithLogger(kgo.BasicLogger(os.Stderr, kgo.LogLevelInfo, nil)))
+	case "warn":
+		opts = append(opts, kgo.WithLogger(kgo.BasicLogger(os.Stderr, kgo.LogLevelWarn, nil)))
+	case "error":
+		opts = append(opts, kgo.WithLogger(kgo.BasicLogger(os.Stderr, kgo.LogLevelError, nil)))
+	default:
+		die("unrecognized log level %s", *logLevel)
+	}
+
+	if *saslMethod != "" || *saslUser != "" || *saslPass != "" {
+		if *saslMethod == "" || *saslUser == "" || *saslPass == "" {
+			die("all of -sasl-method, -sasl-user, -sasl-pass must be specified if any are")
+		}
+		method := strings.ToLower(*saslMethod)
+		method = strings.ReplaceAll(method, "-", "")
+		method = strings.ReplaceAll(method, "_", "")
+		switch method {
+		case "plain":
+			opts = append(opts, kgo.SASL(plain.Auth{
+				User: *saslUser,
+				Pass: *saslPass,
+			}.AsMechanism()))
+		case "scramsha256":
+			opts = append(opts, kgo.SASL(scram.Auth{
+				User: *saslUser,
+				Pass: *saslPass,
+			}.AsSha256Mechanism()))
+		case "scramsha512":
+			opts = append(opts, kgo.SASL(scram.Auth{
+				User: *saslUser,
+				Pass: *saslPass,
+			}.AsSha512Mechanism()))
+		default:
+			die("unrecognized sasl option %s", *saslMethod)
+		}
+	}
+	return opts
+}
+
+// newClient returns a client using the global flags and any extra options.
+func newClient(extra ...kgo.Opt) *kgo.Client {
+	cl, err := kgo.NewClient(append(commonOpts(), extra...)...)
+	if err != nil {
+		die("unable to create client: %v", err)
+	}
+	return cl
+}
+
+// newAdm returns an admin client using the global flags.
+func newAdm() (*kgo.Client, *kadm.Client) {
+	cl := newClient()
+	return cl, kadm.NewClient(cl)
+}
+
+// subcommand splits a subcommand from its arguments.
+func subcommand(cmd string, args []string, subs ...string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		die("missing %s subcommand (one of %s)", cmd, strings.Join(subs, ", "))
+	}
+	return args[0], args[1:]
+}
+
+// parse parses command flags, printing a usage line on -help.
+func parse(fs *flag.FlagSet, args []string, usage string) {
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: franz %s\n\nFlags:\n", usage)
+		fs.PrintDefaults()
+	}
+	fs.Parse(args) // ExitOnError: errors exit
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func produce(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("produce", flag.ExitOnError)
+	var (
+		topic  = fs.String("topic", "", "topic to produce to, if the format does not contain %t")
+		format = fs.String("format", "%v\n", "format to parse records from stdin with; see kgo.NewRecordReader (i.e., '%k %v\\n' for space delimited keys and values)")
+		acks   = fs.Int("acks", -1, "acks required; 0, -1, 1")
+	)
+	parse(fs, args, "produce -topic <topic> [flags] < input")
+
+	opts := []kgo.Opt{kgo.DefaultProduceTopic(*topic)}
+	switch *acks {
+	case 0:
+		opts = append(opts, kgo.RequiredAcks(kgo.NoAck()), kgo.DisableIdempotentWrite())
+	case 1:
+		opts = append(opts, kgo.RequiredAcks(kgo.LeaderAck()), kgo.DisableIdempotentWrite())
+	default:
+		opts = append(opts, kgo.RequiredAcks(kgo.AllISRAcks()))
+	}
+	cl := newClient(opts...)
+	defer cl.Close()
+
+	reader, err := kgo.NewRecordReader(os.Stdin, *format)
+	if err != nil {
+		die("invalid -format: %v", err)
+	}
+
+	var (
+		produced atomic.Int64
+		errMu    sync.Mutex
+		firstErr error
+	)
+	for {
+		r, err := reader.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			die("unable to read record: %v", err)
+		}
+		if r.Topic == "" && *topic == "" {
+			die("missing -topic and no topic in the -format")
+		}
+		cl.Produce(ctx, r, func(_ *kgo.Record, err error) {
+			if err == nil {
+				produced.Add(1)
+				return
+			}
+			errMu.Lock()
+			defer errMu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		})
+	}
+	if err := cl.Flush(ctx); err != nil {
+		die("unable to flush: %v", err)
+	}
+	if firstErr != nil {
+		die("produced %d records; first error: %v", produced.Load(), firstErr)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func topic(ctx context.Context, args []string) {
+	sub, args := subcommand("topic", args, "list", "describe", "create", "delete", "alter", "partitions")
+	switch sub {
+	case "list":
+		topicList(ctx, args)
+	case "describe":
+		topicDescribe(ctx, args)
+	case "create":
+		topicCreate(ctx, args)
+	case "delete":
+		topicDelete(ctx, args)
+	case "alter":
+		topicAlter(ctx, args)
+	case "partitions":
+		topicPartitions(ctx, args)
+	default:
+		die("unknown topic subcommand %q", sub)
+	}
+}
+
+// configFlag is a repeatable k=v flag.
+type configFlag map[string]*string
+
+func (c configFlag) String() string { return "" }
+
+func (c configFlag) Set(kv string) error {
+	k, v, ok := strings.Cut(kv, "=")
+	if !ok {
+		return fmt.Errorf("config %q is not in the form key=value", kv)
+	}
+	c[k] = kadm.StringPtr(v)
+	return nil
+}
+
+func topicList(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic list", flag.ExitOnError)
+	internal := fs.Bool("internal", false, "if true, include internal topics")
+	parse(fs, args, "topic list [flags]")
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	list := adm.ListTopics
+	if *internal {
+		list = adm.ListTopicsWithInternal
+	}
+	topics, err := list(ctx)
+	if err != nil {
+		die("unable to list topics: %v", err)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+	defer tw.Flush()
+	fmt.Fprintf(tw, "TOPIC\tID\tPARTITIONS\tREPLICAS\tERROR\n")
+	for _, t := range topics.Sorted() {
+		var rf int
+		if p, ok := t.Partitions[0]; ok {
+			rf = len(p.Replicas)
+		}
+		var errStr string
+		if t.Err != nil {
+			errStr = t.Err.Error()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", t.Topic, t.ID, len(t.Partitions), rf, errStr)
+	}
+}
+
+func topicDescribe(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic describe", flag.ExitOnError)
+	parse(fs, args, "topic describe <topics...>")
+	if fs.NArg() == 0 {
+		die("missing topics to describe")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	topics, err := adm.ListTopics(ctx, fs.Args()...)
+	if err != nil {
+		die("unable to describe topics: %v", err)
+	}
+	configs, err := adm.DescribeTopicConfigs(ctx, fs.Args()...)
+	if err != nil {
+		die("unable to describe topic configs: %v", err)
+	}
+
+	for i, t := range topics.Sorted() {
+		if i > 0 {
+			fmt.Println()
+		}
+		fmt.Printf("TOPIC       %s\n", t.Topic)
+		if t.Err != nil {
+			fmt.Printf("ERROR       %v\n", t.Err)
+			continue
+		}
+		fmt.Printf("ID          %s\n", t.ID)
+		fmt.Printf("INTERNAL    %v\n", t.IsInternal)
+		fmt.Printf("PARTITIONS  %d\n\n", len(t.Partitions))
+
+		tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+		fmt.Fprintf(tw, "PARTITION\tLEADER\tEPOCH\tREPLICAS\tISR\tOFFLINE\tERROR\n")
+		for _, p := range t.Partitions.Sorted() {
+			var errStr string
+			if p.Err != nil {
+				errStr = p.Err.Error()
+			}
+			fmt.Fprintf(tw, "%d\t%d\t%d\t%v\t%v\t%v\t%s\n", p.Partition, p.Leader, p.LeaderEpoch, p.Replicas, p.ISR, p.OfflineReplicas, errStr)
+		}
+		tw.Flush()
+
+		if rc, err := configs.On(t.Topic, nil); err == nil && rc.Err == nil {
+			fmt.Println()
+			tw := tabwriter.NewWriter(os.Stdout, 6, 4, 2, ' ', 0)
+			fmt.Fprintf(tw, "CONFIG\tVALUE\tSOURCE\n")
+			for _, c := range rc.Configs {
+				if c.Source == kmsg.ConfigSourceDefaultConfig {
+					continue
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", c.Key, c.MaybeValue(), c.Source)
+			}
+			tw.Flush()
+		}
+	}
+}
+
+func topicCreate(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic create", flag.ExitOnError)
+	var (
+		partitions = fs.Int("p", -1, "number of partitions, or -1 for the broker default")
+		replicas   = fs.Int("r", -1, "replication factor, or -1 for the broker default")
+		configs    = make(configFlag)
+	)
+	fs.Var(configs, "c", "key=value topic config; may be repeated")
+	parse(fs, args, "topic create [flags] <topics...>")
+	if fs.NArg() == 0 {
+		die("missing topics to create")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	resps, err := adm.CreateTopics(ctx, int32(*partitions), int16(*replicas), configs, fs.Args()...)
+	if err != nil {
+		die("unable to create topics: %v", err)
+	}
+	failed := false
+	for _, r := range resps.Sorted() {
+		if r.Err != nil {
+			failed = true
+			fmt.Printf("%s: %v %s\n", r.Topic, r.Err, r.ErrMessage)
+			continue
+		}
+		fmt.Printf("%s: created (%d partitions, replication factor %d)\n", r.Topic, r.NumPartitions, r.ReplicationFactor)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func topicDelete(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic delete", flag.ExitOnError)
+	parse(fs, args, "topic delete <topics...>")
+	if fs.NArg() == 0 {
+		die("missing topics to delete")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	resps, err := adm.DeleteTopics(ctx, fs.Args()...)
+	if err != nil {
+		die("unable to delete topics: %v", err)
+	}
+	failed := false
+	for _, r := range resps.Sorted() {
+		if r.Err != nil {
+			failed = true
+			fmt.Printf("%s: %v %s\n", r.Topic, r.Err, r.ErrMessage)
+			continue
+		}
+		fmt.Printf("%s: deleted\n", r.Topic)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func topicAlter(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic alter", flag.ExitOnError)
+	var (
+		set = make(configFlag)
+		del = fs.String("delete", "", "comma delimited list of configs to delete (reset to the default)")
+	)
+	fs.Var(set, "set", "key=value topic config to set; may be repeated")
+	parse(fs, args, "topic alter [flags] <topics...>")
+	if fs.NArg() == 0 {
+		die("missing topics to alter")
+	}
+
+	var alters []kadm.AlterConfig
+	for k, v := range set {
+		alters = append(alters, kadm.AlterConfig{Op: kadm.SetConfig, Name: k, Value: v})
+	}
+	if *del != "" {
+		for _, k := range strings.Split(*del, ",") {
+			alters = append(alters, kadm.AlterConfig{Op: kadm.DeleteConfig, Name: k})
+		}
+	}
+	if len(alters) == 0 {
+		die("missing -set or -delete configs")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	resps, err := adm.AlterTopicConfigs(ctx, alters, fs.Args()...)
+	if err != nil {
+		die("unable to alter topic configs: %v", err)
+	}
+	failed := false
+	for _, r := range resps {
+		if r.Err != nil {
+			failed = true
+			fmt.Printf("%s: %v %s\n", r.Name, r.Err, r.ErrMessage)
+			continue
+		}
+		fmt.Printf("%s: altered\n", r.Name)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func topicPartitions(ctx context.Context, args []string) {
+	fs := flag.NewFlagSet("topic partitions", flag.ExitOnError)
+	add := fs.Int("add", 0, "number of partitions to add")
+	parse(fs, args, "topic partitions -add <n> <topics...>")
+	if fs.NArg() == 0 || *add <= 0 {
+		die("missing topics or a positive -add")
+	}
+
+	cl, adm := newAdm()
+	defer cl.Close()
+
+	resps, err := adm.CreatePartitions(ctx, *add, fs.Args()...)
+	if err != nil {
+		die("unable to add partitions: %v", err)
+	}
+	failed := false
+	for _, r := range resps.Sorted() {
+		if r.Err != nil {
+			failed = true
+			fmt.Printf("%s: %v %s\n", r.Topic, r.Err, r.ErrMessage)
+			continue
+		}
+		fmt.Printf("%s: added %d partitions\n", r.Topic, *add)
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,14 @@
+module kbench
+
+go 1.25.0
+
+require github.com/twmb/franz-go v1.18.1
+
+require (
+	github.com/klauspost/compress v1.18.0 // indirect
+	github.com/pierrec/lz4/v4 v4.1.22 // indirect
+	github.com/twmb/franz-go/pkg/kmsg v1.12.0 // indirect
+	golang.org/x/crypto v0.43.0 // indirect
+)
+
+replace github.com/twmb/franz-go => ../..
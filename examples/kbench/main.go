@@ -0,0 +1,115 @@
+// This example runs a kbench producer or consumer benchmark, printing the
+// throughput and latency of every interval and a summary at the end.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kbench"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+var (
+	seedBrokers = flag.String("brokers", "localhost:9092", "comma delimited list of seed brokers")
+	topic       = flag.String("topic", "", "topic to produce to or consume from")
+
+	consume = flag.Bool("consume", false, "if true, consume rather than produce")
+	group   = flag.String("group", "", "if non-empty, group to use for consuming rather than direct partition consuming (consuming)")
+
+	records  = flag.Int64("records", 0, "number of records to produce or consume (0 for no limit)")
+	duration = flag.Duration("duration", 0, "how long to produce or consume for (0 for no limit)")
+	interval = flag.Duration("interval", 5*time.Second, "how often to print interval results")
+
+	valueBytes    = flag.Int("value-bytes", 100, "bytes per record value (producing)")
+	valueBytesMax = flag.Int("value-bytes-max", 0, "if larger than -value-bytes, values are uniformly sized between the two (producing)")
+	keys          = flag.Int("keys", 0, "number of distinct keys to produce with, 0 for no keys (producing)")
+	compression   = flag.String("compression", "", "compression algorithm to use (none,gzip,snappy,lz4,zstd, for producing)")
+	rate          = flag.Float64("rate", 0, "records per second to produce, 0 for as fast as possible (producing)")
+	arrival       = flag.String("arrival", "constant", "arrival distribution when using -rate (constant,poisson, for producing)")
+	e2e           = flag.Bool("e2e", false, "if true, add end to end latency headers to produced records, allowing consumers to measure end to end latency (producing)")
+)
+
+func die(msg string, args ...any) {
+	fmt.Fprintf(os.Stderr, msg+"\n", args...)
+	os.Exit(1)
+}
+
+func main() {
+	flag.Parse()
+	if *topic == "" {
+		die("missing required -topic")
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	onInterval := func(r kbench.Result) { fmt.Println(r) }
+	opts := []kgo.Opt{kgo.SeedBrokers(strings.Split(*seedBrokers, ",")...)}
+
+	var (
+		res kbench.Result
+		err error
+	)
+	if *consume {
+		res, err = kbench.Consume(ctx, kbench.ConsumeConfig{
+			Topics:     []string{*topic},
+			Group:      *group,
+			Records:    *records,
+			Duration:   *duration,
+			Interval:   *interval,
+			OnInterval: onInterval,
+		}, opts...)
+	} else {
+		cfg := kbench.ProduceConfig{
+			Topic:              *topic,
+			Records:            *records,
+			Duration:           *duration,
+			ValueBytes:         *valueBytes,
+			ValueBytesMax:      *valueBytesMax,
+			KeyCardinality:     *keys,
+			EndToEndTimestamps: *e2e,
+			Interval:           *interval,
+			OnInterval:         onInterval,
+		}
+		switch strings.ToLower(*compression) {
+		case "":
+		case "none":
+			cfg.Compression = []kgo.CompressionCodec{kgo.NoCompression()}
+		case "gzip":
+			cfg.Compression = []kgo.CompressionCodec{kgo.GzipCompression()}
+		case "snappy":
+			cfg.Compression = []kgo.CompressionCodec{kgo.SnappyCompression()}
+		case "lz4":
+			cfg.Compression = []kgo.CompressionCodec{kgo.Lz4Compression()}
+		case "zstd":
+			cfg.Compression = []kgo.CompressionCodec{kgo.ZstdCompression()}
+		default:
+			die("unrecognized compression %s", *compression)
+		}
+		if *rate > 0 {
+			switch strings.ToLower(*arrival) {
+			case "constant":
+				cfg.Arrival = kbench.Constant(*rate)
+			case "poisson":
+				cfg.Arrival = kbench.Poisson(*rate)
+			default:
+				die("unrecognized arrival %s", *arrival)
+			}
+		}
+		res, err = kbench.Produce(ctx, cfg, opts...)
+	}
+	if err != nil {
+		die("benchmark failed: %v", err)
+	}
+
+	fmt.Println("total:", res)
+	if res.FirstErr != nil {
+		fmt.Println("first error:", res.FirstErr)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+// This file contains the generation of fluent builders and nil-safe getters,
+// which are output instead of the main generated code when the generator is
+// run with -builders.
+
+import "strings"
+
+// article returns "a" or "an" for use before name in doc comments.
+func article(name string) string {
+	if name != "" && strings.ContainsRune("AEIOU", rune(name[0])) {
+		return "an"
+	}
+	return "a"
+}
+
+func (s Struct) WriteBuilder(l *LineWriter) {
+	b := s.Name + "Builder"
+	a := article(s.Name)
+	l.Write("// %s is a fluent builder for %s %s.", b, a, s.Name)
+	l.Write("type %s struct{ v %s }", b, s.Name)
+	l.Write("")
+	l.Write("// New%s returns a builder for %s %s with all defaults set.", b, a, s.Name)
+	l.Write("func New%s() *%s {", b, b)
+	l.Write("b := new(%s)", b)
+	l.Write("b.v.Default()")
+	l.Write("return b")
+	l.Write("}")
+	l.Write("")
+
+	if s.TopLevel {
+		l.Write("// Version sets the %s's Version.", s.Name)
+		l.Write("func (b *%s) Version(v int16) *%s {", b, b)
+		l.Write("b.v.Version = v")
+		l.Write("return b")
+		l.Write("}")
+		l.Write("")
+	}
+	for _, f := range s.Fields {
+		l.Write("// %s sets the %s's %s.", f.FieldName, s.Name, f.FieldName)
+		l.Write("func (b *%s) %s(v %s) *%s {", b, f.FieldName, f.Type.TypeName(), b)
+		l.Write("b.v.%s = v", f.FieldName)
+		l.Write("return b")
+		l.Write("}")
+		l.Write("")
+	}
+
+	if s.TopLevel {
+		l.Write("// Build returns a pointer to a copy of the built %s.", s.Name)
+		l.Write("func (b *%s) Build() *%s {", b, s.Name)
+		l.Write("v := b.v")
+		l.Write("return &v")
+		l.Write("}")
+	} else {
+		l.Write("// Build returns a copy of the built %s.", s.Name)
+		l.Write("func (b *%s) Build() %s { return b.v }", b, s.Name)
+	}
+	l.Write("")
+}
+
+func (s Struct) WriteGetters(l *LineWriter) {
+	for _, f := range s.Fields {
+		typ := f.Type.TypeName()
+		l.Write("// Get%s returns v's %s, or the zero value if v is nil.", f.FieldName, f.FieldName)
+		l.Write("func (v *%s) Get%s() %s {", s.Name, f.FieldName, typ)
+		l.Write("if v == nil {")
+		l.Write("var z %s", typ)
+		l.Write("return z")
+		l.Write("}")
+		l.Write("return v.%s", f.FieldName)
+		l.Write("}")
+		l.Write("")
+	}
+}
+
+func writeBuilders(l *LineWriter) {
+	l.Write("// Code generated by franz-go/generate -builders. DO NOT EDIT.")
+	l.Write("")
+	l.Write("package kmsg")
+	l.Write("")
+	for _, s := range newStructs {
+		s.WriteBuilder(l)
+		s.WriteGetters(l)
+	}
+}
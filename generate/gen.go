@@ -559,7 +559,7 @@ func (s Struct) WriteDecode(l *LineWriter) {
 
 	l.Write("if isFlexible {")
 	if len(tags) == 0 {
-		l.Write("s.UnknownTags = internalReadTags(&b)")
+		l.Write("s.UnknownTags = internalReadTags(&b, unsafe)")
 		l.Write("}")
 		return
 	}
@@ -572,7 +572,7 @@ func (s Struct) WriteDecode(l *LineWriter) {
 	defer l.Write("}")
 
 	l.Write("default:")
-	l.Write("s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))")
+	l.Write("s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)")
 
 	for i := range len(tags) {
 		f, exists := tags[i]
@@ -581,7 +581,7 @@ func (s Struct) WriteDecode(l *LineWriter) {
 		}
 
 		l.Write("case %d:", i)
-		l.Write("b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}")
+		l.Write("b := b.Nested(b.Span(int(b.Uvarint())))")
 		f.WriteDecode(l)
 		l.Write("if err := b.Complete(); err != nil {")
 		l.Write("return err")
@@ -756,18 +756,202 @@ func (s Struct) WriteAppendFunc(l *LineWriter) {
 	l.Write("}")
 }
 
+func (s Struct) WriteApproxSizeFunc(l *LineWriter) {
+	l.Write("// ApproxSize returns the approximate size of v encoded at the given")
+	l.Write("// version, not including the request or response header. This does not")
+	l.Write("// serialize v. The size is exact except for tagged fields: every tagged")
+	l.Write("// field valid at the version is counted even if it would be skipped for")
+	l.Write("// being its default, making the size a slight overestimate at worst.")
+	l.Write("func (v *%s) ApproxSize(version int16) int {", s.Name)
+	l.Write("_ = version")
+	if s.FlexibleAt >= 0 {
+		l.Write("isFlexible := version >= %d", s.FlexibleAt)
+		l.Write("_ = isFlexible")
+	}
+	l.Write("var n int")
+	s.WriteSize(l)
+	l.Write("return n")
+	l.Write("}")
+}
+
+// compactSize writes the size of a length prefix that is compact in flexible
+// versions. Compact lengths are uvarint(len+1), while non-compact lengths are
+// fixed at size bytes.
+func compactSize(fromFlexible bool, length string, size int, l *LineWriter) {
+	if fromFlexible {
+		l.Write("if isFlexible {")
+		l.Write("n += kbin.UvarintLen(uint32(%s + 1))", length)
+		l.Write("} else {")
+		defer l.Write("}")
+	}
+	l.Write("n += %d", size)
+}
+
+// fixedSize returns the encoded size of the type if the size does not depend
+// on the value.
+func fixedSize(t Type) (int, bool) {
+	switch t := t.(type) {
+	case Bool, Int8:
+		return 1, true
+	case Int16, Uint16:
+		return 2, true
+	case Int32, Uint32, Throttle, Timeout:
+		return 4, true
+	case Int64, Float64:
+		return 8, true
+	case Uuid:
+		return 16, true
+	case Enum:
+		return fixedSize(t.Type)
+	}
+	return 0, false
+}
+
+func uvarintLen(i int) int {
+	n := 1
+	for ; i >= 0x80; i >>= 7 {
+		n++
+	}
+	return n
+}
+
+// writeSize writes the size of the type in variable v to n.
+func writeSize(t Type, l *LineWriter) {
+	switch t := t.(type) {
+	case Varint:
+		l.Write("n += kbin.VarintLen(v)")
+	case Varlong:
+		l.Write("n += kbin.VarlongLen(v)")
+	case VarintString:
+		l.Write("n += kbin.VarintLen(int32(len(v))) + len(v)")
+	case VarintBytes:
+		l.Write("n += kbin.VarintLen(int32(len(v))) + len(v)")
+	case FieldLengthMinusBytes:
+		l.Write("n += len(v)")
+	case String:
+		compactSize(t.FromFlexible, "len(v)", 2, l)
+		l.Write("n += len(v)")
+	case NullableString:
+		// A null string has the same encoded size as an empty
+		// string, regardless of the nullable version.
+		l.Write("{")
+		l.Write("var vv string")
+		l.Write("if v != nil {")
+		l.Write("vv = *v")
+		l.Write("}")
+		compactSize(t.FromFlexible, "len(vv)", 2, l)
+		l.Write("n += len(vv)")
+		l.Write("}")
+	case Bytes:
+		compactSize(t.FromFlexible, "len(v)", 4, l)
+		l.Write("n += len(v)")
+	case NullableBytes: // null has the same encoded size as empty
+		compactSize(t.FromFlexible, "len(v)", 4, l)
+		l.Write("n += len(v)")
+	case Array:
+		if t.IsVarintArray {
+			l.Write("n += kbin.VarintLen(int32(len(v)))")
+		} else {
+			// Null arrays have the same encoded size as empty arrays.
+			compactSize(t.FromFlexible, "len(v)", 4, l)
+		}
+		if size, fixed := fixedSize(t.Inner); fixed {
+			l.Write("n += %d * len(v)", size)
+			return
+		}
+		l.Write("for i := range v {")
+		if s, isStruct := t.Inner.(Struct); isStruct && !s.Nullable {
+			l.Write("v := &v[i]")
+		} else {
+			l.Write("v := v[i]")
+		}
+		writeSize(t.Inner, l)
+		l.Write("}")
+	case Struct:
+		t.WriteSize(l)
+	case Enum:
+		l.Write("{")
+		l.Write("v := %s(v)", t.Type.TypeName())
+		writeSize(t.Type, l)
+		l.Write("}")
+	default:
+		die("type %v unsupported in size! fix this!", t.TypeName())
+	}
+}
+
+// WriteSize writes the size of the struct in variable v to n.
+func (s Struct) WriteSize(l *LineWriter) {
+	usesV := s.Nullable || s.FromFlexible
+	for _, f := range s.Fields {
+		if _, fixed := fixedSize(f.Type); !fixed {
+			usesV = true
+		}
+	}
+	if !usesV {
+		l.Write("_ = v")
+	}
+
+	tags := make(map[int]StructField)
+	if s.Nullable {
+		l.Write("n += 1")
+		l.Write("if v != nil {")
+		defer l.Write("}")
+	}
+	for _, f := range s.Fields {
+		if onlyTag := f.writeBeginAndTag(l, tags); onlyTag {
+			continue
+		}
+		if size, fixed := fixedSize(f.Type); fixed {
+			l.Write("n += %d", size)
+			l.Write("}")
+			continue
+		}
+		if s, isStruct := f.Type.(Struct); isStruct && !s.Nullable {
+			l.Write("v := &v.%s", f.FieldName)
+		} else {
+			l.Write("v := v.%s", f.FieldName)
+		}
+		writeSize(f.Type, l)
+		l.Write("}")
+	}
+
+	if !s.FromFlexible {
+		return
+	}
+
+	l.Write("if isFlexible {")
+	defer l.Write("}")
+
+	l.Write("n += kbin.UvarintLen(uint32(%d + v.UnknownTags.Len()))", len(tags))
+	l.Write("v.UnknownTags.Each(func(key uint32, val []byte) {")
+	l.Write("n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)")
+	l.Write("})")
+	for i := range len(tags) {
+		f := tags[i]
+		if size, fixed := fixedSize(f.Type); fixed {
+			l.Write("n += %d", uvarintLen(i)+uvarintLen(size)+size)
+			continue
+		}
+		l.Write("{")
+		l.Write("v := v.%s", f.FieldName)
+		l.Write("start := n")
+		writeSize(f.Type, l)
+		l.Write("n += kbin.UvarintLen(%d) + kbin.UvarintLen(uint32(n-start))", i)
+		l.Write("}")
+	}
+}
+
 func (s Struct) WriteDecodeFunc(l *LineWriter) {
 	l.Write("func (v *%s) ReadFrom(src []byte) error {", s.Name)
-	l.Write("return v.readFrom(src, false)")
+	l.Write("return v.readFrom(kbin.Reader{Src: src}, false)")
 	l.Write("}")
 
 	l.Write("func (v *%s) UnsafeReadFrom(src []byte) error {", s.Name)
-	l.Write("return v.readFrom(src, true)")
+	l.Write("return v.readFrom(kbin.Reader{Src: src}, true)")
 	l.Write("}")
 
-	l.Write("func (v *%s) readFrom(src []byte, unsafe bool) error {", s.Name)
+	l.Write("func (v *%s) readFrom(b kbin.Reader, unsafe bool) error {", s.Name)
 	l.Write("v.Default()")
-	l.Write("b := kbin.Reader{Src: src}")
 	if s.WithVersionField {
 		l.Write("v.Version = b.Int16()")
 	}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -428,7 +429,11 @@ func validateMaxVersions(top, on *Struct) {
 }
 
 //go:generate sh -c "go run . | gofumpt | gofumpt -lang go1.19 -extra > ../pkg/kmsg/generated.go"
+//go:generate sh -c "go run . -builders | gofumpt | gofumpt -lang go1.19 -extra > ../pkg/kmsg/builders.go"
 func main() {
+	builders := flag.Bool("builders", false, "output fluent builders and nil-safe getters for all types rather than the main generated code")
+	flag.Parse()
+
 	const dir = "definitions"
 	const enums = "enums"
 	dirents, err := os.ReadDir(dir)
@@ -461,6 +466,12 @@ func main() {
 	}
 
 	l := &LineWriter{buf: bytes.NewBuffer(make([]byte, 0, 300<<10))}
+	if *builders {
+		sort.SliceStable(newStructs, func(i, j int) bool { return newStructs[i].Key < newStructs[j].Key })
+		writeBuilders(l)
+		fmt.Println(l.buf.String())
+		return
+	}
 	l.Write("package kmsg")
 	l.Write("import (")
 	l.Write(`"context"`)
@@ -521,6 +532,7 @@ func main() {
 
 			l.Write("") // newline before append/decode func
 			s.WriteAppendFunc(l)
+			s.WriteApproxSizeFunc(l)
 			s.WriteDecodeFunc(l)
 			s.WriteNewPtrFunc(l)
 		} else if !s.Anonymous && !s.WithNoEncoding {
@@ -2,6 +2,7 @@ package kadm
 
 import (
 	"context"
+	"errors"
 	"sort"
 	"strconv"
 
@@ -84,6 +85,59 @@ func (cl *Client) DescribeTopicConfigs(
 	return cl.describeConfigs(ctx, kmsg.ConfigResourceTypeTopic, topics)
 }
 
+// DescribeTopicConfigsPaged describes topic configs a page at a time, issuing
+// one request per page of up to pageSize topics and calling fn with each
+// page's configs rather than returning every config at once. If no topics are
+// specified, all topics are described, as listed by ListTopicsPaged. If
+// pageSize is not positive, this uses 1000.
+//
+// If fn returns an error, describing stops and the error is returned. Pages
+// that partially fail are still passed to fn, and their *ShardErrors are
+// merged and returned once every page is described. Any other error (for
+// example, an *AuthError) stops describing and is returned immediately.
+func (cl *Client) DescribeTopicConfigsPaged(
+	ctx context.Context,
+	pageSize int,
+	fn func(ResourceConfigs) error,
+	topics ...string,
+) error {
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	var se error
+	describe := func(topics []string) error {
+		for len(topics) > 0 {
+			n := min(pageSize, len(topics))
+			configs, err := cl.describeConfigs(ctx, kmsg.ConfigResourceTypeTopic, topics[:n])
+			topics = topics[n:]
+			if err != nil {
+				if !errors.As(err, new(*ShardErrors)) {
+					return err
+				}
+				se = mergeShardErrs(se, err)
+			}
+			if len(configs) > 0 {
+				if err := fn(configs); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if len(topics) > 0 {
+		if err := describe(topics); err != nil {
+			return err
+		}
+		return se
+	}
+	if err := cl.ListTopicsPaged(ctx, pageSize, func(tds TopicDetails) error {
+		return describe(tds.Names())
+	}); err != nil {
+		return err
+	}
+	return se
+}
+
 // DescribeBrokerConfigs returns configuration for the requested brokers. If no
 // brokers are requested, a single request is issued and any broker in the
 // cluster replies with the cluster-level dynamic config values.
@@ -4,6 +4,7 @@ go 1.24.0
 
 require (
 	github.com/twmb/franz-go v1.20.0
+	github.com/twmb/franz-go/pkg/kfake v0.0.0-20250711145744-a849b8be17b7
 	github.com/twmb/franz-go/pkg/kmsg v1.12.0
 	golang.org/x/crypto v0.43.0
 )
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sort"
+	"strings"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kgo"
@@ -235,7 +237,8 @@ type ListedGroup struct {
 	Coordinator  int32  // Coordinator is the node ID of the coordinator for this group.
 	Group        string // Group is the name of this group.
 	ProtocolType string // ProtocolType is the type of protocol the group is using, "consumer" for normal consumers, "connect" for Kafka connect.
-	State        string // State is the state this group is in (Empty, Dead, Stable, etc.; only if talking to Kafka 2.6+).
+	State        string // State is the state this group is in (Empty, Dead, Stable, etc.; only if talking to Kafka 2.6+ or if filtering by state).
+	Type         string // Type is the type of this group (classic, consumer, share, streams; only if talking to Kafka 3.8+).
 }
 
 // ListedGroups contains information from a list groups response.
@@ -261,28 +264,83 @@ func (ls ListedGroups) Groups() []string {
 	return all
 }
 
-// ListGroups returns all groups in the cluster. If you are talking to Kafka
-// 2.6+, filter states can be used to return groups only in the requested
-// states. By default, this returns all groups. In almost all cases,
+// ListGroups returns all groups in the cluster. Filter states can be used to
+// return groups only in the requested states (Empty, Stable, Dead, etc., case
+// insensitive). By default, this returns all groups. In almost all cases,
 // DescribeGroups is more useful.
 //
+// The state filter is pushed down to brokers that support it (Kafka 2.6+).
+// Brokers before 2.6 neither filter by nor return group states; for these
+// brokers, groups are filtered by describing them.
+//
 // This may return *ShardErrors or *AuthError.
 func (cl *Client) ListGroups(ctx context.Context, filterStates ...string) (ListedGroups, error) {
 	return cl.ListGroupsByType(ctx, nil, filterStates...)
 }
 
 // ListGroupsByType returns all groups in the cluster, filtered by the given group
-// type (classic, consumer, share, streams). Filter states can be used to
-// further filter by the current group state. Requires Kafka 3.8+.
+// type (classic, consumer, share, streams; case insensitive). Filter states
+// can be used to further filter by the current group state.
+//
+// Filters are pushed down to brokers that support them: types require Kafka
+// 3.8+ and states require Kafka 2.6+. Brokers before 3.8 only have classic
+// groups, so their groups are kept only if filtering for classic groups.
+// Brokers before 2.6 do not return group states, so their groups are filtered
+// by describing them.
 //
 // This may return *ShardErrors or *AuthError.
 func (cl *Client) ListGroupsByType(ctx context.Context, types []string, filterStates ...string) (ListedGroups, error) {
+	req := newListGroupsRequest(types, filterStates)
+	return cl.listGroups(ctx, req, cl.cl.RequestSharded(ctx, req), types, filterStates)
+}
+
+// ListGroupsPaged lists groups one broker at a time, calling fn with the
+// groups coordinated by each broker rather than returning every group at
+// once. This bounds the memory used when listing clusters with hundreds of
+// thousands of groups. Types and filter states filter groups the same as in
+// ListGroupsByType; if no types are specified, all group types are listed.
+//
+// If fn returns an error, listing stops and the error is returned. Brokers
+// that fail to list are skipped and their errors are returned as *ShardErrors
+// once every other broker is listed. An *AuthError stops listing and is
+// returned immediately.
+func (cl *Client) ListGroupsPaged(ctx context.Context, types []string, fn func(ListedGroups) error, filterStates ...string) error {
+	m, err := cl.BrokerMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	var se error
+	for _, b := range m.Brokers {
+		req := newListGroupsRequest(types, filterStates)
+		resp, err := cl.cl.Broker(int(b.NodeID)).Request(ctx, req)
+		shards := []kgo.ResponseShard{{Meta: b, Req: req, Resp: resp, Err: err}}
+		list, err := cl.listGroups(ctx, req, shards, types, filterStates)
+		if err != nil {
+			if !errors.As(err, new(*ShardErrors)) {
+				return err
+			}
+			se = mergeShardErrs(se, err)
+		}
+		if len(list) > 0 {
+			if err := fn(list); err != nil {
+				return err
+			}
+		}
+	}
+	return se
+}
+
+func newListGroupsRequest(types, filterStates []string) *kmsg.ListGroupsRequest {
 	req := kmsg.NewPtrListGroupsRequest()
 	req.StatesFilter = append(req.StatesFilter, filterStates...)
 	req.TypesFilter = append(req.TypesFilter, types...)
-	shards := cl.cl.RequestSharded(ctx, req)
+	return req
+}
+
+func (cl *Client) listGroups(ctx context.Context, req *kmsg.ListGroupsRequest, shards []kgo.ResponseShard, types, filterStates []string) (ListedGroups, error) {
 	list := make(ListedGroups)
-	return list, shardErrEachBroker(req, shards, func(b BrokerDetail, kr kmsg.Response) error {
+	var describe []string
+	err := shardErrEachBroker(req, shards, func(b BrokerDetail, kr kmsg.Response) error {
 		resp := kr.(*kmsg.ListGroupsResponse)
 		if err := maybeAuthErr(resp.ErrorCode); err != nil {
 			return err
@@ -291,15 +349,61 @@ func (cl *Client) ListGroupsByType(ctx context.Context, types []string, filterSt
 			return err
 		}
 		for _, g := range resp.Groups {
+			keep, needDescribe := fallbackFilterGroup(resp.Version, types, filterStates)
+			if !keep {
+				continue
+			}
+			if needDescribe {
+				describe = append(describe, g.Group)
+			}
 			list[g.Group] = ListedGroup{ // group only lives on one broker, no need to exist-check
 				Coordinator:  b.NodeID,
 				Group:        g.Group,
 				ProtocolType: g.ProtocolType,
 				State:        g.GroupState,
+				Type:         g.GroupType,
 			}
 		}
 		return nil
 	})
+	if len(describe) == 0 {
+		return list, err
+	}
+
+	// Groups that cannot be described cannot be filtered, so we drop
+	// them and return the describe error if listing had no error.
+	described, derr := cl.DescribeGroups(ctx, describe...)
+	for _, g := range describe {
+		d, ok := described[g]
+		if !ok || d.Err != nil || !containsFold(filterStates, d.State) {
+			delete(list, g)
+			continue
+		}
+		l := list[g]
+		l.State = d.State
+		list[g] = l
+	}
+	if err == nil {
+		err = derr
+	}
+	return list, err
+}
+
+// fallbackFilterGroup returns whether a group listed in a ListGroups response
+// of the given version should be kept, and whether it must be described to
+// filter by state. Brokers that do not support the filters ignore them:
+// before v5 (Kafka 3.8) all groups are classic, and before v4 (Kafka 2.6)
+// group states are not returned.
+func fallbackFilterGroup(version int16, types, states []string) (keep, describe bool) {
+	if version < 5 && len(types) > 0 && !containsFold(types, "classic") {
+		return false, false
+	}
+	return true, version < 4 && len(states) > 0
+}
+
+// containsFold returns whether any of ss is equal to s, case insensitively.
+func containsFold(ss []string, s string) bool {
+	return slices.ContainsFunc(ss, func(s2 string) bool { return strings.EqualFold(s, s2) })
 }
 
 // DescribeGroups describes either all classic groups specified, or all classic
@@ -520,6 +624,8 @@ type LeaveGroupBuilder struct {
 	group       string
 	reason      *string
 	instanceIDs []*string
+	memberIDs   []string
+	all         bool
 }
 
 // LeaveGroup returns a LeaveGroupBuilder for the input group.
@@ -546,26 +652,52 @@ func (b *LeaveGroupBuilder) InstanceIDs(ids ...string) *LeaveGroupBuilder {
 	return b
 }
 
+// MemberIDs are dynamic members (members without an instance ID) to remove
+// from a group. This can be used to evict a wedged member that is not using
+// static membership. This requires Kafka 2.4+.
+func (b *LeaveGroupBuilder) MemberIDs(ids ...string) *LeaveGroupBuilder {
+	for _, id := range ids {
+		if id != "" {
+			b.memberIDs = append(b.memberIDs, id)
+		}
+	}
+	return b
+}
+
+// AllMembers removes every current member of the group, static or dynamic.
+// The group is described first to find its members, and every member found
+// is removed in addition to any members added with InstanceIDs or MemberIDs.
+// This is similar to the Java admin client's removeMembersFromConsumerGroup
+// with no specific members. This requires Kafka 2.4+.
+func (b *LeaveGroupBuilder) AllMembers() *LeaveGroupBuilder {
+	b.all = true
+	return b
+}
+
 // LeaveGroupResponse contains the response for an individual instance ID that
 // left a group.
 type LeaveGroupResponse struct {
 	Group      string // Group is the group that was left.
-	InstanceID string // InstanceID is the instance ID that left the group.
+	InstanceID string // InstanceID is the instance ID that left the group, or empty for a dynamic member.
 	MemberID   string // MemberID is the member ID that left the group.
-	Err        error  // Err is non-nil if this member did not exist.
+	Err        error  // Err is non-nil if this member did not exist or the group could not be left.
 }
 
 // LeaveGroupResponses contains responses for each member of a leave group
-// request. The map key is the instance ID that was removed from the group.
+// request. The map key is the instance ID that was removed from the group, or
+// the member ID for dynamic members.
 type LeaveGroupResponses map[string]LeaveGroupResponse
 
-// Sorted returns all removed group members by instance ID.
+// Sorted returns all removed group members by instance ID, and then member ID.
 func (ls LeaveGroupResponses) Sorted() []LeaveGroupResponse {
 	s := make([]LeaveGroupResponse, 0, len(ls))
 	for _, l := range ls {
 		s = append(s, l)
 	}
-	sort.Slice(s, func(i, j int) bool { return s[i].InstanceID < s[j].InstanceID })
+	sort.Slice(s, func(i, j int) bool {
+		l, r := s[i], s[j]
+		return l.InstanceID < r.InstanceID || l.InstanceID == r.InstanceID && l.MemberID < r.MemberID
+	})
 	return s
 }
 
@@ -602,24 +734,56 @@ func (ls LeaveGroupResponses) Ok() bool {
 	return ls.Error() == nil
 }
 
-// LeaveGroup causes instance IDs to leave a group.
+// LeaveGroup causes instance IDs or member IDs to leave a group.
 //
 // This function allows manually removing members using instance IDs from a
 // group, which allows for fast scale down / host replacement (see KIP-345 for
-// more detail). This returns an *AuthErr if the use is not authorized to
-// remove members from groups.
+// more detail). Dynamic members can be removed by member ID, and every member
+// can be removed with AllMembers, which allows evicting a wedged member so that
+// the group rebalances without it. This returns an *AuthErr if the use is not
+// authorized to remove members from groups.
 func (cl *Client) LeaveGroup(ctx context.Context, b *LeaveGroupBuilder) (LeaveGroupResponses, error) {
-	if b == nil || len(b.instanceIDs) == 0 {
+	if b == nil {
 		return nil, nil
 	}
 	req := kmsg.NewPtrLeaveGroupRequest()
 	req.Group = b.group
-	for _, id := range b.instanceIDs {
+	addMember := func(instanceID *string, memberID string) {
 		m := kmsg.NewLeaveGroupRequestMember()
-		m.InstanceID = id
+		m.InstanceID = instanceID
+		m.MemberID = memberID
 		m.Reason = b.reason
 		req.Members = append(req.Members, m)
 	}
+	for _, id := range b.instanceIDs {
+		addMember(id, "")
+	}
+	for _, id := range b.memberIDs {
+		addMember(nil, id)
+	}
+	if b.all {
+		described, err := cl.DescribeGroups(ctx, b.group)
+		if err != nil {
+			return nil, err
+		}
+		g, ok := described[b.group]
+		if !ok {
+			return nil, kerr.GroupIDNotFound
+		}
+		if g.Err != nil {
+			return nil, g.Err
+		}
+		for _, m := range g.Members {
+			if m.InstanceID != nil {
+				addMember(m.InstanceID, "")
+			} else {
+				addMember(nil, m.MemberID)
+			}
+		}
+	}
+	if len(req.Members) == 0 {
+		return nil, nil
+	}
 
 	resp, err := req.RequestWith(ctx, cl.cl)
 	if err != nil {
@@ -628,23 +792,35 @@ func (cl *Client) LeaveGroup(ctx context.Context, b *LeaveGroupBuilder) (LeaveGr
 	if err := maybeAuthErr(resp.ErrorCode); err != nil {
 		return nil, err
 	}
-	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
-		return nil, err
-	}
 
+	// A group level error fails every member; Kafka does not return
+	// members in this case, so we use the members we requested.
 	resps := make(LeaveGroupResponses)
-	for _, m := range resp.Members {
-		if m.InstanceID == nil {
-			continue // highly unexpected, buggy kafka
+	add := func(instanceID *string, memberID string, errCode int16) {
+		if errCode == 0 {
+			errCode = resp.ErrorCode
 		}
-		resps[*m.InstanceID] = LeaveGroupResponse{
-			Group:      b.group,
-			MemberID:   m.MemberID,
-			InstanceID: *m.InstanceID,
-			Err:        kerr.ErrorForCode(resp.ErrorCode),
+		l := LeaveGroupResponse{
+			Group:    b.group,
+			MemberID: memberID,
+			Err:      kerr.ErrorForCode(errCode),
 		}
+		key := memberID
+		if instanceID != nil {
+			l.InstanceID = *instanceID
+			key = *instanceID
+		}
+		resps[key] = l
 	}
-	return resps, err
+	for _, m := range resp.Members {
+		add(m.InstanceID, m.MemberID, m.ErrorCode)
+	}
+	if len(resp.Members) == 0 && resp.ErrorCode != 0 {
+		for _, m := range req.Members {
+			add(m.InstanceID, m.MemberID, 0)
+		}
+	}
+	return resps, nil
 }
 
 // OffsetResponse contains the response for an individual offset for offset
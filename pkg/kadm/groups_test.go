@@ -0,0 +1,149 @@
+package kadm
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFallbackFilterGroup(t *testing.T) {
+	for _, test := range []struct {
+		version  int16
+		types    []string
+		states   []string
+		keep     bool
+		describe bool
+	}{
+		{version: 5, types: []string{"consumer"}, states: []string{"Stable"}, keep: true},
+		{version: 4, states: []string{"Stable"}, keep: true},
+		{version: 4, types: []string{"consumer"}},
+		{version: 4, types: []string{"consumer", "Classic"}, keep: true},
+		{version: 3, keep: true},
+		{version: 3, states: []string{"empty"}, keep: true, describe: true},
+		{version: 3, types: []string{"share"}, states: []string{"empty"}},
+	} {
+		keep, describe := fallbackFilterGroup(test.version, test.types, test.states)
+		if keep != test.keep || describe != test.describe {
+			t.Errorf("v%d types %v states %v: got (keep %v, describe %v) != exp (%v, %v)",
+				test.version, test.types, test.states, keep, describe, test.keep, test.describe)
+		}
+	}
+}
+
+func TestLeaveGroup(t *testing.T) {
+	ctx := context.Background()
+	c, adm := newFakeCluster(t, nil)
+
+	// The group has a static member with instance ID "a" and two dynamic
+	// members.
+	c.ControlKey(kmsg.DescribeGroups.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		resp := kreq.ResponseKind().(*kmsg.DescribeGroupsResponse)
+		g := kmsg.NewDescribeGroupsResponseGroup()
+		g.Group = "g"
+		g.State = "Stable"
+		for _, m := range []struct {
+			instanceID *string
+			memberID   string
+		}{
+			{kmsg.StringPtr("a"), "static-member"},
+			{nil, "c"},
+			{nil, "b"},
+		} {
+			dm := kmsg.NewDescribeGroupsResponseGroupMember()
+			dm.InstanceID = m.instanceID
+			dm.MemberID = m.memberID
+			g.Members = append(g.Members, dm)
+		}
+		resp.Groups = append(resp.Groups, g)
+		return resp, nil, true
+	})
+
+	// We echo every member back, failing member ID "b", or fail the
+	// whole request for group "bad".
+	type member struct {
+		instanceID string
+		memberID   string
+	}
+	var requested []member
+	c.ControlKey(kmsg.LeaveGroup.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.LeaveGroupRequest)
+		resp := req.ResponseKind().(*kmsg.LeaveGroupResponse)
+		if req.Group == "bad" {
+			resp.ErrorCode = kerr.GroupIDNotFound.Code
+			return resp, nil, true
+		}
+		requested = requested[:0]
+		for _, m := range req.Members {
+			requested = append(requested, member{unptrStr(m.InstanceID), m.MemberID})
+			rm := kmsg.NewLeaveGroupResponseMember()
+			rm.InstanceID = m.InstanceID
+			rm.MemberID = m.MemberID
+			if m.MemberID == "b" {
+				rm.ErrorCode = kerr.UnknownMemberID.Code
+			}
+			resp.Members = append(resp.Members, rm)
+		}
+		return resp, nil, true
+	})
+
+	ls, err := adm.LeaveGroup(ctx, LeaveGroup("g").InstanceIDs("a", "").MemberIDs("b", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []member{{"a", ""}, {"", "b"}}; !reflect.DeepEqual(requested, exp) {
+		t.Errorf("got requested members %v != exp %v", requested, exp)
+	}
+	if l, ok := ls["a"]; !ok || l.Err != nil || l.InstanceID != "a" {
+		t.Errorf("instance a: got %+v, %v, exp a successful leave", l, ok)
+	}
+	if l, ok := ls["b"]; !ok || !errors.Is(l.Err, kerr.UnknownMemberID) || l.InstanceID != "" {
+		t.Errorf("member b: got %+v, %v, exp UnknownMemberID", l, ok)
+	}
+	if !errors.Is(ls.Error(), kerr.UnknownMemberID) || ls.Ok() {
+		t.Errorf("got error %v != exp UnknownMemberID", ls.Error())
+	}
+
+	ls, err = adm.LeaveGroup(ctx, LeaveGroup("g").AllMembers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []member{{"a", ""}, {"", "b"}, {"", "c"}}; !reflect.DeepEqual(requested, exp) {
+		t.Errorf("got requested members %v != exp %v", requested, exp)
+	}
+	if len(ls) != 3 {
+		t.Fatalf("got %d responses %v != exp 3", len(ls), ls)
+	}
+	if static := ls["a"]; static.InstanceID != "a" || static.Err != nil {
+		t.Errorf("static a: got %+v, exp a successful leave for instance a", static)
+	}
+	if dynamic := ls["c"]; dynamic.InstanceID != "" || dynamic.MemberID != "c" || dynamic.Err != nil {
+		t.Errorf("dynamic c: got %+v, exp a successful leave for member c", dynamic)
+	}
+	var sorted []member
+	for _, l := range ls.Sorted() {
+		sorted = append(sorted, member{l.InstanceID, l.MemberID})
+	}
+	if exp := []member{{"", "b"}, {"", "c"}, {"a", ""}}; !reflect.DeepEqual(sorted, exp) {
+		t.Errorf("got sorted %v != exp %v", sorted, exp)
+	}
+
+	// A group level error is returned on each member.
+	ls, err = adm.LeaveGroup(ctx, LeaveGroup("bad").InstanceIDs("a").MemberIDs("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ls) != 2 {
+		t.Fatalf("got %d responses %v != exp 2", len(ls), ls)
+	}
+	ls.Each(func(l LeaveGroupResponse) {
+		if !errors.Is(l.Err, kerr.GroupIDNotFound) {
+			t.Errorf("got %+v, exp GroupIDNotFound", l)
+		}
+	})
+}
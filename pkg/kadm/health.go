@@ -0,0 +1,229 @@
+package kadm
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// HealthStatus is the overall status of a health report.
+type HealthStatus int8
+
+const (
+	// HealthOK indicates that no problems were found.
+	HealthOK HealthStatus = iota
+	// HealthDegraded indicates that some partitions are under-replicated,
+	// but all partitions are available for reading and writing.
+	HealthDegraded
+	// HealthUnavailable indicates that some partitions cannot be read from
+	// or written to (offline or below min.insync.replicas), or that the
+	// cluster has no controller.
+	HealthUnavailable
+)
+
+// String returns OK, DEGRADED, UNAVAILABLE, or UNKNOWN.
+func (s HealthStatus) String() string {
+	switch s {
+	case HealthOK:
+		return "OK"
+	case HealthDegraded:
+		return "DEGRADED"
+	case HealthUnavailable:
+		return "UNAVAILABLE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// UnhealthyPartition is a partition that was found to have a problem in a
+// health check.
+type UnhealthyPartition struct {
+	Topic     string // Topic is the topic this partition belongs to.
+	Partition int32  // Partition is the partition number.
+
+	Leader          int32   // Leader is the broker leader, if there is one, otherwise -1.
+	Replicas        []int32 // Replicas is the list of replicas.
+	ISR             []int32 // ISR is the list of in sync replicas.
+	OfflineReplicas []int32 // OfflineReplicas is the list of offline replicas.
+
+	// MinISR is the topic's min.insync.replicas, or -1 if it is not known.
+	// This is only described for topics that have under-replicated
+	// partitions.
+	MinISR int
+
+	// UnderReplicatedFor is how long this partition has been seen as
+	// under-replicated across Health calls on this admin client. This is 0
+	// the first time a partition is seen under-replicated.
+	UnderReplicatedFor time.Duration
+}
+
+// HealthReport is the result of a cluster health check.
+type HealthReport struct {
+	Status HealthStatus // Status is the overall status; see the fields below for why.
+
+	Controller    int32 // Controller is the node ID of the controller broker, or -1.
+	HasController bool  // HasController is whether the cluster currently has a controller.
+	NumBrokers    int   // NumBrokers is the number of live brokers in the metadata response.
+
+	// UnderReplicated contains partitions whose ISR is smaller than their
+	// replica set.
+	UnderReplicated []UnhealthyPartition
+	// Offline contains partitions that have no leader.
+	Offline []UnhealthyPartition
+	// UnderMinISR contains partitions whose ISR is smaller than the
+	// topic's min.insync.replicas; producing with acks=all fails for
+	// these partitions.
+	UnderMinISR []UnhealthyPartition
+
+	// ConfigsErr is any error encountered while describing
+	// min.insync.replicas. If non-nil, min ISR violations may be missing
+	// for some topics.
+	ConfigsErr error
+}
+
+// Healthy returns whether the report's status is HealthOK.
+func (r HealthReport) Healthy() bool { return r.Status == HealthOK }
+
+// Ready returns whether the report's status is not HealthUnavailable. This is
+// meant to be used for readiness probes of services that depend on Kafka: a
+// degraded cluster is still usable.
+func (r HealthReport) Ready() bool { return r.Status != HealthUnavailable }
+
+// Health checks the health of the cluster, or of only the given topics if any
+// are specified. This issues an uncached metadata request and, if any
+// partitions are under-replicated, a describe configs request to check
+// min.insync.replicas for the affected topics.
+//
+// The admin client tracks when partitions are first seen under-replicated
+// across calls to Health, such that repeated calls (such as from a readiness
+// probe) can report how long each partition has been under-replicated.
+//
+// This returns an error if the metadata request fails or is not authorized.
+// Failing to describe configs is returned in the report's ConfigsErr.
+func (cl *Client) Health(ctx context.Context, topics ...string) (HealthReport, error) {
+	m, err := cl.metadata(ctx, false, true, topics)
+	if err != nil {
+		return HealthReport{}, err
+	}
+
+	r := HealthReport{
+		Controller:    m.Controller,
+		HasController: m.Controller >= 0,
+		NumBrokers:    len(m.Brokers),
+	}
+
+	var urpTopics []string
+	urpTopicsSeen := make(map[string]bool)
+	m.Topics.EachPartition(func(d PartitionDetail) {
+		if d.Err != nil && d.Leader < 0 && len(d.Replicas) == 0 {
+			return // partition failed to load entirely; nothing to report
+		}
+		up := UnhealthyPartition{
+			Topic:           d.Topic,
+			Partition:       d.Partition,
+			Leader:          d.Leader,
+			Replicas:        d.Replicas,
+			ISR:             d.ISR,
+			OfflineReplicas: d.OfflineReplicas,
+			MinISR:          -1,
+		}
+		if d.Leader < 0 {
+			r.Offline = append(r.Offline, up)
+		}
+		if len(d.ISR) < len(d.Replicas) {
+			r.UnderReplicated = append(r.UnderReplicated, up)
+			if !urpTopicsSeen[d.Topic] {
+				urpTopicsSeen[d.Topic] = true
+				urpTopics = append(urpTopics, d.Topic)
+			}
+		}
+	})
+
+	cl.trackURPs(r.UnderReplicated, m.Topics)
+
+	if len(urpTopics) > 0 {
+		minISRs := make(map[string]int)
+		rcs, err := cl.DescribeTopicConfigs(ctx, urpTopics...)
+		r.ConfigsErr = err
+		for _, rc := range rcs {
+			if rc.Err != nil {
+				if r.ConfigsErr == nil {
+					r.ConfigsErr = rc.Err
+				}
+				continue
+			}
+			for _, c := range rc.Configs {
+				if c.Key != "min.insync.replicas" {
+					continue
+				}
+				if n, err := strconv.Atoi(c.MaybeValue()); err == nil {
+					minISRs[rc.Name] = n
+				}
+			}
+		}
+		for i := range r.UnderReplicated {
+			up := &r.UnderReplicated[i]
+			minISR, ok := minISRs[up.Topic]
+			if !ok {
+				continue
+			}
+			up.MinISR = minISR
+			if len(up.ISR) < minISR {
+				r.UnderMinISR = append(r.UnderMinISR, *up)
+			}
+		}
+	}
+
+	sortUnhealthy(r.UnderReplicated)
+	sortUnhealthy(r.Offline)
+	sortUnhealthy(r.UnderMinISR)
+
+	switch {
+	case !r.HasController || len(r.Offline) > 0 || len(r.UnderMinISR) > 0:
+		r.Status = HealthUnavailable
+	case len(r.UnderReplicated) > 0:
+		r.Status = HealthDegraded
+	default:
+		r.Status = HealthOK
+	}
+	return r, nil
+}
+
+// trackURPs records when partitions were first seen under-replicated and
+// fills in UnderReplicatedFor. Partitions in checked topics that are no longer
+// under-replicated are forgotten.
+func (cl *Client) trackURPs(urps []UnhealthyPartition, checked TopicDetails) {
+	now := time.Now()
+
+	cl.urpMu.Lock()
+	defer cl.urpMu.Unlock()
+
+	if cl.urpSince == nil {
+		cl.urpSince = make(map[Partition]time.Time)
+	}
+	seen := make(map[Partition]bool, len(urps))
+	for i := range urps {
+		up := &urps[i]
+		p := Partition{Topic: up.Topic, Partition: up.Partition}
+		seen[p] = true
+		since, ok := cl.urpSince[p]
+		if !ok {
+			cl.urpSince[p] = now
+			continue
+		}
+		up.UnderReplicatedFor = now.Sub(since)
+	}
+	for p := range cl.urpSince {
+		if _, wasChecked := checked[p.Topic]; wasChecked && !seen[p] {
+			delete(cl.urpSince, p)
+		}
+	}
+}
+
+func sortUnhealthy(ps []UnhealthyPartition) {
+	sort.Slice(ps, func(i, j int) bool {
+		l, r := ps[i], ps[j]
+		return l.Topic < r.Topic || l.Topic == r.Topic && l.Partition < r.Partition
+	})
+}
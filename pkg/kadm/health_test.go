@@ -0,0 +1,134 @@
+package kadm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestHealth(t *testing.T) {
+	ctx := context.Background()
+	c, adm := newFakeCluster(t, []string{"foo"})
+
+	// kfake always reports every partition as fully replicated, so we
+	// answer metadata requests with the real metadata modified by the
+	// current test case.
+	base, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, adm.cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		mu     sync.Mutex
+		modify func(*kmsg.MetadataResponseTopicPartition)
+	)
+	c.ControlKey(kmsg.Metadata.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		mu.Lock()
+		defer mu.Unlock()
+		if modify == nil {
+			return nil, nil, false
+		}
+		resp := *base
+		resp.Version = kreq.GetVersion()
+		resp.Topics = nil
+		for _, bt := range base.Topics {
+			t := bt
+			t.Partitions = nil
+			for _, p := range bt.Partitions {
+				p.Replicas = []int32{0, 1}
+				p.ISR = []int32{0, 1}
+				modify(&p)
+				t.Partitions = append(t.Partitions, p)
+			}
+			resp.Topics = append(resp.Topics, t)
+		}
+		return &resp, nil, true
+	})
+	setModify := func(fn func(*kmsg.MetadataResponseTopicPartition)) {
+		mu.Lock()
+		defer mu.Unlock()
+		modify = fn
+	}
+
+	r, err := adm.Health(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != HealthOK || !r.Healthy() || !r.Ready() {
+		t.Errorf("got status %v, exp OK", r.Status)
+	}
+	if !r.HasController || r.NumBrokers != 1 {
+		t.Errorf("got controller %v, %d brokers, exp a controller and 1 broker", r.HasController, r.NumBrokers)
+	}
+	if len(r.UnderReplicated) != 0 || len(r.Offline) != 0 || len(r.UnderMinISR) != 0 || r.ConfigsErr != nil {
+		t.Errorf("unexpected problems in a healthy report: %+v", r)
+	}
+
+	// Partition 1 losing a replica from its ISR is degraded, because the
+	// default min.insync.replicas is 1.
+	setModify(func(p *kmsg.MetadataResponseTopicPartition) {
+		if p.Partition == 1 {
+			p.ISR = []int32{0}
+		}
+	})
+	for i := 0; i < 2; i++ {
+		r, err = adm.Health(ctx, "foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Status != HealthDegraded || r.Healthy() || !r.Ready() {
+			t.Errorf("got status %v, exp DEGRADED", r.Status)
+		}
+		if len(r.UnderReplicated) != 1 || len(r.Offline) != 0 || len(r.UnderMinISR) != 0 {
+			t.Fatalf("got %+v, exp only partition 1 under-replicated", r)
+		}
+		up := r.UnderReplicated[0]
+		if up.Topic != "foo" || up.Partition != 1 || up.MinISR != 1 || len(up.ISR) != 1 {
+			t.Errorf("got under-replicated %+v, exp foo partition 1 with min ISR 1", up)
+		}
+		if first := i == 0; first != (up.UnderReplicatedFor == 0) {
+			t.Errorf("check %d: got under-replicated for %v", i, up.UnderReplicatedFor)
+		}
+	}
+
+	// Raising min.insync.replicas above the ISR makes the partition
+	// unavailable, as does a partition losing its leader.
+	two := "2"
+	if _, err := adm.AlterTopicConfigs(ctx, []AlterConfig{{Name: "min.insync.replicas", Value: &two}}, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	setModify(func(p *kmsg.MetadataResponseTopicPartition) {
+		switch p.Partition {
+		case 1:
+			p.ISR = []int32{0}
+		case 2:
+			p.Leader = -1
+		}
+	})
+	r, err = adm.Health(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Status != HealthUnavailable || r.Ready() {
+		t.Errorf("got status %v, exp UNAVAILABLE", r.Status)
+	}
+	if len(r.UnderMinISR) != 1 || r.UnderMinISR[0].Partition != 1 || r.UnderMinISR[0].MinISR != 2 {
+		t.Errorf("got under min ISR %+v, exp partition 1 with min ISR 2", r.UnderMinISR)
+	}
+	if len(r.Offline) != 1 || r.Offline[0].Partition != 2 {
+		t.Errorf("got offline %+v, exp partition 2", r.Offline)
+	}
+
+	// Once healthy again, the under-replicated tracking is forgotten.
+	setModify(func(*kmsg.MetadataResponseTopicPartition) {})
+	if r, err = adm.Health(ctx, "foo"); err != nil || r.Status != HealthOK {
+		t.Errorf("got status %v, err %v, exp OK", r.Status, err)
+	}
+	adm.urpMu.Lock()
+	defer adm.urpMu.Unlock()
+	if len(adm.urpSince) != 0 {
+		t.Errorf("still tracking under-replicated partitions %v", adm.urpSince)
+	}
+}
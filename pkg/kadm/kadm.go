@@ -72,6 +72,7 @@ import (
 	"runtime/debug"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 )
@@ -111,11 +112,14 @@ type Client struct {
 	cl *kgo.Client
 
 	timeoutMillis int32
+
+	urpMu    sync.Mutex
+	urpSince map[Partition]time.Time // when Health first saw a partition under-replicated
 }
 
 // NewClient returns an admin client.
 func NewClient(cl *kgo.Client) *Client {
-	return &Client{cl, 15000} // 15s timeout default, matching kmsg
+	return &Client{cl: cl, timeoutMillis: 15000} // 15s timeout default, matching kmsg
 }
 
 // NewOptClient returns a new client directly from kgo options. This is a
@@ -86,3 +86,20 @@ func TestAny(t *testing.T) {
 		}
 	}
 }
+
+func TestDetailedPartitionNumRecords(t *testing.T) {
+	for _, test := range []struct {
+		start, end ListedOffset
+		exp        int64
+	}{
+		{ListedOffset{Offset: 3}, ListedOffset{Offset: 10}, 7},
+		{ListedOffset{Offset: 10}, ListedOffset{Offset: 10}, 0},
+		{ListedOffset{Offset: -1}, ListedOffset{Offset: 10}, -1},
+		{ListedOffset{Offset: 3}, ListedOffset{Offset: 10, Err: errors.New("fail")}, -1},
+	} {
+		d := DetailedPartition{StartOffset: test.start, EndOffset: test.end}
+		if got := d.NumRecords(); got != test.exp {
+			t.Errorf("start %d end %d: got %d != exp %d", test.start.Offset, test.end.Offset, got, test.exp)
+		}
+	}
+}
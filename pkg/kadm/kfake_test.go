@@ -0,0 +1,61 @@
+package kadm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kfake"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// newFakeCluster returns a kfake cluster seeded with the given topics, each
+// with three partitions, and an admin client for it.
+//
+// kfake does not implement every admin request. Each request in controlled is
+// additionally advertised in ApiVersions at its max version, so that tests can
+// answer it with ControlKey.
+func newFakeCluster(t *testing.T, topics []string, controlled ...kmsg.Request) (*kfake.Cluster, *Client) {
+	t.Helper()
+	c, err := kfake.NewCluster(kfake.NumBrokers(1), kfake.SeedTopics(3, topics...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(c.Close)
+
+	cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cl.Close)
+
+	if len(controlled) > 0 {
+		// We issue ApiVersions on a separate client to learn what
+		// kfake supports, and then control every ApiVersions to add
+		// what the test is controlling.
+		vcl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions, err := kmsg.NewPtrApiVersionsRequest().RequestWith(context.Background(), vcl)
+		vcl.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys := versions.ApiKeys
+		for _, r := range controlled {
+			k := kmsg.NewApiVersionsResponseApiKey()
+			k.ApiKey = r.Key()
+			k.MaxVersion = r.MaxVersion()
+			keys = append(keys, k)
+		}
+		c.ControlKey(18, func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+			c.KeepControl()
+			resp := kreq.ResponseKind().(*kmsg.ApiVersionsResponse)
+			resp.ApiKeys = keys
+			return resp, nil, true
+		})
+	}
+
+	return c, NewClient(cl)
+}
@@ -212,7 +212,7 @@ func (cl *Client) ListBrokers(ctx context.Context) (BrokerDetails, error) {
 //
 // This returns an error if the request fails to be issued, or an *AuthErr.
 func (cl *Client) BrokerMetadata(ctx context.Context) (Metadata, error) {
-	return cl.metadata(ctx, true, nil)
+	return cl.metadata(ctx, true, false, nil)
 }
 
 // Metadata issues a metadata request and returns it. Specific topics to
@@ -224,10 +224,10 @@ func (cl *Client) Metadata(
 	ctx context.Context,
 	topics ...string,
 ) (Metadata, error) {
-	return cl.metadata(ctx, false, topics)
+	return cl.metadata(ctx, false, false, topics)
 }
 
-func (cl *Client) metadata(ctx context.Context, noTopics bool, topics []string) (Metadata, error) {
+func (cl *Client) metadata(ctx context.Context, noTopics, uncached bool, topics []string) (Metadata, error) {
 	req := kmsg.NewPtrMetadataRequest()
 
 	fn := func() (*kmsg.MetadataResponse, error) {
@@ -236,6 +236,9 @@ func (cl *Client) metadata(ctx context.Context, noTopics bool, topics []string)
 	if ctx.Value(&includeAuthOps) != nil { // cached metadata does not query auth
 		req.IncludeClusterAuthorizedOperations = true
 		req.IncludeTopicAuthorizedOperations = true
+		uncached = true
+	}
+	if uncached {
 		fn = func() (*kmsg.MetadataResponse, error) {
 			return req.RequestWith(ctx, cl.cl)
 		}
@@ -494,12 +497,79 @@ func (cl *Client) ListOffsetsAfterMilli(ctx context.Context, millisecond int64,
 	return cl.listOffsets(ctx, 0, millisecond, topics)
 }
 
+// ListOffsetsForTimestamps lists offsets for many partitions at once, each at
+// its own timestamp. This is the same as ListOffsetsAfterMilli, but rather
+// than using one millisecond for every partition, each partition in
+// timestamps is listed at its own millisecond. This is useful for tooling
+// that translates or replays offsets, where every partition may need a
+// different timestamp. Requests are sharded to partition leaders as usual, so
+// listing many partitions costs one request per broker.
+//
+// A timestamp can also be one of the special Kafka values: -1 for the end
+// offset, -2 for the start offset, or -3 for the max timestamp offset (Kafka
+// 3.0+). If a partition has no offsets after a non-negative timestamp, the
+// offset will be the current end offset.
+//
+// Partitions that do not exist are returned with the error
+// kerr.UnknownTopicOrPartition. If any topics being listed do not exist, a
+// special -1 partition is added to the response with the expected error code
+// kerr.UnknownTopicOrPartition.
+//
+// This may return *ShardErrors.
+func (cl *Client) ListOffsetsForTimestamps(ctx context.Context, timestamps map[string]map[int32]int64) (ListedOffsets, error) {
+	if len(timestamps) == 0 {
+		return make(ListedOffsets), nil
+	}
+	topics := make([]string, 0, len(timestamps))
+	for t := range timestamps {
+		topics = append(topics, t)
+	}
+	tds, err := cl.ListTopics(ctx, topics...)
+	if err != nil {
+		return nil, err
+	}
+	list, err := cl.listOffsetsAt(ctx, 0, tds, func(t string, p int32) (int64, bool) {
+		ts, ok := timestamps[t][p]
+		return ts, ok
+	})
+	for t, ps := range timestamps {
+		td, ok := tds[t]
+		if !ok || td.Err != nil {
+			continue
+		}
+		for p := range ps {
+			if _, exists := td.Partitions[p]; exists {
+				continue
+			}
+			lt := list[t]
+			if lt == nil {
+				lt = make(map[int32]ListedOffset)
+				list[t] = lt
+			}
+			lt[p] = ListedOffset{
+				Topic:       t,
+				Partition:   p,
+				Timestamp:   -1,
+				Offset:      -1,
+				LeaderEpoch: -1,
+				Err:         kerr.UnknownTopicOrPartition,
+			}
+		}
+	}
+	return list, err
+}
+
 func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int64, topics []string) (ListedOffsets, error) {
 	tds, err := cl.ListTopics(ctx, topics...)
 	if err != nil {
 		return nil, err
 	}
+	return cl.listOffsetsAt(ctx, isolation, tds, func(string, int32) (int64, bool) { return timestamp, true })
+}
 
+// listOffsetsAt lists offsets for the partitions in tds, using tsFn to return
+// the timestamp to list each partition at, or false to skip the partition.
+func (cl *Client) listOffsetsAt(ctx context.Context, isolation int8, tds TopicDetails, tsFn func(string, int32) (int64, bool)) (ListedOffsets, error) {
 	// If we request with timestamps, we may request twice: once for after
 	// timestamps, and once for any -1 (and no error) offsets where the
 	// timestamp is in the future.
@@ -537,7 +607,7 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 					LeaderEpoch: p.LeaderEpoch,
 					Err:         kerr.ErrorForCode(p.ErrorCode),
 				}
-				if timestamp != -1 && p.Offset == -1 && p.ErrorCode == 0 {
+				if timestamp, _ := tsFn(t.Topic, p.Partition); timestamp != -1 && p.Offset == -1 && p.ErrorCode == 0 {
 					rerequest[t.Topic] = append(rerequest[t.Topic], p.Partition)
 				}
 			}
@@ -555,15 +625,21 @@ func (cl *Client) listOffsets(ctx context.Context, isolation int8, timestamp int
 		}
 		rt.Topic = t
 		for p := range td.Partitions {
+			timestamp, ok := tsFn(t, p)
+			if !ok {
+				continue
+			}
 			rp := kmsg.NewListOffsetsRequestTopicPartition()
 			rp.Partition = p
 			rp.Timestamp = timestamp
 			rt.Partitions = append(rt.Partitions, rp)
 		}
-		req.Topics = append(req.Topics, rt)
+		if len(rt.Partitions) > 0 {
+			req.Topics = append(req.Topics, rt)
+		}
 	}
 	shards := cl.cl.RequestSharded(ctx, req)
-	err = shardErrEach(req, shards, shardfn)
+	err := shardErrEach(req, shards, shardfn)
 	if len(rerequest) > 0 {
 		req.Topics = req.Topics[:0]
 		for t, ps := range rerequest {
@@ -0,0 +1,84 @@
+package kadm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestListOffsetsForTimestamps(t *testing.T) {
+	ctx := context.Background()
+	c, adm := newFakeCluster(t, []string{"foo", "bar"})
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+
+	// Each record is produced alone so that it is in its own batch.
+	for _, r := range []struct {
+		topic     string
+		partition int32
+		ms        int64
+	}{
+		{"foo", 0, 100}, // offset 0
+		{"foo", 0, 200}, // offset 1
+		{"foo", 0, 300}, // offset 2
+		{"foo", 1, 100}, // offset 0
+		{"bar", 0, 100}, // offset 0
+		{"bar", 0, 200}, // offset 1
+	} {
+		rec := &kgo.Record{Topic: r.topic, Partition: r.partition, Timestamp: time.UnixMilli(r.ms)}
+		if err := producer.ProduceSync(ctx, rec).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listed, err := adm.ListOffsetsForTimestamps(ctx, map[string]map[int32]int64{
+		"foo": {
+			0: 150,  // between records: the next record
+			1: 5000, // past the end: the end offset
+			2: -2,   // the start offset of an empty partition
+			7: 100,  // an unknown partition
+		},
+		"bar": {
+			0: -1, // the end offset
+		},
+		"missing": {
+			0: 100,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, exp := range []struct {
+		topic     string
+		partition int32
+		offset    int64
+	}{
+		{"foo", 0, 1},
+		{"foo", 1, 1},
+		{"foo", 2, 0},
+		{"bar", 0, 2},
+	} {
+		l, ok := listed.Lookup(exp.topic, exp.partition)
+		if !ok || l.Err != nil || l.Offset != exp.offset {
+			t.Errorf("%s %d: got %+v (exists? %v), exp offset %d", exp.topic, exp.partition, l, ok, exp.offset)
+		}
+	}
+	if _, ok := listed.Lookup("bar", 1); ok {
+		t.Error("bar partition 1 was listed, but no timestamp was requested for it")
+	}
+	if l, ok := listed.Lookup("foo", 7); !ok || !errors.Is(l.Err, kerr.UnknownTopicOrPartition) {
+		t.Errorf("foo 7: got %+v (exists? %v), exp UnknownTopicOrPartition", l, ok)
+	}
+	if l, ok := listed.Lookup("missing", -1); !ok || !errors.Is(l.Err, kerr.UnknownTopicOrPartition) {
+		t.Errorf("missing: got %+v (exists? %v), exp UnknownTopicOrPartition", l, ok)
+	}
+}
@@ -546,6 +546,17 @@ type DescribedUserSCRAM struct {
 	ErrMessage string     // ErrMessage a potential extra message describing any error.
 }
 
+// CredInfo returns the credential info for the given mechanism, if the user
+// has a password for it.
+func (d DescribedUserSCRAM) CredInfo(m ScramMechanism) (CredInfo, bool) {
+	for _, c := range d.CredInfos {
+		if c.Mechanism == m {
+			return c, true
+		}
+	}
+	return CredInfo{}, false
+}
+
 // DescribedUserSCRAMs contains described user SCRAM credentials keyed by user.
 type DescribedUserSCRAMs map[string]DescribedUserSCRAM
 
@@ -775,11 +786,210 @@ func (cl *Client) AlterUserSCRAMs(ctx context.Context, del []DeleteSCRAM, upsert
 			Err:        kerr.ErrorForCode(res.ErrorCode),
 			ErrMessage: unptrStr(res.ErrorMessage),
 		}
+		// A user can be altered for multiple mechanisms at once; we
+		// do not let a later success hide an earlier failure.
+		if prior, exists := rs[r.User]; exists && prior.Err != nil {
+			continue
+		}
 		rs[r.User] = r
 	}
 	return rs, nil
 }
 
+// ErrSCRAMExists is returned from CreateUserSCRAMs for users that already
+// have a password for a mechanism that is being created.
+var ErrSCRAMExists = errors.New("user already has a SCRAM password for the requested mechanism")
+
+// SCRAMPasswordPolicy validates a plaintext password before it is salted. If
+// the policy returns an error, the user is not altered and the error is
+// returned as the user's AlteredUserSCRAM.Err.
+type SCRAMPasswordPolicy func(user string, mechanism ScramMechanism, password string) error
+
+// BulkSCRAM configures how CreateUserSCRAMs and RotateUserSCRAMs generate
+// SCRAM credentials. Salts are always generated client side.
+type BulkSCRAM struct {
+	// Mechanisms are the mechanisms to create passwords for. When
+	// creating, this defaults to SCRAM-SHA-256. When rotating, this
+	// defaults to every mechanism each user currently has a password for.
+	Mechanisms []ScramMechanism
+
+	// Iterations is the SCRAM iterations to use, and must be between 4096
+	// and 16384; other values fail the entire call before anything is
+	// requested. When creating, this defaults to 4096. When rotating,
+	// this defaults to the iterations each user currently has per
+	// mechanism.
+	Iterations int32
+
+	// Policy, if non-nil, is called for every user and mechanism before
+	// the password is salted.
+	Policy SCRAMPasswordPolicy
+}
+
+// CreateUserSCRAMs creates SCRAM passwords for many users at once. The input
+// map is of users to their plaintext passwords. Users are first described:
+// any user that already has a password for one of the mechanisms being
+// created fails with ErrSCRAMExists and is not altered. Any user that fails
+// the password policy is similarly not altered.
+//
+// The returned AlteredUserSCRAMs contains a result for every input user. If
+// describing the users fails entirely, this returns the error.
+func (cl *Client) CreateUserSCRAMs(ctx context.Context, b BulkSCRAM, passwords map[string]string) (AlteredUserSCRAMs, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	mechanisms := b.Mechanisms
+	if len(mechanisms) == 0 {
+		mechanisms = []ScramMechanism{ScramSha256}
+	}
+	iterations := b.Iterations
+	if iterations == 0 {
+		iterations = 4096
+	}
+	described, err := cl.describeBulkSCRAMs(ctx, passwords)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(AlteredUserSCRAMs)
+	var upsert []UpsertSCRAM
+	for user, password := range passwords {
+		d, exists := described[user]
+		if exists && d.Err != nil && !errors.Is(d.Err, kerr.ResourceNotFound) {
+			failed[user] = AlteredUserSCRAM{User: user, Err: d.Err, ErrMessage: d.ErrMessage}
+			continue
+		}
+		us, err := b.upserts(user, password, mechanisms, func(m ScramMechanism) (int32, error) {
+			if _, has := d.CredInfo(m); has {
+				return 0, ErrSCRAMExists
+			}
+			return iterations, nil
+		})
+		if err != nil {
+			failed[user] = AlteredUserSCRAM{User: user, Err: err}
+			continue
+		}
+		upsert = append(upsert, us...)
+	}
+	return cl.alterBulkSCRAMs(ctx, upsert, failed)
+}
+
+// RotateUserSCRAMs rotates SCRAM passwords for many users at once. The input
+// map is of users to their new plaintext passwords. Users are first
+// described: any user that does not exist fails with kerr.ResourceNotFound,
+// and any user that does not currently have a password for a requested
+// mechanism fails similarly. Any user that fails the password policy is not
+// altered.
+//
+// If b.Mechanisms is empty, every mechanism a user currently has is rotated.
+// If b.Iterations is zero, the user's current iterations for each mechanism
+// are kept. A new salt is always generated.
+//
+// The returned AlteredUserSCRAMs contains a result for every input user. If
+// describing the users fails entirely, this returns the error.
+func (cl *Client) RotateUserSCRAMs(ctx context.Context, b BulkSCRAM, passwords map[string]string) (AlteredUserSCRAMs, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+	described, err := cl.describeBulkSCRAMs(ctx, passwords)
+	if err != nil {
+		return nil, err
+	}
+
+	failed := make(AlteredUserSCRAMs)
+	var upsert []UpsertSCRAM
+	for user, password := range passwords {
+		d, exists := described[user]
+		if !exists {
+			failed[user] = AlteredUserSCRAM{User: user, Err: kerr.ResourceNotFound}
+			continue
+		}
+		if d.Err != nil {
+			failed[user] = AlteredUserSCRAM{User: user, Err: d.Err, ErrMessage: d.ErrMessage}
+			continue
+		}
+		mechanisms := b.Mechanisms
+		if len(mechanisms) == 0 {
+			for _, c := range d.CredInfos {
+				mechanisms = append(mechanisms, c.Mechanism)
+			}
+		}
+		us, err := b.upserts(user, password, mechanisms, func(m ScramMechanism) (int32, error) {
+			c, has := d.CredInfo(m)
+			if !has {
+				return 0, kerr.ResourceNotFound
+			}
+			if b.Iterations != 0 {
+				return b.Iterations, nil
+			}
+			return c.Iterations, nil
+		})
+		if err != nil {
+			failed[user] = AlteredUserSCRAM{User: user, Err: err}
+			continue
+		}
+		upsert = append(upsert, us...)
+	}
+	return cl.alterBulkSCRAMs(ctx, upsert, failed)
+}
+
+// validate returns an error if b.Iterations is non-zero and outside the range
+// Kafka allows, so that an invalid input fails before any user is described.
+func (b *BulkSCRAM) validate() error {
+	if b.Iterations != 0 && (b.Iterations < 4096 || b.Iterations > 16384) {
+		return fmt.Errorf("invalid SCRAM iterations %d: must be between 4096 and 16384", b.Iterations)
+	}
+	return nil
+}
+
+// upserts returns the upserts for a user across all mechanisms, using iterFn
+// to determine the iterations per mechanism (or to fail the user).
+func (b *BulkSCRAM) upserts(user, password string, mechanisms []ScramMechanism, iterFn func(ScramMechanism) (int32, error)) ([]UpsertSCRAM, error) {
+	var us []UpsertSCRAM
+	for _, m := range mechanisms {
+		iterations, err := iterFn(m)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", m, err)
+		}
+		if b.Policy != nil {
+			if err := b.Policy(user, m, password); err != nil {
+				return nil, err
+			}
+		}
+		us = append(us, UpsertSCRAM{
+			User:       user,
+			Mechanism:  m,
+			Iterations: iterations,
+			Password:   password,
+		})
+	}
+	return us, nil
+}
+
+func (cl *Client) describeBulkSCRAMs(ctx context.Context, passwords map[string]string) (DescribedUserSCRAMs, error) {
+	if len(passwords) == 0 {
+		return nil, nil
+	}
+	users := make([]string, 0, len(passwords))
+	for user := range passwords {
+		users = append(users, user)
+	}
+	return cl.DescribeUserSCRAMs(ctx, users...)
+}
+
+func (cl *Client) alterBulkSCRAMs(ctx context.Context, upsert []UpsertSCRAM, failed AlteredUserSCRAMs) (AlteredUserSCRAMs, error) {
+	if len(upsert) == 0 {
+		return failed, nil
+	}
+	altered, err := cl.AlterUserSCRAMs(ctx, nil, upsert)
+	if err != nil {
+		return nil, err
+	}
+	for user, f := range failed {
+		altered[user] = f
+	}
+	return altered, nil
+}
+
 // ElectLeadersHow is how partition leaders should be elected.
 type ElectLeadersHow int8
 
@@ -0,0 +1,129 @@
+package kadm
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestBulkSCRAMs(t *testing.T) {
+	ctx := context.Background()
+	c, adm := newFakeCluster(t, nil)
+
+	var requested int
+	c.ControlKey(kmsg.DescribeUserSCRAMCredentials.Int16(), func(kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		requested++
+		return nil, nil, false
+	})
+
+	// Invalid iterations fail before anything is requested.
+	for _, iterations := range []int32{1, 4095, 16385} {
+		b := BulkSCRAM{Iterations: iterations}
+		if _, err := adm.CreateUserSCRAMs(ctx, b, map[string]string{"alice": "password"}); err == nil {
+			t.Errorf("create with %d iterations: expected error", iterations)
+		}
+		if _, err := adm.RotateUserSCRAMs(ctx, b, map[string]string{"alice": "password"}); err == nil {
+			t.Errorf("rotate with %d iterations: expected error", iterations)
+		}
+	}
+	if requested != 0 {
+		t.Errorf("invalid iterations issued %d describe requests, exp 0", requested)
+	}
+
+	errShort := errors.New("password too short")
+	b := BulkSCRAM{
+		Policy: func(_ string, _ ScramMechanism, password string) error {
+			if len(password) < 8 {
+				return errShort
+			}
+			return nil
+		},
+	}
+	created, err := adm.CreateUserSCRAMs(ctx, b, map[string]string{"alice": "password", "bob": "short"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := created["alice"].Err; err != nil {
+		t.Errorf("alice: unexpected create error %v", err)
+	}
+	if err := created["bob"].Err; !errors.Is(err, errShort) {
+		t.Errorf("bob: got create error %v != exp policy error", err)
+	}
+
+	described, err := adm.DescribeUserSCRAMs(ctx, "alice", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := described["alice"].CredInfo(ScramSha256); !ok || c.Iterations != 4096 {
+		t.Errorf("alice: got %v, %v != exp SCRAM-SHA-256 with the default 4096 iterations", c, ok)
+	}
+	if d, ok := described["bob"]; ok && len(d.CredInfos) > 0 {
+		t.Errorf("bob: unexpectedly created %v after failing the policy", d.CredInfos)
+	}
+
+	created, err = adm.CreateUserSCRAMs(ctx, BulkSCRAM{}, map[string]string{"alice": "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := created["alice"].Err; !errors.Is(err, ErrSCRAMExists) {
+		t.Errorf("alice: got re-create error %v != exp ErrSCRAMExists", err)
+	}
+
+	rotated, err := adm.RotateUserSCRAMs(ctx, BulkSCRAM{Iterations: 8192}, map[string]string{"alice": "password2", "carol": "password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated["alice"].Err; err != nil {
+		t.Errorf("alice: unexpected rotate error %v", err)
+	}
+	if err := rotated["carol"].Err; !errors.Is(err, kerr.ResourceNotFound) {
+		t.Errorf("carol: got rotate error %v != exp ResourceNotFound", err)
+	}
+	described, err = adm.DescribeUserSCRAMs(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := described["alice"].CredInfo(ScramSha256); !ok || c.Iterations != 8192 {
+		t.Errorf("alice: got %v, %v != exp SCRAM-SHA-256 with 8192 rotated iterations", c, ok)
+	}
+
+	rotated, err = adm.RotateUserSCRAMs(ctx, BulkSCRAM{Mechanisms: []ScramMechanism{ScramSha512}}, map[string]string{"alice": "password3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rotated["alice"].Err; !errors.Is(err, kerr.ResourceNotFound) {
+		t.Errorf("alice: got rotate error %v != exp ResourceNotFound for a mechanism without a password", err)
+	}
+}
+
+func TestAlterUserSCRAMsKeepsFirstError(t *testing.T) {
+	c, adm := newFakeCluster(t, nil)
+
+	// A user altered for two mechanisms has two results; a failure for
+	// the first must not be hidden by a success for the second.
+	c.ControlKey(kmsg.AlterUserSCRAMCredentials.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		resp := kreq.ResponseKind().(*kmsg.AlterUserSCRAMCredentialsResponse)
+		for _, code := range []int16{kerr.UnsupportedSaslMechanism.Code, 0} {
+			r := kmsg.NewAlterUserSCRAMCredentialsResponseResult()
+			r.User = "alice"
+			r.ErrorCode = code
+			resp.Results = append(resp.Results, r)
+		}
+		return resp, nil, true
+	})
+
+	altered, err := adm.AlterUserSCRAMs(context.Background(), nil, []UpsertSCRAM{
+		{User: "alice", Mechanism: ScramSha256, Iterations: 4096, Password: "password"},
+		{User: "alice", Mechanism: ScramSha512, Iterations: 4096, Password: "password"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := altered["alice"].Err; !errors.Is(err, kerr.UnsupportedSaslMechanism) {
+		t.Errorf("got alter error %v != exp UnsupportedSaslMechanism", err)
+	}
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"sort"
+	"sync"
 
 	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
 )
 
 var includeAuthOps = "include_auth_ops"
@@ -43,6 +46,362 @@ func (cl *Client) ListTopicsWithInternal(
 	return m.Topics, nil
 }
 
+// ListTopicsPaged lists all topics a page at a time, calling fn with each page
+// rather than returning every topic at once. This bounds the memory used when
+// listing clusters with hundreds of thousands of topics or partitions.
+// Internal topics are not included, same as ListTopics.
+//
+// A page contains up to pageSize partitions, but a topic is never split
+// across pages: a topic with more partitions than pageSize is passed to fn in
+// a page of its own. If pageSize is not positive, this uses 2000, the most
+// partitions Kafka returns in a single DescribeTopicPartitions response.
+//
+// If the cluster supports DescribeTopicPartitions (Kafka 3.8+), topics are
+// paged through with one request per page. Otherwise, this issues a single
+// metadata request and passes the topics to fn in pages, which bounds the
+// memory used by fn but not the response itself.
+//
+// If fn returns an error, listing stops and the error is returned. This also
+// returns an error if a request fails to be issued, or an *AuthError.
+func (cl *Client) ListTopicsPaged(
+	ctx context.Context,
+	pageSize int,
+	fn func(TopicDetails) error,
+) error {
+	if pageSize <= 0 {
+		pageSize = 2000
+	}
+	req := kmsg.NewPtrDescribeTopicPartitionsRequest()
+	supported, err := cl.supportsKey(ctx, req.Key())
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return cl.listTopicsPagedMetadata(ctx, pageSize, fn)
+	}
+
+	req.ResponsePartitionLimit = int32(pageSize)
+
+	// If a page ends partway through a topic, we keep the topic's partial
+	// details and continue it in the next page.
+	var partial *TopicDetail
+	for {
+		resp, err := req.RequestWith(ctx, cl.cl)
+		if err != nil {
+			return err
+		}
+		page := make(TopicDetails)
+		if partial != nil {
+			page[partial.Topic] = *partial
+			partial = nil
+		}
+		for _, t := range resp.Topics {
+			if err := maybeAuthErr(int16(t.ErrorCode)); err != nil {
+				return err
+			}
+			if t.Topic == nil {
+				continue
+			}
+			td, exists := page[*t.Topic]
+			if !exists {
+				td = TopicDetail{
+					Topic:      *t.Topic,
+					ID:         t.TopicID,
+					IsInternal: t.IsInternal,
+					Partitions: make(PartitionDetails),
+					Err:        kerr.ErrorForCode(int16(t.ErrorCode)),
+				}
+			}
+			for _, p := range t.Partitions {
+				td.Partitions[p.Partition] = PartitionDetail{
+					Topic:     td.Topic,
+					Partition: p.Partition,
+
+					Leader:          p.LeaderID,
+					LeaderEpoch:     p.LeaderEpoch,
+					Replicas:        p.Replicas,
+					ISR:             p.ISR,
+					OfflineReplicas: p.OfflineReplicas,
+
+					Err: kerr.ErrorForCode(int16(p.ErrorCode)),
+				}
+			}
+			page[td.Topic] = td
+		}
+
+		next := resp.NextCursor
+		if next != nil {
+			if td, exists := page[next.Topic]; exists && next.Partition > 0 {
+				partial = &td
+				delete(page, next.Topic)
+			}
+			req.Cursor = &kmsg.DescribeTopicPartitionsRequestCursor{
+				Topic:     next.Topic,
+				Partition: next.Partition,
+			}
+		}
+		page.FilterInternal()
+		if len(page) > 0 {
+			if err := fn(page); err != nil {
+				return err
+			}
+		}
+		if next == nil {
+			return nil
+		}
+	}
+}
+
+// supportsKey returns whether both the client (see kgo.MaxVersions) and a
+// broker in the cluster support the given request key.
+func (cl *Client) supportsKey(ctx context.Context, key int16) (bool, error) {
+	if maxVersions, ok := cl.cl.OptValue(kgo.MaxVersions).(*kversion.Versions); ok && maxVersions != nil {
+		if _, ok := maxVersions.LookupMaxKeyVersion(key); !ok {
+			return false, nil
+		}
+	}
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, cl.cl)
+	if err != nil {
+		return false, err
+	}
+	for _, k := range resp.ApiKeys {
+		if k.ApiKey == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// listTopicsPagedMetadata is ListTopicsPaged for clusters that do not
+// support DescribeTopicPartitions.
+func (cl *Client) listTopicsPagedMetadata(
+	ctx context.Context,
+	pageSize int,
+	fn func(TopicDetails) error,
+) error {
+	all, err := cl.ListTopics(ctx)
+	if err != nil {
+		return err
+	}
+	return pageTopicDetails(all, pageSize, fn)
+}
+
+// pageTopicDetails calls fn with pages of topics sorted by name, each with up
+// to pageSize partitions unless a single topic has more. Topics are removed
+// from all as they are paged.
+func pageTopicDetails(all TopicDetails, pageSize int, fn func(TopicDetails) error) error {
+	page := make(TopicDetails)
+	var n int
+	for _, topic := range all.Names() {
+		td := all[topic]
+		if n > 0 && n+len(td.Partitions) > pageSize {
+			if err := fn(page); err != nil {
+				return err
+			}
+			page, n = make(TopicDetails), 0
+		}
+		page[topic] = td
+		n += len(td.Partitions)
+		delete(all, topic)
+	}
+	if len(page) > 0 {
+		return fn(page)
+	}
+	return nil
+}
+
+// DetailedPartition contains everything DescribeTopicsDetailed returns for a
+// single partition.
+type DetailedPartition struct {
+	Detail      PartitionDetail // Detail is the partition's metadata: leader, leader epoch, replicas, and ISR.
+	StartOffset ListedOffset    // StartOffset is the partition's log start offset.
+	EndOffset   ListedOffset    // EndOffset is the partition's high watermark.
+}
+
+// NumRecords returns the number of offsets between the start and end offset,
+// or -1 if either offset could not be listed. This is an upper bound on the
+// number of records in the partition: compaction and transaction markers
+// mean there may be fewer.
+func (d DetailedPartition) NumRecords() int64 {
+	if d.StartOffset.Err != nil || d.EndOffset.Err != nil || d.StartOffset.Offset < 0 || d.EndOffset.Offset < 0 {
+		return -1
+	}
+	return d.EndOffset.Offset - d.StartOffset.Offset
+}
+
+// DetailedPartitions contains per-partition details for a topic.
+type DetailedPartitions map[int32]DetailedPartition
+
+// Sorted returns the partitions in sorted order.
+func (ds DetailedPartitions) Sorted() []DetailedPartition {
+	s := make([]DetailedPartition, 0, len(ds))
+	for _, d := range ds {
+		s = append(s, d)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Detail.Partition < s[j].Detail.Partition })
+	return s
+}
+
+// DetailedTopic contains everything DescribeTopicsDetailed returns for a
+// single topic. If the topic could not be loaded from metadata, only Topic and
+// Err are set.
+type DetailedTopic struct {
+	Topic      string             // Topic is the topic these details are for.
+	ID         TopicID            // ID is the topic's ID, or all 0 if the broker does not support IDs.
+	IsInternal bool               // IsInternal is whether the topic is an internal topic.
+	Partitions DetailedPartitions // Partitions contains details about the topic's partitions.
+
+	Configs    []Config // Configs contains the topic's configs, if they could be described.
+	ConfigsErr error    // ConfigsErr is non-nil if the topic's configs could not be described.
+
+	Err error // Err is non-nil if the topic could not be loaded.
+}
+
+// DetailedTopics contains details for many topics, as returned from
+// DescribeTopicsDetailed.
+type DetailedTopics map[string]DetailedTopic
+
+// Names returns a sorted list of all topic names.
+func (ds DetailedTopics) Names() []string {
+	all := make([]string, 0, len(ds))
+	for t := range ds {
+		all = append(all, t)
+	}
+	sort.Strings(all)
+	return all
+}
+
+// Sorted returns all topics in sorted order.
+func (ds DetailedTopics) Sorted() []DetailedTopic {
+	s := make([]DetailedTopic, 0, len(ds))
+	for _, d := range ds {
+		s = append(s, d)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i].Topic < s[j].Topic })
+	return s
+}
+
+// Error iterates over all topics and returns the first topic load error
+// encountered, if any. This does not check config or offset errors.
+func (ds DetailedTopics) Error() error {
+	for _, d := range ds {
+		if d.Err != nil {
+			return d.Err
+		}
+	}
+	return nil
+}
+
+// DescribeTopicsDetailed returns metadata, start and end offsets, and configs
+// for the requested topics in one report. If no topics are specified, all
+// non-internal topics are described.
+//
+// This first issues a metadata request, and then concurrently lists start
+// offsets, lists end offsets, and describes configs for every topic that was
+// loaded. Errors from the latter requests do not fail the function; instead,
+// any partition whose offsets could not be listed has the error in its
+// StartOffset or EndOffset, and any topic whose configs could not be described
+// has ConfigsErr set.
+//
+// This returns an error if the metadata request fails to be issued, or an
+// *AuthError.
+func (cl *Client) DescribeTopicsDetailed(ctx context.Context, topics ...string) (DetailedTopics, error) {
+	var (
+		tds TopicDetails
+		err error
+	)
+	if len(topics) == 0 {
+		tds, err = cl.ListTopics(ctx)
+	} else {
+		tds, err = cl.ListTopicsWithInternal(ctx, topics...)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dts := make(DetailedTopics, len(tds))
+	var loaded []string
+	for _, td := range tds {
+		dt := DetailedTopic{
+			Topic:      td.Topic,
+			ID:         td.ID,
+			IsInternal: td.IsInternal,
+			Err:        td.Err,
+		}
+		if td.Err == nil {
+			loaded = append(loaded, td.Topic)
+			dt.Partitions = make(DetailedPartitions, len(td.Partitions))
+			for p, pd := range td.Partitions {
+				dt.Partitions[p] = DetailedPartition{Detail: pd}
+			}
+		}
+		dts[td.Topic] = dt
+	}
+	if len(loaded) == 0 {
+		return dts, nil
+	}
+
+	var (
+		wg               sync.WaitGroup
+		starts, ends     ListedOffsets
+		configs          ResourceConfigs
+		startErr, endErr error
+		configsErr       error
+	)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		starts, startErr = cl.ListStartOffsets(ctx, loaded...)
+	}()
+	go func() {
+		defer wg.Done()
+		ends, endErr = cl.ListEndOffsets(ctx, loaded...)
+	}()
+	go func() {
+		defer wg.Done()
+		configs, configsErr = cl.DescribeTopicConfigs(ctx, loaded...)
+	}()
+	wg.Wait()
+
+	var ae *AuthError
+	for _, err := range []error{startErr, endErr, configsErr} {
+		if errors.As(err, &ae) {
+			return nil, err
+		}
+	}
+
+	// A partition or topic that is missing from a response failed within
+	// a shard (or the whole request failed); we use the request error.
+	offset := func(l ListedOffsets, err error, t string, p int32) ListedOffset {
+		if o, ok := l.Lookup(t, p); ok {
+			return o
+		}
+		if err == nil {
+			err = kerr.UnknownTopicOrPartition
+		}
+		return ListedOffset{Topic: t, Partition: p, Timestamp: -1, Offset: -1, LeaderEpoch: -1, Err: err}
+	}
+	for _, t := range loaded {
+		dt := dts[t]
+		for p, dp := range dt.Partitions {
+			dp.StartOffset = offset(starts, startErr, t, p)
+			dp.EndOffset = offset(ends, endErr, t, p)
+			dt.Partitions[p] = dp
+		}
+		rc, err := configs.On(t, nil)
+		switch {
+		case err == nil:
+			dt.Configs, dt.ConfigsErr = rc.Configs, rc.Err
+		case configsErr != nil:
+			dt.ConfigsErr = configsErr
+		default:
+			dt.ConfigsErr = err
+		}
+		dts[t] = dt
+	}
+	return dts, nil
+}
+
 // CreateTopicResponse contains the response for an individual created topic.
 type CreateTopicResponse struct {
 	Topic             string            // Topic is the topic that was created.
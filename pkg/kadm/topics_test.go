@@ -0,0 +1,153 @@
+package kadm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPageTopicDetails(t *testing.T) {
+	td := func(topic string, partitions int) TopicDetail {
+		d := TopicDetail{Topic: topic, Partitions: make(PartitionDetails)}
+		for p := range int32(partitions) {
+			d.Partitions[p] = PartitionDetail{Topic: topic, Partition: p}
+		}
+		return d
+	}
+	all := TopicDetails{
+		"a": td("a", 3),
+		"b": td("b", 3),
+		"c": td("c", 10), // larger than a page, so it is alone
+		"d": td("d", 1),
+		"e": td("e", 4),
+	}
+	var pages [][]string
+	if err := pageTopicDetails(all, 6, func(page TopicDetails) error {
+		pages = append(pages, page.Names())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if exp := [][]string{{"a", "b"}, {"c"}, {"d", "e"}}; !reflect.DeepEqual(pages, exp) {
+		t.Errorf("got pages %v != exp %v", pages, exp)
+	}
+	if len(all) != 0 {
+		t.Errorf("got %d topics remaining after paging, exp 0", len(all))
+	}
+
+	errStop := fmt.Errorf("stop")
+	var n int
+	if err := pageTopicDetails(TopicDetails{"a": td("a", 6), "b": td("b", 6)}, 6, func(TopicDetails) error {
+		n++
+		return errStop
+	}); err != errStop || n != 1 {
+		t.Errorf("got err %v after %d pages, exp stop after 1", err, n)
+	}
+}
+
+func TestDescribeTopicsDetailed(t *testing.T) {
+	ctx := context.Background()
+	c, adm := newFakeCluster(t, []string{"foo", "bar"})
+
+	producer, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+	for range 3 {
+		if err := producer.ProduceSync(ctx, &kgo.Record{Topic: "foo", Partition: 0}).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var toDelete Offsets
+	toDelete.Add(Offset{Topic: "foo", Partition: 0, At: 1})
+	if _, err := adm.DeleteRecords(ctx, toDelete); err != nil {
+		t.Fatal(err)
+	}
+	retention := "1000"
+	if _, err := adm.AlterTopicConfigs(ctx, []AlterConfig{{Name: "retention.ms", Value: &retention}}, "foo"); err != nil {
+		t.Fatal(err)
+	}
+
+	// kfake describes every topic's configs successfully, so we answer
+	// DescribeConfigs with the real response, failing bar.
+	base := kmsg.NewPtrDescribeConfigsRequest()
+	for _, topic := range []string{"foo", "bar"} {
+		r := kmsg.NewDescribeConfigsRequestResource()
+		r.ResourceType = kmsg.ConfigResourceTypeTopic
+		r.ResourceName = topic
+		base.Resources = append(base.Resources, r)
+	}
+	baseResp, err := base.RequestWith(ctx, adm.cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.ControlKey(kmsg.DescribeConfigs.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.DescribeConfigsRequest)
+		resp := req.ResponseKind().(*kmsg.DescribeConfigsResponse)
+		for _, rr := range req.Resources {
+			for _, br := range baseResp.Resources {
+				if br.ResourceName != rr.ResourceName {
+					continue
+				}
+				if br.ResourceName == "bar" {
+					br.ErrorCode = kerr.InvalidRequest.Code
+					br.Configs = nil
+				}
+				resp.Resources = append(resp.Resources, br)
+			}
+		}
+		return resp, nil, true
+	})
+
+	dts, err := adm.DescribeTopicsDetailed(ctx, "foo", "bar", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dts) != 3 {
+		t.Fatalf("got %d topics %v, exp 3", len(dts), dts.Names())
+	}
+
+	foo := dts["foo"]
+	if foo.Err != nil || foo.ConfigsErr != nil || len(foo.Partitions) != 3 {
+		t.Fatalf("foo: got err %v, configs err %v, %d partitions; exp no errors and 3 partitions", foo.Err, foo.ConfigsErr, len(foo.Partitions))
+	}
+	var found bool
+	for _, c := range foo.Configs {
+		if c.Key == "retention.ms" {
+			found = c.MaybeValue() == retention
+		}
+	}
+	if !found {
+		t.Errorf("foo: retention.ms %s not in configs %v", retention, foo.Configs)
+	}
+	p0 := foo.Partitions[0]
+	if p0.StartOffset.Offset != 1 || p0.EndOffset.Offset != 3 || p0.NumRecords() != 2 {
+		t.Errorf("foo 0: got start %d, end %d, %d records; exp 1, 3, 2", p0.StartOffset.Offset, p0.EndOffset.Offset, p0.NumRecords())
+	}
+	if p0.Detail.Leader != 0 || p0.Detail.Partition != 0 {
+		t.Errorf("foo 0: got detail %+v, exp partition 0 led by broker 0", p0.Detail)
+	}
+	if p1 := foo.Partitions[1]; p1.StartOffset.Offset != 0 || p1.EndOffset.Offset != 0 || p1.NumRecords() != 0 {
+		t.Errorf("foo 1: got start %d, end %d, %d records; exp an empty partition", p1.StartOffset.Offset, p1.EndOffset.Offset, p1.NumRecords())
+	}
+
+	bar := dts["bar"]
+	if bar.Err != nil || len(bar.Partitions) != 3 || !errors.Is(bar.ConfigsErr, kerr.InvalidRequest) || bar.Configs != nil {
+		t.Errorf("bar: got err %v, %d partitions, configs err %v; exp only an InvalidRequest configs error", bar.Err, len(bar.Partitions), bar.ConfigsErr)
+	}
+
+	if missing := dts["missing"]; !errors.Is(missing.Err, kerr.UnknownTopicOrPartition) || missing.Partitions != nil {
+		t.Errorf("missing: got %+v, exp UnknownTopicOrPartition", missing)
+	}
+	if !errors.Is(dts.Error(), kerr.UnknownTopicOrPartition) {
+		t.Errorf("got error %v, exp UnknownTopicOrPartition", dts.Error())
+	}
+}
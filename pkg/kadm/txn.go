@@ -3,7 +3,10 @@ package kadm
 import (
 	"context"
 	"errors"
+	"fmt"
+	"regexp"
 	"sort"
+	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
@@ -499,7 +502,8 @@ func (cl *Client) ListTransactions(ctx context.Context, producerIDs []int64, fil
 // states. By default, this returns all transactions you have DESCRIBE access
 // to. Producer IDs can be specified to filter for transactions from the given
 // producer. TxIDPatterns, a re2 regular expression, is used to further filter
-// by transactional ID. This requires Kafka 4.1+.
+// by transactional ID. The pattern is pushed down to brokers that support it
+// (Kafka 4.1+); transactions from older brokers are filtered client side.
 //
 // This may return *ShardErrors or *AuthError.
 func (cl *Client) ListTransactionsByTxPattern(ctx context.Context, producerIDs []int64, filterStates []string, txIDPattern string) (ListedTransactions, error) {
@@ -509,6 +513,13 @@ func (cl *Client) ListTransactionsByTxPattern(ctx context.Context, producerIDs [
 	if txIDPattern != "" {
 		req.TransactionalIDPattern = &txIDPattern
 	}
+	var fallback *regexp.Regexp
+	if txIDPattern != "" {
+		var err error
+		if fallback, err = regexp.Compile(txIDPattern); err != nil {
+			return nil, fmt.Errorf("invalid transactional ID pattern: %w", err)
+		}
+	}
 	shards := cl.cl.RequestSharded(ctx, req)
 	list := make(ListedTransactions)
 	return list, shardErrEachBroker(req, shards, func(b BrokerDetail, kr kmsg.Response) error {
@@ -520,6 +531,9 @@ func (cl *Client) ListTransactionsByTxPattern(ctx context.Context, producerIDs [
 			return err
 		}
 		for _, t := range resp.TransactionStates {
+			if !fallbackFilterTxn(resp.Version, fallback, t.TransactionalID) {
+				continue
+			}
 			list[t.TransactionalID] = ListedTransaction{ // txnID lives on one coordinator, no need to exist-check
 				Coordinator: b.NodeID,
 				TxnID:       t.TransactionalID,
@@ -531,6 +545,13 @@ func (cl *Client) ListTransactionsByTxPattern(ctx context.Context, producerIDs [
 	})
 }
 
+// fallbackFilterTxn returns whether a transaction listed in a ListTransactions
+// response of the given version should be kept. Brokers before v2 (Kafka 4.1)
+// ignore the transactional ID pattern.
+func fallbackFilterTxn(version int16, pattern *regexp.Regexp, txnID string) bool {
+	return version >= 2 || pattern == nil || pattern.MatchString(txnID)
+}
+
 // TxnMarkers marks the end of a partition: the producer ID / epoch doing the
 // writing, whether this is a commit, the coordinator epoch of the broker we
 // are writing to (for fencing), and the topics and partitions that we are
@@ -774,3 +795,156 @@ func (cl *Client) WriteTxnMarkers(ctx context.Context, markers ...TxnMarkers) (T
 		return nil
 	})
 }
+
+// FindHangingTransactions returns producers that have an open transaction on
+// a partition that has not been written to in at least olderThan, and whose
+// transaction the transaction coordinator does not know about (or knows about,
+// but not for the partition in question). Hanging transactions block the last
+// stable offset of a partition, meaning read committed consumers cannot
+// consume past the start of the transaction. If the input set is empty, this
+// searches all partitions.
+//
+// A reasonable olderThan is the broker's transaction.max.timeout.ms (15
+// minutes by default): any transaction open longer than that should have been
+// aborted by the coordinator.
+//
+// The returned producers can be passed to AbortHangingTransactions. This may
+// return *ShardErrors or *AuthError from any of the underlying requests; on
+// error, the returned producers are nil.
+func (cl *Client) FindHangingTransactions(ctx context.Context, s TopicsSet, olderThan time.Duration) ([]DescribedProducer, error) {
+	described, err := cl.DescribeProducers(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-olderThan).UnixMilli()
+	var (
+		candidates []DescribedProducer
+		pids       []int64
+		seenPID    = make(map[int64]bool)
+	)
+	described.EachProducer(func(d DescribedProducer) {
+		if d.CurrentTxnStartOffset < 0 || d.LastTimestamp > cutoff {
+			return
+		}
+		candidates = append(candidates, d)
+		if !seenPID[d.ProducerID] {
+			seenPID[d.ProducerID] = true
+			pids = append(pids, d.ProducerID)
+		}
+	})
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	// We now look for what the coordinators think. If a coordinator has
+	// an ongoing transaction for the producer ID that includes the
+	// partition, the transaction is just slow, not hanging.
+	listed, err := cl.ListTransactions(ctx, pids, nil)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[int64]TopicsSet)
+	if txnIDs := listed.TransactionalIDs(); len(txnIDs) > 0 {
+		txns, err := cl.DescribeTransactions(ctx, txnIDs...)
+		if err != nil {
+			return nil, err
+		}
+		txns.Each(func(t DescribedTransaction) {
+			if t.Err != nil {
+				return
+			}
+			switch t.State {
+			case "Ongoing", "PrepareCommit", "PrepareAbort":
+				active[t.ProducerID] = t.Topics
+			}
+		})
+	}
+
+	var hanging []DescribedProducer
+	for _, d := range candidates {
+		if ps, ok := active[d.ProducerID][d.Topic]; ok {
+			if _, ok := ps[d.Partition]; ok {
+				continue
+			}
+		}
+		hanging = append(hanging, d)
+	}
+	sort.Slice(hanging, func(i, j int) bool { return hanging[i].Less(&hanging[j]) })
+	return hanging, nil
+}
+
+// AbortTransactionsAck is an explicit acknowledgement that aborting
+// transactions out of band is dangerous. The only valid value is
+// IKnowAbortingTransactionsIsDangerous.
+type AbortTransactionsAck string
+
+// IKnowAbortingTransactionsIsDangerous acknowledges that writing abort markers
+// for a transaction that is not actually hanging breaks the transaction: the
+// producer may continue writing to a partition whose transaction was aborted
+// underneath it, and the coordinator may later try to commit a transaction
+// whose data has already been aborted.
+const IKnowAbortingTransactionsIsDangerous AbortTransactionsAck = "I know aborting transactions is dangerous"
+
+// AbortHangingTransactions writes abort markers for each producer's open
+// transaction on the producer's topic and partition, unblocking the last
+// stable offset of the partition. This is an advanced admin operation to
+// remediate transactions that the coordinator has lost track of, and is the
+// equivalent of Kafka's kafka-transactions.sh abort command.
+//
+// You must pass IKnowAbortingTransactionsIsDangerous as the ack. Producers
+// should be the return from FindHangingTransactions, or be taken directly from
+// a DescribeProducers response, since the producer epoch and coordinator epoch
+// must match the open transaction. Any producer without an open transaction
+// (a negative CurrentTxnStartOffset) fails the entire request before anything
+// is written.
+//
+// This may return *ShardErrors or *AuthError.
+func (cl *Client) AbortHangingTransactions(ctx context.Context, ack AbortTransactionsAck, producers ...DescribedProducer) (TxnMarkersResponses, error) {
+	if ack != IKnowAbortingTransactionsIsDangerous {
+		return nil, errors.New("refusing to abort transactions without IKnowAbortingTransactionsIsDangerous")
+	}
+	markers, err := abortMarkers(producers)
+	if err != nil {
+		return nil, err
+	}
+	if len(markers) == 0 {
+		return make(TxnMarkersResponses), nil
+	}
+	return cl.WriteTxnMarkers(ctx, markers...)
+}
+
+// abortMarkers groups producers into one abort marker per producer ID, epoch,
+// and coordinator epoch. A producer's coordinator epoch is -1 if the partition
+// leader has not seen a marker from the coordinator; like Kafka's abort
+// command, we write such markers with coordinator epoch 0.
+func abortMarkers(producers []DescribedProducer) ([]TxnMarkers, error) {
+	type key struct {
+		pid        int64
+		epoch      int16
+		coordEpoch int32
+	}
+	var (
+		markers []TxnMarkers
+		idx     = make(map[key]int)
+	)
+	for _, p := range producers {
+		if p.CurrentTxnStartOffset < 0 {
+			return nil, fmt.Errorf("producer %d has no open transaction on %s[%d]", p.ProducerID, p.Topic, p.Partition)
+		}
+		k := key{p.ProducerID, p.ProducerEpoch, max(p.CoordinatorEpoch, 0)}
+		i, ok := idx[k]
+		if !ok {
+			i = len(markers)
+			idx[k] = i
+			markers = append(markers, TxnMarkers{
+				ProducerID:       p.ProducerID,
+				ProducerEpoch:    p.ProducerEpoch,
+				CoordinatorEpoch: k.coordEpoch,
+				Topics:           make(TopicsSet),
+			})
+		}
+		markers[i].Topics.Add(p.Topic, p.Partition)
+	}
+	return markers, nil
+}
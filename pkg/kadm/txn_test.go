@@ -0,0 +1,226 @@
+package kadm
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFallbackFilterTxn(t *testing.T) {
+	re := regexp.MustCompile("^orders-")
+	for _, test := range []struct {
+		version int16
+		pattern *regexp.Regexp
+		txnID   string
+		exp     bool
+	}{
+		{2, re, "payments-1", true}, // the broker filtered
+		{1, re, "payments-1", false},
+		{1, re, "orders-1", true},
+		{1, nil, "payments-1", true},
+	} {
+		if got := fallbackFilterTxn(test.version, test.pattern, test.txnID); got != test.exp {
+			t.Errorf("v%d %s: got %v != exp %v", test.version, test.txnID, got, test.exp)
+		}
+	}
+}
+
+func TestAbortMarkers(t *testing.T) {
+	producers := []DescribedProducer{
+		{Topic: "foo", Partition: 0, ProducerID: 1, ProducerEpoch: 2, CoordinatorEpoch: -1, CurrentTxnStartOffset: 10},
+		{Topic: "foo", Partition: 1, ProducerID: 1, ProducerEpoch: 2, CoordinatorEpoch: 0, CurrentTxnStartOffset: 3},
+		{Topic: "bar", Partition: 0, ProducerID: 1, ProducerEpoch: 2, CoordinatorEpoch: 5, CurrentTxnStartOffset: 0},
+		{Topic: "bar", Partition: 1, ProducerID: 2, ProducerEpoch: 0, CoordinatorEpoch: -1, CurrentTxnStartOffset: 7},
+	}
+	got, err := abortMarkers(producers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []TxnMarkers{
+		// An unknown (-1) coordinator epoch is written as 0, and
+		// thus grouped with the producer's epoch 0 marker.
+		{ProducerID: 1, ProducerEpoch: 2, CoordinatorEpoch: 0, Topics: TopicsSet{"foo": {0: {}, 1: {}}}},
+		{ProducerID: 1, ProducerEpoch: 2, CoordinatorEpoch: 5, Topics: TopicsSet{"bar": {0: {}}}},
+		{ProducerID: 2, ProducerEpoch: 0, CoordinatorEpoch: 0, Topics: TopicsSet{"bar": {1: {}}}},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+
+	producers = append(producers, DescribedProducer{Topic: "foo", Partition: 2, ProducerID: 3, CurrentTxnStartOffset: -1})
+	if _, err := abortMarkers(producers); err == nil {
+		t.Error("expected error for a producer without an open transaction")
+	}
+}
+
+func TestFindAbortHangingTransactions(t *testing.T) {
+	var (
+		old    = time.Now().Add(-time.Hour).UnixMilli()
+		recent = time.Now().UnixMilli()
+		ctx    = context.Background()
+	)
+	c, adm := newFakeCluster(t, []string{"foo"},
+		kmsg.NewPtrDescribeProducersRequest(),
+		kmsg.NewPtrListTransactionsRequest(),
+		kmsg.NewPtrDescribeTransactionsRequest(),
+		kmsg.NewPtrWriteTxnMarkersRequest(),
+	)
+
+	// foo[0]: pid 1 has an old open transaction that the coordinator
+	// does not know about at all: hanging.
+	//
+	// foo[1]: pid 2 has an old open transaction that the coordinator
+	// has ongoing including foo[1]: slow, not hanging.
+	//
+	// foo[2]: pid 2 also has an old open transaction here, but the
+	// coordinator's transaction does not include foo[2]: hanging. pid 3
+	// has no open transaction, and pid 4's transaction is recent.
+	active := map[int32][]kmsg.DescribeProducersResponseTopicPartitionActiveProducer{
+		0: {{ProducerID: 1, ProducerEpoch: 2, LastTimestamp: old, CoordinatorEpoch: -1, CurrentTxnStartOffset: 10}},
+		1: {{ProducerID: 2, ProducerEpoch: 1, LastTimestamp: old, CoordinatorEpoch: 3, CurrentTxnStartOffset: 5}},
+		2: {
+			{ProducerID: 2, ProducerEpoch: 1, LastTimestamp: old, CoordinatorEpoch: 3, CurrentTxnStartOffset: 8},
+			{ProducerID: 3, ProducerEpoch: 0, LastTimestamp: old, CoordinatorEpoch: 0, CurrentTxnStartOffset: -1},
+			{ProducerID: 4, ProducerEpoch: 0, LastTimestamp: recent, CoordinatorEpoch: 0, CurrentTxnStartOffset: 9},
+		},
+	}
+	c.ControlKey(kmsg.DescribeProducers.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.DescribeProducersRequest)
+		resp := req.ResponseKind().(*kmsg.DescribeProducersResponse)
+		for _, rt := range req.Topics {
+			st := kmsg.NewDescribeProducersResponseTopic()
+			st.Topic = rt.Topic
+			for _, p := range rt.Partitions {
+				sp := kmsg.NewDescribeProducersResponseTopicPartition()
+				sp.Partition = p
+				sp.ActiveProducers = active[p]
+				st.Partitions = append(st.Partitions, sp)
+			}
+			resp.Topics = append(resp.Topics, st)
+		}
+		return resp, nil, true
+	})
+
+	var listedPIDs []int64
+	c.ControlKey(kmsg.ListTransactions.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.ListTransactionsRequest)
+		listedPIDs = append(listedPIDs, req.ProducerIDFilters...)
+		resp := req.ResponseKind().(*kmsg.ListTransactionsResponse)
+		st := kmsg.NewListTransactionsResponseTransactionState()
+		st.TransactionalID = "txn-2"
+		st.ProducerID = 2
+		st.TransactionState = "Ongoing"
+		resp.TransactionStates = append(resp.TransactionStates, st)
+		return resp, nil, true
+	})
+	c.ControlKey(kmsg.DescribeTransactions.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.DescribeTransactionsRequest)
+		resp := req.ResponseKind().(*kmsg.DescribeTransactionsResponse)
+		for _, id := range req.TransactionalIDs {
+			st := kmsg.NewDescribeTransactionsResponseTransactionState()
+			st.TransactionalID = id
+			st.State = "Ongoing"
+			st.ProducerID = 2
+			st.ProducerEpoch = 1
+			st.Topics = []kmsg.DescribeTransactionsResponseTransactionStateTopic{{Topic: "foo", Partitions: []int32{1}}}
+			resp.TransactionStates = append(resp.TransactionStates, st)
+		}
+		return resp, nil, true
+	})
+
+	s := make(TopicsSet)
+	s.Add("foo") // all partitions, loaded from metadata
+	hanging, err := adm.FindHangingTransactions(ctx, s, 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []DescribedProducer{
+		{Leader: 0, Topic: "foo", Partition: 0, ProducerID: 1, ProducerEpoch: 2, LastTimestamp: old, CoordinatorEpoch: -1, CurrentTxnStartOffset: 10},
+		{Leader: 0, Topic: "foo", Partition: 2, ProducerID: 2, ProducerEpoch: 1, LastTimestamp: old, CoordinatorEpoch: 3, CurrentTxnStartOffset: 8},
+	}
+	if !reflect.DeepEqual(hanging, exp) {
+		t.Errorf("got hanging %v != exp %v", hanging, exp)
+	}
+	sort.Slice(listedPIDs, func(i, j int) bool { return listedPIDs[i] < listedPIDs[j] })
+	if !reflect.DeepEqual(listedPIDs, []int64{1, 2}) {
+		t.Errorf("got listed producer IDs %v != exp [1 2]", listedPIDs)
+	}
+
+	if _, err := adm.AbortHangingTransactions(ctx, "", hanging...); err == nil {
+		t.Error("expected error aborting without the ack")
+	}
+
+	var written []kmsg.WriteTxnMarkersRequestMarker
+	c.ControlKey(kmsg.WriteTxnMarkers.Int16(), func(kreq kmsg.Request) (kmsg.Response, error, bool) {
+		c.KeepControl()
+		req := kreq.(*kmsg.WriteTxnMarkersRequest)
+		resp := req.ResponseKind().(*kmsg.WriteTxnMarkersResponse)
+		for _, m := range req.Markers {
+			written = append(written, m)
+			sm := kmsg.NewWriteTxnMarkersResponseMarker()
+			sm.ProducerID = m.ProducerID
+			for _, rt := range m.Topics {
+				st := kmsg.NewWriteTxnMarkersResponseMarkerTopic()
+				st.Topic = rt.Topic
+				for _, p := range rt.Partitions {
+					sp := kmsg.NewWriteTxnMarkersResponseMarkerTopicPartition()
+					sp.Partition = p
+					st.Partitions = append(st.Partitions, sp)
+				}
+				sm.Topics = append(sm.Topics, st)
+			}
+			resp.Markers = append(resp.Markers, sm)
+		}
+		return resp, nil, true
+	})
+
+	if _, err := adm.AbortHangingTransactions(ctx, IKnowAbortingTransactionsIsDangerous, append(hanging, DescribedProducer{Topic: "foo", Partition: 2, ProducerID: 3, CurrentTxnStartOffset: -1})...); err == nil {
+		t.Error("expected error aborting a producer without an open transaction")
+	}
+	if len(written) != 0 {
+		t.Errorf("unexpectedly wrote markers %v when a producer had no open transaction", written)
+	}
+
+	resps, err := adm.AbortHangingTransactions(ctx, IKnowAbortingTransactionsIsDangerous, hanging...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Slice(written, func(i, j int) bool { return written[i].ProducerID < written[j].ProducerID })
+	if len(written) != 2 {
+		t.Fatalf("got %d markers written != exp 2", len(written))
+	}
+	// The coordinator epochs of the markers are checked in
+	// TestAbortMarkers.
+	for i, exp := range []struct {
+		pid       int64
+		epoch     int16
+		partition int32
+	}{
+		{1, 2, 0},
+		{2, 1, 2},
+	} {
+		m := written[i]
+		if m.ProducerID != exp.pid || m.ProducerEpoch != exp.epoch || m.Committed ||
+			len(m.Topics) != 1 || m.Topics[0].Topic != "foo" || !reflect.DeepEqual(m.Topics[0].Partitions, []int32{exp.partition}) {
+			t.Errorf("marker %d: got %+v, exp abort for pid %d epoch %d on foo[%d]", i, m, exp.pid, exp.epoch, exp.partition)
+		}
+	}
+	for _, pid := range []int64{1, 2} {
+		for _, p := range resps[pid].Topics["foo"].Partitions {
+			if p.Err != nil {
+				t.Errorf("pid %d foo[%d]: unexpected error %v", pid, p.Partition, p.Err)
+			}
+		}
+		if len(resps[pid].Topics["foo"].Partitions) != 1 {
+			t.Errorf("pid %d: got %d partition responses != exp 1", pid, len(resps[pid].Topics["foo"].Partitions))
+		}
+	}
+}
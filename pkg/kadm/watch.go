@@ -0,0 +1,359 @@
+package kadm
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+// TopicEventType is the type of change in a TopicEvent.
+type TopicEventType int8
+
+const (
+	// TopicEventError indicates that a metadata poll failed. The watch
+	// continues and retries on the next interval.
+	TopicEventError TopicEventType = iota
+	// TopicEventPartitionAdded indicates a partition that was not present
+	// in the prior poll, either because partitions were added to a topic
+	// or because the topic was created.
+	TopicEventPartitionAdded
+	// TopicEventTopicDeleted indicates that a topic that was present in
+	// the prior poll no longer exists.
+	TopicEventTopicDeleted
+	// TopicEventLeaderChange indicates that a partition moved from one
+	// leader to another.
+	TopicEventLeaderChange
+	// TopicEventOffline indicates that a partition no longer has a leader.
+	TopicEventOffline
+	// TopicEventOnline indicates that a partition that had no leader now
+	// has one.
+	TopicEventOnline
+	// TopicEventISRShrink indicates that replicas were removed from a
+	// partition's ISR.
+	TopicEventISRShrink
+	// TopicEventISRExpand indicates that replicas were added to a
+	// partition's ISR.
+	TopicEventISRExpand
+)
+
+// String returns the event type in words, or UNKNOWN.
+func (t TopicEventType) String() string {
+	switch t {
+	case TopicEventError:
+		return "ERROR"
+	case TopicEventPartitionAdded:
+		return "PARTITION_ADDED"
+	case TopicEventTopicDeleted:
+		return "TOPIC_DELETED"
+	case TopicEventLeaderChange:
+		return "LEADER_CHANGE"
+	case TopicEventOffline:
+		return "OFFLINE"
+	case TopicEventOnline:
+		return "ONLINE"
+	case TopicEventISRShrink:
+		return "ISR_SHRINK"
+	case TopicEventISRExpand:
+		return "ISR_EXPAND"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// TopicEvent is a change to a topic or partition observed by WatchTopics.
+type TopicEvent struct {
+	Type TopicEventType // Type is the type of change.
+
+	Topic     string // Topic is the topic that changed; empty for TopicEventError.
+	Partition int32  // Partition is the partition that changed, or -1 for topic and error events.
+
+	// Prior and Current are the partition details before and after the
+	// change. Prior is the zero value for TopicEventPartitionAdded, and
+	// Current is the zero value for TopicEventTopicDeleted.
+	Prior   PartitionDetail
+	Current PartitionDetail
+
+	// Replicas are the replicas that left or joined the ISR for ISR
+	// events.
+	Replicas []int32
+
+	Err error // Err is the poll error for TopicEventError.
+}
+
+// WatchTopics polls metadata for the given topics every interval and sends
+// typed events for changes between polls: added partitions, deleted topics,
+// leader changes, partitions going offline or coming back online, and ISR
+// shrinks and expansions. If no topics are specified, all topics are watched,
+// and topics created after the watch begins are sent as added partitions.
+//
+// The first poll establishes what the cluster looks like and does not send
+// events. Events from a single poll are sent sorted by topic and partition.
+// Failed polls send a TopicEventError and the watch continues. A topic that
+// fails to load for any reason other than being unknown is compared as it was
+// last seen once it loads again, rather than reported as deleted.
+//
+// Events are sent on an unbuffered channel; polling pauses until events are
+// received. The channel is closed once the context is canceled.
+func (cl *Client) WatchTopics(ctx context.Context, interval time.Duration, topics ...string) <-chan TopicEvent {
+	ch := make(chan TopicEvent)
+	go func() {
+		defer close(ch)
+
+		var (
+			prior  TopicDetails
+			ticker = time.NewTicker(interval)
+		)
+		defer ticker.Stop()
+		for {
+			tds, err := cl.ListTopics(ctx, topics...)
+			var events []TopicEvent
+			switch {
+			case err != nil:
+				if ctx.Err() != nil {
+					return
+				}
+				events = []TopicEvent{{Type: TopicEventError, Partition: -1, Err: err}}
+			case prior == nil:
+				prior = tds
+			default:
+				events, prior = diffTopicDetails(prior, tds)
+			}
+			for _, e := range events {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// diffTopicDetails returns the events between two polls, sorted by topic and
+// partition, and the details to diff the next poll against. A topic is deleted
+// if it is missing from the current poll or is unknown; a topic that fails to
+// load with any other error keeps its prior details until it loads again.
+func diffTopicDetails(prior, current TopicDetails) ([]TopicEvent, TopicDetails) {
+	next := make(TopicDetails, len(current))
+	for t, td := range current {
+		next[t] = td
+	}
+
+	var events []TopicEvent
+	for _, t := range prior.Names() {
+		ptd := prior[t]
+		if ptd.Err != nil {
+			continue
+		}
+		td, ok := current[t]
+		switch {
+		case !ok || errors.Is(td.Err, kerr.UnknownTopicOrPartition) || errors.Is(td.Err, kerr.UnknownTopicID):
+			events = append(events, TopicEvent{Type: TopicEventTopicDeleted, Topic: t, Partition: -1})
+		case td.Err != nil:
+			next[t] = ptd
+		}
+	}
+
+	for _, t := range current.Names() {
+		td := current[t]
+		if td.Err != nil {
+			continue
+		}
+		ptd, existed := prior[t]
+		if ptd.Err != nil {
+			existed = false
+		}
+		for _, p := range td.Partitions.Sorted() {
+			pp, ok := ptd.Partitions[p.Partition]
+			if !existed || !ok {
+				events = append(events, TopicEvent{Type: TopicEventPartitionAdded, Topic: t, Partition: p.Partition, Current: p})
+				continue
+			}
+			e := TopicEvent{Topic: t, Partition: p.Partition, Prior: pp, Current: p}
+			switch {
+			case pp.Leader >= 0 && p.Leader < 0:
+				e.Type = TopicEventOffline
+				events = append(events, e)
+			case pp.Leader < 0 && p.Leader >= 0:
+				e.Type = TopicEventOnline
+				events = append(events, e)
+			case pp.Leader != p.Leader:
+				e.Type = TopicEventLeaderChange
+				events = append(events, e)
+			}
+			if left := int32sMissing(pp.ISR, p.ISR); len(left) > 0 {
+				e.Type, e.Replicas = TopicEventISRShrink, left
+				events = append(events, e)
+			}
+			if joined := int32sMissing(p.ISR, pp.ISR); len(joined) > 0 {
+				e.Type, e.Replicas = TopicEventISRExpand, joined
+				events = append(events, e)
+			}
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		l, r := &events[i], &events[j]
+		return l.Topic < r.Topic || l.Topic == r.Topic && l.Partition < r.Partition
+	})
+	return events, next
+}
+
+// int32sMissing returns the sorted numbers in l that are not in r.
+func int32sMissing(l, r []int32) []int32 {
+	var missing []int32
+	for _, li := range l {
+		var found bool
+		for _, ri := range r {
+			if li == ri {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, li)
+		}
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	return missing
+}
+
+// GroupLagRate is the lag of a group at one LagMonitor sample along with how
+// quickly the group is consuming and how quickly its partitions are being
+// produced to since the prior sample.
+type GroupLagRate struct {
+	Group string // Group is the group name.
+
+	// Lag is the total lag of the group at this sample.
+	Lag int64
+
+	// ConsumeRate is the records per second the group committed since
+	// the prior sample, and ProduceRate is the records per second the
+	// end offsets of the group's partitions advanced. Only partitions
+	// that were error free in both samples are included. Both are zero
+	// for the first sample.
+	ConsumeRate float64
+	ProduceRate float64
+
+	// ETA is how long the group will take to reach zero lag if both
+	// rates hold, zero if the group has no lag, or -1 if the group is
+	// not catching up (or if this is the first sample).
+	ETA time.Duration
+}
+
+// LagSample is a timestamped lag snapshot sent by LagMonitor.
+type LagSample struct {
+	Time    time.Time     // Time is when this sample was taken.
+	Elapsed time.Duration // Elapsed is the time since the prior successful sample, or zero for the first.
+
+	// Lags is the full lag for each group, as returned from Client.Lag.
+	Lags DescribedGroupLags
+
+	// Rates contains the lag derivatives for each group that did not
+	// have a describe or fetch error.
+	Rates map[string]GroupLagRate
+
+	// Err is the error from Client.Lag, if any. If non-nil, Lags and
+	// Rates are empty and the next sample's rates are computed against
+	// the last successful sample.
+	Err error
+}
+
+// LagMonitor samples the lag of the given groups every interval and sends
+// timestamped snapshots with per-group consume and produce rates and an
+// estimate of when each group will catch up. Rates are computed against the
+// prior successful sample; the first sample has zero rates.
+//
+// Samples are sent on an unbuffered channel; sampling pauses until samples
+// are received. The channel is closed once the context is canceled.
+func (cl *Client) LagMonitor(ctx context.Context, groups []string, interval time.Duration) <-chan LagSample {
+	ch := make(chan LagSample)
+	go func() {
+		defer close(ch)
+
+		var (
+			prior     DescribedGroupLags
+			priorTime time.Time
+			ticker    = time.NewTicker(interval)
+		)
+		defer ticker.Stop()
+		for {
+			lags, err := cl.Lag(ctx, groups...)
+			now := time.Now()
+			s := LagSample{Time: now}
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				s.Err = err
+			} else {
+				if prior != nil {
+					s.Elapsed = now.Sub(priorTime)
+				}
+				s.Lags = lags
+				s.Rates = lagRates(prior, lags, s.Elapsed)
+				prior, priorTime = lags, now
+			}
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// lagRates returns the lag derivatives for each group in current compared to
+// prior. If elapsed is not positive, the rates are zero.
+func lagRates(prior, current DescribedGroupLags, elapsed time.Duration) map[string]GroupLagRate {
+	rates := make(map[string]GroupLagRate, len(current))
+	for g, l := range current {
+		if l.Error() != nil {
+			continue
+		}
+		r := GroupLagRate{Group: g, Lag: l.Lag.Total(), ETA: -1}
+		if pl, ok := prior[g]; ok && pl.Error() == nil && elapsed > 0 {
+			var consumed, produced int64
+			for t, ps := range l.Lag {
+				for p, cur := range ps {
+					prev, ok := pl.Lag.Lookup(t, p)
+					if !ok || cur.Err != nil || prev.Err != nil {
+						continue
+					}
+					if d := cur.Commit.At - prev.Commit.At; d > 0 {
+						consumed += d
+					}
+					if d := cur.End.Offset - prev.End.Offset; d > 0 {
+						produced += d
+					}
+				}
+			}
+			secs := elapsed.Seconds()
+			r.ConsumeRate = float64(consumed) / secs
+			r.ProduceRate = float64(produced) / secs
+			if net := r.ConsumeRate - r.ProduceRate; net > 0 {
+				r.ETA = time.Duration(float64(r.Lag) / net * float64(time.Second))
+			}
+		}
+		if r.Lag == 0 {
+			r.ETA = 0
+		}
+		rates[g] = r
+	}
+	return rates
+}
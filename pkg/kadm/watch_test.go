@@ -0,0 +1,120 @@
+package kadm
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+)
+
+func TestDiffTopicDetails(t *testing.T) {
+	pd := func(topic string, p, leader int32, isr ...int32) PartitionDetail {
+		return PartitionDetail{Topic: topic, Partition: p, Leader: leader, ISR: isr}
+	}
+	td := func(topic string, ps ...PartitionDetail) TopicDetail {
+		d := TopicDetail{Topic: topic, Partitions: make(PartitionDetails)}
+		for _, p := range ps {
+			d.Partitions[p.Partition] = p
+		}
+		return d
+	}
+
+	prior := TopicDetails{
+		"a": td("a", pd("a", 0, 1, 1, 2, 3), pd("a", 1, 2, 2, 3)),
+		"b": td("b", pd("b", 0, 1, 1)),
+		"c": td("c", pd("c", 0, -1)),
+		"e": td("e", pd("e", 0, 1, 1)),
+	}
+	current := TopicDetails{
+		"a": td("a", pd("a", 0, 2, 2, 3), pd("a", 1, 2, 1, 2, 3), pd("a", 2, 3, 3)),
+		"b": {Topic: "b", Err: kerr.UnknownTopicOrPartition},
+		"c": td("c", pd("c", 0, 3, 3)),
+		"d": td("d", pd("d", 0, 1, 1)),
+		"e": {Topic: "e", Err: kerr.LeaderNotAvailable},
+	}
+
+	type event struct {
+		typ      TopicEventType
+		topic    string
+		p        int32
+		replicas []int32
+	}
+	var got []event
+	events, next := diffTopicDetails(prior, current)
+	for _, e := range events {
+		got = append(got, event{e.Type, e.Topic, e.Partition, e.Replicas})
+	}
+	exp := []event{
+		{TopicEventLeaderChange, "a", 0, nil},
+		{TopicEventISRShrink, "a", 0, []int32{1}},
+		{TopicEventISRExpand, "a", 1, []int32{1}},
+		{TopicEventPartitionAdded, "a", 2, nil},
+		{TopicEventTopicDeleted, "b", -1, nil},
+		{TopicEventOnline, "c", 0, nil},
+		{TopicEventISRExpand, "c", 0, []int32{3}},
+		{TopicEventPartitionAdded, "d", 0, nil},
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got events\n%v\nexp\n%v", got, exp)
+	}
+
+	// Topic e failing to load temporarily is not a deletion: its prior
+	// details are kept, so nothing changes once it loads again.
+	if !reflect.DeepEqual(next["e"], prior["e"]) {
+		t.Errorf("got next e %+v != prior %+v", next["e"], prior["e"])
+	}
+	current["e"] = td("e", pd("e", 0, 1, 1))
+	if events, _ := diffTopicDetails(next, current); len(events) != 0 {
+		t.Errorf("got events %v after e loaded again, exp none", events)
+	}
+}
+
+func TestLagRates(t *testing.T) {
+	lag := func(commit, end int64) GroupMemberLag {
+		return GroupMemberLag{
+			Topic:  "t",
+			Commit: Offset{Topic: "t", At: commit},
+			End:    ListedOffset{Topic: "t", Offset: end},
+			Lag:    end - commit,
+		}
+	}
+	group := func(g string, ps ...GroupMemberLag) DescribedGroupLag {
+		l := DescribedGroupLag{Group: g, Lag: GroupLag{"t": make(map[int32]GroupMemberLag)}}
+		for i, p := range ps {
+			p.Partition = int32(i)
+			l.Lag["t"][int32(i)] = p
+		}
+		return l
+	}
+
+	prior := DescribedGroupLags{
+		"catching": group("catching", lag(0, 100), lag(50, 100)),
+		"behind":   group("behind", lag(0, 100)),
+		"caughtup": group("caughtup", lag(100, 100)),
+	}
+	current := DescribedGroupLags{
+		"catching": group("catching", lag(40, 110), lag(80, 110)),
+		"behind":   group("behind", lag(10, 150)),
+		"caughtup": group("caughtup", lag(120, 120)),
+		"new":      group("new", lag(0, 10)),
+		"failed":   {Group: "failed", FetchErr: kerr.GroupAuthorizationFailed},
+	}
+
+	first := lagRates(nil, prior, 0)
+	if r := first["catching"]; r.Lag != 150 || r.ConsumeRate != 0 || r.ETA != -1 {
+		t.Errorf("first sample: got %+v", r)
+	}
+
+	rates := lagRates(prior, current, 10*time.Second)
+	exp := map[string]GroupLagRate{
+		// consumed 70, produced 20 over 10s; lag 100 at 5/s net.
+		"catching": {Group: "catching", Lag: 100, ConsumeRate: 7, ProduceRate: 2, ETA: 20 * time.Second},
+		"behind":   {Group: "behind", Lag: 140, ConsumeRate: 1, ProduceRate: 5, ETA: -1},
+		"caughtup": {Group: "caughtup", Lag: 0, ConsumeRate: 2, ProduceRate: 2, ETA: 0},
+		"new":      {Group: "new", Lag: 10, ETA: -1},
+	}
+	if !reflect.DeepEqual(rates, exp) {
+		t.Errorf("got %+v != exp %+v", rates, exp)
+	}
+}
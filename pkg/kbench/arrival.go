@@ -0,0 +1,93 @@
+package kbench
+
+import (
+	"math"
+	"math/rand/v2"
+	"time"
+)
+
+// Arrival returns the time between produced records, controlling the shape of
+// the load a benchmark generates. A nil Arrival produces as fast as possible.
+//
+// Arrivals are open loop: if producing falls behind, records are produced
+// back to back until the benchmark catches up to the schedule, which keeps
+// latency measurements honest when the cluster cannot keep up.
+type Arrival interface {
+	// Next returns the duration between the prior record and the next.
+	Next() time.Duration
+}
+
+type constantArrival time.Duration
+
+func (c constantArrival) Next() time.Duration { return time.Duration(c) }
+
+// Constant returns an Arrival that produces perSecond records every second,
+// evenly spaced.
+func Constant(perSecond float64) Arrival {
+	return constantArrival(float64(time.Second) / perSecond)
+}
+
+type poissonArrival struct {
+	mean float64
+	rng  *rand.Rand
+}
+
+func (p *poissonArrival) Next() time.Duration {
+	return time.Duration(p.rng.ExpFloat64() * p.mean)
+}
+
+// Poisson returns an Arrival that produces an average of perSecond records
+// every second, with exponentially distributed gaps between records. This
+// models many independent producers, and is bursty compared to Constant.
+func Poisson(perSecond float64) Arrival {
+	return &poissonArrival{
+		mean: float64(time.Second) / perSecond,
+		rng:  rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+}
+
+type burstArrival struct {
+	every time.Duration
+	size  int
+	n     int
+}
+
+func (b *burstArrival) Next() time.Duration {
+	if b.n++; b.n < b.size {
+		return 0
+	}
+	b.n = 0
+	return b.every
+}
+
+// Bursts returns an Arrival that produces size records back to back every
+// interval, which can be used to test how quickly a cluster absorbs spikes.
+func Bursts(size int, every time.Duration) Arrival {
+	return &burstArrival{every: every, size: max(size, 1)}
+}
+
+// ramp is used for Ramp.
+type ramp struct {
+	from, to float64
+	over     time.Duration
+	start    time.Time
+}
+
+func (r *ramp) Next() time.Duration {
+	if r.start.IsZero() {
+		r.start = time.Now()
+	}
+	frac := 1.0
+	if r.over > 0 {
+		frac = math.Min(float64(time.Since(r.start))/float64(r.over), 1)
+	}
+	rate := r.from + (r.to-r.from)*frac
+	return time.Duration(float64(time.Second) / math.Max(rate, 1e-9))
+}
+
+// Ramp returns an Arrival that linearly increases (or decreases) the rate of
+// records from `from` records per second to `to` records per second over the
+// given duration, and then stays at `to`.
+func Ramp(from, to float64, over time.Duration) Arrival {
+	return &ramp{from: from, to: to, over: over}
+}
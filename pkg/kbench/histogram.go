@@ -0,0 +1,103 @@
+package kbench
+
+import (
+	"math/bits"
+	"sync"
+	"time"
+)
+
+// histogram is a log-linear latency histogram with microsecond resolution.
+// Values below 2*subBuckets microseconds are recorded exactly; larger values
+// are recorded in buckets that are at most 1/subBuckets (~3%) wide relative to
+// their value. This is a tiny version of an HDR histogram.
+type histogram struct {
+	mu       sync.Mutex
+	counts   []int64
+	count    int64
+	sum      time.Duration
+	min, max time.Duration
+}
+
+const subBuckets = 32
+
+// bucketFor returns the bucket index for a value in microseconds.
+func bucketFor(us uint64) int {
+	if us < 2*subBuckets {
+		return int(us)
+	}
+	shift := bits.Len64(us) - 6 // us>>shift is in [32, 64)
+	return 2*subBuckets + (shift-1)*subBuckets + int(us>>shift) - subBuckets
+}
+
+// bucketUpper returns the largest value in microseconds in a bucket.
+func bucketUpper(idx int) uint64 {
+	if idx < 2*subBuckets {
+		return uint64(idx)
+	}
+	idx -= 2 * subBuckets
+	shift := idx/subBuckets + 1
+	lower := uint64(idx%subBuckets+subBuckets) << shift
+	return lower + (1 << shift) - 1
+}
+
+func (h *histogram) observe(d time.Duration) {
+	d = max(d, 0)
+	idx := bucketFor(uint64(d / time.Microsecond))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if idx >= len(h.counts) {
+		h.counts = append(h.counts, make([]int64, idx+1-len(h.counts))...)
+	}
+	h.counts[idx]++
+	if h.count == 0 || d < h.min {
+		h.min = d
+	}
+	h.max = max(h.max, d)
+	h.count++
+	h.sum += d
+}
+
+// reset returns the current histogram and clears it.
+func (h *histogram) reset() *histogram {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cp := &histogram{counts: h.counts, count: h.count, sum: h.sum, min: h.min, max: h.max}
+	h.counts, h.count, h.sum, h.min, h.max = nil, 0, 0, 0, 0
+	return cp
+}
+
+// summary returns the latency summary of the histogram.
+func (h *histogram) summary() Latency {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	l := Latency{
+		Count: h.count,
+		Min:   h.min,
+		Max:   h.max,
+	}
+	if h.count == 0 {
+		return l
+	}
+	l.Mean = h.sum / time.Duration(h.count)
+	l.P50 = h.percentile(50)
+	l.P90 = h.percentile(90)
+	l.P95 = h.percentile(95)
+	l.P99 = h.percentile(99)
+	l.P999 = h.percentile(99.9)
+	return l
+}
+
+// percentile returns the upper bound of the bucket containing the given
+// percentile, capped to the max observed value. The mutex must be held.
+func (h *histogram) percentile(p float64) time.Duration {
+	want := int64(float64(h.count)*p/100 + 0.5)
+	want = min(max(want, 1), h.count)
+	var seen int64
+	for idx, n := range h.counts {
+		if seen += n; seen >= want {
+			return min(time.Duration(bucketUpper(idx))*time.Microsecond, h.max)
+		}
+	}
+	return h.max
+}
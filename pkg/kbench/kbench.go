@@ -0,0 +1,434 @@
+// Package kbench generates produce and consume load against a Kafka cluster
+// using a kgo.Client, reporting throughput and latency percentiles measured
+// through the client's own hooks. This is a franz-go native equivalent of
+// Kafka's kafka-producer-perf-test and kafka-consumer-perf-test.
+//
+// A producer benchmark can be run like so:
+//
+//	res, err := kbench.Produce(ctx, kbench.ProduceConfig{
+//	        Topic:          "bench",
+//	        Records:        1_000_000,
+//	        ValueBytes:     100,
+//	        KeyCardinality: 1000,
+//	        Arrival:        kbench.Poisson(50_000),
+//	}, kgo.SeedBrokers("localhost:9092"))
+//	fmt.Println(res)
+//
+// Producer latency is measured from when a record is created to when its
+// produce request is acknowledged, and thus includes time spent buffered in
+// the client. Consumer latency is the end to end latency from when a record
+// was produced to when it is polled, and is only measured for records
+// produced with EndToEndTimestamps.
+package kbench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Latency is a summary of latencies measured in a benchmark. Percentiles are
+// accurate to within ~3%.
+type Latency struct {
+	Count int64         // Count is the number of latencies measured.
+	Min   time.Duration // Min is the smallest latency measured.
+	Max   time.Duration // Max is the largest latency measured.
+	Mean  time.Duration // Mean is the mean latency.
+	P50   time.Duration // P50 is the median latency.
+	P90   time.Duration // P90 is the 90th percentile latency.
+	P95   time.Duration // P95 is the 95th percentile latency.
+	P99   time.Duration // P99 is the 99th percentile latency.
+	P999  time.Duration // P999 is the 99.9th percentile latency.
+}
+
+// Result is the result of a benchmark, or of an interval of a benchmark.
+type Result struct {
+	// Elapsed is how long the benchmark or interval ran for.
+	Elapsed time.Duration
+
+	// Records is the number of records successfully produced or consumed.
+	Records int64
+
+	// Bytes is the number of key and value bytes successfully produced or
+	// consumed.
+	Bytes int64
+
+	// Errors is the number of records that failed to produce, or the
+	// number of fetch errors encountered while consuming.
+	Errors int64
+
+	// FirstErr is the first error encountered, if any.
+	FirstErr error
+
+	// Latency summarizes the latencies measured.
+	Latency Latency
+}
+
+// RecordsPerSec returns the records per second of the result.
+func (r Result) RecordsPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Records) / r.Elapsed.Seconds()
+}
+
+// MiBPerSec returns the MiB per second of the result.
+func (r Result) MiBPerSec() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Bytes) / (1 << 20) / r.Elapsed.Seconds()
+}
+
+// String returns a one line summary of the result in the style of Kafka's
+// perf test tools.
+func (r Result) String() string {
+	l := r.Latency
+	return fmt.Sprintf("%d records (%.1f records/s, %.2f MiB/s), %d errors; latency avg %v, max %v, p50 %v, p95 %v, p99 %v, p99.9 %v",
+		r.Records, r.RecordsPerSec(), r.MiBPerSec(), r.Errors,
+		l.Mean, l.Max, l.P50, l.P95, l.P99, l.P999,
+	)
+}
+
+// ProduceConfig configures a producer benchmark.
+type ProduceConfig struct {
+	// Topic is the topic to produce to. This is required.
+	Topic string
+
+	// Records is the number of records to produce. If zero, records are
+	// produced until Duration elapses or the context is canceled.
+	Records int64
+
+	// Duration is how long to produce for. If zero, records are produced
+	// until Records have been produced or the context is canceled.
+	Duration time.Duration
+
+	// ValueBytes is the size of record values. If ValueBytesMax is larger,
+	// value sizes are uniformly distributed between ValueBytes and
+	// ValueBytesMax, inclusive.
+	ValueBytes    int
+	ValueBytesMax int
+
+	// KeyCardinality is the number of distinct keys to produce with, with
+	// keys chosen uniformly at random. If zero, records have no key and are
+	// partitioned by the client's partitioner.
+	KeyCardinality int
+
+	// Compression, if non-empty, sets the codecs to compress with in order
+	// of preference; see kgo.ProducerBatchCompression.
+	Compression []kgo.CompressionCodec
+
+	// Arrival controls when records are produced. If nil, records are
+	// produced as fast as possible.
+	Arrival Arrival
+
+	// EndToEndTimestamps adds end to end latency headers to every record,
+	// allowing a Consume benchmark to measure produce to consume latency.
+	EndToEndTimestamps bool
+
+	// Interval, if non-zero, is how often to call OnInterval with the
+	// result for the prior interval.
+	Interval   time.Duration
+	OnInterval func(Result)
+}
+
+// ConsumeConfig configures a consumer benchmark.
+type ConsumeConfig struct {
+	// Topics are the topics to consume. This is required.
+	Topics []string
+
+	// Group, if non-empty, is the group to consume in. Otherwise, all
+	// partitions of the topics are consumed directly.
+	Group string
+
+	// Records is the number of records to consume. If zero, records are
+	// consumed until Duration elapses or the context is canceled.
+	Records int64
+
+	// Duration is how long to consume for. If zero, records are consumed
+	// until Records have been consumed or the context is canceled.
+	Duration time.Duration
+
+	// Interval, if non-zero, is how often to call OnInterval with the
+	// result for the prior interval.
+	Interval   time.Duration
+	OnInterval func(Result)
+}
+
+// meter measures a benchmark through client hooks.
+type meter struct {
+	records atomic.Int64
+	bytes   atomic.Int64
+	errors  atomic.Int64
+
+	errMu    sync.Mutex
+	firstErr error
+
+	total    histogram
+	interval histogram
+
+	// Interval counts are tracked by snapshotting the totals.
+	intervalMu sync.Mutex
+	lastAt     time.Time
+	lastRecs   int64
+	lastBytes  int64
+	lastErrs   int64
+
+	intervalStop chan struct{}
+	intervalDone chan struct{}
+}
+
+var (
+	_ kgo.HookProduceRecordUnbuffered = new(meter)
+	_ kgo.HookFetchRecordUnbuffered   = new(meter)
+	_ kgo.HookFetchRecordEndToEnd     = new(meter)
+)
+
+func (m *meter) observe(latency time.Duration) {
+	m.total.observe(latency)
+	m.interval.observe(latency)
+}
+
+func (m *meter) fail(err error) {
+	m.errors.Add(1)
+	m.errMu.Lock()
+	if m.firstErr == nil {
+		m.firstErr = err
+	}
+	m.errMu.Unlock()
+}
+
+// OnProduceRecordUnbuffered implements kgo.HookProduceRecordUnbuffered.
+func (m *meter) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.records.Add(1)
+	m.bytes.Add(int64(len(r.Key) + len(r.Value)))
+	m.observe(time.Since(r.Timestamp))
+}
+
+// OnFetchRecordUnbuffered implements kgo.HookFetchRecordUnbuffered.
+func (m *meter) OnFetchRecordUnbuffered(r *kgo.Record, polled bool) {
+	if !polled {
+		return
+	}
+	m.records.Add(1)
+	m.bytes.Add(int64(len(r.Key) + len(r.Value)))
+}
+
+// OnFetchRecordEndToEnd implements kgo.HookFetchRecordEndToEnd.
+func (m *meter) OnFetchRecordEndToEnd(_ *kgo.Record, latency time.Duration) {
+	m.observe(latency)
+}
+
+// start begins calling fn every interval with the interval's result.
+func (m *meter) start(interval time.Duration, fn func(Result)) {
+	m.lastAt = time.Now()
+	if interval <= 0 || fn == nil {
+		return
+	}
+	m.intervalStop, m.intervalDone = make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(m.intervalDone)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-m.intervalStop:
+				return
+			case <-t.C:
+				fn(m.intervalResult())
+			}
+		}
+	}()
+}
+
+func (m *meter) stop() {
+	if m.intervalStop != nil {
+		close(m.intervalStop)
+		<-m.intervalDone
+	}
+}
+
+func (m *meter) intervalResult() Result {
+	m.intervalMu.Lock()
+	defer m.intervalMu.Unlock()
+	now := time.Now()
+	recs, bytes, errs := m.records.Load(), m.bytes.Load(), m.errors.Load()
+	r := Result{
+		Elapsed: now.Sub(m.lastAt),
+		Records: recs - m.lastRecs,
+		Bytes:   bytes - m.lastBytes,
+		Errors:  errs - m.lastErrs,
+		Latency: m.interval.reset().summary(),
+	}
+	m.lastAt, m.lastRecs, m.lastBytes, m.lastErrs = now, recs, bytes, errs
+	return r
+}
+
+func (m *meter) result(elapsed time.Duration) Result {
+	m.errMu.Lock()
+	defer m.errMu.Unlock()
+	return Result{
+		Elapsed:  elapsed,
+		Records:  m.records.Load(),
+		Bytes:    m.bytes.Load(),
+		Errors:   m.errors.Load(),
+		FirstErr: m.firstErr,
+		Latency:  m.total.summary(),
+	}
+}
+
+// benchCtx returns a context that is canceled after d, if d is non-zero.
+func benchCtx(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	if d > 0 {
+		return context.WithTimeout(ctx, d)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Produce runs a producer benchmark with a new client created from opts and
+// returns the result once all records are produced, Duration elapses, or the
+// context is canceled. The client is closed before returning.
+//
+// Records that were produced before the benchmark stopped are flushed
+// before returning and are included in the result, unless the context is
+// canceled. The returned error is only non-nil if the client cannot be
+// created or flushing fails; produce errors are counted in the result.
+func Produce(ctx context.Context, cfg ProduceConfig, opts ...kgo.Opt) (Result, error) {
+	if cfg.Topic == "" {
+		return Result{}, errors.New("kbench: missing topic to produce to")
+	}
+	if cfg.ValueBytes < 0 || cfg.KeyCardinality < 0 {
+		return Result{}, errors.New("kbench: value bytes and key cardinality cannot be negative")
+	}
+
+	m := new(meter)
+	opts = append(opts, kgo.WithHooks(m), kgo.DefaultProduceTopic(cfg.Topic))
+	if len(cfg.Compression) > 0 {
+		opts = append(opts, kgo.ProducerBatchCompression(cfg.Compression...))
+	}
+	if cfg.EndToEndTimestamps {
+		opts = append(opts, kgo.RecordEndToEndTimestamps())
+	}
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer cl.Close()
+
+	// All records share one value buffer, sliced to size; the client
+	// does not modify record values.
+	maxValue := max(cfg.ValueBytes, cfg.ValueBytesMax)
+	value := make([]byte, maxValue)
+	for i := range value {
+		value[i] = byte('a' + i%26)
+	}
+	keys := make([][]byte, cfg.KeyCardinality)
+	for i := range keys {
+		keys[i] = strconv.AppendInt([]byte("key-"), int64(i), 10)
+	}
+
+	runCtx, cancel := benchCtx(ctx, cfg.Duration)
+	defer cancel()
+
+	start := time.Now()
+	m.start(cfg.Interval, cfg.OnInterval)
+	next := start
+	for n := int64(0); cfg.Records == 0 || n < cfg.Records; n++ {
+		if cfg.Arrival != nil {
+			next = next.Add(cfg.Arrival.Next())
+			if wait := time.Until(next); wait > 0 {
+				timer := time.NewTimer(wait)
+				select {
+				case <-runCtx.Done():
+				case <-timer.C:
+				}
+				timer.Stop()
+			}
+		}
+		if runCtx.Err() != nil {
+			break
+		}
+
+		size := cfg.ValueBytes
+		if cfg.ValueBytesMax > cfg.ValueBytes {
+			size += rand.IntN(cfg.ValueBytesMax - cfg.ValueBytes + 1)
+		}
+		r := &kgo.Record{
+			Value:     value[:size],
+			Timestamp: time.Now(),
+		}
+		if len(keys) > 0 {
+			r.Key = keys[rand.IntN(len(keys))]
+		}
+		// Records use the original context so that the Duration
+		// ending does not fail buffered records.
+		cl.Produce(ctx, r, nil)
+	}
+
+	// If the original context is canceled, we stop without waiting for
+	// buffered records.
+	err = cl.Flush(ctx)
+	if ctx.Err() != nil {
+		err = nil
+	}
+	elapsed := time.Since(start)
+	m.stop()
+	return m.result(elapsed), err
+}
+
+// Consume runs a consumer benchmark with a new client created from opts,
+// consuming from the start of the topics, and returns the result once Records
+// are consumed, Duration elapses, or the context is canceled. The client is
+// closed before returning.
+func Consume(ctx context.Context, cfg ConsumeConfig, opts ...kgo.Opt) (Result, error) {
+	if len(cfg.Topics) == 0 {
+		return Result{}, errors.New("kbench: missing topics to consume")
+	}
+
+	m := new(meter)
+	opts = append(opts,
+		kgo.WithHooks(m),
+		kgo.ConsumeTopics(cfg.Topics...),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.MeasureEndToEndLatency(),
+	)
+	if cfg.Group != "" {
+		opts = append(opts, kgo.ConsumerGroup(cfg.Group))
+	}
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return Result{}, err
+	}
+	defer cl.Close()
+
+	runCtx, cancel := benchCtx(ctx, cfg.Duration)
+	defer cancel()
+
+	start := time.Now()
+	m.start(cfg.Interval, cfg.OnInterval)
+	for cfg.Records == 0 || m.records.Load() < cfg.Records {
+		fs := cl.PollFetches(runCtx)
+		if runCtx.Err() != nil {
+			break
+		}
+		fs.EachError(func(_ string, _ int32, err error) {
+			if !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded) {
+				m.fail(err)
+			}
+		})
+	}
+	elapsed := time.Since(start)
+	m.stop()
+	return m.result(elapsed), nil
+}
@@ -0,0 +1,90 @@
+package kbench
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHistogramBuckets(t *testing.T) {
+	t.Parallel()
+
+	prior := -1
+	for us := range uint64(1 << 20) {
+		idx := bucketFor(us)
+		if idx != prior && idx != prior+1 {
+			t.Fatalf("value %d: bucket %d skipped from prior bucket %d", us, idx, prior)
+		}
+		if upper := bucketUpper(idx); us > upper {
+			t.Fatalf("value %d: bucket %d upper bound %d is too small", us, idx, upper)
+		}
+		if idx > 0 {
+			if below := bucketUpper(idx - 1); us <= below {
+				t.Fatalf("value %d: bucket %d: prior bucket upper bound %d is too large", us, idx, below)
+			}
+		}
+		prior = idx
+	}
+}
+
+func TestHistogramSummary(t *testing.T) {
+	t.Parallel()
+
+	var h histogram
+	for i := 1; i <= 1000; i++ {
+		h.observe(time.Duration(i) * time.Millisecond)
+	}
+	l := h.summary()
+	if l.Count != 1000 || l.Min != time.Millisecond || l.Max != time.Second {
+		t.Errorf("got count %d min %v max %v", l.Count, l.Min, l.Max)
+	}
+	if exp := 500500 * time.Microsecond; l.Mean != exp {
+		t.Errorf("got mean %v != exp %v", l.Mean, exp)
+	}
+	for _, test := range []struct {
+		got, exp time.Duration
+	}{
+		{l.P50, 500 * time.Millisecond},
+		{l.P90, 900 * time.Millisecond},
+		{l.P99, 990 * time.Millisecond},
+		{l.P999, 999 * time.Millisecond},
+	} {
+		// Percentiles are the upper bound of their bucket.
+		if test.got < test.exp || float64(test.got) > float64(test.exp)*1.04 {
+			t.Errorf("got percentile %v, expected within 4%% above %v", test.got, test.exp)
+		}
+	}
+
+	if h.reset().summary().Count != 1000 || h.summary().Count != 0 {
+		t.Error("reset did not return and clear the histogram")
+	}
+}
+
+func TestArrivals(t *testing.T) {
+	t.Parallel()
+
+	if got := Constant(100).Next(); got != 10*time.Millisecond {
+		t.Errorf("constant: got %v != exp 10ms", got)
+	}
+
+	p := Poisson(1000)
+	var sum time.Duration
+	const n = 100000
+	for range n {
+		sum += p.Next()
+	}
+	if mean := sum / n; mean < 900*time.Microsecond || mean > 1100*time.Microsecond {
+		t.Errorf("poisson: got mean %v, expected about 1ms", mean)
+	}
+
+	b := Bursts(3, time.Second)
+	for i, exp := range []time.Duration{0, 0, time.Second, 0, 0, time.Second} {
+		if got := b.Next(); got != exp {
+			t.Errorf("bursts: %d: got %v != exp %v", i, got, exp)
+		}
+	}
+
+	r := Ramp(10, 1000, 0)
+	if got := r.Next(); got != time.Millisecond {
+		t.Errorf("ramp: got %v != exp 1ms at the end of the ramp", got)
+	}
+}
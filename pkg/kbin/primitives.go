@@ -4,6 +4,7 @@ package kbin
 import (
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"math"
 	"math/bits"
 	"reflect"
@@ -491,6 +492,40 @@ func AppendCompactNullableArrayLen(dst []byte, l int, isNil bool) []byte {
 	return AppendUvarint(dst, 1+uint32(l))
 }
 
+// Limits bounds what a Reader will decode, allowing untrusted input to be
+// decoded without a malicious length prefix causing large allocations. A zero
+// value for any field means no limit.
+//
+// Lengths are always bounded by the remaining input; limits bound lengths
+// further. Array lengths in particular are worth limiting: each encoded array
+// element may be as small as one byte, but may decode into a much larger Go
+// type.
+type Limits struct {
+	// MaxStringLen is the maximum length of any decoded string.
+	MaxStringLen int
+
+	// MaxBytesLen is the maximum length of any decoded byte array.
+	MaxBytesLen int
+
+	// MaxArrayLen is the maximum length of any decoded array.
+	MaxArrayLen int
+
+	// MaxNesting is the maximum depth of readers created with Nested.
+	MaxNesting int
+}
+
+// LimitError is returned from Reader.Complete if decoding stopped because a
+// length exceeded one of the reader's limits.
+type LimitError struct {
+	Limit string // Limit is the limit that was exceeded: "string length", "bytes length", "array length", or "nesting".
+	Max   int    // Max is the configured limit.
+	Got   int    // Got is the length or depth that exceeded the limit.
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("decode %s %d exceeds limit %d", e.Limit, e.Got, e.Max)
+}
+
 // Reader is used to decode Kafka messages.
 //
 // For all functions on Reader, if the reader has been invalidated, functions
@@ -499,6 +534,75 @@ func AppendCompactNullableArrayLen(dst []byte, l int, isNil bool) []byte {
 type Reader struct {
 	Src []byte
 	bad bool
+
+	// Limits, if non-nil, bounds lengths decoded by the reader. Limits are
+	// inherited by readers created with Nested.
+	Limits *Limits
+
+	depth int
+	err   *LimitError
+}
+
+// Nested returns a new reader for src, which should be a span of this reader,
+// inheriting this reader's limits. This is meant for decoding size prefixed
+// sections, such as tagged fields. If the new reader would exceed the
+// MaxNesting limit, the new reader is invalid.
+func (b *Reader) Nested(src []byte) Reader {
+	n := Reader{Src: src, Limits: b.Limits, depth: b.depth + 1}
+	if b.Limits != nil && b.Limits.MaxNesting > 0 && n.depth > b.Limits.MaxNesting {
+		n.exceed("nesting", b.Limits.MaxNesting, n.depth)
+	}
+	return n
+}
+
+// exceed invalidates the reader with a limit error.
+func (b *Reader) exceed(limit string, maxv, got int) {
+	b.bad = true
+	b.Src = nil
+	if b.err == nil {
+		b.err = &LimitError{limit, maxv, got}
+	}
+}
+
+// checkLen returns whether l is within the given limit, invalidating the
+// reader if not.
+func (b *Reader) checkLen(limit string, maxv, l int) bool {
+	if maxv > 0 && l > maxv {
+		b.exceed(limit, maxv, l)
+		return false
+	}
+	return true
+}
+
+// strSpan returns l bytes for a string, obeying MaxStringLen.
+func (b *Reader) strSpan(l int) []byte {
+	if b.Limits != nil && !b.checkLen("string length", b.Limits.MaxStringLen, l) {
+		return nil
+	}
+	return b.Span(l)
+}
+
+// bytesSpan returns l bytes for a byte array, obeying MaxBytesLen.
+func (b *Reader) bytesSpan(l int) []byte {
+	if b.Limits != nil && !b.checkLen("bytes length", b.Limits.MaxBytesLen, l) {
+		return nil
+	}
+	return b.Span(l)
+}
+
+// arrayLen validates an array length, obeying MaxArrayLen.
+func (b *Reader) arrayLen(r int32) int32 {
+	// The min size of a Kafka type is a byte, so if we do not have
+	// at least the array length of bytes left, it is bad.
+	if len(b.Src) < int(r) {
+		b.bad = true
+		b.Src = nil
+		return 0
+	}
+	if b.Limits != nil && !b.checkLen("array length", b.Limits.MaxArrayLen, int(r)) {
+		return 0
+	}
+	return r
 }
 
 // Bool returns a bool from the reader.
@@ -654,13 +758,13 @@ func (b *Reader) Span(l int) []byte {
 // reference to the original slice.
 func (b *Reader) UnsafeString() string {
 	l := b.Int16()
-	return UnsafeString(b.Span(int(l)))
+	return UnsafeString(b.strSpan(int(l)))
 }
 
 // String returns a Kafka string from the reader.
 func (b *Reader) String() string {
 	l := b.Int16()
-	return string(b.Span(int(l)))
+	return string(b.strSpan(int(l)))
 }
 
 // UnsafeCompactString returns a Kafka compact string from the reader without
@@ -668,13 +772,13 @@ func (b *Reader) String() string {
 // string holds a reference to the original slice.
 func (b *Reader) UnsafeCompactString() string {
 	l := int(b.Uvarint()) - 1
-	return UnsafeString(b.Span(l))
+	return UnsafeString(b.strSpan(l))
 }
 
 // CompactString returns a Kafka compact string from the reader.
 func (b *Reader) CompactString() string {
 	l := int(b.Uvarint()) - 1
-	return string(b.Span(l))
+	return string(b.strSpan(l))
 }
 
 // UnsafeNullableString returns a Kafka nullable string from the reader without
@@ -685,7 +789,7 @@ func (b *Reader) UnsafeNullableString() *string {
 	if l < 0 {
 		return nil
 	}
-	s := UnsafeString(b.Span(int(l)))
+	s := UnsafeString(b.strSpan(int(l)))
 	return &s
 }
 
@@ -695,7 +799,7 @@ func (b *Reader) NullableString() *string {
 	if l < 0 {
 		return nil
 	}
-	s := string(b.Span(int(l)))
+	s := string(b.strSpan(int(l)))
 	return &s
 }
 
@@ -707,7 +811,7 @@ func (b *Reader) UnsafeCompactNullableString() *string {
 	if l < 0 {
 		return nil
 	}
-	s := UnsafeString(b.Span(l))
+	s := UnsafeString(b.strSpan(l))
 	return &s
 }
 
@@ -718,7 +822,7 @@ func (b *Reader) CompactNullableString() *string {
 	if l < 0 {
 		return nil
 	}
-	s := string(b.Span(l))
+	s := string(b.strSpan(l))
 	return &s
 }
 
@@ -736,7 +840,7 @@ func (b *Reader) Bytes() []byte {
 	if l == -1 {
 		return []byte{}
 	}
-	return b.Span(int(l))
+	return b.bytesSpan(int(l))
 }
 
 // CompactBytes returns a Kafka compact byte array from the reader.
@@ -747,7 +851,7 @@ func (b *Reader) CompactBytes() []byte {
 	if l == -1 { // same as above: -1 should not be allowed here
 		return []byte{}
 	}
-	return b.Span(l)
+	return b.bytesSpan(l)
 }
 
 // NullableBytes returns a Kafka nullable byte array from the reader, returning
@@ -757,7 +861,7 @@ func (b *Reader) NullableBytes() []byte {
 	if l < 0 {
 		return nil
 	}
-	r := b.Span(int(l))
+	r := b.bytesSpan(int(l))
 	return r
 }
 
@@ -768,47 +872,23 @@ func (b *Reader) CompactNullableBytes() []byte {
 	if l < 0 {
 		return nil
 	}
-	r := b.Span(l)
+	r := b.bytesSpan(l)
 	return r
 }
 
 // ArrayLen returns a Kafka array length from the reader.
 func (b *Reader) ArrayLen() int32 {
-	r := b.Int32()
-	// The min size of a Kafka type is a byte, so if we do not have
-	// at least the array length of bytes left, it is bad.
-	if len(b.Src) < int(r) {
-		b.bad = true
-		b.Src = nil
-		return 0
-	}
-	return r
+	return b.arrayLen(b.Int32())
 }
 
 // VarintArrayLen returns a Kafka array length from the reader.
 func (b *Reader) VarintArrayLen() int32 {
-	r := b.Varint()
-	// The min size of a Kafka type is a byte, so if we do not have
-	// at least the array length of bytes left, it is bad.
-	if len(b.Src) < int(r) {
-		b.bad = true
-		b.Src = nil
-		return 0
-	}
-	return r
+	return b.arrayLen(b.Varint())
 }
 
 // CompactArrayLen returns a Kafka compact array length from the reader.
 func (b *Reader) CompactArrayLen() int32 {
-	r := int32(b.Uvarint()) - 1
-	// The min size of a Kafka type is a byte, so if we do not have
-	// at least the array length of bytes left, it is bad.
-	if len(b.Src) < int(r) {
-		b.bad = true
-		b.Src = nil
-		return 0
-	}
-	return r
+	return b.arrayLen(int32(b.Uvarint()) - 1)
 }
 
 // VarintBytes returns a Kafka encoded varint array from the reader, returning
@@ -818,7 +898,7 @@ func (b *Reader) VarintBytes() []byte {
 	if l < 0 {
 		return nil
 	}
-	return b.Span(int(l))
+	return b.bytesSpan(int(l))
 }
 
 // UnsafeVarintString returns a Kafka encoded varint string from the reader
@@ -833,8 +913,12 @@ func (b *Reader) VarintString() string {
 	return string(b.VarintBytes())
 }
 
-// Complete returns ErrNotEnoughData if the source ran out while decoding.
+// Complete returns ErrNotEnoughData if the source ran out while decoding, or
+// a *LimitError if decoding exceeded one of the reader's limits.
 func (b *Reader) Complete() error {
+	if b.err != nil {
+		return b.err
+	}
 	if b.bad {
 		return ErrNotEnoughData
 	}
@@ -3,6 +3,7 @@ package kbin
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"testing"
 	"testing/quick"
@@ -89,6 +90,90 @@ func TestVarlong(t *testing.T) {
 	}
 }
 
+func TestReaderLimits(t *testing.T) {
+	limits := &Limits{
+		MaxStringLen: 3,
+		MaxBytesLen:  4,
+		MaxArrayLen:  2,
+		MaxNesting:   1,
+	}
+	for _, test := range []struct {
+		name  string
+		src   []byte
+		read  func(*Reader)
+		limit string
+	}{
+		{"string ok", AppendString(nil, "foo"), func(b *Reader) { _ = b.String() }, ""},
+		{"string", AppendString(nil, "fooo"), func(b *Reader) { _ = b.String() }, "string length"},
+		{"compact string", AppendCompactString(nil, "fooo"), func(b *Reader) { b.UnsafeCompactString() }, "string length"},
+		{"nullable string", AppendNullableString(nil, nil), func(b *Reader) { b.NullableString() }, ""},
+		{"bytes", AppendBytes(nil, []byte("fooba")), func(b *Reader) { b.Bytes() }, "bytes length"},
+		{"varint bytes", AppendVarintBytes(nil, []byte("fooba")), func(b *Reader) { b.VarintBytes() }, "bytes length"},
+		{"array ok", append(AppendArrayLen(nil, 2), 0, 0), func(b *Reader) { b.ArrayLen(); b.Span(2) }, ""},
+		{"array", append(AppendArrayLen(nil, 3), 0, 0, 0), func(b *Reader) { b.ArrayLen() }, "array length"},
+		{"compact array", append(AppendCompactArrayLen(nil, 3), 0, 0, 0), func(b *Reader) { b.CompactArrayLen() }, "array length"},
+		{"nesting ok", []byte{1, 0}, func(b *Reader) {
+			n := b.Nested(b.Span(int(b.Uvarint())))
+			n.Int8()
+			if err := n.Complete(); err != nil {
+				t.Errorf("nesting ok: unexpected nested err %v", err)
+			}
+		}, ""},
+		{"nesting", []byte{1, 0}, func(b *Reader) {
+			n := b.Nested(b.Src)
+			nn := n.Nested(n.Src)
+			nn.Uvarint()
+			*b = nn
+		}, "nesting"},
+	} {
+		b := Reader{Src: test.src, Limits: limits}
+		test.read(&b)
+		err := b.Complete()
+		var le *LimitError
+		switch {
+		case test.limit == "" && err != nil:
+			t.Errorf("%s: unexpected err %v", test.name, err)
+		case test.limit != "" && !errors.As(err, &le):
+			t.Errorf("%s: got err %v, expected *LimitError", test.name, err)
+		case test.limit != "" && le.Limit != test.limit:
+			t.Errorf("%s: got limit %q != exp %q", test.name, le.Limit, test.limit)
+		}
+
+		// Without limits, everything reads fine.
+		b = Reader{Src: test.src}
+		test.read(&b)
+		if err := b.Complete(); err != nil {
+			t.Errorf("%s: unexpected err without limits: %v", test.name, err)
+		}
+	}
+}
+
+func FuzzReaderLimits(f *testing.F) {
+	f.Add(AppendString(nil, "foo"))
+	f.Add(AppendCompactArrayLen(nil, 100))
+	f.Add([]byte{0x7f, 0xff, 0xff, 0xff})
+	f.Fuzz(func(t *testing.T, src []byte) {
+		limits := &Limits{MaxStringLen: 8, MaxBytesLen: 8, MaxArrayLen: 8, MaxNesting: 2}
+		b := Reader{Src: src, Limits: limits}
+		for b.Ok() && len(b.Src) > 0 {
+			if n := b.ArrayLen(); n > 8 {
+				t.Fatalf("array length %d exceeds limit", n)
+			}
+			if s := b.CompactString(); len(s) > 8 {
+				t.Fatalf("string length %d exceeds limit", len(s))
+			}
+			if bs := b.NullableBytes(); len(bs) > 8 {
+				t.Fatalf("bytes length %d exceeds limit", len(bs))
+			}
+			b = b.Nested(b.Src)
+		}
+		var le *LimitError
+		if err := b.Complete(); err != nil && err != ErrNotEnoughData && !errors.As(err, &le) {
+			t.Fatalf("unexpected error %v", err)
+		}
+	})
+}
+
 func BenchmarkUvarint(b *testing.B) {
 	for _, u := range []uint32{
 		0,         // len 1
@@ -0,0 +1,33 @@
+// Package kcoord decodes records from Kafka's internal coordinator topics.
+//
+// Group and transaction coordinators persist their state to compacted
+// internal topics. Consuming these topics directly is the cheapest way to
+// audit commits, track lag for every group at once, or watch transactions
+// without polling the admin APIs. This package turns the raw keys and values
+// of those records into typed structs: DecodeOffsets for __consumer_offsets
+// and DecodeTxn for __transaction_state.
+//
+//	cl, err := kgo.NewClient(
+//	        kgo.SeedBrokers("localhost:9092"),
+//	        kgo.ConsumeTopics(kcoord.OffsetsTopic),
+//	)
+//	// ...
+//	fetches := cl.PollFetches(ctx)
+//	fetches.EachRecord(func(r *kgo.Record) {
+//	        rec, err := kcoord.DecodeOffsetsRecord(r)
+//	        if err != nil {
+//	                return
+//	        }
+//	        switch rec.Type {
+//	        case kcoord.OffsetsRecordOffsetCommit:
+//	                // rec.OffsetCommit
+//	        case kcoord.OffsetsRecordGroupMetadata:
+//	                // rec.GroupMetadata
+//	        }
+//	})
+//
+// Every key and value schema version Kafka has written is supported. Key types
+// this package does not understand, such as the records of KIP-848 consumer
+// groups, are returned with an unknown type rather than an error so that
+// consumers can skip them.
+package kcoord
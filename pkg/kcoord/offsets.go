@@ -0,0 +1,226 @@
+package kcoord
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// OffsetsTopic is the internal topic that group coordinators write offset
+// commits and group metadata to.
+const OffsetsTopic = "__consumer_offsets"
+
+var errShortKey = errors.New("key is too short to contain a version")
+
+// OffsetsRecordType is the type of a record in the __consumer_offsets topic,
+// determined by the version that prefixes the record key.
+type OffsetsRecordType int8
+
+const (
+	// OffsetsRecordUnknown is a record with a key version this package
+	// does not decode.
+	OffsetsRecordUnknown OffsetsRecordType = iota
+	// OffsetsRecordOffsetCommit is an offset commit, key versions 0 and 1.
+	OffsetsRecordOffsetCommit
+	// OffsetsRecordGroupMetadata is group metadata, key version 2.
+	OffsetsRecordGroupMetadata
+)
+
+// String returns the record type in words, or UNKNOWN.
+func (t OffsetsRecordType) String() string {
+	switch t {
+	case OffsetsRecordOffsetCommit:
+		return "OFFSET_COMMIT"
+	case OffsetsRecordGroupMetadata:
+		return "GROUP_METADATA"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// OffsetCommit is a committed offset for a group's topic partition.
+type OffsetCommit struct {
+	Group     string // Group is the group that committed.
+	Topic     string // Topic is the topic committed to.
+	Partition int32  // Partition is the partition committed to.
+
+	Offset          int64    // Offset is the committed offset.
+	LeaderEpoch     int32    // LeaderEpoch is the leader epoch of the commit, or -1 if the value version predates leader epochs.
+	Metadata        string   // Metadata is the metadata included in the commit.
+	CommitTimestamp int64    // CommitTimestamp is the millisecond timestamp of when the commit occurred.
+	ExpireTimestamp int64    // ExpireTimestamp is when the commit expires, or -1 if the value version does not include it (only version 1 does).
+	TopicID         [16]byte // TopicID is the ID of the committed topic, if the broker included it.
+}
+
+// GroupMetadataMember is a member of a group in GroupMetadata.
+type GroupMetadataMember struct {
+	MemberID               string  // MemberID is the member ID the coordinator assigned.
+	InstanceID             *string // InstanceID is the member's instance ID, if it is a static member.
+	ClientID               string  // ClientID is the member's client ID.
+	ClientHost             string  // ClientHost is the host the member connected from.
+	RebalanceTimeoutMillis int32   // RebalanceTimeoutMillis is the member's rebalance timeout, or -1 if the value version predates it.
+	SessionTimeoutMillis   int32   // SessionTimeoutMillis is the member's session timeout.
+
+	// Subscription and Assignment are the raw protocol metadata and
+	// assignment of this member.
+	Subscription []byte
+	Assignment   []byte
+
+	// ConsumerSubscription and ConsumerAssignment are Subscription and
+	// Assignment decoded, if the group's protocol type is "consumer" and
+	// the bytes could be decoded. A member that has not yet received an
+	// assignment has a nil ConsumerAssignment.
+	ConsumerSubscription *kmsg.ConsumerMemberMetadata
+	ConsumerAssignment   *kmsg.ConsumerMemberAssignment
+}
+
+// GroupMetadata is the state of a classic group at the end of a rebalance.
+type GroupMetadata struct {
+	Group                 string                // Group is the group name.
+	ProtocolType          string                // ProtocolType is the group's protocol type, e.g. "consumer" or "connect".
+	Generation            int32                 // Generation is the group's generation.
+	Protocol              string                // Protocol is the agreed upon protocol, e.g. "cooperative-sticky", or empty if the group is empty.
+	Leader                string                // Leader is the member ID of the group leader, or empty if the group is empty.
+	CurrentStateTimestamp int64                 // CurrentStateTimestamp is the millisecond timestamp this state was reached, or -1 if the value version predates it.
+	Members               []GroupMetadataMember // Members are the members of the group.
+}
+
+// OffsetsRecord is a decoded __consumer_offsets record.
+type OffsetsRecord struct {
+	Type       OffsetsRecordType // Type is the type of this record.
+	KeyVersion int16             // KeyVersion is the version prefixing the record key.
+
+	// ValueVersion is the version prefixing the record value, or -1 for
+	// tombstones.
+	ValueVersion int16
+
+	// Tombstone is true if the record has no value: the offset commit
+	// was deleted or expired, or the group was deleted. For tombstones,
+	// only the key fields of OffsetCommit or GroupMetadata are set.
+	Tombstone bool
+
+	// OffsetCommit is set if Type is OffsetsRecordOffsetCommit.
+	OffsetCommit OffsetCommit
+
+	// GroupMetadata is set if Type is OffsetsRecordGroupMetadata.
+	GroupMetadata GroupMetadata
+}
+
+// DecodeOffsetsRecord decodes a record consumed from the __consumer_offsets
+// topic. This is a shortcut for DecodeOffsets(r.Key, r.Value).
+func DecodeOffsetsRecord(r *kgo.Record) (OffsetsRecord, error) {
+	return DecodeOffsets(r.Key, r.Value)
+}
+
+// DecodeOffsets decodes the key and value of a __consumer_offsets record. If
+// the key version is not one this package understands, this returns a record
+// of type OffsetsRecordUnknown with KeyVersion set and no error.
+func DecodeOffsets(key, value []byte) (OffsetsRecord, error) {
+	if len(key) < 2 {
+		return OffsetsRecord{}, errShortKey
+	}
+	rec := OffsetsRecord{
+		KeyVersion:   int16(binary.BigEndian.Uint16(key)),
+		ValueVersion: -1,
+		Tombstone:    value == nil,
+	}
+	if !rec.Tombstone && len(value) >= 2 {
+		rec.ValueVersion = int16(binary.BigEndian.Uint16(value))
+	}
+
+	switch rec.KeyVersion {
+	case 0, 1:
+		rec.Type = OffsetsRecordOffsetCommit
+		return rec, decodeOffsetCommit(&rec.OffsetCommit, key, value)
+	case 2:
+		rec.Type = OffsetsRecordGroupMetadata
+		return rec, decodeGroupMetadata(&rec.GroupMetadata, key, value)
+	default:
+		return rec, nil
+	}
+}
+
+func decodeOffsetCommit(c *OffsetCommit, key, value []byte) error {
+	var k kmsg.OffsetCommitKey
+	if err := k.ReadFrom(key); err != nil {
+		return fmt.Errorf("unable to decode offset commit key: %w", err)
+	}
+	c.Group, c.Topic, c.Partition = k.Group, k.Topic, k.Partition
+	if value == nil {
+		return nil
+	}
+
+	var v kmsg.OffsetCommitValue
+	if err := v.ReadFrom(value); err != nil {
+		return fmt.Errorf("unable to decode offset commit value for group %q: %w", k.Group, err)
+	}
+	c.Offset = v.Offset
+	c.LeaderEpoch = -1
+	if v.Version >= 3 {
+		c.LeaderEpoch = v.LeaderEpoch
+	}
+	c.Metadata = v.Metadata
+	c.CommitTimestamp = v.CommitTimestamp
+	c.ExpireTimestamp = -1
+	if v.Version == 1 {
+		c.ExpireTimestamp = v.ExpireTimestamp
+	}
+	c.TopicID = v.TopicID
+	return nil
+}
+
+func decodeGroupMetadata(g *GroupMetadata, key, value []byte) error {
+	var k kmsg.GroupMetadataKey
+	if err := k.ReadFrom(key); err != nil {
+		return fmt.Errorf("unable to decode group metadata key: %w", err)
+	}
+	g.Group = k.Group
+	if value == nil {
+		return nil
+	}
+
+	var v kmsg.GroupMetadataValue
+	if err := v.ReadFrom(value); err != nil {
+		return fmt.Errorf("unable to decode group metadata value for group %q: %w", k.Group, err)
+	}
+	g.ProtocolType = v.ProtocolType
+	g.Generation = v.Generation
+	if v.Protocol != nil {
+		g.Protocol = *v.Protocol
+	}
+	if v.Leader != nil {
+		g.Leader = *v.Leader
+	}
+	g.CurrentStateTimestamp = v.CurrentStateTimestamp
+	for _, vm := range v.Members {
+		m := GroupMetadataMember{
+			MemberID:               vm.MemberID,
+			InstanceID:             vm.InstanceID,
+			ClientID:               vm.ClientID,
+			ClientHost:             vm.ClientHost,
+			RebalanceTimeoutMillis: vm.RebalanceTimeoutMillis,
+			SessionTimeoutMillis:   vm.SessionTimeoutMillis,
+			Subscription:           vm.Subscription,
+			Assignment:             vm.Assignment,
+		}
+		if g.ProtocolType == "consumer" {
+			if len(vm.Subscription) > 0 {
+				var s kmsg.ConsumerMemberMetadata
+				if s.ReadFrom(vm.Subscription) == nil {
+					m.ConsumerSubscription = &s
+				}
+			}
+			if len(vm.Assignment) > 0 {
+				var a kmsg.ConsumerMemberAssignment
+				if a.ReadFrom(vm.Assignment) == nil {
+					m.ConsumerAssignment = &a
+				}
+			}
+		}
+		g.Members = append(g.Members, m)
+	}
+	return nil
+}
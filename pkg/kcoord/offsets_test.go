@@ -0,0 +1,128 @@
+package kcoord
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDecodeOffsetCommit(t *testing.T) {
+	key := (&kmsg.OffsetCommitKey{Version: 1, Group: "g", Topic: "t", Partition: 3}).AppendTo(nil)
+
+	for _, test := range []struct {
+		version int16
+		exp     OffsetCommit
+	}{
+		{0, OffsetCommit{Offset: 10, LeaderEpoch: -1, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1}},
+		{1, OffsetCommit{Offset: 10, LeaderEpoch: -1, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: 200}},
+		{2, OffsetCommit{Offset: 10, LeaderEpoch: -1, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1}},
+		{3, OffsetCommit{Offset: 10, LeaderEpoch: 5, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1}},
+		{4, OffsetCommit{Offset: 10, LeaderEpoch: 5, Metadata: "m", CommitTimestamp: 100, ExpireTimestamp: -1, TopicID: [16]byte{1}}},
+	} {
+		v := kmsg.OffsetCommitValue{
+			Version:         test.version,
+			Offset:          10,
+			LeaderEpoch:     5,
+			Metadata:        "m",
+			CommitTimestamp: 100,
+			ExpireTimestamp: 200,
+			TopicID:         [16]byte{1},
+		}
+		rec, err := DecodeOffsets(key, v.AppendTo(nil))
+		if err != nil {
+			t.Fatalf("v%d: unexpected err: %v", test.version, err)
+		}
+		test.exp.Group, test.exp.Topic, test.exp.Partition = "g", "t", 3
+		if rec.Type != OffsetsRecordOffsetCommit || rec.KeyVersion != 1 || rec.ValueVersion != test.version || rec.Tombstone {
+			t.Errorf("v%d: got unexpected record header %+v", test.version, rec)
+		}
+		if !reflect.DeepEqual(rec.OffsetCommit, test.exp) {
+			t.Errorf("v%d: got %+v != exp %+v", test.version, rec.OffsetCommit, test.exp)
+		}
+	}
+
+	rec, err := DecodeOffsets(key, nil)
+	if err != nil {
+		t.Fatalf("tombstone: unexpected err: %v", err)
+	}
+	if exp := (OffsetCommit{Group: "g", Topic: "t", Partition: 3}); !rec.Tombstone || rec.ValueVersion != -1 || rec.OffsetCommit != exp {
+		t.Errorf("tombstone: got %+v", rec)
+	}
+
+	if _, err := DecodeOffsets(key, []byte{0, 3, 0}); err == nil {
+		t.Error("expected error on truncated value")
+	}
+}
+
+func TestDecodeGroupMetadata(t *testing.T) {
+	key := (&kmsg.GroupMetadataKey{Version: 2, Group: "g"}).AppendTo(nil)
+
+	sub := (&kmsg.ConsumerMemberMetadata{Topics: []string{"t"}}).AppendTo(nil)
+	asn := (&kmsg.ConsumerMemberAssignment{Topics: []kmsg.ConsumerMemberAssignmentTopic{{Topic: "t", Partitions: []int32{0, 1}}}}).AppendTo(nil)
+	protocol, leader, instance := "sticky", "m1", "i1"
+
+	for _, version := range []int16{0, 1, 2, 3, 4} {
+		v := kmsg.NewGroupMetadataValue()
+		v.Version = version
+		v.ProtocolType = "consumer"
+		v.Generation = 7
+		v.Protocol = &protocol
+		v.Leader = &leader
+		v.CurrentStateTimestamp = 1000
+		m := kmsg.NewGroupMetadataValueMember()
+		m.MemberID = "m1"
+		m.InstanceID = &instance
+		m.ClientID = "c"
+		m.ClientHost = "/127.0.0.1"
+		m.RebalanceTimeoutMillis = 60000
+		m.SessionTimeoutMillis = 45000
+		m.Subscription = sub
+		m.Assignment = asn
+		v.Members = append(v.Members, m)
+
+		rec, err := DecodeOffsets(key, v.AppendTo(nil))
+		if err != nil {
+			t.Fatalf("v%d: unexpected err: %v", version, err)
+		}
+		if rec.Type != OffsetsRecordGroupMetadata || rec.ValueVersion != version {
+			t.Fatalf("v%d: got unexpected record header %+v", version, rec)
+		}
+		g := rec.GroupMetadata
+		if g.Group != "g" || g.ProtocolType != "consumer" || g.Generation != 7 || g.Protocol != protocol || g.Leader != leader {
+			t.Errorf("v%d: got unexpected group %+v", version, g)
+		}
+		if exp := map[bool]int64{true: 1000, false: -1}[version >= 2]; g.CurrentStateTimestamp != exp {
+			t.Errorf("v%d: got state timestamp %d != exp %d", version, g.CurrentStateTimestamp, exp)
+		}
+		if len(g.Members) != 1 {
+			t.Fatalf("v%d: got %d members != exp 1", version, len(g.Members))
+		}
+		gm := g.Members[0]
+		if (gm.InstanceID != nil) != (version >= 3) {
+			t.Errorf("v%d: got unexpected instance ID %v", version, gm.InstanceID)
+		}
+		if exp := map[bool]int32{true: 60000, false: -1}[version >= 1]; gm.RebalanceTimeoutMillis != exp {
+			t.Errorf("v%d: got rebalance timeout %d != exp %d", version, gm.RebalanceTimeoutMillis, exp)
+		}
+		if gm.ConsumerSubscription == nil || !reflect.DeepEqual(gm.ConsumerSubscription.Topics, []string{"t"}) {
+			t.Errorf("v%d: got unexpected subscription %+v", version, gm.ConsumerSubscription)
+		}
+		if gm.ConsumerAssignment == nil || len(gm.ConsumerAssignment.Topics) != 1 || !reflect.DeepEqual(gm.ConsumerAssignment.Topics[0].Partitions, []int32{0, 1}) {
+			t.Errorf("v%d: got unexpected assignment %+v", version, gm.ConsumerAssignment)
+		}
+	}
+}
+
+func TestDecodeOffsetsUnknown(t *testing.T) {
+	rec, err := DecodeOffsets([]byte{0, 11, 'x'}, []byte{0, 0})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if rec.Type != OffsetsRecordUnknown || rec.KeyVersion != 11 {
+		t.Errorf("got unexpected record %+v", rec)
+	}
+	if _, err := DecodeOffsets([]byte{0}, nil); err == nil {
+		t.Error("expected error on short key")
+	}
+}
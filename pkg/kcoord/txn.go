@@ -0,0 +1,199 @@
+package kcoord
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// TxnStateTopic is the internal topic that transaction coordinators write
+// transactional ID state to.
+const TxnStateTopic = "__transaction_state"
+
+// TxnRecordType is the type of a record in the __transaction_state topic,
+// determined by the version that prefixes the record key.
+type TxnRecordType int8
+
+const (
+	// TxnRecordUnknown is a record with a key version this package does
+	// not decode.
+	TxnRecordUnknown TxnRecordType = iota
+	// TxnRecordMetadata is transaction metadata, key version 0.
+	TxnRecordMetadata
+)
+
+// String returns the record type in words, or UNKNOWN.
+func (t TxnRecordType) String() string {
+	switch t {
+	case TxnRecordMetadata:
+		return "TXN_METADATA"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// TxnMetadata is the state of a transactional ID.
+type TxnMetadata struct {
+	TransactionalID string // TransactionalID is the transactional ID this state is for.
+
+	ProducerID    int64 // ProducerID is the producer ID in use by the transactional ID.
+	ProducerEpoch int16 // ProducerEpoch is the epoch of the producer ID.
+
+	// PreviousProducerID and NextProducerID are the producer IDs before
+	// and after an epoch overflow rotated the ID (KIP-890), or -1. These
+	// are only written in value version 1+.
+	PreviousProducerID int64
+	NextProducerID     int64
+
+	// ClientTransactionVersion is the transaction version the client
+	// used (KIP-890), or 0 if the value version predates it.
+	ClientTransactionVersion int16
+
+	TimeoutMillis int32                 // TimeoutMillis is the transaction timeout.
+	State         kmsg.TransactionState // State is the transaction state, e.g. Ongoing or CompleteCommit.
+
+	// Partitions are the partitions that are part of the ongoing
+	// transaction, by topic. This is empty once a transaction completes.
+	Partitions map[string][]int32
+
+	LastUpdateTimestamp int64 // LastUpdateTimestamp is the millisecond timestamp the state was last updated.
+	StartTimestamp      int64 // StartTimestamp is the millisecond timestamp the current transaction started, or -1.
+}
+
+// TxnRecord is a decoded __transaction_state record.
+type TxnRecord struct {
+	Type       TxnRecordType // Type is the type of this record.
+	KeyVersion int16         // KeyVersion is the version prefixing the record key.
+
+	// ValueVersion is the version prefixing the record value, or -1 for
+	// tombstones.
+	ValueVersion int16
+
+	// Tombstone is true if the record has no value: the transactional ID
+	// expired. For tombstones, only Metadata.TransactionalID is set.
+	Tombstone bool
+
+	// Metadata is set if Type is TxnRecordMetadata.
+	Metadata TxnMetadata
+}
+
+// DecodeTxnRecord decodes a record consumed from the __transaction_state
+// topic. This is a shortcut for DecodeTxn(r.Key, r.Value).
+func DecodeTxnRecord(r *kgo.Record) (TxnRecord, error) {
+	return DecodeTxn(r.Key, r.Value)
+}
+
+// DecodeTxn decodes the key and value of a __transaction_state record. If the
+// key version is not one this package understands, this returns a record of
+// type TxnRecordUnknown with KeyVersion set and no error.
+func DecodeTxn(key, value []byte) (TxnRecord, error) {
+	if len(key) < 2 {
+		return TxnRecord{}, errShortKey
+	}
+	rec := TxnRecord{
+		KeyVersion:   int16(binary.BigEndian.Uint16(key)),
+		ValueVersion: -1,
+		Tombstone:    value == nil,
+	}
+	if rec.KeyVersion != 0 {
+		return rec, nil
+	}
+	rec.Type = TxnRecordMetadata
+
+	var k kmsg.TxnMetadataKey
+	if err := k.ReadFrom(key); err != nil {
+		return rec, fmt.Errorf("unable to decode txn metadata key: %w", err)
+	}
+	rec.Metadata.TransactionalID = k.TransactionalID
+	if value == nil {
+		return rec, nil
+	}
+	if err := decodeTxnValue(&rec, value); err != nil {
+		return rec, fmt.Errorf("unable to decode txn metadata value for transactional ID %q: %w", k.TransactionalID, err)
+	}
+	return rec, nil
+}
+
+// decodeTxnValue decodes the value by hand rather than through
+// kmsg.TxnMetadataValue, which only understands version 0. Version 1 is
+// flexible and adds the KIP-890 fields as tags.
+func decodeTxnValue(rec *TxnRecord, value []byte) error {
+	b := kbin.Reader{Src: value}
+	version := b.Int16()
+	rec.ValueVersion = version
+	isFlexible := version >= 1
+
+	m := &rec.Metadata
+	m.PreviousProducerID, m.NextProducerID = -1, -1
+	m.ProducerID = b.Int64()
+	m.ProducerEpoch = b.Int16()
+	m.TimeoutMillis = b.Int32()
+	m.State = kmsg.TransactionState(b.Int8())
+
+	var ntopics int32
+	if isFlexible {
+		ntopics = b.CompactArrayLen()
+	} else {
+		ntopics = b.ArrayLen()
+	}
+	for ; ntopics > 0 && b.Ok(); ntopics-- {
+		var topic string
+		if isFlexible {
+			topic = b.CompactString()
+		} else {
+			topic = b.String()
+		}
+		var nparts int32
+		if isFlexible {
+			nparts = b.CompactArrayLen()
+		} else {
+			nparts = b.ArrayLen()
+		}
+		var ps []int32
+		for ; nparts > 0 && b.Ok(); nparts-- {
+			ps = append(ps, b.Int32())
+		}
+		if isFlexible {
+			skipTags(&b)
+		}
+		if m.Partitions == nil {
+			m.Partitions = make(map[string][]int32)
+		}
+		m.Partitions[topic] = append(m.Partitions[topic], ps...)
+	}
+
+	m.LastUpdateTimestamp = b.Int64()
+	m.StartTimestamp = b.Int64()
+
+	if isFlexible {
+		for num := b.Uvarint(); num > 0 && b.Ok(); num-- {
+			tag, size := b.Uvarint(), b.Uvarint()
+			tb := kbin.Reader{Src: b.Span(int(size))}
+			switch tag {
+			default:
+				continue // unknown tags are skipped, as in kmsg
+			case 0:
+				m.PreviousProducerID = tb.Int64()
+			case 1:
+				m.NextProducerID = tb.Int64()
+			case 2:
+				m.ClientTransactionVersion = tb.Int16()
+			}
+			if err := tb.Complete(); err != nil {
+				return err
+			}
+		}
+	}
+	return b.Complete()
+}
+
+// skipTags skips a tag section of a flexible struct.
+func skipTags(b *kbin.Reader) {
+	for num := b.Uvarint(); num > 0 && b.Ok(); num-- {
+		b.Uvarint()
+		b.Span(int(b.Uvarint()))
+	}
+}
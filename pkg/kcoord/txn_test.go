@@ -0,0 +1,105 @@
+package kcoord
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestDecodeTxn(t *testing.T) {
+	key := (&kmsg.TxnMetadataKey{TransactionalID: "txid"}).AppendTo(nil)
+
+	v0 := (&kmsg.TxnMetadataValue{
+		ProducerID:          3,
+		ProducerEpoch:       4,
+		TimeoutMillis:       60000,
+		State:               kmsg.TransactionStateOngoing,
+		Topics:              []kmsg.TxnMetadataValueTopic{{Topic: "t", Partitions: []int32{0, 2}}},
+		LastUpdateTimestamp: 200,
+		StartTimestamp:      100,
+	}).AppendTo(nil)
+
+	// Version 1 is flexible, with KIP-890 fields as tags at the end.
+	v1 := kbin.AppendInt16(nil, 1)
+	v1 = kbin.AppendInt64(v1, 3)
+	v1 = kbin.AppendInt16(v1, 4)
+	v1 = kbin.AppendInt32(v1, 60000)
+	v1 = kbin.AppendInt8(v1, int8(kmsg.TransactionStateOngoing))
+	v1 = kbin.AppendCompactArrayLen(v1, 1)
+	v1 = kbin.AppendCompactString(v1, "t")
+	v1 = kbin.AppendCompactArrayLen(v1, 2)
+	v1 = kbin.AppendInt32(v1, 0)
+	v1 = kbin.AppendInt32(v1, 2)
+	v1 = kbin.AppendUvarint(v1, 0) // topic tags
+	v1 = kbin.AppendInt64(v1, 200)
+	v1 = kbin.AppendInt64(v1, 100)
+	// Unknown tags, empty or not, are skipped.
+	v1 = kbin.AppendUvarint(v1, 5)
+	for _, tag := range []struct {
+		tag uint32
+		v   []byte
+	}{
+		{0, kbin.AppendInt64(nil, 1)},
+		{1, kbin.AppendInt64(nil, 5)},
+		{2, kbin.AppendInt16(nil, 2)},
+		{8, nil},
+		{9, []byte{1, 2, 3}},
+	} {
+		v1 = kbin.AppendUvarint(v1, tag.tag)
+		v1 = kbin.AppendUvarint(v1, uint32(len(tag.v)))
+		v1 = append(v1, tag.v...)
+	}
+
+	base := TxnMetadata{
+		TransactionalID:     "txid",
+		ProducerID:          3,
+		ProducerEpoch:       4,
+		PreviousProducerID:  -1,
+		NextProducerID:      -1,
+		TimeoutMillis:       60000,
+		State:               kmsg.TransactionStateOngoing,
+		Partitions:          map[string][]int32{"t": {0, 2}},
+		LastUpdateTimestamp: 200,
+		StartTimestamp:      100,
+	}
+	withTags := base
+	withTags.PreviousProducerID, withTags.NextProducerID, withTags.ClientTransactionVersion = 1, 5, 2
+
+	for _, test := range []struct {
+		value []byte
+		ver   int16
+		exp   TxnMetadata
+	}{
+		{v0, 0, base},
+		{v1, 1, withTags},
+	} {
+		rec, err := DecodeTxn(key, test.value)
+		if err != nil {
+			t.Fatalf("v%d: unexpected err: %v", test.ver, err)
+		}
+		if rec.Type != TxnRecordMetadata || rec.ValueVersion != test.ver || rec.Tombstone {
+			t.Errorf("v%d: got unexpected record header %+v", test.ver, rec)
+		}
+		if !reflect.DeepEqual(rec.Metadata, test.exp) {
+			t.Errorf("v%d: got %+v != exp %+v", test.ver, rec.Metadata, test.exp)
+		}
+		if _, err := DecodeTxn(key, test.value[:len(test.value)-3]); err == nil {
+			t.Errorf("v%d: expected error on truncated value", test.ver)
+		}
+	}
+
+	rec, err := DecodeTxn(key, nil)
+	if err != nil {
+		t.Fatalf("tombstone: unexpected err: %v", err)
+	}
+	if !rec.Tombstone || rec.Metadata.TransactionalID != "txid" || rec.ValueVersion != -1 {
+		t.Errorf("tombstone: got %+v", rec)
+	}
+
+	rec, err = DecodeTxn([]byte{0, 1, 0}, nil)
+	if err != nil || rec.Type != TxnRecordUnknown {
+		t.Errorf("unknown key: got %+v, %v", rec, err)
+	}
+}
@@ -0,0 +1,263 @@
+package kerr
+
+import "errors"
+
+// ErrorCategory is a broad classification of Kafka errors, allowing
+// applications to build alerting and retry logic without maintaining lists of
+// error codes. Categories are orthogonal to whether an error is retriable:
+// for example, OffsetOutOfRange and FetchSessionIDNotFound are both offset
+// errors, but only the latter is retriable.
+type ErrorCategory int8
+
+const (
+	// CategoryUnknown is for errors that are not Kafka errors.
+	CategoryUnknown ErrorCategory = iota
+	// CategoryServer is for unexpected errors in the broker, such as
+	// storage errors.
+	CategoryServer
+	// CategoryNetwork is for errors where the broker timed out or had a
+	// network problem.
+	CategoryNetwork
+	// CategoryMetadata is for errors caused by stale or unavailable
+	// metadata, such as a partition moving to a new leader. These
+	// generally resolve by refreshing metadata.
+	CategoryMetadata
+	// CategoryCoordinator is for errors caused by a group or transaction
+	// coordinator moving or loading.
+	CategoryCoordinator
+	// CategoryGroup is for errors in the consumer group protocol.
+	CategoryGroup
+	// CategoryTransaction is for errors from idempotent and transactional
+	// producing.
+	CategoryTransaction
+	// CategoryAuth is for authentication and authorization errors.
+	CategoryAuth
+	// CategoryQuota is for errors caused by hitting a quota.
+	CategoryQuota
+	// CategoryOffset is for errors positioning a fetch, including fetch
+	// session errors.
+	CategoryOffset
+	// CategoryRecord is for errors caused by invalid records or batches
+	// being produced.
+	CategoryRecord
+	// CategoryInvalid is for invalid requests or requests that are not
+	// allowed, typically admin requests.
+	CategoryInvalid
+	// CategoryUnsupported is for requests the broker does not support.
+	CategoryUnsupported
+	// CategoryCluster is for errors internal to the cluster, such as
+	// broker registration and KRaft errors.
+	CategoryCluster
+	// CategoryShare is for errors in share groups.
+	CategoryShare
+)
+
+func (c ErrorCategory) String() string {
+	switch c {
+	case CategoryServer:
+		return "server"
+	case CategoryNetwork:
+		return "network"
+	case CategoryMetadata:
+		return "metadata"
+	case CategoryCoordinator:
+		return "coordinator"
+	case CategoryGroup:
+		return "group"
+	case CategoryTransaction:
+		return "transaction"
+	case CategoryAuth:
+		return "auth"
+	case CategoryQuota:
+		return "quota"
+	case CategoryOffset:
+		return "offset"
+	case CategoryRecord:
+		return "record"
+	case CategoryInvalid:
+		return "invalid"
+	case CategoryUnsupported:
+		return "unsupported"
+	case CategoryCluster:
+		return "cluster"
+	case CategoryShare:
+		return "share"
+	default:
+		return "unknown"
+	}
+}
+
+// Category returns the category of a Kafka error, or CategoryUnknown if the
+// error is not a Kafka error.
+func Category(err error) ErrorCategory {
+	var kerr *Error
+	if !errors.As(err, &kerr) {
+		return CategoryUnknown
+	}
+	return CategoryForCode(kerr.Code)
+}
+
+// CategoryForCode returns the category of the error for an error code. Code 0
+// and unknown codes return CategoryUnknown.
+func CategoryForCode(code int16) ErrorCategory {
+	err := TypedErrorForCode(code)
+	if err == nil || err == UnknownServerError && code != UnknownServerError.Code {
+		return CategoryUnknown
+	}
+	return categories[err]
+}
+
+// IsAuth returns whether a Kafka error is an authentication or authorization
+// error.
+func IsAuth(err error) bool { return Category(err) == CategoryAuth }
+
+// IsQuota returns whether a Kafka error is caused by hitting a quota.
+func IsQuota(err error) bool { return Category(err) == CategoryQuota }
+
+// IsMetadata returns whether a Kafka error is caused by stale or unavailable
+// metadata.
+func IsMetadata(err error) bool { return Category(err) == CategoryMetadata }
+
+// Errors returns all known Kafka errors, sorted by error code.
+func Errors() []*Error {
+	errs := make([]*Error, 0, len(code2err))
+	for code := int16(-1); len(errs) < len(code2err)-1; code++ {
+		if err, ok := code2err[code].(*Error); ok {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+var categories = map[*Error]ErrorCategory{
+	UnknownServerError:                 CategoryServer,
+	OffsetOutOfRange:                   CategoryOffset,
+	CorruptMessage:                     CategoryRecord,
+	UnknownTopicOrPartition:            CategoryMetadata,
+	InvalidFetchSize:                   CategoryOffset,
+	LeaderNotAvailable:                 CategoryMetadata,
+	NotLeaderForPartition:              CategoryMetadata,
+	RequestTimedOut:                    CategoryNetwork,
+	BrokerNotAvailable:                 CategoryMetadata,
+	ReplicaNotAvailable:                CategoryMetadata,
+	MessageTooLarge:                    CategoryRecord,
+	StaleControllerEpoch:               CategoryCluster,
+	OffsetMetadataTooLarge:             CategoryGroup,
+	NetworkException:                   CategoryNetwork,
+	CoordinatorLoadInProgress:          CategoryCoordinator,
+	CoordinatorNotAvailable:            CategoryCoordinator,
+	NotCoordinator:                     CategoryCoordinator,
+	InvalidTopicException:              CategoryInvalid,
+	RecordListTooLarge:                 CategoryRecord,
+	NotEnoughReplicas:                  CategoryMetadata,
+	NotEnoughReplicasAfterAppend:       CategoryMetadata,
+	InvalidRequiredAcks:                CategoryRecord,
+	IllegalGeneration:                  CategoryGroup,
+	InconsistentGroupProtocol:          CategoryGroup,
+	InvalidGroupID:                     CategoryGroup,
+	UnknownMemberID:                    CategoryGroup,
+	InvalidSessionTimeout:              CategoryGroup,
+	RebalanceInProgress:                CategoryGroup,
+	InvalidCommitOffsetSize:            CategoryGroup,
+	TopicAuthorizationFailed:           CategoryAuth,
+	GroupAuthorizationFailed:           CategoryAuth,
+	ClusterAuthorizationFailed:         CategoryAuth,
+	InvalidTimestamp:                   CategoryRecord,
+	UnsupportedSaslMechanism:           CategoryAuth,
+	IllegalSaslState:                   CategoryAuth,
+	UnsupportedVersion:                 CategoryUnsupported,
+	TopicAlreadyExists:                 CategoryInvalid,
+	InvalidPartitions:                  CategoryInvalid,
+	InvalidReplicationFactor:           CategoryInvalid,
+	InvalidReplicaAssignment:           CategoryInvalid,
+	InvalidConfig:                      CategoryInvalid,
+	NotController:                      CategoryMetadata,
+	InvalidRequest:                     CategoryInvalid,
+	UnsupportedForMessageFormat:        CategoryRecord,
+	PolicyViolation:                    CategoryInvalid,
+	OutOfOrderSequenceNumber:           CategoryTransaction,
+	DuplicateSequenceNumber:            CategoryTransaction,
+	InvalidProducerEpoch:               CategoryTransaction,
+	InvalidTxnState:                    CategoryTransaction,
+	InvalidProducerIDMapping:           CategoryTransaction,
+	InvalidTransactionTimeout:          CategoryTransaction,
+	ConcurrentTransactions:             CategoryTransaction,
+	TransactionCoordinatorFenced:       CategoryTransaction,
+	TransactionalIDAuthorizationFailed: CategoryAuth,
+	SecurityDisabled:                   CategoryAuth,
+	OperationNotAttempted:              CategoryInvalid,
+	KafkaStorageError:                  CategoryServer,
+	LogDirNotFound:                     CategoryServer,
+	SaslAuthenticationFailed:           CategoryAuth,
+	UnknownProducerID:                  CategoryTransaction,
+	ReassignmentInProgress:             CategoryInvalid,
+	DelegationTokenAuthDisabled:        CategoryAuth,
+	DelegationTokenNotFound:            CategoryAuth,
+	DelegationTokenOwnerMismatch:       CategoryAuth,
+	DelegationTokenRequestNotAllowed:   CategoryAuth,
+	DelegationTokenAuthorizationFailed: CategoryAuth,
+	DelegationTokenExpired:             CategoryAuth,
+	InvalidPrincipalType:               CategoryAuth,
+	NonEmptyGroup:                      CategoryGroup,
+	GroupIDNotFound:                    CategoryGroup,
+	FetchSessionIDNotFound:             CategoryOffset,
+	InvalidFetchSessionEpoch:           CategoryOffset,
+	ListenerNotFound:                   CategoryMetadata,
+	TopicDeletionDisabled:              CategoryInvalid,
+	FencedLeaderEpoch:                  CategoryMetadata,
+	UnknownLeaderEpoch:                 CategoryMetadata,
+	UnsupportedCompressionType:         CategoryRecord,
+	StaleBrokerEpoch:                   CategoryCluster,
+	OffsetNotAvailable:                 CategoryMetadata,
+	MemberIDRequired:                   CategoryGroup,
+	PreferredLeaderNotAvailable:        CategoryMetadata,
+	GroupMaxSizeReached:                CategoryGroup,
+	FencedInstanceID:                   CategoryGroup,
+	EligibleLeadersNotAvailable:        CategoryMetadata,
+	ElectionNotNeeded:                  CategoryInvalid,
+	NoReassignmentInProgress:           CategoryInvalid,
+	GroupSubscribedToTopic:             CategoryGroup,
+	InvalidRecord:                      CategoryRecord,
+	UnstableOffsetCommit:               CategoryGroup,
+	ThrottlingQuotaExceeded:            CategoryQuota,
+	ProducerFenced:                     CategoryTransaction,
+	ResourceNotFound:                   CategoryInvalid,
+	DuplicateResource:                  CategoryInvalid,
+	UnacceptableCredential:             CategoryAuth,
+	InconsistentVoterSet:               CategoryCluster,
+	InvalidUpdateVersion:               CategoryInvalid,
+	FeatureUpdateFailed:                CategoryInvalid,
+	PrincipalDeserializationFailure:    CategoryAuth,
+	SnapshotNotFound:                   CategoryCluster,
+	PositionOutOfRange:                 CategoryOffset,
+	UnknownTopicID:                     CategoryMetadata,
+	DuplicateBrokerRegistration:        CategoryCluster,
+	BrokerIDNotRegistered:              CategoryCluster,
+	InconsistentTopicID:                CategoryMetadata,
+	InconsistentClusterID:              CategoryCluster,
+	TransactionalIDNotFound:            CategoryTransaction,
+	FetchSessionTopicIDError:           CategoryOffset,
+	IneligibleReplica:                  CategoryCluster,
+	NewLeaderElected:                   CategoryMetadata,
+	OffsetMovedToTieredStorage:         CategoryOffset,
+	FencedMemberEpoch:                  CategoryGroup,
+	UnreleasedInstanceID:               CategoryGroup,
+	UnsupportedAssignor:                CategoryGroup,
+	StaleMemberEpoch:                   CategoryGroup,
+	MismatchedEndpointType:             CategoryMetadata,
+	UnsupportedEndpointType:            CategoryMetadata,
+	UnknownControllerID:                CategoryMetadata,
+	UnknownSubscriptionID:              CategoryInvalid,
+	TelemetryTooLarge:                  CategoryInvalid,
+	InvalidRegistration:                CategoryCluster,
+	TransactionAbortable:               CategoryTransaction,
+	InvalidRecordState:                 CategoryShare,
+	ShareSessionNotFound:               CategoryShare,
+	InvalidShareSessionEpoch:           CategoryShare,
+	FencedStateEpoch:                   CategoryShare,
+	InvalidVoterKey:                    CategoryCluster,
+	DuplicateVoter:                     CategoryCluster,
+	VoterNotFound:                      CategoryCluster,
+	InvalidRegularExpression:           CategoryGroup,
+	RebootstrapRequired:                CategoryMetadata,
+}
@@ -0,0 +1,44 @@
+package kerr
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	errs := Errors()
+	if len(errs) != len(code2err)-1 {
+		t.Fatalf("got %d errors, exp %d", len(errs), len(code2err)-1)
+	}
+	for i, err := range errs {
+		if i > 0 && errs[i-1].Code >= err.Code {
+			t.Errorf("errors not sorted at %s", err.Message)
+		}
+		if c := Category(err); c == CategoryUnknown {
+			t.Errorf("%s has no category", err.Message)
+		}
+	}
+	if len(categories) != len(errs) {
+		t.Errorf("got %d categorized errors, exp %d", len(categories), len(errs))
+	}
+
+	for _, test := range []struct {
+		err error
+		exp ErrorCategory
+	}{
+		{nil, CategoryUnknown},
+		{fmt.Errorf("wrapped: %w", TopicAuthorizationFailed), CategoryAuth},
+		{ThrottlingQuotaExceeded, CategoryQuota},
+		{NotLeaderForPartition, CategoryMetadata},
+	} {
+		if got := Category(test.err); got != test.exp {
+			t.Errorf("%v: got %v, exp %v", test.err, got, test.exp)
+		}
+	}
+	if CategoryForCode(0) != CategoryUnknown || CategoryForCode(10000) != CategoryUnknown {
+		t.Error("expected no category for codes 0 and unknown codes")
+	}
+	if !IsAuth(SaslAuthenticationFailed) || IsAuth(UnknownServerError) || !IsQuota(ThrottlingQuotaExceeded) {
+		t.Error("unexpected IsAuth / IsQuota result")
+	}
+}
@@ -18,20 +18,21 @@ import (
 
 func init() { regKey(0, 3, 13) }
 
-func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
+func (c *Cluster) handleProduce(creq *clientReq) (kmsg.Response, error) {
 	type tpid struct {
 		t  string
 		id [16]byte
 	}
 	var (
-		req   = kreq.(*kmsg.ProduceRequest)
+		b     = creq.cc.b
+		req   = creq.kreq.(*kmsg.ProduceRequest)
 		resp  = req.ResponseKind().(*kmsg.ProduceResponse)
 		tdone = make(map[tpid][]kmsg.ProduceResponseTopicPartition)
 
 		id = func(t kmsg.ProduceRequestTopic) tpid { return tpid{t.Topic, t.TopicID} }
 	)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -98,6 +99,10 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 			}
 			rt.Topic = topic
 		}
+		if !c.allowed(creq, kmsg.ACLResourceTypeTopic, rt.Topic, kmsg.ACLOperationWrite) {
+			donet(rt, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		for _, rp := range rt.Partitions {
 			pd, ok := c.data.tps.getp(rt.Topic, rp.Partition)
 			if !ok {
@@ -143,9 +148,11 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 				continue
 			}
 			logAppendTime := int64(-1)
-			if attrs&0x0008 > 0 {
+			if attrs&0x0008 > 0 || c.data.topicConfig(rt.Topic, "message.timestamp.type") == "LogAppendTime" {
+				b.Attributes |= 0x0008
 				b.FirstTimestamp = now
 				b.MaxTimestamp = now
+				b.CRC = batchCRC(&b)
 				logAppendTime = now
 			}
 			if attrs&0xfff0 != 0 { // TODO txn bit
@@ -193,3 +200,10 @@ func (c *Cluster) handleProduce(b *broker, kreq kmsg.Request) (kmsg.Response, er
 }
 
 var crc32c = crc32.MakeTable(crc32.Castagnoli)
+
+// batchCRC returns the CRC of a batch, which must be recomputed if we modify
+// any field after the CRC (i.e., when overwriting timestamps).
+func batchCRC(b *kmsg.RecordBatch) int32 {
+	raw := b.AppendTo(nil)
+	return int32(crc32.Checksum(raw[21:], crc32c))
+}
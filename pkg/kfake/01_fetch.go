@@ -26,7 +26,7 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 		resp = req.ResponseKind().(*kmsg.FetchResponse)
 	)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -46,6 +46,10 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 			if !ok {
 				continue
 			}
+			if !c.allowed(creq, kmsg.ACLResourceTypeTopic, rt.Topic, kmsg.ACLOperationRead) {
+				returnEarly = true // TopicAuthorizationFailed
+				break out
+			}
 			for _, rp := range rt.Partitions {
 				pd, ok := t[rp.Partition]
 				if !ok || pd.createdAt.After(creq.at) {
@@ -55,6 +59,10 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 					returnEarly = true // NotLeaderForPartition
 					break out
 				}
+				if le := rp.CurrentLeaderEpoch; le != -1 && le != pd.epoch {
+					returnEarly = true // FencedLeaderEpoch or UnknownLeaderEpoch
+					break out
+				}
 				i, ok, atEnd := pd.searchOffset(rp.FetchOffset)
 				if atEnd {
 					continue
@@ -102,6 +110,9 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 				if !ok || pd.createdAt.After(creq.at) {
 					continue
 				}
+				if pd.watch == nil {
+					pd.watch = make(map[*watchFetch]struct{})
+				}
 				pd.watch[w] = struct{}{}
 				w.in = append(w.in, pd)
 			}
@@ -153,7 +164,12 @@ func (c *Cluster) handleFetch(creq *clientReq, w *watchFetch) (kmsg.Response, er
 	nbytes = 0
 full:
 	for _, rt := range req.Topics {
+		allowed := c.allowed(creq, kmsg.ACLResourceTypeTopic, rt.Topic, kmsg.ACLOperationRead)
 		for _, rp := range rt.Partitions {
+			if !allowed {
+				donep(rt.Topic, rt.TopicID, rp.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
 			pd, ok := c.data.tps.getp(rt.Topic, rp.Partition)
 			if !ok {
 				if req.Version >= 13 {
@@ -170,6 +186,13 @@ full:
 				includeBrokers = true
 				continue
 			}
+			if le := rp.CurrentLeaderEpoch; le != -1 && le < pd.epoch {
+				donep(rt.Topic, rt.TopicID, rp.Partition, kerr.FencedLeaderEpoch.Code)
+				continue
+			} else if le > pd.epoch {
+				donep(rt.Topic, rt.TopicID, rp.Partition, kerr.UnknownLeaderEpoch.Code)
+				continue
+			}
 			sp := donep(rt.Topic, rt.TopicID, rp.Partition, 0)
 			sp.HighWatermark = pd.highWatermark
 			sp.LastStableOffset = pd.lastStableOffset
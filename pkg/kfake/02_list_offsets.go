@@ -1,19 +1,17 @@
 package kfake
 
 import (
-	"sort"
-
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 func init() { regKey(2, 0, 10) }
 
-func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.ListOffsetsRequest)
+func (c *Cluster) handleListOffsets(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.ListOffsetsRequest)
 	resp := req.ResponseKind().(*kmsg.ListOffsetsResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -39,7 +37,12 @@ func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response
 
 	for _, rt := range req.Topics {
 		ps, ok := c.data.tps.gett(rt.Topic)
+		allowed := c.allowed(creq, kmsg.ACLResourceTypeTopic, rt.Topic, kmsg.ACLOperationDescribe)
 		for _, rp := range rt.Partitions {
+			if !allowed {
+				donep(rt.Topic, rp.Partition, kerr.TopicAuthorizationFailed.Code)
+				continue
+			}
 			if !ok {
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
@@ -49,7 +52,7 @@ func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response
 				donep(rt.Topic, rp.Partition, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
-			if pd.leader != b {
+			if pd.leader != creq.cc.b {
 				donep(rt.Topic, rp.Partition, kerr.NotLeaderForPartition.Code)
 				continue
 			}
@@ -74,39 +77,22 @@ func (c *Cluster) handleListOffsets(b *broker, kreq kmsg.Request) (kmsg.Response
 				} else {
 					sp.Offset = pd.highWatermark
 				}
+			case -3: // max timestamp, v7+
+				o, ts, err := pd.maxTimestampOffset()
+				if err != nil {
+					sp.ErrorCode = kerr.CorruptMessage.Code
+					continue
+				}
+				sp.Offset, sp.Timestamp = o, ts
+			case -4: // earliest local, v8+; we have no tiered storage
+				sp.Offset = pd.logStartOffset
 			default:
-				// returns the index of the first batch _after_ the requested timestamp
-				idx, _ := sort.Find(len(pd.batches), func(idx int) int {
-					maxTimestamp := pd.batches[idx].MaxTimestamp
-					switch {
-					case maxTimestamp > rp.Timestamp:
-						return -1
-					case maxTimestamp == rp.Timestamp:
-						return 0
-					default:
-						return 1
-					}
-				})
-				if idx == len(pd.batches) {
-					sp.Offset = -1
-				} else {
-					batch := pd.batches[idx]
-					sp.Offset = batch.FirstOffset
-					sp.Timestamp = batch.FirstTimestamp
-					err := forEachBatchRecord(batch.RecordBatch, func(rec kmsg.Record) error {
-						timestamp := batch.FirstTimestamp + rec.TimestampDelta64
-						offset := batch.FirstOffset + int64(rec.OffsetDelta)
-						if timestamp <= rp.Timestamp {
-							sp.Offset = offset
-							sp.Timestamp = timestamp
-						}
-						return nil
-					})
-					if err != nil {
-						donep(rt.Topic, rp.Partition, kerr.CorruptMessage.Code)
-						continue
-					}
+				o, ts, err := pd.searchTimestamp(rp.Timestamp)
+				if err != nil {
+					sp.ErrorCode = kerr.CorruptMessage.Code
+					continue
 				}
+				sp.Offset, sp.Timestamp = o, ts
 			}
 		}
 	}
@@ -10,11 +10,11 @@ import (
 
 func init() { regKey(3, 0, 13) }
 
-func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.MetadataRequest)
+func (c *Cluster) handleMetadata(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.MetadataRequest)
 	resp := req.ResponseKind().(*kmsg.MetadataResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -58,22 +58,20 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 		return &st.Partitions[len(st.Partitions)-1]
 	}
 	okp := func(t string, id uuid, p int32, pd *partData) {
-		nreplicas := c.data.treplicas[t]
-		if nreplicas > len(c.bs) {
-			nreplicas = len(c.bs)
-		}
-
 		sp := donep(t, id, p, 0)
 		sp.Leader = pd.leader.node
 		sp.LeaderEpoch = pd.epoch
-
-		for i := 0; i < nreplicas; i++ {
-			idx := (pd.leader.bsIdx + i) % len(c.bs)
-			sp.Replicas = append(sp.Replicas, c.bs[idx].node)
-		}
+		sp.Replicas = c.replicas(t, pd)
 		sp.ISR = sp.Replicas
 	}
 
+	// Kafka resolves topics by ID if any requested topic has an ID, in
+	// which case topics requested only by name are not returned.
+	var useIDs bool
+	for _, rt := range req.Topics {
+		useIDs = useIDs || rt.TopicID != noID
+	}
+
 	allowAuto := req.AllowAutoTopicCreation && c.cfg.allowAutoTopic
 	for _, rt := range req.Topics {
 		var topic string
@@ -86,15 +84,19 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 				donet("", rt.TopicID, kerr.UnknownTopicID.Code)
 				continue
 			}
-		} else if rt.Topic == nil {
+		} else if rt.Topic == nil || useIDs {
 			continue
 		} else {
 			topic = *rt.Topic
 		}
+		if !c.allowed(creq, kmsg.ACLResourceTypeTopic, topic, kmsg.ACLOperationDescribe) {
+			donet(topic, rt.TopicID, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 
 		ps, ok := c.data.tps.gett(topic)
 		if !ok {
-			if !allowAuto {
+			if !allowAuto || !c.allowedCreate(creq, topic) {
 				donet(topic, rt.TopicID, kerr.UnknownTopicOrPartition.Code)
 				continue
 			}
@@ -109,6 +111,9 @@ func (c *Cluster) handleMetadata(kreq kmsg.Request) (kmsg.Response, error) {
 	}
 	if req.Topics == nil && c.data.tps != nil {
 		for topic, ps := range c.data.tps {
+			if !c.allowed(creq, kmsg.ACLResourceTypeTopic, topic, kmsg.ACLOperationDescribe) {
+				continue
+			}
 			id := c.data.t2id[topic]
 			for p, pd := range ps {
 				okp(topic, id, p, pd)
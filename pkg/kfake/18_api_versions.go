@@ -15,33 +15,22 @@ func (c *Cluster) handleApiVersions(kreq kmsg.Request) (kmsg.Response, error) {
 	req := kreq.(*kmsg.ApiVersionsRequest)
 	resp := req.ResponseKind().(*kmsg.ApiVersionsResponse)
 
-	if resp.Version > 3 && resp.Version > apiVersionsKeys[18].MaxVersion {
+	if resp.Version > c.apiVersions[18].MaxVersion {
 		resp.Version = 0 // downgrades to 0 if the version is unknown
 		resp.ErrorCode = kerr.UnsupportedVersion.Code
 	}
 
 	// We do not checkReqVersion for ApiVersions; if the client uses a
 	// version larger than we support, we auto-downgrade.
-
-	// If we are handling ApiVersions, our package is initialized and we
-	// build our response once.
-	apiVersionsOnce.Do(func() {
-		for _, v := range apiVersionsKeys {
-			apiVersionsSorted = append(apiVersionsSorted, v)
-		}
-		sort.Slice(apiVersionsSorted, func(i, j int) bool {
-			return apiVersionsSorted[i].ApiKey < apiVersionsSorted[j].ApiKey
-		})
-	})
-	resp.ApiKeys = apiVersionsSorted
+	resp.ApiKeys = c.apiVersionsSorted
 
 	return resp, nil
 }
 
 // Called at the beginning of every request, this validates that the client
-// is sending requests within version ranges we can handle.
-func checkReqVersion(key, version int16) error {
-	v, exists := apiVersionsKeys[key]
+// is sending requests within version ranges we advertise.
+func (c *Cluster) checkReqVersion(key, version int16) error {
+	v, exists := c.apiVersions[key]
 	if !exists {
 		return fmt.Errorf("unsupported request key %d", key)
 	}
@@ -54,12 +43,46 @@ func checkReqVersion(key, version int16) error {
 	return nil
 }
 
+// initApiVersions builds the versions this cluster advertises: every key we
+// implement, limited by MaxVersions and KeyVersions. ApiVersions itself is
+// always advertised.
+func (c *Cluster) initApiVersions() {
+	apiVersionsMu.Lock()
+	defer apiVersionsMu.Unlock()
+
+	c.apiVersions = make(map[int16]kmsg.ApiVersionsResponseApiKey, len(apiVersionsKeys))
+	for key, v := range apiVersionsKeys {
+		if c.cfg.maxVersions != nil {
+			vmax, exists := c.cfg.maxVersions.LookupMaxKeyVersion(key)
+			if !exists {
+				if key != 18 {
+					continue
+				}
+				vmax = 0
+			}
+			v.MaxVersion = min(v.MaxVersion, vmax)
+		}
+		if kv, exists := c.cfg.keyVersions[key]; exists {
+			v.MinVersion = max(v.MinVersion, kv.min)
+			v.MaxVersion = min(v.MaxVersion, kv.max)
+		}
+		if v.MinVersion > v.MaxVersion {
+			if key != 18 {
+				continue
+			}
+			v.MinVersion, v.MaxVersion = 0, 0
+		}
+		c.apiVersions[key] = v
+		c.apiVersionsSorted = append(c.apiVersionsSorted, v)
+	}
+	sort.Slice(c.apiVersionsSorted, func(i, j int) bool {
+		return c.apiVersionsSorted[i].ApiKey < c.apiVersionsSorted[j].ApiKey
+	})
+}
+
 var (
 	apiVersionsMu   sync.Mutex
 	apiVersionsKeys = make(map[int16]kmsg.ApiVersionsResponseApiKey)
-
-	apiVersionsOnce   sync.Once
-	apiVersionsSorted []kmsg.ApiVersionsResponseApiKey
 )
 
 // Every request we implement calls regKey in an init function, allowing us to
@@ -11,11 +11,11 @@ import (
 
 func init() { regKey(19, 0, 7) }
 
-func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.CreateTopicsRequest)
+func (c *Cluster) handleCreateTopics(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.CreateTopicsRequest)
 	resp := req.ResponseKind().(*kmsg.CreateTopicsResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -32,7 +32,7 @@ func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 		}
 	}
 
-	if b != c.controller {
+	if creq.cc.b != c.controller {
 		donets(kerr.NotController.Code)
 		return resp, nil
 	}
@@ -47,6 +47,10 @@ func (c *Cluster) handleCreateTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 	}
 
 	for _, rt := range req.Topics {
+		if !c.allowedCreate(creq, rt.Topic) {
+			donet(rt.Topic, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		if _, ok := c.data.tps.gett(rt.Topic); ok {
 			donet(rt.Topic, kerr.TopicAlreadyExists.Code)
 			continue
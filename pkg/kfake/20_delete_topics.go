@@ -7,11 +7,11 @@ import (
 
 func init() { regKey(20, 0, 6) }
 
-func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Response, error) {
-	req := kreq.(*kmsg.DeleteTopicsRequest)
+func (c *Cluster) handleDeleteTopics(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DeleteTopicsRequest)
 	resp := req.ResponseKind().(*kmsg.DeleteTopicsResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -37,7 +37,7 @@ func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 		}
 	}
 
-	if b != c.controller {
+	if creq.cc.b != c.controller {
 		donets(kerr.NotController.Code)
 		return resp, nil
 	}
@@ -71,6 +71,10 @@ func (c *Cluster) handleDeleteTopics(b *broker, kreq kmsg.Request) (kmsg.Respons
 			topic = c.data.id2t[rt.TopicID]
 			id = rt.TopicID
 		}
+		if !c.allowed(creq, kmsg.ACLResourceTypeTopic, topic, kmsg.ACLOperationDelete) {
+			donet(&topic, id, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
 		t, ok := c.data.tps.gett(topic)
 		if !ok {
 			if rt.Topic != nil {
@@ -12,21 +12,31 @@ import (
 
 func init() { regKey(22, 0, 4) }
 
-func (c *Cluster) handleInitProducerID(kreq kmsg.Request) (kmsg.Response, error) {
+func (c *Cluster) handleInitProducerID(creq *clientReq) (kmsg.Response, error) {
 	var (
-		req  = kreq.(*kmsg.InitProducerIDRequest)
+		req  = creq.kreq.(*kmsg.InitProducerIDRequest)
 		resp = req.ResponseKind().(*kmsg.InitProducerIDResponse)
 	)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
 	if req.TransactionalID != nil {
+		if !c.allowed(creq, kmsg.ACLResourceTypeTransactionalId, *req.TransactionalID, kmsg.ACLOperationWrite) {
+			resp.ErrorCode = kerr.TransactionalIDAuthorizationFailed.Code
+			return resp, nil
+		}
 		resp.ErrorCode = kerr.UnknownServerError.Code
 		return resp, nil
 	}
 
+	if !c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLOperationIdempotentWrite) &&
+		!c.allowedAny(creq, kmsg.ACLResourceTypeTopic, kmsg.ACLOperationWrite) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+
 	pid := c.pids.create(nil)
 	resp.ProducerID = pid.id
 	resp.ProducerEpoch = pid.epoch
@@ -1,8 +1,6 @@
 package kfake
 
 import (
-	"sort"
-
 	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
@@ -13,7 +11,7 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 	req := kreq.(*kmsg.OffsetForLeaderEpochRequest)
 	resp := req.ResponseKind().(*kmsg.OffsetForLeaderEpochResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -66,55 +64,7 @@ func (c *Cluster) handleOffsetForLeaderEpoch(b *broker, kreq kmsg.Request) (kmsg
 			}
 
 			sp := donep(rt.Topic, rp.Partition, 0)
-
-			// If the user is requesting our current epoch, we return the HWM.
-			if rp.LeaderEpoch == pd.epoch {
-				sp.LeaderEpoch = pd.epoch
-				sp.EndOffset = pd.highWatermark
-				continue
-			}
-
-			// If our epoch was bumped before anything was
-			// produced, return the epoch and a start offset of 0.
-			if len(pd.batches) == 0 {
-				sp.LeaderEpoch = pd.epoch
-				sp.EndOffset = 0
-				if rp.LeaderEpoch > pd.epoch {
-					sp.LeaderEpoch = -1
-					sp.EndOffset = -1
-				}
-				continue
-			}
-
-			// What is the largest epoch after the requested epoch?
-			nextEpoch := rp.LeaderEpoch + 1
-			idx, _ := sort.Find(len(pd.batches), func(idx int) int {
-				batchEpoch := pd.batches[idx].epoch
-				switch {
-				case nextEpoch <= batchEpoch:
-					return -1
-				default:
-					return 1
-				}
-			})
-
-			// Requested epoch is not yet known: keep -1 returns.
-			if idx == len(pd.batches) {
-				sp.LeaderEpoch = -1
-				sp.EndOffset = -1
-				continue
-			}
-
-			// Next epoch is actually the first epoch: return the
-			// requested epoch and the LSO.
-			if idx == 0 {
-				sp.LeaderEpoch = rp.LeaderEpoch
-				sp.EndOffset = pd.logStartOffset
-				continue
-			}
-
-			sp.LeaderEpoch = pd.batches[idx-1].epoch
-			sp.EndOffset = pd.batches[idx].FirstOffset
+			sp.LeaderEpoch, sp.EndOffset = pd.epochEndOffset(rp.LeaderEpoch)
 		}
 	}
 	return resp, nil
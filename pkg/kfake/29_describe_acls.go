@@ -0,0 +1,65 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(29, 0, 3) }
+
+func (c *Cluster) handleDescribeACLs(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DescribeACLsRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeACLsResponse)
+
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if !c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLOperationDescribe) {
+		resp.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+		return resp, nil
+	}
+
+	f := aclFilter{
+		rtyp:      req.ResourceType,
+		name:      req.ResourceName,
+		pattern:   req.ResourcePatternType,
+		principal: req.Principal,
+		host:      req.Host,
+		op:        req.Operation,
+		perm:      req.PermissionType,
+	}
+	if req.Version == 0 {
+		f.pattern = kmsg.ACLResourcePatternTypeLiteral
+	}
+
+	type resource struct {
+		typ     kmsg.ACLResourceType
+		name    string
+		pattern kmsg.ACLResourcePatternType
+	}
+	ridx := make(map[resource]int)
+	for _, a := range c.ACLs() {
+		if !f.matches(&a) {
+			continue
+		}
+		r := resource{a.ResourceType, a.ResourceName, a.PatternType}
+		i, ok := ridx[r]
+		if !ok {
+			i = len(resp.Resources)
+			ridx[r] = i
+			sr := kmsg.NewDescribeACLsResponseResource()
+			sr.ResourceType = r.typ
+			sr.ResourceName = r.name
+			sr.ResourcePatternType = r.pattern
+			resp.Resources = append(resp.Resources, sr)
+		}
+		sa := kmsg.NewDescribeACLsResponseResourceACL()
+		sa.Principal = a.Principal
+		sa.Host = a.Host
+		sa.Operation = a.Operation
+		sa.PermissionType = a.Permission
+		resp.Resources[i].ACLs = append(resp.Resources[i].ACLs, sa)
+	}
+	return resp, nil
+}
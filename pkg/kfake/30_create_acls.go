@@ -0,0 +1,51 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(30, 0, 3) }
+
+func (c *Cluster) handleCreateACLs(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.CreateACLsRequest)
+	resp := req.ResponseKind().(*kmsg.CreateACLsResponse)
+
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	done := func(errCode int16) {
+		sr := kmsg.NewCreateACLsResponseResult()
+		sr.ErrorCode = errCode
+		resp.Results = append(resp.Results, sr)
+	}
+
+	allowed := c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLOperationAlter)
+	var creates []ACL
+	for _, rc := range req.Creations {
+		a := ACL{
+			Principal:    rc.Principal,
+			Host:         rc.Host,
+			ResourceType: rc.ResourceType,
+			ResourceName: rc.ResourceName,
+			PatternType:  rc.ResourcePatternType,
+			Operation:    rc.Operation,
+			Permission:   rc.PermissionType,
+		}
+		if req.Version == 0 {
+			a.PatternType = kmsg.ACLResourcePatternTypeLiteral
+		}
+		switch {
+		case !allowed:
+			done(kerr.ClusterAuthorizationFailed.Code)
+		case !validACL(&a):
+			done(kerr.InvalidRequest.Code)
+		default:
+			creates = append(creates, a)
+			done(0)
+		}
+	}
+	c.AddACLs(creates...)
+	return resp, nil
+}
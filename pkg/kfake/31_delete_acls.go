@@ -0,0 +1,74 @@
+package kfake
+
+import (
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func init() { regKey(31, 0, 3) }
+
+func (c *Cluster) handleDeleteACLs(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DeleteACLsRequest)
+	resp := req.ResponseKind().(*kmsg.DeleteACLsResponse)
+
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	if !c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLOperationAlter) {
+		for range req.Filters {
+			sr := kmsg.NewDeleteACLsResponseResult()
+			sr.ErrorCode = kerr.ClusterAuthorizationFailed.Code
+			resp.Results = append(resp.Results, sr)
+		}
+		return resp, nil
+	}
+
+	c.acls.mu.Lock()
+	defer c.acls.mu.Unlock()
+
+	// Every filter is matched against the ACLs that existed before the
+	// request, and an ACL matching multiple filters is returned for each.
+	deleted := make([]bool, len(c.acls.entries))
+	for _, rf := range req.Filters {
+		f := aclFilter{
+			rtyp:      rf.ResourceType,
+			name:      rf.ResourceName,
+			pattern:   rf.ResourcePatternType,
+			principal: rf.Principal,
+			host:      rf.Host,
+			op:        rf.Operation,
+			perm:      rf.PermissionType,
+		}
+		if req.Version == 0 {
+			f.pattern = kmsg.ACLResourcePatternTypeLiteral
+		}
+		sr := kmsg.NewDeleteACLsResponseResult()
+		for i := range c.acls.entries {
+			a := &c.acls.entries[i]
+			if !f.matches(a) {
+				continue
+			}
+			deleted[i] = true
+			sm := kmsg.NewDeleteACLsResponseResultMatchingACL()
+			sm.ResourceType = a.ResourceType
+			sm.ResourceName = a.ResourceName
+			sm.ResourcePatternType = a.PatternType
+			sm.Principal = a.Principal
+			sm.Host = a.Host
+			sm.Operation = a.Operation
+			sm.PermissionType = a.Permission
+			sr.MatchingACLs = append(sr.MatchingACLs, sm)
+		}
+		resp.Results = append(resp.Results, sr)
+	}
+
+	keep := c.acls.entries[:0]
+	for i, a := range c.acls.entries {
+		if !deleted[i] {
+			keep = append(keep, a)
+		}
+	}
+	c.acls.entries = keep
+	return resp, nil
+}
@@ -13,7 +13,7 @@ func (c *Cluster) handleAlterConfigs(b *broker, kreq kmsg.Request) (kmsg.Respons
 	req := kreq.(*kmsg.AlterConfigsRequest)
 	resp := req.ResponseKind().(*kmsg.AlterConfigsResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -13,7 +13,7 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 	req := creq.kreq.(*kmsg.SASLAuthenticateRequest)
 	resp := req.ResponseKind().(*kmsg.SASLAuthenticateResponse)
 
-	if err := checkReqVersion(req.Key(), req.Version); err != nil {
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
 		return nil, err
 	}
 
@@ -34,6 +34,7 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 			return nil, errors.New("invalid sasl")
 		}
 		creq.cc.saslStage = saslStageComplete
+		creq.cc.user = u
 
 	case saslStageAuthScram0_256:
 		c0, err := scramParseClient0(req.SASLAuthBytes)
@@ -51,6 +52,7 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 		resp.SASLAuthBytes = serverFirst
 		creq.cc.saslStage = saslStageAuthScram1
 		creq.cc.s0 = &s0
+		creq.cc.user = c0.user
 
 	case saslStageAuthScram0_512:
 		c0, err := scramParseClient0(req.SASLAuthBytes)
@@ -68,6 +70,7 @@ func (c *Cluster) handleSASLAuthenticate(creq *clientReq) (kmsg.Response, error)
 		resp.SASLAuthBytes = serverFirst
 		creq.cc.saslStage = saslStageAuthScram1
 		creq.cc.s0 = &s0
+		creq.cc.user = c0.user
 
 	case saslStageAuthScram1:
 		serverFinal, err := creq.cc.s0.serverFinal(req.SASLAuthBytes)
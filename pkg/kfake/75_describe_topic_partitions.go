@@ -0,0 +1,103 @@
+package kfake
+
+import (
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Behavior:
+//
+// * Topics are returned sorted by name, and partitions in order
+// * A response includes at most ResponsePartitionLimit partitions (capped at
+//   2000, like Kafka), and NextCursor is set if more partitions remain
+// * Errored topics do not count against the limit
+
+func init() { regKey(75, 0, 0) }
+
+const maxDescribeTopicPartitions = 2000
+
+func (c *Cluster) handleDescribeTopicPartitions(creq *clientReq) (kmsg.Response, error) {
+	req := creq.kreq.(*kmsg.DescribeTopicPartitionsRequest)
+	resp := req.ResponseKind().(*kmsg.DescribeTopicPartitionsResponse)
+
+	if err := c.checkReqVersion(req.Key(), req.Version); err != nil {
+		return nil, err
+	}
+
+	donet := func(t string, errCode int16) *kmsg.DescribeTopicPartitionsResponseTopic {
+		st := kmsg.NewDescribeTopicPartitionsResponseTopic()
+		st.Topic = kmsg.StringPtr(t)
+		st.TopicID = c.data.t2id[t]
+		st.ErrorCode = int32(errCode)
+		resp.Topics = append(resp.Topics, st)
+		return &resp.Topics[len(resp.Topics)-1]
+	}
+
+	var topics []string
+	if len(req.Topics) == 0 {
+		for t := range c.data.tps {
+			if c.allowed(creq, kmsg.ACLResourceTypeTopic, t, kmsg.ACLOperationDescribe) {
+				topics = append(topics, t)
+			}
+		}
+	} else {
+		seen := make(map[string]struct{})
+		for _, rt := range req.Topics {
+			if _, ok := seen[rt.Topic]; !ok {
+				seen[rt.Topic] = struct{}{}
+				topics = append(topics, rt.Topic)
+			}
+		}
+	}
+	sort.Strings(topics)
+
+	limit := int(req.ResponsePartitionLimit)
+	if limit <= 0 || limit > maxDescribeTopicPartitions {
+		limit = maxDescribeTopicPartitions
+	}
+
+	var n int
+	for _, t := range topics {
+		var start int32
+		if cur := req.Cursor; cur != nil {
+			if t < cur.Topic {
+				continue
+			}
+			if t == cur.Topic {
+				start = max(cur.Partition, 0)
+			}
+		}
+		if !c.allowed(creq, kmsg.ACLResourceTypeTopic, t, kmsg.ACLOperationDescribe) {
+			donet(t, kerr.TopicAuthorizationFailed.Code)
+			continue
+		}
+		ps, ok := c.data.tps.gett(t)
+		if !ok {
+			donet(t, kerr.UnknownTopicOrPartition.Code)
+			continue
+		}
+		if n == limit {
+			resp.NextCursor = &kmsg.DescribeTopicPartitionsResponseNextCursor{Topic: t, Partition: start}
+			break
+		}
+		st := donet(t, 0)
+		for p := start; p < int32(len(ps)); p++ {
+			if n == limit {
+				resp.NextCursor = &kmsg.DescribeTopicPartitionsResponseNextCursor{Topic: t, Partition: p}
+				return resp, nil
+			}
+			pd := ps[p]
+			sp := kmsg.NewDescribeTopicPartitionsResponseTopicPartition()
+			sp.Partition = p
+			sp.LeaderID = pd.leader.node
+			sp.LeaderEpoch = pd.epoch
+			sp.Replicas = c.replicas(t, pd)
+			sp.ISR = sp.Replicas
+			st.Partitions = append(st.Partitions, sp)
+			n++
+		}
+	}
+	return resp, nil
+}
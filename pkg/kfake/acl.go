@@ -0,0 +1,246 @@
+package kfake
+
+import (
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ACLs are only enforced if the cluster is created with EnableACLs, and are
+// evaluated similar to Kafka's StandardAuthorizer with
+// allow.everyone.if.no.acl.found=false:
+//
+//   - superusers are allowed everything
+//   - any matching DENY denies the operation
+//   - otherwise, a matching ALLOW for the operation (or ALL) allows it;
+//     DESCRIBE is implied by READ, WRITE, DELETE, and ALTER, and
+//     DESCRIBE_CONFIGS is implied by ALTER_CONFIGS
+//   - anything else is denied
+//
+// Group handlers authorize from the group's manage goroutine, so ACLs are
+// guarded by a mutex rather than only being accessed in the cluster run loop.
+
+const anonymousPrincipal = "User:ANONYMOUS"
+
+type (
+	acls struct {
+		mu         sync.RWMutex
+		superusers map[string]struct{} // principals
+		entries    []ACL
+	}
+
+	// ACL is an access control entry. The fields correspond to the fields
+	// of a CreateACLs request: Principal is of the form "User:name" (or
+	// "User:*" for all users), Host is an IP address (or "*" for all hosts),
+	// and PatternType is either literal or prefixed. A literal ResourceName
+	// of "*" matches all resources of the type.
+	ACL struct {
+		Principal    string
+		Host         string
+		ResourceType kmsg.ACLResourceType
+		ResourceName string
+		PatternType  kmsg.ACLResourcePatternType
+		Operation    kmsg.ACLOperation
+		Permission   kmsg.ACLPermissionType
+	}
+)
+
+// EnableACLs enables authorization: every request is authorized against the
+// cluster's ACLs, and unauthorized requests fail with the appropriate
+// authorization error (TOPIC_AUTHORIZATION_FAILED, GROUP_AUTHORIZATION_FAILED,
+// CLUSTER_AUTHORIZATION_FAILED, ...). Responses listing all topics or groups
+// only include the topics and groups the client is allowed to describe.
+//
+// Users seeded with [Superuser] (or the default "admin" user if SASL is
+// enabled) are superusers and are allowed everything. Clients that do not
+// authenticate with SASL use the principal "User:ANONYMOUS". ACLs can be
+// managed with CreateACLs, DescribeACLs, and DeleteACLs requests, which
+// require ALTER and DESCRIBE on the cluster, or directly with
+// [Cluster.AddACLs].
+//
+// The following requests are authorized: Produce (WRITE on the topic), Fetch
+// (READ on the topic), ListOffsets (DESCRIBE on the topic), Metadata and
+// DescribeTopicPartitions (DESCRIBE on the topic, and CREATE on the cluster
+// or topic for auto creation), CreateTopics
+// (CREATE on the cluster or topic), DeleteTopics (DELETE on the topic),
+// InitProducerID (WRITE on the transactional ID if one is used, otherwise
+// IDEMPOTENT_WRITE on the cluster or WRITE on any topic),
+// group requests (READ on the group for joining and committing, DESCRIBE for
+// OffsetFetch, DescribeGroups, and ListGroups, DELETE for DeleteGroups and
+// OffsetDelete), and the ACL requests themselves. Other requests are not
+// authorized.
+func EnableACLs() Opt {
+	return opt{func(cfg *cfg) { cfg.enableACLs = true }}
+}
+
+// AddACLs adds ACLs to the cluster, bypassing authorization. This can be used
+// to set up permissions before clients connect. ACLs are only enforced if the
+// cluster was created with [EnableACLs].
+func (c *Cluster) AddACLs(acls ...ACL) {
+	c.acls.mu.Lock()
+	defer c.acls.mu.Unlock()
+	c.acls.entries = append(c.acls.entries, acls...)
+}
+
+// ACLs returns all ACLs in the cluster.
+func (c *Cluster) ACLs() []ACL {
+	c.acls.mu.RLock()
+	defer c.acls.mu.RUnlock()
+	return append([]ACL(nil), c.acls.entries...)
+}
+
+func (cc *clientConn) principal() string {
+	if cc.user == "" {
+		return anonymousPrincipal
+	}
+	return "User:" + cc.user
+}
+
+func (cc *clientConn) host() string {
+	h, _, err := net.SplitHostPort(cc.conn.RemoteAddr().String())
+	if err != nil {
+		return cc.conn.RemoteAddr().String()
+	}
+	return h
+}
+
+// allowed returns whether the client that sent creq is allowed to perform op
+// on the given resource. If ACLs are not enabled, everything is allowed.
+func (c *Cluster) allowed(creq *clientReq, rtyp kmsg.ACLResourceType, name string, op kmsg.ACLOperation) bool {
+	if !c.cfg.enableACLs {
+		return true
+	}
+	principal, host := creq.cc.principal(), creq.cc.host()
+
+	c.acls.mu.RLock()
+	defer c.acls.mu.RUnlock()
+	if _, ok := c.acls.superusers[principal]; ok {
+		return true
+	}
+
+	var allow bool
+	for _, a := range c.acls.entries {
+		if !a.matchesResource(rtyp, name) ||
+			a.Principal != principal && a.Principal != "User:*" ||
+			a.Host != host && a.Host != "*" {
+			continue
+		}
+		switch a.Permission {
+		case kmsg.ACLPermissionTypeDeny:
+			if a.Operation == op || a.Operation == kmsg.ACLOperationAll {
+				return false
+			}
+		case kmsg.ACLPermissionTypeAllow:
+			allow = allow || a.implies(op)
+		}
+	}
+	return allow
+}
+
+// allowedAny returns whether the client is allowed to perform op on any
+// resource of the given type, which is used for idempotent producing.
+func (c *Cluster) allowedAny(creq *clientReq, rtyp kmsg.ACLResourceType, op kmsg.ACLOperation) bool {
+	if c.allowed(creq, rtyp, "", op) { // disabled, superuser, or wildcard
+		return true
+	}
+	c.acls.mu.RLock()
+	var names []string
+	for _, a := range c.acls.entries {
+		if a.ResourceType == rtyp && a.Permission == kmsg.ACLPermissionTypeAllow {
+			names = append(names, a.ResourceName)
+		}
+	}
+	c.acls.mu.RUnlock()
+	for _, name := range names {
+		if c.allowed(creq, rtyp, name, op) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *ACL) matchesResource(rtyp kmsg.ACLResourceType, name string) bool {
+	if a.ResourceType != rtyp {
+		return false
+	}
+	switch a.PatternType {
+	case kmsg.ACLResourcePatternTypeLiteral:
+		return a.ResourceName == name || a.ResourceName == "*"
+	case kmsg.ACLResourcePatternTypePrefixed:
+		return strings.HasPrefix(name, a.ResourceName)
+	default:
+		return false
+	}
+}
+
+func (a *ACL) implies(op kmsg.ACLOperation) bool {
+	switch a.Operation {
+	case op, kmsg.ACLOperationAll:
+		return true
+	case kmsg.ACLOperationRead, kmsg.ACLOperationWrite, kmsg.ACLOperationDelete, kmsg.ACLOperationAlter:
+		return op == kmsg.ACLOperationDescribe
+	case kmsg.ACLOperationAlterConfigs:
+		return op == kmsg.ACLOperationDescribeConfigs
+	default:
+		return false
+	}
+}
+
+// aclFilter is the common filter of DescribeACLs and DeleteACLs requests.
+type aclFilter struct {
+	rtyp      kmsg.ACLResourceType
+	name      *string
+	pattern   kmsg.ACLResourcePatternType
+	principal *string
+	host      *string
+	op        kmsg.ACLOperation
+	perm      kmsg.ACLPermissionType
+}
+
+func (f *aclFilter) matches(a *ACL) bool {
+	if f.rtyp != kmsg.ACLResourceTypeAny && f.rtyp != a.ResourceType {
+		return false
+	}
+	switch f.pattern {
+	case kmsg.ACLResourcePatternTypeAny:
+		if f.name != nil && *f.name != a.ResourceName {
+			return false
+		}
+	case kmsg.ACLResourcePatternTypeMatch:
+		if f.name != nil && !a.matchesResource(a.ResourceType, *f.name) {
+			return false
+		}
+	default:
+		if f.pattern != a.PatternType || f.name != nil && *f.name != a.ResourceName {
+			return false
+		}
+	}
+	return (f.principal == nil || *f.principal == a.Principal) &&
+		(f.host == nil || *f.host == a.Host) &&
+		(f.op == kmsg.ACLOperationAny || f.op == a.Operation) &&
+		(f.perm == kmsg.ACLPermissionTypeAny || f.perm == a.Permission)
+}
+
+// validACL returns whether an ACL can be created: all fields must be
+// concrete (not ANY, MATCH, or UNKNOWN) and the principal must have a type.
+func validACL(a *ACL) bool {
+	switch {
+	case a.ResourceType <= kmsg.ACLResourceTypeAny,
+		a.PatternType != kmsg.ACLResourcePatternTypeLiteral && a.PatternType != kmsg.ACLResourcePatternTypePrefixed,
+		a.Operation <= kmsg.ACLOperationAny,
+		a.Permission != kmsg.ACLPermissionTypeAllow && a.Permission != kmsg.ACLPermissionTypeDeny,
+		!strings.Contains(a.Principal, ":"),
+		a.Host == "":
+		return false
+	}
+	return true
+}
+
+// allowedCreate returns whether the client can create the given topic, which
+// requires CREATE on either the cluster or the topic.
+func (c *Cluster) allowedCreate(creq *clientReq, topic string) bool {
+	return c.allowed(creq, kmsg.ACLResourceTypeCluster, "kafka-cluster", kmsg.ACLOperationCreate) ||
+		c.allowed(creq, kmsg.ACLResourceTypeTopic, topic, kmsg.ACLOperationCreate)
+}
@@ -0,0 +1,324 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+)
+
+func TestACLs(t *testing.T) {
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, "allowed-topic", "denied-topic"),
+		EnableACLs(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.RecordRetries(0),
+		kgo.UnknownTopicRetries(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	adm := kadm.NewClient(cl)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// With no ACLs, we cannot manage ACLs.
+	create := kadm.NewACLs().
+		Allow(anonymousPrincipal).
+		Topics("allowed").
+		ResourcePatternType(kadm.ACLPatternPrefixed).
+		Operations(kadm.OpWrite, kadm.OpRead)
+	results, err := adm.CreateACLs(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d create results, exp 2", len(results))
+	}
+	for _, r := range results {
+		if !errors.Is(r.Err, kerr.ClusterAuthorizationFailed) {
+			t.Fatalf("got create err %v, exp cluster authorization failed", r.Err)
+		}
+	}
+
+	c.AddACLs(ACL{
+		Principal:    anonymousPrincipal,
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeCluster,
+		ResourceName: "kafka-cluster",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationAlter,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+	results, err = adm.CreateACLs(ctx, create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			t.Fatalf("unable to create ACL: %v", r.Err)
+		}
+	}
+
+	// DescribeACLs requires DESCRIBE, which ALTER implies.
+	described, err := adm.DescribeACLs(ctx, kadm.NewACLs().
+		Allow().
+		AllowHosts().
+		AnyResource().
+		ResourcePatternType(kadm.ACLPatternAny).
+		Operations(kadm.OpAny))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(described) != 1 || described[0].Err != nil || len(described[0].Described) != 3 {
+		t.Fatalf("got described %v, exp three ACLs", described)
+	}
+
+	// The anonymous user can now write to and see topics prefixed with
+	// "allowed", but nothing else.
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "allowed-topic"}).FirstErr(); err != nil {
+		t.Errorf("unable to produce to allowed topic: %v", err)
+	}
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "denied-topic"}).FirstErr(); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("got produce err %v, exp topic authorization failed", err)
+	}
+	topics, err := adm.ListTopics(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := topics.Names(); !slices.Equal(names, []string{"allowed-topic"}) {
+		t.Errorf("got listed topics %v, exp only allowed-topic", names)
+	}
+
+	// Groups are authorized as well.
+	if _, err := adm.FetchOffsets(ctx, "group"); !errors.Is(err, kerr.GroupAuthorizationFailed) {
+		t.Errorf("got fetch offsets err %v, exp group authorization failed", err)
+	}
+	c.AddACLs(ACL{
+		Principal:    "User:*",
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeGroup,
+		ResourceName: "*",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationRead,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+	if _, err := adm.FetchOffsets(ctx, "group"); err != nil && !errors.Is(err, kerr.GroupIDNotFound) {
+		t.Errorf("unable to fetch offsets after allowing groups: %v", err)
+	}
+
+	// A DENY takes precedence over an ALLOW, and deleting ACLs removes
+	// them.
+	c.AddACLs(ACL{
+		Principal:    anonymousPrincipal,
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeTopic,
+		ResourceName: "allowed-topic",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationWrite,
+		Permission:   kmsg.ACLPermissionTypeDeny,
+	})
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "allowed-topic"}).FirstErr(); !errors.Is(err, kerr.TopicAuthorizationFailed) {
+		t.Errorf("got produce err %v after deny, exp topic authorization failed", err)
+	}
+	deleted, err := adm.DeleteACLs(ctx, kadm.NewACLs().
+		Deny(anonymousPrincipal).
+		DenyHosts("*").
+		Topics("allowed-topic").
+		ResourcePatternType(kadm.ACLPatternLiteral).
+		Operations(kadm.OpWrite))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].Err != nil || len(deleted[0].Deleted) != 1 {
+		t.Fatalf("got deleted %v, exp one deleted ACL", deleted)
+	}
+	if err := cl.ProduceSync(ctx, &kgo.Record{Topic: "allowed-topic"}).FirstErr(); err != nil {
+		t.Errorf("unable to produce after deleting deny: %v", err)
+	}
+	if n := len(c.ACLs()); n != 4 {
+		t.Errorf("got %d ACLs, exp 4", n)
+	}
+}
+
+func TestACLSuperuser(t *testing.T) {
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, "foo"),
+		EnableSASL(),
+		Superuser(saslPlain, "root", "pass"),
+		EnableACLs(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	req := kmsg.NewPtrMetadataRequest()
+	rt := kmsg.NewMetadataRequestTopic()
+	rt.Topic = kmsg.StringPtr("foo")
+	req.Topics = append(req.Topics, rt)
+
+	// Only seeded users are superusers; users added later are not.
+	c.admin(func() { c.sasls.plain["nobody"] = "pass" })
+	c.AddACLs(ACL{
+		Principal:    "User:nobody",
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeTopic,
+		ResourceName: "bar",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationAll,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+
+	for _, test := range []struct {
+		user string
+		exp  int16
+	}{
+		{"root", 0},
+		{"nobody", kerr.TopicAuthorizationFailed.Code},
+	} {
+		cl, err := kgo.NewClient(
+			kgo.SeedBrokers(c.ListenAddrs()...),
+			kgo.SASL(plain.Auth{User: test.user, Pass: "pass"}.AsMechanism()),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := req.RequestWith(ctx, cl)
+		cl.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := resp.Topics[0].ErrorCode; got != test.exp {
+			t.Errorf("user %s: got metadata error code %d, exp %d", test.user, got, test.exp)
+		}
+	}
+}
+
+func TestACLOffsetsAndProducerIDs(t *testing.T) {
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, "foo"),
+		EnableACLs(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	br := cl.Broker(0) // the only broker
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// ListOffsets requires only DESCRIBE on the topic, whereas Fetch
+	// requires READ.
+	listOffsets := func() int16 {
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = "foo"
+		rp := kmsg.NewListOffsetsRequestTopicPartition()
+		rp.Timestamp = -1
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	fetch := func() int16 {
+		req := kmsg.NewPtrFetchRequest()
+		req.Version = 11 // topic names rather than IDs
+		rt := kmsg.NewFetchRequestTopic()
+		rt.Topic = "foo"
+		rt.Partitions = append(rt.Partitions, kmsg.NewFetchRequestTopicPartition())
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.Topics[0].Partitions[0].ErrorCode
+	}
+	if got := listOffsets(); got != kerr.TopicAuthorizationFailed.Code {
+		t.Errorf("got list offsets error code %d without ACLs, exp topic authorization failed", got)
+	}
+	c.AddACLs(ACL{
+		Principal:    anonymousPrincipal,
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeTopic,
+		ResourceName: "foo",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationDescribe,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+	if got := listOffsets(); got != 0 {
+		t.Errorf("got list offsets error code %d with DESCRIBE, exp 0", got)
+	}
+	if got := fetch(); got != kerr.TopicAuthorizationFailed.Code {
+		t.Errorf("got fetch error code %d with only DESCRIBE, exp topic authorization failed", got)
+	}
+
+	// InitProducerID with a transactional ID requires WRITE on the
+	// transactional ID; topic WRITE is not enough.
+	c.AddACLs(ACL{
+		Principal:    anonymousPrincipal,
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeTopic,
+		ResourceName: "foo",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationWrite,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+	initPID := func() int16 {
+		req := kmsg.NewPtrInitProducerIDRequest()
+		req.TransactionalID = kmsg.StringPtr("txn")
+		req.TransactionTimeoutMillis = 10000
+		resp, err := req.RequestWith(ctx, br)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.ErrorCode
+	}
+	if got := initPID(); got != kerr.TransactionalIDAuthorizationFailed.Code {
+		t.Errorf("got init producer ID error code %d, exp transactional ID authorization failed", got)
+	}
+	c.AddACLs(ACL{
+		Principal:    anonymousPrincipal,
+		Host:         "*",
+		ResourceType: kmsg.ACLResourceTypeTransactionalId,
+		ResourceName: "txn",
+		PatternType:  kmsg.ACLResourcePatternTypeLiteral,
+		Operation:    kmsg.ACLOperationWrite,
+		Permission:   kmsg.ACLPermissionTypeAllow,
+	})
+	if got := initPID(); got == kerr.TransactionalIDAuthorizationFailed.Code {
+		t.Error("got transactional ID authorization failed after allowing WRITE on the transactional ID")
+	}
+}
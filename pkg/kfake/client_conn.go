@@ -19,6 +19,7 @@ type (
 
 		saslStage saslStage
 		s0        *scramServer0
+		user      string // SASL user; set once the client begins authenticating
 	}
 
 	clientReq struct {
@@ -47,8 +47,15 @@ type (
 		pids   pids
 		groups groups
 		sasls  sasls
+		acls   acls
 		bcfgs  map[string]*string
 
+		apiVersions       map[int16]kmsg.ApiVersionsResponseApiKey
+		apiVersionsSorted []kmsg.ApiVersionsResponseApiKey
+
+		quotas    quotas
+		throttles map[string]map[int16]time.Duration // client ID => key => fixed throttle
+
 		die  chan struct{}
 		dead atomic.Bool
 	}
@@ -131,6 +138,7 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 	}
 	c.data.c = c
 	c.groups.c = c
+	c.initApiVersions()
 	var err error
 	defer func() {
 		if err != nil {
@@ -138,7 +146,9 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 		}
 	}()
 
+	c.acls.superusers = make(map[string]struct{})
 	for mu, p := range cfg.sasls {
+		c.acls.superusers["User:"+mu.u] = struct{}{}
 		switch mu.m {
 		case saslPlain:
 			if c.sasls.plain == nil {
@@ -165,6 +175,7 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 		c.sasls.scram256 = map[string]scramAuth{
 			"admin": newScramAuth(saslScram256, "admin"),
 		}
+		c.acls.superusers["User:admin"] = struct{}{}
 	}
 
 	for i := 0; i < cfg.nbrokers; i++ {
@@ -201,6 +212,9 @@ func NewCluster(opts ...Opt) (*Cluster, error) {
 	for t, p := range seedTopics {
 		c.data.mkt(t, int(p), -1, nil)
 	}
+	for _, q := range cfg.quotas {
+		c.quotas.set(q.clientID, q.produce, q.fetch)
+	}
 
 	go c.run()
 
@@ -355,13 +369,13 @@ outer:
 		kreq = creq.kreq
 		switch k := kmsg.Key(kreq.Key()); k {
 		case kmsg.Produce:
-			kresp, err = c.handleProduce(creq.cc.b, kreq)
+			kresp, err = c.handleProduce(creq)
 		case kmsg.Fetch:
 			kresp, err = c.handleFetch(creq, w)
 		case kmsg.ListOffsets:
-			kresp, err = c.handleListOffsets(creq.cc.b, kreq)
+			kresp, err = c.handleListOffsets(creq)
 		case kmsg.Metadata:
-			kresp, err = c.handleMetadata(kreq)
+			kresp, err = c.handleMetadata(creq)
 		case kmsg.OffsetCommit:
 			kresp, err = c.handleOffsetCommit(creq)
 		case kmsg.OffsetFetch:
@@ -385,15 +399,21 @@ outer:
 		case kmsg.ApiVersions:
 			kresp, err = c.handleApiVersions(kreq)
 		case kmsg.CreateTopics:
-			kresp, err = c.handleCreateTopics(creq.cc.b, kreq)
+			kresp, err = c.handleCreateTopics(creq)
 		case kmsg.DeleteTopics:
-			kresp, err = c.handleDeleteTopics(creq.cc.b, kreq)
+			kresp, err = c.handleDeleteTopics(creq)
 		case kmsg.DeleteRecords:
 			kresp, err = c.handleDeleteRecords(creq.cc.b, kreq)
 		case kmsg.InitProducerID:
-			kresp, err = c.handleInitProducerID(kreq)
+			kresp, err = c.handleInitProducerID(creq)
 		case kmsg.OffsetForLeaderEpoch:
 			kresp, err = c.handleOffsetForLeaderEpoch(creq.cc.b, kreq)
+		case kmsg.DescribeACLs:
+			kresp, err = c.handleDescribeACLs(creq)
+		case kmsg.CreateACLs:
+			kresp, err = c.handleCreateACLs(creq)
+		case kmsg.DeleteACLs:
+			kresp, err = c.handleDeleteACLs(creq)
 		case kmsg.DescribeConfigs:
 			kresp, err = c.handleDescribeConfigs(creq.cc.b, kreq)
 		case kmsg.AlterConfigs:
@@ -412,6 +432,8 @@ outer:
 			kresp, err = c.handleIncrementalAlterConfigs(creq.cc.b, kreq)
 		case kmsg.OffsetDelete:
 			kresp, err = c.handleOffsetDelete(creq)
+		case kmsg.DescribeTopicPartitions:
+			kresp, err = c.handleDescribeTopicPartitions(creq)
 		case kmsg.DescribeUserSCRAMCredentials:
 			kresp, err = c.handleDescribeUserSCRAMCredentials(kreq)
 		case kmsg.AlterUserSCRAMCredentials:
@@ -431,6 +453,9 @@ outer:
 		if kresp == nil && err == nil { // produce request with no acks, or otherwise hijacked request (group, sleep)
 			continue
 		}
+		if kresp != nil && !handled {
+			c.applyThrottle(creq, kresp)
+		}
 
 		select {
 		case creq.cc.respCh <- clientResp{kresp: kresp, corr: creq.corr, err: err, seq: creq.seq}:
@@ -961,11 +986,64 @@ func (c *Cluster) MoveTopicPartition(topic string, partition, nodeID int32) erro
 			return
 		}
 		pd.leader = br
-		pd.epoch++
+		pd.bumpEpoch()
 	})
 	return err
 }
 
+// BumpLeaderEpoch simulates a leader election that keeps the current leader:
+// the partition's leader epoch is bumped, fencing clients that are using the
+// prior epoch. This returns the new epoch, or an error if the topic or
+// partition does not exist.
+func (c *Cluster) BumpLeaderEpoch(topic string, partition int32) (int32, error) {
+	var (
+		epoch int32
+		err   error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = errors.New("topic/partition not found")
+			return
+		}
+		pd.bumpEpoch()
+		epoch = pd.epoch
+	})
+	return epoch, err
+}
+
+// TruncatePartition simulates an unclean leader election, where the new leader
+// is missing the end of the log: all records at and after offset are
+// discarded and the partition's leader epoch is bumped. Like Kafka, the log is
+// truncated on batch boundaries, so if offset is within a batch, the entire
+// batch is discarded.
+//
+// Clients that consumed discarded records detect the truncation with
+// OffsetForLeaderEpoch requests. This can be used to test data loss handling
+// and offset reset policies. This returns the new end offset of the log, or
+// an error if the topic or partition does not exist or offset is before the
+// log start offset.
+func (c *Cluster) TruncatePartition(topic string, partition int32, offset int64) (int64, error) {
+	var (
+		end int64
+		err error
+	)
+	c.admin(func() {
+		pd, ok := c.data.tps.getp(topic, partition)
+		if !ok {
+			err = errors.New("topic/partition not found")
+			return
+		}
+		if offset < pd.logStartOffset {
+			err = fmt.Errorf("offset %d is before the log start offset %d", offset, pd.logStartOffset)
+			return
+		}
+		end = pd.truncate(offset)
+		pd.bumpEpoch()
+	})
+	return end, err
+}
+
 // CoordinatorFor returns the node ID of the group or transaction coordinator
 // for the given key.
 func (c *Cluster) CoordinatorFor(key string) int32 {
@@ -1096,7 +1174,7 @@ func (c *Cluster) shufflePartitionsLocked() {
 			leader = c.bs[rand.Intn(len(c.bs))]
 		}
 		p.leader = leader
-		p.epoch++
+		p.bumpEpoch()
 	})
 }
 
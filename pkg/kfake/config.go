@@ -3,7 +3,10 @@ package kfake
 import (
 	"crypto/tls"
 	"net"
+	"strconv"
 	"time"
+
+	"github.com/twmb/franz-go/pkg/kversion"
 )
 
 // Opt is an option to configure a client.
@@ -20,6 +23,11 @@ type seedTopics struct {
 	ts []string
 }
 
+type cfgQuota struct {
+	clientID       string
+	produce, fetch int64
+}
+
 type cfg struct {
 	nbrokers        int
 	ports           []int
@@ -36,9 +44,16 @@ type cfg struct {
 	sasls      map[struct{ m, u string }]string // cleared after client initialization
 	tls        *tls.Config
 
+	enableACLs bool
+
 	listenFn func(network, address string) (net.Listener, error)
 
 	sleepOutOfOrder bool
+
+	quotas []cfgQuota
+
+	maxVersions *kversion.Versions
+	keyVersions map[int16]struct{ min, max int16 }
 }
 
 // NumBrokers sets the number of brokers to start in the fake cluster.
@@ -124,6 +139,24 @@ func SeedTopics(partitions int32, ts ...string) Opt {
 	return opt{func(cfg *cfg) { cfg.seedTopics = append(cfg.seedTopics, seedTopics{partitions, ts}) }}
 }
 
+// SeedNumberedTopics seeds the cluster with n topics named prefix followed by
+// the topic number (i.e., "foo-0" through "foo-999" for prefix "foo-" and n
+// 1000), each with the given number of partitions. Like SeedTopics, a
+// non-positive number of partitions uses [DefaultNumPartitions].
+//
+// Partitions do not store anything until records are produced to them, so
+// this can be used to cheaply simulate very large clusters with hundreds of
+// thousands of partitions to test metadata handling and regex consuming.
+func SeedNumberedTopics(partitions int32, prefix string, n int) Opt {
+	return opt{func(cfg *cfg) {
+		ts := make([]string, n)
+		for i := range ts {
+			ts[i] = prefix + strconv.Itoa(i)
+		}
+		cfg.seedTopics = append(cfg.seedTopics, seedTopics{partitions, ts})
+	}}
+}
+
 // SleepOutOfOrder allows functions to be handled out of order when control
 // functions are sleeping. The functions are be handled internally out of
 // order, but responses still wait for the sleeping requests to finish. This
@@ -132,3 +165,28 @@ func SeedTopics(partitions int32, ts ...string) Opt {
 func SleepOutOfOrder() Opt {
 	return opt{func(cfg *cfg) { cfg.sleepOutOfOrder = true }}
 }
+
+// MaxVersions caps the API versions the cluster advertises and accepts to the
+// versions in v, allowing clients to be tested against older broker behavior
+// (i.e., kversion.V2_8_0()). Keys that do not exist in v are not advertised.
+//
+// The cluster only handles the request versions it implements: if v's max
+// version for a key is below the minimum version the cluster implements, the
+// key is not advertised. ApiVersions itself is always advertised.
+func MaxVersions(v *kversion.Versions) Opt {
+	return opt{func(cfg *cfg) { cfg.maxVersions = v }}
+}
+
+// KeyVersions limits the advertised and accepted versions of an individual
+// request key to min and max, inclusive. This can be combined with
+// [MaxVersions], and can be provided multiple times for different keys. Like
+// MaxVersions, the range is further limited to what the cluster implements; if
+// the resulting range is empty, the key is not advertised.
+func KeyVersions(key, min, max int16) Opt {
+	return opt{func(cfg *cfg) {
+		if cfg.keyVersions == nil {
+			cfg.keyVersions = make(map[int16]struct{ min, max int16 })
+		}
+		cfg.keyVersions[key] = struct{ min, max int16 }{min, max}
+	}}
+}
@@ -1,7 +1,6 @@
 package kfake
 
 import (
-	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
 	"math/rand"
@@ -42,6 +41,7 @@ type (
 		lastStableOffset int64
 		logStartOffset   int64
 		epoch            int32 // current epoch
+		epochs           []epochStart
 		maxTimestamp     int64 // current max timestamp in all batches
 		nbytes           int64
 
@@ -50,13 +50,21 @@ type (
 		leader    *broker
 		followers followers
 
-		watch map[*watchFetch]struct{}
+		watch map[*watchFetch]struct{} // lazily created when a fetch waits
 
 		createdAt time.Time
 	}
 
 	followers []int32
 
+	// epochStart is where a leader epoch begins in a partition's log,
+	// similar to Kafka's leader epoch cache. Epoch 0 implicitly starts at
+	// offset 0, so a partition only tracks epochs once its epoch is bumped.
+	epochStart struct {
+		epoch int32
+		start int64
+	}
+
 	partBatch struct {
 		kmsg.RecordBatch
 		nbytes int
@@ -64,13 +72,12 @@ type (
 
 		// For list offsets, we may need to return the first offset
 		// after a given requested timestamp. Client provided
-		// timestamps gan go forwards and backwards. We answer list
-		// offsets with a binary search: even if this batch has a small
-		// timestamp, this is produced _after_ a potentially higher
-		// timestamp, so it is after it in the list offset response.
-		//
-		// When we drop the earlier timestamp, we update all following
-		// firstMaxTimestamps that match the dropped timestamp.
+		// timestamps can go forwards and backwards. We answer list
+		// offsets with a binary search on the max timestamp of this
+		// and all earlier batches, which is monotonic: the first batch
+		// whose running max is at or after the requested timestamp is
+		// the first batch that can contain a record at or after the
+		// timestamp.
 		maxEarlierTimestamp int64
 	}
 )
@@ -92,9 +99,8 @@ func (d *data) mkt(t string, nparts, nreplicas int, configs map[string]*string)
 	}
 	var id uuid
 	for {
-		sha := sha256.Sum256([]byte(strconv.Itoa(int(time.Now().UnixNano()))))
-		copy(id[:], sha[:])
-		if _, exists := d.id2t[id]; !exists {
+		id = randUUID()
+		if _, exists := d.id2t[id]; !exists && id != noID {
 			break
 		}
 	}
@@ -120,6 +126,17 @@ func (d *data) mkt(t string, nparts, nreplicas int, configs map[string]*string)
 	}
 }
 
+// replicas returns the replicas of a partition, starting with its leader.
+func (c *Cluster) replicas(t string, pd *partData) []int32 {
+	nreplicas := min(c.data.treplicas[t], len(c.bs))
+	replicas := make([]int32, 0, nreplicas)
+	for i := 0; i < nreplicas; i++ {
+		idx := (pd.leader.bsIdx + i) % len(c.bs)
+		replicas = append(replicas, c.bs[idx].node)
+	}
+	return replicas
+}
+
 func (c *Cluster) noLeader() *broker {
 	return &broker{
 		c:    c,
@@ -133,14 +150,13 @@ func (c *Cluster) newPartData(p int32) func() *partData {
 			p:         p,
 			dir:       defLogDir,
 			leader:    c.bs[rand.Intn(len(c.bs))],
-			watch:     make(map[*watchFetch]struct{}),
 			createdAt: time.Now(),
 		}
 	}
 }
 
 func (pd *partData) pushBatch(nbytes int, b kmsg.RecordBatch) {
-	maxEarlierTimestamp := b.FirstTimestamp
+	maxEarlierTimestamp := b.MaxTimestamp
 	if maxEarlierTimestamp < pd.maxTimestamp {
 		maxEarlierTimestamp = pd.maxTimestamp
 	} else {
@@ -157,6 +173,63 @@ func (pd *partData) pushBatch(nbytes int, b kmsg.RecordBatch) {
 	}
 }
 
+// bumpEpoch increments the partition's leader epoch, which begins at the
+// current end of the log.
+func (pd *partData) bumpEpoch() {
+	pd.epoch++
+	pd.epochs = append(pd.epochs, epochStart{pd.epoch, pd.highWatermark})
+}
+
+// epochEndOffset returns the largest epoch at or before the requested epoch
+// and the offset that the requested epoch ends at: the start of the next
+// epoch, or the end of the log if the requested epoch is the current epoch.
+// If the requested epoch is unknown, this returns -1, -1.
+func (pd *partData) epochEndOffset(epoch int32) (int32, int64) {
+	if epoch < 0 || epoch > pd.epoch {
+		return -1, -1
+	}
+	idx := sort.Search(len(pd.epochs), func(i int) bool { return pd.epochs[i].epoch > epoch })
+	if idx == len(pd.epochs) {
+		return pd.epoch, pd.highWatermark
+	}
+	end := pd.epochs[idx].start
+	if idx == 0 {
+		return 0, end
+	}
+	return pd.epochs[idx-1].epoch, end
+}
+
+// truncate discards all batches at and after offset, rounding down to the
+// start of the batch containing offset, and returns the new end of the log.
+// Epochs that begin after the new end of the log are discarded as well.
+func (pd *partData) truncate(offset int64) int64 {
+	idx, _ := sort.Find(len(pd.batches), func(idx int) int {
+		b := &pd.batches[idx]
+		if offset < b.FirstOffset+int64(b.LastOffsetDelta)+1 {
+			return -1
+		}
+		return 1
+	})
+	end := min(offset, pd.highWatermark)
+	if idx < len(pd.batches) {
+		end = pd.batches[idx].FirstOffset
+	}
+	for _, b := range pd.batches[idx:] {
+		pd.nbytes -= int64(b.nbytes)
+	}
+	pd.batches = pd.batches[:idx]
+	pd.highWatermark = end
+	pd.lastStableOffset = min(pd.lastStableOffset, end)
+	pd.maxTimestamp = 0
+	if len(pd.batches) > 0 {
+		pd.maxTimestamp = pd.batches[len(pd.batches)-1].maxEarlierTimestamp
+	}
+	for len(pd.epochs) > 0 && pd.epochs[len(pd.epochs)-1].start >= end {
+		pd.epochs = pd.epochs[:len(pd.epochs)-1]
+	}
+	return end
+}
+
 func (pd *partData) searchOffset(o int64) (index int, found, atEnd bool) {
 	if o < pd.logStartOffset || o > pd.highWatermark {
 		return 0, false, false
@@ -185,6 +258,71 @@ func (pd *partData) searchOffset(o int64) (index int, found, atEnd bool) {
 	return index, found, false
 }
 
+// recordTimestamp returns the timestamp of a record in the batch: if the batch
+// uses LogAppendTime, all records have the batch's max timestamp.
+func (b *partBatch) recordTimestamp(rec kmsg.Record) int64 {
+	if b.Attributes&0x0008 != 0 {
+		return b.MaxTimestamp
+	}
+	return b.FirstTimestamp + rec.TimestampDelta64
+}
+
+// searchTimestamp returns the offset and timestamp of the first record whose
+// timestamp is at or after ts, or -1 and -1 if there is no such record.
+func (pd *partData) searchTimestamp(ts int64) (offset, timestamp int64, err error) {
+	idx, _ := sort.Find(len(pd.batches), func(idx int) int {
+		if pd.batches[idx].maxEarlierTimestamp >= ts {
+			return 0
+		}
+		return 1
+	})
+	for _, b := range pd.batches[idx:] {
+		if b.MaxTimestamp < ts {
+			continue
+		}
+		offset, timestamp = -1, -1
+		err = forEachBatchRecord(b.RecordBatch, func(rec kmsg.Record) error {
+			if recTs := b.recordTimestamp(rec); offset == -1 && recTs >= ts {
+				offset = b.FirstOffset + int64(rec.OffsetDelta)
+				timestamp = recTs
+			}
+			return nil
+		})
+		if err != nil || offset != -1 {
+			return offset, timestamp, err
+		}
+	}
+	return -1, -1, nil
+}
+
+// maxTimestampOffset returns the offset and timestamp of the first record
+// with the largest timestamp in the partition, or -1 and -1 if the partition
+// is empty.
+func (pd *partData) maxTimestampOffset() (offset, timestamp int64, err error) {
+	var mb *partBatch
+	for i := range pd.batches {
+		if b := &pd.batches[i]; mb == nil || b.MaxTimestamp > mb.MaxTimestamp {
+			mb = b
+		}
+	}
+	if mb == nil {
+		return -1, -1, nil
+	}
+	offset, timestamp = mb.FirstOffset, mb.MaxTimestamp
+	if mb.Attributes&0x0008 != 0 {
+		return offset, timestamp, nil
+	}
+	found := false
+	err = forEachBatchRecord(mb.RecordBatch, func(rec kmsg.Record) error {
+		if !found && mb.recordTimestamp(rec) == mb.MaxTimestamp {
+			offset = mb.FirstOffset + int64(rec.OffsetDelta)
+			found = true
+		}
+		return nil
+	})
+	return offset, timestamp, err
+}
+
 func (pd *partData) trimLeft() {
 	for len(pd.batches) > 0 {
 		b0 := pd.batches[0]
@@ -266,6 +404,21 @@ func (d *data) configs(t string, fn func(k string, v *string, src kmsg.ConfigSou
 	}
 }
 
+// topicConfig returns the effective value of a topic config: the dynamic
+// topic config if set, otherwise the dynamic broker equivalent if set,
+// otherwise the default.
+func (d *data) topicConfig(t, k string) string {
+	if v, ok := d.tcfgs[t][k]; ok && v != nil {
+		return *v
+	}
+	if bk := validTopicConfigs[k]; bk != "" {
+		if v, ok := d.c.bcfgs[bk]; ok && v != nil {
+			return *v
+		}
+	}
+	return configDefaults[k]
+}
+
 // Unlike Kafka, we validate the value before allowing it to be set.
 func (c *Cluster) setBrokerConfig(k string, v *string, dry bool) bool {
 	if dry {
@@ -1,6 +1,14 @@
 package kfake
 
-import "testing"
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kadm"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
 
 func TestConfigDefaults(t *testing.T) {
 	exceptions := map[string]struct{}{
@@ -17,3 +25,89 @@ func TestConfigDefaults(t *testing.T) {
 		}
 	}
 }
+
+func TestTimestampTypes(t *testing.T) {
+	const (
+		createTopic = "create"
+		appendTopic = "append"
+	)
+
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, createTopic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumeTopics(appendTopic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	logAppendTime := "LogAppendTime"
+	if _, err := kadm.NewClient(cl).CreateTopic(ctx, 1, 1, map[string]*string{"message.timestamp.type": &logAppendTime}, appendTopic); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	for _, topic := range []string{createTopic, appendTopic} {
+		for _, ms := range []int64{1000, 3000, 2000} {
+			r := &kgo.Record{Topic: topic, Timestamp: time.UnixMilli(ms)}
+			if err := cl.ProduceSync(ctx, r).FirstErr(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	fs := cl.PollRecords(ctx, 3)
+	if err := fs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	fs.EachRecord(func(r *kgo.Record) {
+		if r.Timestamp.Before(start.Truncate(time.Millisecond)) {
+			t.Errorf("offset %d: got create timestamp %v, expected log append time", r.Offset, r.Timestamp)
+		}
+		if r.Attrs.TimestampType() != 1 {
+			t.Errorf("offset %d: timestamp type is not log append time", r.Offset)
+		}
+	})
+
+	for _, test := range []struct {
+		ts        int64
+		expOffset int64
+		expTs     int64
+	}{
+		{0, 0, 1000},
+		{1000, 0, 1000},
+		{1500, 1, 3000},
+		{2500, 1, 3000},
+		{3001, -1, -1},
+		{-3, 1, 3000},
+	} {
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = createTopic
+		rp := kmsg.NewListOffsetsRequestTopicPartition()
+		rp.Timestamp = test.ts
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sp := resp.Topics[0].Partitions[0]
+		if sp.ErrorCode != 0 || sp.Offset != test.expOffset || sp.Timestamp != test.expTs {
+			t.Errorf("list offsets at %d: got (err %d, offset %d, ts %d) != exp (offset %d, ts %d)",
+				test.ts, sp.ErrorCode, sp.Offset, sp.Timestamp, test.expOffset, test.expTs)
+		}
+	}
+}
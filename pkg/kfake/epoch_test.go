@@ -0,0 +1,135 @@
+package kfake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestEpochEndOffset(t *testing.T) {
+	pd := new(partData)
+	check := func(epoch, expEpoch int32, expEnd int64) {
+		t.Helper()
+		if gotEpoch, gotEnd := pd.epochEndOffset(epoch); gotEpoch != expEpoch || gotEnd != expEnd {
+			t.Errorf("epoch %d: got (%d, %d) != exp (%d, %d)", epoch, gotEpoch, gotEnd, expEpoch, expEnd)
+		}
+	}
+
+	pd.highWatermark = 10
+	check(0, 0, 10)
+	check(1, -1, -1)
+
+	pd.bumpEpoch() // epoch 1 starts at 10
+	pd.bumpEpoch() // epoch 2 starts at 10, epoch 1 is empty
+	pd.highWatermark = 20
+	pd.bumpEpoch() // epoch 3 starts at 20
+	pd.highWatermark = 25
+	check(0, 0, 10)
+	check(1, 1, 10)
+	check(2, 2, 20)
+	check(3, 3, 25)
+	check(4, -1, -1)
+	check(-1, -1, -1)
+
+	// Truncating to 15 discards epoch 3; the new epoch begins at 15.
+	if end := pd.truncate(15); end != 15 {
+		t.Fatalf("got truncated end %d != exp 15", end)
+	}
+	pd.bumpEpoch()
+	check(2, 2, 15)
+	check(4, 4, 15)
+}
+
+func TestTruncatePartition(t *testing.T) {
+	const topic = "foo"
+	c, err := NewCluster(NumBrokers(1), SeedTopics(1, topic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	producer, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.DefaultProduceTopic(topic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer producer.Close()
+	consumer, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.ConsumeTopics(topic),
+		kgo.FetchMaxWait(100*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	produce := func(prefix string, n int) {
+		t.Helper()
+		for i := range n { // one batch per record
+			if err := producer.ProduceSync(ctx, kgo.StringRecord(fmt.Sprintf("%s-%d", prefix, i))).FirstErr(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	consume := func(n int) (vs []string, dataLoss bool) {
+		t.Helper()
+		for len(vs) < n {
+			fs := consumer.PollFetches(ctx)
+			if err := ctx.Err(); err != nil {
+				t.Fatalf("consumed %v before context expired, waiting for %d records", vs, n)
+			}
+			fs.EachError(func(_ string, _ int32, err error) {
+				var edl *kgo.ErrDataLoss
+				if !errors.As(err, &edl) {
+					t.Fatalf("unexpected fetch error: %v", err)
+				}
+				dataLoss = true
+			})
+			fs.EachRecord(func(r *kgo.Record) { vs = append(vs, string(r.Value)) })
+		}
+		return vs, dataLoss
+	}
+
+	produce("old", 10)
+	if vs, _ := consume(10); vs[9] != "old-9" {
+		t.Fatalf("got last consumed %s, exp old-9", vs[9])
+	}
+
+	// Bumping the epoch fences the consumer, which reloads metadata and
+	// continues where it left off.
+	if epoch, err := c.BumpLeaderEpoch(topic, 0); err != nil || epoch != 1 {
+		t.Fatalf("got bumped epoch %d, err %v; exp 1, nil", epoch, err)
+	}
+	produce("bumped", 1)
+	if vs, dataLoss := consume(1); vs[0] != "bumped-0" || dataLoss {
+		t.Fatalf("got consumed %v (data loss? %v) after bump, exp [bumped-0]", vs, dataLoss)
+	}
+
+	// Truncating discards the end of the log; the consumer detects that
+	// its offset diverged and resets to the truncation point.
+	end, err := c.TruncatePartition(topic, 0, 5)
+	if err != nil || end != 5 {
+		t.Fatalf("got truncated end %d, err %v; exp 5, nil", end, err)
+	}
+	if pi := c.PartitionInfo(topic, 0); pi.HighWatermark != 5 || pi.Epoch != 2 {
+		t.Fatalf("got high watermark %d, epoch %d after truncating; exp 5, 2", pi.HighWatermark, pi.Epoch)
+	}
+	produce("new", 3)
+	vs, dataLoss := consume(3)
+	if !dataLoss {
+		t.Error("did not see data loss after truncation")
+	}
+	if exp := []string{"new-0", "new-1", "new-2"}; fmt.Sprint(vs) != fmt.Sprint(exp) {
+		t.Errorf("got consumed %v after truncation, exp %v", vs, exp)
+	}
+}
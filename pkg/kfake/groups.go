@@ -119,6 +119,16 @@ func (c *Cluster) validateGroup(creq *clientReq, group string) *kerr.Error {
 	if coordinator != creq.cc.b.node {
 		return kerr.NotCoordinator
 	}
+	op := kmsg.ACLOperationRead
+	switch kmsg.Key(creq.kreq.Key()) {
+	case kmsg.DescribeGroups, kmsg.OffsetFetch:
+		op = kmsg.ACLOperationDescribe
+	case kmsg.DeleteGroups, kmsg.OffsetDelete:
+		op = kmsg.ACLOperationDelete
+	}
+	if !c.allowed(creq, kmsg.ACLResourceTypeGroup, group, op) {
+		return kerr.GroupAuthorizationFailed
+	}
 	return nil
 }
 
@@ -250,7 +260,8 @@ func (gs *groups) handleList(creq *clientReq) *kmsg.ListGroupsResponse {
 	}
 
 	for _, g := range gs.gs {
-		if g.c.coordinator(g.name).node != creq.cc.b.node {
+		if g.c.coordinator(g.name).node != creq.cc.b.node ||
+			!g.c.allowed(creq, kmsg.ACLResourceTypeGroup, g.name, kmsg.ACLOperationDescribe) {
 			continue
 		}
 		g.waitControl(func() {
@@ -705,7 +716,10 @@ func (g *group) handleJoin(creq *clientReq) (kmsg.Response, bool) {
 		}
 		g.updateMemberAndRebalance(m, creq, req)
 	case groupStable:
-		if g.leader != req.MemberID || m.sameJoin(req) {
+		// As in Kafka, a follower rejoining with the same metadata
+		// gets the current state back, while the leader rejoining or
+		// any member changing its metadata triggers a rebalance.
+		if g.leader != req.MemberID && m.sameJoin(req) {
 			g.fillJoinResp(req, resp)
 			return resp, true
 		}
@@ -0,0 +1,98 @@
+package kfake
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestLargeClusterMetadata(t *testing.T) {
+	const (
+		ntopics = 1000
+		nparts  = 100
+	)
+	c, err := NewCluster(
+		NumBrokers(3),
+		SeedNumberedTopics(nparts, "t-", ntopics),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cl, err := kgo.NewClient(kgo.SeedBrokers(c.ListenAddrs()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	meta, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for _, t := range meta.Topics {
+		n += len(t.Partitions)
+	}
+	if len(meta.Topics) != ntopics || n != ntopics*nparts {
+		t.Fatalf("got %d topics and %d partitions in metadata, exp %d and %d", len(meta.Topics), n, ntopics, ntopics*nparts)
+	}
+
+	// Page through every partition with DescribeTopicPartitions, using a
+	// limit that splits topics across pages.
+	const limit = 1500
+	seen := make(map[string]map[int32]bool)
+	req := kmsg.NewPtrDescribeTopicPartitionsRequest()
+	req.ResponsePartitionLimit = limit
+	var pages int
+	for {
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+		var npage int
+		for _, rt := range resp.Topics {
+			if rt.ErrorCode != 0 {
+				t.Fatalf("topic %s: unexpected error code %d", *rt.Topic, rt.ErrorCode)
+			}
+			ps := seen[*rt.Topic]
+			if ps == nil {
+				ps = make(map[int32]bool)
+				seen[*rt.Topic] = ps
+			}
+			for _, p := range rt.Partitions {
+				if ps[p.Partition] {
+					t.Fatalf("topic %s partition %d returned twice", *rt.Topic, p.Partition)
+				}
+				ps[p.Partition] = true
+				npage++
+			}
+		}
+		if npage > limit {
+			t.Fatalf("got %d partitions in a page, exp at most %d", npage, limit)
+		}
+		if resp.NextCursor == nil {
+			break
+		}
+		req.Cursor = &kmsg.DescribeTopicPartitionsRequestCursor{
+			Topic:     resp.NextCursor.Topic,
+			Partition: resp.NextCursor.Partition,
+		}
+	}
+	if exp := (ntopics*nparts + limit - 1) / limit; pages != exp {
+		t.Errorf("got %d pages, exp %d", pages, exp)
+	}
+	for i := range ntopics {
+		if topic := fmt.Sprintf("t-%d", i); len(seen[topic]) != nparts {
+			t.Errorf("topic %s: got %d partitions, exp %d", topic, len(seen[topic]), nparts)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package kfake
+
+import (
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Quotas are simulated per client ID with a simple token bucket per
+// direction: each bucket holds up to one second worth of bytes and refills at
+// the quota rate. Requests are never rejected; once a bucket goes negative,
+// the response has ThrottleMillis set to how long it takes for the bucket to
+// refill back to zero. Like Kafka post KIP-219, we return the throttle
+// immediately and rely on the client to back off.
+//
+// All quota state is only accessed in the cluster run loop.
+
+type (
+	quotas struct {
+		clients map[string]*clientQuota
+	}
+
+	clientQuota struct {
+		produce quotaBucket
+		fetch   quotaBucket
+	}
+
+	quotaBucket struct {
+		rate   int64 // bytes per second; 0 means unlimited
+		tokens int64
+		last   time.Time
+	}
+)
+
+// ClientQuota sets simulated produce and fetch byte rate quotas for a client
+// ID. A rate of zero or less means the direction is unlimited. This option can
+// be provided multiple times for different client IDs. See
+// [Cluster.SetClientQuota] for more details.
+func ClientQuota(clientID string, produceBytesPerSec, fetchBytesPerSec int64) Opt {
+	return opt{func(cfg *cfg) {
+		cfg.quotas = append(cfg.quotas, cfgQuota{clientID, produceBytesPerSec, fetchBytesPerSec})
+	}}
+}
+
+// SetClientQuota sets simulated produce and fetch byte rate quotas for a
+// client ID, overriding any prior quota for the client. A rate of zero or less
+// means the direction is unlimited; using zero for both removes the quota.
+//
+// Produce requests count the bytes of all record batches in the request, and
+// fetch responses count the bytes of all record batches returned. Once a client
+// exceeds its quota, produce and fetch responses have ThrottleMillis set to
+// how long the client must wait to be back within its quota. Requests are not
+// rejected nor delayed.
+func (c *Cluster) SetClientQuota(clientID string, produceBytesPerSec, fetchBytesPerSec int64) {
+	c.admin(func() { c.quotas.set(clientID, produceBytesPerSec, fetchBytesPerSec) })
+}
+
+// ThrottleClient sets a fixed throttle to return for all throttle-capable
+// responses to requests for the given key from the given client ID. This can
+// be used to test client throttle handling deterministically. A key of -1
+// throttles all keys. A non-positive duration removes the throttle.
+//
+// A fixed throttle takes precedence over any quota based throttle.
+func (c *Cluster) ThrottleClient(clientID string, key int16, d time.Duration) {
+	c.admin(func() {
+		if c.throttles == nil {
+			c.throttles = make(map[string]map[int16]time.Duration)
+		}
+		ks := c.throttles[clientID]
+		if d <= 0 {
+			delete(ks, key)
+			if len(ks) == 0 {
+				delete(c.throttles, clientID)
+			}
+			return
+		}
+		if ks == nil {
+			ks = make(map[int16]time.Duration)
+			c.throttles[clientID] = ks
+		}
+		ks[key] = d
+	})
+}
+
+func (q *quotas) set(clientID string, produce, fetch int64) {
+	if produce <= 0 && fetch <= 0 {
+		delete(q.clients, clientID)
+		return
+	}
+	if q.clients == nil {
+		q.clients = make(map[string]*clientQuota)
+	}
+	now := time.Now()
+	q.clients[clientID] = &clientQuota{
+		produce: newQuotaBucket(produce, now),
+		fetch:   newQuotaBucket(fetch, now),
+	}
+}
+
+func newQuotaBucket(rate int64, now time.Time) quotaBucket {
+	rate = max(rate, 0)
+	return quotaBucket{rate: rate, tokens: rate, last: now}
+}
+
+// take removes n bytes from the bucket and returns how long the client should
+// be throttled, if at all.
+func (b *quotaBucket) take(n int64, now time.Time) time.Duration {
+	if b.rate == 0 {
+		return 0
+	}
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.tokens+int64(elapsed)*b.rate/int64(time.Second), b.rate)
+		b.last = now
+	}
+	b.tokens -= n
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens * int64(time.Second) / b.rate)
+}
+
+// applyThrottle sets ThrottleMillis in the response if the client has a fixed
+// throttle or has exceeded its quota.
+func (c *Cluster) applyThrottle(creq *clientReq, kresp kmsg.Response) {
+	tr, ok := kresp.(kmsg.SetThrottleResponse)
+	if !ok {
+		return
+	}
+	key := creq.kreq.Key()
+	if ks := c.throttles[creq.cid]; ks != nil {
+		d, ok := ks[key]
+		if !ok {
+			d, ok = ks[-1]
+		}
+		if ok {
+			tr.SetThrottle(int32(d.Milliseconds()))
+			return
+		}
+	}
+
+	cq := c.quotas.clients[creq.cid]
+	if cq == nil {
+		return
+	}
+	var throttle time.Duration
+	now := time.Now()
+	switch req := creq.kreq.(type) {
+	case *kmsg.ProduceRequest:
+		var n int64
+		for _, t := range req.Topics {
+			for _, p := range t.Partitions {
+				n += int64(len(p.Records))
+			}
+		}
+		throttle = cq.produce.take(n, now)
+	case *kmsg.FetchRequest:
+		var n int64
+		for _, t := range kresp.(*kmsg.FetchResponse).Topics {
+			for _, p := range t.Partitions {
+				n += int64(len(p.RecordBatches))
+			}
+		}
+		throttle = cq.fetch.take(n, now)
+	default:
+		return
+	}
+	if throttle > 0 {
+		tr.SetThrottle(int32(throttle.Milliseconds()))
+	}
+}
@@ -0,0 +1,80 @@
+package kfake
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+type throttleHook struct {
+	produceThrottled atomic.Int64
+}
+
+func (h *throttleHook) OnBrokerThrottle(_ kgo.BrokerMetadata, throttleInterval time.Duration, _ bool) {
+	h.produceThrottled.Store(int64(throttleInterval))
+}
+
+func TestClientQuota(t *testing.T) {
+	const testTopic = "foo"
+
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, testTopic),
+		ClientQuota("slow", 100, 0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	h := new(throttleHook)
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.DefaultProduceTopic(testTopic),
+		kgo.ClientID("slow"),
+		kgo.WithHooks(h),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Our first produce is within the quota's one second burst; the
+	// second is well over.
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("a")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	if h.produceThrottled.Load() != 0 {
+		t.Fatalf("unexpectedly throttled on first produce")
+	}
+	if err := cl.ProduceSync(ctx, kgo.SliceRecord(make([]byte, 500))).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	if h.produceThrottled.Load() == 0 {
+		t.Fatal("expected throttle after exceeding produce quota")
+	}
+
+	// A fixed throttle applies regardless of quota.
+	c.ThrottleClient("slow", int16(kmsg.Metadata), 7*time.Millisecond)
+	req := kmsg.NewPtrMetadataRequest()
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.ThrottleMillis != 7 {
+		t.Errorf("got metadata throttle %d != exp 7", resp.ThrottleMillis)
+	}
+	c.ThrottleClient("slow", int16(kmsg.Metadata), 0)
+	if resp, err = req.RequestWith(ctx, cl); err != nil {
+		t.Fatal(err)
+	} else if resp.ThrottleMillis != 0 {
+		t.Errorf("got metadata throttle %d after removing throttle, exp 0", resp.ThrottleMillis)
+	}
+}
@@ -0,0 +1,74 @@
+package kfake
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+	"github.com/twmb/franz-go/pkg/kversion"
+)
+
+func TestMaxVersions(t *testing.T) {
+	const testTopic = "foo"
+
+	c, err := NewCluster(
+		NumBrokers(1),
+		SeedTopics(1, testTopic),
+		MaxVersions(kversion.V2_8_0()),
+		KeyVersions(int16(kmsg.Metadata), 1, 4),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	cl, err := kgo.NewClient(
+		kgo.SeedBrokers(c.ListenAddrs()...),
+		kgo.DefaultProduceTopic(testTopic),
+		kgo.ConsumeTopics(testTopic),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := kmsg.NewPtrApiVersionsRequest().RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v28 := kversion.V2_8_0()
+	for _, k := range resp.ApiKeys {
+		exp, ok := v28.LookupMaxKeyVersion(k.ApiKey)
+		if !ok {
+			t.Errorf("key %d advertised but does not exist in 2.8", k.ApiKey)
+			continue
+		}
+		if k.ApiKey == int16(kmsg.Metadata) {
+			if k.MinVersion != 1 || k.MaxVersion != 4 {
+				t.Errorf("metadata versions %d-%d != exp 1-4", k.MinVersion, k.MaxVersion)
+			}
+			continue
+		}
+		if k.MaxVersion > exp {
+			t.Errorf("key %d max version %d > exp %d", k.ApiKey, k.MaxVersion, exp)
+		}
+	}
+
+	// The client should still be able to produce and consume using the
+	// older versions.
+	if err := cl.ProduceSync(ctx, kgo.StringRecord("v")).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+	fs := cl.PollFetches(ctx)
+	if err := fs.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := fs.NumRecords(); n != 1 {
+		t.Errorf("got %d records != exp 1", n)
+	}
+}
@@ -1,6 +1,9 @@
 package kgo
 
-import "sync/atomic"
+import (
+	"sync/atomic"
+	"time"
+)
 
 const (
 	stateUnstarted = iota
@@ -74,3 +77,9 @@ type lazyI32 int32
 
 func (v *lazyI32) store(s int32) { atomic.StoreInt32((*int32)(v), s) }
 func (v *lazyI32) load() int32   { return atomic.LoadInt32((*int32)(v)) }
+
+// lazyDur is the same as lazyI32, but for a few duration settings.
+type lazyDur time.Duration
+
+func (v *lazyDur) store(d time.Duration) { atomic.StoreInt64((*int64)(v), int64(d)) }
+func (v *lazyDur) load() time.Duration   { return time.Duration(atomic.LoadInt64((*int64)(v))) }
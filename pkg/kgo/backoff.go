@@ -0,0 +1,159 @@
+package kgo
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Backoff returns how long to wait before retrying after a given number of
+// consecutive failures. The number of failures is always at least one for
+// the first retry.
+//
+// Backoffs can be used for specific request types with MetadataRetryBackoff,
+// ProduceRetryBackoff, FetchRetryBackoff, and GroupRetryBackoff. Request
+// types without a dedicated backoff use RetryBackoffFn.
+type Backoff interface {
+	// Backoff returns how long to wait after fails consecutive failures.
+	Backoff(fails int) time.Duration
+}
+
+// BackoffFn is a function that implements Backoff.
+type BackoffFn func(fails int) time.Duration
+
+// Backoff implements Backoff.
+func (fn BackoffFn) Backoff(fails int) time.Duration { return fn(fails) }
+
+// FixedBackoff returns a Backoff that always waits d.
+func FixedBackoff(d time.Duration) Backoff {
+	return BackoffFn(func(int) time.Duration { return d })
+}
+
+// FullJitterBackoff returns an exponential Backoff that waits a random
+// duration between zero and an exponentially growing ceiling. The ceiling
+// starts at base and doubles on every failure until it reaches limit.
+//
+// Full jitter spreads out retries from many clients that failed at the same
+// time, at the expense of sometimes retrying almost immediately.
+func FullJitterBackoff(base, limit time.Duration) Backoff {
+	return BackoffFn(func(fails int) time.Duration {
+		ceil := expBackoff(base, limit, fails)
+		if ceil <= 0 {
+			return 0
+		}
+		return time.Duration(rand.Int63n(int64(ceil) + 1))
+	})
+}
+
+// DecorrelatedJitterBackoff returns a Backoff that waits a random duration
+// between base and three times the prior wait, capped at limit. The prior wait
+// resets to base whenever fails is one, i.e. on the first retry of a new
+// sequence of failures.
+//
+// Unlike the other shipped backoffs, this backoff is stateful: the next wait
+// depends on the previous one. Every request type the backoff is used for
+// shares the same state, so it is best to use a dedicated
+// DecorrelatedJitterBackoff per request type.
+func DecorrelatedJitterBackoff(base, limit time.Duration) Backoff {
+	var (
+		mu   sync.Mutex
+		prev = base
+	)
+	return BackoffFn(func(fails int) time.Duration {
+		mu.Lock()
+		defer mu.Unlock()
+		if fails <= 1 {
+			prev = base
+		}
+		ceil := 3 * prev
+		if ceil > limit || ceil < 0 {
+			ceil = limit
+		}
+		wait := base
+		if ceil > base {
+			wait += time.Duration(rand.Int63n(int64(ceil - base + 1)))
+		}
+		prev = wait
+		return wait
+	})
+}
+
+// expBackoff returns base doubled for every failure after the first, capped
+// at limit.
+func expBackoff(base, limit time.Duration, fails int) time.Duration {
+	if fails <= 1 {
+		return min(base, limit)
+	}
+	backoff := base
+	for range fails - 1 {
+		backoff *= 2
+		if backoff >= limit || backoff <= 0 {
+			return limit
+		}
+	}
+	return backoff
+}
+
+// retryBackoffKind is the group of requests a backoff applies to.
+type retryBackoffKind uint8
+
+const (
+	backoffDefault retryBackoffKind = iota
+	backoffMetadata
+	backoffProduce
+	backoffFetch
+	backoffGroup
+)
+
+// backoffKindForKey returns which backoff to use when retrying a request.
+func backoffKindForKey(key int16) retryBackoffKind {
+	switch kmsg.Key(key) {
+	case kmsg.Metadata:
+		return backoffMetadata
+	case kmsg.Produce,
+		kmsg.InitProducerID,
+		kmsg.AddPartitionsToTxn,
+		kmsg.AddOffsetsToTxn,
+		kmsg.EndTxn,
+		kmsg.TxnOffsetCommit:
+		return backoffProduce
+	case kmsg.Fetch,
+		kmsg.ListOffsets,
+		kmsg.OffsetForLeaderEpoch:
+		return backoffFetch
+	case kmsg.FindCoordinator,
+		kmsg.JoinGroup,
+		kmsg.SyncGroup,
+		kmsg.Heartbeat,
+		kmsg.LeaveGroup,
+		kmsg.OffsetCommit,
+		kmsg.OffsetFetch,
+		kmsg.ConsumerGroupHeartbeat:
+		return backoffGroup
+	default:
+		return backoffDefault
+	}
+}
+
+// backoff returns how long to backoff for the given kind of request after
+// fails failures, falling back to the client-wide retry backoff if the kind
+// has no dedicated backoff.
+func (cfg *cfg) backoff(kind retryBackoffKind, fails int) time.Duration {
+	var b Backoff
+	switch kind {
+	case backoffMetadata:
+		b = cfg.metadataBackoff
+	case backoffProduce:
+		b = cfg.produceBackoff
+	case backoffFetch:
+		b = cfg.fetchBackoff
+	case backoffGroup:
+		b = cfg.groupBackoff
+	}
+	if b != nil {
+		return b.Backoff(fails)
+	}
+	return cfg.retryBackoff(fails)
+}
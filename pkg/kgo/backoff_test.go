@@ -0,0 +1,64 @@
+package kgo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestBackoffs(t *testing.T) {
+	const (
+		base  = 100 * time.Millisecond
+		limit = time.Second
+	)
+
+	for fails, exp := range map[int]time.Duration{
+		0:  base,
+		1:  base,
+		2:  2 * base,
+		4:  8 * base,
+		5:  limit,
+		64: limit,
+	} {
+		if got := expBackoff(base, limit, fails); got != exp {
+			t.Errorf("expBackoff fails %d: got %v != exp %v", fails, got, exp)
+		}
+	}
+
+	full := FullJitterBackoff(base, limit)
+	decor := DecorrelatedJitterBackoff(base, limit)
+	for range 100 {
+		for fails := 1; fails < 10; fails++ {
+			if got := full.Backoff(fails); got < 0 || got > expBackoff(base, limit, fails) {
+				t.Errorf("full jitter fails %d: got %v out of bounds", fails, got)
+			}
+			if got := decor.Backoff(fails); got < base || got > limit || fails == 1 && got > 3*base {
+				t.Errorf("decorrelated jitter fails %d: got %v out of bounds", fails, got)
+			}
+		}
+	}
+	if got := FixedBackoff(base).Backoff(7); got != base {
+		t.Errorf("fixed: got %v != exp %v", got, base)
+	}
+
+	cl, err := NewClient(
+		RetryBackoffFn(func(int) time.Duration { return time.Hour }),
+		MetadataRetryBackoff(FixedBackoff(time.Second)),
+		GroupRetryBackoff(FixedBackoff(time.Minute)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	for key, exp := range map[kmsg.Key]time.Duration{
+		kmsg.Metadata:    time.Second,
+		kmsg.JoinGroup:   time.Minute,
+		kmsg.Produce:     time.Hour,
+		kmsg.ApiVersions: time.Hour,
+	} {
+		if got := cl.cfg.backoff(backoffKindForKey(int16(key)), 1); got != exp {
+			t.Errorf("%s: got backoff %v != exp %v", key.Name(), got, exp)
+		}
+	}
+}
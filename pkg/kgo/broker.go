@@ -157,6 +157,10 @@ type broker struct {
 	cxnGroup   *brokerCxn
 	cxnSlow    *brokerCxn
 
+	// cxnFetchShards are additional fetch connections used with
+	// ConcurrentFetchesPerBroker: source shard i+1 fetches on index i.
+	cxnFetchShards []*brokerCxn
+
 	reapMu sync.Mutex // held when modifying a brokerCxn
 
 	// reqs manages incoming message requests.
@@ -223,9 +227,17 @@ func (cl *Client) newBroker(nodeID int32, host string, port int32, rack *string)
 			Port:   port,
 			Rack:   rack,
 		},
+
+		cxnFetchShards: make([]*brokerCxn, max(cl.cfg.fetchConcurrencyPerBroker-1, 0)),
 	}
 }
 
+// errDead returns the error for requests that were issued to this broker
+// after it was stopped.
+func (b *broker) errDead() error {
+	return &ErrBrokerDown{b.meta.NodeID, errChosenBrokerDead}
+}
+
 // stopForever permanently disables this broker.
 func (b *broker) stopForever() {
 	if b.dead.Swap(true) {
@@ -242,6 +254,9 @@ func (b *broker) stopForever() {
 	b.cxnFetch.die()
 	b.cxnGroup.die()
 	b.cxnSlow.die()
+	for _, cxn := range b.cxnFetchShards {
+		cxn.die()
+	}
 }
 
 // do issues a request to the broker, eventually calling the response
@@ -260,7 +275,7 @@ func (b *broker) do(
 	if first {
 		go b.handleReqs(pr)
 	} else if dead {
-		promise(nil, errChosenBrokerDead)
+		promise(nil, b.errDead())
 	}
 }
 
@@ -282,7 +297,7 @@ func (b *broker) handleReqs(pr promisedReq) {
 	var more, dead bool
 start:
 	if dead {
-		pr.promise(nil, errChosenBrokerDead)
+		pr.promise(nil, b.errDead())
 	} else {
 		b.handleReq(pr)
 	}
@@ -312,6 +327,10 @@ start:
 			return
 		}
 	}
+	if _, ok := req.(*connectReq); ok {
+		pr.promise(nil, nil)
+		return
+	}
 
 	v := b.loadVersions()
 
@@ -324,7 +343,7 @@ start:
 	// versions. If the version for this request is negative, we
 	// know the broker cannot handle this request.
 	if v.maxVers[0] >= 0 && v.maxVers[req.Key()] < 0 {
-		pr.promise(nil, errBrokerTooOld)
+		pr.promise(nil, &ErrBrokerTooOld{b.meta.NodeID, req.Key()})
 		return
 	}
 
@@ -386,7 +405,7 @@ start:
 	//   vs broker maxes. Technically the broker isn't too old, but we keep
 	//   it simple here.
 	if ourMin > -1 && ourMin > ourMax {
-		pr.promise(nil, errBrokerTooOld) // this error is relied on for sharding
+		pr.promise(nil, &ErrBrokerTooOld{b.meta.NodeID, req.Key()}) // this error is relied on for sharding
 		return
 	}
 	req.SetVersion(ourMax)
@@ -483,7 +502,7 @@ start:
 		return
 	}
 
-	rt, _ := cxn.cl.connTimeouter.timeouts(req)
+	rt, _ := cxn.cl.connTimeouter.timeouts(cxn.b.meta.NodeID, req)
 
 	cxn.waitResp(promisedResp{
 		pr.ctx,
@@ -500,14 +519,16 @@ start:
 }
 
 func (cxn *brokerCxn) hookWriteE2E(key int16, bytesWritten int, writeWait, timeToWrite time.Duration, writeErr error) {
+	e2e := BrokerE2E{
+		BytesWritten: bytesWritten,
+		WriteWait:    writeWait,
+		TimeToWrite:  timeToWrite,
+		WriteErr:     writeErr,
+	}
+	cxn.cl.stats.observeE2E(cxn.b.meta.NodeID, e2e)
 	cxn.cl.cfg.hooks.each(func(h Hook) {
 		if h, ok := h.(HookBrokerE2E); ok {
-			h.OnBrokerE2E(cxn.b.meta, key, BrokerE2E{
-				BytesWritten: bytesWritten,
-				WriteWait:    writeWait,
-				TimeToWrite:  timeToWrite,
-				WriteErr:     writeErr,
-			})
+			h.OnBrokerE2E(cxn.b.meta, key, e2e)
 		}
 	})
 }
@@ -540,6 +561,9 @@ func (b *broker) loadConnection(ctx context.Context, req kmsg.Request) (*brokerC
 		isProduceCxn = true
 	case reqKey == 1:
 		pcxn = &b.cxnFetch
+		if fr, ok := req.(*fetchRequest); ok && fr.shard > 0 {
+			pcxn = &b.cxnFetchShards[fr.shard-1]
+		}
 		isFetchCxn = true
 	case reqKey == 11 || reqKey == 14: // join || sync
 		pcxn = &b.cxnGroup
@@ -547,8 +571,27 @@ func (b *broker) loadConnection(ctx context.Context, req kmsg.Request) (*brokerC
 		pcxn = &b.cxnSlow
 	}
 
-	if *pcxn != nil && !(*pcxn).dead.Load() {
-		return *pcxn, nil
+	// If the connection has lived past ConnMaxLifetime, we open a new
+	// connection and retire the old one. If opening fails, we keep using
+	// the old connection and try again on the next request.
+	//
+	// We never cycle the produce connection while requests are inflight:
+	// the sink pipelines sequence numbers and relies on them arriving in
+	// order on one connection. Requests are written to a broker serially,
+	// so nothing can be added to the old connection after we check.
+	var retire *brokerCxn
+	if cur := *pcxn; cur != nil && !cur.dead.Load() {
+		if lifetime := b.cl.cfg.connMaxLifetime; lifetime <= 0 || time.Since(cur.created) < lifetime || isProduceCxn && !cur.resps.empty() {
+			return cur, nil
+		}
+		retire = cur
+	}
+	keepRetiring := func(err error) (*brokerCxn, error) {
+		if retire == nil {
+			return nil, err
+		}
+		b.cl.cfg.logger.Log(LogLevelWarn, "unable to open a connection to replace a connection past its max lifetime, continuing to use the old connection", "addr", b.addr, "broker", logID(b.meta.NodeID), "err", err)
+		return retire, nil
 	}
 
 	var tries int
@@ -565,16 +608,17 @@ doConnect:
 		})
 	}()
 	if err != nil {
-		return nil, err
+		return keepRetiring(err)
 	}
 
 	cxn := &brokerCxn{
 		cl: b.cl,
 		b:  b,
 
-		addr:   b.addr,
-		conn:   conn,
-		deadCh: make(chan struct{}),
+		addr:    b.addr,
+		conn:    conn,
+		created: time.Now(),
+		deadCh:  make(chan struct{}),
 	}
 	if err = cxn.init(isProduceCxn, tries); err != nil {
 		// EventHubs does not handle v4 and resets the connection. We
@@ -589,7 +633,7 @@ doConnect:
 		}
 		b.cl.cfg.logger.Log(LogLevelDebug, "connection initialization failed", "addr", b.addr, "broker", logID(b.meta.NodeID), "err", err)
 		cxn.closeConn()
-		return nil, err
+		return keepRetiring(err)
 	}
 	b.cl.cfg.logger.Log(LogLevelDebug, "connection initialized successfully", "addr", b.addr, "broker", logID(b.meta.NodeID))
 
@@ -602,6 +646,10 @@ doConnect:
 	b.reapMu.Lock()
 	defer b.reapMu.Unlock()
 	*pcxn = cxn
+	if retire != nil {
+		b.cl.cfg.logger.Log(LogLevelDebug, "retiring connection past its max lifetime", "addr", b.addr, "broker", logID(b.meta.NodeID), "age", time.Since(retire.created))
+		retire.retire()
+	}
 	return cxn, nil
 }
 
@@ -648,13 +696,13 @@ func (b *broker) reapConnections(idleTimeout time.Duration) (total int) {
 	b.reapMu.Lock()
 	defer b.reapMu.Unlock()
 
-	for _, cxn := range []*brokerCxn{
+	for _, cxn := range append([]*brokerCxn{
 		b.cxnNormal,
 		b.cxnProduce,
 		b.cxnFetch,
 		b.cxnGroup,
 		b.cxnSlow,
-	} {
+	}, b.cxnFetchShards...) {
 		if cxn == nil || cxn.dead.Load() {
 			continue
 		}
@@ -683,7 +731,15 @@ func (b *broker) reapConnections(idleTimeout time.Duration) (total int) {
 // connect connects to the broker's addr, returning the new connection.
 func (b *broker) connect(ctx context.Context) (net.Conn, error) {
 	b.cl.cfg.logger.Log(LogLevelDebug, "opening connection to broker", "addr", b.addr, "broker", logID(b.meta.NodeID))
-	conn, err := b.cl.cfg.dialFn(ctx, "tcp", b.addr)
+	var (
+		conn net.Conn
+		err  error
+	)
+	if r := b.cl.resolver; r != nil {
+		conn, err = r.dial(ctx, b.meta, b.addr)
+	} else {
+		conn, err = b.cl.cfg.dialFn(ctx, "tcp", b.addr)
+	}
 	if err != nil {
 		if !errors.Is(err, ErrClientClosed) && !errors.Is(err, context.Canceled) && !strings.Contains(err.Error(), "operation was canceled") {
 			if errors.Is(err, io.EOF) {
@@ -694,6 +750,9 @@ func (b *broker) connect(ctx context.Context) (net.Conn, error) {
 		}
 		return nil, fmt.Errorf("unable to dial: %w", err)
 	}
+	if err := tuneTCPConn(&b.cl.cfg, conn); err != nil {
+		b.cl.cfg.logger.Log(LogLevelWarn, "unable to apply TCP options to broker connection", "addr", b.addr, "broker", logID(b.meta.NodeID), "err", err)
+	}
 	b.cl.cfg.logger.Log(LogLevelDebug, "connection opened to broker", "addr", b.addr, "broker", logID(b.meta.NodeID))
 	return conn, nil
 }
@@ -725,6 +784,12 @@ type brokerCxn struct {
 
 	successes uint64
 
+	// created is when the connection was opened, for ConnMaxLifetime.
+	// Once retired, no more requests are written to the connection and
+	// it is closed as soon as all in flight responses are read.
+	created time.Time
+	retired atomicBool
+
 	// resps manages reading kafka responses.
 	resps ring[promisedResp]
 	// dead is an atomic so that a backed up resps cannot block cxn death.
@@ -790,7 +855,7 @@ start:
 		return writeErr
 	}
 
-	rt, _ := cxn.cl.connTimeouter.timeouts(req)
+	rt, _ := cxn.cl.connTimeouter.timeouts(cxn.b.meta.NodeID, req)
 	// api versions does *not* use flexible response headers; see comment in promisedResp
 	rawResp, err := cxn.readResponse(nil, req.Key(), req.GetVersion(), corrID, false, rt, bytesWritten, writeWait, timeToWrite, readEnqueue)
 	if err != nil {
@@ -886,7 +951,7 @@ start:
 			return writeErr
 		}
 
-		rt, _ := cxn.cl.connTimeouter.timeouts(req)
+		rt, _ := cxn.cl.connTimeouter.timeouts(cxn.b.meta.NodeID, req)
 		rawResp, err := cxn.readResponse(nil, req.Key(), req.GetVersion(), corrID, req.IsFlexible(), rt, bytesWritten, writeWait, timeToWrite, readEnqueue)
 		if err != nil {
 			return err
@@ -932,7 +997,7 @@ func (cxn *brokerCxn) doSasl(authenticate bool) error {
 
 	// Even if we do not wrap our reads/writes in SASLAuthenticate, we
 	// still use the SASLAuthenticate timeouts.
-	rt, wt := cxn.cl.connTimeouter.timeouts(kmsg.NewPtrSASLAuthenticateRequest())
+	rt, wt := cxn.cl.connTimeouter.timeouts(cxn.b.meta.NodeID, kmsg.NewPtrSASLAuthenticateRequest())
 
 	// We continue writing until both the challenging is done AND the
 	// responses are done. We can have an additional response once we
@@ -1080,7 +1145,7 @@ func (cxn *brokerCxn) writeRequest(ctx context.Context, enqueuedForWritingAt tim
 			case <-cxn.cl.ctx.Done():
 				writeErr = ErrClientClosed
 			case <-cxn.deadCh:
-				writeErr = errChosenBrokerDead
+				writeErr = cxn.b.errDead()
 			}
 			if writeErr != nil {
 				after.Stop()
@@ -1090,13 +1155,20 @@ func (cxn *brokerCxn) writeRequest(ctx context.Context, enqueuedForWritingAt tim
 		}
 	}
 
-	buf := cxn.cl.reqFormatter.AppendRequest(
+	if len(cxn.cl.cfg.faults) > 0 {
+		if writeErr = cxn.injectWriteFault(ctx, req.Key()); writeErr != nil {
+			writeWait = time.Since(enqueuedForWritingAt)
+			return corrID, bytesWritten, writeWait, timeToWrite, readEnqueue, writeErr
+		}
+	}
+
+	buf := cxn.cl.taggedFormatterFor(ctx, req.Key()).AppendRequest(
 		cxn.cl.bufPool.get()[:0],
 		req,
 		cxn.corrID,
 	)
 
-	_, wt := cxn.cl.connTimeouter.timeouts(req)
+	_, wt := cxn.cl.connTimeouter.timeouts(cxn.b.meta.NodeID, req)
 	bytesWritten, writeWait, timeToWrite, readEnqueue, writeErr = cxn.writeConn(ctx, buf, wt, enqueuedForWritingAt)
 
 	cxn.cl.bufPool.put(buf)
@@ -1290,20 +1362,22 @@ func (cxn *brokerCxn) readResponse(
 ) ([]byte, error) {
 	bytesRead, buf, readWait, timeToRead, readErr := cxn.readConn(ctx, timeout, readEnqueue)
 
+	e2e := BrokerE2E{
+		BytesWritten: bytesWritten,
+		BytesRead:    bytesRead,
+		WriteWait:    writeWait,
+		TimeToWrite:  timeToWrite,
+		ReadWait:     readWait,
+		TimeToRead:   timeToRead,
+		ReadErr:      readErr,
+	}
+	cxn.cl.stats.observeE2E(cxn.b.meta.NodeID, e2e)
 	cxn.cl.cfg.hooks.each(func(h Hook) {
 		if h, ok := h.(HookBrokerRead); ok {
 			h.OnBrokerRead(cxn.b.meta, key, bytesRead, readWait, timeToRead, readErr)
 		}
 		if h, ok := h.(HookBrokerE2E); ok {
-			h.OnBrokerE2E(cxn.b.meta, key, BrokerE2E{
-				BytesWritten: bytesWritten,
-				BytesRead:    bytesRead,
-				WriteWait:    writeWait,
-				TimeToWrite:  timeToWrite,
-				ReadWait:     readWait,
-				TimeToRead:   timeToRead,
-				ReadErr:      readErr,
-			})
+			h.OnBrokerE2E(cxn.b.meta, key, e2e)
 		}
 	})
 
@@ -1363,6 +1437,19 @@ func (cxn *brokerCxn) die() {
 	cxn.resps.die()
 }
 
+// retire closes the connection once all in flight requests have had their
+// responses read. This must only be called after the connection is no longer
+// used for new requests. If responses are still being read, handleResps kills
+// the connection once it drains; we set retired first so that either we see
+// the ring is empty or handleResps sees that we are retired (or both, which is
+// fine since die is idempotent).
+func (cxn *brokerCxn) retire() {
+	cxn.retired.Store(true)
+	if cxn.resps.empty() {
+		cxn.die()
+	}
+}
+
 // waitResp, called serially by a broker's handleReqs, manages handling a
 // message requests's response.
 func (cxn *brokerCxn) waitResp(pr promisedResp) {
@@ -1370,8 +1457,9 @@ func (cxn *brokerCxn) waitResp(pr promisedResp) {
 	if first {
 		go cxn.handleResps(pr)
 	} else if dead {
-		pr.promise(nil, errChosenBrokerDead)
-		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, errChosenBrokerDead)
+		err := cxn.b.errDead()
+		pr.promise(nil, err)
+		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, err)
 	}
 }
 
@@ -1446,7 +1534,7 @@ func (cxn *brokerCxn) discard() {
 		//
 		deadline := time.Time{}
 		if i == 0 {
-			deadline = time.Now().Add(3*cxn.cl.cfg.requestTimeoutOverhead + cxn.cl.cfg.produceTimeout)
+			deadline = time.Now().Add(3*cxn.cl.connTimeouter.overhead(cxn.b.meta.NodeID, 0) + cxn.cl.cfg.produceTimeout)
 		}
 		cxn.conn.SetReadDeadline(deadline)
 
@@ -1516,8 +1604,9 @@ func (cxn *brokerCxn) handleResps(pr promisedResp) {
 	var more, dead bool
 start:
 	if dead {
-		pr.promise(nil, errChosenBrokerDead)
-		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, errChosenBrokerDead)
+		err := cxn.b.errDead()
+		pr.promise(nil, err)
+		cxn.hookWriteE2E(pr.resp.Key(), pr.bytesWritten, pr.writeWait, pr.timeToWrite, err)
 	} else {
 		cxn.handleResp(pr)
 	}
@@ -1526,6 +1615,9 @@ start:
 	if more {
 		goto start
 	}
+	if cxn.retired.Load() {
+		cxn.die()
+	}
 }
 
 func (cxn *brokerCxn) handleResp(pr promisedResp) {
@@ -1565,6 +1657,15 @@ func (cxn *brokerCxn) handleResp(pr promisedResp) {
 	cxn.successes++
 	readErr := pr.resp.ReadFrom(rawResp)
 
+	if readErr == nil && len(cxn.cl.cfg.faults) > 0 {
+		if err := cxn.injectResponseFault(pr.resp); err != nil {
+			cxn.b.cl.cfg.logger.Log(LogLevelDebug, "injected response fault, killing connection", "req", kmsg.Key(pr.resp.Key()).Name(), "broker", logID(cxn.b.meta.NodeID), "err", err)
+			pr.promise(nil, err)
+			cxn.die()
+			return
+		}
+	}
+
 	// If we had no error, we read the response successfully.
 	//
 	// Any response that can cause throttling satisfies the
@@ -0,0 +1,411 @@
+package kgo
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Headers used for chunked record values; see [Client.ProduceChunked].
+const (
+	// ChunkIDHeader is a random 16 byte ID shared by all chunks of a
+	// value.
+	ChunkIDHeader = "kgo-chunk-id"
+
+	// ChunkIndexHeader is the 4 byte big endian index of a chunk.
+	ChunkIndexHeader = "kgo-chunk-index"
+
+	// ChunkTotalHeader is the 4 byte big endian total number of chunks
+	// of a value. If the total is not known until the value is fully
+	// read, this is zero for every chunk but the final chunk.
+	ChunkTotalHeader = "kgo-chunk-total"
+
+	// ChunkChecksumHeader is the 4 byte big endian CRC-32C (Castagnoli)
+	// checksum of the full value, and exists only on the final chunk.
+	ChunkChecksumHeader = "kgo-chunk-crc32c"
+
+	// ChunkKeylessHeader exists if the original record had no key, in
+	// which case chunks use the chunk ID as the key so that all chunks
+	// are partitioned to the same partition.
+	ChunkKeylessHeader = "kgo-chunk-keyless"
+)
+
+// ChunkInfo is the chunking metadata for a record produced with
+// [Client.ProduceChunked], as returned from [ParseChunk].
+type ChunkInfo struct {
+	// ID is the ID shared by all chunks of a value.
+	ID []byte
+
+	// Index is the index of this chunk.
+	Index uint32
+
+	// Total is the total number of chunks of the value, or zero if the
+	// total was not known when this chunk was produced.
+	Total uint32
+
+	// Checksum is the CRC-32C checksum of the full value, if HasChecksum
+	// is true. Only the final chunk has a checksum.
+	Checksum    uint32
+	HasChecksum bool
+
+	// Keyless is true if the original record had no key.
+	Keyless bool
+}
+
+// IsFinal returns whether this is the final chunk of a value.
+func (c ChunkInfo) IsFinal() bool { return c.Total > 0 && c.Index == c.Total-1 }
+
+// ParseChunk returns the chunking metadata for a record produced with
+// [Client.ProduceChunked], and false if the record is not a chunk. This can
+// be used to stream chunks to a destination (i.e., a file) as they are
+// consumed rather than reassembling values in memory with [ChunkAssembler].
+func ParseChunk(r *Record) (ChunkInfo, bool) {
+	var (
+		c                         ChunkInfo
+		hasID, hasIndex, hasTotal bool
+	)
+	for _, h := range r.Headers {
+		switch h.Key {
+		case ChunkIDHeader:
+			c.ID, hasID = h.Value, len(h.Value) > 0
+		case ChunkIndexHeader:
+			if len(h.Value) == 4 {
+				c.Index, hasIndex = binary.BigEndian.Uint32(h.Value), true
+			}
+		case ChunkTotalHeader:
+			if len(h.Value) == 4 {
+				c.Total, hasTotal = binary.BigEndian.Uint32(h.Value), true
+			}
+		case ChunkChecksumHeader:
+			if len(h.Value) == 4 {
+				c.Checksum, c.HasChecksum = binary.BigEndian.Uint32(h.Value), true
+			}
+		case ChunkKeylessHeader:
+			c.Keyless = true
+		}
+	}
+	return c, hasID && hasIndex && hasTotal
+}
+
+// ProduceChunked produces a value read from an io.Reader as a series of
+// records of at most chunkSize value bytes each, allowing values that are
+// larger than the broker's max.message.bytes to be produced. The input record
+// is used as a template: every chunk has the record's topic, key, headers, and
+// timestamp, plus chunk headers (see [ChunkIDHeader] and the related
+// headers). The record's Value is ignored. Consumers can reassemble values
+// with [ChunkAssembler].
+//
+// All chunks of a value must be produced to the same partition. Chunks use the
+// record's key, and if the record has no key, the chunk ID is used as the key
+// (and the keyless header is added so that the original key can be restored).
+// If you use a custom partitioner, it must partition records with the same key
+// to the same partition.
+//
+// If the input reader has a Len() int method (such as *bytes.Reader), every
+// chunk has the total number of chunks; otherwise, only the final chunk has the
+// total. The final chunk also has a checksum of the full value.
+//
+// This function waits for all chunks to be produced and returns the first
+// error encountered. If producing fails part way through, no further chunks
+// are produced; chunks that were produced are orphaned and are eventually
+// discarded by consumers using a ChunkAssembler with MaxPending.
+func (cl *Client) ProduceChunked(ctx context.Context, r *Record, value io.Reader, chunkSize int) error {
+	if chunkSize <= 0 {
+		return errors.New("invalid non-positive chunk size")
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return fmt.Errorf("unable to generate chunk ID: %w", err)
+	}
+	var total uint32
+	if l, ok := value.(interface{ Len() int }); ok {
+		total = uint32(max((l.Len()+chunkSize-1)/chunkSize, 1))
+	}
+
+	var (
+		wg       sync.WaitGroup
+		errMu    sync.Mutex
+		firstErr error
+
+		setErr = func(err error) {
+			errMu.Lock()
+			defer errMu.Unlock()
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		getErr = func() error {
+			errMu.Lock()
+			defer errMu.Unlock()
+			return firstErr
+		}
+
+		crc = crc32.New(crc32c)
+	)
+
+	// readChunk reads the next chunk; eof is true if the reader is
+	// exhausted, in which case the chunk may be short or empty.
+	readChunk := func() (chunk []byte, eof bool, err error) {
+		chunk = make([]byte, chunkSize)
+		n, err := io.ReadFull(value, chunk)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return chunk[:n], true, nil
+		}
+		return chunk[:n], false, err
+	}
+
+	cur, eof, readErr := readChunk()
+	for idx := uint32(0); readErr == nil && getErr() == nil; idx++ {
+		var next []byte
+		last := eof
+		if !last {
+			if next, eof, readErr = readChunk(); readErr != nil {
+				break
+			}
+			last = eof && len(next) == 0
+		}
+
+		crc.Write(cur)
+		chunkTotal := total
+		if last {
+			chunkTotal = idx + 1
+		}
+		chunk := newChunkRecord(r, id, idx, chunkTotal)
+		chunk.Value = cur
+		if last {
+			chunk.Headers = append(chunk.Headers, RecordHeader{ChunkChecksumHeader, binary.BigEndian.AppendUint32(nil, crc.Sum32())})
+		}
+
+		wg.Add(1)
+		cl.Produce(ctx, chunk, func(_ *Record, err error) {
+			defer wg.Done()
+			if err != nil {
+				setErr(err)
+			}
+		})
+		if last {
+			break
+		}
+		cur = next
+	}
+	if readErr != nil {
+		setErr(fmt.Errorf("unable to read chunked value: %w", readErr))
+	}
+	wg.Wait()
+	return getErr()
+}
+
+// newChunkRecord returns a chunk record with all chunk headers except the
+// checksum.
+func newChunkRecord(r *Record, id []byte, idx, total uint32) *Record {
+	chunk := &Record{
+		Key:       r.Key,
+		Headers:   slices.Clip(slices.Clone(r.Headers)),
+		Timestamp: r.Timestamp,
+		Topic:     r.Topic,
+		Partition: r.Partition,
+		Context:   r.Context,
+	}
+	if chunk.Key == nil {
+		chunk.Key = id
+		chunk.Headers = append(chunk.Headers, RecordHeader{Key: ChunkKeylessHeader})
+	}
+	chunk.Headers = append(chunk.Headers,
+		RecordHeader{ChunkIDHeader, id},
+		RecordHeader{ChunkIndexHeader, binary.BigEndian.AppendUint32(nil, idx)},
+		RecordHeader{ChunkTotalHeader, binary.BigEndian.AppendUint32(nil, total)},
+	)
+	return chunk
+}
+
+// ChunkAssembler reassembles values produced with [Client.ProduceChunked].
+// The zero value is ready to use, and an assembler is safe for concurrent
+// use.
+//
+// Assembled records are a copy of the final chunk with the full value, the
+// original key, and with chunk headers removed. Because the assembled record
+// has the final chunk's offset, committing the assembled record commits past
+// all of its chunks. If values are chunked by multiple concurrent producers to
+// the same partition, chunks can interleave, and committing one assembled
+// record can commit past chunks of a value that is still being assembled; on
+// restart, that value is lost. If this is a concern, use
+// [ChunkAssembler.PendingFirstOffset] to avoid committing past pending
+// values.
+type ChunkAssembler struct {
+	// MaxValueBytes, if positive, is the maximum size of an assembled
+	// value. Values that grow larger are discarded and Add returns an
+	// error.
+	MaxValueBytes int64
+
+	// MaxPending, if positive, is the maximum number of values that can
+	// be pending assembly. If a new value would exceed this limit, the
+	// value that has been pending the longest is discarded. This bounds
+	// memory used by orphaned chunks.
+	MaxPending int
+
+	mu      sync.Mutex
+	seq     uint64
+	pending map[chunkKey]*pendingChunks
+}
+
+type chunkKey struct {
+	topic     string
+	partition int32
+	id        string
+}
+
+type pendingChunks struct {
+	seq         uint64
+	firstOffset int64
+	total       uint32
+	bytes       int64
+	chunks      map[uint32][]byte
+	final       *Record
+	info        ChunkInfo
+}
+
+// Add adds a consumed record to the assembler. If the record is not a chunk,
+// it is returned as is. If the record completes a value, the assembled record
+// is returned. Otherwise, this returns nil.
+//
+// An error is returned if a completed value does not match its checksum, or
+// if a value exceeds MaxValueBytes. The value is discarded in either case.
+func (a *ChunkAssembler) Add(r *Record) (*Record, error) {
+	info, ok := ParseChunk(r)
+	if !ok {
+		return r, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	k := chunkKey{r.Topic, r.Partition, string(info.ID)}
+	p := a.pending[k]
+	if p == nil {
+		if a.pending == nil {
+			a.pending = make(map[chunkKey]*pendingChunks)
+		}
+		if a.MaxPending > 0 && len(a.pending) >= a.MaxPending {
+			a.evictOldest()
+		}
+		a.seq++
+		p = &pendingChunks{
+			seq:         a.seq,
+			firstOffset: r.Offset,
+			chunks:      make(map[uint32][]byte),
+		}
+		a.pending[k] = p
+	}
+	p.firstOffset = min(p.firstOffset, r.Offset)
+
+	if old, exists := p.chunks[info.Index]; exists {
+		p.bytes -= int64(len(old))
+	}
+	p.chunks[info.Index] = r.Value
+	p.bytes += int64(len(r.Value))
+	if a.MaxValueBytes > 0 && p.bytes > a.MaxValueBytes {
+		delete(a.pending, k)
+		return nil, fmt.Errorf("chunked value in topic %s partition %d is larger than the max %d bytes", r.Topic, r.Partition, a.MaxValueBytes)
+	}
+	if info.Total > 0 {
+		p.total = info.Total
+	}
+	if info.IsFinal() {
+		p.final, p.info = r, info
+	}
+	if p.final == nil || uint32(len(p.chunks)) < p.total {
+		return nil, nil
+	}
+
+	delete(a.pending, k)
+	value := make([]byte, 0, p.bytes)
+	for i := range p.total {
+		chunk, ok := p.chunks[i]
+		if !ok {
+			return nil, fmt.Errorf("chunked value in topic %s partition %d is missing chunk %d of %d", r.Topic, r.Partition, i, p.total)
+		}
+		value = append(value, chunk...)
+	}
+	if p.info.HasChecksum {
+		if got := crc32.Checksum(value, crc32c); got != p.info.Checksum {
+			return nil, fmt.Errorf("chunked value in topic %s partition %d has checksum %x != expected %x", r.Topic, r.Partition, got, p.info.Checksum)
+		}
+	}
+
+	assembled := *p.final
+	assembled.Value = value
+	assembled.Headers = nil
+	for _, h := range p.final.Headers {
+		if !strings.HasPrefix(h.Key, "kgo-chunk-") {
+			assembled.Headers = append(assembled.Headers, h)
+		}
+	}
+	if p.info.Keyless {
+		assembled.Key = nil
+	}
+	return &assembled, nil
+}
+
+// evictOldest discards the value that has been pending the longest. The
+// mutex must be held.
+func (a *ChunkAssembler) evictOldest() {
+	var (
+		oldest  chunkKey
+		seq     uint64
+		evicted bool
+	)
+	for k, p := range a.pending {
+		if !evicted || p.seq < seq {
+			oldest, seq, evicted = k, p.seq, true
+		}
+	}
+	delete(a.pending, oldest)
+}
+
+// Pending returns the number of values that are pending assembly.
+func (a *ChunkAssembler) Pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.pending)
+}
+
+// PendingFirstOffset returns the smallest offset of any chunk of any value
+// pending assembly in the given partition, and false if no values are
+// pending. Committing an offset at or before this offset ensures that pending
+// values are fully reconsumed after a restart.
+func (a *ChunkAssembler) PendingFirstOffset(topic string, partition int32) (int64, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	var (
+		first int64
+		found bool
+	)
+	for k, p := range a.pending {
+		if k.topic == topic && k.partition == partition && (!found || p.firstOffset < first) {
+			first, found = p.firstOffset, true
+		}
+	}
+	return first, found
+}
+
+// DropPartitions discards all values pending assembly in the given
+// partitions. This should be called when partitions are revoked or lost,
+// since the chunks will be consumed again by whichever member is assigned the
+// partition.
+func (a *ChunkAssembler) DropPartitions(partitions map[string][]int32) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for k := range a.pending {
+		if slices.Contains(partitions[k.topic], k.partition) {
+			delete(a.pending, k)
+		}
+	}
+}
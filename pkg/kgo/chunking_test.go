@@ -0,0 +1,204 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// testChunks splits value into chunk records as ProduceChunked would.
+func testChunks(value []byte, chunkSize int, key []byte, knownTotal bool) []*Record {
+	id := []byte("0123456789abcdef")
+	total := uint32(max((len(value)+chunkSize-1)/chunkSize, 1))
+	template := &Record{Topic: "t", Key: key, Headers: []RecordHeader{{"user", []byte("h")}}}
+
+	var chunks []*Record
+	for idx := range total {
+		chunkTotal := total
+		if !knownTotal && idx < total-1 {
+			chunkTotal = 0
+		}
+		c := newChunkRecord(template, id, idx, chunkTotal)
+		c.Value = value[int(idx)*chunkSize : min(int(idx+1)*chunkSize, len(value))]
+		c.Offset = int64(idx)
+		if idx == total-1 {
+			c.Headers = append(c.Headers, RecordHeader{ChunkChecksumHeader, binary.BigEndian.AppendUint32(nil, crc32.Checksum(value, crc32c))})
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
+
+func TestChunkAssembler(t *testing.T) {
+	t.Parallel()
+
+	value := make([]byte, 1000)
+	rand.New(rand.NewSource(0)).Read(value)
+
+	for _, test := range []struct {
+		name       string
+		key        []byte
+		knownTotal bool
+		shuffle    bool
+	}{
+		{"keyed", []byte("k"), true, false},
+		{"keyless", nil, true, false},
+		{"unknown_total", nil, false, false},
+		{"shuffled", []byte("k"), true, true},
+		{"shuffled_unknown_total", []byte("k"), false, true},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			chunks := testChunks(value, 64, test.key, test.knownTotal)
+			if test.shuffle {
+				rand.New(rand.NewSource(1)).Shuffle(len(chunks), func(i, j int) { chunks[i], chunks[j] = chunks[j], chunks[i] })
+			}
+			var a ChunkAssembler
+			var got *Record
+			for i, c := range chunks {
+				r, err := a.Add(c)
+				if err != nil {
+					t.Fatalf("chunk %d: unexpected err: %v", i, err)
+				}
+				if r != nil {
+					if i != len(chunks)-1 {
+						t.Fatalf("assembled after chunk %d of %d", i, len(chunks))
+					}
+					got = r
+				}
+			}
+			if got == nil {
+				t.Fatal("value was not assembled")
+			}
+			if !bytes.Equal(got.Value, value) {
+				t.Error("assembled value mismatch")
+			}
+			if !bytes.Equal(got.Key, test.key) {
+				t.Errorf("got key %q != exp %q", got.Key, test.key)
+			}
+			if len(got.Headers) != 1 || got.Headers[0].Key != "user" {
+				t.Errorf("got headers %v, expected only the user header", got.Headers)
+			}
+			if got.Offset != int64(len(chunks)-1) {
+				t.Errorf("got offset %d, expected the final chunk offset %d", got.Offset, len(chunks)-1)
+			}
+			if a.Pending() != 0 {
+				t.Errorf("got %d pending values after assembling", a.Pending())
+			}
+		})
+	}
+
+	t.Run("not_chunked", func(t *testing.T) {
+		var a ChunkAssembler
+		r := &Record{Value: []byte("v")}
+		if got, err := a.Add(r); got != r || err != nil {
+			t.Errorf("got %v, %v; expected the input record", got, err)
+		}
+	})
+
+	t.Run("checksum_mismatch", func(t *testing.T) {
+		var a ChunkAssembler
+		chunks := testChunks(value, 100, nil, true)
+		chunks[3].Value = []byte("corrupt")
+		var err error
+		for _, c := range chunks {
+			_, err = a.Add(c)
+		}
+		if err == nil {
+			t.Error("expected checksum error")
+		}
+	})
+
+	t.Run("max_value_bytes", func(t *testing.T) {
+		a := ChunkAssembler{MaxValueBytes: 500}
+		var err error
+		for _, c := range testChunks(value, 100, nil, true) {
+			if _, err = a.Add(c); err != nil {
+				break
+			}
+		}
+		if err == nil || a.Pending() != 0 {
+			t.Errorf("expected max bytes error and no pending values, got %v, %d pending", err, a.Pending())
+		}
+	})
+
+	t.Run("max_pending", func(t *testing.T) {
+		a := ChunkAssembler{MaxPending: 2}
+		for i := range 3 {
+			c := testChunks(value, 100, nil, true)[0]
+			c.Headers[2].Value = []byte{byte(i)} // unique ID
+			c.Offset = int64(i)
+			a.Add(c)
+		}
+		if a.Pending() != 2 {
+			t.Errorf("got %d pending != exp 2", a.Pending())
+		}
+		if first, ok := a.PendingFirstOffset("t", 0); !ok || first != 1 {
+			t.Errorf("got first pending offset %d, %v; expected the oldest value to be evicted", first, ok)
+		}
+		a.DropPartitions(map[string][]int32{"t": {0}})
+		if a.Pending() != 0 {
+			t.Errorf("got %d pending after dropping partitions", a.Pending())
+		}
+	})
+}
+
+func TestProduceChunked(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 3)
+	defer cleanup()
+
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	small := []byte("small")
+	big := make([]byte, 1<<20)
+	rand.New(rand.NewSource(0)).Read(big)
+
+	// A keyed value with a known length, a keyless value read through a
+	// reader without a length, and an empty value.
+	if err := cl.ProduceChunked(ctx, &Record{Key: []byte("big")}, bytes.NewReader(big), 64<<10); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if err := cl.ProduceChunked(ctx, &Record{}, io.MultiReader(bytes.NewReader(small)), 2); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if err := cl.ProduceChunked(ctx, &Record{Key: []byte("empty")}, bytes.NewReader(nil), 10); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+
+	var (
+		a   ChunkAssembler
+		got = make(map[string][]byte)
+	)
+	for len(got) < 3 {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only assembled %d values before timing out", len(got))
+		}
+		fs.EachRecord(func(r *Record) {
+			assembled, err := a.Add(r)
+			if err != nil {
+				t.Errorf("unable to assemble: %v", err)
+			}
+			if assembled != nil {
+				got[string(assembled.Key)] = assembled.Value
+			}
+		})
+	}
+	if !bytes.Equal(got["big"], big) || !bytes.Equal(got[""], small) || len(got["empty"]) != 0 {
+		t.Error("assembled values do not match produced values")
+	}
+}
@@ -0,0 +1,127 @@
+package kgo
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// ClaimCheckHeader is the record header that replaces an offloaded record
+// value when using [OffloadPayloads]. The header value is the reference
+// returned from [PayloadOffloader.StorePayload].
+const ClaimCheckHeader = "kgo-claim-check"
+
+// maxConcurrentRehydrates is the maximum number of payloads fetched at once
+// while rehydrating a poll.
+const maxConcurrentRehydrates = 16
+
+// PayloadOffloader stores and fetches large record values in external
+// storage (i.e., object storage), implementing the claim-check pattern for
+// [OffloadPayloads].
+type PayloadOffloader interface {
+	// StorePayload stores the record's value and returns a reference
+	// that can be used to fetch it. The reference is usually small, such
+	// as an object key.
+	StorePayload(ctx context.Context, r *Record) (ref []byte, err error)
+
+	// FetchPayload returns the value stored for a reference. The record
+	// is the consumed record, with a nil value, that contains the
+	// reference.
+	FetchPayload(ctx context.Context, r *Record, ref []byte) ([]byte, error)
+}
+
+// PayloadOffloaderFuncs implements PayloadOffloader with functions.
+type PayloadOffloaderFuncs struct {
+	// Store implements PayloadOffloader.StorePayload.
+	Store func(ctx context.Context, r *Record) (ref []byte, err error)
+	// Fetch implements PayloadOffloader.FetchPayload.
+	Fetch func(ctx context.Context, r *Record, ref []byte) ([]byte, error)
+}
+
+// StorePayload implements PayloadOffloader.
+func (f PayloadOffloaderFuncs) StorePayload(ctx context.Context, r *Record) ([]byte, error) {
+	return f.Store(ctx, r)
+}
+
+// FetchPayload implements PayloadOffloader.
+func (f PayloadOffloaderFuncs) FetchPayload(ctx context.Context, r *Record, ref []byte) ([]byte, error) {
+	return f.Fetch(ctx, r, ref)
+}
+
+// ClaimCheckRef returns the reference to an offloaded value in a record, and
+// false if the record's value was not offloaded. This is useful when
+// consuming with a client that does not use OffloadPayloads, or to retry
+// rehydrating a record that failed to rehydrate.
+func ClaimCheckRef(r *Record) ([]byte, bool) {
+	for _, h := range r.Headers {
+		if h.Key == ClaimCheckHeader {
+			return h.Value, true
+		}
+	}
+	return nil, false
+}
+
+// offloadPayload stores the record's value and replaces it with a claim check
+// header, returning a promise that restores the record's value and headers
+// before calling the original promise.
+func (cl *Client) offloadPayload(r *Record, promise func(*Record, error)) (func(*Record, error), error) {
+	ref, err := cl.cfg.offloader.StorePayload(r.Context, r)
+	if err != nil {
+		return promise, fmt.Errorf("unable to offload record value: %w", err)
+	}
+	value, headers := r.Value, r.Headers
+	r.Value = nil
+	r.Headers = append(slices.Clip(headers), RecordHeader{ClaimCheckHeader, ref})
+	return func(r *Record, err error) {
+		r.Value, r.Headers = value, headers
+		promise(r, err)
+	}, nil
+}
+
+// rehydratePayloads replaces the value of all records that have a claim check
+// header with the value fetched from the offloader. If a value cannot be
+// fetched, the record keeps its claim check header and nil value, and the
+// record's partition has its Err field set, if it is not already set.
+func (cl *Client) rehydratePayloads(ctx context.Context, fetches Fetches) {
+	if ctx == nil {
+		ctx = cl.ctx
+	}
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, maxConcurrentRehydrates)
+	)
+	for i := range fetches {
+		for j := range fetches[i].Topics {
+			t := &fetches[i].Topics[j]
+			for k := range t.Partitions {
+				p := &t.Partitions[k]
+				for _, r := range p.Records {
+					ref, ok := ClaimCheckRef(r)
+					if !ok {
+						continue
+					}
+					wg.Add(1)
+					sem <- struct{}{}
+					go func() {
+						defer func() { <-sem; wg.Done() }()
+						value, err := cl.cfg.offloader.FetchPayload(ctx, r, ref)
+						if err != nil {
+							cl.cfg.logger.Log(LogLevelWarn, "unable to rehydrate offloaded record value", "topic", r.Topic, "partition", r.Partition, "offset", r.Offset, "err", err)
+							mu.Lock()
+							if p.Err == nil {
+								p.Err = fmt.Errorf("unable to rehydrate offloaded value for record at offset %d: %w", r.Offset, err)
+							}
+							mu.Unlock()
+							return
+						}
+						r.Value = value
+						r.Headers = slices.DeleteFunc(r.Headers, func(h RecordHeader) bool { return h.Key == ClaimCheckHeader })
+					}()
+				}
+			}
+		}
+	}
+	wg.Wait()
+}
@@ -0,0 +1,113 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testOffloader is an in memory PayloadOffloader.
+type testOffloader struct {
+	mu     sync.Mutex
+	stored map[string][]byte
+	fail   bool
+}
+
+func (o *testOffloader) StorePayload(_ context.Context, r *Record) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.fail {
+		return nil, errors.New("store failed")
+	}
+	ref := []byte("ref-" + strconv.Itoa(len(o.stored)))
+	o.stored[string(ref)] = bytes.Clone(r.Value)
+	return ref, nil
+}
+
+func (o *testOffloader) FetchPayload(_ context.Context, _ *Record, ref []byte) ([]byte, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	v, ok := o.stored[string(ref)]
+	if !ok {
+		return nil, errors.New("unknown ref")
+	}
+	return v, nil
+}
+
+func TestOffloadPayloads(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	o := &testOffloader{stored: make(map[string][]byte)}
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		OffloadPayloads(o, 10),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	small := &Record{Value: []byte("small")}
+	large := &Record{Value: bytes.Repeat([]byte("large"), 100), Headers: []RecordHeader{{"h", nil}}}
+	if err := cl.ProduceSync(ctx, small, large).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if len(o.stored) != 1 {
+		t.Errorf("got %d stored values != exp 1", len(o.stored))
+	}
+	if len(large.Value) != 500 || len(large.Headers) != 1 {
+		t.Error("produced record was not restored before its promise was called")
+	}
+
+	// A failing store fails the record.
+	o.mu.Lock()
+	o.fail = true
+	o.mu.Unlock()
+	if err := cl.ProduceSync(ctx, &Record{Value: large.Value}).FirstErr(); err == nil {
+		t.Error("expected store error")
+	}
+
+	// A client without the offloader sees the claim check; ours
+	// transparently rehydrates.
+	raw, _ := newTestClient(ConsumeTopics(topic), ConsumeResetOffset(NewOffset().AtStart()))
+	defer raw.Close()
+	for _, c := range []struct {
+		cl        *Client
+		rehydrate bool
+	}{
+		{raw, false},
+		{cl, true},
+	} {
+		var got []*Record
+		for len(got) < 2 {
+			fs := c.cl.PollFetches(ctx)
+			if err := ctx.Err(); err != nil {
+				t.Fatal("timed out consuming")
+			}
+			if errs := fs.Errors(); len(errs) > 0 {
+				t.Fatalf("unexpected fetch errors: %v", errs)
+			}
+			got = append(got, fs.Records()...)
+		}
+		if !bytes.Equal(got[0].Value, small.Value) {
+			t.Errorf("rehydrate %v: small value changed", c.rehydrate)
+		}
+		ref, hasRef := ClaimCheckRef(got[1])
+		if c.rehydrate {
+			if hasRef || !bytes.Equal(got[1].Value, large.Value) || len(got[1].Headers) != 1 {
+				t.Error("large value was not rehydrated")
+			}
+		} else if !hasRef || len(got[1].Value) != 0 || o.stored[string(ref)] == nil {
+			t.Error("expected claim check reference in place of the large value")
+		}
+	}
+}
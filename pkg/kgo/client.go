@@ -31,13 +31,20 @@ import (
 	"github.com/twmb/franz-go/pkg/sasl"
 )
 
-var crc32c = crc32.MakeTable(crc32.Castagnoli) // record crc's use Castagnoli table; for consuming/producing
+// crc32c is used for record batch checksums when consuming and producing. This
+// must be the standard library's Castagnoli table: hash/crc32 recognizes it
+// and computes checksums with SSE4.2 on amd64 and the CRC32 instructions on
+// arm64, falling back to slicing-by-8 elsewhere.
+var crc32c = crc32.MakeTable(crc32.Castagnoli)
 
 // Client issues requests and handles responses to a Kafka cluster.
 type Client struct {
 	cfg  cfg
 	opts []Opt
 
+	reconfigureMu sync.Mutex
+	compression   atomic.Pointer[producerCompression] // non-nil once reconfigured, overriding the cfg compression
+
 	ctx       context.Context
 	ctxCancel func()
 
@@ -60,7 +67,10 @@ type Client struct {
 	sinksAndSources   map[int32]sinkAndSource
 
 	reqFormatter  *kmsg.RequestFormatter
+	keyClientIDs  map[int16]string                 // ClientIDFn overrides
+	keyFormatters map[int16]*kmsg.RequestFormatter // formatters for keyClientIDs
 	connTimeouter connTimeouter
+	resolver      *dnsResolver // non-nil if resolving hosts ourselves
 
 	bufPool bufPool // for to brokers to share underlying reusable request buffers
 	prsPool prsPool // for sinks to reuse []promisedNumberedRecord
@@ -83,6 +93,7 @@ type Client struct {
 	id2t     atomic.Value // map[[16]byte]string
 
 	metrics metrics
+	stats   clientStats
 
 	coordinatorsMu sync.Mutex
 	coordinators   map[coordinatorKey]*coordinatorLoad
@@ -91,7 +102,9 @@ type Client struct {
 	updateMetadataNowCh  chan string // like above, but with high priority
 	blockingMetadataFnCh chan func()
 	metawait             metawait
+	metaCache            metadataCache // only used in the metadata loop
 	metadone             chan struct{}
+	metaSnaps            [2]topicMetaSnap // producer, consumer; only used in the metadata loop
 
 	mappedMetaMu sync.Mutex
 	mappedMeta   map[string]mappedMetadataTopic
@@ -102,6 +115,49 @@ func (cl *Client) idempotent() bool { return !cl.cfg.disableIdempotency }
 type sinkAndSource struct {
 	sink   *sink
 	source *source
+
+	// shards are additional sources to the same broker, used with
+	// ConcurrentFetchesPerBroker. Partitions are spread across source and
+	// shards with sourceFor.
+	shards []*source
+}
+
+func (cl *Client) newSinkAndSource(nodeID int32) sinkAndSource {
+	sns := sinkAndSource{
+		sink:   cl.newSink(nodeID),
+		source: cl.newSource(nodeID, 0),
+	}
+	for i := 1; i < cl.cfg.fetchConcurrencyPerBroker; i++ {
+		sns.shards = append(sns.shards, cl.newSource(nodeID, i))
+	}
+	return sns
+}
+
+// sourceFor returns the source a partition is consumed from. We hash the
+// topic and add the partition so that a topic's partitions are spread evenly
+// across shards.
+func (sns sinkAndSource) sourceFor(topic string, partition int32) *source {
+	if len(sns.shards) == 0 {
+		return sns.source
+	}
+	h := uint32(2166136261) // fnv-1a
+	for i := 0; i < len(topic); i++ {
+		h ^= uint32(topic[i])
+		h *= 16777619
+	}
+	shard := (h + uint32(partition)) % uint32(len(sns.shards)+1)
+	if shard == 0 {
+		return sns.source
+	}
+	return sns.shards[shard-1]
+}
+
+// eachSource calls fn for the source and every shard.
+func (sns sinkAndSource) eachSource(fn func(*source)) {
+	fn(sns.source)
+	for _, s := range sns.shards {
+		fn(s)
+	}
 }
 
 func (cl *Client) allSinksAndSources(fn func(sns sinkAndSource)) {
@@ -124,6 +180,28 @@ func ValidateOpts(opts ...Opt) error {
 	return err
 }
 
+// ValidateOptsAll returns every problem with the given options, or nil if
+// there are none. ValidateOpts and NewClient fail with only the first invalid
+// option they find; this instead returns all invalid options, followed by
+// option combinations that NewClient allows but that have no effect, such as
+// BlockRebalanceOnPoll without a ConsumerGroup.
+func ValidateOptsAll(opts ...Opt) []error {
+	cfg := defaultCfg()
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	errs := cfg.validateAll()
+	if _, err := parseSeeds(cfg.seedBrokers); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.compressor == nil {
+		if _, err := DefaultCompressor(cfg.compression...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return append(errs, cfg.conflicts()...)
+}
+
 func parseSeeds(addrs []string) ([]hostport, error) {
 	seeds := make([]hostport, 0, len(addrs))
 	for _, seedBroker := range addrs {
@@ -147,6 +225,7 @@ func validateCfg(opts ...Opt) (cfg, []hostport, error) {
 	if err := cfg.validate(); err != nil {
 		return cfg, nil, err
 	}
+	cfg.clampMaxPartBytes()
 	seeds, err := parseSeeds(cfg.seedBrokers)
 	if err != nil {
 		return cfg, nil, err
@@ -243,17 +322,29 @@ func (cl *Client) OptValues(opt any) []any {
 			return []any{*cfg.id, true}
 		}
 		return []any{"", false}
+	case namefn(ClientIDFn):
+		return []any{cfg.idFn}
+	case namefn(RequestTagsFn):
+		return []any{cfg.requestTagsFn}
 	case namefn(SoftwareNameAndVersion):
 		return []any{cfg.softwareName, cfg.softwareVersion}
 	case namefn(WithLogger):
 		if _, wrapped := cfg.logger.(*wrappedLogger); wrapped {
-			return []any{cfg.logger.(*wrappedLogger).inner}
+			return []any{cfg.logger.(*wrappedLogger).load()}
 		}
 		return []any{nil}
+	case namefn(LogDedupInterval):
+		return []any{cfg.logDedupInterval}
 	case namefn(RequestTimeoutOverhead):
 		return []any{cfg.requestTimeoutOverhead}
+	case namefn(RequestClassTimeoutOverhead):
+		return []any{cfg.requestClassTimeoutOverheads}
+	case namefn(BrokerRequestTimeoutOverhead):
+		return []any{cfg.brokerTimeoutOverheads}
 	case namefn(ConnIdleTimeout):
 		return []any{cfg.connIdleTimeout}
+	case namefn(ConnMaxLifetime):
+		return []any{cfg.connMaxLifetime}
 	case namefn(Dialer):
 		return []any{cfg.dialFn}
 	case namefn(DialTLSConfig):
@@ -262,6 +353,25 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.dialTLS != nil}
 	case namefn(DialTimeout):
 		return []any{cfg.dialTimeout}
+	case namefn(HedgeBootstrapDials):
+		return []any{cfg.hedgeBootstrapStagger}
+	case namefn(TCPKeepAlive):
+		if cfg.tcpKeepAlive == nil {
+			return []any{net.KeepAliveConfig{}}
+		}
+		return []any{*cfg.tcpKeepAlive}
+	case namefn(TCPUserTimeout):
+		return []any{cfg.tcpUserTimeout}
+	case namefn(TCPBufferSizes):
+		return []any{cfg.tcpReadBuffer, cfg.tcpWriteBuffer}
+	case namefn(TCPNoDelay):
+		return []any{cfg.tcpNoDelay || !cfg.setTCPNoDelay}
+	case namefn(DNSLookupFn):
+		return []any{cfg.dnsLookupFn}
+	case namefn(DNSCacheTTL):
+		return []any{cfg.dnsCacheTTL}
+	case namefn(PreferIPFamily):
+		return []any{cfg.preferIPFamily}
 	case namefn(SeedBrokers):
 		return []any{cfg.seedBrokers}
 	case namefn(MaxVersions):
@@ -270,6 +380,8 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.minVersions}
 	case namefn(RetryBackoffFn):
 		return []any{cfg.retryBackoff}
+	case namefn(MetadataRetryBackoff):
+		return []any{cfg.metadataBackoff}
 	case namefn(RequestRetries):
 		return []any{cfg.retries}
 	case namefn(RetryTimeout):
@@ -283,13 +395,19 @@ func (cl *Client) OptValues(opt any) []any {
 	case namefn(BrokerMaxReadBytes):
 		return []any{cfg.maxBrokerReadBytes}
 	case namefn(MetadataMaxAge):
-		return []any{cfg.metadataMaxAge}
+		return []any{cfg.metadataMaxAge.load()}
+	case namefn(MetadataTopicIDs):
+		return []any{cfg.metadataTopicIDs}
 	case namefn(MetadataMinAge):
-		return []any{cfg.metadataMinAge}
+		return []any{cfg.metadataMinAge.load()}
 	case namefn(SASL):
 		return []any{cfg.sasls}
 	case namefn(WithHooks):
 		return []any{cfg.hooks}
+	case namefn(InjectFaults):
+		return []any{cfg.faults}
+	case namefn(OffloadPayloads):
+		return []any{cfg.offloader, cfg.offloadThreshold}
 	case namefn(WithPools):
 		return []any{cfg.pools}
 	case namefn(ConcurrentTransactionsBackoff):
@@ -309,6 +427,12 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.defaultProduceTopic}
 	case namefn(DefaultProduceTopicAlways):
 		return []any{cfg.defaultProduceTopicAlways}
+	case namefn(ProduceTopicAllowList):
+		return []any{cfg.produceAllowTopics}
+	case namefn(ValidateTopicMaxMessageBytes):
+		return []any{cfg.validateTopicMaxBytes}
+	case namefn(ProduceDryRun):
+		return []any{cfg.produceDryRun}
 	case namefn(RequiredAcks):
 		return []any{cfg.acks}
 	case namefn(DisableIdempotentWrite):
@@ -316,9 +440,12 @@ func (cl *Client) OptValues(opt any) []any {
 	case namefn(MaxProduceRequestsInflightPerBroker):
 		return []any{cfg.maxProduceInflight}
 	case namefn(ProducerBatchCompression):
+		if c := cl.compression.Load(); c != nil {
+			return []any{c.codecs}
+		}
 		return []any{cfg.compression}
 	case namefn(WithCompressor):
-		return []any{cfg.compressor}
+		return []any{cl.compressor()}
 	case namefn(ProducerBatchMaxBytes):
 		return []any{cfg.maxRecordBatchBytes}
 	case namefn(MaxBufferedRecords):
@@ -331,6 +458,8 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.produceTimeout}
 	case namefn(RecordRetries):
 		return []any{cfg.recordRetries}
+	case namefn(ProduceRetryBackoff):
+		return []any{cfg.produceBackoff}
 	case namefn(UnknownTopicRetries):
 		return []any{cfg.maxUnknownFailures}
 	case namefn(StopProducerOnDataLossDetected):
@@ -338,9 +467,21 @@ func (cl *Client) OptValues(opt any) []any {
 	case namefn(ProducerOnDataLossDetected):
 		return []any{cfg.onDataLoss}
 	case namefn(ProducerLinger):
-		return []any{cfg.linger}
+		return []any{cfg.linger.load()}
 	case namefn(ManualFlushing):
 		return []any{cfg.manualFlushing}
+	case namefn(RecordEndToEndTimestamps):
+		return []any{cfg.e2eTimestamps}
+	case namefn(RecordTimestamps):
+		return []any{cfg.timestampSource}
+	case namefn(OrderedProducePromises):
+		return []any{cfg.orderedPromises}
+	case namefn(VerifyAmbiguousProduces):
+		return []any{cfg.verifyAmbiguous}
+	case namefn(TeeProduce):
+		return []any{cfg.teeClient, cfg.teeTopics}
+	case namefn(TeeProduceSync):
+		return []any{cfg.teeSync}
 	case namefn(RecordDeliveryTimeout):
 		return []any{cfg.recordTimeout}
 	case namefn(TransactionalID):
@@ -348,6 +489,8 @@ func (cl *Client) OptValues(opt any) []any {
 			return []any{cfg.txnID, true}
 		}
 		return []any{"", false}
+	case namefn(TransactionalIDPerGroupMember):
+		return []any{cfg.txnIDPerMember}
 	case namefn(TransactionTimeout):
 		return []any{cfg.txnTimeout}
 
@@ -359,10 +502,20 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.decompressor}
 	case namefn(ConsumeRegex):
 		return []any{cfg.regex}
+	case namefn(ConsumeRegexUnmatchedTTL):
+		return []any{cfg.regexUnmatchedTTL}
+	case namefn(ConsumeRegexDropIdleTopics):
+		return []any{cfg.regexDropIdle, cfg.regexOnDropIdle}
 	case namefn(ConsumeStartOffset):
 		return []any{cfg.startOffset}
 	case namefn(ConsumeResetOffset):
 		return []any{cfg.resetOffset}
+	case namefn(ConsumeResetOffsetFn):
+		return []any{cfg.resetOffsetFn}
+	case namefn(ConsumeTopicAllowList):
+		return []any{cfg.consumeAllowTopics}
+	case namefn(ConsumeTopicMatcher):
+		return []any{cfg.topicMatcher}
 	case namefn(ConsumeTopics):
 		return []any{cfg.topics}
 	case namefn(DisableFetchSessions):
@@ -374,21 +527,39 @@ func (cl *Client) OptValues(opt any) []any {
 	case namefn(FetchMaxPartitionBytes):
 		return []any{int32(cfg.maxPartBytes)}
 	case namefn(FetchMaxWait):
-		return []any{time.Duration(cfg.maxWait) * time.Millisecond}
+		return []any{time.Duration(cfg.maxWait.load()) * time.Millisecond}
+	case namefn(FetchRetryBackoff):
+		return []any{cfg.fetchBackoff}
 	case namefn(FetchMinBytes):
 		return []any{cfg.minBytes}
 	case namefn(KeepControlRecords):
 		return []any{cfg.keepControl}
 	case namefn(MaxConcurrentFetches):
 		return []any{cfg.maxConcurrentFetches}
+	case namefn(ConcurrentFetchesPerBroker):
+		return []any{cfg.fetchConcurrencyPerBroker}
+	case namefn(FetchTargetBufferedBytes):
+		return []any{cfg.fetchTargetBufferedBytes}
+	case namefn(MaxPollRecords):
+		return []any{cfg.maxPollRecords}
+	case namefn(MaxPollBytes):
+		return []any{cfg.maxPollBytes}
 	case namefn(Rack):
 		return []any{cfg.rack}
 	case namefn(KeepRetryableFetchErrors):
 		return []any{cfg.keepRetryableFetchErrors}
+	case namefn(SuppressTransientFetchErrors):
+		return []any{cfg.suppressTransientErrors}
 	case namefn(DisableFetchCRCValidation):
 		return []any{cfg.disableFetchCRCValidation}
 	case namefn(RecheckPreferredReplicaInterval):
 		return []any{cfg.recheckPreferredReplicaInterval}
+	case namefn(MeasureEndToEndLatency):
+		return []any{cfg.e2eLatency}
+	case namefn(DisableOffsetEpochValidation):
+		return []any{cfg.disableOffsetEpochValidation}
+	case namefn(OffsetLoadPartitionsPerRequest):
+		return []any{cfg.offsetLoadPartitionsPerRequest}
 
 	case namefn(AdjustFetchOffsetsFn):
 		return []any{cfg.adjustOffsetsBeforeAssign}
@@ -398,8 +569,12 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.autocommitInterval}
 	case namefn(AutoCommitMarks):
 		return []any{cfg.autocommitMarks}
+	case namefn(RetryFencedCommits):
+		return []any{cfg.retryFencedCommits}
 	case namefn(Balancers):
 		return []any{cfg.balancers}
+	case namefn(AllowRebalanceAfter):
+		return []any{cfg.allowRebalanceAfter}
 	case namefn(BlockRebalanceOnPoll):
 		return []any{cfg.blockRebalanceOnPoll}
 	case namefn(ConsumerGroup):
@@ -408,10 +583,16 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.autocommitDisable}
 	case namefn(GreedyAutoCommit):
 		return []any{cfg.autocommitGreedy}
+	case namefn(GroupMemberCensus):
+		return []any{cfg.groupCensus}
 	case namefn(GroupProtocol):
 		return []any{cfg.protocol}
+	case namefn(PreferredPartitions):
+		return []any{cfg.preferredPartitions}
 	case namefn(HeartbeatInterval):
 		return []any{cfg.heartbeatInterval}
+	case namefn(GroupRetryBackoff):
+		return []any{cfg.groupBackoff}
 	case namefn(InstanceID):
 		if cfg.instanceID != nil {
 			return []any{*cfg.instanceID, true}
@@ -425,10 +606,18 @@ func (cl *Client) OptValues(opt any) []any {
 		return []any{cfg.onLost}
 	case namefn(OnPartitionsRevoked):
 		return []any{cfg.onRevoked}
+	case namefn(OnStandbyAssignment):
+		return []any{cfg.onStandby}
+	case namefn(OnPartitionsCheckpoint):
+		return []any{cfg.onCheckpoint}
+	case namefn(OnPartitionsRestore):
+		return []any{cfg.onRestore}
 	case namefn(RebalanceTimeout):
 		return []any{cfg.rebalanceTimeout}
 	case namefn(RequireStableFetchOffsets):
 		return []any{cfg.requireStable}
+	case namefn(WarmupJoinsGroup):
+		return []any{cfg.warmupJoinGroup}
 	case namefn(SessionTimeout):
 		return []any{cfg.sessionTimeout}
 
@@ -468,8 +657,10 @@ func NewClient(opts ...Opt) (*Client, error) {
 		}
 	}
 
+	var resolver *dnsResolver
 	if cfg.dialFn == nil {
 		dialer := &net.Dialer{Timeout: cfg.dialTimeout}
+		resolver = newDNSResolver(&cfg, dialer)
 		cfg.dialFn = dialer.DialContext
 		if cfg.dialTLS != nil {
 			cfg.dialFn = func(ctx context.Context, network, host string) (net.Conn, error) {
@@ -489,6 +680,10 @@ func NewClient(opts ...Opt) (*Client, error) {
 		}
 	}
 
+	if w, ok := cfg.logger.(*wrappedLogger); ok && cfg.logDedupInterval > 0 {
+		w.dedup = newLogDedup(cfg.logDedupInterval)
+	}
+
 	if cfg.setResetOffset && !cfg.setStartOffset {
 		cfg.startOffset = cfg.resetOffset
 	} else if cfg.setStartOffset && !cfg.setResetOffset {
@@ -523,8 +718,12 @@ func NewClient(opts ...Opt) (*Client, error) {
 
 		sinksAndSources: make(map[int32]sinkAndSource),
 
-		reqFormatter:  kmsg.NewRequestFormatter(),
-		connTimeouter: connTimeouter{def: cfg.requestTimeoutOverhead},
+		reqFormatter: kmsg.NewRequestFormatter(),
+		connTimeouter: connTimeouter{
+			def:     cfg.requestTimeoutOverhead,
+			classes: cfg.requestClassTimeoutOverheads,
+			brokers: cfg.brokerTimeoutOverheads,
+		},
 
 		bufPool: newBufPool(),
 		prsPool: newPrsPool(),
@@ -535,6 +734,11 @@ func NewClient(opts ...Opt) (*Client, error) {
 		updateMetadataNowCh:  make(chan string, 1),
 		blockingMetadataFnCh: make(chan func()),
 		metadone:             make(chan struct{}),
+
+		resolver: resolver,
+	}
+	if resolver != nil {
+		resolver.cl = cl
 	}
 
 	// Before we start any goroutines below, we must notify any interested
@@ -553,6 +757,16 @@ func NewClient(opts ...Opt) (*Client, error) {
 	if cfg.id != nil {
 		cl.reqFormatter = kmsg.NewRequestFormatter(kmsg.FormatterClientID(*cfg.id))
 	}
+	if cfg.idFn != nil {
+		cl.keyClientIDs = make(map[int16]string)
+		cl.keyFormatters = make(map[int16]*kmsg.RequestFormatter)
+		for key := int16(0); key <= kmsg.MaxKey; key++ {
+			if id := cfg.idFn(key); id != "" {
+				cl.keyClientIDs[key] = id
+				cl.keyFormatters[key] = kmsg.NewRequestFormatter(kmsg.FormatterClientID(id))
+			}
+		}
+	}
 
 	seedBrokers := make([]*broker, 0, len(seeds))
 	for i, seed := range seeds {
@@ -567,6 +781,69 @@ func NewClient(opts ...Opt) (*Client, error) {
 	return cl, nil
 }
 
+// formatterFor returns the request formatter to use for a request key, which
+// differs from the default formatter only if ClientIDFn overrides the client
+// ID for the key.
+func (cl *Client) formatterFor(key int16) *kmsg.RequestFormatter {
+	if f, ok := cl.keyFormatters[key]; ok {
+		return f
+	}
+	return cl.reqFormatter
+}
+
+// maxRequestTagsLen is the maximum length of tags we append to a client ID
+// with RequestTagsFn, including the leading semicolon.
+const maxRequestTagsLen = 256
+
+// taggedFormatterFor returns the request formatter to use for a request,
+// appending any RequestTagsFn tags to the request's client ID.
+func (cl *Client) taggedFormatterFor(ctx context.Context, key int16) *kmsg.RequestFormatter {
+	if cl.cfg.requestTagsFn == nil {
+		return cl.formatterFor(key)
+	}
+	if ctx == nil {
+		ctx = cl.ctx
+	}
+	tags := cl.cfg.requestTagsFn(ctx, key)
+	if len(tags) == 0 {
+		return cl.formatterFor(key)
+	}
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	if id := cl.clientIDFor(key); id != nil {
+		sb.WriteString(*id)
+	}
+	base := sb.Len()
+	for i, k := range keys {
+		if i == 0 {
+			sb.WriteByte(';')
+		} else {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(k)
+		sb.WriteByte('=')
+		sb.WriteString(tags[k])
+	}
+	id := sb.String()
+	if len(id)-base > maxRequestTagsLen {
+		id = id[:base+maxRequestTagsLen]
+	}
+	return kmsg.NewRequestFormatter(kmsg.FormatterClientID(id))
+}
+
+// clientIDFor returns the client ID used for a request key, if any.
+func (cl *Client) clientIDFor(key int16) *string {
+	if id, ok := cl.keyClientIDs[key]; ok {
+		return &id
+	}
+	return cl.cfg.id
+}
+
 // Opts returns the options that were used to create this client. This can be
 // as a base to generate a new client, where you can add override options to
 // the end of the original input list. If you want to know a specific option
@@ -575,6 +852,106 @@ func (cl *Client) Opts() []Opt {
 	return cl.opts
 }
 
+type producerCompression struct {
+	codecs     []CompressionCodec
+	compressor Compressor
+}
+
+func (cl *Client) compressor() Compressor {
+	if c := cl.compression.Load(); c != nil {
+		return c.compressor
+	}
+	return cl.cfg.compressor
+}
+
+// Reconfigure changes options on a running client, avoiding a client restart
+// to tune it. Only the following options can be changed:
+//
+//   - WithLogger, which can be used to change the log level
+//   - MetadataMaxAge and MetadataMinAge
+//   - ProducerLinger, ProducerBatchCompression, and WithCompressor
+//   - FetchMaxBytes, FetchMaxPartitionBytes, and FetchMaxWait
+//
+// If any other option is used, or if the new values are invalid in the same
+// way NewClient would reject them, this returns an error and nothing is
+// changed.
+//
+// New values take effect for work started after this returns: records that
+// are already lingering keep their prior linger, and in flight produce and
+// fetch requests keep their prior settings. Opts continues to return the
+// options the client was created with, while OptValue and OptValues return
+// the reconfigured values.
+func (cl *Client) Reconfigure(opts ...Opt) error {
+	for i, opt := range opts {
+		if _, ok := opt.(interface{ dynamic() }); !ok {
+			return fmt.Errorf("option %d cannot be changed on a running client", i)
+		}
+	}
+
+	cl.reconfigureMu.Lock()
+	defer cl.reconfigureMu.Unlock()
+
+	// We validate against a default config that contains the current
+	// dynamic values, as well as any static values dynamic values are
+	// checked against. This avoids touching any shared state that
+	// validating our actual config would modify.
+	probe := defaultCfg()
+	probe.metadataMaxAge = lazyDur(cl.cfg.metadataMaxAge.load())
+	probe.metadataMinAge = lazyDur(cl.cfg.metadataMinAge.load())
+	probe.linger = lazyDur(cl.cfg.linger.load())
+	probe.maxWait = lazyI32(cl.cfg.maxWait.load())
+	probe.maxBytes = lazyI32(cl.cfg.maxBytes.load())
+	probe.maxPartBytes = lazyI32(cl.cfg.maxPartBytes.load())
+	probe.maxBrokerReadBytes = cl.cfg.maxBrokerReadBytes
+	probe.logger = nil
+	probe.compression = nil
+	probe.compressor = nil
+
+	for _, opt := range opts {
+		opt.apply(&probe)
+	}
+	if err := probe.validate(); err != nil {
+		return err
+	}
+	probe.clampMaxPartBytes()
+
+	compression := cl.compression.Load()
+	switch {
+	case probe.compressor != nil:
+		codecs := cl.cfg.compression
+		if compression != nil {
+			codecs = compression.codecs
+		}
+		compression = &producerCompression{codecs, probe.compressor}
+	case probe.compression != nil:
+		compressor, err := DefaultCompressor(probe.compression...)
+		if err != nil {
+			return err
+		}
+		compression = &producerCompression{probe.compression, compressor}
+	}
+	if compression != nil {
+		cl.compression.Store(compression)
+	}
+
+	if l, ok := probe.logger.(*wrappedLogger); ok {
+		if w, ok := cl.cfg.logger.(*wrappedLogger); ok {
+			w.inner.Store(l.inner.Load())
+		}
+	}
+
+	cl.cfg.linger.store(probe.linger.load())
+	cl.cfg.maxWait.store(probe.maxWait.load())
+	cl.cfg.maxBytes.store(probe.maxBytes.load())
+	cl.cfg.maxPartBytes.store(probe.maxPartBytes.load())
+	cl.cfg.metadataMinAge.store(probe.metadataMinAge.load())
+	if age := probe.metadataMaxAge.load(); age != cl.cfg.metadataMaxAge.load() {
+		cl.cfg.metadataMaxAge.store(age)
+		cl.blockingMetadataFn(func() {}) // wake the metadata loop to reset its ticker
+	}
+	return nil
+}
+
 // Context returns the internal context used wherever possible in the client.
 // By default this is context.WithCancel(context.Background()). You may
 // override the background context with your own via [WithContext].
@@ -744,14 +1121,76 @@ func parseBrokerAddr(addr string) (hostport, error) {
 	return hostport{h, int32(port)}, nil
 }
 
+// RequestClass is a class of requests that can have its own timeout overhead,
+// for use in RequestClassTimeoutOverhead.
+type RequestClass uint8
+
+const (
+	// RequestClassProduce is produce requests.
+	RequestClassProduce RequestClass = iota
+	// RequestClassFetch is fetch, list offsets, and offset for leader
+	// epoch requests.
+	RequestClassFetch
+	// RequestClassMetadata is metadata requests.
+	RequestClassMetadata
+	// RequestClassGroup is requests issued to group coordinators: finding
+	// the coordinator, joining, syncing, heartbeating, leaving, and
+	// committing and fetching offsets.
+	RequestClassGroup
+	// RequestClassAdmin is every other request, with the exception of
+	// ApiVersions and SASL requests, which are issued while initializing
+	// a connection and always use RequestTimeoutOverhead.
+	RequestClassAdmin
+)
+
+// requestClass returns the class of a request key, or false if the request
+// is part of initializing a connection.
+func requestClass(key int16) (RequestClass, bool) {
+	switch key {
+	case 0:
+		return RequestClassProduce, true
+	case 1, 2, 23: // fetch, list offsets, offset for leader epoch
+		return RequestClassFetch, true
+	case 3:
+		return RequestClassMetadata, true
+	case 8, 9, 10, 11, 12, 13, 14, 68: // offset commit & fetch, find coordinator, join, heartbeat, leave, sync, consumer group heartbeat
+		return RequestClassGroup, true
+	case 17, 18, 36: // sasl handshake, api versions, sasl authenticate
+		return 0, false
+	default:
+		return RequestClassAdmin, true
+	}
+}
+
 type connTimeouter struct {
-	def                  time.Duration
+	def     time.Duration
+	classes map[RequestClass]time.Duration
+	brokers map[int32]time.Duration
+
 	joinMu               sync.Mutex
 	lastRebalanceTimeout time.Duration
 }
 
-func (c *connTimeouter) timeouts(req kmsg.Request) (r, w time.Duration) {
-	def := c.def
+// overhead returns the timeout overhead to use for a request to the given
+// broker: a broker override takes precedence over a request class override,
+// which takes precedence over RequestTimeoutOverhead. Requests that initialize
+// a connection have no class and always use RequestTimeoutOverhead.
+func (c *connTimeouter) overhead(node int32, key int16) time.Duration {
+	class, ok := requestClass(key)
+	if !ok {
+		return c.def
+	}
+	if overhead, ok := c.brokers[node]; ok {
+		return overhead
+	}
+	if overhead, ok := c.classes[class]; ok {
+		return overhead
+	}
+	return c.def
+}
+
+func (c *connTimeouter) timeouts(node int32, req kmsg.Request) (r, w time.Duration) {
+	def := c.overhead(node, req.Key())
 	millis := func(m int32) time.Duration { return time.Duration(m) * time.Millisecond }
 	switch t := req.(type) {
 	default:
@@ -961,20 +1400,79 @@ func (cl *Client) fetchBrokerMetadata(ctx context.Context) error {
 }
 
 func (cl *Client) fetchMetadataForTopics(ctx context.Context, all bool, topics []string, intoMapped map[string]mappedMetadataTopic) (*broker, *kmsg.MetadataResponse, error) {
-	req := kmsg.NewPtrMetadataRequest()
-	req.AllowAutoTopicCreation = cl.cfg.allowAutoTopicCreation
-	if all {
-		req.Topics = nil
-	} else if len(topics) == 0 {
-		req.Topics = []kmsg.MetadataRequestTopic{}
-	} else {
-		for _, topic := range topics {
-			reqTopic := kmsg.NewMetadataRequestTopic()
+	return cl.fetchMetadataForTopicIDs(ctx, all, topics, nil, intoMapped)
+}
+
+// fetchMetadataForTopicIDs is fetchMetadataForTopics, but requests topics
+// that are in ids by topic ID. Response topics that are missing a name (which
+// Kafka does for unknown topic IDs) have their name filled in from ids.
+//
+// Brokers only return the ID topics of a request that contains any topic ID,
+// so topics that are not in ids are requested by name in a separate request.
+// Both responses are merged into one.
+func (cl *Client) fetchMetadataForTopicIDs(ctx context.Context, all bool, topics []string, ids map[string][16]byte, intoMapped map[string]mappedMetadataTopic) (*broker, *kmsg.MetadataResponse, error) {
+	newReq := func() *kmsg.MetadataRequest {
+		req := kmsg.NewPtrMetadataRequest()
+		req.AllowAutoTopicCreation = cl.cfg.allowAutoTopicCreation
+		return req
+	}
+	if all || len(topics) == 0 {
+		req := newReq()
+		if !all {
+			req.Topics = []kmsg.MetadataRequestTopic{}
+		}
+		return cl.fetchMetadata(ctx, req, true, intoMapped)
+	}
+
+	var idReq, nameReq *kmsg.MetadataRequest
+	for _, topic := range topics {
+		reqTopic := kmsg.NewMetadataRequestTopic()
+		req := &nameReq
+		if id, ok := ids[topic]; ok {
+			reqTopic.TopicID = id
+			req = &idReq
+		} else {
 			reqTopic.Topic = kmsg.StringPtr(topic)
-			req.Topics = append(req.Topics, reqTopic)
 		}
+		if *req == nil {
+			*req = newReq()
+		}
+		(*req).Topics = append((*req).Topics, reqTopic)
 	}
-	return cl.fetchMetadata(ctx, req, true, intoMapped)
+
+	var (
+		b    *broker
+		meta *kmsg.MetadataResponse
+	)
+	if idReq != nil {
+		idB, idMeta, err := cl.fetchMetadata(ctx, idReq, true, intoMapped)
+		if err != nil {
+			return idB, idMeta, err
+		}
+		id2t := make(map[[16]byte]string, len(ids))
+		for t, id := range ids {
+			id2t[id] = t
+		}
+		for i := range idMeta.Topics {
+			if rt := &idMeta.Topics[i]; rt.Topic == nil {
+				if t, ok := id2t[rt.TopicID]; ok {
+					rt.Topic = kmsg.StringPtr(t)
+				}
+			}
+		}
+		b, meta = idB, idMeta
+	}
+	if nameReq != nil {
+		nameB, nameMeta, err := cl.fetchMetadata(ctx, nameReq, true, intoMapped)
+		if err != nil {
+			return nameB, nameMeta, err
+		}
+		if meta != nil {
+			nameMeta.Topics = append(meta.Topics, nameMeta.Topics...)
+		}
+		b, meta = nameB, nameMeta
+	}
+	return b, meta, nil
 }
 
 func (cl *Client) fetchMetadata(ctx context.Context, req *kmsg.MetadataRequest, limitRetries bool, intoMapped map[string]mappedMetadataTopic) (*broker, *kmsg.MetadataResponse, error) {
@@ -995,7 +1493,18 @@ start:
 		r.limitRetries = 3
 	}
 
-	meta, err := req.RequestWith(ctx, r)
+	var (
+		meta *kmsg.MetadataResponse
+		err  error
+	)
+	if hedged, hedgedMeta, hedgeErr := cl.maybeHedgeBootstrap(ctx, req); hedged != nil {
+		r.last, meta = hedged, hedgedMeta
+	} else {
+		if hedgeErr != nil {
+			cl.cfg.logger.Log(LogLevelInfo, "hedged bootstrap metadata request to all seeds failed, falling back to issuing to seeds in order", "err", hedgeErr)
+		}
+		meta, err = req.RequestWith(ctx, r)
+	}
 	if err == nil {
 		if err = kerr.ErrorForCode(meta.ErrorCode); !rebootstrapped && errors.Is(err, kerr.RebootstrapRequired) && cl.cfg.onRebootstrapRequired != nil {
 			var seeds []string
@@ -1017,6 +1526,85 @@ start:
 	return r.last, meta, err
 }
 
+// maybeHedgeBootstrap, if the client is hedging bootstrap dials and has not
+// yet discovered any broker, issues the metadata request to every seed broker
+// concurrently, staggering each new seed by the configured delay. This
+// returns the first seed to successfully reply. If not hedging, this returns
+// a nil broker and nil error.
+//
+// Seeds are tried early if a prior seed fails, similar to RFC 8305 (happy
+// eyeballs). Once a seed replies, the requests to all other seeds are
+// canceled.
+func (cl *Client) maybeHedgeBootstrap(ctx context.Context, req *kmsg.MetadataRequest) (*broker, *kmsg.MetadataResponse, error) {
+	if !cl.cfg.hedgeBootstrap {
+		return nil, nil, nil
+	}
+	seeds := cl.loadSeeds()
+	cl.brokersMu.RLock()
+	discovered := len(cl.brokers) > 0
+	cl.brokersMu.RUnlock()
+	if discovered || len(seeds) < 2 {
+		return nil, nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		b    *broker
+		resp kmsg.Response
+		err  error
+	}
+	results := make(chan result, len(seeds))
+
+	var (
+		next     int
+		inflight int
+		lastErr  error
+		stagger  = time.NewTimer(0)
+	)
+	stagger.Stop()
+	defer stagger.Stop()
+
+	launch := func() {
+		b := seeds[next]
+		next++
+		inflight++
+		// Issuing a request sets its version; we copy the request so
+		// that concurrent requests do not race.
+		req := *req
+		go func() {
+			resp, err := b.waitResp(ctx, &req)
+			results <- result{b, resp, err}
+		}()
+		if next < len(seeds) {
+			stagger.Reset(cl.cfg.hedgeBootstrapStagger)
+		}
+	}
+
+	launch()
+	for inflight > 0 || next < len(seeds) {
+		select {
+		case <-stagger.C:
+			launch()
+		case r := <-results:
+			inflight--
+			if r.err == nil {
+				cl.cfg.logger.Log(LogLevelDebug, "hedged bootstrap metadata request succeeded", "seed", r.b.addr)
+				return r.b, r.resp.(*kmsg.MetadataResponse), nil
+			}
+			lastErr = r.err
+			if next < len(seeds) {
+				stagger.Stop()
+				launch()
+			}
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+	return nil, nil, lastErr
+}
+
 func (cl *Client) updateMetadataBrokers(resp *kmsg.MetadataResponse) {
 	cl.controllerIDMu.Lock()
 	if resp.ControllerID >= 0 {
@@ -1144,14 +1732,15 @@ func (cl *Client) close(ctx context.Context) (rerr error) {
 	sessCloseCtx, sessCloseCancel := context.WithTimeout(ctx, time.Second)
 	var wg sync.WaitGroup
 	cl.allSinksAndSources(func(sns sinkAndSource) {
-		if sns.source.session.id != 0 {
-			sns := sns
-			wg.Add(1)
-			go func() {
-				defer wg.Done()
-				sns.source.killSessionOnClose(sessCloseCtx)
-			}()
-		}
+		sns.eachSource(func(s *source) {
+			if s.session.id != 0 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					s.killSessionOnClose(sessCloseCtx)
+				}()
+			}
+		})
 	})
 	wg.Wait()
 	sessCloseCancel()
@@ -1189,8 +1778,8 @@ func (cl *Client) close(ctx context.Context) (rerr error) {
 	<-cl.metadone
 
 	for _, sns := range cl.sinksAndSources {
-		sns.sink.maybeDrain()     // awaken anything in backoff
-		sns.source.maybeConsume() // same
+		sns.sink.maybeDrain()                  // awaken anything in backoff
+		sns.eachSource((*source).maybeConsume) // same
 	}
 
 	cl.failBufferedRecords(ErrClientClosed)
@@ -1251,6 +1840,7 @@ func (cl *Client) close(ctx context.Context) (rerr error) {
 //	ListTransactions
 //	ConsumerGroupDescribe
 //	ShareGroupDescribe
+//	DescribeShareGroupOffsets
 //
 // Kafka 3.0 introduced batch OffsetFetch and batch FindCoordinator requests.
 // This function is forward and backward compatible: old requests will be
@@ -1258,7 +1848,8 @@ func (cl *Client) close(ctx context.Context) (rerr error) {
 // recommended to always use batch requests for simplicity.
 //
 // In short, this method tries to do the correct thing depending on what type
-// of request is being issued.
+// of request is being issued. To send a request somewhere other than where
+// this method would, see RequestRouted and RequestAll.
 //
 // The passed context can be used to cancel a request and return early. Note
 // that if the request was written to Kafka but the context canceled before a
@@ -1291,6 +1882,10 @@ func (cl *Client) Request(ctx context.Context, req kmsg.Request) (kmsg.Response,
 // package all require metadata to run; those functions use cached metadata
 // as much as possible.
 //
+// If the request's Topics is nil, all topics are requested. The cache cannot
+// know whether it contains every topic, so requesting all topics always
+// issues a metadata request (and caches the result).
+//
 // This function does *not* return authorized operations, even if the request
 // has IncludeClusterAuthorizedOperations or IncludeTopicAuthorizedOperations
 // set to true. This function cannot be used to request topics via TopicID;
@@ -1303,7 +1898,16 @@ func (cl *Client) RequestCachedMetadata(ctx context.Context, req *kmsg.MetadataR
 		}
 		topics = append(topics, *t.Topic)
 	}
-	mapped, err := cl.fetchMappedMetadata(ctx, topics, true, limit)
+	var (
+		mapped map[string]mappedMetadataTopic
+		err    error
+	)
+	if req.Topics == nil {
+		mapped = make(map[string]mappedMetadataTopic)
+		_, _, err = cl.fetchMetadataForTopics(ctx, true, nil, mapped)
+	} else {
+		mapped, err = cl.fetchMappedMetadata(ctx, topics, true, limit)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1434,13 +2038,14 @@ start:
 
 	if err != nil || retryErr != nil {
 		if r.limitRetries == 0 || tries <= r.limitRetries {
-			backoff := r.cl.cfg.retryBackoff(tries)
+			backoff := r.cl.cfg.backoff(backoffKindForKey(req.Key()), tries)
 			if retryTimeout == 0 || time.Now().Add(backoff).Sub(tryStart) <= retryTimeout {
 				// If this broker / request had a retryable error, we can
 				// just retry now. If the error is *not* retryable but
 				// is a broker-specific network error, and the next
 				// broker is different than the current, we also retry.
 				if r.cl.shouldRetry(tries, err) || r.cl.shouldRetry(tries, retryErr) {
+					r.cl.stats.retries.Add(1)
 					r.cl.cfg.logger.Log(LogLevelDebug, "retrying request",
 						"request", kmsg.NameForKey(req.Key()),
 						"tries", tries,
@@ -1558,25 +2163,26 @@ func (cl *Client) shardedRequest(ctx context.Context, req kmsg.Request) ([]Respo
 	// to fall into the handleCoordinatorReq logic.
 	switch t := req.(type) {
 	case *kmsg.ListOffsetsRequest, // key 2
-		*kmsg.OffsetFetchRequest,             // key 9
-		*kmsg.FindCoordinatorRequest,         // key 10
-		*kmsg.DescribeGroupsRequest,          // key 15
-		*kmsg.ListGroupsRequest,              // key 16
-		*kmsg.DeleteRecordsRequest,           // key 21
-		*kmsg.OffsetForLeaderEpochRequest,    // key 23
-		*kmsg.AddPartitionsToTxnRequest,      // key 24
-		*kmsg.WriteTxnMarkersRequest,         // key 27
-		*kmsg.DescribeConfigsRequest,         // key 32
-		*kmsg.AlterConfigsRequest,            // key 33
-		*kmsg.AlterReplicaLogDirsRequest,     // key 34
-		*kmsg.DescribeLogDirsRequest,         // key 35
-		*kmsg.DeleteGroupsRequest,            // key 42
-		*kmsg.IncrementalAlterConfigsRequest, // key 44
-		*kmsg.DescribeProducersRequest,       // key 61
-		*kmsg.DescribeTransactionsRequest,    // key 65
-		*kmsg.ListTransactionsRequest,        // key 66
-		*kmsg.ConsumerGroupDescribeRequest,   // key 69
-		*kmsg.ShareGroupDescribeRequest:      // key 77
+		*kmsg.OffsetFetchRequest,               // key 9
+		*kmsg.FindCoordinatorRequest,           // key 10
+		*kmsg.DescribeGroupsRequest,            // key 15
+		*kmsg.ListGroupsRequest,                // key 16
+		*kmsg.DeleteRecordsRequest,             // key 21
+		*kmsg.OffsetForLeaderEpochRequest,      // key 23
+		*kmsg.AddPartitionsToTxnRequest,        // key 24
+		*kmsg.WriteTxnMarkersRequest,           // key 27
+		*kmsg.DescribeConfigsRequest,           // key 32
+		*kmsg.AlterConfigsRequest,              // key 33
+		*kmsg.AlterReplicaLogDirsRequest,       // key 34
+		*kmsg.DescribeLogDirsRequest,           // key 35
+		*kmsg.DeleteGroupsRequest,              // key 42
+		*kmsg.IncrementalAlterConfigsRequest,   // key 44
+		*kmsg.DescribeProducersRequest,         // key 61
+		*kmsg.DescribeTransactionsRequest,      // key 65
+		*kmsg.ListTransactionsRequest,          // key 66
+		*kmsg.ConsumerGroupDescribeRequest,     // key 69
+		*kmsg.ShareGroupDescribeRequest,        // key 77
+		*kmsg.DescribeShareGroupOffsetsRequest: // key 90
 		return cl.handleShardedReq(ctx, req)
 
 	case *kmsg.MetadataRequest:
@@ -1589,7 +2195,12 @@ func (cl *Client) shardedRequest(ctx context.Context, req kmsg.Request) ([]Respo
 		return shards(cl.handleAdminReq(ctx, t)), nil
 
 	case kmsg.GroupCoordinatorRequest,
-		kmsg.TxnCoordinatorRequest:
+		kmsg.TxnCoordinatorRequest,
+		// The share group requests below go to the group coordinator,
+		// but are not marked as group coordinator requests in kmsg.
+		*kmsg.ShareGroupHeartbeatRequest,     // key 76
+		*kmsg.AlterShareGroupOffsetsRequest,  // key 91
+		*kmsg.DeleteShareGroupOffsetsRequest: // key 92
 		return shards(cl.handleCoordinatorReq(ctx, t)), nil
 
 	case *kmsg.ApiVersionsRequest:
@@ -1640,6 +2251,7 @@ func findBroker(candidates []*broker, node int32) *broker {
 // metadata load once before failing. If the metadata load fails, this returns
 // that error.
 func (cl *Client) brokerOrErr(ctx context.Context, id int32, err error) (*broker, error) {
+	err = &ErrBrokerDown{id, err}
 	if id < 0 {
 		return nil, err
 	}
@@ -2079,16 +2691,27 @@ func (cl *Client) handleCoordinatorReq(ctx context.Context, req kmsg.Request) Re
 		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.Group, req)
 	case *kmsg.OffsetDeleteRequest:
 		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.Group, req)
+	case *kmsg.AlterShareGroupOffsetsRequest:
+		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.GroupID, req)
+	case *kmsg.DeleteShareGroupOffsetsRequest:
+		return cl.handleCoordinatorReqSimple(ctx, coordinatorTypeGroup, t.GroupID, req)
 
-	// ConsumerGroupHeartbeat cannot be retried at all
+	// ConsumerGroupHeartbeat and ShareGroupHeartbeat cannot be retried at all
 	case *kmsg.ConsumerGroupHeartbeatRequest:
-		br, err := cl.loadCoordinator(ctx, coordinatorTypeGroup, t.Group)
-		var resp kmsg.Response
-		if err == nil {
-			resp, err = br.waitResp(ctx, req)
-		}
-		return shard(br, req, resp, err)
+		return cl.handleCoordinatorReqNoRetry(ctx, t.Group, req)
+	case *kmsg.ShareGroupHeartbeatRequest:
+		return cl.handleCoordinatorReqNoRetry(ctx, t.GroupID, req)
+	}
+}
+
+// handleCoordinatorReqNoRetry issues a group request to its coordinator once.
+func (cl *Client) handleCoordinatorReqNoRetry(ctx context.Context, group string, req kmsg.Request) ResponseShard {
+	br, err := cl.loadCoordinator(ctx, coordinatorTypeGroup, group)
+	var resp kmsg.Response
+	if err == nil {
+		resp, err = br.waitResp(ctx, req)
 	}
+	return shard(br, req, resp, err)
 }
 
 // handleCoordinatorReqSimple issues a request that contains a single group or
@@ -2152,10 +2775,15 @@ func (cl *Client) handleReqWithCoordinator(
 			code = t.ErrorCode
 		case *kmsg.ConsumerGroupHeartbeatResponse:
 			code = t.ErrorCode
+		case *kmsg.AlterShareGroupOffsetsResponse:
+			code = t.ErrorCode
+		case *kmsg.DeleteShareGroupOffsetsResponse:
+			code = t.ErrorCode
 		}
 
-		// ListGroups, OffsetFetch, DeleteGroups, DescribeGroups, and
-		// DescribeTransactions handled in sharding.
+		// ListGroups, OffsetFetch, DeleteGroups, DescribeGroups,
+		// DescribeTransactions, and DescribeShareGroupOffsets handled
+		// in sharding.
 
 		if err := kerr.ErrorForCode(code); cl.maybeDeleteStaleCoordinator(name, typ, err) {
 			return err
@@ -2395,8 +3023,14 @@ func (cl *Client) handleShardedReq(ctx context.Context, req kmsg.Request) ([]Res
 		sharder = &consumerGroupDescribeSharder{cl}
 	case *kmsg.ShareGroupDescribeRequest:
 		sharder = &shareGroupDescribeSharder{cl}
+	case *kmsg.DescribeShareGroupOffsetsRequest:
+		sharder = &describeShareGroupOffsetsSharder{cl}
 	}
+	return cl.issueShardedReq(ctx, req, sharder)
+}
 
+// issueShardedReq issues a request split by the given sharder.
+func (cl *Client) issueShardedReq(ctx context.Context, req kmsg.Request, sharder sharder) ([]ResponseShard, shardMerge) {
 	// If a request fails, we re-shard it (in case it needs to be split
 	// again). reqTry tracks how many total tries a request piece has had;
 	// we quit at either the max configured tries or max configured time.
@@ -2513,7 +3147,7 @@ func (cl *Client) handleShardedReq(ctx context.Context, req kmsg.Request) ([]Res
 				// immediately. The request was not even issued. However, as a
 				// safety, we only do this 3 times to avoid some super weird
 				// pathological spin loop.
-				backoff := cl.cfg.retryBackoff(tries)
+				backoff := cl.cfg.backoff(backoffKindForKey(myIssue.req.Key()), tries)
 				if err != nil &&
 					(reshardable && isPinned && errors.Is(err, errBrokerTooOld) && tries <= 3) ||
 					(retryTimeout == 0 || time.Now().Add(backoff).Sub(start) <= retryTimeout) && cl.shouldRetry(tries, err) && cl.waitTries(ctx, backoff) && !noRetries {
@@ -2625,8 +3259,8 @@ func (cl *Client) maybeDeleteMappedMetadata(unknownTopic bool, ts ...string) (sh
 	var min time.Duration
 	if unknownTopic {
 		min = time.Second
-		if cl.cfg.metadataMinAge < min {
-			min = cl.cfg.metadataMinAge
+		if cl.cfg.metadataMinAge.load() < min {
+			min = cl.cfg.metadataMinAge.load()
 		}
 	}
 
@@ -2659,7 +3293,7 @@ func (cl *Client) fetchCachedMappedMetadata(limit time.Duration, ts ...string) (
 	needed := ts[:0]
 
 	if limit <= 0 {
-		limit = cl.cfg.metadataMinAge
+		limit = cl.cfg.metadataMinAge.load()
 	}
 
 	for _, t := range ts {
@@ -2729,7 +3363,7 @@ func (cl *Client) storeCachedMappedMetadata(meta *kmsg.MetadataResponse, intoMap
 			if mapped.when.Equal(when) {
 				continue
 			}
-			if now.Sub(mapped.when) > cl.cfg.metadataMinAge {
+			if now.Sub(mapped.when) > cl.cfg.metadataMinAge.load() {
 				delete(cl.mappedMeta, topic)
 			}
 		}
@@ -3854,9 +4488,10 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 	}
 
 	type pidEpochCommit struct {
-		pid    int64
-		epoch  int16
-		commit bool
+		pid        int64
+		epoch      int16
+		commit     bool
+		coordEpoch int32
 	}
 
 	brokerReqs := make(map[int32]map[pidEpochCommit]map[string][]int32)
@@ -3894,6 +4529,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			marker.ProducerID,
 			marker.ProducerEpoch,
 			marker.Committed,
+			marker.CoordinatorEpoch,
 		}
 		for _, topic := range marker.Topics {
 			t := topic.Topic
@@ -3929,6 +4565,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			rm.ProducerID = pec.pid
 			rm.ProducerEpoch = pec.epoch
 			rm.Committed = pec.commit
+			rm.CoordinatorEpoch = pec.coordEpoch
 			for topic, parts := range topics {
 				rt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
 				rt.Topic = topic
@@ -3950,6 +4587,7 @@ func (cl *writeTxnMarkersSharder) shard(ctx context.Context, kreq kmsg.Request,
 			rm.ProducerID = pec.pid
 			rm.ProducerEpoch = pec.epoch
 			rm.Committed = pec.commit
+			rm.CoordinatorEpoch = pec.coordEpoch
 			for topic, parts := range topics {
 				rt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
 				rt.Topic = topic
@@ -4964,3 +5602,88 @@ func (*shareGroupDescribeSharder) merge(sresps []ResponseShard) (kmsg.Response,
 		merged.Groups = append(merged.Groups, resp.Groups...)
 	})
 }
+
+// handles sharding DescribeShareGroupOffsetsRequest
+type describeShareGroupOffsetsSharder struct{ *Client }
+
+func (cl *describeShareGroupOffsetsSharder) shard(ctx context.Context, kreq kmsg.Request, _ error) ([]issueShard, bool, error) {
+	req := kreq.(*kmsg.DescribeShareGroupOffsetsRequest)
+	groupIDs := make([]string, 0, len(req.Groups))
+	for i := range req.Groups {
+		groupIDs = append(groupIDs, req.Groups[i].GroupID)
+	}
+	coordinators := cl.loadCoordinators(ctx, coordinatorTypeGroup, groupIDs...)
+	type unkerr struct {
+		err   error
+		group kmsg.DescribeShareGroupOffsetsRequestGroup
+	}
+	var (
+		brokerReqs = make(map[int32]*kmsg.DescribeShareGroupOffsetsRequest)
+		kerrs      = make(map[*kerr.Error][]kmsg.DescribeShareGroupOffsetsRequestGroup)
+		unkerrs    []unkerr
+	)
+	newReq := func(groups ...kmsg.DescribeShareGroupOffsetsRequestGroup) *kmsg.DescribeShareGroupOffsetsRequest {
+		newReq := kmsg.NewPtrDescribeShareGroupOffsetsRequest()
+		newReq.Groups = groups
+		return newReq
+	}
+	for _, group := range req.Groups {
+		berr := coordinators[group.GroupID]
+		var ke *kerr.Error
+		switch {
+		case berr.err == nil:
+			brokerReq := brokerReqs[berr.b.meta.NodeID]
+			if brokerReq == nil {
+				brokerReq = newReq()
+				brokerReqs[berr.b.meta.NodeID] = brokerReq
+			}
+			brokerReq.Groups = append(brokerReq.Groups, group)
+		case errors.As(berr.err, &ke):
+			kerrs[ke] = append(kerrs[ke], group)
+		default:
+			unkerrs = append(unkerrs, unkerr{berr.err, group})
+		}
+	}
+	var issues []issueShard
+	for id, req := range brokerReqs {
+		issues = append(issues, issueShard{
+			req:    req,
+			broker: id,
+		})
+	}
+	for _, unkerr := range unkerrs {
+		issues = append(issues, issueShard{
+			req: newReq(unkerr.group),
+			err: unkerr.err,
+		})
+	}
+	for kerr, groups := range kerrs {
+		issues = append(issues, issueShard{
+			req: newReq(groups...),
+			err: kerr,
+		})
+	}
+	return issues, true, nil // reshardable to load correct coordinators
+}
+
+func (cl *describeShareGroupOffsetsSharder) onResp(_ kmsg.Request, kresp kmsg.Response) error {
+	resp := kresp.(*kmsg.DescribeShareGroupOffsetsResponse)
+	var retErr error
+	for i := range resp.Groups {
+		group := &resp.Groups[i]
+		err := kerr.ErrorForCode(group.ErrorCode)
+		cl.maybeDeleteStaleCoordinator(group.GroupID, coordinatorTypeGroup, err)
+		onRespShardErr(&retErr, err)
+	}
+	return retErr
+}
+
+func (*describeShareGroupOffsetsSharder) merge(sresps []ResponseShard) (kmsg.Response, error) {
+	merged := kmsg.NewPtrDescribeShareGroupOffsetsResponse()
+	return merged, firstErrMerger(sresps, func(kresp kmsg.Response) {
+		resp := kresp.(*kmsg.DescribeShareGroupOffsetsResponse)
+		merged.Version = resp.Version
+		merged.ThrottleMillis = resp.ThrottleMillis
+		merged.Groups = append(merged.Groups, resp.Groups...)
+	})
+}
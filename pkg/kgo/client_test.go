@@ -1,12 +1,24 @@
 package kgo
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"os"
 	"reflect"
+	"slices"
 	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -109,6 +121,632 @@ func TestParseBrokerAddrErrors(t *testing.T) {
 	}
 }
 
+func TestClientIDFn(t *testing.T) {
+	cl, err := NewClient(
+		ClientID("default"),
+		ClientIDFn(func(key int16) string {
+			if key == int16(kmsg.Produce) {
+				return "producer"
+			}
+			return ""
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	for _, test := range []struct {
+		req kmsg.Request
+		exp string
+	}{
+		{kmsg.NewPtrProduceRequest(), "producer"},
+		{kmsg.NewPtrFetchRequest(), "default"},
+	} {
+		raw := cl.formatterFor(test.req.Key()).AppendRequest(nil, test.req, 0)
+		r := kbin.Reader{Src: raw[12:]} // skip length, key, version, correlation ID
+		if got := r.String(); got != test.exp {
+			t.Errorf("%s: got client ID %q != exp %q", kmsg.NameForKey(test.req.Key()), got, test.exp)
+		}
+		if got := *cl.clientIDFor(test.req.Key()); got != test.exp {
+			t.Errorf("%s: got clientIDFor %q != exp %q", kmsg.NameForKey(test.req.Key()), got, test.exp)
+		}
+	}
+
+	if _, err := NewClient(ClientIDFn(func(int16) string { return strings.Repeat("a", 257) })); err == nil {
+		t.Error("expected error for too long client ID")
+	}
+}
+
+func TestRequestTagsFn(t *testing.T) {
+	type tenantKey struct{}
+	cl, err := NewClient(
+		ClientID("cid"),
+		RequestTagsFn(func(ctx context.Context, key int16) map[string]string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			if tenant == "" || key != int16(kmsg.Metadata) {
+				return nil
+			}
+			return map[string]string{"tenant": tenant, "job": "j"}
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "t1")
+	for _, test := range []struct {
+		ctx context.Context
+		req kmsg.Request
+		exp string
+	}{
+		{ctx, kmsg.NewPtrMetadataRequest(), "cid;job=j,tenant=t1"},
+		{ctx, kmsg.NewPtrFetchRequest(), "cid"},
+		{nil, kmsg.NewPtrMetadataRequest(), "cid"},
+	} {
+		raw := cl.taggedFormatterFor(test.ctx, test.req.Key()).AppendRequest(nil, test.req, 0)
+		r := kbin.Reader{Src: raw[12:]} // skip length, key, version, correlation ID
+		if got := r.String(); got != test.exp {
+			t.Errorf("%s: got client ID %q != exp %q", kmsg.NameForKey(test.req.Key()), got, test.exp)
+		}
+	}
+}
+
+func TestValidateOpts(t *testing.T) {
+	t.Parallel()
+
+	if errs := ValidateOptsAll(ConsumerGroup("g"), ConsumeTopics("t")); errs != nil {
+		t.Errorf("unexpected errors for valid options: %v", errs)
+	}
+
+	errs := ValidateOptsAll(
+		TransactionalID("txn"),
+		RequiredAcks(LeaderAck()),
+		ConsumerGroup("g"),
+		ConsumePartitions(map[string]map[int32]Offset{"t": {0: NewOffset()}}),
+		DisableAutoCommit(),
+		GreedyAutoCommit(),
+		AutoCommitInterval(time.Second),
+	)
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	exp := []string{
+		"transactional producing requires acks=all",
+		"invalid direct-partition consuming option when consuming as a group",
+		"cannot both disable autocommitting and enable greedy autocommitting",
+		"AutoCommitInterval has no effect when autocommitting is disabled",
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got errors\n%s\n!= exp\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	}
+
+	if errs := ValidateOptsAll(BlockRebalanceOnPoll(), SeedBrokers("host:port")); len(errs) != 2 {
+		t.Errorf("got %d errors != exp 2 (invalid seed, BlockRebalanceOnPoll without a group): %v", len(errs), errs)
+	}
+	if err := ValidateOpts(DisableAutoCommit(), GreedyAutoCommit(), SeedBrokers("host:port")); err == nil ||
+		err.Error() != "cannot both disable autocommitting and enable greedy autocommitting" {
+		t.Errorf("got ValidateOpts err %v != exp the first invalid option", err)
+	}
+	if err := ValidateOpts(BlockRebalanceOnPoll()); err != nil {
+		t.Errorf("ValidateOpts unexpectedly failed on an ineffective option combination: %v", err)
+	}
+
+	cl, err := NewClient(BlockRebalanceOnPoll())
+	if err != nil {
+		t.Fatalf("NewClient unexpectedly failed on an ineffective option combination: %v", err)
+	}
+	cl.Close()
+}
+
+func TestReconfigure(t *testing.T) {
+	t.Parallel()
+
+	cl, err := NewClient(ProducerLinger(time.Millisecond), MetadataMaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	if err := cl.Reconfigure(ConsumeTopics("foo")); err == nil {
+		t.Error("unexpected success reconfiguring a static option")
+	}
+	if err := cl.Reconfigure(FetchMaxWait(time.Second), ProducerLinger(2*time.Minute)); err == nil {
+		t.Error("unexpected success reconfiguring to an invalid linger")
+	}
+	if wait := cl.OptValue(FetchMaxWait); wait != 5*time.Second {
+		t.Errorf("failed reconfigure changed max wait to %v", wait)
+	}
+
+	if lvl := cl.cfg.logger.Level(); lvl != LogLevelNone {
+		t.Errorf("got default log level %v != exp none", lvl)
+	}
+	if err := cl.Reconfigure(
+		ProducerLinger(50*time.Millisecond),
+		FetchMaxWait(time.Second),
+		FetchMaxBytes(1<<20),
+		MetadataMaxAge(30*time.Second),
+		ProducerBatchCompression(ZstdCompression()),
+		WithLogger(BasicLogger(io.Discard, LogLevelDebug, nil)),
+	); err != nil {
+		t.Fatalf("unexpected reconfigure error: %v", err)
+	}
+	for _, test := range []struct {
+		opt any
+		exp any
+	}{
+		{ProducerLinger, 50 * time.Millisecond},
+		{FetchMaxWait, time.Second},
+		{FetchMaxBytes, int32(1 << 20)},
+		{FetchMaxPartitionBytes, int32(1 << 20)}, // clamped to max bytes
+		{MetadataMaxAge, 30 * time.Second},
+		{ProducerBatchCompression, []CompressionCodec{ZstdCompression()}},
+	} {
+		if got := cl.OptValue(test.opt); !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("%s: got %v != exp %v", namefn(test.opt), got, test.exp)
+		}
+	}
+	if lvl := cl.cfg.logger.Level(); lvl != LogLevelDebug {
+		t.Errorf("got reconfigured log level %v != exp debug", lvl)
+	}
+	if _, codec := cl.compressor().Compress(new(bytes.Buffer), []byte("foo")); codec != CodecZstd {
+		t.Errorf("got reconfigured compression codec %v != exp zstd", codec)
+	}
+}
+
+func TestRequestTimeoutOverheads(t *testing.T) {
+	cl, _ := NewClient(
+		RequestTimeoutOverhead(5*time.Second),
+		RequestClassTimeoutOverhead(RequestClassProduce, time.Second),
+		RequestClassTimeoutOverhead(RequestClassMetadata, 30*time.Second),
+		BrokerRequestTimeoutOverhead(3, time.Minute),
+	)
+	defer cl.Close()
+
+	for _, test := range []struct {
+		node int32
+		req  kmsg.Request
+		exp  time.Duration
+	}{
+		{1, kmsg.NewPtrProduceRequest(), time.Second},
+		{1, kmsg.NewPtrMetadataRequest(), 30 * time.Second},
+		{1, kmsg.NewPtrHeartbeatRequest(), 5 * time.Second},
+		{1, kmsg.NewPtrApiVersionsRequest(), 5 * time.Second},
+		{3, kmsg.NewPtrProduceRequest(), time.Minute},
+		{3, kmsg.NewPtrMetadataRequest(), time.Minute},
+		{3, kmsg.NewPtrApiVersionsRequest(), 5 * time.Second}, // connection initialization ignores broker overrides
+		{3, kmsg.NewPtrSASLHandshakeRequest(), 5 * time.Second},
+	} {
+		if _, w := cl.connTimeouter.timeouts(test.node, test.req); w != test.exp {
+			t.Errorf("node %d %T: got write timeout %v != exp %v", test.node, test.req, w, test.exp)
+		}
+	}
+
+	if errs := ValidateOptsAll(RequestClassTimeoutOverhead(RequestClassFetch, time.Millisecond)); len(errs) != 1 {
+		t.Errorf("got %d errors != exp 1 (request class overhead too small): %v", len(errs), errs)
+	}
+}
+
+func TestMetadataTopicIDs(t *testing.T) {
+	t.Parallel()
+
+	t1, cleanup1 := tmpTopicPartitions(t, 2)
+	defer cleanup1()
+	t2, cleanup2 := tmpTopicPartitions(t, 2)
+	defer cleanup2()
+
+	cl, _ := newTestClient(MetadataTopicIDs())
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	produce := func() {
+		t.Helper()
+		for _, topic := range []string{t1, t2} {
+			if err := cl.ProduceSync(ctx, &Record{Topic: topic, Value: []byte("v")}).FirstErr(); err != nil {
+				t.Fatalf("unable to produce to %s: %v", topic, err)
+			}
+		}
+	}
+	produce()
+
+	// The first metadata request is by name; once the topics are cached,
+	// a refresh requests them by ID.
+	cl.ForceMetadataRefresh()
+	produce()
+
+	var (
+		supported bool
+		ids       map[string][16]byte
+	)
+	cl.blockingMetadataFn(func() {
+		supported = cl.supportsMetadataTopicIDs()
+		ids = maps.Clone(cl.metaCache.ids)
+	})
+	if !supported {
+		t.Skip("brokers do not support metadata requests by topic ID")
+	}
+	id2t := cl.id2tMap()
+	for _, topic := range []string{t1, t2} {
+		if id, ok := ids[topic]; !ok || id2t[id] != topic {
+			t.Fatalf("topic %s is not cached by its ID, cached: %v", topic, ids)
+		}
+	}
+
+	// An incremental update only requests t1, and t2 remains known from
+	// the cache.
+	var err error
+	cl.blockingMetadataFn(func() {
+		_, err = cl.updateMetadata(map[string]struct{}{t1: {}})
+	})
+	if err != nil {
+		t.Fatalf("unable to incrementally update metadata: %v", err)
+	}
+	id2t = cl.id2tMap()
+	if id2t[ids[t1]] != t1 || id2t[ids[t2]] != t2 {
+		t.Fatalf("incremental update lost topics, id2t: %v", id2t)
+	}
+	produce()
+
+	// A request for topics that are cached by ID and a topic that is not
+	// must return all of them: brokers only answer the ID topics of a
+	// request that uses IDs, so names must be requested separately.
+	t3, cleanup3 := tmpTopicPartitions(t, 2)
+	defer cleanup3()
+	var meta *kmsg.MetadataResponse
+	cl.blockingMetadataFn(func() {
+		_, meta, err = cl.fetchMetadataForTopicIDs(ctx, false, []string{t1, t2, t3}, ids, nil)
+	})
+	if err != nil {
+		t.Fatalf("unable to fetch metadata by ID and name: %v", err)
+	}
+	got := make(map[string]int16)
+	for _, rt := range meta.Topics {
+		if rt.Topic != nil {
+			got[*rt.Topic] = rt.ErrorCode
+		}
+	}
+	for _, topic := range []string{t1, t2, t3} {
+		if code, ok := got[topic]; !ok || code != 0 {
+			t.Errorf("topic %s: got %v, %d in mixed ID and name metadata, exp no error", topic, ok, code)
+		}
+	}
+	if err := cl.ProduceSync(ctx, &Record{Topic: t3, Value: []byte("v")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce to %s: %v", t3, err)
+	}
+	produce()
+}
+
+type connCountHook struct{ connects, disconnects atomicI64 }
+
+func (h *connCountHook) OnBrokerConnect(_ BrokerMetadata, _ time.Duration, _ net.Conn, err error) {
+	if err == nil {
+		h.connects.Add(1)
+	}
+}
+
+func (h *connCountHook) OnBrokerDisconnect(BrokerMetadata, net.Conn) {
+	h.disconnects.Add(1)
+}
+
+func TestConnMaxLifetime(t *testing.T) {
+	t.Parallel()
+
+	if errs := ValidateOptsAll(ConnMaxLifetime(-time.Second)); len(errs) != 1 {
+		t.Errorf("got %d errors != exp 1 (negative lifetime): %v", len(errs), errs)
+	}
+
+	hook := new(connCountHook)
+	cl, _ := newTestClient(ConnMaxLifetime(100*time.Millisecond), WithHooks(hook))
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Requests continually issued across the lifetime boundary all
+	// succeed: old connections are drained, not killed.
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := time.Now(); time.Since(start) < time.Second; {
+				if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl); err != nil {
+					t.Errorf("unable to request metadata: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	connects, disconnects := hook.connects.Load(), hook.disconnects.Load()
+	if connects < 5 {
+		t.Errorf("got %d connects, exp connections to be cycled at least 5 times", connects)
+	}
+	if disconnects < connects/2 { // the latest connections are still live
+		t.Errorf("got %d disconnects with %d connects, exp retired connections to be closed", disconnects, connects)
+	}
+}
+
+func TestConnMaxLifetimeProduce(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	hook := new(recoveryHook)
+	cl, _ := newTestClient(
+		ConnMaxLifetime(50*time.Millisecond),
+		DefaultProduceTopic(topic),
+		WithHooks(hook),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Idempotent produce requests pipelined across the lifetime boundary
+	// must all land in order: the produce connection is never cycled
+	// while requests are inflight.
+	var (
+		mu      sync.Mutex
+		offsets []int64
+	)
+	for start := time.Now(); time.Since(start) < time.Second; {
+		cl.Produce(ctx, StringRecord("v"), func(r *Record, err error) {
+			if err != nil {
+				t.Errorf("unable to produce: %v", err)
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			offsets = append(offsets, r.Offset)
+		})
+		time.Sleep(100 * time.Microsecond)
+	}
+	if err := cl.Flush(ctx); err != nil {
+		t.Fatalf("unable to flush: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, o := range offsets {
+		if o != int64(i) {
+			t.Fatalf("got offset %d at index %d, exp records produced in order", o, i)
+		}
+	}
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.rs) != 0 {
+		t.Errorf("got unexpected producer ID recoveries %v", hook.rs)
+	}
+}
+
+func TestRequestCachedMetadataAllTopics(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopic(t)
+	defer cleanup()
+
+	cl, _ := newTestClient()
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	// Caching metadata for no topics must not short circuit a later
+	// request for all topics.
+	if _, err := cl.RequestCachedMetadata(ctx, &kmsg.MetadataRequest{Topics: []kmsg.MetadataRequestTopic{}}, 0); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := cl.RequestCachedMetadata(ctx, kmsg.NewPtrMetadataRequest(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(resp.Topics, func(rt kmsg.MetadataResponseTopic) bool { return rt.Topic != nil && *rt.Topic == topic }) {
+		t.Errorf("all topics cached metadata response is missing topic %s", topic)
+	}
+}
+
+func TestWriteTxnMarkersShardCoordinatorEpoch(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 2)
+	defer cleanup()
+
+	cl, _ := newTestClient()
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	req := kmsg.NewPtrWriteTxnMarkersRequest()
+	for i, coordEpoch := range []int32{3, 7} {
+		rm := kmsg.NewWriteTxnMarkersRequestMarker()
+		rm.ProducerID = 1
+		rm.CoordinatorEpoch = coordEpoch
+		rt := kmsg.NewWriteTxnMarkersRequestMarkerTopic()
+		rt.Topic = topic
+		rt.Partitions = []int32{int32(i)}
+		rm.Topics = append(rm.Topics, rt)
+		req.Markers = append(req.Markers, rm)
+	}
+
+	issues, _, err := (&writeTxnMarkersSharder{cl}).shard(ctx, req, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Markers that differ only in coordinator epoch must not be merged,
+	// and each must keep its coordinator epoch.
+	got := make(map[int32]int32) // partition => coordinator epoch
+	for _, issue := range issues {
+		if issue.err != nil {
+			t.Fatalf("unexpected shard error: %v", issue.err)
+		}
+		for _, rm := range issue.req.(*kmsg.WriteTxnMarkersRequest).Markers {
+			for _, rt := range rm.Topics {
+				for _, p := range rt.Partitions {
+					got[p] = rm.CoordinatorEpoch
+				}
+			}
+		}
+	}
+	if exp := map[int32]int32{0: 3, 1: 7}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got partition coordinator epochs %v != exp %v", got, exp)
+	}
+}
+
+func TestOptsFromConfigMap(t *testing.T) {
+	t.Parallel()
+
+	opts, unmapped, err := OptsFromConfigMap(map[string]string{
+		"bootstrap.servers":             "a:9092, b:9092",
+		"group.id":                      "g",
+		"compression.type":              "zstd",
+		"fetch.min.bytes":               "100",
+		"fetch.wait.max.ms":             "250",
+		"linger.ms":                     "20",
+		"acks":                          "1",
+		"max.in.flight":                 "3",
+		"auto.offset.reset":             "earliest",
+		"enable.auto.commit":            "false",
+		"partition.assignment.strategy": "org.apache.kafka.clients.consumer.CooperativeStickyAssignor,range",
+		"ssl.ca.location":               "/etc/ca.pem",
+		"unknown.property":              "x",
+	})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if exp := []string{"ssl.ca.location", "unknown.property"}; !reflect.DeepEqual(unmapped, exp) {
+		t.Errorf("got unmapped %v != exp %v", unmapped, exp)
+	}
+	if errs := ValidateOptsAll(opts...); errs != nil {
+		t.Fatalf("translated options are invalid: %v", errs)
+	}
+
+	cfg, _, err := validateCfg(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.seedBrokers, []string{"a:9092", "b:9092"}) ||
+		cfg.group != "g" ||
+		!reflect.DeepEqual(cfg.compression, []CompressionCodec{ZstdCompression()}) ||
+		cfg.minBytes != 100 ||
+		cfg.maxWait != 250 ||
+		cfg.linger != lazyDur(20*time.Millisecond) ||
+		cfg.acks.val != 1 ||
+		!cfg.disableIdempotency ||
+		cfg.maxProduceInflight != 3 ||
+		cfg.resetOffset != NewOffset().AtStart() ||
+		!cfg.autocommitDisable ||
+		len(cfg.balancers) != 2 || cfg.balancers[0].ProtocolName() != "cooperative-sticky" || cfg.balancers[1].ProtocolName() != "range" {
+		t.Errorf("unexpected translated config")
+	}
+
+	for _, m := range []map[string]string{
+		{"linger.ms": "soon"},
+		{"compression.type": "brotli"},
+		{"acks": "2"},
+		{"security.protocol": "SASL_SSL", "sasl.mechanism": "GSSAPI"},
+	} {
+		if _, _, err := OptsFromConfigMap(m); err == nil {
+			t.Errorf("%v: unexpected success", m)
+		}
+	}
+
+	opts, _, err = OptsFromConfigMap(map[string]string{"acks": "1", "enable.idempotence": "true"})
+	if err != nil {
+		t.Fatalf("unexpected err: %v", err)
+	}
+	if ValidateOpts(opts...) == nil {
+		t.Error("unexpected valid options with explicit idempotency and acks=1")
+	}
+}
+
+func TestLogDedup(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	w := newWrappedLogger(BasicLogger(&buf, LogLevelDebug, nil))
+	w.dedup = newLogDedup(50 * time.Millisecond)
+
+	for range 5 {
+		w.Log(LogLevelWarn, "request failed", "broker", 1, "err", "eof")
+		w.Log(LogLevelWarn, "request failed", "broker", 2, "err", "eof")
+		w.Log(LogLevelInfo, "not deduplicated")
+	}
+	if got := strings.Count(buf.String(), "request failed"); got != 2 {
+		t.Errorf("got %d request failed logs, exp 2 (one per broker):\n%s", got, buf.String())
+	}
+	if got := strings.Count(buf.String(), "not deduplicated"); got != 5 {
+		t.Errorf("got %d info logs, exp 5", got)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	buf.Reset()
+	w.Log(LogLevelWarn, "request failed", "broker", 1, "err", "eof")
+	out := buf.String()
+	if !strings.Contains(out, "suppressed repeated log messages") || !strings.Contains(out, "suppressed: 4") {
+		t.Errorf("missing suppressed summary:\n%s", out)
+	}
+	if !strings.Contains(out, "[WARN] request failed") {
+		t.Errorf("missing repeated log after interval:\n%s", out)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	cl, _ := newTestClient(ConsumeTopics(topic))
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for range 3 {
+		if err := cl.ProduceSync(ctx, &Record{Topic: topic, Value: []byte("v")}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+	var consumed int
+	for consumed < 3 {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+		consumed += fs.NumRecords()
+	}
+
+	m := cl.Metrics()
+	tm := m.Topics[topic]
+	if tm.ProducedRecords != 3 || tm.ProducedBatches != 3 || tm.ProducedBytes == 0 {
+		t.Errorf("unexpected produce metrics: %+v", tm)
+	}
+	if tm.ConsumedRecords != 3 || tm.ConsumedBytes == 0 {
+		t.Errorf("unexpected consume metrics: %+v", tm)
+	}
+	var produced, reqs int64
+	for _, bm := range m.Brokers {
+		produced += bm.ProducedRecords
+		reqs += bm.Requests
+		if bm.Requests > 0 && (bm.BytesWritten == 0 || bm.RequestLatency == 0 || bm.MaxRequestLatency > bm.RequestLatency) {
+			t.Errorf("unexpected broker metrics: %+v", bm)
+		}
+	}
+	if produced != 3 || reqs == 0 {
+		t.Errorf("got %d produced records and %d requests across brokers, exp 3 and > 0", produced, reqs)
+	}
+	if m.Group.Group != "" || m.Group.Generation != -1 {
+		t.Errorf("unexpected group metrics for non-group client: %+v", m.Group)
+	}
+}
+
 func TestUnknownGroupOffsetFetchPinned(t *testing.T) {
 	req := kmsg.NewOffsetFetchRequest()
 	req.Group = "unknown-" + strconv.FormatInt(time.Now().UnixNano(), 10)
@@ -257,3 +895,977 @@ func TestIssue1034(t *testing.T) {
 		t.Errorf("coordinator key responses missing: %v", need)
 	}
 }
+
+func TestRequestRouted(t *testing.T) {
+	t.Parallel()
+
+	cl, _ := newTestClient()
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	meta, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl)
+	if err != nil {
+		t.Fatalf("unable to request metadata: %v", err)
+	}
+
+	shards := cl.RequestAll(ctx, kmsg.NewPtrApiVersionsRequest())
+	if len(shards) != len(meta.Brokers) {
+		t.Fatalf("got %d shards != exp %d brokers", len(shards), len(meta.Brokers))
+	}
+	for i, shard := range shards {
+		if shard.Err != nil {
+			t.Errorf("shard %d: unexpected err: %v", i, shard.Err)
+		}
+		if i > 0 && shard.Meta.NodeID <= shards[i-1].Meta.NodeID {
+			t.Errorf("shards not sorted by distinct broker IDs: %d after %d", shard.Meta.NodeID, shards[i-1].Meta.NodeID)
+		}
+	}
+
+	var merged int
+	if _, err := cl.RequestRouted(ctx, RouteAllBrokers(func(shards []ResponseShard) (kmsg.Response, error) {
+		merged = len(shards)
+		return shards[0].Resp, shards[0].Err
+	}), kmsg.NewPtrApiVersionsRequest()); err != nil || merged != len(meta.Brokers) {
+		t.Errorf("all brokers: got %d merged shards, err %v", merged, err)
+	}
+
+	for _, route := range []RequestRoute{
+		RouteController(),
+		RouteGroupCoordinator("foo"),
+		RouteTxnCoordinator("foo"),
+		RouteBroker(meta.Brokers[0].NodeID),
+	} {
+		if _, err := cl.RequestRouted(ctx, route, kmsg.NewPtrApiVersionsRequest()); err != nil {
+			t.Errorf("route %v: unexpected err: %v", route.kind, err)
+		}
+	}
+	if _, err := cl.RequestRouted(ctx, RouteBroker(1<<30), kmsg.NewPtrApiVersionsRequest()); err == nil {
+		t.Error("expected error routing to an unknown broker")
+	}
+}
+
+func TestTypedBrokerErrors(t *testing.T) {
+	cl, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	_, err = cl.brokerOrErr(nil, 5, errUnknownBroker)
+	var bd *ErrBrokerDown
+	if !errors.As(err, &bd) || bd.Broker != 5 {
+		t.Fatalf("got %v, expected ErrBrokerDown for broker 5", err)
+	}
+	if !errors.Is(err, errUnknownBroker) || !isSkippableBrokerErr(err) {
+		t.Errorf("ErrBrokerDown %v does not unwrap to errUnknownBroker", err)
+	}
+
+	b := cl.newBroker(3, "localhost", 9092, nil)
+	if err := b.errDead(); !errors.As(err, &bd) || bd.Broker != 3 || !isRetryableBrokerErr(err) {
+		t.Errorf("got %v, expected retryable ErrBrokerDown for broker 3", err)
+	}
+
+	var tooOld error = &ErrBrokerTooOld{Broker: 1, Key: int16(kmsg.DescribeQuorum)}
+	var to *ErrBrokerTooOld
+	if !errors.Is(tooOld, errBrokerTooOld) || !errors.As(tooOld, &to) || to.Key != int16(kmsg.DescribeQuorum) {
+		t.Errorf("ErrBrokerTooOld %v is not errBrokerTooOld", tooOld)
+	}
+
+	var gs error = &ErrGroupSession{Err: kerr.FencedInstanceID, Reason: "heartbeating"}
+	if !errors.Is(gs, kerr.FencedInstanceID) || !strings.Contains(gs.Error(), "heartbeating") {
+		t.Errorf("ErrGroupSession %v does not include its error and reason", gs)
+	}
+}
+
+func TestHedgeBootstrapDials(t *testing.T) {
+	t.Parallel()
+
+	// Our first seed accepts connections but never replies.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	seeds := append([]string{ln.Addr().String()}, strings.Split(os.Getenv("KGO_SEEDS"), ",")...)
+	if seeds[1] == "" {
+		seeds[1] = "127.0.0.1:9092"
+	}
+	opts := append(testClientOpts(), SeedBrokers(seeds...), HedgeBootstrapDials(50*time.Millisecond))
+	cl, err := NewClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := kmsg.NewPtrMetadataRequest().RequestWith(ctx, cl); err != nil {
+		t.Fatalf("unable to issue hedged metadata request: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("hedged metadata request took %v, expected it to not wait on the unresponsive seed", elapsed)
+	}
+}
+
+func TestWarmup(t *testing.T) {
+	t.Parallel()
+
+	produceTopic, produceCleanup := tmpTopicPartitions(t, 3)
+	defer produceCleanup()
+	consumeTopic, consumeCleanup := tmpTopicPartitions(t, 2)
+	defer consumeCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	cl, _ := newTestClient(
+		ConsumeTopics(consumeTopic),
+		ConsumerGroup(group),
+		WarmupJoinsGroup(),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := cl.Warmup(ctx, produceTopic); err != nil {
+		t.Fatalf("unable to warm up: %v", err)
+	}
+
+	if id := cl.producer.id.Load().(*producerID); id.err != nil || id.id < 0 {
+		t.Errorf("producer ID not initialized: %d, %v", id.id, id.err)
+	}
+	for topic, cxn := range map[string]func(*broker) *brokerCxn{
+		produceTopic: func(b *broker) *brokerCxn { return b.cxnProduce },
+		consumeTopic: func(b *broker) *brokerCxn { return b.cxnFetch },
+	} {
+		for p := range int32(2) {
+			leader, _, err := cl.PartitionLeader(topic, p)
+			if err != nil || leader < 0 {
+				t.Fatalf("topic %s partition %d: leader not loaded: %d, %v", topic, p, leader, err)
+			}
+			br, err := cl.brokerOrErr(nil, leader, errUnknownBroker)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cxn(br) == nil {
+				t.Errorf("topic %s partition %d: no connection to leader %d", topic, p, leader)
+			}
+		}
+	}
+	select {
+	case <-cl.consumer.g.firstAssigned:
+	default:
+		t.Error("warmup returned before the group was assigned")
+	}
+}
+
+type commitFencedHook struct{ ch chan GroupCommitFenced }
+
+func (h commitFencedHook) OnGroupCommitFenced(e GroupCommitFenced) { h.ch <- e }
+
+func TestCommitFenced(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 1)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	hook := commitFencedHook{make(chan GroupCommitFenced, 2)}
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumerGroup(group),
+		DisableAutoCommit(),
+		RetryFencedCommits(),
+		WithHooks(hook),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := cl.ProduceSync(ctx, &Record{Topic: topic, Value: []byte("v")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	for cl.PollFetches(ctx).NumRecords() == 0 {
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// We bump the generation in the request to have the commit fenced.
+	stale := PreCommitFnContext(ctx, func(req *kmsg.OffsetCommitRequest) error {
+		req.Generation += 5
+		return nil
+	})
+	offsets := map[string]map[int32]EpochOffset{topic: {0: {-1, 1}}}
+	cl.CommitOffsetsSync(stale, offsets, nil)
+
+	var e GroupCommitFenced
+	select {
+	case e = <-hook.ch:
+	case <-ctx.Done():
+		t.Fatal("fenced commit hook was not called")
+	}
+	if !errors.Is(e.Err, kerr.IllegalGeneration) ||
+		e.Group != group ||
+		e.CommitGeneration != e.Generation+5 ||
+		e.MemberID != e.CommitMemberID ||
+		!e.Retrying || e.Retry ||
+		!reflect.DeepEqual(e.Partitions, map[string][]int32{topic: {0}}) {
+		t.Fatalf("unexpected fenced commit event: %+v", e)
+	}
+
+	// Once we rejoin, the fenced offset is committed for us.
+	cl.ForceRebalance()
+	for {
+		req := kmsg.NewPtrOffsetFetchRequest()
+		req.Group = group
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatalf("unable to fetch offsets: %v", err)
+		}
+		if len(resp.Topics) == 1 && len(resp.Topics[0].Partitions) == 1 && resp.Topics[0].Partitions[0].Offset == 1 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("fenced commit was not retried after rejoining")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+
+	// If a newer offset is committed before we rejoin, the retry does not
+	// move the committed offset backwards.
+	cl.CommitOffsetsSync(stale, map[string]map[int32]EpochOffset{topic: {0: {-1, 2}}}, nil)
+	select {
+	case <-hook.ch:
+	case <-ctx.Done():
+		t.Fatal("fenced commit hook was not called")
+	}
+	cl.CommitOffsetsSync(ctx, map[string]map[int32]EpochOffset{topic: {0: {-1, 3}}}, func(_ *Client, _ *kmsg.OffsetCommitRequest, _ *kmsg.OffsetCommitResponse, err error) {
+		if err != nil {
+			t.Errorf("unable to commit: %v", err)
+		}
+	})
+	_, gen := cl.GroupMetadata()
+	cl.ForceRebalance()
+	for {
+		if _, now := cl.GroupMetadata(); now != gen && len(cl.consumer.g.nowAssigned.read()) > 0 {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("group was not rejoined")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	time.Sleep(250 * time.Millisecond) // give the retry time to (not) commit
+	committed, err := cl.Committed(ctx, nil)
+	if err != nil {
+		t.Fatalf("unable to fetch committed: %v", err)
+	}
+	if got := committed[topic][0].Offset; got != 3 {
+		t.Errorf("got committed offset %d != exp 3", got)
+	}
+}
+
+// slowOffsetFetchHook delays reading the next OffsetFetch response once armed.
+type slowOffsetFetchHook struct{ armed atomicBool }
+
+func (h *slowOffsetFetchHook) OnBrokerRead(_ BrokerMetadata, key int16, _ int, _, _ time.Duration, _ error) {
+	if key == kmsg.OffsetFetch.Int16() && h.armed.Swap(false) {
+		time.Sleep(250 * time.Millisecond)
+	}
+}
+
+func TestCommitFencedReassigned(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 1)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// With eager balancing, a rebalance revokes our partition and drops
+	// what we knew was committed. We commit a newer offset while revoking
+	// outside of the client, as another member would while owning the
+	// partition, and the fenced retry must not rewind past it. Fetching
+	// the newly assigned offsets is slowed so that the retry would win
+	// if it did not wait for the fetch.
+	var commitOnRevoke atomicBool
+	hook := commitFencedHook{make(chan GroupCommitFenced, 1)}
+	slow := new(slowOffsetFetchHook)
+	var cl *Client
+	cl, _ = newTestClient(
+		ConsumeTopics(topic),
+		ConsumerGroup(group),
+		Balancers(RoundRobinBalancer()),
+		DisableAutoCommit(),
+		RetryFencedCommits(),
+		WithHooks(hook, slow),
+		OnPartitionsRevoked(func(ctx context.Context, _ *Client, _ map[string][]int32) {
+			if !commitOnRevoke.Swap(false) {
+				return
+			}
+			member, gen := cl.GroupMetadata()
+			req := kmsg.NewPtrOffsetCommitRequest()
+			req.Group = group
+			req.MemberID = member
+			req.Generation = gen
+			rt := kmsg.NewOffsetCommitRequestTopic()
+			rt.Topic = topic
+			rp := kmsg.NewOffsetCommitRequestTopicPartition()
+			rp.Offset = 3
+			rp.LeaderEpoch = -1
+			rt.Partitions = append(rt.Partitions, rp)
+			req.Topics = append(req.Topics, rt)
+			resp, err := req.RequestWith(ctx, cl)
+			if err == nil {
+				err = kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode)
+			}
+			if err != nil {
+				t.Errorf("unable to commit while revoking: %v", err)
+			}
+		}),
+	)
+	defer cl.Close()
+
+	if err := cl.ProduceSync(ctx, &Record{Topic: topic, Value: []byte("v")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	for cl.PollFetches(ctx).NumRecords() == 0 {
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stale := PreCommitFnContext(ctx, func(req *kmsg.OffsetCommitRequest) error {
+		req.Generation += 5
+		return nil
+	})
+	cl.CommitOffsetsSync(stale, map[string]map[int32]EpochOffset{topic: {0: {-1, 2}}}, nil)
+	select {
+	case <-hook.ch:
+	case <-ctx.Done():
+		t.Fatal("fenced commit hook was not called")
+	}
+
+	commitOnRevoke.Store(true)
+	slow.armed.Store(true)
+	_, gen := cl.GroupMetadata()
+	cl.ForceRebalance()
+	for {
+		cl.consumer.g.assignedMu.Lock()
+		fetched := cl.consumer.g.hasAssignedGen && cl.consumer.g.assignedGen != gen
+		cl.consumer.g.assignedMu.Unlock()
+		if fetched {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			t.Fatal("group was not rejoined")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+	time.Sleep(250 * time.Millisecond) // give the retry time to (not) commit
+	committed, err := cl.Committed(ctx, nil)
+	if err != nil {
+		t.Fatalf("unable to fetch committed: %v", err)
+	}
+	if got := committed[topic][0].Offset; got != 3 {
+		t.Errorf("got committed offset %d != exp 3", got)
+	}
+}
+
+func TestAllowRebalancePartial(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 2)
+	defer topicCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for _, allowAfter := range []bool{false, true} {
+		group, groupCleanup := tmpGroup(t)
+		defer groupCleanup()
+
+		blocked := make(chan map[string][]int32, 1)
+		opts := []Opt{
+			ConsumeTopics(topic),
+			ConsumerGroup(group),
+			BlockRebalanceOnPoll(),
+			OnPartitionsCallbackBlocked(func(_ context.Context, cl *Client) {
+				// Leaving and the group management goroutine
+				// both block; we only need the first.
+				select {
+				case blocked <- cl.BlockedRebalanceRevoking():
+				default:
+				}
+			}),
+		}
+		if allowAfter {
+			opts = append(opts, AllowRebalanceAfter(100*time.Millisecond))
+		}
+		cl, _ := newTestClient(opts...)
+		defer cl.Close()
+
+		if err := cl.ProduceSync(ctx, &Record{Topic: topic, Partition: 0, Value: []byte("v")}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+		for cl.PollFetches(ctx).NumRecords() == 0 {
+			if err := ctx.Err(); err != nil {
+				t.Fatal(err)
+			}
+			cl.AllowRebalance()
+		}
+
+		// We have not allowed rebalancing, so leaving the group blocks
+		// until we allow revoking everything or AllowRebalanceAfter
+		// fires.
+		left := make(chan error, 1)
+		go func() { left <- cl.LeaveGroupContext(ctx) }()
+
+		var revoking map[string][]int32
+		select {
+		case revoking = <-blocked:
+		case <-ctx.Done():
+			t.Fatal("rebalance was not blocked")
+		}
+		for _, ps := range revoking {
+			slices.Sort(ps)
+		}
+		if exp := map[string][]int32{topic: {0, 1}}; !reflect.DeepEqual(revoking, exp) {
+			t.Fatalf("got blocked revoking %v != exp %v", revoking, exp)
+		}
+
+		if !allowAfter {
+			cl.AllowRebalanceRevoking(map[string][]int32{topic: {0}})
+			select {
+			case <-left:
+				t.Fatal("leave group finished after allowing only part of the revoke")
+			case <-time.After(200 * time.Millisecond):
+			}
+			cl.AllowRebalanceRevoking(map[string][]int32{topic: {1}})
+		}
+		select {
+		case err := <-left:
+			if err != nil {
+				t.Fatalf("unable to leave group: %v", err)
+			}
+		case <-ctx.Done():
+			t.Fatal("leave group was not unblocked")
+		}
+		cl.AllowRebalance()
+	}
+}
+
+func TestTeeProduce(t *testing.T) {
+	t.Parallel()
+
+	teedTopic, teedCleanup := tmpTopicPartitions(t, 1)
+	defer teedCleanup()
+	otherTopic, otherCleanup := tmpTopicPartitions(t, 1)
+	defer otherCleanup()
+
+	to, _ := newTestClient()
+	defer to.Close()
+	cl, _ := newTestClient(TeeProduce(to, teedTopic), TeeProduceSync())
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for i := range 10 {
+		topic := teedTopic
+		if i%2 == 1 {
+			topic = otherTopic
+		}
+		if err := cl.ProduceSync(ctx, &Record{Topic: topic, Value: []byte("v")}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+	if got, exp := cl.TeeStats(), (TeeStats{Teed: 5, BothSucceeded: 5}); got != exp {
+		t.Errorf("got stats %+v != exp %+v", got, exp)
+	}
+
+	// The tee client wrote a second copy of each teed record.
+	offsets := make(map[string]int64)
+	for _, topic := range []string{teedTopic, otherTopic} {
+		req := kmsg.NewPtrListOffsetsRequest()
+		rt := kmsg.NewListOffsetsRequestTopic()
+		rt.Topic = topic
+		rp := kmsg.NewListOffsetsRequestTopicPartition()
+		rp.Timestamp = -1
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			t.Fatalf("unable to list offsets: %v", err)
+		}
+		offsets[topic] = resp.Topics[0].Partitions[0].Offset
+	}
+	if offsets[teedTopic] != 10 || offsets[otherTopic] != 5 {
+		t.Errorf("got end offsets %v, expected 10 for %s and 5 for %s", offsets, teedTopic, otherTopic)
+	}
+
+	// A failing copy does not fail the original record.
+	small, _ := newTestClient(MaxBufferedBytes(1))
+	defer small.Close()
+	cl2, _ := newTestClient(TeeProduce(small), TeeProduceSync())
+	defer cl2.Close()
+	if err := cl2.ProduceSync(ctx, &Record{Topic: teedTopic, Value: []byte("value")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if got := cl2.TeeStats(); got.TeeOnlyFailed != 1 || got.Diverged() != 1 {
+		t.Errorf("got stats %+v, expected one tee only failure", got)
+	}
+
+	// A slow tee does not block the promises of other records, but Flush
+	// still waits for the held promise.
+	slow, _ := newTestClient(ManualFlushing())
+	defer slow.Close()
+	cl3, _ := newTestClient(TeeProduce(slow, teedTopic), TeeProduceSync())
+	defer cl3.Close()
+	teedDone := make(chan error, 1)
+	cl3.Produce(ctx, &Record{Topic: teedTopic, Value: []byte("v")}, func(_ *Record, err error) { teedDone <- err })
+	if err := cl3.ProduceSync(ctx, &Record{Topic: otherTopic, Value: []byte("v")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	flushCtx, flushCancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer flushCancel()
+	if err := cl3.Flush(flushCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got flush err %v, expected flushing to wait for the held teed record", err)
+	}
+	select {
+	case err := <-teedDone:
+		t.Fatalf("teed record promise called before the tee finished: %v", err)
+	default:
+	}
+	if err := slow.Flush(ctx); err != nil {
+		t.Fatalf("unable to flush the tee: %v", err)
+	}
+	if err := <-teedDone; err != nil {
+		t.Errorf("unable to produce teed record: %v", err)
+	}
+	if err := cl3.Flush(ctx); err != nil {
+		t.Errorf("unable to flush: %v", err)
+	}
+}
+
+type skewHook func(topic string, skew time.Duration)
+
+func (h skewHook) OnProduceTimestampSkew(_ BrokerMetadata, topic string, _ int32, skew time.Duration) {
+	h(topic, skew)
+}
+
+func TestRecordTimestamps(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+
+	// User only: records must have a timestamp, which is kept.
+	cl, _ := newTestClient(DefaultProduceTopic(topic), RecordTimestamps(TimestampUserOnly()))
+	defer cl.Close()
+	if err := cl.ProduceSync(ctx, &Record{}).FirstErr(); !errors.Is(err, ErrMissingTimestamp) {
+		t.Errorf("got err %v != exp ErrMissingTimestamp", err)
+	}
+	r := &Record{Timestamp: past}
+	if err := cl.ProduceSync(ctx, r).FirstErr(); err != nil || !r.Timestamp.Equal(past) {
+		t.Errorf("got timestamp %v, err %v; expected the user timestamp %v", r.Timestamp, err, past)
+	}
+
+	// At flush: every record in a batch shares the flush timestamp,
+	// overwriting any user timestamp.
+	start := time.Now().Truncate(time.Millisecond)
+	cl2, _ := newTestClient(DefaultProduceTopic(topic), RecordTimestamps(TimestampAtFlush()), ProducerLinger(50*time.Millisecond))
+	defer cl2.Close()
+	rs := []*Record{{Timestamp: past}, {}, {}}
+	if err := cl2.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	for i, r := range rs {
+		if r.Timestamp.Before(start) || !r.Timestamp.Equal(rs[0].Timestamp) {
+			t.Errorf("record %d: got timestamp %v, expected the shared flush timestamp after %v", i, r.Timestamp, start)
+		}
+	}
+
+	// The skew hook is called for LogAppendTime topics.
+	req := kmsg.NewPtrIncrementalAlterConfigsRequest()
+	rr := kmsg.NewIncrementalAlterConfigsRequestResource()
+	rr.ResourceType = kmsg.ConfigResourceTypeTopic
+	rr.ResourceName = topic
+	rc := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+	rc.Name = "message.timestamp.type"
+	rc.Value = kmsg.StringPtr("LogAppendTime")
+	rr.Configs = append(rr.Configs, rc)
+	req.Resources = append(req.Resources, rr)
+	if _, err := req.RequestWith(ctx, cl); err != nil {
+		t.Fatalf("unable to alter topic config: %v", err)
+	}
+
+	skews := make(chan time.Duration, 1)
+	cl3, _ := newTestClient(DefaultProduceTopic(topic), WithHooks(skewHook(func(_ string, skew time.Duration) { skews <- skew })))
+	defer cl3.Close()
+	if err := cl3.ProduceSync(ctx, &Record{Timestamp: past}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	select {
+	case skew := <-skews:
+		if skew < time.Hour || skew > time.Hour+time.Minute {
+			t.Errorf("got skew %v, expected about one hour", skew)
+		}
+	default:
+		t.Error("timestamp skew hook was not called")
+	}
+}
+
+type metaChangeHook func([]TopicMetadataChange)
+
+func (h metaChangeHook) OnTopicMetadataChanged(cs []TopicMetadataChange) { h(cs) }
+
+func TestTopicMetadataChanged(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	changesC := make(chan TopicMetadataChange, 100)
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		MetadataMinAge(10*time.Millisecond),
+		WithHooks(metaChangeHook(func(cs []TopicMetadataChange) {
+			for _, c := range cs {
+				changesC <- c
+			}
+		})),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	next := func() TopicMetadataChange {
+		t.Helper()
+		select {
+		case c := <-changesC:
+			return c
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for a metadata change")
+			return TopicMetadataChange{}
+		}
+	}
+
+	if err := cl.ProduceSync(ctx, StringRecord("v")).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if c := next(); c.Topic != topic || c.Consuming || !c.Added || c.Partitions != 1 {
+		t.Fatalf("unexpected first change %+v", c)
+	}
+
+	req := kmsg.NewPtrCreatePartitionsRequest()
+	rt := kmsg.NewCreatePartitionsRequestTopic()
+	rt.Topic = topic
+	rt.Count = 3
+	req.Topics = append(req.Topics, rt)
+	if _, err := req.RequestWith(ctx, cl); err != nil {
+		t.Fatalf("unable to create partitions: %v", err)
+	}
+	cl.ForceMetadataRefresh()
+	if c := next(); c.Topic != topic || c.Added || c.PrevPartitions != 1 || c.Partitions != 3 {
+		t.Fatalf("unexpected partition change %+v", c)
+	}
+
+	cl.PurgeTopicsFromClient(topic)
+	cl.ForceMetadataRefresh()
+	if c := next(); c.Topic != topic || !c.Removed || c.PrevPartitions != 3 {
+		t.Fatalf("unexpected removal %+v", c)
+	}
+}
+
+func TestOrderedProducePromises(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	for _, ordered := range []bool{false, true} {
+		opts := []Opt{DefaultProduceTopic(topic), ProducerLinger(100 * time.Millisecond)}
+		if ordered {
+			opts = append(opts, OrderedProducePromises())
+		}
+		cl, _ := newTestClient(opts...)
+
+		// The canceled record is failed when its batch is first
+		// drained, before the records around it are produced.
+		ctx, cancel := context.WithCancel(context.Background())
+		var order []string
+		var wg sync.WaitGroup
+		for i, rctx := range []context.Context{context.Background(), ctx, context.Background()} {
+			wg.Add(1)
+			cl.Produce(rctx, StringRecord(strconv.Itoa(i)), func(r *Record, _ error) {
+				defer wg.Done()
+				order = append(order, string(r.Value))
+			})
+		}
+		cancel()
+		wg.Wait()
+		cl.Close()
+
+		exp := []string{"1", "0", "2"}
+		if ordered {
+			exp = []string{"0", "1", "2"}
+		}
+		if !reflect.DeepEqual(order, exp) {
+			t.Errorf("ordered %v: got promise order %v != exp %v", ordered, order, exp)
+		}
+	}
+}
+
+type batchAckedHook func(topic string, partition int32, baseOffset int64, numRecords int, err error)
+
+func (h batchAckedHook) OnProduceBatchAcked(topic string, partition int32, baseOffset int64, numRecords int, err error) {
+	h(topic, partition, baseOffset, numRecords, err)
+}
+
+func TestProduceBatchAcked(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	var (
+		nextOffset int64
+		acked      int
+	)
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		ProducerLinger(50*time.Millisecond),
+		WithHooks(batchAckedHook(func(ackTopic string, partition int32, baseOffset int64, numRecords int, err error) {
+			if ackTopic != topic || partition != 0 || err != nil || baseOffset != nextOffset {
+				t.Errorf("unexpected ack of %s[%d] at offset %d (exp %d), err %v", ackTopic, partition, baseOffset, nextOffset, err)
+			}
+			nextOffset += int64(numRecords)
+			acked += numRecords
+		})),
+	)
+	defer cl.Close()
+
+	for i := 0; i < 1000; i++ {
+		cl.Produce(context.Background(), StringRecord(strconv.Itoa(i)), nil)
+	}
+	if err := cl.Flush(context.Background()); err != nil {
+		t.Fatalf("unable to flush: %v", err)
+	}
+	if acked != 1000 {
+		t.Errorf("got %d acked records != exp 1000", acked)
+	}
+}
+
+type pidRecoveryHook func(ProducerIDRecovery)
+
+func (h pidRecoveryHook) OnProducerIDRecovery(r ProducerIDRecovery) { h(r) }
+
+func TestResetProducerID(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	var recoveries []ProducerIDRecovery
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		WithHooks(pidRecoveryHook(func(r ProducerIDRecovery) { recoveries = append(recoveries, r) })),
+	)
+	defer cl.Close()
+
+	if err := cl.ProduceSync(ctx, StringRecord("v")).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	id, epoch, err := cl.ProducerID(ctx)
+	if err != nil {
+		t.Fatalf("unable to load producer id: %v", err)
+	}
+
+	if err := cl.ResetProducerID(ctx); err != nil {
+		t.Fatalf("unable to reset producer id: %v", err)
+	}
+	newID, newEpoch, err := cl.ProducerID(ctx)
+	if err != nil || newID != id || newEpoch != epoch+1 {
+		t.Errorf("got id %d epoch %d err %v after reset, exp id %d epoch %d", newID, newEpoch, err, id, epoch+1)
+	}
+	if len(recoveries) != 1 || recoveries[0].Action != ProducerIDResetManual || recoveries[0].ProducerID != id || !recoveries[0].DuplicatesPossible {
+		t.Errorf("unexpected recoveries %v", recoveries)
+	}
+
+	txnCl, _ := newTestClient(TransactionalID(randsha()))
+	defer txnCl.Close()
+	if err := txnCl.ResetProducerID(ctx); err == nil {
+		t.Error("unexpected success resetting a transactional producer id")
+	}
+}
+
+func TestTopicAllowLists(t *testing.T) {
+	t.Parallel()
+
+	errs := ValidateOptsAll(
+		DefaultProduceTopic("foo"),
+		ProduceTopicAllowList("bar"),
+		ConsumeTopics("foo"),
+		ConsumeTopicAllowList("bar"),
+	)
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+	exp := []string{
+		`DefaultProduceTopic "foo" is not in the ProduceTopicAllowList`,
+		`ConsumeTopics topic "foo" is not in the ConsumeTopicAllowList`,
+	}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got errors\n%s\n!= exp\n%s", strings.Join(got, "\n"), strings.Join(exp, "\n"))
+	}
+
+	allowed, cleanup1 := tmpTopicPartitions(t, 1)
+	defer cleanup1()
+	denied, cleanup2 := tmpTopicPartitions(t, 1)
+	defer cleanup2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient()
+	defer producer.Close()
+	if err := producer.ProduceSync(ctx, &Record{Topic: denied}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+
+	cl, _ := newTestClient(
+		ProduceTopicAllowList(allowed),
+		ConsumeTopics("^("+allowed+"|"+denied+")$"),
+		ConsumeRegex(),
+		ConsumeTopicAllowList(allowed),
+	)
+	defer cl.Close()
+
+	if err := cl.ProduceSync(ctx, &Record{Topic: denied}).FirstErr(); !errors.Is(err, ErrTopicNotAllowed) {
+		t.Errorf("got err %v != exp ErrTopicNotAllowed", err)
+	}
+	if err := cl.ProduceSync(ctx, &Record{Topic: allowed}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce to allowed topic: %v", err)
+	}
+
+	var consumed bool
+	for !consumed {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+		fs.EachRecord(func(r *Record) {
+			if r.Topic != allowed {
+				t.Errorf("consumed from disallowed topic %s", r.Topic)
+			}
+			consumed = true
+		})
+	}
+	if got := cl.GetConsumeTopics(); !reflect.DeepEqual(got, []string{allowed}) {
+		t.Errorf("got consume topics %v != exp [%s]", got, allowed)
+	}
+}
+
+func TestValidateTopicMaxMessageBytes(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Records rejected locally as too large are not teed.
+	to, _ := newTestClient()
+	defer to.Close()
+	cl, _ := newTestClient(DefaultProduceTopic(topic), ValidateTopicMaxMessageBytes(), ProducerBatchCompression(NoCompression()), TeeProduce(to), TeeProduceSync())
+	defer cl.Close()
+	var produced int64
+
+	req := kmsg.NewPtrIncrementalAlterConfigsRequest()
+	rr := kmsg.NewIncrementalAlterConfigsRequestResource()
+	rr.ResourceType = kmsg.ConfigResourceTypeTopic
+	rr.ResourceName = topic
+	rc := kmsg.NewIncrementalAlterConfigsRequestResourceConfig()
+	rc.Name = "max.message.bytes"
+	rc.Value = kmsg.StringPtr("1000")
+	rr.Configs = append(rr.Configs, rc)
+	req.Resources = append(req.Resources, rr)
+	if _, err := req.RequestWith(ctx, cl); err != nil {
+		t.Fatalf("unable to alter topic config: %v", err)
+	}
+
+	// The first produce triggers learning the limit.
+	if err := cl.ProduceSync(ctx, StringRecord("small")).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	produced++
+
+	wait(t, 10*time.Second, func() error {
+		err := cl.ProduceSync(ctx, &Record{Value: make([]byte, 2000)}).FirstErr()
+		if err == nil {
+			produced++
+		}
+		var tooLarge *ErrRecordTooLarge
+		if !errors.As(err, &tooLarge) {
+			return fmt.Errorf("got err %v, waiting for *ErrRecordTooLarge", err)
+		}
+		if tooLarge.Topic != topic || tooLarge.MaxMessageBytes != 1000 || tooLarge.Size <= 2000 {
+			t.Errorf("got unexpected error fields %+v", *tooLarge)
+		}
+		if !errors.Is(err, kerr.MessageTooLarge) {
+			t.Errorf("got err %v which does not unwrap to MessageTooLarge", err)
+		}
+		return nil
+	})
+
+	if err := cl.ProduceSync(ctx, &Record{Value: make([]byte, 900)}).FirstErr(); err != nil {
+		t.Errorf("unable to produce a record under the limit: %v", err)
+	}
+	produced++
+
+	if got := cl.TeeStats(); got.Teed != produced || got.Diverged() != 0 {
+		t.Errorf("got tee stats %+v, expected only the %d produced records to be teed", got, produced)
+	}
+
+	// With compression, records are not validated locally: this record
+	// compresses under the limit and is accepted by the broker.
+	compressed, _ := newTestClient(DefaultProduceTopic(topic), ValidateTopicMaxMessageBytes())
+	defer compressed.Close()
+	for range 2 {
+		if err := compressed.ProduceSync(ctx, &Record{Value: make([]byte, 2000)}).FirstErr(); err != nil {
+			t.Errorf("unable to produce a compressible record over the limit: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond) // give the limit a chance to be loaded, were it checked
+	}
+}
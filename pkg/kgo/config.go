@@ -60,6 +60,22 @@ func (producerOpt) producerOpt()       {}
 func (consumerOpt) consumerOpt()       {}
 func (groupOpt) groupOpt()             {}
 
+// Options that can be changed on a running client with Reconfigure.
+type (
+	dynClientOpt   struct{ fn func(*cfg) }
+	dynProducerOpt struct{ fn func(*cfg) }
+	dynConsumerOpt struct{ fn func(*cfg) }
+)
+
+func (opt dynClientOpt) apply(cfg *cfg)   { opt.fn(cfg) }
+func (opt dynProducerOpt) apply(cfg *cfg) { opt.fn(cfg) }
+func (opt dynConsumerOpt) apply(cfg *cfg) { opt.fn(cfg) }
+func (dynProducerOpt) producerOpt()       {}
+func (dynConsumerOpt) consumerOpt()       {}
+func (dynClientOpt) dynamic()             {}
+func (dynProducerOpt) dynamic()           {}
+func (dynConsumerOpt) dynamic()           {}
+
 // A cfg can be written to while initializing a client, and after that it is
 // (mostly) only ever read from. Some areas can continue to be modified --
 // particularly reconfiguring what to consume from -- but most areas are
@@ -70,33 +86,60 @@ type cfg struct {
 	/////////////////////
 
 	id                     *string // client ID
+	idFn                   func(int16) string
+	requestTagsFn          func(context.Context, int16) map[string]string
 	ctx                    context.Context
 	dialFn                 func(context.Context, string, string) (net.Conn, error)
 	dialTimeout            time.Duration
 	dialTLS                *tls.Config
 	requestTimeoutOverhead time.Duration
 	connIdleTimeout        time.Duration
+	connMaxLifetime        time.Duration
+
+	requestClassTimeoutOverheads map[RequestClass]time.Duration
+	brokerTimeoutOverheads       map[int32]time.Duration
 
 	softwareName    string // KIP-511
 	softwareVersion string // KIP-511
 
-	logger Logger
+	logger           Logger
+	logDedupInterval time.Duration
 
 	seedBrokers []string
 	maxVersions *kversion.Versions
 	minVersions *kversion.Versions
 
+	hedgeBootstrap        bool
+	hedgeBootstrapStagger time.Duration
+
+	dnsLookupFn    func(context.Context, string) ([]string, time.Duration, error)
+	dnsCacheTTL    time.Duration
+	preferIPFamily IPFamily
+
+	tcpKeepAlive   *net.KeepAliveConfig
+	tcpUserTimeout time.Duration
+	tcpReadBuffer  int
+	tcpWriteBuffer int
+	tcpNoDelay     bool
+	setTCPNoDelay  bool
+
 	onRebootstrapRequired func() ([]string, error)
 
 	retryBackoff func(int) time.Duration
 	retries      int64
 	retryTimeout func(int16) time.Duration
 
+	metadataBackoff Backoff
+	produceBackoff  Backoff
+	fetchBackoff    Backoff
+	groupBackoff    Backoff
+
 	maxBrokerWriteBytes int32
 	maxBrokerReadBytes  int32
 
-	metadataMaxAge time.Duration
-	metadataMinAge time.Duration
+	metadataMaxAge   lazyDur
+	metadataMinAge   lazyDur
+	metadataTopicIDs bool
 
 	sasls []sasl.Mechanism
 
@@ -104,14 +147,19 @@ type cfg struct {
 	disableClientMetrics   bool
 	userMetrics            func() iter.Seq[Metric]
 
-	hooks hooks
-	pools pools
+	hooks  hooks
+	pools  pools
+	faults []FaultInjector
+
+	offloader        PayloadOffloader
+	offloadThreshold int
 
 	//////////////////////
 	// PRODUCER SECTION //
 	//////////////////////
 
 	txnID              *string
+	txnIDPerMember     bool
 	txnTimeout         time.Duration
 	acks               Acks
 	disableIdempotency bool
@@ -120,21 +168,32 @@ type cfg struct {
 
 	defaultProduceTopic       string
 	defaultProduceTopicAlways bool
+	produceAllowTopics        map[string]struct{} // if non-nil, the only topics that can be produced to
 	maxRecordBatchBytes       int32
+	validateTopicMaxBytes     bool
+	produceDryRun             bool
 	maxBufferedRecords        int64
 	maxBufferedBytes          int64
 	produceTimeout            time.Duration
 	recordRetries             int64
 	maxUnknownFailures        int64
-	linger                    time.Duration
+	linger                    lazyDur
 	recordTimeout             time.Duration
 	manualFlushing            bool
+	e2eTimestamps             bool
+	timestampSource           TimestampSource
+	orderedPromises           bool
+	verifyAmbiguous           bool
+	teeSync                   bool
 	txnBackoff                time.Duration
 	missingTopicDelete        time.Duration
 
 	partitioner Partitioner
 	compressor  Compressor
 
+	teeClient *Client
+	teeTopics map[string]struct{}
+
 	stopOnDataLoss bool
 	onDataLoss     func(string, int32)
 
@@ -142,7 +201,7 @@ type cfg struct {
 	// CONSUMER SECTION //
 	//////////////////////
 
-	maxWait        int32
+	maxWait        lazyI32
 	minBytes       int32
 	maxBytes       lazyI32
 	maxPartBytes   lazyI32
@@ -150,6 +209,7 @@ type cfg struct {
 	resetOffset    Offset
 	setStartOffset bool
 	setResetOffset bool
+	resetOffsetFn  func(string, int32) Offset
 	isolationLevel int8
 	keepControl    bool
 	rack           string
@@ -157,9 +217,18 @@ type cfg struct {
 	decompressor   Decompressor
 
 	maxConcurrentFetches      int
+	fetchConcurrencyPerBroker int
+	fetchTargetBufferedBytes  int32
+	maxPollRecords            int
+	maxPollBytes              int64
 	disableFetchSessions      bool
 	keepRetryableFetchErrors  bool
+	suppressTransientErrors   bool
 	disableFetchCRCValidation bool
+	e2eLatency                bool
+
+	disableOffsetEpochValidation   bool
+	offsetLoadPartitionsPerRequest int
 
 	recheckPreferredReplicaInterval time.Duration
 
@@ -167,7 +236,14 @@ type cfg struct {
 	excludeTopics map[string]*regexp.Regexp   // topics to exclude; only used if regex is true, values are compiled regular expressions
 	partitions    map[string]map[int32]Offset // partitions to directly consume from
 	regex         bool
+	topicMatcher  func(string) bool // if non-nil and regex is true, an additional matcher for topics
 
+	regexDropIdle   time.Duration           // if non-zero, regex matched topics with no data for this long are dropped
+	regexOnDropIdle func(*Client, []string) // called after idle topics are dropped
+
+	regexUnmatchedTTL time.Duration // if non-zero, topics that did not match are evaluated again after this long
+
+	consumeAllowTopics map[string]struct{} // if non-nil, the only topics that can be consumed
 	////////////////////////////
 	// CONSUMER GROUP SECTION //
 	////////////////////////////
@@ -185,46 +261,87 @@ type cfg struct {
 	onAssigned func(context.Context, *Client, map[string][]int32)
 	onRevoked  func(context.Context, *Client, map[string][]int32)
 	onLost     func(context.Context, *Client, map[string][]int32)
+	onStandby  func(context.Context, *Client, map[string][]int32)
 	onBlocked  func(context.Context, *Client)
 	onFetched  func(context.Context, *Client, *kmsg.OffsetFetchResponse) error
 
+	onCheckpoint func(context.Context, *Client, map[string]map[int32]EpochOffset)
+	onRestore    func(context.Context, *Client, map[string]map[int32]Offset) error
+
 	adjustOffsetsBeforeAssign func(ctx context.Context, offsets map[string]map[int32]Offset) (map[string]map[int32]Offset, error)
 
 	blockRebalanceOnPoll bool
+	allowRebalanceAfter  time.Duration
+	groupCensus          bool
+	warmupJoinGroup      bool
+	preferredPartitions  func() map[string][]int32
 
 	autocommitDisable  bool // true if autocommit was disabled or we are transactional
 	autocommitGreedy   bool
 	autocommitMarks    bool
 	autocommitInterval time.Duration
 	commitCallback     func(*Client, *kmsg.OffsetCommitRequest, *kmsg.OffsetCommitResponse, error)
+	retryFencedCommits bool
 
 	disableNextGenBalancer bool
 }
 
 func (cfg *cfg) validate() error {
-	if len(cfg.seedBrokers) == 0 {
-		return errors.New("config erroneously has no seed brokers")
+	if errs := cfg.validateAll(); len(errs) > 0 {
+		return errs[0]
 	}
+	return nil
+}
 
-	// We clamp maxPartBytes to maxBytes because some fake Kafka endpoints
-	// (Oracle) cannot handle the mismatch correctly.
+// clampMaxPartBytes clamps maxPartBytes to maxBytes because some fake Kafka
+// endpoints (Oracle) cannot handle the mismatch correctly. This is done after
+// validating, since validating must not modify the config.
+func (cfg *cfg) clampMaxPartBytes() {
 	if cfg.maxPartBytes > cfg.maxBytes {
 		cfg.maxPartBytes = cfg.maxBytes
 	}
+}
+
+// validateAll returns every problem with the configuration in the order they
+// are checked; NewClient fails with the first.
+func (cfg *cfg) validateAll() []error {
+	var errs []error
+	fail := func(err error) { errs = append(errs, err) }
+
+	if len(cfg.seedBrokers) == 0 {
+		fail(errors.New("config erroneously has no seed brokers"))
+	}
+
+	if cfg.txnIDPerMember && (cfg.txnID == nil || cfg.group == "") {
+		fail(errors.New("TransactionalIDPerGroupMember requires both TransactionalID and ConsumerGroup"))
+	}
 
 	if cfg.disableIdempotency {
 		if cfg.txnID != nil {
-			return errors.New("cannot both disable idempotent writes and use transactional IDs")
+			fail(errors.New("cannot both disable idempotent writes and use transactional IDs"))
 		}
 		if cfg.maxProduceInflight <= 0 {
-			return fmt.Errorf("invalid max produce inflight %d with idempotency disabled", cfg.maxProduceInflight)
+			fail(fmt.Errorf("invalid max produce inflight %d with idempotency disabled", cfg.maxProduceInflight))
 		}
 	} else {
 		if cfg.acks.val != -1 {
-			return errors.New("idempotency requires acks=all")
+			if cfg.txnID != nil {
+				fail(errors.New("transactional producing requires acks=all"))
+			} else {
+				fail(errors.New("idempotency requires acks=all"))
+			}
 		}
 		if cfg.maxProduceInflight != 1 {
-			return fmt.Errorf("invalid usage of MaxProduceRequestsInflightPerBroker with idempotency enabled")
+			fail(fmt.Errorf("invalid usage of MaxProduceRequestsInflightPerBroker with idempotency enabled"))
+		}
+	}
+
+	if cfg.idFn != nil {
+		for key := int16(0); key <= kmsg.MaxKey; key++ {
+			if id := cfg.idFn(key); len(id) > 256 {
+				fail(fmt.Errorf("client id for %s length %d is larger than max allowed %d", kmsg.NameForKey(key), len(id), 256))
+				break
+			}
 		}
 	}
 
@@ -252,7 +369,7 @@ func (cfg *cfg) validate() error {
 			s = **limit.sp
 		}
 		if len(s) > limit.allowed {
-			return fmt.Errorf("%s length %d is larger than max allowed %d", limit.name, len(s), limit.allowed)
+			fail(fmt.Errorf("%s length %d is larger than max allowed %d", limit.name, len(s), limit.allowed))
 		}
 	}
 
@@ -292,6 +409,18 @@ func (cfg *cfg) validate() error {
 
 		// 0 <= allowed concurrency
 		{name: "max concurrent fetches", v: int64(cfg.maxConcurrentFetches), allowed: 0, badcmp: i64lt},
+		{name: "offset load partitions per request", v: int64(cfg.offsetLoadPartitionsPerRequest), allowed: 0, badcmp: i64lt},
+
+		// 1 <= concurrent fetches per broker <= 16
+		{name: "concurrent fetches per broker", v: int64(cfg.fetchConcurrencyPerBroker), allowed: 1, badcmp: i64lt},
+		{name: "concurrent fetches per broker", v: int64(cfg.fetchConcurrencyPerBroker), allowed: 16, badcmp: i64gt},
+
+		// 0 <= max poll records, max poll bytes
+		{name: "max poll records", v: int64(cfg.maxPollRecords), allowed: 0, badcmp: i64lt},
+		{name: "max poll bytes", v: cfg.maxPollBytes, allowed: 0, badcmp: i64lt},
+
+		// 0 <= target buffered partition bytes
+		{name: "fetch target buffered bytes", v: int64(cfg.fetchTargetBufferedBytes), allowed: 0, badcmp: i64lt},
 
 		// 100ms <= request timeout overhead <= 15m
 		{name: "request timeout max overhead", v: int64(cfg.requestTimeoutOverhead), allowed: int64(15 * time.Minute), badcmp: i64gt, durs: true},
@@ -300,6 +429,7 @@ func (cfg *cfg) validate() error {
 		// 1s <= conn idle <= 15m
 		{name: "conn min idle timeout", v: int64(cfg.connIdleTimeout), allowed: int64(time.Second), badcmp: i64lt, durs: true},
 		{name: "conn max idle timeout", v: int64(cfg.connIdleTimeout), allowed: int64(15 * time.Minute), badcmp: i64gt, durs: true},
+		{name: "conn max lifetime", v: int64(cfg.connMaxLifetime), allowed: 0, badcmp: i64lt, durs: true},
 
 		// 10ms <= metadata <= 1hr
 		{name: "metadata max age", v: int64(cfg.metadataMaxAge), allowed: int64(time.Hour), badcmp: i64gt, durs: true},
@@ -338,95 +468,170 @@ func (cfg *cfg) validate() error {
 		{v: int64(cfg.heartbeatInterval), allowed: int64(cfg.rebalanceTimeout) * int64(time.Millisecond), badcmp: i64gt, durs: true, fmt: "heartbeat interval %v is erroneously larger than the session timeout %v"},
 	} {
 		bad, cmp := limit.badcmp(limit.v, limit.allowed)
-		if bad {
-			if limit.fmt != "" {
-				if limit.durs {
-					return fmt.Errorf(limit.fmt, time.Duration(limit.v), time.Duration(limit.allowed))
-				}
-				return fmt.Errorf(limit.fmt, limit.v, limit.allowed)
-			}
-			if limit.durs {
-				return fmt.Errorf("%s %v is %s than allowed %v", limit.name, time.Duration(limit.v), cmp, time.Duration(limit.allowed))
-			}
-			return fmt.Errorf("%s %v is %s than allowed %v", limit.name, limit.v, cmp, limit.allowed)
+		switch {
+		case !bad:
+		case limit.fmt != "" && limit.durs:
+			fail(fmt.Errorf(limit.fmt, time.Duration(limit.v), time.Duration(limit.allowed)))
+		case limit.fmt != "":
+			fail(fmt.Errorf(limit.fmt, limit.v, limit.allowed))
+		case limit.durs:
+			fail(fmt.Errorf("%s %v is %s than allowed %v", limit.name, time.Duration(limit.v), cmp, time.Duration(limit.allowed)))
+		default:
+			fail(fmt.Errorf("%s %v is %s than allowed %v", limit.name, limit.v, cmp, limit.allowed))
+		}
+	}
+
+	for _, overhead := range cfg.requestClassTimeoutOverheads {
+		if overhead < 100*time.Millisecond || overhead > 15*time.Minute {
+			fail(fmt.Errorf("request class timeout overhead %v is outside the allowed range of 100ms to 15m", overhead))
+		}
+	}
+	for _, overhead := range cfg.brokerTimeoutOverheads {
+		if overhead < 100*time.Millisecond || overhead > 15*time.Minute {
+			fail(fmt.Errorf("broker request timeout overhead %v is outside the allowed range of 100ms to 15m", overhead))
 		}
 	}
 
 	if cfg.defaultProduceTopicAlways && cfg.defaultProduceTopic == "" {
-		return errors.New("invalid empty DefaultProduceTopic when using DefaultProduceTopicAlways")
+		fail(errors.New("invalid empty DefaultProduceTopic when using DefaultProduceTopicAlways"))
+	}
+	if cfg.produceDryRun && cfg.txnID != nil {
+		fail(errors.New("cannot use ProduceDryRun with a TransactionalID"))
+	}
+	if cfg.defaultProduceTopic != "" && !cfg.canProduceTo(cfg.defaultProduceTopic) {
+		fail(fmt.Errorf("DefaultProduceTopic %q is not in the ProduceTopicAllowList", cfg.defaultProduceTopic))
 	}
 
 	if cfg.dialFn != nil {
 		if cfg.dialTLS != nil {
-			return errors.New("cannot set both Dialer and DialTLSConfig")
+			fail(errors.New("cannot set both Dialer and DialTLSConfig"))
+		}
+		if cfg.dnsLookupFn != nil || cfg.dnsCacheTTL > 0 || cfg.preferIPFamily != IPFamilyAny {
+			fail(errors.New("cannot use DNS resolution options with a custom Dialer; the dialer is responsible for resolving hosts"))
 		}
 	}
 
 	if len(cfg.group) > 0 {
 		if len(cfg.partitions) != 0 {
-			return errors.New("invalid direct-partition consuming option when consuming as a group")
+			fail(errors.New("invalid direct-partition consuming option when consuming as a group"))
 		}
 	}
 
 	if cfg.regex {
 		if len(cfg.partitions) != 0 {
-			return errors.New("invalid direct-partition consuming option when consuming as regex")
+			fail(errors.New("invalid direct-partition consuming option when consuming as regex"))
 		}
 		for re := range cfg.topics {
 			compiled, err := regexp.Compile(re)
 			if err != nil {
-				return fmt.Errorf("invalid regular expression %q", re)
+				fail(fmt.Errorf("invalid regular expression %q", re))
+				continue
 			}
 			cfg.topics[re] = compiled
 		}
 		for re := range cfg.excludeTopics {
 			compiled, err := regexp.Compile(re)
 			if err != nil {
-				return fmt.Errorf("invalid regular expression %q", re)
+				fail(fmt.Errorf("invalid regular expression %q", re))
+				continue
 			}
 			cfg.excludeTopics[re] = compiled
 		}
 	} else if len(cfg.excludeTopics) > 0 {
-		return errors.New("invalid use of ConsumeExcludeTopics when not using ConsumeRegex")
+		fail(errors.New("invalid use of ConsumeExcludeTopics when not using ConsumeRegex"))
+	} else if cfg.topicMatcher != nil {
+		fail(errors.New("invalid use of ConsumeTopicMatcher when not using ConsumeRegex"))
+	} else if cfg.regexDropIdle > 0 {
+		fail(errors.New("invalid use of ConsumeRegexDropIdleTopics when not using ConsumeRegex"))
+	} else if cfg.regexUnmatchedTTL > 0 {
+		fail(errors.New("invalid use of ConsumeRegexUnmatchedTTL when not using ConsumeRegex"))
+	}
+
+	if !cfg.regex {
+		for topic := range cfg.topics {
+			if !cfg.canConsume(topic) {
+				fail(fmt.Errorf("ConsumeTopics topic %q is not in the ConsumeTopicAllowList", topic))
+			}
+		}
+	}
+	for topic := range cfg.partitions {
+		if !cfg.canConsume(topic) {
+			fail(fmt.Errorf("ConsumePartitions topic %q is not in the ConsumeTopicAllowList", topic))
+		}
 	}
 
 	if cfg.topics != nil && cfg.partitions != nil {
 		for topic := range cfg.partitions {
 			if _, exists := cfg.topics[topic]; exists {
-				return fmt.Errorf("topic %q seen in both ConsumePartitions and ConsumeTopics; these options are a union, it is invalid to specify specific partitions for a topic while also consuming the entire topic", topic)
+				fail(fmt.Errorf("topic %q seen in both ConsumePartitions and ConsumeTopics; these options are a union, it is invalid to specify specific partitions for a topic while also consuming the entire topic", topic))
 			}
 		}
 	}
 
 	if cfg.autocommitDisable && cfg.autocommitGreedy {
-		return errors.New("cannot both disable autocommitting and enable greedy autocommitting")
+		fail(errors.New("cannot both disable autocommitting and enable greedy autocommitting"))
 	}
 	if cfg.autocommitDisable && cfg.autocommitMarks {
-		return errors.New("cannot both disable autocommitting and enable marked autocommitting")
+		fail(errors.New("cannot both disable autocommitting and enable marked autocommitting"))
 	}
 	if cfg.autocommitGreedy && cfg.autocommitMarks {
-		return errors.New("cannot enable both greedy autocommitting and marked autocommitting")
+		fail(errors.New("cannot enable both greedy autocommitting and marked autocommitting"))
 	}
 	if (cfg.autocommitGreedy || cfg.autocommitDisable || cfg.autocommitMarks || cfg.commitCallback != nil) && len(cfg.group) == 0 {
-		return errors.New("invalid autocommit options specified when a group was not specified")
+		fail(errors.New("invalid autocommit options specified when a group was not specified"))
 	}
-	if (cfg.onLost != nil || cfg.onRevoked != nil || cfg.onAssigned != nil) && len(cfg.group) == 0 {
-		return errors.New("invalid group partition assigned/revoked/lost functions set when a group was not specified")
+	if (cfg.onLost != nil || cfg.onRevoked != nil || cfg.onAssigned != nil || cfg.onStandby != nil || cfg.onCheckpoint != nil || cfg.onRestore != nil) && len(cfg.group) == 0 {
+		fail(errors.New("invalid group partition assigned/revoked/lost functions set when a group was not specified"))
 	}
 
-	processedHooks, err := processHooks(cfg.hooks)
-	if err != nil {
-		return err
+	if processedHooks, err := processHooks(cfg.hooks); err != nil {
+		fail(err)
+	} else {
+		cfg.hooks = processedHooks
 	}
-	cfg.hooks = processedHooks
 
-	processedPools, err := processPools(cfg.pools)
-	if err != nil {
-		return err
+	if processedPools, err := processPools(cfg.pools); err != nil {
+		fail(err)
+	} else {
+		cfg.pools = processedPools
 	}
-	cfg.pools = processedPools
 
-	return nil
+	return errs
+}
+
+// conflicts returns option combinations that NewClient allows but that have
+// no effect or do not do what they appear to.
+func (cfg *cfg) conflicts() []error {
+	var errs []error
+	fail := func(err error) { errs = append(errs, err) }
+
+	if len(cfg.group) == 0 {
+		if cfg.blockRebalanceOnPoll {
+			fail(errors.New("BlockRebalanceOnPoll has no effect when a group was not specified"))
+		}
+		if cfg.allowRebalanceAfter > 0 {
+			fail(errors.New("AllowRebalanceAfter has no effect when a group was not specified"))
+		}
+		if cfg.requireStable {
+			fail(errors.New("RequireStableFetchOffsets has no effect when a group was not specified"))
+		}
+		if cfg.instanceID != nil {
+			fail(errors.New("InstanceID has no effect when a group was not specified"))
+		}
+		if cfg.retryFencedCommits {
+			fail(errors.New("RetryFencedCommits has no effect when a group was not specified"))
+		}
+	}
+	if cfg.allowRebalanceAfter > 0 && !cfg.blockRebalanceOnPoll {
+		fail(errors.New("AllowRebalanceAfter has no effect without BlockRebalanceOnPoll"))
+	}
+	if cfg.autocommitDisable && cfg.autocommitInterval != defaultCfg().autocommitInterval {
+		fail(errors.New("AutoCommitInterval has no effect when autocommitting is disabled"))
+	}
+	if cfg.regex && len(cfg.topics) == 0 {
+		fail(errors.New("ConsumeRegex has no effect without any ConsumeTopics"))
+	}
+	return errs
 }
 
 // processHooks will inspect and recursively unpack slices of hooks stopping
@@ -500,7 +705,7 @@ func defaultCfg() cfg {
 		softwareName:    "kgo",
 		softwareVersion: softwareVersion(),
 
-		logger: new(nopLogger),
+		logger: new(wrappedLogger),
 
 		seedBrokers: []string{"127.0.0.1"},
 		maxVersions: kversion.Stable(), // kversion bumps what is returned from Stable on the same release we add support for new features to kgo
@@ -539,8 +744,8 @@ func defaultCfg() cfg {
 		maxBrokerWriteBytes: 100 << 20, // Kafka socket.request.max.bytes default is 100<<20
 		maxBrokerReadBytes:  100 << 20,
 
-		metadataMaxAge:     5 * time.Minute,
-		metadataMinAge:     5 * time.Second,
+		metadataMaxAge:     lazyDur(5 * time.Minute),
+		metadataMinAge:     lazyDur(5 * time.Second),
 		missingTopicDelete: 15 * time.Second,
 
 		//////////////
@@ -556,7 +761,7 @@ func defaultCfg() cfg {
 		produceTimeout:      10 * time.Second,
 		recordRetries:       math.MaxInt64, // effectively unbounded
 		maxUnknownFailures:  4,
-		linger:              10 * time.Millisecond,
+		linger:              lazyDur(10 * time.Millisecond),
 		partitioner:         UniformBytesPartitioner(64<<10, true, true, nil),
 		txnBackoff:          20 * time.Millisecond,
 
@@ -572,7 +777,8 @@ func defaultCfg() cfg {
 		resetOffset:    NewOffset().AtStart(),
 		isolationLevel: 0,
 
-		maxConcurrentFetches: 0, // unbounded default
+		maxConcurrentFetches:      0, // unbounded default
+		fetchConcurrencyPerBroker: 1,
 
 		recheckPreferredReplicaInterval: 30 * time.Minute,
 
@@ -603,6 +809,50 @@ func ClientID(id string) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.id = &id }}
 }
 
+// ClientIDFn sets a function that returns the client ID to use for requests of
+// a given key, overriding ClientID (or the default "kgo") for that key. If the
+// function returns an empty string, the ClientID is used. The function is
+// called once per request key when the client is created.
+//
+// Kafka applies quotas per client ID. This option allows separating produce,
+// consume, and admin traffic from a single client into different quotas:
+//
+//	kgo.ClientIDFn(func(key int16) string {
+//		switch kmsg.Key(key) {
+//		case kmsg.Produce:
+//			return "svc-producer"
+//		case kmsg.Fetch:
+//			return "svc-consumer"
+//		}
+//		return ""
+//	})
+//
+// Each client ID is limited to 256 bytes, as with ClientID.
+func ClientIDFn(fn func(key int16) string) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.idFn = fn }}
+}
+
+// RequestTagsFn sets a function that returns structured tags (i.e., a tenant
+// or job ID) to append to the client ID of an outgoing request, allowing
+// broker side request logs to attribute traffic. The function is called
+// before writing every request with the request's context and key, and can
+// return nil to not tag the request.
+//
+// Tags are appended to the client ID as ";k1=v1,k2=v2", sorted by key, and are
+// truncated to 256 bytes. Kafka does not define any request header field for
+// arbitrary tags, so the tags are part of the client ID as the broker sees it:
+// note that brokers apply quotas per client ID, so tags with many distinct
+// values split a client's traffic into many quotas. It is recommended to only
+// tag requests that you need to attribute (i.e., with a check on the key).
+//
+// Requests issued with [Client.Request] use the context passed to Request,
+// allowing you to propagate tags from a tracing context. Requests issued
+// internally by the client (produce requests, fetch requests, group
+// management, metadata) use the client's context.
+func RequestTagsFn(fn func(ctx context.Context, key int16) map[string]string) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.requestTagsFn = fn }}
+}
+
 // SoftwareNameAndVersion sets the client software name and version that will
 // be sent to Kafka as part of the ApiVersions request as of Kafka 2.4,
 // overriding the default "kgo" and internal version number.
@@ -625,7 +875,22 @@ func SoftwareNameAndVersion(name, version string) Opt {
 //
 // It is invalid to use a nil logger; doing so will cause panics.
 func WithLogger(l Logger) Opt {
-	return clientOpt{func(cfg *cfg) { cfg.logger = &wrappedLogger{l} }}
+	return dynClientOpt{func(cfg *cfg) { cfg.logger = newWrappedLogger(l) }}
+}
+
+// LogDedupInterval deduplicates warnings and errors logged by the client,
+// logging an identical message at most once per interval, overriding the
+// default of no deduplication. Messages are identical if they have the same
+// level, message, and broker and topic keys (if any).
+//
+// When a deduplicated message is logged again after the interval, it includes
+// a "suppressed" key with the number of times it was dropped. If a message
+// stops repeating, a summary of how many were dropped is logged the next time
+// the client logs anything after the interval passes. This prevents log
+// floods during broker outages, where every request to a down broker would
+// otherwise log the same failure.
+func LogDedupInterval(interval time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.logDedupInterval = interval }}
 }
 
 // WithContext sets the client to use a custom context.
@@ -651,11 +916,51 @@ func WithContext(ctx context.Context) Opt {
 // active writes or reads on the connection.
 //
 // This option is roughly equivalent to request.timeout.ms, but grants
-// additional time to requests that have timeout fields.
+// additional time to requests that have timeout fields. To use a different
+// overhead for specific kinds of requests or specific brokers, see
+// RequestClassTimeoutOverhead and BrokerRequestTimeoutOverhead.
 func RequestTimeoutOverhead(overhead time.Duration) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.requestTimeoutOverhead = overhead }}
 }
 
+// RequestClassTimeoutOverhead overrides RequestTimeoutOverhead for a class of
+// requests. This option can be specified multiple times to override the
+// overhead for multiple classes.
+//
+// A single overhead is a compromise between requests that should fail fast,
+// such as produce requests where a hung connection delays everything buffered
+// behind it, and requests that may be slow to be served but must eventually
+// succeed, such as metadata requests on large clusters or admin requests.
+// This option allows, for example, a 3s produce overhead alongside a 30s
+// metadata overhead. The overhead is used exactly as RequestTimeoutOverhead
+// is: it is added to any timeout in the request itself.
+func RequestClassTimeoutOverhead(class RequestClass, overhead time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) {
+		if cfg.requestClassTimeoutOverheads == nil {
+			cfg.requestClassTimeoutOverheads = make(map[RequestClass]time.Duration)
+		}
+		cfg.requestClassTimeoutOverheads[class] = overhead
+	}}
+}
+
+// BrokerRequestTimeoutOverhead overrides RequestTimeoutOverhead and any
+// RequestClassTimeoutOverhead for all requests to the broker with the given
+// node ID. As with RequestClassTimeoutOverhead, ApiVersions and SASL requests
+// always use RequestTimeoutOverhead. This option can be specified multiple
+// times to override the overhead for multiple brokers. Seed brokers use
+// negative node IDs, see BrokerMetadata.
+//
+// This can be used to give a known-slow broker more time without loosening
+// the timeout for every other broker.
+func BrokerRequestTimeoutOverhead(nodeID int32, overhead time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) {
+		if cfg.brokerTimeoutOverheads == nil {
+			cfg.brokerTimeoutOverheads = make(map[int32]time.Duration)
+		}
+		cfg.brokerTimeoutOverheads[nodeID] = overhead
+	}}
+}
+
 // ConnIdleTimeout is a rough amount of time to allow connections to idle
 // before they are closed, overriding the default 20.
 //
@@ -673,6 +978,25 @@ func ConnIdleTimeout(timeout time.Duration) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.connIdleTimeout = timeout }}
 }
 
+// ConnMaxLifetime sets the maximum amount of time a broker connection is
+// used for, overriding the default of 0 (connections are used until they are
+// idle reaped or fail).
+//
+// A connection past its lifetime is cycled gracefully: the next request that
+// would use the connection opens a new connection instead, and the old
+// connection is closed once every request in flight on it has been replied
+// to. If opening the new connection fails, the old connection continues to be
+// used until a new connection can be opened. The produce connection is only
+// cycled when no produce requests are in flight on it, since idempotent
+// produce requests must be received in order.
+//
+// This is useful in environments where a load balancer forcibly closes
+// connections after a maximum age, and to spread connections across brokers
+// behind a single address after the brokers scale out.
+func ConnMaxLifetime(lifetime time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.connMaxLifetime = lifetime }}
+}
+
 // Dialer uses fn to dial addresses, overriding the default dialer that uses a
 // 10s dial timeout and no TLS.
 //
@@ -700,6 +1024,105 @@ func DialTimeout(timeout time.Duration) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.dialTimeout = timeout }}
 }
 
+// HedgeBootstrapDials opts into issuing the client's initial metadata request
+// to all seed brokers concurrently rather than one at a time, using the first
+// seed to reply. This cuts the time to start when the first seed brokers are
+// unreachable: rather than waiting for a dial or request to time out, the
+// client moves on to the next seed after the stagger delay.
+//
+// Similar to happy eyeballs (RFC 8305), the request is issued to the first
+// seed immediately and to each subsequent seed after the stagger delay (or
+// immediately after a prior seed fails), until a seed replies. A stagger of 0
+// issues the request to all seeds at once. Once a seed replies, requests to
+// all other seeds are canceled.
+//
+// Hedging applies only while the client has not yet discovered any broker and
+// more than one seed broker is configured. If all seeds fail, the client falls
+// back to its normal retry logic.
+func HedgeBootstrapDials(stagger time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.hedgeBootstrap, cfg.hedgeBootstrapStagger = true, stagger }}
+}
+
+// DNSLookupFn sets the function to use to resolve seed and broker hostnames,
+// overriding the default of net.DefaultResolver.LookupHost. The function
+// returns the host's addresses and how long the addresses can be cached for,
+// which overrides DNSCacheTTL if positive. Use this option to honor DNS
+// record TTLs, which the Go standard library does not expose.
+//
+// By default, the client resolves a host every time it opens a new connection
+// to a broker, which is what you want if brokers are behind round-robin DNS or
+// a Kubernetes headless service.
+//
+// This option cannot be used with a custom Dialer, and hosts that are IP
+// addresses are never resolved.
+func DNSLookupFn(fn func(ctx context.Context, host string) (addrs []string, ttl time.Duration, err error)) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.dnsLookupFn = fn }}
+}
+
+// DNSCacheTTL sets how long the client caches resolved seed and broker
+// addresses, overriding the default of 0 (resolving on every new connection).
+// A cached entry is also discarded whenever the client cannot dial any of the
+// host's addresses, so that a broker moving to a new address is discovered on
+// the next connection attempt.
+//
+// This option cannot be used with a custom Dialer.
+func DNSCacheTTL(ttl time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.dnsCacheTTL = ttl }}
+}
+
+// PreferIPFamily sets which IP address family to dial first when a seed or
+// broker hostname resolves to both IPv4 and IPv6 addresses. Addresses in the
+// other family are still dialed if every preferred address fails.
+//
+// This option cannot be used with a custom Dialer.
+func PreferIPFamily(family IPFamily) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.preferIPFamily = family }}
+}
+
+// TCPKeepAlive sets the TCP keepalive configuration for broker connections,
+// overriding Go's default of probing every 15s after 15s idle, up to 9 times.
+// Lowering the idle time, interval, and count detects connections that were
+// silently dropped (for example, by a load balancer reaping idle flows) much
+// faster. Setting Enable to false disables keepalives entirely.
+//
+// Zero fields in the config keep the system or Go defaults; see
+// net.KeepAliveConfig for details. Unlike the DNS options, this option (and
+// the other TCP options) also applies to connections from a custom Dialer, so
+// long as the dialer returns a *net.TCPConn or a *tls.Conn wrapping one.
+func TCPKeepAlive(keepalive net.KeepAliveConfig) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.tcpKeepAlive = &keepalive }}
+}
+
+// TCPUserTimeout sets TCP_USER_TIMEOUT on broker connections, which is the
+// maximum amount of time that written data may remain unacknowledged before
+// the kernel forcibly closes the connection. Without this, a connection that
+// is silently dropped while the client has outstanding writes can take over
+// 15 minutes to error, since keepalives are not sent while data is
+// unacknowledged.
+//
+// This option is only supported on Linux and is ignored elsewhere.
+func TCPUserTimeout(timeout time.Duration) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.tcpUserTimeout = timeout }}
+}
+
+// TCPBufferSizes sets the socket receive (read) and send (write) buffer sizes
+// for broker connections, overriding the operating system defaults. A
+// non-positive size keeps the default for that buffer.
+//
+// Larger buffers can improve throughput over high latency links.
+func TCPBufferSizes(read, write int) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.tcpReadBuffer, cfg.tcpWriteBuffer = read, write }}
+}
+
+// TCPNoDelay sets whether TCP_NODELAY is set on broker connections, which
+// disables Nagle's algorithm. Go enables TCP_NODELAY by default, which is
+// almost always what you want for Kafka, since the client already batches
+// records into requests. Disabling it can reduce the number of packets sent
+// on links that are charged per packet, at the cost of latency.
+func TCPNoDelay(noDelay bool) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.tcpNoDelay, cfg.setTCPNoDelay = noDelay, true }}
+}
+
 // DialTLSConfig opts into dialing brokers with the given TLS config with a
 // 10s dial timeout. This is a shortcut for manually specifying a tls dialer
 // using the Dialer option. You can also change the default 10s timeout with
@@ -762,10 +1185,22 @@ func MinVersions(versions *kversion.Versions) Opt {
 //
 // This (roughly) corresponds to Kafka's retry.backoff.ms setting and
 // retry.backoff.max.ms (which is being introduced with KIP-500).
+//
+// Metadata, produce, fetch, and group requests can use a different backoff
+// with MetadataRetryBackoff, ProduceRetryBackoff, FetchRetryBackoff, and
+// GroupRetryBackoff.
 func RetryBackoffFn(backoff func(int) time.Duration) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.retryBackoff = backoff }}
 }
 
+// MetadataRetryBackoff sets the backoff to use when retrying metadata
+// requests, overriding RetryBackoffFn for metadata requests only. This also
+// applies to the backoff between failed metadata updates in the client's
+// internal metadata loop.
+func MetadataRetryBackoff(backoff Backoff) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.metadataBackoff = backoff }}
+}
+
 // RequestRetries sets the number of tries that retryable requests are allowed,
 // overriding the default of 20s.
 //
@@ -858,7 +1293,7 @@ func BrokerMaxReadBytes(v int32) Opt {
 //
 // This corresponds to Kafka's metadata.max.age.ms.
 func MetadataMaxAge(age time.Duration) Opt {
-	return clientOpt{func(cfg *cfg) { cfg.metadataMaxAge = age }}
+	return dynClientOpt{func(cfg *cfg) { cfg.metadataMaxAge = lazyDur(age) }}
 }
 
 // MetadataMinAge sets the minimum time between metadata queries, overriding
@@ -866,7 +1301,30 @@ func MetadataMaxAge(age time.Duration) Opt {
 // metadata queries the client will make. Notably, if metadata detects an error
 // in any topic or partition, it triggers itself to update as soon as allowed.
 func MetadataMinAge(age time.Duration) Opt {
-	return clientOpt{func(cfg *cfg) { cfg.metadataMinAge = age }}
+	return dynClientOpt{func(cfg *cfg) { cfg.metadataMinAge = lazyDur(age) }}
+}
+
+// MetadataTopicIDs opts into requesting metadata by topic ID for topics the
+// client has already discovered, and into incremental metadata refreshes.
+//
+// Once a topic is discovered, the client caches its metadata keyed by topic
+// ID. If all brokers support topic IDs in metadata requests (Kafka 3.1+),
+// later refreshes request the topic by ID rather than by name. If a refresh
+// finds errors for some topics or partitions, the client quickly re-requests
+// metadata a few times until the errors resolve; with this option, the quick
+// re-requests only include the topics that had errors, and every other topic
+// uses its cached metadata. On clusters with tens of thousands of partitions,
+// this cuts the size of these metadata responses and the time spent parsing
+// them. Periodic refreshes (see MetadataMaxAge) and refreshes triggered for
+// any other reason still request every topic, so the cache never drifts far
+// from the cluster.
+//
+// If a topic is deleted and recreated with the same name, the request by ID
+// fails with UNKNOWN_TOPIC_ID, the client drops the topic from its cache, and
+// the next refresh requests the topic by name. This option has no effect for
+// regex consumers, which always request all topics.
+func MetadataTopicIDs() Opt {
+	return clientOpt{func(cfg *cfg) { cfg.metadataTopicIDs = true }}
 }
 
 // SASL appends sasl authentication options to use for all connections.
@@ -890,6 +1348,41 @@ func WithHooks(hooks ...Hook) Opt {
 	return clientOpt{func(cfg *cfg) { cfg.hooks = append(cfg.hooks, hooks...) }}
 }
 
+// OffloadPayloads uses the claim-check pattern to keep large values out of
+// Kafka: when producing, record values larger than threshold bytes are stored
+// with the offloader and replaced with a [ClaimCheckHeader] header containing
+// the returned reference. When consuming, records with a claim check header
+// are transparently rehydrated with the value fetched from the offloader
+// before they are returned from polling.
+//
+// Storing a value is done synchronously in Produce, before the record is
+// buffered, so that records remain ordered. If storing fails, the record is
+// failed with the error. Once a record is produced, its original value and
+// headers are restored before the record's promise is called.
+//
+// Rehydrating values is done concurrently when polling, and polling blocks
+// until all values in the poll are fetched. If a value cannot be fetched, the
+// record is returned with its claim check header and a nil value, and the
+// record's partition has an error; see [ClaimCheckRef].
+//
+// A threshold of zero or less disables offloading when producing, which can
+// be used for consumers that only need to rehydrate records.
+func OffloadPayloads(offloader PayloadOffloader, threshold int) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.offloader, cfg.offloadThreshold = offloader, threshold }}
+}
+
+// InjectFaults adds fault injectors to the client, which are used to delay
+// or fail writes to brokers and to drop or corrupt responses. This option is
+// meant for resilience testing only: faults are injected inside the client so
+// that tests exercise the client's real error handling paths without requiring
+// a proxy. See FaultInjector for more information, and DelayWrites,
+// DropEveryNthResponse, and CorruptEveryNthFetchCRC for the provided injectors.
+//
+// This option can be used multiple times; all injectors are used.
+func InjectFaults(injectors ...FaultInjector) Opt {
+	return clientOpt{func(cfg *cfg) { cfg.faults = append(cfg.faults, injectors...) }}
+}
+
 // WithPools sets memory pools to use wherever relevant.
 //
 // Pools can be used to optimize memory usage for data that is frequently
@@ -1001,6 +1494,34 @@ func DefaultProduceTopicAlways() ProducerOpt {
 	return producerOpt{func(cfg *cfg) { cfg.defaultProduceTopicAlways = true }}
 }
 
+// ProduceTopicAllowList restricts the client to only produce to the given
+// topics. Producing a record to any other topic fails the record immediately
+// with [ErrTopicNotAllowed], before the record is buffered or any request is
+// issued. This can be used to guard against a misconfigured service writing
+// to the wrong topics in a shared cluster. This option can be used multiple
+// times to allow more topics.
+//
+// If a DefaultProduceTopic is set, it must be in the allow list, otherwise
+// NewClient fails.
+func ProduceTopicAllowList(topics ...string) ProducerOpt {
+	return producerOpt{func(cfg *cfg) {
+		if cfg.produceAllowTopics == nil {
+			cfg.produceAllowTopics = make(map[string]struct{}, len(topics))
+		}
+		for _, t := range topics {
+			cfg.produceAllowTopics[t] = struct{}{}
+		}
+	}}
+}
+
+func (cfg *cfg) canProduceTo(topic string) bool {
+	if cfg.produceAllowTopics == nil {
+		return true
+	}
+	_, ok := cfg.produceAllowTopics[topic]
+	return ok
+}
+
 // Acks represents the number of acks a broker leader must have before
 // a produce request is considered complete.
 //
@@ -1072,7 +1593,7 @@ func MaxProduceRequestsInflightPerBroker(n int) ProducerOpt {
 // Alternatively, if you want finer control over compression you can use
 // [WithCompressor] for complete control.
 func ProducerBatchCompression(preference ...CompressionCodec) ProducerOpt {
-	return producerOpt{func(cfg *cfg) { cfg.compression = preference }}
+	return dynProducerOpt{func(cfg *cfg) { cfg.compression = preference }}
 }
 
 // WithCompressor allows you to completely control how produce batches are
@@ -1082,7 +1603,46 @@ func ProducerBatchCompression(preference ...CompressionCodec) ProducerOpt {
 // for simplicity (or specify nothing, which opts into snappy by default).
 // The client default compressor is the [DefaultCompressor].
 func WithCompressor(compressor Compressor) ProducerOpt {
-	return producerOpt{func(cfg *cfg) { cfg.compressor = compressor }}
+	return dynProducerOpt{func(cfg *cfg) { cfg.compressor = compressor }}
+}
+
+// ValidateTopicMaxMessageBytes opts in to learning each produced topic's
+// max.message.bytes with DescribeConfigs and failing records that are larger
+// than the limit immediately when they are produced, with [ErrRecordTooLarge].
+// Without this option, oversized records are buffered, batched, possibly
+// compressed, and only then rejected by the broker.
+//
+// The limit for a topic is loaded asynchronously the first time the topic is
+// produced to and is refreshed after the metadata max age passes. Records
+// produced before the limit is known are not validated locally. A record is
+// compared as if it were alone in an uncompressed batch, so records are only
+// validated if batches are not compressed: the default compression is snappy,
+// meaning you must also use ProducerBatchCompression(NoCompression()) to
+// validate records locally.
+//
+// The client must be authorized to describe the configs of produced topics;
+// if it is not, records are not validated and a warning is logged.
+func ValidateTopicMaxMessageBytes() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.validateTopicMaxBytes = true }}
+}
+
+// ProduceDryRun opts in to a mode where producing goes through everything
+// except actually writing to brokers: records are partitioned, buffered,
+// batched, and compressed, and produce requests are encoded, but requests are
+// never sent. Instead, every batch is completed successfully as if the broker
+// had accepted it. This can be used to validate partitioning strategies and
+// batch sizes before producing for real.
+//
+// The client still loads metadata to learn topics and partitions, but does
+// not initialize a producer ID. Records are promised with the partition they
+// were assigned and with offsets that start at 0 and are contiguous per
+// partition. Computed batch sizes (uncompressed and compressed) are available
+// through [HookProduceBatchWritten] and [Client.Metrics]. Kafka has no
+// validate-only mode for producing, so nothing is ever sent to brokers.
+//
+// This option cannot be used with a TransactionalID.
+func ProduceDryRun() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.produceDryRun = true }}
 }
 
 // ProducerBatchMaxBytes upper bounds the size of a record batch, overriding
@@ -1098,6 +1658,12 @@ func WithCompressor(compressor Compressor) ProducerOpt {
 // Note that this is the maximum size of a record batch before compression. If
 // a batch compresses poorly and actually grows the batch, the uncompressed
 // form will be used.
+//
+// If a broker rejects a batch with MESSAGE_TOO_LARGE or RECORD_LIST_TOO_LARGE
+// (for example, because a topic has a lower max.message.bytes than this
+// option), the client splits the batch in half and retries, repeatedly, until
+// the broker accepts the batches. Only a single record that the broker still
+// rejects is failed; other records in the partition continue to be produced.
 func ProducerBatchMaxBytes(v int32) ProducerOpt {
 	return producerOpt{func(cfg *cfg) { cfg.maxRecordBatchBytes = v }}
 }
@@ -1167,6 +1733,14 @@ func RecordRetries(n int) ProducerOpt {
 	return producerOpt{func(cfg *cfg) { cfg.recordRetries = int64(n) }}
 }
 
+// ProduceRetryBackoff sets the backoff to use between produce request
+// failures to a broker, overriding RetryBackoffFn for producing only. This
+// also applies to retrying requests that initialize producer IDs and manage
+// transactions.
+func ProduceRetryBackoff(backoff Backoff) ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.produceBackoff = backoff }}
+}
+
 // UnknownTopicRetries sets the number of times a record can fail with
 // UNKNOWN_TOPIC_OR_PARTITION, overriding the default 4.
 //
@@ -1210,7 +1784,7 @@ func ProducerOnDataLossDetected(fn func(string, int32)) ProducerOpt {
 // If a produce request is triggered by any topic partition, all partitions
 // with a possible batch to be sent are used and all lingers are reset.
 func ProducerLinger(linger time.Duration) ProducerOpt {
-	return producerOpt{func(cfg *cfg) { cfg.linger = linger }}
+	return dynProducerOpt{func(cfg *cfg) { cfg.linger = lazyDur(linger) }}
 }
 
 // ManualFlushing disables auto-flushing when producing. While you can still
@@ -1222,6 +1796,144 @@ func ManualFlushing() ProducerOpt {
 	return producerOpt{func(cfg *cfg) { cfg.manualFlushing = true }}
 }
 
+// RecordEndToEndTimestamps adds the [EndToEndLatencyHeader] header to every
+// produced record, containing the time the record was passed to Produce.
+// Consumers using [MeasureEndToEndLatency] use this header to measure produce
+// to consume latency.
+//
+// The header is added before any HookProduceRecordBuffered hooks are called.
+// If a record already has the header (i.e., because it is being produced
+// again), the header value is updated.
+func RecordEndToEndTimestamps() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.e2eTimestamps = true }}
+}
+
+// TimestampSource controls how the timestamps of produced records are chosen.
+//
+// The default is TimestampAtProduce.
+type TimestampSource struct {
+	val int8
+}
+
+// TimestampAtProduce sets the timestamp of records that do not have one to the
+// client's wall clock when the record is passed to Produce.
+func TimestampAtProduce() TimestampSource { return TimestampSource{0} }
+
+// TimestampAtFlush sets the timestamp of every record to the client's wall
+// clock when the record's batch is first written in a produce request. All
+// records in a batch share one timestamp, and any timestamp set on a record
+// before producing is overwritten. The record's Timestamp field is updated
+// before the record's promise is called.
+//
+// Because the delivery timeout is measured from a batch's first record
+// timestamp, RecordDeliveryTimeout is measured from when a batch is first
+// written rather than from when its first record was buffered.
+func TimestampAtFlush() TimestampSource { return TimestampSource{1} }
+
+// TimestampUserOnly only uses timestamps set on records before producing.
+// Producing a record without a timestamp fails the record with
+// ErrMissingTimestamp.
+func TimestampUserOnly() TimestampSource { return TimestampSource{2} }
+
+// TimestampMonotonic sets the timestamp of records that do not have one from
+// the client's monotonic clock, anchored to the wall clock when the client was
+// created. Timestamps do not go backwards if the system wall clock is stepped
+// backwards (i.e., by NTP), at the cost of drifting from the wall clock in
+// long lived clients if the system clock is adjusted.
+func TimestampMonotonic() TimestampSource { return TimestampSource{3} }
+
+// RecordTimestamps sets how the timestamps of produced records are chosen,
+// overriding the default TimestampAtProduce.
+//
+// To validate chosen timestamps against the broker's clock, produce to topics
+// that use LogAppendTime and use HookProduceTimestampSkew.
+func RecordTimestamps(source TimestampSource) ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.timestampSource = source }}
+}
+
+// OrderedProducePromises guarantees that promises for records buffered to the
+// same partition are called in the order the records were buffered, which for
+// successfully produced records is offset order.
+//
+// Promises are always called serially, but by default, promises can be called
+// out of order within a partition: a record can be failed individually (for
+// example, its context is canceled before its batch is sent) while earlier
+// records are still in flight, and if idempotency is disabled, retries can
+// reorder batches. With this option, a promise is held until every promise
+// for a record buffered before it to the same partition has been called.
+//
+// Records that fail before being buffered to a partition (for example, if the
+// topic does not exist) are not ordered and have their promises called
+// immediately.
+func OrderedProducePromises() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.orderedPromises = true }}
+}
+
+// VerifyAmbiguousProduces opts in to verifying whether batches landed after
+// ambiguous produce errors. A produce error is ambiguous if the request may
+// have been written before the error, for example if the connection was cut
+// or the request timed out waiting for a response. In this case, the client
+// cannot know whether the broker processed the request.
+//
+// When verifying, the client lists the offset of the batch's first timestamp
+// in the batch's partition, and then fetches from that offset up to the high
+// watermark looking for a batch with the producer ID, epoch, and sequence
+// number the batch was written with. The result is logged and passed to any
+// [HookProduceBatchVerified] hooks, reporting whether the batch landed and
+// whether retrying the batch could create duplicates.
+//
+// A batch is not retried until it is verified, since a retry is written with
+// the same producer ID, epoch, and sequence number and would otherwise be
+// mistaken for the original write. Verifying takes at most 30s; otherwise,
+// verification does not change how the batch is retried and is meant to
+// provide visibility into duplicates. Verification is best effort: a batch
+// written but not yet replicated is beyond the high watermark and is not
+// found. Batches can only be identified if the client is idempotent.
+func VerifyAmbiguousProduces() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.verifyAmbiguous = true }}
+}
+
+// TeeProduce copies records produced to the given topics (or all topics, if
+// none are specified) to a second client, which is usually configured to
+// produce to a different cluster. This is useful for shadowing production
+// traffic to a new cluster for testing.
+//
+// By default, teeing is asynchronous and best effort: a record is copied to
+// the tee client with TryProduce and is dropped if the tee client has the
+// maximum amount of records or bytes buffered. The result of producing to the
+// tee client does not affect the original record. See TeeProduceSync to wait
+// for the teed record to finish before the original record's promise is
+// called.
+//
+// Records are copied before they are buffered in this client, so copies may
+// have different timestamps and partitions unless the records set them.
+// Client.TeeStats returns counters for how many records were teed and how
+// often the two clients diverged.
+func TeeProduce(to *Client, topics ...string) ProducerOpt {
+	return producerOpt{func(cfg *cfg) {
+		cfg.teeClient = to
+		cfg.teeTopics = make(map[string]struct{}, len(topics))
+		for _, topic := range topics {
+			cfg.teeTopics[topic] = struct{}{}
+		}
+	}}
+}
+
+// TeeProduceSync makes teeing with TeeProduce synchronous: a record's promise
+// is not called until the copy of the record finishes producing to the tee
+// client, and copying a record blocks if the tee client has the maximum
+// amount of records or bytes buffered. This slows producing to the speed of
+// the slower of the two clients, but ensures that no records are dropped
+// from the tee. You likely want to use RecordDeliveryTimeout on the tee
+// client so that an unavailable tee cluster does not block producing forever.
+//
+// A record's promise may be called from the tee client once the copy
+// finishes, so promises are not necessarily called in order. Flush waits for
+// these promises to be called.
+func TeeProduceSync() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.teeSync = true }}
+}
+
 // RecordDeliveryTimeout sets a rough time of how long a record can sit around
 // in a batch before timing out, overriding the unlimited default.
 //
@@ -1277,6 +1989,28 @@ func TransactionalID(id string) ProducerOpt {
 	return producerOpt{func(cfg *cfg) { cfg.txnID = &id }}
 }
 
+// TransactionalIDPerGroupMember uses the TransactionalID as a prefix, suffixed
+// with the client's group member ID (or group instance ID, if using static
+// membership), so that horizontally scaled group consumers that produce
+// transactionally do not need to allocate unique transactional IDs.
+//
+// As of Kafka 2.5 (KIP-447), zombie producers are fenced by the group
+// generation when committing offsets in a transaction, so a transactional ID
+// only needs to be unique per live group member. When beginning a transaction,
+// if the client's member ID changed since the last transaction (for example,
+// the client was kicked from the group and rejoined as a new member), the
+// client switches to the new transactional ID and initializes a new producer
+// ID. The generation itself is not part of the ID, because that would create
+// a new transactional ID on every rebalance.
+//
+// The member ID is only known after the client joins the group, so
+// BeginTransaction returns an error if the client has not yet joined; poll
+// before beginning a transaction. This option requires ConsumerGroup and
+// requires Kafka 2.5+ for the fencing semantics described above.
+func TransactionalIDPerGroupMember() ProducerOpt {
+	return producerOpt{func(cfg *cfg) { cfg.txnIDPerMember = true }}
+}
+
 // TransactionTimeout sets the allowed for a transaction, overriding the
 // default 40s. It is a good idea to keep this less than a group's session
 // timeout, so that a group member will always be alive for the duration of a
@@ -1301,7 +2035,14 @@ func TransactionTimeout(timeout time.Duration) ProducerOpt {
 //
 // This corresponds to the Java fetch.max.wait.ms setting.
 func FetchMaxWait(wait time.Duration) ConsumerOpt {
-	return consumerOpt{func(cfg *cfg) { cfg.maxWait = int32(wait.Milliseconds()) }}
+	return dynConsumerOpt{func(cfg *cfg) { cfg.maxWait = lazyI32(wait.Milliseconds()) }}
+}
+
+// FetchRetryBackoff sets the backoff to use between fetch request failures
+// to a broker, overriding RetryBackoffFn for consuming only. This also
+// applies to retrying requests that list and validate offsets.
+func FetchRetryBackoff(backoff Backoff) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.fetchBackoff = backoff }}
 }
 
 // FetchMaxBytes sets the maximum amount of bytes a broker will try to send
@@ -1318,7 +2059,7 @@ func FetchMaxWait(wait time.Duration) ConsumerOpt {
 // recommended to set this option so that decompression does not eat all of
 // your RAM.
 func FetchMaxBytes(b int32) ConsumerOpt {
-	return consumerOpt{func(cfg *cfg) { cfg.maxBytes = lazyI32(b) }}
+	return dynConsumerOpt{func(cfg *cfg) { cfg.maxBytes = lazyI32(b) }}
 }
 
 // FetchMinBytes sets the minimum amount of bytes a broker will try to send
@@ -1340,7 +2081,7 @@ func FetchMinBytes(b int32) ConsumerOpt {
 //
 // This corresponds to the Java max.partition.fetch.bytes setting.
 func FetchMaxPartitionBytes(b int32) ConsumerOpt {
-	return consumerOpt{func(cfg *cfg) { cfg.maxPartBytes = lazyI32(b) }}
+	return dynConsumerOpt{func(cfg *cfg) { cfg.maxPartBytes = lazyI32(b) }}
 }
 
 // MaxConcurrentFetches sets the maximum number of fetch requests to allow in
@@ -1371,6 +2112,79 @@ func MaxConcurrentFetches(n int) ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.maxConcurrentFetches = n }}
 }
 
+// ConcurrentFetchesPerBroker sets the number of fetch requests the client can
+// have in flight to a single broker at once, overriding the default of 1. This
+// can be at most 16.
+//
+// By default, the client issues one fetch request at a time to each broker,
+// covering every partition the broker leads, and that request is bounded by
+// FetchMaxBytes. If one broker leads many of your partitions, a single fetch
+// response can become the bottleneck. With this option, the partitions on each
+// broker are split into n disjoint sets, and each set is fetched on its own
+// connection with its own fetch session, so that up to n requests (and up to n
+// times FetchMaxBytes) can be in flight or buffered per broker. Each
+// partition is always fetched in the same set, so ordering within a partition
+// is unaffected.
+//
+// Each concurrent fetch counts against MaxConcurrentFetches.
+func ConcurrentFetchesPerBroker(n int) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.fetchConcurrencyPerBroker = n }}
+}
+
+// FetchTargetBufferedBytes sets a target for how many bytes the client keeps
+// buffered per consumed partition, overriding the default of no target.
+//
+// By default, every broker is fetched from as soon as its previous fetch is
+// drained, and every partition on that broker is included in the fetch. If you
+// poll everything at once, every broker is fetched from at once, and the
+// amount of memory buffered between polls is bounded only by FetchMaxBytes
+// per broker.
+//
+// With this option, the client budgets roughly perPartition bytes for each
+// partition being consumed. Each partition in a fetch is requested with at
+// most perPartition bytes (or FetchMaxPartitionBytes, if smaller), and a
+// partition is only added to a fetch if the bytes buffered and requested
+// across the whole client leave room for it in the budget. Once the budget is
+// exhausted, fetching stops until polling drains the buffered bytes back down
+// to half of the budget, at which point fetching resumes. This smooths memory
+// usage and avoids every broker being fetched from at the same moment after a
+// large poll.
+//
+// As with FetchMaxPartitionBytes, a single batch larger than the target is
+// still returned so that the client can make progress, so the target is a
+// soft limit. Buffered bytes are measured the same as BufferedFetchBytes.
+func FetchTargetBufferedBytes(perPartition int32) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.fetchTargetBufferedBytes = perPartition }}
+}
+
+// MaxPollRecords caps the number of records a single PollFetches or
+// PollRecords call returns, overriding the default of no cap. If PollRecords
+// is called with a smaller limit, the smaller limit is used.
+//
+// Records beyond the cap stay buffered, and the next poll returns them
+// immediately without waiting for a new fetch. This is useful if you process
+// polled records in batches and want predictable batch sizes regardless of
+// how much a single fetch returns. The cap is split round robin across all
+// buffered partitions, as documented on PollRecords.
+//
+// This corresponds to the Java max.poll.records setting.
+func MaxPollRecords(n int) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.maxPollRecords = n }}
+}
+
+// MaxPollBytes caps the number of bytes a single PollFetches or PollRecords
+// call returns, overriding the default of no cap. Bytes are measured the same
+// as BufferedFetchBytes: the size of each record's key, value, and headers.
+//
+// As with MaxPollRecords, records beyond the cap stay buffered and the next
+// poll returns them immediately. A poll always returns at least one record if
+// any is buffered, even if that record alone is larger than the cap. This can
+// be combined with MaxPollRecords, in which case a poll stops at whichever
+// limit is reached first.
+func MaxPollBytes(n int64) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.maxPollBytes = n }}
+}
+
 // ConsumeStartOffset sets the offset to start consuming from when consuming a
 // partition for the first time. If you do not set [ConsumeResetOffset], this
 // is also the offset to reset to if the client sees an OffsetOutOfRange error
@@ -1455,6 +2269,55 @@ func ConsumeResetOffset(offset Offset) ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.resetOffset, cfg.setResetOffset = offset, true }}
 }
 
+// ConsumeResetOffsetFn sets a function that returns the offset to start
+// consuming a partition from when the partition has no committed offset, or to
+// reset to if the client sees OffsetOutOfRange on the first fetch of a
+// partition. This overrides both [ConsumeStartOffset] and
+// [ConsumeResetOffset].
+//
+// The function is called lazily, whenever the client needs a start or reset
+// offset for a partition, and may be called more than once for the same
+// partition. This allows seeding consumption from offsets or timestamps
+// stored in an external system without loading every checkpoint upfront. For example, to resume from a millisecond checkpoint,
+// return NewOffset().AfterMilli(checkpoint); for a partition without a
+// checkpoint, return NewOffset().AtStart() (or any other offset).
+//
+// When group consuming, the function is called when a partition is assigned
+// and the group has no commit for it. When direct consuming, the function is
+// called the first time a partition is seen in a topic being consumed;
+// partitions specified in [ConsumePartitions] use their specified offsets.
+// The function is also called every time a fetch for the partition returns
+// OffsetOutOfRange. If the partition has already been consumed, only a
+// returned [NoResetOffset] is used; otherwise, the client resets to the
+// offset nearest the last consumed record.
+// Returning [NoResetOffset] disables resetting the partition on
+// OffsetOutOfRange, the same as if [NoResetOffset] was passed to
+// ConsumeResetOffset.
+//
+// The function must not block for long: it is called while the client is
+// assigning partitions or handling a fetch response.
+func ConsumeResetOffsetFn(fn func(topic string, partition int32) Offset) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.resetOffsetFn = fn }}
+}
+
+// startOffsetFor returns the offset to start consuming a partition from if
+// there is no committed offset for it.
+func (cfg *cfg) startOffsetFor(topic string, partition int32) Offset {
+	if cfg.resetOffsetFn != nil {
+		return cfg.resetOffsetFn(topic, partition)
+	}
+	return cfg.startOffset
+}
+
+// resetOffsetFor returns the offset to reset a partition to if the first
+// fetch for it is out of range.
+func (cfg *cfg) resetOffsetFor(topic string, partition int32) Offset {
+	if cfg.resetOffsetFn != nil {
+		return cfg.resetOffsetFn(topic, partition)
+	}
+	return cfg.resetOffset
+}
+
 // Rack specifies where the client is physically located and changes fetch
 // requests to consume from the closest replica as opposed to the leader
 // replica.
@@ -1487,7 +2350,9 @@ func FetchIsolationLevel(level IsolationLevel) ConsumerOpt {
 // KeepControlRecords sets the client to keep control messages and return
 // them with fetches, overriding the default that discards them.
 //
-// Generally, control messages are not useful.
+// Generally, control messages are not useful, but change data capture and
+// audit tools can use transaction markers to reconstruct transaction
+// boundaries. Use Record.ControlRecord to decode a kept control record.
 func KeepControlRecords() ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.keepControl = true }}
 }
@@ -1521,9 +2386,37 @@ func ConsumePartitions(partitions map[string]map[int32]Offset) ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.partitions = partitions }}
 }
 
+// ConsumeTopicAllowList restricts the client to only consume from the given
+// topics. This option can be used multiple times to allow more topics.
+//
+// If any topic in ConsumeTopics (when not consuming with regex) or in
+// ConsumePartitions is not in the allow list, NewClient fails. When consuming
+// with regex, topics that match but are not in the allow list are skipped.
+// Topics that are not in the allow list are dropped from AddConsumeTopics and
+// AddConsumePartitions with an error log.
+func ConsumeTopicAllowList(topics ...string) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) {
+		if cfg.consumeAllowTopics == nil {
+			cfg.consumeAllowTopics = make(map[string]struct{}, len(topics))
+		}
+		for _, t := range topics {
+			cfg.consumeAllowTopics[t] = struct{}{}
+		}
+	}}
+}
+
+func (cfg *cfg) canConsume(topic string) bool {
+	if cfg.consumeAllowTopics == nil {
+		return true
+	}
+	_, ok := cfg.consumeAllowTopics[topic]
+	return ok
+}
+
 // ConsumeRegex sets the client to parse all topics passed to ConsumeTopics as
 // regular expressions. You can further use ConsumeExcludeTopics to exclude
-// topics that would match any ConsumeTopics regex.
+// topics that would match any ConsumeTopics regex, and ConsumeTopicMatcher to
+// match topics with a custom function.
 //
 // When consuming via regex, every metadata request loads *all* topics, so that
 // all topics can be passed to any regular expressions. Every topic is
@@ -1537,7 +2430,8 @@ func ConsumeRegex() ConsumerOpt {
 // This option only has effect when ConsumeRegex is enabled.
 //
 // Topics matching any of the provided regular expressions will be excluded from
-// consumption, even if they match patterns provided to ConsumeTopics.
+// consumption, even if they match patterns provided to ConsumeTopics or a
+// ConsumeTopicMatcher function.
 func ConsumeExcludeTopics(topics ...string) ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) {
 		if cfg.excludeTopics == nil {
@@ -1549,6 +2443,66 @@ func ConsumeExcludeTopics(topics ...string) ConsumerOpt {
 	}}
 }
 
+// ConsumeTopicMatcher sets a function to match topics when consuming via
+// regex, in addition to any regular expressions passed to ConsumeTopics. This
+// option only has effect when ConsumeRegex is enabled.
+//
+// A topic is consumed if it matches any ConsumeTopics regular expression or if
+// fn returns true, and if it does not match any ConsumeExcludeTopics regular
+// expression. Like regular expressions, fn is evaluated only once per topic,
+// and internal topics are never consumed. With a matcher, ConsumeTopics is
+// optional, which allows logic such as "all topics except those with a prefix"
+// that is unwieldy to express with one regular expression:
+//
+//	kgo.ConsumeRegex(),
+//	kgo.ConsumeTopicMatcher(func(topic string) bool {
+//		return !strings.HasPrefix(topic, "_")
+//	}),
+//
+// The function is called serially from the client's metadata loop and must
+// not block. If using the next generation group protocol (KIP-848), the
+// client falls back to the classic protocol, because the broker matches
+// regular expressions server side and cannot evaluate a custom matcher.
+func ConsumeTopicMatcher(fn func(topic string) bool) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.topicMatcher = fn }}
+}
+
+// ConsumeRegexDropIdleTopics drops regex matched topics that have had no
+// records consumed for at least idle, calling onDrop (if non-nil) with the
+// dropped topics after they are purged from consuming. This option only has
+// effect when ConsumeRegex is enabled.
+//
+// By default, every topic that matches a regex is consumed until it is
+// deleted. In clusters with many short lived topics that are never deleted,
+// the consumed set of topics can grow without bound. With this option, a topic
+// is considered idle if no records have been polled from it for the idle
+// duration since it was first discovered or since records were last polled.
+// Idle topics are purged as if by PurgeTopicsFromConsuming and are not
+// consumed again, even though they still match. If a dropped topic is later
+// missing from a metadata response (i.e., it is deleted), the client forgets
+// that it dropped the topic, so that a recreated topic is consumed again.
+//
+// Idleness is checked on metadata updates, so topics are dropped at most
+// MetadataMaxAge after they become idle.
+func ConsumeRegexDropIdleTopics(idle time.Duration, onDrop func(cl *Client, topics []string)) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.regexDropIdle, cfg.regexOnDropIdle = idle, onDrop }}
+}
+
+// ConsumeRegexUnmatchedTTL evaluates topics that did not match any regex (or
+// ConsumeTopicMatcher) again once ttl has passed since they were last
+// evaluated. This option only has effect when ConsumeRegex is enabled.
+//
+// A regex consumer requests metadata for every topic in the cluster. Each
+// topic is evaluated once when it is first seen, and topics that do not match
+// are remembered and skipped on every later metadata update, so that clusters
+// with tens of thousands of topics do not rescan or reprocess topics the
+// client will never use. By default, an unmatched topic is remembered until
+// it is deleted. If you use ConsumeTopicMatcher with a function whose answer
+// can change over time, this option allows the function to be asked again.
+func ConsumeRegexUnmatchedTTL(ttl time.Duration) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.regexUnmatchedTTL = ttl }}
+}
+
 // DisableFetchSessions sets the client to not use fetch sessions (Kafka 1.0+).
 //
 // A "fetch session" is a way to reduce bandwidth for fetch requests &
@@ -1621,13 +2575,72 @@ func KeepRetryableFetchErrors() ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.keepRetryableFetchErrors = true }}
 }
 
+// SuppressTransientFetchErrors strips transient errors (see
+// [Fetches.TransientErrors]) from polled fetches. The client automatically
+// recovers from transient errors, so most applications only log them; with
+// this option, only fatal errors are returned from polling, and you can
+// observe stripped errors with [HookFetchErrorSuppressed].
+//
+// If stripping errors leaves nothing to return, polling with a non-nil
+// context continues to wait for more fetches.
+func SuppressTransientFetchErrors() ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.suppressTransientErrors = true }}
+}
+
 // DisableFetchCRCValidation disables crc32 checksum validation when fetching.
-// This should only be used if you are working with a broker that does not
-// properly support CRCs in record batches.
+// This can be used if you are working with a broker that does not properly
+// support CRCs in record batches, or if you are on a trusted network and want
+// to save the CPU spent checksumming every fetched batch.
+//
+// Checksums are computed with hardware acceleration where available (SSE4.2
+// on amd64, the CRC32 instructions on arm64). Skipping validation matters
+// most for uncompressed batches, where checksumming is a large part of the
+// cost of processing a fetch; BenchmarkProcessFetchPartitionCRC measures it.
 func DisableFetchCRCValidation() ConsumerOpt {
 	return consumerOpt{func(cfg *cfg) { cfg.disableFetchCRCValidation = true }}
 }
 
+// DisableOffsetEpochValidation skips validating exact offsets that have a
+// leader epoch when partitions are assigned, trusting that the offsets are
+// valid and were not truncated.
+//
+// By default, when consuming from an exact offset that has an epoch (which is
+// the case for offsets committed by this client and loaded from the group or
+// from [Offset.WithEpoch]), the client issues an OffsetForLeaderEpoch request
+// to detect log truncation (KIP-320) before fetching. With thousands of
+// partitions, these requests can add seconds to startup. With this option,
+// the offset is used directly if the partition's leader is known, and the
+// epoch is kept so that truncation can still be detected while fetching.
+// Offsets for partitions without a known leader are still bounds checked
+// with ListOffsets.
+//
+// This option should only be used in trusted environments where unclean
+// leader elections are disabled and data loss is not a concern.
+func DisableOffsetEpochValidation() ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.disableOffsetEpochValidation = true }}
+}
+
+// OffsetLoadPartitionsPerRequest sets the maximum number of partitions
+// included in a single ListOffsets or OffsetForLeaderEpoch request when
+// loading where to start consuming, defaulting to 0 (unlimited).
+//
+// By default, the client issues one request per broker that contains every
+// partition that broker leads. Each broker processes a request serially, so
+// when starting to consume thousands of partitions, splitting the load into
+// many smaller requests that are issued concurrently can significantly cut
+// startup time, at the cost of more requests.
+func OffsetLoadPartitionsPerRequest(n int) ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.offsetLoadPartitionsPerRequest = n }}
+}
+
+// MeasureEndToEndLatency opts in to measuring the produce to consume latency
+// of polled records that were produced with [RecordEndToEndTimestamps].
+// Latencies can be read with [Client.EndToEndLatencies], and are passed to any
+// [HookFetchRecordEndToEnd] hooks.
+func MeasureEndToEndLatency() ConsumerOpt {
+	return consumerOpt{func(cfg *cfg) { cfg.e2eLatency = true }}
+}
+
 // RecheckPreferredReplicaInterval configures how long the consumer should
 // fetch from a preferred replica before switching back to the leader.
 // Periodically switching back to the leader allows the leader to re-choose a
@@ -1719,6 +2732,14 @@ func HeartbeatInterval(interval time.Duration) GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.heartbeatInterval = interval }}
 }
 
+// GroupRetryBackoff sets the backoff to use between failed group management
+// sessions and when retrying group requests (finding the coordinator,
+// joining, syncing, heartbeating, and committing and fetching offsets),
+// overriding RetryBackoffFn for group requests only.
+func GroupRetryBackoff(backoff Backoff) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.groupBackoff = backoff }}
+}
+
 // RequireStableFetchOffsets sets the group consumer to require "stable" fetch
 // offsets before consuming from the group. Proposed in KIP-447 and introduced
 // in Kafka 2.5, stable offsets are important when consuming from partitions
@@ -1737,6 +2758,14 @@ func RequireStableFetchOffsets() GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.requireStable = true }}
 }
 
+// WarmupJoinsGroup makes Client.Warmup also wait until the group member has
+// joined the group and received its first assignment (and OnPartitionsAssigned
+// has returned). Group joining always begins in the background once the
+// client is created; this option only makes Warmup wait for it.
+func WarmupJoinsGroup() GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.warmupJoinGroup = true }}
+}
+
 // BlockRebalanceOnPoll switches the client to block rebalances whenever you
 // poll until you explicitly call AllowRebalance. This option also ensures that
 // any OnPartitions{Assigned,Revoked,Lost} callbacks are only called when you
@@ -1772,10 +2801,30 @@ func RequireStableFetchOffsets() GroupOpt {
 // You can use [OnPartitionsCallbackBlocked] as a signal that a rebalance WANTS
 // to happen, but you are currently blocking it, and that you need to either
 // finish processing or abort processing to allow the rebalance to continue.
+// If you process partitions independently, you can use
+// [Client.BlockedRebalanceRevoking] and [Client.AllowRebalanceRevoking] to
+// allow a rebalance to revoke only the partitions you are done with. To guard
+// against a forgotten AllowRebalance stalling the group, see
+// [AllowRebalanceAfter].
 func BlockRebalanceOnPoll() GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.blockRebalanceOnPoll = true }}
 }
 
+// AllowRebalanceAfter automatically allows rebalances that have been blocked by
+// BlockRebalanceOnPoll for longer than d, as if AllowRebalance were called,
+// and logs a warning. By default, rebalances are blocked until you call
+// AllowRebalance.
+//
+// This is a safety net against a forgotten AllowRebalance stalling the entire
+// group: a member that blocks a rebalance past the rebalance timeout is
+// kicked from the group, and until then, every other member waits. This
+// should be set below the rebalance timeout. Note that once this fires, you
+// may be processing records for partitions that are no longer assigned to
+// you, which is exactly what BlockRebalanceOnPoll otherwise protects against.
+func AllowRebalanceAfter(d time.Duration) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.allowRebalanceAfter = d }}
+}
+
 // AdjustFetchOffsetsFn sets the function to be called when a group is joined
 // after offsets are fetched so that a user can adjust offsets before
 // consumption begins.
@@ -1795,6 +2844,46 @@ func AdjustFetchOffsetsFn(adjustOffsetsBeforeAssign func(context.Context, map[st
 	return groupOpt{func(cfg *cfg) { cfg.adjustOffsetsBeforeAssign = adjustOffsetsBeforeAssign }}
 }
 
+// OnPartitionsCheckpoint sets the function to be called when partitions are
+// handed over to another member, with the offsets the next owner of each
+// partition resumes consuming from. This is meant for applications that keep
+// local state (i.e., RocksDB or badger) derived from the partitions they
+// consume: the function can flush and checkpoint the state at exactly the
+// handover offsets, so that whoever restores the checkpoint later with
+// OnPartitionsRestore resumes consistently.
+//
+// This function is called after OnPartitionsRevoked, and thus after the
+// default OnPartitionsRevoked commit, and before the partitions are released
+// to the group. It is not called when partitions are lost (see
+// OnPartitionsLost), because lost partitions may already be owned elsewhere
+// and have no well defined handover offset. The offsets are the last
+// committed offsets this client knows of; a partition that was never
+// committed and never consumed has an offset of -1, meaning the next owner
+// resets per ConsumeResetOffset.
+//
+// This function is passed the client's context, which is only canceled if the
+// client is closed, and should not exceed the rebalance interval.
+func OnPartitionsCheckpoint(onCheckpoint func(context.Context, *Client, map[string]map[int32]EpochOffset)) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.onCheckpoint = onCheckpoint }}
+}
+
+// OnPartitionsRestore sets the function to be called when partitions are
+// assigned, once the offsets consumption resumes from are known and before
+// any record is fetched. This is the counterpart to OnPartitionsCheckpoint:
+// the function should restore local state to match the given offsets. The
+// offsets are those fetched from the group, after AdjustFetchOffsetsFn if
+// set; a partition with no committed offset has the offset from
+// ConsumeResetOffset.
+//
+// If the function returns an error, the group session ends with that error
+// and the member rejoins, as with AdjustFetchOffsetsFn.
+//
+// This function is passed a context that is canceled if the current group
+// session finishes, and is called after OnPartitionsAssigned.
+func OnPartitionsRestore(onRestore func(context.Context, *Client, map[string]map[int32]Offset) error) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.onRestore = onRestore }}
+}
+
 // OnPartitionsAssigned sets the function to be called when a group is joined
 // after partitions are assigned before fetches for those partitions begin.
 //
@@ -1815,6 +2904,24 @@ func OnPartitionsAssigned(onAssigned func(context.Context, *Client, map[string][
 	return groupOpt{func(cfg *cfg) { cfg.onAssigned = onAssigned }}
 }
 
+// OnStandbyAssignment sets the function to be called with this member's
+// standby assignment after every assignment when using the
+// CooperativeStandbyBalancer. The function is called after
+// OnPartitionsAssigned with the member's full standby set, which may be empty.
+//
+// This is only a notification: the client does not consume, fetch offsets
+// for, or otherwise act on standby partitions. Warming state for them, such
+// as by consuming with a separate client that is not in the group, is up to
+// you, as is stopping once a partition is no longer in the set. A standby
+// partition that is promoted to active is passed to OnPartitionsAssigned
+// before this function is called without it.
+//
+// This function is passed the client's context, which is only canceled if the
+// client is closed, and is given a new map that the user is free to modify.
+func OnStandbyAssignment(onStandby func(context.Context, *Client, map[string][]int32)) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.onStandby = onStandby }}
+}
+
 // OnPartitionsRevoked sets the function to be called once this group member
 // has partitions revoked.
 //
@@ -1871,6 +2978,8 @@ func OnPartitionsLost(onLost func(context.Context, *Client, map[string][]int32))
 // callbacks are blocked from [BlockRebalanceOnPoll]. You can use this as a
 // signal in your processing function to hurry up and unblock rebalancing
 // before your group member is kicked from the group at the session timeout.
+// The callback can call [Client.BlockedRebalanceRevoking] to see which
+// partitions the blocked rebalance is revoking.
 func OnPartitionsCallbackBlocked(fn func(context.Context, *Client)) GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.onBlocked = fn }}
 }
@@ -1942,6 +3051,25 @@ func AutoCommitMarks() GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.autocommitMarks = true }}
 }
 
+// RetryFencedCommits retries offset commits that are fenced because the
+// group rebalanced while the commit was in flight (IllegalGeneration,
+// UnknownMemberID, RebalanceInProgress, or StaleMemberEpoch).
+//
+// Once the client rejoins the group and has fetched the committed offsets for
+// its new assignment, the fenced offsets are committed again for partitions
+// that are still assigned to this member and that have not had a newer offset
+// committed in the meantime. Partitions that moved to another
+// member are never retried, because the new owner may have already committed
+// further. Each fenced commit is retried at most once, in the background: the
+// original commit's callback still receives the fenced error, and the result
+// of the retry is logged. FencedInstanceID and FencedMemberEpoch are never
+// retried.
+//
+// To be notified of fenced commits, use HookGroupCommitFenced.
+func RetryFencedCommits() GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.retryFencedCommits = true }}
+}
+
 // InstanceID sets the group consumer's instance ID, switching the group member
 // from "dynamic" to "static".
 //
@@ -1976,6 +3104,40 @@ func InstanceID(id string) GroupOpt {
 	return groupOpt{func(cfg *cfg) { cfg.instanceID = &id }}
 }
 
+// GroupMemberCensus opts in to publishing host and process metadata (hostname,
+// process ID, client start time, client ID, and software name and version) in
+// this member's group protocol metadata. All members of the group, including
+// those published by other clients using this option, can be inspected with
+// [Client.GroupMembers]. If this client becomes the group leader, it logs a
+// warning if multiple members are from the same process.
+//
+// The metadata is appended to the user data of each balancer's join group
+// metadata and is stripped by this client before balancing. The Java client
+// ignores the trailing metadata for its standard balancers, but you should not
+// use this option if the group has non-kgo members using a custom balancer
+// that parses user data strictly. This option has no effect with the KIP-848
+// next generation group protocol.
+func GroupMemberCensus() GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.groupCensus = true }}
+}
+
+// PreferredPartitions sets a function that returns the partitions this member
+// would prefer to be assigned, such as partitions whose state this member
+// already has in a local state store. The function is called every time this
+// member joins the group, and the preferences are published in this member's
+// group protocol metadata.
+//
+// Preferences are hints for the group leader's balancer. The standard
+// balancers ignore them; use CooperativeAffinityStickyBalancer to honor them,
+// or read them in a custom balancer with ConsumerBalancer.PreferredPartitions.
+// As with GroupMemberCensus, the preferences are appended to the user data of
+// each balancer's join group metadata and are stripped by this client before
+// balancing. This option has no effect with the KIP-848 next generation group
+// protocol.
+func PreferredPartitions(fn func() map[string][]int32) GroupOpt {
+	return groupOpt{func(cfg *cfg) { cfg.preferredPartitions = fn }}
+}
+
 // GroupProtocol sets the group's join protocol, overriding the default value
 // "consumer". The only reason to override this is if you are implementing
 // custom join and sync group logic.
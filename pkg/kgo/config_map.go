@@ -0,0 +1,337 @@
+package kgo
+
+import (
+	"crypto/tls"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// OptsFromConfigMap translates Java and librdkafka style client properties
+// (bootstrap.servers, compression.type, fetch.min.bytes, ...) into options,
+// easing migrations from other clients. This returns the options, the sorted
+// keys that could not be translated, and an error if any value is invalid.
+//
+// Translation is best effort: properties that do not have an equivalent
+// option, or whose semantics differ too much from any option, are returned as
+// unmapped rather than guessed at. Notably, ssl.* file locations are not read,
+// and setting security.protocol to SSL or SASL_SSL uses a default tls.Config.
+//
+// As in the Java client, setting acks to anything but all disables idempotent
+// producing unless enable.idempotence is explicitly true, in which case the
+// client fails validation. Properties that are only meaningful without
+// idempotency, such as max.in.flight.requests.per.connection, are reported as
+// unmapped when idempotency is enabled.
+func OptsFromConfigMap(m map[string]string) ([]Opt, []string, error) {
+	var (
+		opts     []Opt
+		unmapped []string
+		handled  = make(map[string]bool)
+	)
+
+	// get returns the value for the first key that exists, marking all
+	// given keys as handled; many properties have a Java and librdkafka
+	// name.
+	get := func(keys ...string) (string, string, bool) {
+		var (
+			k, v string
+			ok   bool
+		)
+		for _, key := range keys {
+			if kv, exists := m[key]; exists && !ok {
+				k, v, ok = key, strings.TrimSpace(kv), true
+			}
+			if _, exists := m[key]; exists {
+				handled[key] = true
+			}
+		}
+		return k, v, ok
+	}
+	bad := func(k, v string, err error) error {
+		return fmt.Errorf("invalid %s value %q: %w", k, v, err)
+	}
+	i32 := func(k, v string) (int32, error) {
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return 0, bad(k, v, err)
+		}
+		return int32(n), nil
+	}
+	millis := func(k, v string) (time.Duration, error) {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return 0, bad(k, v, err)
+		}
+		return time.Duration(n) * time.Millisecond, nil
+	}
+	boolean := func(k, v string) (bool, error) {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return false, bad(k, v, err)
+		}
+		return b, nil
+	}
+
+	// Idempotency and acks interact, so we handle them first.
+	idempotent := true
+	idempotentSet := false
+	if k, v, ok := get("enable.idempotence"); ok {
+		b, err := boolean(k, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		idempotent, idempotentSet = b, true
+	}
+	if k, v, ok := get("acks", "request.required.acks"); ok {
+		switch strings.ToLower(v) {
+		case "all", "-1":
+			opts = append(opts, RequiredAcks(AllISRAcks()))
+		case "1":
+			opts = append(opts, RequiredAcks(LeaderAck()))
+			idempotent = idempotent && idempotentSet
+		case "0":
+			opts = append(opts, RequiredAcks(NoAck()))
+			idempotent = idempotent && idempotentSet
+		default:
+			return nil, nil, bad(k, v, fmt.Errorf("unknown acks"))
+		}
+	}
+	if !idempotent {
+		opts = append(opts, DisableIdempotentWrite())
+	}
+	if k, v, ok := get("max.in.flight.requests.per.connection", "max.in.flight"); ok {
+		if idempotent {
+			unmapped = append(unmapped, k)
+		} else {
+			n, err := i32(k, v)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts = append(opts, MaxProduceRequestsInflightPerBroker(int(n)))
+		}
+	}
+
+	// Security: TLS and a username/password SASL mechanism.
+	if k, v, ok := get("security.protocol"); ok {
+		var useTLS, useSASL bool
+		switch strings.ToUpper(v) {
+		case "PLAINTEXT":
+		case "SSL":
+			useTLS = true
+		case "SASL_PLAINTEXT":
+			useSASL = true
+		case "SASL_SSL":
+			useTLS, useSASL = true, true
+		default:
+			return nil, nil, bad(k, v, fmt.Errorf("unknown security protocol"))
+		}
+		if useTLS {
+			opts = append(opts, DialTLSConfig(new(tls.Config)))
+		}
+		if useSASL {
+			mk, mechanism, _ := get("sasl.mechanism", "sasl.mechanisms")
+			_, user, _ := get("sasl.username")
+			_, pass, _ := get("sasl.password")
+			switch strings.ToUpper(mechanism) {
+			case "", "PLAIN":
+				opts = append(opts, SASL(plain.Auth{User: user, Pass: pass}.AsMechanism()))
+			case "SCRAM-SHA-256":
+				opts = append(opts, SASL(scram.Auth{User: user, Pass: pass}.AsSha256Mechanism()))
+			case "SCRAM-SHA-512":
+				opts = append(opts, SASL(scram.Auth{User: user, Pass: pass}.AsSha512Mechanism()))
+			default:
+				return nil, nil, bad(mk, mechanism, fmt.Errorf("unsupported sasl mechanism"))
+			}
+		}
+	}
+
+	for _, p := range []struct {
+		keys []string
+		fn   func(k, v string) (Opt, error)
+	}{
+		{[]string{"bootstrap.servers", "metadata.broker.list"}, func(_, v string) (Opt, error) {
+			var seeds []string
+			for s := range strings.SplitSeq(v, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					seeds = append(seeds, s)
+				}
+			}
+			return SeedBrokers(seeds...), nil
+		}},
+		{[]string{"client.id"}, func(_, v string) (Opt, error) { return ClientID(v), nil }},
+		{[]string{"client.rack"}, func(_, v string) (Opt, error) { return Rack(v), nil }},
+		{[]string{"metadata.max.age.ms", "topic.metadata.refresh.interval.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return MetadataMaxAge(d), err
+		}},
+		{[]string{"connections.max.idle.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return ConnIdleTimeout(d), err
+		}},
+		{[]string{"socket.connection.setup.timeout.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return DialTimeout(d), err
+		}},
+		{[]string{"retry.backoff.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return RetryBackoffFn(func(int) time.Duration { return d }), err
+		}},
+		{[]string{"max.request.size", "message.max.bytes"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return BrokerMaxWriteBytes(n), err
+		}},
+
+		// Producer
+		{[]string{"compression.type", "compression.codec"}, func(k, v string) (Opt, error) {
+			var codec CompressionCodec
+			switch strings.ToLower(v) {
+			case "none":
+				codec = NoCompression()
+			case "gzip":
+				codec = GzipCompression()
+			case "snappy":
+				codec = SnappyCompression()
+			case "lz4":
+				codec = Lz4Compression()
+			case "zstd":
+				codec = ZstdCompression()
+			default:
+				return nil, bad(k, v, fmt.Errorf("unknown compression"))
+			}
+			return ProducerBatchCompression(codec), nil
+		}},
+		{[]string{"linger.ms", "queue.buffering.max.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return ProducerLinger(d), err
+		}},
+		{[]string{"batch.size"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return ProducerBatchMaxBytes(n), err
+		}},
+		{[]string{"delivery.timeout.ms", "message.timeout.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return RecordDeliveryTimeout(d), err
+		}},
+		{[]string{"retries", "message.send.max.retries"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return RecordRetries(int(n)), err
+		}},
+		{[]string{"transactional.id"}, func(_, v string) (Opt, error) { return TransactionalID(v), nil }},
+		{[]string{"transaction.timeout.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return TransactionTimeout(d), err
+		}},
+
+		// Consumer
+		{[]string{"fetch.min.bytes"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return FetchMinBytes(n), err
+		}},
+		{[]string{"fetch.max.bytes"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return FetchMaxBytes(n), err
+		}},
+		{[]string{"max.partition.fetch.bytes"}, func(k, v string) (Opt, error) {
+			n, err := i32(k, v)
+			return FetchMaxPartitionBytes(n), err
+		}},
+		{[]string{"fetch.max.wait.ms", "fetch.wait.max.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return FetchMaxWait(d), err
+		}},
+		{[]string{"isolation.level"}, func(k, v string) (Opt, error) {
+			switch strings.ToLower(v) {
+			case "read_committed":
+				return FetchIsolationLevel(ReadCommitted()), nil
+			case "read_uncommitted":
+				return FetchIsolationLevel(ReadUncommitted()), nil
+			}
+			return nil, bad(k, v, fmt.Errorf("unknown isolation level"))
+		}},
+		{[]string{"auto.offset.reset"}, func(k, v string) (Opt, error) {
+			switch strings.ToLower(v) {
+			case "earliest", "smallest", "beginning":
+				return ConsumeResetOffset(NewOffset().AtStart()), nil
+			case "latest", "largest", "end":
+				return ConsumeResetOffset(NewOffset().AtEnd()), nil
+			case "none", "error":
+				return ConsumeResetOffset(NoResetOffset()), nil
+			}
+			return nil, bad(k, v, fmt.Errorf("unknown offset reset"))
+		}},
+
+		// Group
+		{[]string{"group.id"}, func(_, v string) (Opt, error) { return ConsumerGroup(v), nil }},
+		{[]string{"group.instance.id"}, func(_, v string) (Opt, error) { return InstanceID(v), nil }},
+		{[]string{"session.timeout.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return SessionTimeout(d), err
+		}},
+		{[]string{"heartbeat.interval.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return HeartbeatInterval(d), err
+		}},
+		{[]string{"max.poll.interval.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return RebalanceTimeout(d), err
+		}},
+		{[]string{"enable.auto.commit"}, func(k, v string) (Opt, error) {
+			b, err := boolean(k, v)
+			if err != nil || b {
+				return nil, err
+			}
+			return DisableAutoCommit(), nil
+		}},
+		{[]string{"auto.commit.interval.ms"}, func(k, v string) (Opt, error) {
+			d, err := millis(k, v)
+			return AutoCommitInterval(d), err
+		}},
+		{[]string{"partition.assignment.strategy"}, func(k, v string) (Opt, error) {
+			var balancers []GroupBalancer
+			for s := range strings.SplitSeq(v, ",") {
+				// Java uses class names, e.g.
+				// org.apache.kafka.clients.consumer.RangeAssignor.
+				s = strings.TrimSpace(s)
+				s = strings.ToLower(s[strings.LastIndexByte(s, '.')+1:])
+				switch strings.TrimSuffix(s, "assignor") {
+				case "range":
+					balancers = append(balancers, RangeBalancer())
+				case "roundrobin":
+					balancers = append(balancers, RoundRobinBalancer())
+				case "sticky":
+					balancers = append(balancers, StickyBalancer())
+				case "cooperative-sticky", "cooperativesticky":
+					balancers = append(balancers, CooperativeStickyBalancer())
+				default:
+					return nil, bad(k, v, fmt.Errorf("unknown assignment strategy %q", s))
+				}
+			}
+			return Balancers(balancers...), nil
+		}},
+	} {
+		k, v, ok := get(p.keys...)
+		if !ok {
+			continue
+		}
+		opt, err := p.fn(k, v)
+		if err != nil {
+			return nil, nil, err
+		}
+		if opt != nil {
+			opts = append(opts, opt)
+		}
+	}
+
+	for k := range m {
+		if !handled[k] {
+			unmapped = append(unmapped, k)
+		}
+	}
+	slices.Sort(unmapped)
+	return opts, unmapped, nil
+}
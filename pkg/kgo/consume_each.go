@@ -0,0 +1,119 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// TopicPartition is a topic and partition.
+type TopicPartition struct {
+	Topic     string
+	Partition int32
+}
+
+// ConsumeEach is a push based alternative to a PollFetches loop: the client
+// polls and calls fn with the records for each partition, one goroutine per
+// partition, until the context is canceled or the client is closed. This
+// returns the context error or ErrClientClosed.
+//
+// Records within a partition are always passed to fn in order, and fn is
+// never called concurrently for the same partition. Every partition from a
+// poll is processed before the client polls again, meaning a slow partition
+// delays the next poll for all partitions.
+//
+// If fn returns an error, the error is logged, the partition is paused, and
+// the partition is rewound to the first record passed to fn. Records after
+// the failed batch are discarded. Once the problem is fixed, resume the
+// partition with ResumeFetchPartitions to have the records delivered again.
+//
+// If you are group consuming with AutoCommitMarks, records are marked for
+// commit once fn returns successfully, giving at least once processing.
+// Without AutoCommitMarks, autocommitting commits offsets as records are
+// polled, before fn is called. If you use BlockRebalanceOnPoll, this calls
+// AllowRebalance after every poll is processed; this is recommended when
+// group consuming, because rewinding a partition races with a concurrent
+// rebalance.
+//
+// Fetch errors are logged and otherwise skipped, as they are with a poll loop
+// that does not act on errors.
+func (cl *Client) ConsumeEach(ctx context.Context, fn func(TopicPartition, []*Record) error) error {
+	for {
+		fs := cl.PollFetches(ctx)
+		if fs.IsClientClosed() {
+			return ErrClientClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fs.EachError(func(t string, p int32, err error) {
+			if !errors.Is(err, context.Canceled) {
+				cl.cfg.logger.Log(LogLevelWarn, "ConsumeEach skipping fetch error", "topic", t, "partition", p, "err", err)
+			}
+		})
+
+		// A partition can be in fetches more than once if we drained
+		// multiple buffered fetches; we merge in order.
+		var (
+			order []TopicPartition
+			recs  = make(map[TopicPartition][]*Record)
+		)
+		fs.EachPartition(func(p FetchTopicPartition) {
+			if len(p.Records) == 0 {
+				return
+			}
+			tp := TopicPartition{p.Topic, p.Partition}
+			if _, exists := recs[tp]; !exists {
+				order = append(order, tp)
+			}
+			recs[tp] = append(recs[tp], p.Records...)
+		})
+
+		var (
+			wg      sync.WaitGroup
+			mu      sync.Mutex
+			pause   map[string][]int32
+			rewinds map[string]map[int32]EpochOffset
+		)
+		for _, tp := range order {
+			rs := recs[tp]
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := fn(tp, rs); err != nil {
+					first := rs[0]
+					cl.cfg.logger.Log(LogLevelError, "ConsumeEach callback failed, pausing and rewinding partition",
+						"topic", tp.Topic,
+						"partition", tp.Partition,
+						"rewind_to", first.Offset,
+						"err", err,
+					)
+					mu.Lock()
+					defer mu.Unlock()
+					if pause == nil {
+						pause = make(map[string][]int32)
+						rewinds = make(map[string]map[int32]EpochOffset)
+					}
+					pause[tp.Topic] = append(pause[tp.Topic], tp.Partition)
+					if rewinds[tp.Topic] == nil {
+						rewinds[tp.Topic] = make(map[int32]EpochOffset)
+					}
+					rewinds[tp.Topic][tp.Partition] = EpochOffset{first.LeaderEpoch, first.Offset}
+					return
+				}
+				if cl.cfg.autocommitMarks {
+					cl.MarkCommitRecords(rs[len(rs)-1])
+				}
+			}()
+		}
+		wg.Wait()
+
+		if len(pause) > 0 {
+			cl.PauseFetchPartitions(pause)
+			cl.SetOffsets(rewinds)
+		}
+		if cl.cfg.blockRebalanceOnPoll {
+			cl.AllowRebalance()
+		}
+	}
+}
@@ -182,6 +182,12 @@ type consumer struct {
 	bufferedRecords atomicI64
 	bufferedBytes   atomicI64
 
+	// For FetchTargetBufferedBytes: the number of partitions being
+	// consumed across all sources, and the bytes requested by in flight
+	// fetches that have not yet been buffered.
+	numCursors    atomicI64
+	fetchReserved atomicI64
+
 	cl *Client
 
 	pausedMu sync.Mutex   // grabbed when updating paused
@@ -194,6 +200,12 @@ type consumer struct {
 	d  *directConsumer // if non-nil, we are consuming partitions directly
 	g  *groupConsumer  // if non-nil, we are consuming as a group member
 
+	// reMissAt tracks when topics were evaluated to not match any regex;
+	// see unmatchedRegexTopics and ConsumeRegexUnmatchedTTL. Topics that
+	// are not wanted for other reasons (idle topics) are not tracked.
+	// This is guarded by mu.
+	reMissAt map[string]time.Time
+
 	// On metadata update, if the consumer is set (direct or group), the
 	// client begins a goroutine that updates the consumer kind's
 	// assignments.
@@ -227,6 +239,15 @@ type consumer struct {
 	pollWaitMu    sync.Mutex
 	pollWaitC     *sync.Cond
 	pollWaitState uint64 // 0 == nothing, low 32 bits: # pollers, high 32: # waiting rebalances
+
+	// Partitions that waiting rebalances want to revoke, and partitions
+	// the user allowed to be revoked with AllowRebalanceRevoking. Both are
+	// guarded by pollWaitMu.
+	pollWaitRevoking []*map[string][]int32
+	pollWaitAllowed  map[string]map[int32]struct{}
+
+	e2e  e2eLatencies // only used if MeasureEndToEndLatency
+	idle idleTopics   // only used if ConsumeRegexDropIdleTopics
 }
 
 func (c *consumer) loadPaused() pausedTopics   { return c.paused.Load().(pausedTopics) }
@@ -245,6 +266,11 @@ func (c *consumer) waitAndAddPoller() {
 	// Rebalance always takes priority, but if there are no active
 	// rebalances, our poll blocks rebalances.
 	c.pollWaitState++
+
+	// Polling cannot begin while a rebalance is waiting, so anything
+	// allowed to be revoked has been revoked, or may be returned again
+	// from this poll: prior allowances no longer apply.
+	c.pollWaitAllowed = nil
 }
 
 func (c *consumer) unaddPoller() {
@@ -263,26 +289,131 @@ func (c *consumer) allowRebalance() {
 	}
 	c.pollWaitMu.Lock()
 	defer c.pollWaitMu.Unlock()
+	c.allowRebalanceLocked()
+}
+
+func (c *consumer) allowRebalanceLocked() {
 	// When allowing rebalances, the user is explicitly saying all pollers
 	// are done. We mask them out.
 	c.pollWaitState &= math.MaxUint32 << 32
+	c.pollWaitAllowed = nil
+	c.pollWaitC.Broadcast()
+}
+
+func (c *consumer) allowRebalanceRevoking(revoking map[string][]int32) {
+	if !c.cl.cfg.blockRebalanceOnPoll {
+		return
+	}
+	c.pollWaitMu.Lock()
+	defer c.pollWaitMu.Unlock()
+	if c.pollWaitAllowed == nil {
+		c.pollWaitAllowed = make(map[string]map[int32]struct{})
+	}
+	for t, ps := range revoking {
+		allowed := c.pollWaitAllowed[t]
+		if allowed == nil {
+			allowed = make(map[int32]struct{}, len(ps))
+			c.pollWaitAllowed[t] = allowed
+		}
+		for _, p := range ps {
+			allowed[p] = struct{}{}
+		}
+	}
 	c.pollWaitC.Broadcast()
 }
 
-func (c *consumer) waitAndAddRebalance() {
+func (c *consumer) blockedRebalanceRevoking() map[string][]int32 {
+	if !c.cl.cfg.blockRebalanceOnPoll {
+		return nil
+	}
+	c.pollWaitMu.Lock()
+	defer c.pollWaitMu.Unlock()
+	var blocked map[string][]int32
+	for _, revoking := range c.pollWaitRevoking {
+		for t, ps := range *revoking {
+			if blocked == nil {
+				blocked = make(map[string][]int32)
+			}
+			for _, p := range ps {
+				if !slices.Contains(blocked[t], p) {
+					blocked[t] = append(blocked[t], p)
+				}
+			}
+		}
+	}
+	return blocked
+}
+
+// revokingAllowed returns whether the user allowed every partition in a
+// non-empty revoking set. An empty set is never partially allowed: the
+// rebalance is serializing a callback rather than revoking anything, and only
+// AllowRebalance unblocks it.
+func (c *consumer) revokingAllowed(revoking map[string][]int32) bool {
+	var any bool
+	for t, ps := range revoking {
+		for _, p := range ps {
+			if _, ok := c.pollWaitAllowed[t][p]; !ok {
+				return false
+			}
+			any = true
+		}
+	}
+	return any
+}
+
+// waitAndAddRebalance waits for pollers to allow a rebalance that revokes the
+// given partitions.
+func (c *consumer) waitAndAddRebalance(revoking map[string][]int32) {
 	if !c.cl.cfg.blockRebalanceOnPoll {
 		return
 	}
-	var blockedCalled bool
+	var (
+		blockedCalled bool
+		timedOut      bool
+		timer         *time.Timer
+	)
 	c.pollWaitMu.Lock()
 	defer c.pollWaitMu.Unlock()
 	c.pollWaitState += 1 << 32
-	for c.pollWaitState&math.MaxUint32 != 0 {
+	c.pollWaitRevoking = append(c.pollWaitRevoking, &revoking)
+	defer func() {
+		for i, r := range c.pollWaitRevoking {
+			if r == &revoking {
+				c.pollWaitRevoking = slices.Delete(c.pollWaitRevoking, i, i+1)
+				break
+			}
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for c.pollWaitState&math.MaxUint32 != 0 && !c.revokingAllowed(revoking) {
+		if timedOut {
+			c.cl.cfg.logger.Log(LogLevelWarn, "rebalance was blocked longer than AllowRebalanceAfter, allowing rebalance",
+				"group", c.cl.cfg.group,
+				"blocked_for", c.cl.cfg.allowRebalanceAfter,
+			)
+			c.allowRebalanceLocked()
+			break
+		}
 		if !blockedCalled {
+			blockedCalled = true
+			if d := c.cl.cfg.allowRebalanceAfter; d > 0 {
+				timer = time.AfterFunc(d, func() {
+					c.pollWaitMu.Lock()
+					defer c.pollWaitMu.Unlock()
+					timedOut = true
+					c.pollWaitC.Broadcast()
+				})
+			}
 			if c.cl.cfg.onBlocked != nil {
+				// We unlock so that the callback can call
+				// AllowRebalance or BlockedRebalanceRevoking.
+				c.pollWaitMu.Unlock()
 				c.cl.cfg.onBlocked(c.cl.ctx, c.cl)
+				c.pollWaitMu.Lock()
+				continue
 			}
-			blockedCalled = true
 		}
 		c.pollWaitC.Wait()
 	}
@@ -318,6 +449,66 @@ func (cl *Client) BufferedFetchBytes() int64 {
 	return cl.consumer.bufferedBytes.Load()
 }
 
+// PartitionWatermark contains offsets for a consumed partition as observed on
+// the latest fetch response for that partition.
+type PartitionWatermark struct {
+	// Topic is the topic this is for.
+	Topic string
+	// Partition is the partition this is for.
+	Partition int32
+	// LastConsumedOffset is the offset of the last record returned from
+	// polling, or -1 if nothing has been polled since the partition's
+	// offset was last set (assigned, reset, or SetOffsets).
+	LastConsumedOffset int64
+	// HighWatermark is the high watermark returned in the latest fetch
+	// response.
+	HighWatermark int64
+	// LastStableOffset is the last stable offset returned in the latest
+	// fetch response, or -1 if the broker does not support returning it.
+	LastStableOffset int64
+	// LogStartOffset is the log start offset returned in the latest
+	// fetch response, or -1 if the broker does not support returning it.
+	LogStartOffset int64
+}
+
+// PartitionWatermarks returns the watermarks for all partitions that are
+// currently being consumed and have received at least one successful fetch
+// response. This does not issue any requests; the returned values are those
+// observed on the latest fetches, and are only as fresh as the fetches
+// themselves.
+//
+// This can be used for lightweight progress or lag gauges. For an accurate
+// view of lag at a point in time, use the kadm package.
+func (cl *Client) PartitionWatermarks() map[string]map[int32]PartitionWatermark {
+	wms := make(map[string]map[int32]PartitionWatermark)
+	cl.allSinksAndSources(func(sns sinkAndSource) {
+		sns.eachSource(func(s *source) {
+			s.cursorsMu.Lock()
+			defer s.cursorsMu.Unlock()
+			for _, c := range s.cursors {
+				wm := &c.watermarks
+				if !wm.seen.Load() {
+					continue
+				}
+				ps := wms[c.topic]
+				if ps == nil {
+					ps = make(map[int32]PartitionWatermark)
+					wms[c.topic] = ps
+				}
+				ps[c.partition] = PartitionWatermark{
+					Topic:              c.topic,
+					Partition:          c.partition,
+					LastConsumedOffset: wm.lastConsumed.Load(),
+					HighWatermark:      wm.hwm.Load(),
+					LastStableOffset:   wm.lso.Load(),
+					LogStartOffset:     wm.logStart.Load(),
+				}
+			}
+		})
+	})
+	return wms
+}
+
 type usedCursors map[*cursor]struct{}
 
 func (u *usedCursors) use(c *cursor) {
@@ -333,7 +524,7 @@ func (c *consumer) init(cl *Client) {
 	c.sourcesReadyCond = sync.NewCond(&c.sourcesReadyMu)
 	c.pollWaitC = sync.NewCond(&c.pollWaitMu)
 
-	if len(cl.cfg.topics) > 0 || len(cl.cfg.partitions) > 0 {
+	if len(cl.cfg.topics) > 0 || len(cl.cfg.partitions) > 0 || cl.cfg.regex && cl.cfg.topicMatcher != nil {
 		defer cl.triggerUpdateMetadataNow("querying metadata for consumer initialization") // we definitely want to trigger a metadata update
 	}
 
@@ -401,6 +592,10 @@ func NewErrFetch(err error) Fetches {
 // any partition has a fatal error and actually had no records, fake fetch will
 // be injected with the error.
 //
+// If MaxPollRecords or MaxPollBytes are set, this returns at most that many
+// records or bytes. Anything beyond the limit stays buffered, and the next
+// poll returns it immediately.
+//
 // If you are group consuming, a rebalance can happen under the hood while you
 // process the returned fetches. This can result in duplicate work, and you may
 // accidentally commit to partitions that you no longer own. You can prevent
@@ -410,6 +605,117 @@ func (cl *Client) PollFetches(ctx context.Context) Fetches {
 	return cl.PollRecords(ctx, 0)
 }
 
+// fairPollQuotas returns how many records to take from each buffered,
+// unpaused partition across all sources to fill n records round robin. If
+// maxBytes is positive, the quotas are also limited to maxBytes total.
+func fairPollQuotas(sources []*source, paused pausedTopics, n int, maxBytes int64) map[string]map[int32]int {
+	type tp struct {
+		t string
+		p int32
+	}
+	var (
+		tps  []tp
+		recs [][]*Record
+	)
+	for _, s := range sources {
+		for _, t := range s.buffered.fetch.Topics {
+			if paused.has(t.Topic, -1) {
+				continue
+			}
+			for _, p := range t.Partitions {
+				if len(p.Records) == 0 || paused.has(t.Topic, p.Partition) {
+					continue
+				}
+				tps = append(tps, tp{t.Topic, p.Partition})
+				recs = append(recs, p.Records)
+			}
+		}
+	}
+	var shares []int
+	if maxBytes > 0 {
+		shares = fairShareBytes(recs, n, maxBytes)
+	} else {
+		counts := make([]int, len(recs))
+		for i, rs := range recs {
+			counts[i] = len(rs)
+		}
+		shares = fairShares(counts, n)
+	}
+	quotas := make(map[string]map[int32]int)
+	for i, share := range shares {
+		tq := quotas[tps[i].t]
+		if tq == nil {
+			tq = make(map[int32]int)
+			quotas[tps[i].t] = tq
+		}
+		tq[tps[i].p] = share
+	}
+	return quotas
+}
+
+// fairShareBytes is like fairShares, but takes one record at a time from
+// each partition round robin and stops once taking the next record would
+// exceed maxBytes. The first record is always taken so that polling can make
+// progress even if a single record is larger than maxBytes.
+func fairShareBytes(recs [][]*Record, n int, maxBytes int64) []int {
+	var (
+		shares = make([]int, len(recs))
+		nbytes int64
+		taken  int
+	)
+	for more := true; more && n > 0; {
+		more = false
+		for i, rs := range recs {
+			if n == 0 {
+				break
+			}
+			if shares[i] == len(rs) {
+				continue
+			}
+			size := rs[shares[i]].userSize()
+			if taken > 0 && nbytes+size > maxBytes {
+				return shares
+			}
+			nbytes += size
+			shares[i]++
+			taken++
+			n--
+			more = true
+		}
+	}
+	return shares
+}
+
+// fairShares splits n across counts round robin: each count is given an even
+// share of what remains, capped at the count, until n is exhausted or every
+// count is satisfied. If n does not divide evenly, the earlier counts receive
+// the remainder.
+func fairShares(counts []int, n int) []int {
+	shares := make([]int, len(counts))
+	for n > 0 {
+		var want int
+		for i, c := range counts {
+			if shares[i] < c {
+				want++
+			}
+		}
+		if want == 0 {
+			break
+		}
+		each := max(n/want, 1)
+		for i, c := range counts {
+			if n == 0 {
+				break
+			}
+			if add := min(each, c-shares[i], n); add > 0 {
+				shares[i] += add
+				n -= add
+			}
+		}
+	}
+	return shares
+}
+
 // PollRecords waits for fetches to be available, returning as soon as any
 // broker returns a fetch. If the context is nil, this function will return
 // immediately with any currently buffered fetches.
@@ -420,7 +726,13 @@ func (cl *Client) PollFetches(ctx context.Context) Fetches {
 // can be used to break out of a poll loop.
 //
 // This returns a maximum of maxPollRecords total across all fetches, or
-// returns all buffered records if maxPollRecords is <= 0.
+// returns all buffered records if maxPollRecords is <= 0. If MaxPollRecords or
+// MaxPollBytes are set, the returned fetches are additionally capped by those
+// options. The limit is split round robin across all buffered partitions:
+// every partition is given an even share, and shares unused by partitions with
+// few buffered records go to partitions with more. One partition with many
+// buffered records thus cannot starve other partitions within a poll. Records
+// within a partition are always returned in order.
 //
 // It is important to check all partition errors in the returned fetches. If
 // any partition has a fatal error and actually had no records, fake fetch will
@@ -432,8 +744,15 @@ func (cl *Client) PollFetches(ctx context.Context) Fetches {
 // this by using BlockRebalanceOnPoll, but this comes with different tradeoffs.
 // See the documentation on BlockRebalanceOnPoll for more information.
 func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
-	if maxPollRecords == 0 {
+	if cfgMax := cl.cfg.maxPollRecords; cfgMax > 0 && (maxPollRecords <= 0 || cfgMax < maxPollRecords) {
+		maxPollRecords = cfgMax
+	}
+	maxPollBytes := cl.cfg.maxPollBytes
+	if maxPollRecords <= 0 {
 		maxPollRecords = -1
+		if maxPollBytes > 0 {
+			maxPollRecords = math.MaxInt
+		}
 	}
 	c := &cl.consumer
 
@@ -459,6 +778,9 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 				}
 			}()
 		}
+		if c.cl.cfg.suppressTransientErrors {
+			defer func() { fetches = cl.stripTransientErrors(fetches) }()
+		}
 
 		paused := c.loadPaused()
 
@@ -484,15 +806,21 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 			}
 			c.sourcesReadyForDraining = nil
 		} else {
-			for len(c.sourcesReadyForDraining) > 0 && maxPollRecords > 0 {
-				source := c.sourcesReadyForDraining[0]
-				fetch, taken, drained := source.takeNBuffered(paused, maxPollRecords)
-				if drained {
-					c.sourcesReadyForDraining = c.sourcesReadyForDraining[1:]
+			quotas := fairPollQuotas(c.sourcesReadyForDraining, paused, maxPollRecords, maxPollBytes)
+			var keep []*source
+			for i, source := range c.sourcesReadyForDraining {
+				if maxPollRecords <= 0 {
+					keep = append(keep, c.sourcesReadyForDraining[i:]...)
+					break
+				}
+				fetch, taken, drained := source.takeNBuffered(paused, maxPollRecords, quotas)
+				if !drained {
+					keep = append(keep, source)
 				}
 				maxPollRecords -= taken
 				fetches = append(fetches, fetch)
 			}
+			c.sourcesReadyForDraining = keep
 		}
 
 		realFetches := fetches
@@ -518,46 +846,70 @@ func (cl *Client) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
 		if c.g != nil {
 			c.g.updateUncommitted(realFetches)
 		}
+		if cl.cfg.e2eLatency {
+			for i := range realFetches {
+				c.e2e.observe(cl, &realFetches[i])
+			}
+		}
+		if cl.cfg.regexDropIdle > 0 {
+			c.idle.observe(realFetches)
+		}
 	}
 
 	// We try filling fetches once before waiting. If we have no context,
 	// we guarantee that we just drain anything available and return.
+	// If we are rehydrating offloaded payloads, we do so after filling and
+	// outside of any consumer locks.
+	rehydrated := func() Fetches {
+		if cl.cfg.offloader != nil {
+			cl.rehydratePayloads(ctx, fetches)
+		}
+		return fetches
+	}
+
 	fill()
 	if len(fetches) > 0 || ctx == nil {
-		return fetches
+		return rehydrated()
 	}
 
-	done := make(chan struct{})
-	quit := false
-	go func() {
-		c.sourcesReadyMu.Lock()
-		defer c.sourcesReadyMu.Unlock()
-		defer close(done)
+	for {
+		done := make(chan struct{})
+		quit := false
+		go func() {
+			c.sourcesReadyMu.Lock()
+			defer c.sourcesReadyMu.Unlock()
+			defer close(done)
+
+			for !quit && len(c.sourcesReadyForDraining) == 0 && len(c.fakeReadyForDraining) == 0 {
+				c.sourcesReadyCond.Wait()
+			}
+		}()
 
-		for !quit && len(c.sourcesReadyForDraining) == 0 && len(c.fakeReadyForDraining) == 0 {
-			c.sourcesReadyCond.Wait()
+		exit := func() {
+			c.sourcesReadyMu.Lock()
+			quit = true
+			c.sourcesReadyMu.Unlock()
+			c.sourcesReadyCond.Broadcast()
 		}
-	}()
 
-	exit := func() {
-		c.sourcesReadyMu.Lock()
-		quit = true
-		c.sourcesReadyMu.Unlock()
-		c.sourcesReadyCond.Broadcast()
-	}
+		select {
+		case <-cl.ctx.Done():
+			exit()
+			return NewErrFetch(ErrClientClosed)
+		case <-ctx.Done():
+			exit()
+			return NewErrFetch(ctx.Err())
+		case <-done:
+		}
 
-	select {
-	case <-cl.ctx.Done():
-		exit()
-		return NewErrFetch(ErrClientClosed)
-	case <-ctx.Done():
-		exit()
-		return NewErrFetch(ctx.Err())
-	case <-done:
-	}
+		fill()
 
-	fill()
-	return fetches
+		// If we stripped every transient error and have nothing left,
+		// we wait again rather than returning nothing.
+		if len(fetches) > 0 || !cl.cfg.suppressTransientErrors {
+			return rehydrated()
+		}
+	}
 }
 
 // AllowRebalance allows a consumer group to rebalance if it was blocked by you
@@ -573,10 +925,37 @@ func (cl *Client) AllowRebalance() {
 	cl.consumer.allowRebalance()
 }
 
+// AllowRebalanceRevoking allows a rebalance blocked by BlockRebalanceOnPoll to
+// continue if every partition it is revoking is in revoking, while you keep
+// processing other partitions. This is useful if you process partitions
+// independently: once you finish with (or abandon) the partitions that are
+// being revoked, the rebalance can continue without waiting for your other
+// partitions. Use BlockedRebalanceRevoking to see which partitions a blocked
+// rebalance is revoking.
+//
+// This does not replace AllowRebalance: you must still call AllowRebalance
+// once you are done processing everything you polled. Allowances apply until
+// you call AllowRebalance or poll again. Rebalances that only assign
+// partitions still require AllowRebalance, and eager rebalances revoke
+// everything, so they require allowing every partition.
+func (cl *Client) AllowRebalanceRevoking(revoking map[string][]int32) {
+	cl.consumer.allowRebalanceRevoking(revoking)
+}
+
+// BlockedRebalanceRevoking returns the partitions that rebalances currently
+// blocked by BlockRebalanceOnPoll are waiting to revoke, or nil if no
+// rebalance is blocked or the blocked rebalance is not revoking anything.
+// This can be called from the OnPartitionsCallbackBlocked callback.
+func (cl *Client) BlockedRebalanceRevoking() map[string][]int32 {
+	return cl.consumer.blockedRebalanceRevoking()
+}
+
 // UpdateFetchMaxBytes updates the max bytes that a fetch request will ask for
 // and the max partition bytes that a fetch request will ask for each
 // partition.
 func (cl *Client) UpdateFetchMaxBytes(maxBytes, maxPartBytes int32) {
+	cl.reconfigureMu.Lock()
+	defer cl.reconfigureMu.Unlock()
 	cl.cfg.maxBytes.store(maxBytes)
 	cl.cfg.maxPartBytes.store(maxPartBytes)
 }
@@ -632,7 +1011,7 @@ func (cl *Client) PauseFetchPartitions(topicPartitions map[string][]int32) map[s
 // See the documentation on PauseFetchTopics for more details.
 func (cl *Client) ResumeFetchTopics(topics ...string) {
 	defer cl.allSinksAndSources(func(sns sinkAndSource) {
-		sns.source.maybeConsume()
+		sns.eachSource((*source).maybeConsume)
 	})
 
 	c := &cl.consumer
@@ -650,7 +1029,7 @@ func (cl *Client) ResumeFetchTopics(topics ...string) {
 // details.
 func (cl *Client) ResumeFetchPartitions(topicPartitions map[string][]int32) {
 	defer cl.allSinksAndSources(func(sns sinkAndSource) {
-		sns.source.maybeConsume()
+		sns.eachSource((*source).maybeConsume)
 	})
 
 	c := &cl.consumer
@@ -752,6 +1131,7 @@ func (c *consumer) purgeTopics(topics []string) {
 		for _, topic := range topics {
 			delete(c.g.using, topic)
 			delete(c.g.reSeen, topic)
+			delete(c.reMissAt, topic)
 		}
 		c.g.rejoin("rejoin from PurgeFetchTopics")
 	} else {
@@ -759,6 +1139,7 @@ func (c *consumer) purgeTopics(topics []string) {
 		for _, topic := range topics {
 			delete(c.d.using, topic)
 			delete(c.d.reSeen, topic)
+			delete(c.reMissAt, topic)
 			delete(c.d.m, topic)
 			delete(c.d.ps, topic)
 		}
@@ -775,7 +1156,11 @@ func (c *consumer) purgeTopics(topics []string) {
 // entire topic is purged.
 func (cl *Client) AddConsumeTopics(topics ...string) {
 	c := &cl.consumer
-	if len(topics) == 0 || c.g == nil && c.d == nil || cl.cfg.regex {
+	if c.g == nil && c.d == nil || cl.cfg.regex {
+		return
+	}
+	topics = slices.DeleteFunc(slices.Clone(topics), func(t string) bool { return !cl.allowConsume(t, "AddConsumeTopics") })
+	if len(topics) == 0 {
 		return
 	}
 
@@ -795,6 +1180,16 @@ func (cl *Client) AddConsumeTopics(topics ...string) {
 	cl.triggerUpdateMetadataNow("from AddConsumeTopics")
 }
 
+// allowConsume returns whether the topic is in the ConsumeTopicAllowList,
+// logging an error if not.
+func (cl *Client) allowConsume(topic, from string) bool {
+	if cl.cfg.canConsume(topic) {
+		return true
+	}
+	cl.cfg.logger.Log(LogLevelError, "refusing to consume topic that is not in the ConsumeTopicAllowList", "from", from, "topic", topic)
+	return false
+}
+
 // GetConsumeTopics retrives a list of current topics being consumed.
 func (cl *Client) GetConsumeTopics() []string {
 	c := &cl.consumer
@@ -827,7 +1222,7 @@ func (cl *Client) AddConsumePartitions(partitions map[string]map[int32]Offset) {
 	}
 	var topics []string
 	for t, ps := range partitions {
-		if len(ps) == 0 {
+		if len(ps) == 0 || !cl.allowConsume(t, "AddConsumePartitions") {
 			delete(partitions, t)
 			continue
 		}
@@ -1045,8 +1440,9 @@ func (c *consumer) assignPartitions(assignments map[string]map[int32]Offset, how
 							shouldKeep = false
 						} else { // how == assignSetMatching
 							usedCursor.setOffset(cursorOffset{
-								offset:            assignPart.at,
-								lastConsumedEpoch: assignPart.epoch,
+								offset:             assignPart.at,
+								lastConsumedEpoch:  assignPart.epoch,
+								lastConsumedOffset: -1,
 							})
 						}
 					}
@@ -1169,7 +1565,11 @@ func (c *consumer) assignPartitions(assignments map[string]map[int32]Offset, how
 			// fetch offsets only if the broker supports KIP-320,
 			// but we do not override the user manually specifying
 			// an epoch.
-			if offset.at >= 0 && offset.epoch >= 0 {
+			//
+			// If the user disabled epoch validation, we skip the
+			// OffsetForLeaderEpoch request and fall into using the
+			// exact offset directly below.
+			if offset.at >= 0 && offset.epoch >= 0 && !c.cl.cfg.disableOffsetEpochValidation {
 				loadOffsets.addLoad(topic, partition, loadTypeEpoch, offsetLoad{
 					replica: -1,
 					Offset:  offset,
@@ -1189,12 +1589,21 @@ func (c *consumer) assignPartitions(assignments map[string]map[int32]Offset, how
 			// If an offset is unspecified or we have not loaded
 			// the partition, we list offsets to find out what to
 			// use.
+			//
+			// If epoch validation is disabled, the epoch is the
+			// one the offset was consumed in and we keep it, the
+			// same as if we had validated it.
 			if offset.at >= 0 && partition >= 0 && partition < int32(len(topicPartitions.partitions)) {
+				lastConsumedEpoch := int32(-1)
+				if c.cl.cfg.disableOffsetEpochValidation {
+					lastConsumedEpoch = offset.epoch
+				}
 				part := topicPartitions.partitions[partition]
 				cursor := part.cursor
 				cursor.setOffset(cursorOffset{
-					offset:            offset.at,
-					lastConsumedEpoch: -1,
+					offset:             offset.at,
+					lastConsumedEpoch:  lastConsumedEpoch,
+					lastConsumedOffset: -1,
 				})
 				cursor.allowUsable()
 				c.usingCursors.use(cursor)
@@ -1226,16 +1635,14 @@ func (c *consumer) filterMetadataAllTopics(topics []string) []string {
 	var rns reNews
 	defer rns.log(&c.cl.cfg)
 
-	var reSeen map[string]bool
-	if c.d != nil {
-		reSeen = c.d.reSeen
-	} else {
-		reSeen = c.g.reSeen
-	}
+	reSeen := c.reSeen()
 
 	keep := topics[:0]
 	for _, topic := range topics {
 		want, seen := reSeen[topic]
+		if seen && !want && c.reMissExpired(topic) {
+			seen = false
+		}
 		if !seen {
 			for rawRe, re := range c.cl.cfg.topics {
 				if want = re.MatchString(topic); want {
@@ -1243,6 +1650,14 @@ func (c *consumer) filterMetadataAllTopics(topics []string) []string {
 					break
 				}
 			}
+			if fn := c.cl.cfg.topicMatcher; !want && fn != nil {
+				if want = fn(topic); want {
+					rns.add("<matcher>", topic)
+				}
+			}
+			if want && !c.cl.cfg.canConsume(topic) {
+				want = false
+			}
 			if want {
 				for _, re := range c.cl.cfg.excludeTopics {
 					if re.MatchString(topic) {
@@ -1253,6 +1668,12 @@ func (c *consumer) filterMetadataAllTopics(topics []string) []string {
 			}
 			if !want {
 				rns.skip(topic)
+				if c.reMissAt == nil {
+					c.reMissAt = make(map[string]time.Time)
+				}
+				c.reMissAt[topic] = time.Now()
+			} else {
+				delete(c.reMissAt, topic)
 			}
 			reSeen[topic] = want
 		}
@@ -1263,6 +1684,58 @@ func (c *consumer) filterMetadataAllTopics(topics []string) []string {
 	return keep
 }
 
+// reMissExpired returns whether a topic that did not match any regex should
+// be evaluated again per ConsumeRegexUnmatchedTTL. This must be called with
+// c.mu held.
+func (c *consumer) reMissExpired(topic string) bool {
+	ttl := c.cl.cfg.regexUnmatchedTTL
+	at, ok := c.reMissAt[topic]
+	if !ok || ttl == 0 || time.Since(at) < ttl {
+		return false
+	}
+	delete(c.reMissAt, topic)
+	return true
+}
+
+// unmatchedRegexTopics returns topics that we already know do not match any
+// regex, so that an all-topics metadata update does not spend time processing
+// topics we will never use. Topics that are produced to, or consumed by other
+// group members (if we are the group leader), are not returned.
+func (c *consumer) unmatchedRegexTopics() map[string]struct{} {
+	produced := c.cl.producer.topics.load()
+	var external map[string]int32
+	if c.g != nil {
+		c.g.loadExternal().fn(func(tps map[string]int32) { external = tps })
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.reMissAt) == 0 {
+		return nil
+	}
+	ttl := c.cl.cfg.regexUnmatchedTTL
+	unmatched := make(map[string]struct{}, len(c.reMissAt))
+	for topic, at := range c.reMissAt {
+		_, isProduced := produced[topic]
+		_, isExternal := external[topic]
+		if isProduced || isExternal || ttl > 0 && time.Since(at) >= ttl {
+			continue
+		}
+		unmatched[topic] = struct{}{}
+	}
+	return unmatched
+}
+
+func (c *consumer) forgetUnmatchedRegexTopics(topics map[string]struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	reSeen := c.reSeen()
+	for topic := range topics {
+		delete(c.reMissAt, topic)
+		delete(reSeen, topic)
+	}
+}
+
 func (c *consumer) doOnMetadataUpdate() {
 	if !c.consuming() {
 		return
@@ -1337,6 +1810,36 @@ func (o offsetLoad) MarshalJSON() ([]byte, error) {
 	return fmt.Appendf(nil, `{"Replica":%d,"At":%d,"Relative":%d,"Epoch":%d,"CurrentEpoch":%d}`, o.replica, o.at, o.relative, o.epoch, o.currentEpoch), nil
 }
 
+// split returns the loads in chunks of at most n partitions. If n is
+// non-positive, this returns the map as is.
+func (o offsetLoadMap) split(n int) []offsetLoadMap {
+	if n <= 0 {
+		return []offsetLoadMap{o}
+	}
+	var (
+		splits []offsetLoadMap
+		cur    offsetLoadMap
+		inCur  int
+	)
+	for t, ps := range o {
+		for p, load := range ps {
+			if cur == nil || inCur == n {
+				cur = make(offsetLoadMap)
+				inCur = 0
+				splits = append(splits, cur)
+			}
+			cps := cur[t]
+			if cps == nil {
+				cps = make(map[int32]offsetLoad)
+				cur[t] = cps
+			}
+			cps[p] = load
+			inCur++
+		}
+	}
+	return splits
+}
+
 func (o offsetLoadMap) errToLoaded(err error) []loadedOffset {
 	var loaded []loadedOffset
 	for t, ps := range o {
@@ -1712,7 +2215,7 @@ func (c *consumer) stopSession() (listOrEpochLoads, *topicsPartitions) {
 	// register itself.
 
 	c.cl.allSinksAndSources(func(sns sinkAndSource) {
-		sns.source.session.reset()
+		sns.eachSource(func(s *source) { s.session.reset() })
 	})
 
 	// At this point, if we begin fetching anew, then the sources will not
@@ -1756,7 +2259,7 @@ func (c *consumer) startNewSession(tps *topicsPartitions) *consumerSession {
 	c.sessionChangeMu.Unlock()
 
 	c.cl.allSinksAndSources(func(sns sinkAndSource) {
-		sns.source.maybeConsume()
+		sns.eachSource((*source).maybeConsume)
 	})
 
 	// At this point, any source that was not consuming becauase it saw the
@@ -1815,18 +2318,38 @@ func (s *consumerSession) listOrEpoch(waiting listOrEpochLoads, immediate bool,
 
 	brokerLoads := s.mapLoadsToBrokers(loading)
 
-	results := make(chan loadedOffsets, 2*len(brokerLoads)) // each broker can receive up to two requests
-
-	var issued, received int
+	// Each broker receives up to two requests per chunk of partitions;
+	// by default, we do not chunk and issue at most two requests.
+	perReq := s.c.cl.cfg.offsetLoadPartitionsPerRequest
+	type issue struct {
+		broker *broker
+		load   offsetLoadMap
+		epoch  bool
+	}
+	var issues []issue
 	for broker, brokerLoad := range brokerLoads {
 		s.c.cl.cfg.logger.Log(LogLevelDebug, "offsets to load broker", "broker", broker.meta.NodeID, "load", brokerLoad)
 		if len(brokerLoad.List) > 0 {
-			issued++
-			go s.c.cl.listOffsetsForBrokerLoad(s.ctx, broker, brokerLoad.List, s.tps, results)
+			for _, load := range brokerLoad.List.split(perReq) {
+				issues = append(issues, issue{broker, load, false})
+			}
 		}
 		if len(brokerLoad.Epoch) > 0 {
-			issued++
-			go s.c.cl.loadEpochsForBrokerLoad(s.ctx, broker, brokerLoad.Epoch, s.tps, results)
+			for _, load := range brokerLoad.Epoch.split(perReq) {
+				issues = append(issues, issue{broker, load, true})
+			}
+		}
+	}
+
+	results := make(chan loadedOffsets, len(issues))
+
+	var issued, received int
+	for _, i := range issues {
+		issued++
+		if i.epoch {
+			go s.c.cl.loadEpochsForBrokerLoad(s.ctx, i.broker, i.load, s.tps, results)
+		} else {
+			go s.c.cl.listOffsetsForBrokerLoad(s.ctx, i.broker, i.load, s.tps, results)
 		}
 	}
 
@@ -1928,8 +2451,9 @@ func (s *consumerSession) handleListOrEpochResults(loaded loadedOffsets) (reload
 			}
 
 			load.cursor.setOffset(cursorOffset{
-				offset:            load.offset,
-				lastConsumedEpoch: load.leaderEpoch,
+				offset:             load.offset,
+				lastConsumedEpoch:  load.leaderEpoch,
+				lastConsumedOffset: -1,
 			})
 			load.cursor.allowUsable()
 			s.c.usingCursors.use(load.cursor)
@@ -86,7 +86,7 @@ func (d *directConsumer) findNewAssignments() map[string]map[int32]Offset {
 			}
 			toUseTopic := make(map[int32]Offset, len(partitions.partitions))
 			for partition := range partitions.partitions {
-				toUseTopic[int32(partition)] = d.cfg.startOffset
+				toUseTopic[int32(partition)] = d.cfg.startOffsetFor(topic, int32(partition))
 			}
 			toUse[topic] = toUseTopic
 		}
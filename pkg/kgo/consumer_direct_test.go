@@ -18,6 +18,171 @@ import (
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
+func TestConcurrentFetchesPerBroker(t *testing.T) {
+	t.Parallel()
+
+	const partitions = 8
+	topic, cleanup := tmpTopicPartitions(t, partitions)
+	defer cleanup()
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		ConcurrentFetchesPerBroker(4),
+		RecordPartitioner(ManualPartitioner()),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	for p := range int32(partitions) {
+		if err := cl.ProduceSync(ctx, &Record{Topic: topic, Partition: p, Value: []byte("v")}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+
+	got := make(map[int32]bool)
+	for len(got) < partitions {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed partitions %v: %v", got, err)
+		}
+		fs.EachRecord(func(r *Record) { got[r.Partition] = true })
+	}
+
+	// Every partition must be on one of the sources for its leader, and
+	// with 8 partitions on at most 3 brokers, some broker spreads its
+	// partitions across more than one source.
+	var spread bool
+	cl.allSinksAndSources(func(sns sinkAndSource) {
+		if len(sns.shards) != 3 {
+			t.Errorf("got %d shards != exp 3", len(sns.shards))
+		}
+		var used int
+		sns.eachSource(func(s *source) {
+			s.cursorsMu.Lock()
+			defer s.cursorsMu.Unlock()
+			if len(s.cursors) > 0 {
+				used++
+			}
+			for _, c := range s.cursors {
+				if exp := sns.sourceFor(c.topic, c.partition); exp != s {
+					t.Errorf("cursor %s/%d is on shard %d, expected shard %d", c.topic, c.partition, s.shard, exp.shard)
+				}
+			}
+		})
+		spread = spread || used > 1
+	})
+	if !spread {
+		t.Error("no broker fetched on more than one source")
+	}
+}
+
+func TestFetchTargetBufferedBytes(t *testing.T) {
+	t.Parallel()
+
+	const (
+		partitions = 2
+		perPart    = 100
+		target     = 1024
+	)
+	topic, cleanup := tmpTopicPartitions(t, partitions)
+	defer cleanup()
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		FetchTargetBufferedBytes(target),
+		RecordPartitioner(ManualPartitioner()),
+		ProducerBatchCompression(NoCompression()),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Producing synchronously and uncompressed gives us one small batch
+	// per record, so that each fetch can return many batches without
+	// exceeding the target.
+	value := make([]byte, 100)
+	for p := range int32(partitions) {
+		for range perPart {
+			if err := cl.ProduceSync(ctx, &Record{Topic: topic, Partition: p, Value: value}).FirstErr(); err != nil {
+				t.Fatalf("unable to produce: %v", err)
+			}
+		}
+	}
+
+	// Without polling, we should buffer some, but never much more than
+	// our budget. We allow one extra batch per partition of slop for the
+	// window between a fetch buffering and releasing its reservation.
+	limit := int64(partitions*target + partitions*len(value))
+	var consumed int
+	for consumed < partitions*perPart {
+		wait(t, 10*time.Second, func() error {
+			if cl.BufferedFetchBytes() == 0 {
+				return errors.New("nothing buffered yet")
+			}
+			return nil
+		})
+		time.Sleep(100 * time.Millisecond)
+		if buffered := cl.BufferedFetchBytes(); buffered > limit {
+			t.Fatalf("buffered %d bytes > limit %d", buffered, limit)
+		}
+		fs := cl.PollRecords(ctx, 10)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed %d records: %v", consumed, err)
+		}
+		consumed += fs.NumRecords()
+	}
+}
+
+func TestMaxPollRecordsBytes(t *testing.T) {
+	t.Parallel()
+
+	const records = 20
+	topic, cleanup := tmpTopicPartitions(t, 2)
+	defer cleanup()
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		MaxPollRecords(5),
+		MaxPollBytes(30),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var rs []*Record
+	for range records {
+		rs = append(rs, &Record{Topic: topic, Value: []byte("0123456789")})
+	}
+	if err := cl.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+
+	var consumed int
+	for consumed < records {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed %d records: %v", consumed, err)
+		}
+		if n := fs.NumRecords(); n > 3 {
+			t.Fatalf("polled %d records > exp max 3", n)
+		}
+		consumed += fs.NumRecords()
+
+		// Polling with a larger limit is still capped by our options.
+		fs = cl.PollRecords(nil, 100)
+		if n := fs.NumRecords(); n > 3 {
+			t.Fatalf("polled %d records > exp max 3", n)
+		}
+		consumed += fs.NumRecords()
+	}
+}
+
 // Allow adding a topic to consume after the client is initialized with nothing
 // to consume.
 func TestIssue325(t *testing.T) {
@@ -128,6 +293,146 @@ func TestConsumeRegex(t *testing.T) {
 	}
 }
 
+func TestConsumeTopicMatcher(t *testing.T) {
+	t.Parallel()
+
+	pfx := randsha()[:16] + "-"
+
+	var cleanup []func()
+	for _, name := range []string{
+		pfx + "include-1",
+		pfx + "include-2",
+		pfx + "exclude-1",
+		pfx + "other-1",
+	} {
+		_, c := tmpNamedTopicPartitions(t, name, 1)
+		cleanup = append(cleanup, c)
+	}
+	defer func() {
+		for _, c := range cleanup {
+			c()
+		}
+	}()
+
+	cl, _ := newTestClient(
+		ConsumeRegex(),
+		ConsumeTopicMatcher(func(topic string) bool {
+			return strings.HasPrefix(topic, pfx) && !strings.HasPrefix(topic, pfx+"other-")
+		}),
+		ConsumeExcludeTopics(pfx+"exclude-.*"),
+	)
+	defer cl.Close()
+	var topics []string
+	wait(t, 5*time.Second, func() error {
+		cl.triggerUpdateMetadataNow("querying metadata for consumer initialization")
+		topics = cl.GetConsumeTopics()
+		if len(topics) != 2 {
+			return fmt.Errorf("expected 2 topics, got %v", topics)
+		}
+		return nil
+	})
+	for _, topic := range topics {
+		if !strings.HasPrefix(topic, pfx+"include-") {
+			t.Fatalf("expected to see %sinclude-*, got %v", pfx, topic)
+		}
+	}
+}
+
+func TestConsumeRegexDropIdleTopics(t *testing.T) {
+	t.Parallel()
+
+	pfx := randsha()[:16] + "-"
+	active, idle := pfx+"active", pfx+"idle"
+	_, c1 := tmpNamedTopicPartitions(t, active, 1)
+	defer c1()
+	_, c2 := tmpNamedTopicPartitions(t, idle, 1)
+	defer c2()
+
+	dropped := make(chan []string, 10)
+	cl, _ := newTestClient(
+		ConsumeTopics(pfx+".*"),
+		ConsumeRegex(),
+		ConsumeRegexDropIdleTopics(2*time.Second, func(_ *Client, topics []string) { dropped <- topics }),
+		MetadataMinAge(50*time.Millisecond),
+		MetadataMaxAge(250*time.Millisecond),
+		DefaultProduceTopic(active),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// We keep the active topic busy until the idle topic is dropped.
+	for {
+		if err := cl.ProduceSync(ctx, StringRecord("v")).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+		cl.PollRecords(ctx, 100)
+		select {
+		case topics := <-dropped:
+			if !reflect.DeepEqual(topics, []string{idle}) {
+				t.Fatalf("got dropped topics %v != exp [%s]", topics, idle)
+			}
+		case <-ctx.Done():
+			t.Fatal("idle topic was never dropped")
+		default:
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		break
+	}
+
+	// The idle topic still matches, but should not come back.
+	time.Sleep(time.Second)
+	if topics := cl.GetConsumeTopics(); !reflect.DeepEqual(topics, []string{active}) {
+		t.Errorf("got consumed topics %v != exp [%s]", topics, active)
+	}
+}
+
+func TestConsumeRegexUnmatchedTTL(t *testing.T) {
+	t.Parallel()
+
+	pfx := randsha()[:16] + "-"
+	early, late := pfx+"early", pfx+"late"
+	_, c1 := tmpNamedTopicPartitions(t, early, 1)
+	defer c1()
+	_, c2 := tmpNamedTopicPartitions(t, late, 1)
+	defer c2()
+
+	var allowLate atomic.Bool
+	cl, _ := newTestClient(
+		ConsumeRegex(),
+		ConsumeTopicMatcher(func(topic string) bool {
+			return topic == early || topic == late && allowLate.Load()
+		}),
+		ConsumeRegexUnmatchedTTL(time.Second),
+		MetadataMinAge(50*time.Millisecond),
+		MetadataMaxAge(250*time.Millisecond),
+	)
+	defer cl.Close()
+
+	wait(t, 5*time.Second, func() error {
+		if topics := cl.GetConsumeTopics(); !reflect.DeepEqual(topics, []string{early}) {
+			return fmt.Errorf("got consumed topics %v != exp [%s]", topics, early)
+		}
+		return nil
+	})
+	if _, ok := cl.consumer.unmatchedRegexTopics()[late]; !ok {
+		t.Fatalf("expected %s to be remembered as unmatched", late)
+	}
+
+	// Once the TTL passes, the late topic is evaluated again.
+	allowLate.Store(true)
+	wait(t, 5*time.Second, func() error {
+		topics := cl.GetConsumeTopics()
+		sort.Strings(topics)
+		if exp := []string{early, late}; !reflect.DeepEqual(topics, exp) {
+			return fmt.Errorf("got consumed topics %v != exp %v", topics, exp)
+		}
+		return nil
+	})
+}
+
 // Ensure we only consume one partition if we only ask for one partition.
 func TestIssue337(t *testing.T) {
 	t.Parallel()
@@ -435,6 +740,106 @@ func TestPauseIssue489(t *testing.T) {
 	}
 }
 
+func TestConsumeEach(t *testing.T) {
+	t.Parallel()
+
+	t1, cleanup := tmpTopicPartitions(t, 3)
+	defer cleanup()
+
+	cl, _ := newTestClient(
+		UnknownTopicRetries(-1),
+		DefaultProduceTopic(t1),
+		RecordPartitioner(ManualPartitioner()),
+		ConsumeTopics(t1),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		FetchMaxWait(100*time.Millisecond),
+	)
+	defer cl.Close()
+
+	const perPartition = 10
+	for p := range int32(3) {
+		for i := range perPartition {
+			r := StringRecord(strconv.Itoa(i))
+			r.Partition = p
+			if err := cl.ProduceSync(context.Background(), r).FirstErr(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		seen   = make(map[int32][]string)
+		total  int
+		failed bool
+		active = make(map[int32]bool)
+	)
+	err := cl.ConsumeEach(ctx, func(tp TopicPartition, rs []*Record) error {
+		mu.Lock()
+		if active[tp.Partition] {
+			t.Errorf("partition %d called concurrently", tp.Partition)
+		}
+		active[tp.Partition] = true
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			active[tp.Partition] = false
+			mu.Unlock()
+		}()
+
+		if tp.Topic != t1 {
+			t.Errorf("got topic %s != exp %s", tp.Topic, t1)
+		}
+		mu.Lock()
+		if tp.Partition == 1 && !failed {
+			failed = true
+			mu.Unlock()
+			go func() {
+				for {
+					if paused := cl.PauseFetchPartitions(nil); len(paused[t1]) == 1 && paused[t1][0] == 1 {
+						cl.ResumeFetchPartitions(paused)
+						return
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+			return errors.New("injected failure")
+		}
+		for _, r := range rs {
+			seen[tp.Partition] = append(seen[tp.Partition], string(r.Value))
+			total++
+		}
+		done := total == 3*perPartition
+		mu.Unlock()
+		if done {
+			cancel()
+		}
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v, expected context.Canceled", err)
+	}
+	if !failed {
+		t.Fatal("never failed partition 1")
+	}
+	for p := range int32(3) {
+		got := seen[p]
+		if len(got) != perPartition {
+			t.Errorf("partition %d: got %d records != exp %d", p, len(got), perPartition)
+			continue
+		}
+		for i, v := range got {
+			if v != strconv.Itoa(i) {
+				t.Errorf("partition %d: got out of order records %v", p, got)
+				break
+			}
+		}
+	}
+}
+
 func TestPauseIssueOct2023(t *testing.T) {
 	t1, cleanup1 := tmpTopicPartitions(t, 1)
 	t2, cleanup2 := tmpTopicPartitions(t, 1)
@@ -884,3 +1289,401 @@ func TestGroupSimple(t *testing.T) {
 		}
 	}
 }
+
+func TestFairShares(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		counts []int
+		n      int
+		exp    []int
+	}{
+		{[]int{1000, 10, 10}, 30, []int{10, 10, 10}},
+		{[]int{1000, 2, 10}, 30, []int{18, 2, 10}},
+		{[]int{5, 5}, 100, []int{5, 5}},
+		{[]int{10, 10, 10}, 2, []int{1, 1, 0}},
+		{[]int{10, 10, 10}, 10, []int{4, 3, 3}},
+		{nil, 10, []int{}},
+	} {
+		got := fairShares(test.counts, test.n)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("fairShares(%v, %d): got %v != exp %v", test.counts, test.n, got, test.exp)
+		}
+	}
+}
+
+func TestFairShareBytes(t *testing.T) {
+	t.Parallel()
+
+	recs := func(sizes ...int) []*Record {
+		rs := make([]*Record, 0, len(sizes))
+		for _, size := range sizes {
+			rs = append(rs, &Record{Value: make([]byte, size)})
+		}
+		return rs
+	}
+
+	for _, test := range []struct {
+		recs     [][]*Record
+		n        int
+		maxBytes int64
+		exp      []int
+	}{
+		{[][]*Record{recs(10, 10, 10), recs(10, 10)}, 100, 30, []int{2, 1}},
+		{[][]*Record{recs(10, 10, 10), recs(10, 10)}, 100, 1000, []int{3, 2}},
+		{[][]*Record{recs(10, 10, 10), recs(10, 10)}, 3, 1000, []int{2, 1}},
+		{[][]*Record{recs(100), recs(10)}, 100, 50, []int{1, 0}},
+		{[][]*Record{recs(10), recs(100)}, 100, 50, []int{1, 0}},
+		{nil, 10, 10, []int{}},
+	} {
+		got := fairShareBytes(test.recs, test.n, test.maxBytes)
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("fairShareBytes(%d, %d): got %v != exp %v", test.n, test.maxBytes, got, test.exp)
+		}
+	}
+}
+
+type keyCountHook struct {
+	mu   sync.Mutex
+	keys map[int16]int
+}
+
+func (h *keyCountHook) OnBrokerWrite(_ BrokerMetadata, key int16, _ int, _, _ time.Duration, err error) {
+	if err != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.keys == nil {
+		h.keys = make(map[int16]int)
+	}
+	h.keys[key]++
+}
+
+func (h *keyCountHook) count(key int16) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.keys[key]
+}
+
+func TestOffsetLoadFastStart(t *testing.T) {
+	t.Parallel()
+
+	const partitions = 8
+	topic, cleanup := tmpTopicPartitions(t, partitions)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient(RecordPartitioner(ManualPartitioner()))
+	defer producer.Close()
+	epochs := make(map[int32]int32)
+	for p := range int32(partitions) {
+		for i := range 2 {
+			r := &Record{Topic: topic, Partition: p, Value: []byte(strconv.Itoa(i))}
+			if err := producer.ProduceSync(ctx, r).FirstErr(); err != nil {
+				t.Fatalf("unable to produce: %v", err)
+			}
+			epochs[p] = r.LeaderEpoch
+		}
+	}
+
+	for _, skip := range []bool{false, true} {
+		t.Run(fmt.Sprintf("skip_%v", skip), func(t *testing.T) {
+			at := make(map[int32]Offset)
+			for p, e := range epochs {
+				at[p] = NewOffset().At(1).WithEpoch(e)
+			}
+			hook := new(keyCountHook)
+			opts := []Opt{
+				ConsumePartitions(map[string]map[int32]Offset{topic: at}),
+				OffsetLoadPartitionsPerRequest(3),
+				WithHooks(hook),
+			}
+			if skip {
+				opts = append(opts, DisableOffsetEpochValidation())
+			}
+			cl, _ := newTestClient(opts...)
+			defer cl.Close()
+
+			got := make(map[int32]int64)
+			for len(got) < partitions {
+				fs := cl.PollFetches(ctx)
+				if err := ctx.Err(); err != nil {
+					t.Fatalf("only consumed partitions %v: %v", got, err)
+				}
+				fs.EachRecord(func(r *Record) {
+					if _, ok := got[r.Partition]; !ok {
+						got[r.Partition] = r.Offset
+					}
+				})
+			}
+			for p, o := range got {
+				if o != 1 {
+					t.Errorf("partition %d: got first offset %d != exp 1", p, o)
+				}
+			}
+
+			epochReqs := hook.count(kmsg.OffsetForLeaderEpoch.Int16())
+			switch {
+			case skip && epochReqs != 0:
+				t.Errorf("got %d OffsetForLeaderEpoch requests with validation disabled, exp 0", epochReqs)
+			case !skip && epochReqs < 3:
+				// 8 partitions at 3 per request is at least 3 requests.
+				t.Errorf("got %d OffsetForLeaderEpoch requests, exp at least 3", epochReqs)
+			}
+		})
+	}
+}
+
+// inFlightHook tracks the most requests of one key in flight to a broker at
+// once, delaying the first response read from each broker so that concurrent
+// requests pile up behind it.
+type inFlightHook struct {
+	key int16
+
+	mu       sync.Mutex
+	inFlight map[int32]int
+	max      int
+	delayed  map[int32]bool
+}
+
+func (h *inFlightHook) OnBrokerWrite(meta BrokerMetadata, key int16, _ int, _, _ time.Duration, err error) {
+	if key != h.key || err != nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.inFlight == nil {
+		h.inFlight = make(map[int32]int)
+		h.delayed = make(map[int32]bool)
+	}
+	h.inFlight[meta.NodeID]++
+	h.max = max(h.max, h.inFlight[meta.NodeID])
+}
+
+func (h *inFlightHook) OnBrokerRead(meta BrokerMetadata, key int16, _ int, _, _ time.Duration, err error) {
+	if key != h.key || err != nil {
+		return
+	}
+	h.mu.Lock()
+	delay := !h.delayed[meta.NodeID]
+	h.delayed[meta.NodeID] = true
+	h.mu.Unlock()
+	if delay {
+		time.Sleep(250 * time.Millisecond)
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.inFlight[meta.NodeID]--
+}
+
+func TestOffsetLoadPartitionsPerRequest(t *testing.T) {
+	t.Parallel()
+
+	const partitions = 8
+	topic, cleanup := tmpTopicPartitions(t, partitions)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient(RecordPartitioner(ManualPartitioner()))
+	defer producer.Close()
+	for p := range int32(partitions) {
+		if err := producer.ProduceSync(ctx, &Record{Topic: topic, Partition: p}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+
+	// Every broker leads at least two of the eight partitions, so loading
+	// one partition per request issues concurrent requests to a broker.
+	at := make(map[int32]Offset)
+	for p := range int32(partitions) {
+		at[p] = NewOffset().AtStart()
+	}
+	hook := &inFlightHook{key: kmsg.ListOffsets.Int16()}
+	cl, _ := newTestClient(
+		ConsumePartitions(map[string]map[int32]Offset{topic: at}),
+		OffsetLoadPartitionsPerRequest(1),
+		WithHooks(hook),
+	)
+	defer cl.Close()
+
+	got := make(map[int32]bool)
+	for len(got) < partitions {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed partitions %v: %v", got, err)
+		}
+		fs.EachRecord(func(r *Record) { got[r.Partition] = true })
+	}
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if hook.max < 2 {
+		t.Errorf("got at most %d ListOffsets requests in flight to a broker, exp at least 2", hook.max)
+	}
+}
+
+func TestConsumeResetOffsetFn(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 2)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient(RecordPartitioner(ManualPartitioner()))
+	defer producer.Close()
+	var checkpoint int64
+	for p := range int32(2) {
+		for i := range 3 {
+			r := &Record{Topic: topic, Partition: p, Value: []byte(strconv.Itoa(i))}
+			if p == 1 && i == 1 {
+				time.Sleep(5 * time.Millisecond) // ensure a distinct checkpoint timestamp
+				r.Timestamp = time.Now()
+				checkpoint = r.Timestamp.UnixMilli()
+			}
+			if err := producer.ProduceSync(ctx, r).FirstErr(); err != nil {
+				t.Fatalf("unable to produce: %v", err)
+			}
+		}
+	}
+
+	for _, grouped := range []bool{false, true} {
+		t.Run(fmt.Sprintf("group_%v", grouped), func(t *testing.T) {
+			var (
+				mu     sync.Mutex
+				called = make(map[int32]int)
+			)
+			opts := []Opt{
+				ConsumeTopics(topic),
+				ConsumeResetOffsetFn(func(tp string, p int32) Offset {
+					mu.Lock()
+					defer mu.Unlock()
+					called[p]++
+					if tp != topic {
+						t.Errorf("got topic %s != exp %s", tp, topic)
+					}
+					if p == 0 {
+						return NewOffset().At(2)
+					}
+					return NewOffset().AfterMilli(checkpoint)
+				}),
+			}
+			if grouped {
+				group, groupCleanup := tmpGroup(t)
+				defer groupCleanup()
+				opts = append(opts, ConsumerGroup(group))
+			}
+			cl, _ := newTestClient(opts...)
+			defer cl.Close()
+
+			got := make(map[int32]int64)
+			for len(got) < 2 {
+				fs := cl.PollFetches(ctx)
+				if err := ctx.Err(); err != nil {
+					t.Fatalf("only consumed partitions %v: %v", got, err)
+				}
+				fs.EachRecord(func(r *Record) {
+					if _, ok := got[r.Partition]; !ok {
+						got[r.Partition] = r.Offset
+					}
+				})
+			}
+			if exp := map[int32]int64{0: 2, 1: 1}; !reflect.DeepEqual(got, exp) {
+				t.Errorf("got first offsets %v != exp %v", got, exp)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if exp := map[int32]int{0: 1, 1: 1}; !reflect.DeepEqual(called, exp) {
+				t.Errorf("got fn calls %v != exp %v", called, exp)
+			}
+		})
+	}
+}
+
+func TestPartitionWatermarks(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient()
+	defer producer.Close()
+	for i := range 10 {
+		if err := producer.ProduceSync(ctx, &Record{Topic: topic, Value: []byte(strconv.Itoa(i))}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+
+	// We delete the first two records so that the log start offset is
+	// distinct from the first offset produced.
+	del := kmsg.NewPtrDeleteRecordsRequest()
+	dt := kmsg.NewDeleteRecordsRequestTopic()
+	dt.Topic = topic
+	dp := kmsg.NewDeleteRecordsRequestTopicPartition()
+	dp.Offset = 2
+	dt.Partitions = append(dt.Partitions, dp)
+	del.Topics = append(del.Topics, dt)
+	resp, err := del.RequestWith(ctx, producer)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("unable to delete records: %v", err)
+	}
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+	)
+	defer cl.Close()
+
+	check := func(expLastConsumed int64) {
+		t.Helper()
+		exp := PartitionWatermark{
+			Topic:              topic,
+			Partition:          0,
+			LastConsumedOffset: expLastConsumed,
+			HighWatermark:      10,
+			LastStableOffset:   10,
+			LogStartOffset:     2,
+		}
+		wms := cl.PartitionWatermarks()
+		if len(wms) != 1 || len(wms[topic]) != 1 {
+			t.Fatalf("got watermarks %v, exp only %s partition 0", wms, topic)
+		}
+		if got := wms[topic][0]; got != exp {
+			t.Errorf("got %+v != exp %+v", got, exp)
+		}
+	}
+
+	var consumed []int64
+	for len(consumed) < 3 {
+		fs := cl.PollRecords(ctx, 3-len(consumed))
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed offsets %v: %v", consumed, err)
+		}
+		fs.EachRecord(func(r *Record) { consumed = append(consumed, r.Offset) })
+	}
+	if !slices.Equal(consumed, []int64{2, 3, 4}) {
+		t.Fatalf("got offsets %v != exp [2 3 4]", consumed)
+	}
+	check(4)
+
+	for len(consumed) < 8 {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("only consumed offsets %v: %v", consumed, err)
+		}
+		fs.EachRecord(func(r *Record) { consumed = append(consumed, r.Offset) })
+	}
+	check(9)
+}
@@ -26,6 +26,9 @@ type groupConsumer struct {
 	cancel     func()
 	manageDone chan struct{} // closed once when the manage goroutine quits
 
+	assignedOnce  sync.Once
+	firstAssigned chan struct{} // closed once the member is first assigned, for Warmup
+
 	cooperative atomicBool // true if the group balancer chosen during Join is cooperative
 
 	// The data for topics that the user assigned. Metadata updates the
@@ -113,6 +116,19 @@ type groupConsumer struct {
 	// Not relevant if using KIP-848.
 	noCommitDuringJoinAndSync sync.RWMutex
 
+	// rejoining is true from when we begin waiting to join until sync
+	// finishes, for GroupCommitFenced.RebalanceInProgress.
+	rejoining atomicBool
+
+	// assignedCh is closed and replaced every time a session is assigned
+	// and its committed offsets are fetched, which wakes fenced commits
+	// waiting to be retried. assignedGen is the generation of that session,
+	// valid once hasAssignedGen is true.
+	assignedMu     sync.Mutex
+	assignedCh     chan struct{}
+	assignedGen    int32
+	hasAssignedGen bool
+
 	//////////////
 	// mu block //
 	//////////////
@@ -140,6 +156,18 @@ type groupConsumer struct {
 	// - read when getting uncommitted or committed
 	uncommitted uncommitted
 
+	// shed is what this member is handing off from ShedPartitions. It is
+	// added to when shedding, removed from what we own when revoking at
+	// the end of the session, published in our join metadata, and cleared
+	// once we are next assigned.
+	shed map[string][]int32
+
+	// standby is what the leader assigned us to warm with the standby
+	// balancer. It is replaced on every sync, cleared when leaving a
+	// session, and published as preferred partitions when rejoining so
+	// that a standby is promoted if the active member leaves.
+	standby map[string][]int32
+
 	// memberID and generation are written to in the join and sync loop,
 	// and mostly read within that loop. This can be read during commits,
 	// which can happy any time. It is **recommended** to be done within
@@ -166,6 +194,8 @@ type groupConsumer struct {
 	is848    bool
 	g848     *g848
 
+	census []byte // our encoded member census, if GroupMemberCensus is enabled
+
 	dying    bool // set when closing, read in findNewAssignments
 	left     chan struct{}
 	leaveErr error // set before left is closed
@@ -265,7 +295,7 @@ func (cl *Client) LeaveGroupContext(ctx context.Context) error {
 	}
 
 	go func() {
-		c.waitAndAddRebalance()
+		c.waitAndAddRebalance(c.g.nowAssigned.read())
 		c.mu.Lock() // lock for assign
 		c.assignPartitions(nil, assignInvalidateAll, nil, "invalidating all assignments in LeaveGroup")
 		c.g.leave(ctx)
@@ -307,6 +337,7 @@ func (c *consumer) initGroup() {
 		reSeen: make(map[string]bool),
 
 		manageDone:       make(chan struct{}),
+		firstAssigned:    make(chan struct{}),
 		tps:              newTopicsPartitions(),
 		rejoinCh:         make(chan string, 1),
 		heartbeatForceCh: make(chan func(error)),
@@ -315,6 +346,9 @@ func (c *consumer) initGroup() {
 		left: make(chan struct{}),
 	}
 	c.g = g
+	if g.cfg.groupCensus {
+		g.census = c.cl.newMemberCensus()
+	}
 	if g.cfg.commitCallback == nil {
 		g.cfg.commitCallback = g.defaultCommitCallback
 	}
@@ -367,7 +401,7 @@ func (c *consumer) initGroup() {
 		}
 	}
 
-	if g.cfg.onFetched != nil || g.cfg.adjustOffsetsBeforeAssign != nil {
+	if g.cfg.onFetched != nil || g.cfg.adjustOffsetsBeforeAssign != nil || g.cfg.onRestore != nil {
 		revoked := g.cfg.onRevoked
 		g.cfg.onRevoked = func(ctx context.Context, cl *Client, m map[string][]int32) {
 			g.onFetchedMu.Lock()
@@ -388,10 +422,10 @@ func (c *consumer) initGroup() {
 	}
 }
 
-func (g *groupConsumer) manageFailWait(consecutiveErrors int, err error) (ctxCanceled bool) {
+func (g *groupConsumer) manageFailWait(consecutiveErrors int, stage string, err error) (ctxCanceled bool) {
 	// If the user has BlockPollOnRebalance enabled, we have to
 	// block around the onLost and assigning.
-	g.c.waitAndAddRebalance()
+	g.c.waitAndAddRebalance(g.nowAssigned.read())
 
 	if errors.Is(err, context.Canceled) && g.cfg.onRevoked != nil {
 		// The cooperative consumer does not revoke everything
@@ -408,6 +442,7 @@ func (g *groupConsumer) manageFailWait(consecutiveErrors int, err error) (ctxCan
 		// the cooperative consumer we may as well just also
 		// include the eager consumer.
 		g.cfg.onRevoked(g.cl.ctx, g.cl, g.nowAssigned.read())
+		g.checkpoint(g.nowAssigned.read())
 	} else {
 		// Any other error is perceived as a fatal error,
 		// and we go into onLost as appropriate.
@@ -419,7 +454,7 @@ func (g *groupConsumer) manageFailWait(consecutiveErrors int, err error) (ctxCan
 				h.OnGroupManageError(err)
 			}
 		})
-		g.c.addFakeReadyForDraining("", 0, &ErrGroupSession{err}, "notification of group management loop error")
+		g.c.addFakeReadyForDraining("", 0, &ErrGroupSession{err, stage}, "notification of group management loop error")
 	}
 
 	// If we are eager, we should have invalidated everything
@@ -436,6 +471,8 @@ func (g *groupConsumer) manageFailWait(consecutiveErrors int, err error) (ctxCan
 		g.mu.Lock()     // before allowing poll to touch uncommitted, lock the group
 		g.c.mu.Unlock() // now part of poll can continue
 		g.uncommitted = nil
+		g.shed = nil
+		g.standby = nil
 		g.mu.Unlock()
 
 		g.nowAssigned.store(nil)
@@ -456,7 +493,7 @@ func (g *groupConsumer) manageFailWait(consecutiveErrors int, err error) (ctxCan
 
 	// Waiting for the backoff is a good time to update our
 	// metadata; maybe the error is from stale metadata.
-	backoff := g.cfg.retryBackoff(consecutiveErrors)
+	backoff := g.cfg.backoff(backoffGroup, consecutiveErrors)
 	g.cfg.logger.Log(LogLevelError, "group manage loop errored",
 		"group", g.cfg.group,
 		"err", err,
@@ -495,8 +532,10 @@ func (g *groupConsumer) manage() {
 		if joinWhy == "" {
 			joinWhy = "rejoining from normal rebalance"
 		}
+		stage := "joining and syncing"
 		err := g.joinAndSync(joinWhy)
 		if err == nil {
+			stage = "heartbeating"
 			if joinWhy, err = g.setupAssignedAndHeartbeat(g.cfg.heartbeatInterval, g.heartbeatFn()); err != nil {
 				if errors.Is(err, kerr.RebalanceInProgress) {
 					err = nil
@@ -510,7 +549,7 @@ func (g *groupConsumer) manage() {
 		joinWhy = "rejoining after we previously errored and backed off"
 
 		consecutiveErrors++
-		ctxCanceled := g.manageFailWait(consecutiveErrors, err)
+		ctxCanceled := g.manageFailWait(consecutiveErrors, stage, err)
 		if ctxCanceled {
 			return
 		}
@@ -659,7 +698,11 @@ const (
 // Lastly, for cooperative consumers, this must selectively delete what was
 // lost from the uncommitted map.
 func (g *groupConsumer) revoke(stage revokeStage, lost map[string][]int32, leaving bool) {
-	g.c.waitAndAddRebalance()
+	revoking := lost
+	if !g.cooperative.Load() || leaving {
+		revoking = g.nowAssigned.read()
+	}
+	g.c.waitAndAddRebalance(revoking)
 	defer g.c.unaddRebalance()
 
 	if !g.cooperative.Load() || leaving { // stage == revokeThisSession if not cooperative
@@ -681,6 +724,7 @@ func (g *groupConsumer) revoke(stage revokeStage, lost map[string][]int32, leavi
 		if g.cfg.onRevoked != nil {
 			g.cfg.onRevoked(g.cl.ctx, g.cl, g.nowAssigned.read())
 		}
+		g.checkpoint(g.nowAssigned.read())
 		g.nowAssigned.store(nil)
 		g.lastAssigned = nil
 
@@ -703,7 +747,8 @@ func (g *groupConsumer) revoke(stage revokeStage, lost map[string][]int32, leavi
 	case revokeThisSession:
 		// lost is nil for cooperative assigning. Instead, we determine
 		// lost by finding subscriptions we are no longer interested
-		// in. This would be from a user's PurgeConsumeTopics call.
+		// in. This would be from a user's PurgeConsumeTopics call, or
+		// from partitions we are shedding with ShedPartitions.
 		//
 		// We just paused metadata, but purging triggers a rebalance
 		// which causes a new metadata request -- in short, this could
@@ -720,6 +765,12 @@ func (g *groupConsumer) revoke(stage revokeStage, lost map[string][]int32, leavi
 					delete(nowAssigned, topic)
 				}
 			}
+			for topic, partitions := range g.takeShed(nowAssigned) {
+				if lost == nil {
+					lost = make(map[string][]int32)
+				}
+				lost[topic] = append(lost[topic], partitions...)
+			}
 			g.mu.Unlock()
 		})
 	}
@@ -765,6 +816,7 @@ func (g *groupConsumer) revoke(stage revokeStage, lost map[string][]int32, leavi
 	if len(lost) == 0 { // if we lost nothing, do nothing
 		return
 	}
+	g.checkpoint(lost)
 
 	if stage != revokeThisSession { // cooperative consumers rejoin after they revoking what they lost
 		defer g.rejoin("after revoking what we lost from a rebalance")
@@ -811,6 +863,31 @@ func newAssignRevokeSession() *assignRevokeSession {
 	}
 }
 
+// checkpoint calls OnPartitionsCheckpoint with the committed offsets of the
+// partitions we are handing over. This must be called after onRevoked, which
+// may commit, and before revoked partitions are removed from uncommitted.
+func (g *groupConsumer) checkpoint(revoked map[string][]int32) {
+	if g.cfg.onCheckpoint == nil || len(revoked) == 0 {
+		return
+	}
+	offsets := make(map[string]map[int32]EpochOffset, len(revoked))
+	g.mu.Lock()
+	for t, ps := range revoked {
+		to := make(map[int32]EpochOffset, len(ps))
+		for _, p := range ps {
+			if u, ok := g.uncommitted[t][p]; ok && u.hasCommitted {
+				to[p] = u.committed
+			} else {
+				to[p] = EpochOffset{-1, -1}
+			}
+		}
+		offsets[t] = to
+	}
+	g.mu.Unlock()
+	g.cfg.logger.Log(LogLevelInfo, "calling OnPartitionsCheckpoint with handover offsets", "group", g.cfg.group, "offsets", offsets)
+	g.cfg.onCheckpoint(g.cl.ctx, g.cl, offsets)
+}
+
 // For cooperative consumers, the first thing a cooperative consumer does is to
 // diff its last assignment and its new assignment and revoke anything lost.
 // We call this a "prerevoke".
@@ -834,10 +911,15 @@ func (s *assignRevokeSession) assign(g *groupConsumer, newAssigned map[string][]
 			// assignment is done and do setup logic.
 			//
 			// If configured, we have to block polling.
-			g.c.waitAndAddRebalance()
+			g.c.waitAndAddRebalance(nil)
 			defer g.c.unaddRebalance()
 			g.cfg.onAssigned(g.cl.ctx, g.cl, newAssigned)
 		}
+		if g.cfg.onStandby != nil {
+			g.c.waitAndAddRebalance(nil)
+			defer g.c.unaddRebalance()
+			g.cfg.onStandby(g.cl.ctx, g.cl, g.cl.StandbyAssignment())
+		}
 	}()
 	return s.assignDone
 }
@@ -935,14 +1017,23 @@ func (g *groupConsumer) setupAssignedAndHeartbeat(initialHb time.Duration, hbfn
 	}
 
 	<-s.assignDone
+	g.assignedOnce.Do(func() { close(g.firstAssigned) })
 
+	// Fenced commits are only retried once we know the committed offsets
+	// for our new partitions: revoking deletes what we knew, and another
+	// member may have committed while it owned the partition.
 	if len(added) > 0 {
 		go func() {
 			defer close(fetchDone)
 			defer close(fetchErrCh)
-			fetchErrCh <- g.fetchOffsets(ctx, added)
+			err := g.fetchOffsets(ctx, added)
+			if err == nil {
+				g.notifyAssigned()
+			}
+			fetchErrCh <- err
 		}()
 	} else {
+		g.notifyAssigned()
 		close(fetchDone)
 		close(fetchErrCh)
 	}
@@ -1132,6 +1223,8 @@ func (g *groupConsumer) rejoin(why string) {
 // Joins and then syncs, issuing the two slow requests in goroutines to allow
 // for group cancelation to return early.
 func (g *groupConsumer) joinAndSync(joinWhy string) error {
+	g.rejoining.Store(true)
+	defer g.rejoining.Store(false)
 	g.noCommitDuringJoinAndSync.Lock()
 	g.cfg.logger.Log(LogLevelDebug, "blocking commits from join&sync")
 	defer g.noCommitDuringJoinAndSync.Unlock()
@@ -1492,6 +1585,19 @@ func (g *groupConsumer) handleSyncResp(protocol string, resp *kmsg.SyncGroupResp
 	// Past this point, we will fall into the setupAssigned prerevoke code,
 	// meaning for cooperative, we will revoke what we need to.
 	g.nowAssigned.store(assigned)
+
+	var standby map[string][]int32
+	if _, ok := b.(*standbyBalancer); ok {
+		standby = parseStandby(resp.MemberAssignment)
+		g.cfg.logger.Log(LogLevelInfo, "synced standby", "group", g.cfg.group, "standby", mtps(standby))
+	}
+
+	// Whether or not the leader honored what we shed, we have now been
+	// assigned without owning it and are done shedding.
+	g.mu.Lock()
+	g.shed = nil
+	g.standby = standby
+	g.mu.Unlock()
 	return nil
 }
 
@@ -1506,6 +1612,17 @@ func (g *groupConsumer) joinGroupProtocols() []kmsg.JoinGroupRequestProtocol {
 	for t, ps := range g.lastAssigned {
 		lastDup[t] = slices.Clone(ps) // deep copy to allow modifications
 	}
+	var shed map[string][]int32
+	if len(g.shed) > 0 {
+		shed = make(map[string][]int32, len(g.shed))
+		for t, ps := range g.shed {
+			shed[t] = slices.Clone(ps)
+		}
+	}
+	standby := make(map[string][]int32, len(g.standby))
+	for t, ps := range g.standby {
+		standby[t] = slices.Clone(ps)
+	}
 
 	g.mu.Unlock()
 
@@ -1514,12 +1631,27 @@ func (g *groupConsumer) joinGroupProtocols() []kmsg.JoinGroupRequestProtocol {
 		slices.Sort(partitions) // same for partitions
 	}
 
+	var preferred map[string][]int32
+	if g.cfg.preferredPartitions != nil {
+		preferred = g.cfg.preferredPartitions()
+	}
+	preferred = mergeStandbyPreferred(preferred, standby)
+
 	gen := g.memberGen.generation()
 	var protos []kmsg.JoinGroupRequestProtocol
 	for _, balancer := range g.cfg.balancers {
 		proto := kmsg.NewJoinGroupRequestProtocol()
 		proto.Name = balancer.ProtocolName()
 		proto.Metadata = balancer.JoinGroupMetadata(topics, lastDup, gen)
+		if len(preferred) > 0 {
+			proto.Metadata = appendPreferred(proto.Metadata, preferred)
+		}
+		if len(shed) > 0 {
+			proto.Metadata = appendShed(proto.Metadata, shed)
+		}
+		if g.census != nil {
+			proto.Metadata = appendCensus(proto.Metadata, g.census)
+		}
 		protos = append(protos, proto)
 	}
 	return protos
@@ -1682,7 +1814,7 @@ start:
 				offset.epoch = rPartition.LeaderEpoch
 			}
 			if rPartition.Offset == -1 {
-				offset = g.cfg.startOffset
+				offset = g.cfg.startOffsetFor(rTopic.Topic, rPartition.Partition)
 			}
 			topicOffsets[rPartition.Partition] = offset
 		}
@@ -1712,6 +1844,14 @@ start:
 			return err
 		}
 	}
+	if g.cfg.onRestore != nil {
+		g.onFetchedMu.Lock()
+		err = g.cfg.onRestore(ctx, g.cl, offsets)
+		g.onFetchedMu.Unlock()
+		if err != nil {
+			return err
+		}
+	}
 
 	// Lock for assign and then updating uncommitted.
 	g.c.mu.Lock()
@@ -1743,9 +1883,10 @@ start:
 				Offset: offset.at,
 			}
 			topicUncommitted[partition] = uncommit{
-				dirty:     committed,
-				head:      committed,
-				committed: committed,
+				dirty:        committed,
+				head:         committed,
+				committed:    committed,
+				hasCommitted: true,
 			}
 		}
 	}
@@ -1856,6 +1997,10 @@ type uncommit struct {
 	dirty     EpochOffset // if autocommitting, what will move to head on next Poll
 	head      EpochOffset // ready to commit
 	committed EpochOffset // what is committed
+
+	// hasCommitted is whether committed was committed or fetched, rather
+	// than left zero by consuming before anything was committed.
+	hasCommitted bool
 }
 
 // EpochOffset combines a record offset with the leader epoch the broker
@@ -2082,6 +2227,7 @@ func (g *groupConsumer) updateCommitted(
 				reqPart.Offset,
 			}
 			uncommit.committed = set
+			uncommit.hasCommitted = true
 
 			// head is set in four places:
 			//  (1) if manually committing or greedily autocommitting,
@@ -2231,9 +2377,10 @@ func (g *groupConsumer) getSetAssigns(setOffsets map[string]map[int32]EpochOffse
 		for partition, epochOffset := range partitions {
 			current, exists := topicUncommitted[partition]
 			topicUncommitted[partition] = uncommit{
-				dirty:     epochOffset,
-				head:      epochOffset,
-				committed: epochOffset,
+				dirty:        epochOffset,
+				head:         epochOffset,
+				committed:    epochOffset,
+				hasCommitted: true,
 			}
 			if exists && current.dirty == epochOffset {
 				continue
@@ -2475,9 +2622,10 @@ func (cl *Client) MarkCommitRecords(rs ...*Record) {
 			r.Offset + 1,
 		}); current.head.Less(newHead) {
 			curPartitions[r.Partition] = uncommit{
-				dirty:     current.dirty,
-				committed: current.committed,
-				head:      newHead,
+				dirty:        current.dirty,
+				committed:    current.committed,
+				head:         newHead,
+				hasCommitted: current.hasCommitted,
 			}
 		}
 	}
@@ -2512,9 +2660,10 @@ func (cl *Client) MarkCommitOffsets(unmarked map[string]map[int32]EpochOffset) {
 			current := curPartitions[partition]
 			if current.head.Less(newHead) {
 				curPartitions[partition] = uncommit{
-					dirty:     current.dirty,
-					committed: current.committed,
-					head:      newHead,
+					dirty:        current.dirty,
+					committed:    current.committed,
+					head:         newHead,
+					hasCommitted: current.hasCommitted,
 				}
 			}
 		}
@@ -2604,7 +2753,7 @@ func (cl *Client) CommitOffsetsSync(
 
 	g := cl.consumer.g
 	if g == nil {
-		onDone(cl, kmsg.NewPtrOffsetCommitRequest(), kmsg.NewPtrOffsetCommitResponse(), errNotGroup)
+		onDone(cl, kmsg.NewPtrOffsetCommitRequest(), kmsg.NewPtrOffsetCommitResponse(), ErrNotGroup)
 		return
 	}
 	if len(uncommitted) == 0 {
@@ -2743,7 +2892,7 @@ func (cl *Client) CommitOffsets(
 
 	g := cl.consumer.g
 	if g == nil {
-		onDone(cl, kmsg.NewPtrOffsetCommitRequest(), kmsg.NewPtrOffsetCommitResponse(), errNotGroup)
+		onDone(cl, kmsg.NewPtrOffsetCommitRequest(), kmsg.NewPtrOffsetCommitResponse(), ErrNotGroup)
 		return
 	}
 	if len(uncommitted) == 0 {
@@ -2914,6 +3063,11 @@ func (g *groupConsumer) commit(
 	issue:
 		start := time.Now()
 		resp, err := req.RequestWith(commitCtx, g.cl)
+		g.cl.cfg.hooks.each(func(h Hook) {
+			if h, ok := h.(HookOffsetCommit); ok {
+				h.OnOffsetCommit(req, resp, err)
+			}
+		})
 		if err != nil {
 			onDone(g.cl, req, nil, err)
 			return
@@ -2955,10 +3109,241 @@ func (g *groupConsumer) commit(
 		}
 
 		g.updateCommitted(req, resp)
+		fenced := g.commitFenced(ctx, req, resp)
 		onDone(g.cl, req, resp, nil)
+		if fenced != nil {
+			go g.retryFencedCommit(fenced, generation)
+		}
 	}()
 }
 
+// isFencedCommitErr returns whether a commit error means the commit was
+// issued with a member or generation that is no longer current.
+func isFencedCommitErr(err error) bool {
+	switch err {
+	case kerr.IllegalGeneration,
+		kerr.UnknownMemberID,
+		kerr.FencedInstanceID,
+		kerr.RebalanceInProgress,
+		kerr.StaleMemberEpoch,
+		kerr.FencedMemberEpoch:
+		return true
+	}
+	return false
+}
+
+// commitFenced calls HookGroupCommitFenced if any partition in resp was
+// fenced, returning the offsets to retry if the commit should be retried once
+// we rejoin.
+func (g *groupConsumer) commitFenced(
+	ctx context.Context,
+	req *kmsg.OffsetCommitRequest,
+	resp *kmsg.OffsetCommitResponse,
+) map[string]map[int32]EpochOffset {
+	var (
+		fenced    map[string][]int32
+		retry     map[string]map[int32]EpochOffset
+		fencedErr error
+		offsets   = make(map[string]map[int32]EpochOffset)
+	)
+	for _, t := range req.Topics {
+		ps := make(map[int32]EpochOffset, len(t.Partitions))
+		offsets[t.Topic] = ps
+		for _, p := range t.Partitions {
+			ps[p.Partition] = EpochOffset{p.LeaderEpoch, p.Offset}
+		}
+	}
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			err := kerr.ErrorForCode(p.ErrorCode)
+			if !isFencedCommitErr(err) {
+				continue
+			}
+			if fenced == nil {
+				fenced = make(map[string][]int32)
+				fencedErr = err
+			}
+			fenced[t.Topic] = append(fenced[t.Topic], p.Partition)
+			if err == kerr.FencedInstanceID || err == kerr.FencedMemberEpoch {
+				continue
+			}
+			if eo, ok := offsets[t.Topic][p.Partition]; ok {
+				if retry == nil {
+					retry = make(map[string]map[int32]EpochOffset)
+				}
+				if retry[t.Topic] == nil {
+					retry[t.Topic] = make(map[int32]EpochOffset)
+				}
+				retry[t.Topic][p.Partition] = eo
+			}
+		}
+	}
+	if fenced == nil {
+		return nil
+	}
+
+	isRetry := ctx.Value(fencedCommitRetry) != nil
+	if !g.cfg.retryFencedCommits || isRetry {
+		retry = nil
+	}
+	memberID, generation := g.memberGen.load()
+	event := GroupCommitFenced{
+		Group:               g.cfg.group,
+		Err:                 fencedErr,
+		CommitMemberID:      req.MemberID,
+		CommitGeneration:    req.Generation,
+		MemberID:            memberID,
+		Generation:          generation,
+		RebalanceInProgress: fencedErr == kerr.RebalanceInProgress || g.rejoining.Load(),
+		Partitions:          fenced,
+		Retrying:            retry != nil,
+		Retry:               isRetry,
+	}
+	g.cfg.logger.Log(LogLevelInfo, "offset commit was fenced",
+		"group", g.cfg.group,
+		"err", fencedErr,
+		"commit_member_id", event.CommitMemberID,
+		"commit_generation", event.CommitGeneration,
+		"member_id", memberID,
+		"generation", generation,
+		"rebalance_in_progress", event.RebalanceInProgress,
+		"partitions", fenced,
+		"retrying", event.Retrying,
+	)
+	g.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookGroupCommitFenced); ok {
+			h.OnGroupCommitFenced(event)
+		}
+	})
+	return retry
+}
+
+// fencedCommitRetry is a context key marking a commit as a retry of a fenced
+// commit, so that it is not retried again.
+var fencedCommitRetry = func() *string { v := "fenced_commit_retry"; return &v }()
+
+func (g *groupConsumer) notifyAssigned() {
+	gen := g.memberGen.generation()
+	g.assignedMu.Lock()
+	defer g.assignedMu.Unlock()
+	g.assignedGen, g.hasAssignedGen = gen, true
+	if g.assignedCh != nil {
+		close(g.assignedCh)
+	}
+	g.assignedCh = make(chan struct{})
+}
+
+// retryFencedCommit waits for the member to be assigned in a generation after
+// gen and for the committed offsets of that assignment to be fetched, and
+// then re-commits offsets for partitions that are still assigned and that
+// have not had anything newer committed.
+func (g *groupConsumer) retryFencedCommit(offsets map[string]map[int32]EpochOffset, gen int32) {
+	ctx, cancel := context.WithTimeout(g.ctx, g.cfg.rebalanceTimeout)
+	defer cancel()
+	for {
+		g.assignedMu.Lock()
+		if g.assignedCh == nil {
+			g.assignedCh = make(chan struct{})
+		}
+		assignedCh := g.assignedCh
+		assigned := g.hasAssignedGen && g.assignedGen != gen
+		g.assignedMu.Unlock()
+
+		if assigned && g.nowAssigned.read() != nil {
+			break
+		}
+		select {
+		case <-assignedCh:
+		case <-ctx.Done():
+			g.cfg.logger.Log(LogLevelInfo, "not retrying fenced commit because the group was not rejoined in time", "group", g.cfg.group)
+			return
+		}
+	}
+
+	// CommitOffsets cancels any commit in flight, which may be newer than
+	// our fenced commit. We instead issue the commit directly under the
+	// same locks CommitOffsets uses, checking what to retry and whether a
+	// commit is in flight atomically with issuing our commit. If a commit
+	// is in flight, we wait for it to finish and check again.
+	for {
+		if err := g.waitJoinSyncMu(ctx); err != nil {
+			g.cfg.logger.Log(LogLevelInfo, "not retrying fenced commit because the context was canceled while waiting to commit", "group", g.cfg.group)
+			return
+		}
+		g.syncCommitMu.RLock()
+		g.mu.Lock()
+		unlock := func() {
+			g.mu.Unlock()
+			g.syncCommitMu.RUnlock()
+			g.noCommitDuringJoinAndSync.RUnlock()
+		}
+
+		if priorDone := g.commitDone; priorDone != nil {
+			select {
+			case <-priorDone:
+			default:
+				unlock()
+				select {
+				case <-priorDone:
+					continue
+				case <-ctx.Done():
+					g.cfg.logger.Log(LogLevelInfo, "not retrying fenced commit because the context was canceled while waiting for a commit in flight", "group", g.cfg.group)
+					return
+				}
+			}
+		}
+
+		now := g.nowAssigned.read()
+		retry := make(map[string]map[int32]EpochOffset)
+		for t, ps := range offsets {
+			for p, eo := range ps {
+				if !slices.Contains(now[t], p) {
+					continue
+				}
+				// Offsets for our assignment were fetched before we
+				// were notified, so a missing entry means nothing is
+				// committed for the partition.
+				if u, ok := g.uncommitted[t][p]; !ok || u.committed.Offset < eo.Offset {
+					if retry[t] == nil {
+						retry[t] = make(map[int32]EpochOffset)
+					}
+					retry[t][p] = eo
+				}
+			}
+		}
+		if len(retry) == 0 {
+			unlock()
+			g.cfg.logger.Log(LogLevelInfo, "not retrying fenced commit because no partitions are still assigned and uncommitted", "group", g.cfg.group)
+			return
+		}
+
+		g.cfg.logger.Log(LogLevelInfo, "retrying fenced commit after rejoining", "group", g.cfg.group, "offsets", retry)
+		done := make(chan struct{})
+		g.blockAuto = true
+		g.commit(context.WithValue(ctx, fencedCommitRetry, true), retry, func(_ *Client, _ *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error) {
+			defer close(done)
+			g.noCommitDuringJoinAndSync.RUnlock()
+			g.syncCommitMu.RUnlock()
+			if err == nil {
+				for _, t := range resp.Topics {
+					for _, p := range t.Partitions {
+						if err = kerr.ErrorForCode(p.ErrorCode); err != nil {
+							break
+						}
+					}
+				}
+			}
+			g.cfg.logger.Log(LogLevelInfo, "retried fenced commit", "group", g.cfg.group, "err", err)
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.blockAuto = false
+		})
+		g.mu.Unlock()
+		<-done // wait before canceling our context
+		return
+	}
+}
+
 type reNews struct {
 	added   map[string][]string
 	skipped []string
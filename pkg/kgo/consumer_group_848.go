@@ -23,6 +23,11 @@ func (g *groupConsumer) should848() bool {
 	if g.cl.cfg.disableNextGenBalancer {
 		return false
 	}
+	// The broker evaluates regular expressions server side, and cannot
+	// evaluate a custom matcher or our exclusions.
+	if g.cl.cfg.regex && (g.cl.cfg.topicMatcher != nil || len(g.cl.cfg.excludeTopics) > 0) {
+		return false
+	}
 	// We pin to v1, introduced in Kafka 4, which fully stabilizes KIP-848.
 	if !g.cl.supportsKIP848v1() {
 		return false
@@ -74,6 +79,7 @@ func (g *groupConsumer) manage848() {
 	var initialFences int
 outer:
 	for {
+		stage := "joining"
 		initialHb, err := g848.initialJoin()
 
 		// Even if Kafka replies that the API is available, if we use it
@@ -126,6 +132,7 @@ outer:
 		}
 
 		for err == nil {
+			stage = "heartbeating"
 			initialFences = 0
 			consecutiveErrors = 0
 			var nowAssigned map[string][]int32
@@ -239,7 +246,7 @@ outer:
 		// meaning our initialJoin should *always* have an empty
 		// Topics in the request.
 		consecutiveErrors++
-		ctxCanceled := g.manageFailWait(consecutiveErrors, err)
+		ctxCanceled := g.manageFailWait(consecutiveErrors, stage, err)
 		if ctxCanceled {
 			return
 		}
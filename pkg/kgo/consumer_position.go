@@ -0,0 +1,152 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Position returns the next offset that will be returned from polling for the
+// given partition, along with the leader epoch of the last consumed record (or
+// -1 if unknown). This is similar to the Java consumer's position function.
+//
+// This does not issue any requests and returns false if the partition is not
+// currently being consumed, or if its start offset has not yet been resolved
+// (e.g., the client is still listing offsets after an assignment).
+func (cl *Client) Position(tp TopicPartition) (EpochOffset, bool) {
+	var (
+		pos   EpochOffset
+		found bool
+	)
+	cl.allSinksAndSources(func(sns sinkAndSource) {
+		sns.eachSource(func(s *source) {
+			if found {
+				return
+			}
+			s.cursorsMu.Lock()
+			defer s.cursorsMu.Unlock()
+			for _, c := range s.cursors {
+				if c.topic != tp.Topic || c.partition != tp.Partition {
+					continue
+				}
+				wm := &c.watermarks
+				if offset := wm.position.Load(); offset >= 0 {
+					pos = EpochOffset{Epoch: wm.positionEpoch.Load(), Offset: offset}
+					found = true
+				}
+				return
+			}
+		})
+	})
+	return pos, found
+}
+
+// Committed fetches the committed offsets for the given partitions from the
+// group coordinator, similar to the Java consumer's committed function. If
+// partitions is nil, this returns all offsets committed for the group.
+//
+// Unlike CommittedOffsets, which returns the offsets this client has
+// committed or fetched while joining, this always issues an OffsetFetch
+// request, and thus sees commits from other group members and other clients.
+// Partitions that have no commit are not included in the returned map. This
+// returns ErrNotGroup if the client is not configured with a group.
+func (cl *Client) Committed(ctx context.Context, partitions map[string][]int32) (map[string]map[int32]EpochOffset, error) {
+	if cl.cfg.group == "" {
+		return nil, ErrNotGroup
+	}
+
+	req := kmsg.NewPtrOffsetFetchRequest()
+	req.RequireStable = cl.cfg.requireStable
+	req.Group = cl.cfg.group
+	for topic, ps := range partitions {
+		reqTopic := kmsg.NewOffsetFetchRequestTopic()
+		reqTopic.Topic = topic
+		reqTopic.Partitions = ps
+		req.Topics = append(req.Topics, reqTopic)
+	}
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+		return nil, err
+	}
+
+	committed := make(map[string]map[int32]EpochOffset)
+	for _, rTopic := range resp.Topics {
+		for _, rPartition := range rTopic.Partitions {
+			if err := kerr.ErrorForCode(rPartition.ErrorCode); err != nil {
+				return nil, fmt.Errorf("unable to fetch committed offset for %s[%d]: %w", rTopic.Topic, rPartition.Partition, err)
+			}
+			if rPartition.Offset < 0 {
+				continue
+			}
+			epoch := int32(-1)
+			if resp.Version >= 5 {
+				epoch = rPartition.LeaderEpoch
+			}
+			ps := committed[rTopic.Topic]
+			if ps == nil {
+				ps = make(map[int32]EpochOffset)
+				committed[rTopic.Topic] = ps
+			}
+			ps[rPartition.Partition] = EpochOffset{Epoch: epoch, Offset: rPartition.Offset}
+		}
+	}
+	return committed, nil
+}
+
+// BeginningEndOffsets lists the start and end offsets for the given
+// partitions, similar to the Java consumer's beginningOffsets and endOffsets
+// functions. The end offset is the high watermark, or the last stable offset
+// if the client is configured to read only committed records.
+//
+// This issues two ListOffsets requests. If any partition fails to list, the
+// failure is included in the returned error and the partition is left out of
+// the returned maps; offsets for all other partitions are still returned.
+func (cl *Client) BeginningEndOffsets(ctx context.Context, partitions map[string][]int32) (beginning, end map[string]map[int32]int64, err error) {
+	var errs []error
+	list := func(timestamp int64) map[string]map[int32]int64 {
+		req := kmsg.NewPtrListOffsetsRequest()
+		req.ReplicaID = -1
+		req.IsolationLevel = cl.cfg.isolationLevel
+		for topic, ps := range partitions {
+			reqTopic := kmsg.NewListOffsetsRequestTopic()
+			reqTopic.Topic = topic
+			for _, p := range ps {
+				reqPartition := kmsg.NewListOffsetsRequestTopicPartition()
+				reqPartition.Partition = p
+				reqPartition.Timestamp = timestamp
+				reqTopic.Partitions = append(reqTopic.Partitions, reqPartition)
+			}
+			req.Topics = append(req.Topics, reqTopic)
+		}
+		offsets := make(map[string]map[int32]int64)
+		resp, err := req.RequestWith(ctx, cl)
+		if err != nil {
+			errs = append(errs, err)
+			return offsets
+		}
+		for _, rTopic := range resp.Topics {
+			for _, rPartition := range rTopic.Partitions {
+				if err := kerr.ErrorForCode(rPartition.ErrorCode); err != nil {
+					errs = append(errs, fmt.Errorf("unable to list offset for %s[%d]: %w", rTopic.Topic, rPartition.Partition, err))
+					continue
+				}
+				ps := offsets[rTopic.Topic]
+				if ps == nil {
+					ps = make(map[int32]int64)
+					offsets[rTopic.Topic] = ps
+				}
+				ps[rPartition.Partition] = rPartition.Offset
+			}
+		}
+		return offsets
+	}
+	beginning = list(-2)
+	end = list(-1)
+	return beginning, end, errors.Join(errs...)
+}
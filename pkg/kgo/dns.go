@@ -0,0 +1,214 @@
+package kgo
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+)
+
+// IPFamily is an IP address family to prefer when a broker hostname resolves
+// to both IPv4 and IPv6 addresses, for use in PreferIPFamily.
+type IPFamily uint8
+
+const (
+	// IPFamilyAny dials addresses in the order the resolver returned
+	// them. This is the default.
+	IPFamilyAny IPFamily = iota
+	// IPFamilyV4 dials IPv4 addresses before IPv6 addresses.
+	IPFamilyV4
+	// IPFamilyV6 dials IPv6 addresses before IPv4 addresses.
+	IPFamilyV6
+)
+
+// minAddrDialTimeout is the minimum time we give to dial an individual
+// address when a host resolves to multiple addresses. This mirrors the net
+// package.
+const minAddrDialTimeout = 2 * time.Second
+
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsResolver resolves broker hostnames and dials the resulting addresses in
+// order when the client is using its default dialer and any DNS option or
+// HookBrokerResolve is in use.
+type dnsResolver struct {
+	cl     *Client
+	dialer *net.Dialer
+	tls    *tls.Config
+
+	mu    sync.Mutex
+	cache map[string]dnsCacheEntry
+}
+
+func newDNSResolver(cfg *cfg, dialer *net.Dialer) *dnsResolver {
+	var hasHook bool
+	cfg.hooks.each(func(h Hook) {
+		if _, ok := h.(HookBrokerResolve); ok {
+			hasHook = true
+		}
+	})
+	if !hasHook && cfg.dnsLookupFn == nil && cfg.dnsCacheTTL <= 0 && cfg.preferIPFamily == IPFamilyAny {
+		return nil
+	}
+	return &dnsResolver{
+		dialer: dialer,
+		tls:    cfg.dialTLS,
+		cache:  make(map[string]dnsCacheEntry),
+	}
+}
+
+// resolve returns the addresses for a host, using the cache if an unexpired
+// entry exists.
+func (r *dnsResolver) resolve(ctx context.Context, meta BrokerMetadata, host string) ([]string, error) {
+	r.mu.Lock()
+	e, ok := r.cache[host]
+	r.mu.Unlock()
+	if ok && time.Now().Before(e.expires) {
+		r.cl.cfg.hooks.each(func(h Hook) {
+			if h, ok := h.(HookBrokerResolve); ok {
+				h.OnBrokerResolve(meta, host, e.addrs, true, 0, nil)
+			}
+		})
+		return e.addrs, nil
+	}
+
+	var (
+		addrs []string
+		ttl   = r.cl.cfg.dnsCacheTTL
+		err   error
+		start = time.Now()
+	)
+	if fn := r.cl.cfg.dnsLookupFn; fn != nil {
+		var fnTTL time.Duration
+		addrs, fnTTL, err = fn(ctx, host)
+		if fnTTL > 0 {
+			ttl = fnTTL
+		}
+	} else {
+		addrs, err = net.DefaultResolver.LookupHost(ctx, host)
+	}
+	if err == nil && len(addrs) == 0 {
+		err = fmt.Errorf("no addresses found for host %s", host)
+	}
+	dur := time.Since(start)
+	if err == nil {
+		addrs = sortIPFamily(addrs, r.cl.cfg.preferIPFamily)
+	}
+
+	r.cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookBrokerResolve); ok {
+			h.OnBrokerResolve(meta, host, addrs, false, dur, err)
+		}
+	})
+	if err != nil {
+		r.cl.cfg.logger.Log(LogLevelWarn, "unable to resolve broker host", "host", host, "broker", logID(meta.NodeID), "err", err)
+		return nil, err
+	}
+	r.cl.cfg.logger.Log(LogLevelDebug, "resolved broker host", "host", host, "broker", logID(meta.NodeID), "addrs", addrs)
+
+	if ttl > 0 {
+		r.mu.Lock()
+		r.cache[host] = dnsCacheEntry{addrs, time.Now().Add(ttl)}
+		r.mu.Unlock()
+	}
+	return addrs, nil
+}
+
+// forget removes a host from the cache so that the next dial re-resolves.
+func (r *dnsResolver) forget(host string) {
+	r.mu.Lock()
+	delete(r.cache, host)
+	r.mu.Unlock()
+}
+
+// dial resolves the host in addr and dials each resolved address in order
+// until one succeeds. If no address can be dialed, the host is removed from
+// the cache: a failing dial is a good sign that the broker moved.
+func (r *dnsResolver) dial(ctx context.Context, meta BrokerMetadata, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("unable to split host:port for dialing: %w", err)
+	}
+
+	var addrs []string
+	if _, err := netip.ParseAddr(host); err == nil {
+		addrs = []string{host}
+	} else if addrs, err = r.resolve(ctx, meta, host); err != nil {
+		return nil, err
+	}
+
+	if r.dialer.Timeout > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, r.dialer.Timeout)
+		defer cancel()
+	}
+
+	var errs []error
+	for i, ip := range addrs {
+		dialCtx := ctx
+		if deadline, ok := ctx.Deadline(); ok && i < len(addrs)-1 {
+			partial := time.Until(deadline) / time.Duration(len(addrs)-i)
+			if partial < minAddrDialTimeout {
+				partial = min(minAddrDialTimeout, time.Until(deadline))
+			}
+			var cancel func()
+			dialCtx, cancel = context.WithTimeout(ctx, partial)
+			defer cancel()
+		}
+		conn, err := r.dialAddr(dialCtx, host, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	r.forget(host)
+	return nil, errors.Join(errs...)
+}
+
+// dialAddr dials a resolved address, performing a TLS handshake using the
+// original host as the server name if the client is using TLS.
+func (r *dnsResolver) dialAddr(ctx context.Context, host, addr string) (net.Conn, error) {
+	if r.tls == nil {
+		return r.dialer.DialContext(ctx, "tcp", addr)
+	}
+	c := r.tls.Clone()
+	if c.ServerName == "" {
+		c.ServerName = host
+	}
+	return (&tls.Dialer{
+		NetDialer: r.dialer,
+		Config:    c,
+	}).DialContext(ctx, "tcp", addr)
+}
+
+// sortIPFamily stably sorts addresses such that addresses in the preferred
+// family are first.
+func sortIPFamily(addrs []string, prefer IPFamily) []string {
+	if prefer == IPFamilyAny {
+		return addrs
+	}
+	rank := func(addr string) int {
+		ip, err := netip.ParseAddr(addr)
+		if err != nil {
+			return 1
+		}
+		if ip.Unmap().Is4() == (prefer == IPFamilyV4) {
+			return 0
+		}
+		return 1
+	}
+	sorted := append([]string(nil), addrs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return rank(sorted[i]) < rank(sorted[j]) })
+	return sorted
+}
@@ -0,0 +1,97 @@
+package kgo
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type resolveHook struct{ cached, resolved atomic.Int32 }
+
+func (h *resolveHook) OnBrokerResolve(_ BrokerMetadata, _ string, _ []string, cached bool, _ time.Duration, _ error) {
+	if cached {
+		h.cached.Add(1)
+	} else {
+		h.resolved.Add(1)
+	}
+}
+
+func TestDNSResolver(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, _ := net.SplitHostPort(ln.Addr().String())
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+
+	var lookups atomic.Int32
+	hook := new(resolveHook)
+	cl, err := NewClient(
+		WithHooks(hook),
+		DNSLookupFn(func(_ context.Context, host string) ([]string, time.Duration, error) {
+			lookups.Add(1)
+			if host != "broker.test" {
+				t.Errorf("unexpected lookup of %q", host)
+			}
+			return []string{"::1", "127.0.0.1"}, time.Minute, nil
+		}),
+		PreferIPFamily(IPFamilyV4),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ctx := context.Background()
+	addr := net.JoinHostPort("broker.test", port)
+	for range 2 {
+		conn, err := cl.resolver.dial(ctx, BrokerMetadata{NodeID: 1}, addr)
+		if err != nil {
+			t.Fatalf("unable to dial: %v", err)
+		}
+		conn.Close()
+	}
+	if n, c, r := lookups.Load(), hook.cached.Load(), hook.resolved.Load(); n != 1 || c != 1 || r != 1 {
+		t.Errorf("got %d lookups, %d cached, %d resolved != exp 1, 1, 1", n, c, r)
+	}
+
+	// Failing to dial discards the cached addresses.
+	ln.Close()
+	if _, err := cl.resolver.dial(ctx, BrokerMetadata{NodeID: 1}, addr); err == nil {
+		t.Fatal("unexpected successful dial to closed listener")
+	}
+	if _, ok := cl.resolver.cache["broker.test"]; ok {
+		t.Error("cache unexpectedly still contains failed host")
+	}
+
+	if _, err := NewClient(Dialer((&net.Dialer{}).DialContext), DNSCacheTTL(time.Second)); err == nil {
+		t.Error("expected error using DNS options with a custom dialer")
+	}
+}
+
+func TestSortIPFamily(t *testing.T) {
+	addrs := []string{"::1", "10.0.0.1", "fe80::1", "10.0.0.2"}
+	for _, test := range []struct {
+		prefer IPFamily
+		exp    []string
+	}{
+		{IPFamilyAny, addrs},
+		{IPFamilyV4, []string{"10.0.0.1", "10.0.0.2", "::1", "fe80::1"}},
+		{IPFamilyV6, []string{"::1", "fe80::1", "10.0.0.1", "10.0.0.2"}},
+	} {
+		if got := sortIPFamily(addrs, test.prefer); !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("prefer %d: got %v != exp %v", test.prefer, got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package kgo
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+)
+
+// EndToEndLatencyHeader is the record header the producer adds when using
+// [RecordEndToEndTimestamps]. The header value is the time the record was
+// passed to Produce, encoded as an 8 byte big endian number of nanoseconds
+// since the Unix epoch.
+const EndToEndLatencyHeader = "kgo-e2e-produce-time"
+
+// endToEndBuckets are the upper bounds of latency histogram buckets. The final
+// implicit bucket holds everything slower than the last bound.
+var endToEndBuckets = [...]time.Duration{
+	time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// EndToEndLatencyBucket is a histogram bucket in [EndToEndLatencyStats].
+type EndToEndLatencyBucket struct {
+	// UpperBound is the inclusive upper bound of latencies in this bucket.
+	// The last bucket has an UpperBound of -1, meaning it has no bound.
+	UpperBound time.Duration
+
+	// Count is the number of records whose latency was in this bucket and
+	// larger than the prior bucket's upper bound.
+	Count int64
+}
+
+// EndToEndLatencyStats is a distribution of produce to consume latencies for
+// a topic, as returned from [Client.EndToEndLatencies].
+type EndToEndLatencyStats struct {
+	Count int64         // Count is the number of records measured.
+	Sum   time.Duration // Sum is the sum of all latencies measured.
+	Min   time.Duration // Min is the smallest latency measured.
+	Max   time.Duration // Max is the largest latency measured.
+
+	// Buckets is a histogram of latencies measured.
+	Buckets []EndToEndLatencyBucket
+}
+
+// Mean returns the mean latency, or 0 if nothing has been measured.
+func (s EndToEndLatencyStats) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+type e2eLatencies struct {
+	mu     sync.Mutex
+	topics map[string]*e2eTopicLatency
+}
+
+type e2eTopicLatency struct {
+	count    int64
+	sum      time.Duration
+	min, max time.Duration
+	buckets  [len(endToEndBuckets) + 1]int64
+}
+
+// setEndToEndHeader sets or updates the end to end latency header on a record.
+func setEndToEndHeader(r *Record, now time.Time) {
+	v := binary.BigEndian.AppendUint64(nil, uint64(now.UnixNano()))
+	for i := range r.Headers {
+		if r.Headers[i].Key == EndToEndLatencyHeader {
+			r.Headers[i].Value = v
+			return
+		}
+	}
+	r.Headers = append(r.Headers, RecordHeader{Key: EndToEndLatencyHeader, Value: v})
+}
+
+// endToEndLatency returns the latency of a record from its end to end latency
+// header, if the record has the header.
+func endToEndLatency(r *Record, now time.Time) (time.Duration, bool) {
+	for _, h := range r.Headers {
+		if h.Key != EndToEndLatencyHeader || len(h.Value) != 8 {
+			continue
+		}
+		produced := time.Unix(0, int64(binary.BigEndian.Uint64(h.Value)))
+		return max(now.Sub(produced), 0), true
+	}
+	return 0, false
+}
+
+// observe measures the latency of all records in a polled fetch, calling
+// HookFetchRecordEndToEnd for each measured record.
+func (e *e2eLatencies) observe(cl *Client, f *Fetch) {
+	now := time.Now()
+	type measured struct {
+		r       *Record
+		latency time.Duration
+	}
+	var ms []measured
+
+	e.mu.Lock()
+	for i := range f.Topics {
+		t := &f.Topics[i]
+		var tl *e2eTopicLatency
+		for j := range t.Partitions {
+			for _, r := range t.Partitions[j].Records {
+				latency, ok := endToEndLatency(r, now)
+				if !ok {
+					continue
+				}
+				if tl == nil {
+					if tl = e.topics[t.Topic]; tl == nil {
+						if e.topics == nil {
+							e.topics = make(map[string]*e2eTopicLatency)
+						}
+						tl = new(e2eTopicLatency)
+						e.topics[t.Topic] = tl
+					}
+				}
+				tl.observe(latency)
+				ms = append(ms, measured{r, latency})
+			}
+		}
+	}
+	e.mu.Unlock()
+
+	if len(ms) == 0 {
+		return
+	}
+	cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookFetchRecordEndToEnd); ok {
+			for _, m := range ms {
+				h.OnFetchRecordEndToEnd(m.r, m.latency)
+			}
+		}
+	})
+}
+
+func (tl *e2eTopicLatency) observe(latency time.Duration) {
+	if tl.count == 0 || latency < tl.min {
+		tl.min = latency
+	}
+	if latency > tl.max {
+		tl.max = latency
+	}
+	tl.count++
+	tl.sum += latency
+	idx := len(endToEndBuckets)
+	for i, bound := range endToEndBuckets {
+		if latency <= bound {
+			idx = i
+			break
+		}
+	}
+	tl.buckets[idx]++
+}
+
+// EndToEndLatencies returns the distribution of produce to consume latencies
+// of polled records per topic, measured since the client was created. This
+// requires the [MeasureEndToEndLatency] option, and only records produced with
+// the [RecordEndToEndTimestamps] option are measured.
+//
+// Latencies are measured when records are polled, and thus include the time
+// records spend buffered in the producing and consuming clients. Latencies
+// depend on clocks being synchronized across producing and consuming hosts.
+func (cl *Client) EndToEndLatencies() map[string]EndToEndLatencyStats {
+	e := &cl.consumer.e2e
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	stats := make(map[string]EndToEndLatencyStats, len(e.topics))
+	for topic, tl := range e.topics {
+		s := EndToEndLatencyStats{
+			Count:   tl.count,
+			Sum:     tl.sum,
+			Min:     tl.min,
+			Max:     tl.max,
+			Buckets: make([]EndToEndLatencyBucket, 0, len(tl.buckets)),
+		}
+		for i, n := range tl.buckets {
+			bound := time.Duration(-1)
+			if i < len(endToEndBuckets) {
+				bound = endToEndBuckets[i]
+			}
+			s.Buckets = append(s.Buckets, EndToEndLatencyBucket{bound, n})
+		}
+		stats[topic] = s
+	}
+	return stats
+}
@@ -0,0 +1,55 @@
+package kgo
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEndToEndLatency(t *testing.T) {
+	produced := time.Unix(100, 0)
+	r := &Record{Topic: "t"}
+	setEndToEndHeader(r, produced.Add(-time.Hour))
+	setEndToEndHeader(r, produced) // overwrites
+	if len(r.Headers) != 1 {
+		t.Fatalf("got %d headers != exp 1", len(r.Headers))
+	}
+
+	cl, _ := newTestClient(MeasureEndToEndLatency())
+	defer cl.Close()
+
+	var measured []time.Duration
+	cl.cfg.hooks = append(cl.cfg.hooks, e2eHook(func(_ *Record, d time.Duration) { measured = append(measured, d) }))
+
+	now := time.Now()
+	setEndToEndHeader(r, now.Add(-3*time.Millisecond))
+	f := Fetch{Topics: []FetchTopic{{
+		Topic: "t",
+		Partitions: []FetchPartition{{Records: []*Record{
+			r,
+			{Topic: "t"}, // no header, not measured
+		}}},
+	}}}
+	cl.consumer.e2e.observe(cl, &f)
+
+	if len(measured) != 1 || measured[0] < 3*time.Millisecond {
+		t.Fatalf("got hook latencies %v, expected one of at least 3ms", measured)
+	}
+	stats := cl.EndToEndLatencies()["t"]
+	if stats.Count != 1 || stats.Min != measured[0] || stats.Max != measured[0] || stats.Mean() != measured[0] {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	var nbuckets int64
+	for _, b := range stats.Buckets {
+		nbuckets += b.Count
+		if b.Count == 1 && b.UpperBound != -1 && b.UpperBound < measured[0] {
+			t.Errorf("latency %v counted in bucket with upper bound %v", measured[0], b.UpperBound)
+		}
+	}
+	if nbuckets != 1 {
+		t.Errorf("got %d records in buckets != exp 1", nbuckets)
+	}
+}
+
+type e2eHook func(*Record, time.Duration)
+
+func (h e2eHook) OnFetchRecordEndToEnd(r *Record, d time.Duration) { h(r, d) }
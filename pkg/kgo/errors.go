@@ -72,8 +72,8 @@ func isRetryableBrokerErr(err error) bool {
 		return true
 	}
 	// We could have a retryable producer ID failure, which then bubbled up
-	// as errProducerIDLoadFail so as to be retried later.
-	if pe := (*errProducerIDLoadFail)(nil); errors.As(err, &pe) {
+	// as ErrProducerIDLoadFail so as to be retried later.
+	if pe := (*ErrProducerIDLoadFail)(nil); errors.As(err, &pe) {
 		return true
 	}
 	// We could have chosen a broker, and then a concurrent metadata update
@@ -86,6 +86,10 @@ func isRetryableBrokerErr(err error) bool {
 	if errors.Is(err, errSaslReauthLoop) {
 		return true
 	}
+	// Injected faults mimic connection failures, which we retry.
+	if errors.Is(err, ErrInjectedFault) {
+		return true
+	}
 	// We really should not get correlation mismatch, but if we do, we can
 	// retry.
 	if errors.Is(err, errCorrelationIDMismatch) {
@@ -173,22 +177,6 @@ var (
 	// that the broker cannot handle the request to-be-issued request.
 	errBrokerTooOld = errors.New("broker is too old; the broker has already indicated it will not know how to handle the request")
 
-	// Returned when trying to call group functions when the client is not
-	// assigned a group.
-	errNotGroup = errors.New("invalid group function call when not assigned a group")
-
-	// Returned when trying to begin a transaction with a client that does
-	// not have a transactional ID.
-	errNotTransactional = errors.New("invalid attempt to begin a transaction with a non-transactional client")
-
-	// Returned when trying to produce a record outside of a transaction.
-	errNotInTransaction = errors.New("cannot produce record transactionally if not in a transaction")
-
-	errNoTopic = errors.New("cannot produce record with no topic and no default topic")
-
-	// Returned for all buffered produce records when a user purges topics.
-	errPurged = errors.New("topic purged while buffered")
-
 	errMissingMetadataPartition = errors.New("metadata update is missing a partition that we were previously using")
 
 	errNoCommittedOffset = errors.New("partition has no prior committed offset")
@@ -214,6 +202,35 @@ var (
 	// AbortBufferedRecords is being called.
 	ErrAborting = errors.New("client is aborting buffered records")
 
+	// ErrNotGroup is returned when trying to call group functions when
+	// the client is not assigned a group.
+	ErrNotGroup = errors.New("invalid group function call when not assigned a group")
+
+	// ErrNotTransactional is returned when trying to begin a transaction
+	// with a client that does not have a transactional ID.
+	ErrNotTransactional = errors.New("invalid attempt to begin a transaction with a non-transactional client")
+
+	// ErrNotInTransaction is passed to produce promises when producing a
+	// record with a transactional client outside of a transaction.
+	ErrNotInTransaction = errors.New("cannot produce record transactionally if not in a transaction")
+
+	// ErrNoTopic is passed to produce promises when producing a record
+	// with no topic while the client has no default produce topic.
+	ErrNoTopic = errors.New("cannot produce record with no topic and no default topic")
+
+	// ErrTopicNotAllowed is passed to produce promises when producing a
+	// record to a topic that is not in the ProduceTopicAllowList.
+	ErrTopicNotAllowed = errors.New("cannot produce record to a topic that is not in the produce topic allow list")
+
+	// ErrMissingTimestamp is passed to produce promises when producing a
+	// record with no timestamp while using TimestampUserOnly.
+	ErrMissingTimestamp = errors.New("cannot produce record with no timestamp when only using user provided timestamps")
+
+	// ErrPurged is passed to produce promises for all buffered records
+	// in topics that are purged with PurgeTopicsFromClient or
+	// PurgeTopicsFromProducing.
+	ErrPurged = errors.New("topic purged while buffered")
+
 	// ErrClientClosed is returned in various places when the client's
 	// Close function has been called.
 	//
@@ -224,6 +241,11 @@ var (
 	//
 	// For any request, the request is failed with this error.
 	ErrClientClosed = errors.New("client closed")
+
+	// ErrInjectedFault is returned from the fault injectors used with
+	// InjectFaults. The client retries this error the same as if a
+	// connection was unexpectedly cut.
+	ErrInjectedFault = errors.New("injected fault")
 )
 
 // ErrFirstReadEOF is returned for responses that immediately error with
@@ -242,18 +264,79 @@ type ErrFirstReadEOF struct {
 	err  error
 }
 
-type errProducerIDLoadFail struct {
-	err error
+// ErrProducerIDLoadFail is passed to produce promises when the client is
+// unable to initialize a producer ID for an idempotent or transactional
+// producer. The client considers this error retryable; it is only passed to
+// promises if records cannot be retried further.
+type ErrProducerIDLoadFail struct {
+	// Err is the error that caused the producer ID load to fail.
+	Err error
 }
 
-func (e *errProducerIDLoadFail) Error() string {
-	if e.err == nil {
+func (e *ErrProducerIDLoadFail) Error() string {
+	if e.Err == nil {
 		return "unable to initialize a producer ID due to request failures"
 	}
-	return fmt.Sprintf("unable to initialize a producer ID due to request failures: %v", e.err)
+	return fmt.Sprintf("unable to initialize a producer ID due to request failures: %v", e.Err)
 }
 
-func (e *errProducerIDLoadFail) Unwrap() error { return e.err }
+func (e *ErrProducerIDLoadFail) Unwrap() error { return e.Err }
+
+// ErrRecordTooLarge is passed to produce promises when using
+// ValidateTopicMaxMessageBytes and a record is larger than its topic's
+// max.message.bytes. This unwraps to kerr.MessageTooLarge.
+type ErrRecordTooLarge struct {
+	// Topic is the topic the record was produced to.
+	Topic string
+	// Size is the size of the record when encoded alone in an
+	// uncompressed record batch.
+	Size int32
+	// MaxMessageBytes is the topic's max.message.bytes.
+	MaxMessageBytes int32
+}
+
+func (e *ErrRecordTooLarge) Error() string {
+	return fmt.Sprintf("record of %d bytes is larger than topic %q max.message.bytes %d", e.Size, e.Topic, e.MaxMessageBytes)
+}
+
+func (*ErrRecordTooLarge) Unwrap() error { return kerr.MessageTooLarge }
+
+// ErrBrokerDown is returned when a request could not be issued to a broker
+// because the client does not know of the broker (it is missing from the
+// latest metadata response), or because the broker chosen for the request
+// was removed by a concurrent metadata update. The client retries requests
+// that fail with this error when possible.
+type ErrBrokerDown struct {
+	// Broker is the node ID of the broker the request was meant for.
+	// Seed brokers have negative node IDs.
+	Broker int32
+	// Err is the internal reason the broker could not be used.
+	Err error
+}
+
+func (e *ErrBrokerDown) Error() string {
+	return fmt.Sprintf("broker %d: %v", e.Broker, e.Err)
+}
+
+func (e *ErrBrokerDown) Unwrap() error { return e.Err }
+
+// ErrBrokerTooOld is returned when a broker has indicated, through its
+// ApiVersions response, that it does not support a request. If the request
+// could be split and issued to other brokers, the client already tried to do
+// so.
+type ErrBrokerTooOld struct {
+	// Broker is the node ID of the broker that does not support the
+	// request.
+	Broker int32
+	// Key is the key of the request the broker does not support.
+	Key int16
+}
+
+func (e *ErrBrokerTooOld) Error() string {
+	return fmt.Sprintf("broker %d: %v (request key %d)", e.Broker, errBrokerTooOld, e.Key)
+}
+
+func (*ErrBrokerTooOld) Unwrap() error { return errBrokerTooOld }
 
 const (
 	firstReadDial uint8 = iota
@@ -339,11 +422,18 @@ func (e *errUnknownCoordinator) Error() string {
 // consumer group member was kicked from the group or was never able to join
 // the group.
 type ErrGroupSession struct {
+	// Err is the error that ended the group session.
 	Err error
+	// Reason is what the group member was doing when the session ended,
+	// i.e. "joining and syncing" or "heartbeating". This may be empty.
+	Reason string
 }
 
 func (e *ErrGroupSession) Error() string {
-	return fmt.Sprintf("unable to join group session: %v", e.Err)
+	if e.Reason == "" {
+		return fmt.Sprintf("unable to join group session: %v", e.Err)
+	}
+	return fmt.Sprintf("unable to join group session while %s: %v", e.Reason, e.Err)
 }
 
 func (e *ErrGroupSession) Unwrap() error { return e.Err }
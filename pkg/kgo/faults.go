@@ -0,0 +1,179 @@
+package kgo
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// FaultInjector injects latency and failures into the client's connections to
+// brokers, for use in resilience tests with InjectFaults. Faults are injected
+// inside the client, so tests exercise the same code paths that real network
+// and broker failures do without needing a proxy.
+//
+// A FaultInjector is called concurrently from every broker connection and
+// must be safe for concurrent use.
+type FaultInjector interface {
+	// InjectWrite is called before a request is written to a broker.
+	// If the returned duration is positive, the write is delayed. If the
+	// returned error is non-nil, the request is not written, the request
+	// fails with the error, and the connection is killed.
+	InjectWrite(meta BrokerMetadata, key int16) (time.Duration, error)
+
+	// InjectResponse is called after a response is read and successfully
+	// parsed, before the response is returned to whatever issued the
+	// request. The response can be modified in place. If the returned
+	// error is non-nil, the response is dropped, the request fails with
+	// the error, and the connection is killed.
+	InjectResponse(meta BrokerMetadata, resp kmsg.Response) error
+}
+
+// injectWriteFault calls all fault injectors before writing a request,
+// sleeping for the longest returned delay and returning the first error.
+func (cxn *brokerCxn) injectWriteFault(ctx context.Context, key int16) error {
+	var (
+		delay time.Duration
+		err   error
+	)
+	for _, f := range cxn.cl.cfg.faults {
+		d, ferr := f.InjectWrite(cxn.b.meta, key)
+		delay = max(delay, d)
+		if err == nil {
+			err = ferr
+		}
+	}
+	if delay > 0 {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		after := time.NewTimer(delay)
+		defer after.Stop()
+		select {
+		case <-after.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-cxn.cl.ctx.Done():
+			return ErrClientClosed
+		case <-cxn.deadCh:
+			return cxn.b.errDead()
+		}
+	}
+	return err
+}
+
+// injectResponseFault calls all fault injectors with a parsed response,
+// returning the first error.
+func (cxn *brokerCxn) injectResponseFault(resp kmsg.Response) error {
+	for _, f := range cxn.cl.cfg.faults {
+		if err := f.InjectResponse(cxn.b.meta, resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// everyNth returns true on every nth call.
+type everyNth struct {
+	n     int64
+	calls atomicI64
+}
+
+func (e *everyNth) hit() bool {
+	return e.n > 0 && e.calls.Add(1)%e.n == 0
+}
+
+type delayWrites struct {
+	delay   time.Duration
+	brokers []int32
+}
+
+// DelayWrites returns a fault injector that delays every request written to
+// the given brokers, or to all brokers if none are specified.
+func DelayWrites(delay time.Duration, brokers ...int32) FaultInjector {
+	return &delayWrites{delay, brokers}
+}
+
+func (d *delayWrites) InjectWrite(meta BrokerMetadata, _ int16) (time.Duration, error) {
+	if len(d.brokers) == 0 || slices.Contains(d.brokers, meta.NodeID) {
+		return d.delay, nil
+	}
+	return 0, nil
+}
+
+func (*delayWrites) InjectResponse(BrokerMetadata, kmsg.Response) error { return nil }
+
+type dropResponses struct {
+	key int16
+	nth everyNth
+}
+
+// DropEveryNthResponse returns a fault injector that drops every nth response
+// for the given request key (i.e., kmsg.Fetch.Int16()), failing the request
+// with ErrInjectedFault and killing the connection the response was read on.
+// The broker has still processed the request, which can be used to test
+// retries of requests that were actually handled.
+func DropEveryNthResponse(key int16, n int) FaultInjector {
+	return &dropResponses{key: key, nth: everyNth{n: int64(n)}}
+}
+
+func (*dropResponses) InjectWrite(BrokerMetadata, int16) (time.Duration, error) { return 0, nil }
+
+func (d *dropResponses) InjectResponse(_ BrokerMetadata, resp kmsg.Response) error {
+	if resp.Key() == d.key && d.nth.hit() {
+		return ErrInjectedFault
+	}
+	return nil
+}
+
+type corruptFetchCRCs struct {
+	nth everyNth
+}
+
+// CorruptEveryNthFetchCRC returns a fault injector that corrupts the CRC of
+// the first record batch of every partition in every nth fetch response that
+// contains records. Unless CRC validation is disabled, the client fails to
+// process the corrupted batches and returns an error for the partitions in
+// Fetches.
+func CorruptEveryNthFetchCRC(n int) FaultInjector {
+	return &corruptFetchCRCs{everyNth{n: int64(n)}}
+}
+
+func (*corruptFetchCRCs) InjectWrite(BrokerMetadata, int16) (time.Duration, error) { return 0, nil }
+
+func (c *corruptFetchCRCs) InjectResponse(_ BrokerMetadata, resp kmsg.Response) error {
+	r, ok := resp.(*kmsg.FetchResponse)
+	if !ok {
+		return nil
+	}
+	var hasRecords bool
+	for i := range r.Topics {
+		for j := range r.Topics[i].Partitions {
+			hasRecords = hasRecords || len(r.Topics[i].Partitions[j].RecordBatches) > 0
+		}
+	}
+	if !hasRecords || !c.nth.hit() {
+		return nil
+	}
+	for i := range r.Topics {
+		for j := range r.Topics[i].Partitions {
+			corruptCRC(r.Topics[i].Partitions[j].RecordBatches)
+		}
+	}
+	return nil
+}
+
+// corruptCRC flips the bits of the first byte of the CRC in the first batch
+// or message in raw. The CRC is at byte 17 for record batches (magic 2) and
+// at byte 12 for message sets (magic 0 and 1).
+func corruptCRC(raw []byte) {
+	if len(raw) < 21 {
+		return
+	}
+	if raw[16] == 2 {
+		raw[17] ^= 0xff
+	} else {
+		raw[12] ^= 0xff
+	}
+}
@@ -0,0 +1,107 @@
+package kgo
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestCorruptCRC(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		magic byte
+		at    int
+	}{
+		{2, 17},
+		{1, 12},
+		{0, 12},
+	} {
+		raw := make([]byte, 30)
+		raw[16] = test.magic
+		corruptCRC(raw)
+		for i, b := range raw {
+			exp := byte(0)
+			if i == 16 {
+				exp = test.magic
+			} else if i == test.at {
+				exp = 0xff
+			}
+			if b != exp {
+				t.Errorf("magic %d: byte %d: got %x != exp %x", test.magic, i, b, exp)
+			}
+		}
+	}
+
+	// Too short to contain a CRC: untouched.
+	short := make([]byte, 20)
+	corruptCRC(short)
+	for _, b := range short {
+		if b != 0 {
+			t.Error("short input was modified")
+		}
+	}
+}
+
+func TestInjectFaults(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Every other produce response is dropped: the broker has written the
+	// batch, and the idempotent retry is deduplicated, which we check by
+	// consuming each offset once below.
+	producer, _ := newTestClient(
+		InjectFaults(DropEveryNthResponse(kmsg.Produce.Int16(), 2)),
+		RetryBackoffFn(func(int) time.Duration { return 10 * time.Millisecond }),
+	)
+	defer producer.Close()
+	for i := range 10 {
+		r := &Record{Topic: topic, Value: []byte{byte(i)}}
+		if err := producer.ProduceSync(ctx, r).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+
+	// Every other fetch response is corrupted; with each fetch returning
+	// one batch, we should see CRC errors and still consume all records.
+	consumer, _ := newTestClient(
+		ConsumeTopics(topic),
+		FetchMaxPartitionBytes(1),
+		ConsumeResetOffset(NewOffset().AtStart()),
+		InjectFaults(CorruptEveryNthFetchCRC(2), DelayWrites(10*time.Millisecond)),
+	)
+	defer consumer.Close()
+	var (
+		sawCRCErr bool
+		consumed  int
+	)
+	for consumed < 10 {
+		fs := consumer.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("consumed %d records before timing out", consumed)
+		}
+		for _, fe := range fs.Errors() {
+			if !strings.Contains(fe.Err.Error(), "crc") {
+				t.Fatalf("unexpected fetch error: %v", fe.Err)
+			}
+			sawCRCErr = true
+		}
+		fs.EachRecord(func(r *Record) {
+			if r.Offset != int64(consumed) {
+				t.Errorf("got offset %d != exp %d", r.Offset, consumed)
+			}
+			consumed++
+		})
+	}
+	if !sawCRCErr {
+		t.Error("did not see an injected CRC error")
+	}
+}
@@ -0,0 +1,264 @@
+package kgo
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kgo/internal/sticky"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// A member using PreferredPartitions publishes its preferences as a user data
+// trailer, the same way as the census (see group_census.go). The preferences
+// are appended first, so the leader strips them last.
+const preferredMagic = "\x00kgo-preferred"
+
+// appendPreferred appends the preferred partitions trailer to consumer
+// protocol member metadata.
+func appendPreferred(metadata []byte, preferred map[string][]int32) []byte {
+	return appendUserDataTrailer(metadata, appendTopicPartitions(nil, preferred), preferredMagic)
+}
+
+// splitPreferred strips the preferred partitions trailer from consumer
+// protocol member metadata, returning the original metadata and the member's
+// preferred partitions.
+func splitPreferred(metadata []byte) ([]byte, map[string][]int32) {
+	stripped, data := splitUserDataTrailer(metadata, preferredMagic)
+	if data == nil {
+		return metadata, nil
+	}
+	preferred, ok := readTopicPartitions(data)
+	if !ok {
+		return metadata, nil
+	}
+	return stripped, preferred
+}
+
+// stripPreferred strips the preferred partitions trailer from all members
+// before balancing, returning each member's preferences by member ID.
+func stripPreferred(members []kmsg.JoinGroupResponseMember) map[string]map[string][]int32 {
+	var preferred map[string]map[string][]int32
+	for i := range members {
+		m := &members[i]
+		var prefs map[string][]int32
+		m.ProtocolMetadata, prefs = splitPreferred(m.ProtocolMetadata)
+		if len(prefs) > 0 {
+			if preferred == nil {
+				preferred = make(map[string]map[string][]int32)
+			}
+			preferred[m.MemberID] = prefs
+		}
+	}
+	return preferred
+}
+
+// appendTopicPartitions appends a versioned, sorted encoding of tps.
+func appendTopicPartitions(dst []byte, tps map[string][]int32) []byte {
+	topics := make([]string, 0, len(tps))
+	for t := range tps {
+		topics = append(topics, t)
+	}
+	sort.Strings(topics)
+
+	dst = kbin.AppendInt8(dst, 0) // version
+	dst = kbin.AppendArrayLen(dst, len(topics))
+	for _, t := range topics {
+		ps := slices.Clone(tps[t])
+		slices.Sort(ps)
+		dst = kbin.AppendString(dst, t)
+		dst = kbin.AppendArrayLen(dst, len(ps))
+		for _, p := range ps {
+			dst = kbin.AppendInt32(dst, p)
+		}
+	}
+	return dst
+}
+
+// readTopicPartitions reads what was written with appendTopicPartitions.
+func readTopicPartitions(src []byte) (map[string][]int32, bool) {
+	b := kbin.Reader{Src: src}
+	if b.Int8() != 0 {
+		return nil, false
+	}
+	tps := make(map[string][]int32)
+	for nt := b.ArrayLen(); nt > 0 && b.Ok(); nt-- {
+		t := b.String()
+		for np := b.ArrayLen(); np > 0 && b.Ok(); np-- {
+			tps[t] = append(tps[t], b.Int32())
+		}
+	}
+	return tps, b.Ok()
+}
+
+// PreferredPartitions returns the partitions a member prefers to be assigned,
+// as published with the PreferredPartitions option, or nil if the member did
+// not publish preferences. Preferences are only available when balancing as a
+// kgo group leader.
+func (b *ConsumerBalancer) PreferredPartitions(memberID string) map[string][]int32 {
+	return b.preferred[memberID]
+}
+
+// CooperativeAffinityStickyBalancer returns a cooperative sticky balancer that
+// honors the partitions members prefer with the PreferredPartitions option.
+// This is useful for stateful consumers: a member that already has the local
+// state for a partition can ask for it, avoiding rebuilding the state
+// elsewhere.
+//
+// Stickiness wins over preferences: a partition currently owned by a member
+// stays with that member if the plan remains balanced, exactly as with the
+// cooperative sticky balancer. Partitions that are not owned by anybody, such
+// as after the group restarts or after an owner leaves, are assigned to a
+// member that prefers them, if the plan remains balanced. If multiple members
+// prefer the same unowned partition, the member with fewer preferences
+// honored so far wins.
+//
+// This balancer uses the protocol name "cooperative-affinity-sticky" and is
+// only understood by kgo clients, so every member of the group must use it.
+func CooperativeAffinityStickyBalancer() GroupBalancer {
+	return new(affinityBalancer)
+}
+
+type affinityBalancer struct{}
+
+func (*affinityBalancer) ProtocolName() string { return "cooperative-affinity-sticky" }
+func (*affinityBalancer) IsCooperative() bool  { return true }
+
+func (*affinityBalancer) JoinGroupMetadata(interests []string, currentAssignment map[string][]int32, generation int32) []byte {
+	return (&stickyBalancer{cooperative: true}).JoinGroupMetadata(interests, currentAssignment, generation)
+}
+
+func (*affinityBalancer) ParseSyncAssignment(assignment []byte) (map[string][]int32, error) {
+	return ParseConsumerSyncAssignment(assignment)
+}
+
+func (a *affinityBalancer) MemberBalancer(members []kmsg.JoinGroupResponseMember) (GroupMemberBalancer, map[string]struct{}, error) {
+	b, err := NewConsumerBalancer(a, members)
+	return b, b.MemberTopics(), err
+}
+
+func (*affinityBalancer) Balance(b *ConsumerBalancer, topics map[string]int32) IntoSyncAssignment {
+	stickyMembers := affinityStickyMembers(b, topics)
+	p := &BalancePlan{plan: sticky.Balance(stickyMembers, topics)}
+	p.AdjustCooperative(b)
+	return p
+}
+
+// affinityStickyMembers returns the members to pass to the sticky balancer,
+// with every unowned partition that a member prefers added to that member's
+// owned partitions. The sticky balancer keeps owned partitions where they
+// are if it can, so this biases the plan towards preferences without moving
+// partitions away from real owners. Cooperative adjusting uses the real owned
+// partitions in the member metadata, which we do not modify.
+func affinityStickyMembers(b *ConsumerBalancer, topics map[string]int32) []sticky.GroupMember {
+	type tp struct {
+		t string
+		p int32
+	}
+	owned := make(map[tp]struct{})
+	b.EachMember(func(_ *kmsg.JoinGroupResponseMember, meta *kmsg.ConsumerMemberMetadata) {
+		if meta.Generation < 0 {
+			return // the sticky balancer ignores Owned if the generation is negative
+		}
+		for _, t := range meta.OwnedPartitions {
+			for _, p := range t.Partitions {
+				owned[tp{t.Topic, p}] = struct{}{}
+			}
+		}
+	})
+
+	// We honor preferences round robin across members so that one
+	// member that prefers everything does not starve the others.
+	type pending struct {
+		idx   int
+		prefs []tp
+	}
+	var (
+		members  = b.Members()
+		extra    = make([][]tp, len(members))
+		queues   []*pending
+		claimed  = make(map[tp]struct{})
+		subbed   = make([]map[string]bool, len(members))
+		metadata = b.metadatas
+	)
+	for i := range members {
+		subbed[i] = make(map[string]bool)
+		for _, t := range metadata[i].Topics {
+			subbed[i][t] = true
+		}
+		prefs := b.PreferredPartitions(members[i].MemberID)
+		if len(prefs) == 0 {
+			continue
+		}
+		q := &pending{idx: i}
+		ptopics := make([]string, 0, len(prefs))
+		for t := range prefs {
+			ptopics = append(ptopics, t)
+		}
+		sort.Strings(ptopics)
+		for _, t := range ptopics {
+			ps := slices.Clone(prefs[t])
+			slices.Sort(ps)
+			for _, p := range ps {
+				q.prefs = append(q.prefs, tp{t, p})
+			}
+		}
+		queues = append(queues, q)
+	}
+	for len(queues) > 0 {
+		keep := queues[:0]
+		for _, q := range queues {
+			for len(q.prefs) > 0 {
+				want := q.prefs[0]
+				q.prefs = q.prefs[1:]
+				if n, ok := topics[want.t]; !ok || want.p < 0 || want.p >= n || !subbed[q.idx][want.t] {
+					continue
+				}
+				if _, ok := owned[want]; ok {
+					continue
+				}
+				if _, ok := claimed[want]; ok {
+					continue
+				}
+				claimed[want] = struct{}{}
+				extra[q.idx] = append(extra[q.idx], want)
+				break
+			}
+			if len(q.prefs) > 0 {
+				keep = append(keep, q)
+			}
+		}
+		queues = keep
+	}
+
+	stickyMembers := make([]sticky.GroupMember, 0, len(members))
+	for i := range members {
+		meta := &metadata[i]
+		m := sticky.GroupMember{
+			ID:          members[i].MemberID,
+			Topics:      meta.Topics,
+			UserData:    meta.UserData,
+			Owned:       meta.OwnedPartitions,
+			Generation:  meta.Generation,
+			Cooperative: true,
+		}
+		if len(extra[i]) > 0 {
+			if m.Generation < 0 {
+				m.Generation = 0
+			}
+			m.Owned = slices.Clone(m.Owned)
+			for _, want := range extra[i] {
+				idx := slices.IndexFunc(m.Owned, func(o kmsg.ConsumerMemberMetadataOwnedPartition) bool { return o.Topic == want.t })
+				if idx < 0 {
+					o := kmsg.NewConsumerMemberMetadataOwnedPartition()
+					o.Topic = want.t
+					m.Owned = append(m.Owned, o)
+					idx = len(m.Owned) - 1
+				}
+				m.Owned[idx].Partitions = append(slices.Clone(m.Owned[idx].Partitions), want.p)
+			}
+		}
+		stickyMembers = append(stickyMembers, m)
+	}
+	return stickyMembers
+}
@@ -0,0 +1,180 @@
+package kgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPreferredTrailer(t *testing.T) {
+	prefs := map[string][]int32{"b": {3, 1}, "a": {0}}
+	orig := CooperativeAffinityStickyBalancer().JoinGroupMetadata([]string{"a", "b"}, nil, -1)
+
+	members := []kmsg.JoinGroupResponseMember{{
+		MemberID:         "m",
+		ProtocolMetadata: appendShed(appendPreferred(orig, prefs), map[string][]int32{"a": {0}}),
+	}}
+	sheds := stripSheds(members)
+	preferred := stripPreferred(members)
+	if exp := map[string]map[string][]int32{"m": {"a": {0}, "b": {1, 3}}}; !reflect.DeepEqual(preferred, exp) {
+		t.Errorf("got preferred %v != exp %v", preferred, exp)
+	}
+	if len(sheds["m"]) != 1 {
+		t.Errorf("got unexpected sheds %v", sheds)
+	}
+	if string(members[0].ProtocolMetadata) != string(orig) {
+		t.Error("stripped metadata does not match original")
+	}
+}
+
+func TestAffinityBalance(t *testing.T) {
+	type member struct {
+		id    string
+		owned map[string][]int32
+		prefs map[string][]int32
+	}
+	balance := func(topics map[string]int32, ms ...member) map[string]map[string][]int32 {
+		var (
+			jms       []kmsg.JoinGroupResponseMember
+			preferred = make(map[string]map[string][]int32)
+		)
+		for _, m := range ms {
+			gen := int32(-1)
+			if m.owned != nil {
+				gen = 1
+			}
+			meta := CooperativeAffinityStickyBalancer().JoinGroupMetadata([]string{"t"}, m.owned, gen)
+			jms = append(jms, kmsg.JoinGroupResponseMember{MemberID: m.id, ProtocolMetadata: meta})
+			preferred[m.id] = m.prefs
+		}
+		b, err := NewConsumerBalancer(new(affinityBalancer), jms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.preferred = preferred
+		plan := new(affinityBalancer).Balance(b, topics).(*BalancePlan).AsMemberIDMap()
+		for _, ts := range plan {
+			for _, ps := range ts {
+				slices.Sort(ps)
+			}
+		}
+		return plan
+	}
+	topics := map[string]int32{"t": 4}
+
+	for _, test := range []struct {
+		name    string
+		members []member
+		exp     map[string]map[string][]int32
+	}{
+		{
+			"fresh group follows preferences",
+			[]member{
+				{id: "m1", prefs: map[string][]int32{"t": {2, 3}}},
+				{id: "m2", prefs: map[string][]int32{"t": {0}}},
+			},
+			map[string]map[string][]int32{"m1": {"t": {2, 3}}, "m2": {"t": {0, 1}}},
+		},
+		{
+			"ownership beats preference",
+			[]member{
+				{id: "m1", owned: map[string][]int32{"t": {0, 1}}},
+				{id: "m2", prefs: map[string][]int32{"t": {0, 1, 2, 3}}},
+			},
+			map[string]map[string][]int32{"m1": {"t": {0, 1}}, "m2": {"t": {2, 3}}},
+		},
+		{
+			"conflicting preferences are round robin",
+			[]member{
+				{id: "m1", prefs: map[string][]int32{"t": {0, 1}}},
+				{id: "m2", prefs: map[string][]int32{"t": {0, 3}}},
+			},
+			map[string]map[string][]int32{"m1": {"t": {0, 1}}, "m2": {"t": {2, 3}}},
+		},
+		{
+			"preferences do not unbalance",
+			[]member{
+				{id: "m1", prefs: map[string][]int32{"t": {0, 1, 2, 3}}},
+				{id: "m2"},
+			},
+			nil, // checked below
+		},
+	} {
+		got := balance(topics, test.members...)
+		if test.exp == nil {
+			if len(got["m1"]["t"]) != 2 || len(got["m2"]["t"]) != 2 {
+				t.Errorf("%s: got unbalanced plan %v", test.name, got)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("%s: got %v != exp %v", test.name, got, test.exp)
+		}
+	}
+}
+
+// preferredRecorder records what preferences the leader saw before balancing.
+type preferredRecorder struct {
+	*affinityBalancer
+	seen chan map[string][]int32
+}
+
+func (r *preferredRecorder) MemberBalancer(members []kmsg.JoinGroupResponseMember) (GroupMemberBalancer, map[string]struct{}, error) {
+	b, err := NewConsumerBalancer(r, members)
+	return b, b.MemberTopics(), err
+}
+
+func (r *preferredRecorder) Balance(b *ConsumerBalancer, topics map[string]int32) IntoSyncAssignment {
+	m, _ := b.MemberAt(0)
+	select {
+	case r.seen <- b.PreferredPartitions(m.MemberID):
+	default:
+	}
+	return r.affinityBalancer.Balance(b, topics)
+}
+
+func TestPreferredPartitions(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 4)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	r := &preferredRecorder{new(affinityBalancer), make(chan map[string][]int32, 1)}
+	cl, _ := newTestClient(
+		ConsumerGroup(group),
+		ConsumeTopics(topic),
+		Balancers(r),
+		PreferredPartitions(func() map[string][]int32 { return map[string][]int32{topic: {2, 0}} }),
+	)
+	defer cl.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	go func() {
+		for ctx.Err() == nil {
+			cl.PollFetches(ctx)
+		}
+	}()
+
+	select {
+	case seen := <-r.seen:
+		if exp := map[string][]int32{topic: {0, 2}}; !reflect.DeepEqual(seen, exp) {
+			t.Errorf("got preferred %v != exp %v", seen, exp)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the leader to balance")
+	}
+	wait(t, 10*time.Second, func() error {
+		if n := len(cl.consumer.g.nowAssigned.read()[topic]); n != 4 {
+			return fmt.Errorf("waiting for all partitions to be assigned, have %d", n)
+		}
+		return nil
+	})
+}
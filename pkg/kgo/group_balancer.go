@@ -101,6 +101,7 @@ type ConsumerBalancer struct {
 	members   []kmsg.JoinGroupResponseMember
 	metadatas []kmsg.ConsumerMemberMetadata
 	topics    map[string]struct{}
+	preferred map[string]map[string][]int32 // member => topic => partitions
 
 	err error
 }
@@ -159,7 +160,7 @@ func (b *ConsumerBalancer) NewPlan() *BalancePlan {
 	for i := range b.members {
 		plan[b.members[i].MemberID] = make(map[string][]int32)
 	}
-	return &BalancePlan{plan}
+	return &BalancePlan{plan: plan}
 }
 
 // ConsumerBalancerBalance is what the ConsumerBalancer invokes to balance a
@@ -235,7 +236,8 @@ func NewConsumerBalancer(balance ConsumerBalancerBalance, members []kmsg.JoinGro
 // BalancePlan is a helper type to build the result of balancing topics
 // and partitions among group members.
 type BalancePlan struct {
-	plan map[string]map[string][]int32 // member => topic => partitions
+	plan    map[string]map[string][]int32 // member => topic => partitions
+	standby map[string]map[string][]int32 // member => topic => partitions, from the standby balancer
 }
 
 // AsMemberIDMap returns the plan as a map of member IDs to their topic &
@@ -303,6 +305,9 @@ func (p *BalancePlan) IntoSyncAssignment() []kmsg.SyncGroupRequestGroupAssignmen
 			kassignment.Topics = append(kassignment.Topics, assnTopic)
 		}
 		sort.Slice(kassignment.Topics, func(i, j int) bool { return kassignment.Topics[i].Topic < kassignment.Topics[j].Topic })
+		if standby := p.standby[member]; len(standby) > 0 {
+			kassignment.UserData = appendStandby(standby)
+		}
 		syncAssn := kmsg.NewSyncGroupRequestGroupAssignment()
 		syncAssn.MemberID = member
 		syncAssn.MemberAssignment = kassignment.AppendTo(nil)
@@ -361,12 +366,18 @@ func (g *groupConsumer) balanceGroup(proto string, members []kmsg.JoinGroupRespo
 		return nil, err
 	}
 
+	g.stripCensuses(members)
+	sheds := stripSheds(members)
+	preferred := stripPreferred(members)
 	sortJoinMembers(members)
 
 	memberBalancer, topics, err := b.MemberBalancer(members)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create group member balancer: %v", err)
 	}
+	if cb, ok := memberBalancer.(*ConsumerBalancer); ok {
+		cb.preferred = preferred
+	}
 
 	myTopics := g.tps.load()
 	var needMeta bool
@@ -459,7 +470,10 @@ func (g *groupConsumer) balanceGroup(proto string, members []kmsg.JoinGroupRespo
 		case RangeBalancer().ProtocolName(),
 			RoundRobinBalancer().ProtocolName(),
 			StickyBalancer().ProtocolName(),
-			CooperativeStickyBalancer().ProtocolName():
+			CooperativeStickyBalancer().ProtocolName(),
+			CooperativeAffinityStickyBalancer().ProtocolName(),
+			CooperativeStandbyBalancer(0).ProtocolName(),
+			StaticAssignBalancer(nil).ProtocolName():
 		default:
 			return nil, nil
 		}
@@ -479,6 +493,10 @@ func (g *groupConsumer) balanceGroup(proto string, members []kmsg.JoinGroupRespo
 	}
 
 	if p, ok := into.(*BalancePlan); ok {
+		if cb, ok := memberBalancer.(*ConsumerBalancer); ok && len(sheds) > 0 {
+			moved := p.applySheds(cb, sheds)
+			g.cl.cfg.logger.Log(LogLevelInfo, "moved partitions members are shedding", "sheds", sheds, "moved", moved)
+		}
 		g.cl.cfg.logger.Log(LogLevelInfo, "balanced", "plan", p.String())
 	} else {
 		g.cl.cfg.logger.Log(LogLevelInfo, "unable to log balance plan: the user has returned a custom IntoSyncAssignment (not a *BalancePlan)")
@@ -792,7 +810,7 @@ func (s *stickyBalancer) Balance(b *ConsumerBalancer, topics map[string]int32) I
 		})
 	})
 
-	p := &BalancePlan{sticky.Balance(stickyMembers, topics)}
+	p := &BalancePlan{plan: sticky.Balance(stickyMembers, topics)}
 	if s.cooperative {
 		p.AdjustCooperative(b)
 	}
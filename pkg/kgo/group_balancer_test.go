@@ -92,7 +92,7 @@ func Test_stickyAdjustCooperative(t *testing.T) {
 		},
 	}
 
-	(&BalancePlan{inPlan}).AdjustCooperative(b)
+	(&BalancePlan{plan: inPlan}).AdjustCooperative(b)
 
 	if !reflect.DeepEqual(inPlan, expPlan) {
 		t.Errorf("got plan != exp\ngot: %#v\nexp: %#v\n", inPlan, expPlan)
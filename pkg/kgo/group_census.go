@@ -0,0 +1,240 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The group member census is appended to the UserData of each consumer
+// protocol's member metadata as a trailer:
+//
+//	[original user data][census][int32 census length][censusMagic]
+//
+// Balancers parse their own user data from the front, and we strip the
+// trailer before passing members to balancers as leader. The Java consumer
+// ignores user data for range and roundrobin and ignores trailing bytes in
+// the sticky user data, so the trailer is safe to add for groups using the
+// standard balancers.
+
+const censusMagic = "\x00kgo-census"
+
+// MemberCensus is host and process metadata a group member publishes when
+// using the [GroupMemberCensus] option.
+type MemberCensus struct {
+	Hostname        string    // Hostname is the member's os.Hostname, if available.
+	PID             int       // PID is the member's process ID.
+	StartTime       time.Time // StartTime is when the member's client was created.
+	ClientID        string    // ClientID is the member's client ID.
+	SoftwareName    string    // SoftwareName is the member's KIP-511 client software name.
+	SoftwareVersion string    // SoftwareVersion is the member's KIP-511 client software version.
+}
+
+// GroupMember is a member of the group this client is in, as returned from
+// [Client.GroupMembers].
+type GroupMember struct {
+	MemberID   string  // MemberID is the member ID the broker assigned to this member.
+	InstanceID *string // InstanceID is the member's instance ID, if any.
+	ClientID   string  // ClientID is the client ID the broker sees for this member.
+	ClientHost string  // ClientHost is the host the broker sees for this member.
+	Self       bool    // Self is whether this member is this client.
+
+	// Census is the census metadata the member published, or nil if the
+	// member did not enable GroupMemberCensus (or is not a kgo client).
+	Census *MemberCensus
+}
+
+func (c *MemberCensus) appendTo(dst []byte) []byte {
+	dst = kbin.AppendInt8(dst, 0) // version
+	dst = kbin.AppendString(dst, c.Hostname)
+	dst = kbin.AppendInt64(dst, int64(c.PID))
+	dst = kbin.AppendInt64(dst, c.StartTime.UnixMilli())
+	dst = kbin.AppendString(dst, c.ClientID)
+	dst = kbin.AppendString(dst, c.SoftwareName)
+	dst = kbin.AppendString(dst, c.SoftwareVersion)
+	return dst
+}
+
+func (c *MemberCensus) readFrom(src []byte) bool {
+	b := kbin.Reader{Src: src}
+	if b.Int8() != 0 {
+		return false
+	}
+	c.Hostname = b.String()
+	c.PID = int(b.Int64())
+	c.StartTime = time.UnixMilli(b.Int64())
+	c.ClientID = b.String()
+	c.SoftwareName = b.String()
+	c.SoftwareVersion = b.String()
+	return b.Ok()
+}
+
+func (cl *Client) newMemberCensus() []byte {
+	host, _ := os.Hostname()
+	c := MemberCensus{
+		Hostname:        host,
+		PID:             os.Getpid(),
+		StartTime:       time.Now(),
+		SoftwareName:    cl.cfg.softwareName,
+		SoftwareVersion: cl.cfg.softwareVersion,
+	}
+	if cl.cfg.id != nil {
+		c.ClientID = *cl.cfg.id
+	}
+	return c.appendTo(nil)
+}
+
+// appendCensus appends the census trailer to the user data in consumer
+// protocol member metadata. If the metadata is not consumer protocol metadata,
+// this returns the metadata unchanged.
+func appendCensus(metadata, census []byte) []byte {
+	return appendUserDataTrailer(metadata, census, censusMagic)
+}
+
+// splitCensus strips the census trailer from consumer protocol member
+// metadata, returning the original metadata and the census. If the metadata
+// has no census, this returns the metadata unchanged and a nil census.
+func splitCensus(metadata []byte) ([]byte, *MemberCensus) {
+	stripped, trailer := splitUserDataTrailer(metadata, censusMagic)
+	if trailer == nil {
+		return metadata, nil
+	}
+	c := new(MemberCensus)
+	if !c.readFrom(trailer) {
+		return metadata, nil
+	}
+	return stripped, c
+}
+
+// appendUserDataTrailer appends data and magic as a trailer to the user data
+// in consumer protocol member metadata:
+//
+//	[original user data][data][int32 data length][magic]
+//
+// If the metadata is not consumer protocol metadata, this returns the
+// metadata unchanged.
+func appendUserDataTrailer(metadata, data []byte, magic string) []byte {
+	var meta kmsg.ConsumerMemberMetadata
+	if err := meta.ReadFrom(metadata); err != nil {
+		return metadata
+	}
+	userData := append([]byte(nil), meta.UserData...)
+	userData = append(userData, data...)
+	userData = kbin.AppendInt32(userData, int32(len(data)))
+	meta.UserData = append(userData, magic...)
+	return meta.AppendTo(nil)
+}
+
+// splitUserDataTrailer strips the trailer ending in magic from consumer
+// protocol member metadata, returning the metadata without the trailer and
+// the trailer's data. If the metadata has no such trailer, this returns the
+// metadata unchanged and nil data.
+func splitUserDataTrailer(metadata []byte, magic string) ([]byte, []byte) {
+	var meta kmsg.ConsumerMemberMetadata
+	if err := meta.ReadFrom(metadata); err != nil {
+		return metadata, nil
+	}
+	userData := meta.UserData
+	if !bytes.HasSuffix(userData, []byte(magic)) {
+		return metadata, nil
+	}
+	userData = userData[:len(userData)-len(magic)]
+	if len(userData) < 4 {
+		return metadata, nil
+	}
+	b := kbin.Reader{Src: userData[len(userData)-4:]}
+	n := int(b.Int32())
+	userData = userData[:len(userData)-4]
+	if n < 0 || n > len(userData) {
+		return metadata, nil
+	}
+	data := userData[len(userData)-n:]
+	meta.UserData = userData[:len(userData)-n]
+	if len(meta.UserData) == 0 {
+		meta.UserData = nil
+	}
+	return meta.AppendTo(nil), data
+}
+
+// stripCensuses strips the census from all members before balancing, and
+// warns if any process has joined the group more than once.
+func (g *groupConsumer) stripCensuses(members []kmsg.JoinGroupResponseMember) {
+	type hostPID struct {
+		host string
+		pid  int
+	}
+	seen := make(map[hostPID][]string)
+	for i := range members {
+		m := &members[i]
+		var c *MemberCensus
+		m.ProtocolMetadata, c = splitCensus(m.ProtocolMetadata)
+		if c != nil {
+			hp := hostPID{c.Hostname, c.PID}
+			seen[hp] = append(seen[hp], m.MemberID)
+		}
+	}
+	for hp, ids := range seen {
+		if len(ids) > 1 {
+			g.cl.cfg.logger.Log(LogLevelWarn, "multiple group members are from the same process; this may be an accidentally duplicated client",
+				"host", hp.host,
+				"pid", hp.pid,
+				"member_ids", ids,
+			)
+		}
+	}
+}
+
+// GroupMembers describes the group this client is in and returns all members
+// of the group, sorted by member ID. If members use the [GroupMemberCensus]
+// option, their published host and process metadata is included. This can be
+// used to debug who else is in your group, for example to find an
+// accidentally duplicated deployment.
+//
+// This issues a DescribeGroups request to the group coordinator. This returns
+// an error if the client is not configured with a group, if the request fails,
+// or if the group cannot be described.
+func (cl *Client) GroupMembers(ctx context.Context) ([]GroupMember, error) {
+	if cl.cfg.group == "" {
+		return nil, ErrNotGroup
+	}
+	var self string
+	if g := cl.consumer.g; g != nil {
+		self = g.memberGen.memberID()
+	}
+
+	req := kmsg.NewPtrDescribeGroupsRequest()
+	req.Groups = []string{cl.cfg.group}
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Groups) != 1 {
+		return nil, fmt.Errorf("describe groups response returned %d groups, expected 1", len(resp.Groups))
+	}
+	rg := &resp.Groups[0]
+	if err := kerr.ErrorForCode(rg.ErrorCode); err != nil {
+		return nil, err
+	}
+
+	members := make([]GroupMember, 0, len(rg.Members))
+	for _, rm := range rg.Members {
+		m := GroupMember{
+			MemberID:   rm.MemberID,
+			InstanceID: rm.InstanceID,
+			ClientID:   rm.ClientID,
+			ClientHost: rm.ClientHost,
+			Self:       rm.MemberID == self && self != "",
+		}
+		_, m.Census = splitCensus(rm.ProtocolMetadata)
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].MemberID < members[j].MemberID })
+	return members, nil
+}
@@ -0,0 +1,51 @@
+package kgo
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestCensusRoundTrip(t *testing.T) {
+	exp := MemberCensus{
+		Hostname:        "host",
+		PID:             1234,
+		StartTime:       time.UnixMilli(1700000000000),
+		ClientID:        "cid",
+		SoftwareName:    "kgo",
+		SoftwareVersion: "v1",
+	}
+	census := exp.appendTo(nil)
+
+	for _, b := range []GroupBalancer{
+		RangeBalancer(),
+		StickyBalancer(),
+		CooperativeStickyBalancer(),
+	} {
+		orig := b.JoinGroupMetadata([]string{"a", "b"}, map[string][]int32{"a": {0, 1}}, 3)
+		withCensus := appendCensus(orig, census)
+		if bytes.Equal(orig, withCensus) {
+			t.Errorf("%s: census was not appended", b.ProtocolName())
+			continue
+		}
+		stripped, got := splitCensus(withCensus)
+		if got == nil {
+			t.Errorf("%s: census not found", b.ProtocolName())
+			continue
+		}
+		if !got.StartTime.Equal(exp.StartTime) {
+			t.Errorf("%s: got start time %v != exp %v", b.ProtocolName(), got.StartTime, exp.StartTime)
+		}
+		got.StartTime = exp.StartTime
+		if *got != exp {
+			t.Errorf("%s: got census %+v != exp %+v", b.ProtocolName(), *got, exp)
+		}
+		if !bytes.Equal(stripped, orig) {
+			t.Errorf("%s: stripped metadata does not match original", b.ProtocolName())
+		}
+	}
+
+	if stripped, got := splitCensus([]byte("not consumer metadata")); got != nil || string(stripped) != "not consumer metadata" {
+		t.Errorf("unexpectedly split census from invalid metadata")
+	}
+}
@@ -0,0 +1,258 @@
+package kgo
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// A member shedding partitions with ShedPartitions publishes what it is
+// shedding as a user data trailer, the same way as the census (see
+// group_census.go). The shed trailer is appended before the census, so the
+// leader strips the census first.
+const shedMagic = "\x00kgo-shed"
+
+var errShedNotCooperative = errors.New("shedding partitions requires a cooperative group balancer")
+
+// ShedPartitions hands off n of this member's currently assigned partitions to
+// other group members, returning the partitions being shed. This triggers a
+// cooperative rebalance in which only the shed partitions move: this member
+// revokes the shed partitions (calling OnPartitionsRevoked), rejoins without
+// them, and the group leader assigns each to the least loaded other member
+// that is subscribed to the partition's topic. This allows autoscaling
+// controllers to move load off of a hot member without stopping the entire
+// group.
+//
+// Partitions are chosen evenly across topics, preferring the highest
+// numbered partitions. If n is larger than the number of assigned
+// partitions, all assigned partitions are shed.
+//
+// The leader honors the request only if it is a kgo client that supports
+// shedding; other leaders may assign the partitions back to this member.
+// If no other member can consume a shed partition, it is assigned back to
+// this member. Shedding is a one time handoff: a later rebalance may move
+// partitions back to balance the group.
+//
+// This requires a cooperative balancer and is not supported with KIP-848
+// (ConsumerGroupRebalanceProtocol), where the broker assigns partitions.
+func (cl *Client) ShedPartitions(n int) (map[string][]int32, error) {
+	g := cl.consumer.g
+	if g == nil {
+		return nil, ErrNotGroup
+	}
+	if !g.cooperative.Load() {
+		return nil, errShedNotCooperative
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.is848 {
+		return nil, errors.New("shedding partitions is not supported with KIP-848 consumer groups")
+	}
+
+	shed := pickShed(g.nowAssigned.read(), g.shed, n)
+	if len(shed) == 0 {
+		return nil, nil
+	}
+	if g.shed == nil {
+		g.shed = make(map[string][]int32)
+	}
+	var total int
+	for t, ps := range shed {
+		g.shed[t] = append(g.shed[t], ps...)
+		total += len(ps)
+	}
+	g.cfg.logger.Log(LogLevelInfo, "shedding partitions", "group", g.cfg.group, "shedding", shed)
+	g.rejoin(fmt.Sprintf("shedding %d partitions from ShedPartitions", total))
+	return shed, nil
+}
+
+// pickShed returns up to n partitions from assigned that are not already
+// being shed, round robin across sorted topics, highest partitions first.
+func pickShed(assigned, shedding map[string][]int32, n int) map[string][]int32 {
+	var (
+		topics    []string
+		available = make(map[string][]int32)
+	)
+	for t, ps := range assigned {
+		var avail []int32
+		for _, p := range ps {
+			if !slices.Contains(shedding[t], p) {
+				avail = append(avail, p)
+			}
+		}
+		if len(avail) > 0 {
+			slices.Sort(avail)
+			available[t] = avail
+			topics = append(topics, t)
+		}
+	}
+	sort.Strings(topics)
+
+	shed := make(map[string][]int32)
+	for picked := 0; picked < n && len(topics) > 0; {
+		keep := topics[:0]
+		for _, t := range topics {
+			if picked == n {
+				break
+			}
+			ps := available[t]
+			shed[t] = append(shed[t], ps[len(ps)-1])
+			picked++
+			if ps = ps[:len(ps)-1]; len(ps) > 0 {
+				available[t] = ps
+				keep = append(keep, t)
+			}
+		}
+		topics = keep
+	}
+	if len(shed) == 0 {
+		return nil
+	}
+	return shed
+}
+
+// takeShed removes the partitions we are shedding from nowAssigned and
+// g.lastAssigned, returning what was removed from nowAssigned so that it can
+// be revoked. This is called while revoking at the end of a session with
+// g.mu held: we rejoin without owning the partitions, and we keep g.shed
+// until we are next assigned so that the leader knows not to give them back.
+func (g *groupConsumer) takeShed(nowAssigned map[string][]int32) map[string][]int32 {
+	var lost map[string][]int32
+	for t, shedPs := range g.shed {
+		if owned, ok := nowAssigned[t]; ok {
+			var kept []int32
+			for _, p := range owned {
+				if slices.Contains(shedPs, p) {
+					if lost == nil {
+						lost = make(map[string][]int32)
+					}
+					lost[t] = append(lost[t], p)
+				} else {
+					kept = append(kept, p)
+				}
+			}
+			if len(kept) == 0 {
+				delete(nowAssigned, t)
+			} else {
+				nowAssigned[t] = kept
+			}
+		}
+		if last, ok := g.lastAssigned[t]; ok {
+			kept := slices.DeleteFunc(slices.Clone(last), func(p int32) bool { return slices.Contains(shedPs, p) })
+			if len(kept) == 0 {
+				delete(g.lastAssigned, t)
+			} else {
+				g.lastAssigned[t] = kept
+			}
+		}
+	}
+	return lost
+}
+
+// appendShed appends the shed trailer to consumer protocol member metadata.
+func appendShed(metadata []byte, shed map[string][]int32) []byte {
+	return appendUserDataTrailer(metadata, appendTopicPartitions(nil, shed), shedMagic)
+}
+
+// splitShed strips the shed trailer from consumer protocol member metadata,
+// returning the original metadata and what the member is shedding.
+func splitShed(metadata []byte) ([]byte, map[string][]int32) {
+	stripped, data := splitUserDataTrailer(metadata, shedMagic)
+	if data == nil {
+		return metadata, nil
+	}
+	shed, ok := readTopicPartitions(data)
+	if !ok {
+		return metadata, nil
+	}
+	return stripped, shed
+}
+
+// stripSheds strips the shed trailer from all members before balancing,
+// returning what each member is shedding, by member ID.
+func stripSheds(members []kmsg.JoinGroupResponseMember) map[string]map[string][]int32 {
+	var sheds map[string]map[string][]int32
+	for i := range members {
+		m := &members[i]
+		var shed map[string][]int32
+		m.ProtocolMetadata, shed = splitShed(m.ProtocolMetadata)
+		if len(shed) > 0 {
+			if sheds == nil {
+				sheds = make(map[string]map[string][]int32)
+			}
+			sheds[m.MemberID] = shed
+		}
+	}
+	return sheds
+}
+
+// applySheds moves partitions that the balancer assigned back to the member
+// shedding them to the least loaded other member consuming the partition's
+// topic. The shedding member rejoined without owning the partitions, so no
+// member owns them and they can move in this rebalance.
+func (p *BalancePlan) applySheds(b *ConsumerBalancer, sheds map[string]map[string][]int32) (moved int) {
+	subscribed := make(map[string][]string) // topic => members
+	b.EachMember(func(member *kmsg.JoinGroupResponseMember, meta *kmsg.ConsumerMemberMetadata) {
+		for _, t := range meta.Topics {
+			subscribed[t] = append(subscribed[t], member.MemberID)
+		}
+	})
+	load := func(member string) (n int) {
+		for _, ps := range p.plan[member] {
+			n += len(ps)
+		}
+		return n
+	}
+
+	shedders := make([]string, 0, len(sheds))
+	for member := range sheds {
+		shedders = append(shedders, member)
+	}
+	sort.Strings(shedders)
+
+	for _, from := range shedders {
+		topics := make([]string, 0, len(sheds[from]))
+		for t := range sheds[from] {
+			topics = append(topics, t)
+		}
+		sort.Strings(topics)
+
+		for _, t := range topics {
+			for _, part := range sheds[from][t] {
+				assigned := p.plan[from][t]
+				idx := slices.Index(assigned, part)
+				if idx < 0 {
+					continue
+				}
+				var to string
+				toLoad := -1
+				for _, m := range subscribed[t] {
+					if m == from || slices.Contains(sheds[m][t], part) {
+						continue
+					}
+					if l := load(m); toLoad < 0 || l < toLoad {
+						to, toLoad = m, l
+					}
+				}
+				if to == "" {
+					continue
+				}
+				if assigned = slices.Delete(assigned, idx, idx+1); len(assigned) == 0 {
+					delete(p.plan[from], t)
+				} else {
+					p.plan[from][t] = assigned
+				}
+				if p.plan[to] == nil {
+					p.plan[to] = make(map[string][]int32)
+				}
+				p.plan[to][t] = append(p.plan[to][t], part)
+				p.swapStandby(t, part, from, to)
+				moved++
+			}
+		}
+	}
+	return moved
+}
@@ -0,0 +1,193 @@
+package kgo
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestPickShed(t *testing.T) {
+	assigned := map[string][]int32{
+		"a": {0, 1, 2, 3},
+		"b": {5, 4},
+		"c": {0},
+	}
+	for _, test := range []struct {
+		n        int
+		shedding map[string][]int32
+		exp      map[string][]int32
+	}{
+		{0, nil, nil},
+		{2, nil, map[string][]int32{"a": {3}, "b": {5}}},
+		{5, nil, map[string][]int32{"a": {3, 2}, "b": {5, 4}, "c": {0}}},
+		{100, nil, map[string][]int32{"a": {3, 2, 1, 0}, "b": {5, 4}, "c": {0}}},
+		{2, map[string][]int32{"a": {3}, "c": {0}}, map[string][]int32{"a": {2}, "b": {5}}},
+	} {
+		if got := pickShed(assigned, test.shedding, test.n); !reflect.DeepEqual(got, test.exp) {
+			t.Errorf("n=%d shedding=%v: got %v != exp %v", test.n, test.shedding, got, test.exp)
+		}
+	}
+}
+
+func TestShedTrailer(t *testing.T) {
+	shed := map[string][]int32{"b": {3, 1}, "a": {0}}
+	orig := CooperativeStickyBalancer().JoinGroupMetadata([]string{"a", "b"}, map[string][]int32{"a": {1}}, 3)
+
+	// The census is appended after the shed trailer and stripped first.
+	meta := appendCensus(appendShed(orig, shed), (&MemberCensus{Hostname: "h"}).appendTo(nil))
+	meta, census := splitCensus(meta)
+	if census == nil || census.Hostname != "h" {
+		t.Fatalf("census not found: %v", census)
+	}
+	stripped, got := splitShed(meta)
+	if exp := map[string][]int32{"a": {0}, "b": {1, 3}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got shed %v != exp %v", got, exp)
+	}
+	if !bytes.Equal(stripped, orig) {
+		t.Error("stripped metadata does not match original")
+	}
+	if stripped, got := splitShed(orig); got != nil || !bytes.Equal(stripped, orig) {
+		t.Error("unexpectedly split shed from metadata without a shed trailer")
+	}
+}
+
+func TestApplySheds(t *testing.T) {
+	member := func(id string, topics ...string) kmsg.JoinGroupResponseMember {
+		meta := kmsg.NewConsumerMemberMetadata()
+		meta.Version = 3
+		meta.Topics = topics
+		return kmsg.JoinGroupResponseMember{MemberID: id, ProtocolMetadata: meta.AppendTo(nil)}
+	}
+	b, err := NewConsumerBalancer(nil, []kmsg.JoinGroupResponseMember{
+		member("m1", "a", "b"),
+		member("m2", "a"),
+		member("m3", "a", "b"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &BalancePlan{plan: map[string]map[string][]int32{
+		"m1": {"a": {0, 1}, "b": {0, 1}},
+		"m2": {"a": {2}},
+		"m3": {"a": {3, 4, 5}},
+	}}
+	sheds := map[string]map[string][]int32{
+		"m1": {"a": {1}, "b": {0, 1}, "c": {0}},
+	}
+	if moved := p.applySheds(b, sheds); moved != 3 {
+		t.Errorf("got %d moved != exp 3", moved)
+	}
+	// a1 goes to the least loaded member, m2. Only m3 consumes b, and
+	// c is not consumed by anybody.
+	exp := map[string]map[string][]int32{
+		"m1": {"a": {0}},
+		"m2": {"a": {2, 1}},
+		"m3": {"a": {3, 4, 5}, "b": {0, 1}},
+	}
+	if !reflect.DeepEqual(p.plan, exp) {
+		t.Errorf("got plan %v != exp %v", p.plan, exp)
+	}
+}
+
+func TestShedPartitions(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 4)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	type member struct {
+		cl      *Client
+		mu      sync.Mutex
+		revoked map[string][]int32
+	}
+	newMember := func() *member {
+		m := &member{revoked: make(map[string][]int32)}
+		m.cl, _ = newTestClient(
+			ConsumerGroup(group),
+			ConsumeTopics(topic),
+			Balancers(CooperativeStickyBalancer()),
+			OnPartitionsRevoked(func(_ context.Context, _ *Client, revoked map[string][]int32) {
+				m.mu.Lock()
+				defer m.mu.Unlock()
+				for t, ps := range revoked {
+					m.revoked[t] = append(m.revoked[t], ps...)
+				}
+			}),
+		)
+		return m
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	poll := func(m *member) {
+		for ctx.Err() == nil {
+			m.cl.PollFetches(ctx)
+		}
+	}
+	numAssigned := func(m *member) (n int) {
+		for _, ps := range m.cl.consumer.g.nowAssigned.read() {
+			n += len(ps)
+		}
+		return n
+	}
+
+	m1, m2 := newMember(), newMember()
+	defer m1.cl.Close()
+	defer m2.cl.Close()
+	go poll(m1)
+	go poll(m2)
+
+	wait(t, 20*time.Second, func() error {
+		if n1, n2 := numAssigned(m1), numAssigned(m2); n1 != 2 || n2 != 2 {
+			return fmt.Errorf("waiting for an even split, have %d and %d", n1, n2)
+		}
+		return nil
+	})
+
+	// Initially balancing revokes from whichever member joined first.
+	for _, m := range []*member{m1, m2} {
+		m.mu.Lock()
+		m.revoked = make(map[string][]int32)
+		m.mu.Unlock()
+	}
+
+	shed, err := m1.cl.ShedPartitions(1)
+	if err != nil {
+		t.Fatalf("unable to shed: %v", err)
+	}
+	if len(shed[topic]) != 1 {
+		t.Fatalf("got unexpected shed %v", shed)
+	}
+
+	wait(t, 20*time.Second, func() error {
+		if n1, n2 := numAssigned(m1), numAssigned(m2); n1 != 1 || n2 != 3 {
+			return fmt.Errorf("waiting for the shed partition to move, have %d and %d", n1, n2)
+		}
+		return nil
+	})
+	if got := m2.cl.consumer.g.nowAssigned.read()[topic]; !slices.Contains(got, shed[topic][0]) {
+		t.Errorf("shed partition %d not assigned to other member, which has %v", shed[topic][0], got)
+	}
+	m1.mu.Lock()
+	if !reflect.DeepEqual(m1.revoked, shed) {
+		t.Errorf("got revoked %v != exp shed %v", m1.revoked, shed)
+	}
+	m1.mu.Unlock()
+	m2.mu.Lock()
+	if len(m2.revoked) != 0 {
+		t.Errorf("other member unexpectedly revoked %v", m2.revoked)
+	}
+	m2.mu.Unlock()
+
+	if _, err := (&Client{}).ShedPartitions(1); err != ErrNotGroup {
+		t.Errorf("got err %v != exp ErrNotGroup", err)
+	}
+}
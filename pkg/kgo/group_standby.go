@@ -0,0 +1,207 @@
+package kgo
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// The standby balancer publishes standby partitions in the user data of each
+// member's consumer protocol assignment, prefixed with this magic so that a
+// member does not mistake other user data for standbys.
+const standbyMagic = "\x00kgo-standby"
+
+// appendStandby returns assignment user data containing standby partitions.
+func appendStandby(standby map[string][]int32) []byte {
+	return appendTopicPartitions([]byte(standbyMagic), standby)
+}
+
+// parseStandby returns the standby partitions in a consumer protocol member
+// assignment, or nil if the assignment has no standbys.
+func parseStandby(assignment []byte) map[string][]int32 {
+	var kassignment kmsg.ConsumerMemberAssignment
+	if err := kassignment.ReadFrom(assignment); err != nil {
+		return nil
+	}
+	data, ok := bytes.CutPrefix(kassignment.UserData, []byte(standbyMagic))
+	if !ok {
+		return nil
+	}
+	standby, ok := readTopicPartitions(data)
+	if !ok || len(standby) == 0 {
+		return nil
+	}
+	return standby
+}
+
+// mergeStandbyPreferred adds our standby partitions to the partitions we
+// prefer when joining, so that if the active member for a partition leaves,
+// the partition is promoted on a member that has been warming it.
+func mergeStandbyPreferred(preferred, standby map[string][]int32) map[string][]int32 {
+	if len(standby) == 0 {
+		return preferred
+	}
+	merged := make(map[string][]int32, len(preferred)+len(standby))
+	for t, ps := range preferred {
+		merged[t] = slices.Clone(ps)
+	}
+	for t, ps := range standby {
+		for _, p := range ps {
+			if !slices.Contains(merged[t], p) {
+				merged[t] = append(merged[t], p)
+			}
+		}
+	}
+	return merged
+}
+
+// StandbyAssignment returns the standby partitions the balancer assigned this
+// member when using the CooperativeStandbyBalancer, or nil if the client is
+// not in a group or has no standby partitions. The client does not consume
+// standby partitions; see OnStandbyAssignment.
+func (cl *Client) StandbyAssignment() map[string][]int32 {
+	g := cl.consumer.g
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.standby) == 0 {
+		return nil
+	}
+	standby := make(map[string][]int32, len(g.standby))
+	for t, ps := range g.standby {
+		standby[t] = slices.Clone(ps)
+	}
+	return standby
+}
+
+// CooperativeStandbyBalancer returns a cooperative sticky balancer that, in
+// addition to the active assignment, assigns every partition to up to
+// standbys other members as a standby, similar to how Kafka Streams assigns
+// standby tasks.
+//
+// Standby assignments are only a notification: the group client does not
+// consume standby partitions or commit offsets for them. Members are notified
+// of their standbys with OnStandbyAssignment and can query them with
+// StandbyAssignment, and it is up to the application to warm local state for
+// them, such as by consuming a changelog with a separate client. When the
+// active member for a partition leaves, the partition is promoted to active on
+// a member that had it as a standby: members advertise their standbys as
+// preferred partitions when rejoining, and this balancer honors preferences
+// the same way as the CooperativeAffinityStickyBalancer. Preferences from the
+// PreferredPartitions option are honored as well.
+//
+// Standbys are spread evenly across members subscribed to the partition's
+// topic, keep their current standby member when possible, and are never
+// assigned to the partition's active member. If fewer than standbys other
+// members consume a topic, partitions in that topic have fewer standbys.
+//
+// This balancer uses the protocol name "cooperative-standby-sticky" and is
+// only understood by kgo clients, so every member of the group must use it.
+func CooperativeStandbyBalancer(standbys int) GroupBalancer {
+	return &standbyBalancer{standbys: standbys}
+}
+
+type standbyBalancer struct {
+	affinityBalancer
+	standbys int
+}
+
+func (*standbyBalancer) ProtocolName() string { return "cooperative-standby-sticky" }
+
+func (s *standbyBalancer) MemberBalancer(members []kmsg.JoinGroupResponseMember) (GroupMemberBalancer, map[string]struct{}, error) {
+	b, err := NewConsumerBalancer(s, members)
+	return b, b.MemberTopics(), err
+}
+
+func (s *standbyBalancer) Balance(b *ConsumerBalancer, topics map[string]int32) IntoSyncAssignment {
+	p := s.affinityBalancer.Balance(b, topics).(*BalancePlan)
+	p.assignStandbys(b, topics, s.standbys)
+	return p
+}
+
+// assignStandbys assigns each partition to up to n members other than its
+// active member. Members that had the partition as a standby (and published
+// it as preferred when joining) are chosen first, followed by the members
+// with the fewest standbys so far.
+func (p *BalancePlan) assignStandbys(b *ConsumerBalancer, topics map[string]int32, n int) {
+	if n <= 0 {
+		return
+	}
+
+	subscribed := make(map[string][]string) // topic => members
+	b.EachMember(func(member *kmsg.JoinGroupResponseMember, meta *kmsg.ConsumerMemberMetadata) {
+		for _, t := range meta.Topics {
+			subscribed[t] = append(subscribed[t], member.MemberID)
+		}
+	})
+	active := make(map[string]map[int32]string) // topic => partition => member
+	for member, ts := range p.plan {
+		for t, ps := range ts {
+			if active[t] == nil {
+				active[t] = make(map[int32]string)
+			}
+			for _, part := range ps {
+				active[t][part] = member
+			}
+		}
+	}
+
+	sorted := make([]string, 0, len(topics))
+	for t := range topics {
+		sorted = append(sorted, t)
+	}
+	sort.Strings(sorted)
+
+	p.standby = make(map[string]map[string][]int32)
+	load := make(map[string]int)
+	for _, t := range sorted {
+		members := subscribed[t]
+		for part := int32(0); part < topics[t]; part++ {
+			candidates := make([]string, 0, len(members))
+			for _, m := range members {
+				if m != active[t][part] {
+					candidates = append(candidates, m)
+				}
+			}
+			warm := func(m string) bool { return slices.Contains(b.preferred[m][t], part) }
+			sort.Slice(candidates, func(i, j int) bool {
+				l, r := candidates[i], candidates[j]
+				if wl, wr := warm(l), warm(r); wl != wr {
+					return wl
+				}
+				if load[l] != load[r] {
+					return load[l] < load[r]
+				}
+				return l < r
+			})
+			for _, m := range candidates[:min(n, len(candidates))] {
+				if p.standby[m] == nil {
+					p.standby[m] = make(map[string][]int32)
+				}
+				p.standby[m][t] = append(p.standby[m][t], part)
+				load[m]++
+			}
+		}
+	}
+}
+
+// swapStandby is called when a partition moves from one member's active
+// assignment to another: if the new active member had the partition as a
+// standby, the old active member takes its place as the standby.
+func (p *BalancePlan) swapStandby(t string, part int32, from, to string) {
+	idx := slices.Index(p.standby[to][t], part)
+	if idx < 0 {
+		return
+	}
+	if p.standby[to][t] = slices.Delete(p.standby[to][t], idx, idx+1); len(p.standby[to][t]) == 0 {
+		delete(p.standby[to], t)
+	}
+	if p.standby[from] == nil {
+		p.standby[from] = make(map[string][]int32)
+	}
+	p.standby[from][t] = append(p.standby[from][t], part)
+}
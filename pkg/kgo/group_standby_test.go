@@ -0,0 +1,156 @@
+package kgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestAssignStandbys(t *testing.T) {
+	var jms []kmsg.JoinGroupResponseMember
+	for _, id := range []string{"a", "b", "c"} {
+		meta := CooperativeStandbyBalancer(1).JoinGroupMetadata([]string{"t"}, nil, -1)
+		jms = append(jms, kmsg.JoinGroupResponseMember{MemberID: id, ProtocolMetadata: meta})
+	}
+	b, err := NewConsumerBalancer(nil, jms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.preferred = map[string]map[string][]int32{"c": {"t": {0, 1, 2}}} // c was warming 0 through 2
+
+	p := &BalancePlan{plan: map[string]map[string][]int32{
+		"a": {"t": {0, 1}},
+		"b": {"t": {2, 3}},
+		"c": {"t": {4, 5}},
+	}}
+	p.assignStandbys(b, map[string]int32{"t": 6}, 1)
+
+	standbys := make(map[int32][]string)
+	for m, ts := range p.standby {
+		for _, part := range ts["t"] {
+			standbys[part] = append(standbys[part], m)
+		}
+	}
+	for part := int32(0); part < 6; part++ {
+		ms := standbys[part]
+		if len(ms) != 1 {
+			t.Fatalf("partition %d: got standbys %v, exp exactly one", part, ms)
+		}
+		if slices.Contains(p.plan[ms[0]]["t"], part) {
+			t.Errorf("partition %d: standby %s is also active", part, ms[0])
+		}
+		if part <= 2 && ms[0] != "c" {
+			t.Errorf("partition %d: got standby %s, exp warm member c", part, ms[0])
+		}
+	}
+
+	// Moving an active partition onto its standby swaps the two members.
+	p.swapStandby("t", 0, "a", "c")
+	if slices.Contains(p.standby["c"]["t"], 0) || !slices.Contains(p.standby["a"]["t"], 0) {
+		t.Errorf("swap did not move the standby: %v", p.standby)
+	}
+
+	// The standbys round trip through the sync assignment.
+	for _, assn := range p.IntoSyncAssignment() {
+		got := parseStandby(assn.MemberAssignment)
+		exp := p.standby[assn.MemberID]
+		for _, ps := range exp {
+			slices.Sort(ps)
+		}
+		if len(exp) == 0 {
+			exp = nil
+		}
+		if !reflect.DeepEqual(got, exp) {
+			t.Errorf("member %s: got standby %v != exp %v", assn.MemberID, got, exp)
+		}
+	}
+
+	// Without standbys, nothing is assigned and assignments have no user
+	// data.
+	p.standby = nil
+	p.assignStandbys(b, map[string]int32{"t": 6}, 0)
+	for _, assn := range p.IntoSyncAssignment() {
+		if parseStandby(assn.MemberAssignment) != nil {
+			t.Errorf("member %s: unexpected standbys", assn.MemberID)
+		}
+	}
+}
+
+func TestStandbyAssignment(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 4)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		standby = make(map[*Client]map[string][]int32)
+	)
+	newMember := func() *Client {
+		cl, _ := newTestClient(
+			ConsumerGroup(group),
+			ConsumeTopics(topic),
+			Balancers(CooperativeStandbyBalancer(1)),
+			OnStandbyAssignment(func(_ context.Context, cl *Client, s map[string][]int32) {
+				mu.Lock()
+				defer mu.Unlock()
+				standby[cl] = s
+			}),
+		)
+		go func() {
+			for ctx.Err() == nil {
+				cl.PollFetches(ctx)
+			}
+		}()
+		return cl
+	}
+	sorted := func(ps []int32) []int32 {
+		ps = slices.Clone(ps)
+		slices.Sort(ps)
+		return ps
+	}
+
+	m1 := newMember()
+	defer m1.Close()
+	m2 := newMember()
+	defer m2.Close()
+
+	// Each member's standbys are exactly the other member's active
+	// partitions, and the hook agrees with StandbyAssignment.
+	wait(t, 30*time.Second, func() error {
+		a1, a2 := sorted(m1.consumer.g.nowAssigned.read()[topic]), sorted(m2.consumer.g.nowAssigned.read()[topic])
+		s1, s2 := sorted(m1.StandbyAssignment()[topic]), sorted(m2.StandbyAssignment()[topic])
+		if len(a1) != 2 || len(a2) != 2 || !reflect.DeepEqual(a1, s2) || !reflect.DeepEqual(a2, s1) {
+			return fmt.Errorf("waiting for balanced standbys, have active %v %v, standby %v %v", a1, a2, s1, s2)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(sorted(standby[m1][topic]), s1) || !reflect.DeepEqual(sorted(standby[m2][topic]), s2) {
+			return fmt.Errorf("waiting for standby hooks, have %v", standby)
+		}
+		return nil
+	})
+
+	// When m1 leaves, m2 is promoted and has nothing left to warm.
+	m1.Close()
+	wait(t, 30*time.Second, func() error {
+		if a := m2.consumer.g.nowAssigned.read()[topic]; len(a) != 4 {
+			return fmt.Errorf("waiting for promotion, have %v", a)
+		}
+		if s := m2.StandbyAssignment(); s != nil {
+			return fmt.Errorf("waiting for standbys to be cleared, have %v", s)
+		}
+		return nil
+	})
+}
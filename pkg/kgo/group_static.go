@@ -0,0 +1,129 @@
+package kgo
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// StaticAssignBalancer returns a balancer whose assignment is decided by an
+// external source, such as an operator, rather than by balancing. Group
+// membership is still used for liveness and fencing: a member only consumes
+// what it is assigned while it is in the group, and a member that leaves or
+// is kicked stops consuming.
+//
+// When balancing, assign is called with every member's identity and the
+// topics it is subscribed to, as well as every topic and its partition count,
+// and returns the partitions each identity should consume. A member's
+// identity is its InstanceID if it has one, and its member ID otherwise;
+// because member IDs change every time a member rejoins, deterministic
+// placement requires every member to use InstanceID.
+//
+// The returned assignment is used as is, except:
+//
+//   - identities that are not in the group are ignored, meaning their
+//     partitions are not consumed until they join
+//   - topics an identity is not subscribed to, and partitions that do not
+//     exist, are ignored
+//   - partitions that are not returned are not consumed by anyone
+//
+// If the same partition is assigned to multiple identities, balancing fails
+// and the error is returned to every member of the group. If assign changes
+// what it returns for a running group, partitions move cooperatively: the
+// balancer is cooperative, so a moved partition is first revoked from its
+// current owner and then assigned to its new owner in a second rebalance.
+// Note that a rebalance only occurs when the group membership or
+// subscriptions change; you can use ForceRebalance on the leader to have a
+// new assignment take effect.
+//
+// This balancer uses the protocol name "cooperative-static-assign" and is only
+// understood by kgo clients, so every member of the group must use it.
+func StaticAssignBalancer(assign func(members map[string][]string, topics map[string]int32) map[string]map[string][]int32) GroupBalancer {
+	return &staticBalancer{assign}
+}
+
+// StaticAssignMapBalancer returns a StaticAssignBalancer that always assigns
+// the given identity => topic => partitions map. This can be used to drive
+// assignment from a configuration file.
+func StaticAssignMapBalancer(assignments map[string]map[string][]int32) GroupBalancer {
+	return StaticAssignBalancer(func(map[string][]string, map[string]int32) map[string]map[string][]int32 {
+		return assignments
+	})
+}
+
+type staticBalancer struct {
+	assign func(map[string][]string, map[string]int32) map[string]map[string][]int32
+}
+
+func (*staticBalancer) ProtocolName() string { return "cooperative-static-assign" }
+func (*staticBalancer) IsCooperative() bool  { return true }
+
+func (*staticBalancer) JoinGroupMetadata(interests []string, currentAssignment map[string][]int32, generation int32) []byte {
+	return (&stickyBalancer{cooperative: true}).JoinGroupMetadata(interests, currentAssignment, generation)
+}
+
+func (*staticBalancer) ParseSyncAssignment(assignment []byte) (map[string][]int32, error) {
+	return ParseConsumerSyncAssignment(assignment)
+}
+
+func (s *staticBalancer) MemberBalancer(members []kmsg.JoinGroupResponseMember) (GroupMemberBalancer, map[string]struct{}, error) {
+	b, err := NewConsumerBalancer(s, members)
+	return b, b.MemberTopics(), err
+}
+
+func (s *staticBalancer) Balance(b *ConsumerBalancer, topics map[string]int32) IntoSyncAssignment {
+	var (
+		byID      = make(map[string]*kmsg.JoinGroupResponseMember)
+		subscribe = make(map[string][]string)
+	)
+	b.EachMember(func(member *kmsg.JoinGroupResponseMember, meta *kmsg.ConsumerMemberMetadata) {
+		id := member.MemberID
+		if member.InstanceID != nil {
+			id = *member.InstanceID
+		}
+		byID[id] = member
+		subscribe[id] = meta.Topics
+	})
+
+	assignments := s.assign(subscribe, topics)
+	ids := make([]string, 0, len(assignments))
+	for id := range assignments {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	p := b.NewPlan()
+	owners := make(map[string]map[int32]string) // topic => partition => identity
+	for _, id := range ids {
+		member, ok := byID[id]
+		if !ok {
+			continue
+		}
+		for topic, partitions := range assignments[id] {
+			if _, ok := topics[topic]; !ok || !slices.Contains(subscribe[id], topic) {
+				continue
+			}
+			if owners[topic] == nil {
+				owners[topic] = make(map[int32]string)
+			}
+			for _, partition := range partitions {
+				if partition < 0 || partition >= topics[topic] {
+					continue
+				}
+				prior, owned := owners[topic][partition]
+				switch {
+				case !owned:
+					owners[topic][partition] = id
+					p.AddPartition(member, topic, partition)
+				case prior != id:
+					b.SetError(fmt.Errorf("static assignment assigns %s partition %d to both %s and %s", topic, partition, prior, id))
+					return nil
+				}
+			}
+		}
+	}
+	p.AdjustCooperative(b)
+	return p
+}
@@ -0,0 +1,151 @@
+package kgo
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestStaticAssignBalance(t *testing.T) {
+	member := func(id, instance string, owned map[string][]int32, topics ...string) kmsg.JoinGroupResponseMember {
+		gen := int32(-1)
+		if owned != nil {
+			gen = 1
+		}
+		m := kmsg.JoinGroupResponseMember{
+			MemberID:         id,
+			ProtocolMetadata: StaticAssignBalancer(nil).JoinGroupMetadata(topics, owned, gen),
+		}
+		if instance != "" {
+			m.InstanceID = &instance
+		}
+		return m
+	}
+	balance := func(assignments map[string]map[string][]int32, members ...kmsg.JoinGroupResponseMember) (map[string]map[string][]int32, error) {
+		gb := StaticAssignMapBalancer(assignments).(*staticBalancer)
+		b, err := NewConsumerBalancer(gb, members)
+		if err != nil {
+			t.Fatal(err)
+		}
+		into, err := b.BalanceOrError(map[string]int32{"a": 4, "b": 2})
+		if err != nil {
+			return nil, err
+		}
+		plan := into.(*BalancePlan).AsMemberIDMap()
+		for m, ts := range plan {
+			for t, ps := range ts {
+				slices.Sort(ps)
+				if len(ps) == 0 {
+					delete(ts, t)
+				}
+			}
+			if len(ts) == 0 {
+				delete(plan, m)
+			}
+		}
+		return plan, nil
+	}
+
+	got, err := balance(map[string]map[string][]int32{
+		"i1":     {"a": {0, 1, 1}, "b": {0}, "c": {0}}, // duplicate within one identity is fine; c does not exist
+		"i2":     {"a": {2, 9}, "b": {1}},              // 9 is out of range, i2 is not subscribed to b
+		"m3":     {"a": {3}},                           // member without an instance ID
+		"absent": {"b": {1}},
+	},
+		member("m1", "i1", nil, "a", "b"),
+		member("m2", "i2", nil, "a"),
+		member("m3", "", nil, "a"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]map[string][]int32{
+		"m1": {"a": {0, 1}, "b": {0}},
+		"m2": {"a": {2}},
+		"m3": {"a": {3}},
+	}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+
+	// Moving a partition is cooperative: the current owner must first
+	// revoke it, so nobody is assigned it this round.
+	got, err = balance(map[string]map[string][]int32{"i1": {"a": {0}}, "i2": {"a": {1}}},
+		member("m1", "i1", map[string][]int32{"a": {0, 1}}, "a"),
+		member("m2", "i2", nil, "a"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := map[string]map[string][]int32{"m1": {"a": {0}}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got cooperative %v != exp %v", got, exp)
+	}
+
+	if _, err := balance(map[string]map[string][]int32{"i1": {"a": {0}}, "i2": {"a": {0}}},
+		member("m1", "i1", nil, "a"),
+		member("m2", "i2", nil, "a"),
+	); err == nil {
+		t.Error("expected error assigning a partition to two identities")
+	}
+}
+
+func TestStaticAssignGroup(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 3)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// kfake does not support static membership, so identities here are
+	// member IDs: the first member (sorted) consumes 0 and 2, the second 1.
+	assign := func(members map[string][]string, _ map[string]int32) map[string]map[string][]int32 {
+		ids := slices.Sorted(maps.Keys(members))
+		if len(ids) != 2 {
+			return nil
+		}
+		return map[string]map[string][]int32{
+			ids[0]: {topic: {0, 2}},
+			ids[1]: {topic: {1}},
+		}
+	}
+	newMember := func() *Client {
+		cl, _ := newTestClient(
+			ConsumerGroup(group),
+			ConsumeTopics(topic),
+			Balancers(StaticAssignBalancer(assign)),
+		)
+		go func() {
+			for ctx.Err() == nil {
+				cl.PollFetches(ctx)
+			}
+		}()
+		return cl
+	}
+	first, second := newMember(), newMember()
+	defer first.Close()
+	defer second.Close()
+
+	wait(t, 20*time.Second, func() error {
+		lo, hi := first, second
+		id1, _ := first.GroupMetadata()
+		id2, _ := second.GroupMetadata()
+		if id1 > id2 {
+			lo, hi = second, first
+		}
+		a1 := slices.Sorted(slices.Values(lo.consumer.g.nowAssigned.read()[topic]))
+		a2 := hi.consumer.g.nowAssigned.read()[topic]
+		if !reflect.DeepEqual(a1, []int32{0, 2}) || !reflect.DeepEqual(a2, []int32{1}) {
+			return fmt.Errorf("waiting for static assignment, have %v and %v", a1, a2)
+		}
+		return nil
+	})
+}
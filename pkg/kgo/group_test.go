@@ -3,11 +3,17 @@ package kgo
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"reflect"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 // TestGroupETL tests:
@@ -241,3 +247,319 @@ func (c *testConsumer) etl(etlsBeforeQuit int) {
 		}
 	}
 }
+
+func TestCheckpointRestore(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 2)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient(RecordPartitioner(ManualPartitioner()))
+	defer producer.Close()
+	for i := range 10 {
+		r := &Record{Topic: topic, Partition: int32(i % 2), Value: []byte(strconv.Itoa(i))}
+		if err := producer.ProduceSync(ctx, r).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checkpoints := make(chan map[string]map[int32]EpochOffset, 1)
+	first, _ := newTestClient(
+		ConsumerGroup(group),
+		ConsumeTopics(topic),
+		OnPartitionsCheckpoint(func(_ context.Context, _ *Client, offsets map[string]map[int32]EpochOffset) {
+			checkpoints <- offsets
+		}),
+	)
+	for consumed := 0; consumed < 10; {
+		fs := first.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+		consumed += fs.NumRecords()
+	}
+	// The default revoke only commits what was polled before the final
+	// poll, so we commit everything ourselves before leaving.
+	if err := first.CommitUncommittedOffsets(ctx); err != nil {
+		t.Fatal(err)
+	}
+	first.Close()
+
+	var checkpoint map[string]map[int32]EpochOffset
+	select {
+	case checkpoint = <-checkpoints:
+	default:
+		t.Fatal("checkpoint not called when leaving the group")
+	}
+	for p := range int32(2) {
+		if o := checkpoint[topic][p].Offset; o != 5 {
+			t.Errorf("partition %d: got checkpoint offset %d != exp 5", p, o)
+		}
+	}
+
+	restores := make(chan map[string]map[int32]Offset, 1)
+	second, _ := newTestClient(
+		ConsumerGroup(group),
+		ConsumeTopics(topic),
+		OnPartitionsRestore(func(_ context.Context, _ *Client, offsets map[string]map[int32]Offset) error {
+			restores <- offsets
+			return nil
+		}),
+	)
+	defer second.Close()
+	go second.PollFetches(ctx)
+
+	select {
+	case restore := <-restores:
+		got := make(map[string]map[int32]EpochOffset)
+		for t, ps := range restore {
+			got[t] = make(map[int32]EpochOffset)
+			for p, o := range ps {
+				got[t][p] = o.EpochOffset()
+			}
+		}
+		if !reflect.DeepEqual(got, checkpoint) {
+			t.Errorf("got restore offsets %v != checkpointed %v", got, checkpoint)
+		}
+	case <-ctx.Done():
+		t.Fatal("restore not called after assignment")
+	}
+}
+
+func TestCheckpointCommittedZero(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 1)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// Offset 0 at epoch 0 is a real commit and must be checkpointed as
+	// such, rather than as nothing committed.
+	cl, _ := newTestClient()
+	defer cl.Close()
+	req := kmsg.NewPtrOffsetCommitRequest()
+	req.Group = group
+	req.Generation = -1
+	rt := kmsg.NewOffsetCommitRequestTopic()
+	rt.Topic = topic
+	rp := kmsg.NewOffsetCommitRequestTopicPartition()
+	rp.Partition = 0
+	rp.Offset = 0
+	rp.LeaderEpoch = 0
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+	resp, err := req.RequestWith(ctx, cl)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.Topics[0].Partitions[0].ErrorCode)
+	}
+	if err != nil {
+		t.Fatalf("unable to commit: %v", err)
+	}
+
+	assigned := make(chan struct{})
+	checkpoints := make(chan map[string]map[int32]EpochOffset, 1)
+	consumer, _ := newTestClient(
+		ConsumerGroup(group),
+		ConsumeTopics(topic),
+		OnPartitionsAssigned(func(context.Context, *Client, map[string][]int32) { close(assigned) }),
+		OnPartitionsCheckpoint(func(_ context.Context, _ *Client, offsets map[string]map[int32]EpochOffset) {
+			checkpoints <- offsets
+		}),
+	)
+	go consumer.PollFetches(ctx)
+	select {
+	case <-assigned:
+	case <-ctx.Done():
+		t.Fatal("partitions not assigned")
+	}
+	// Committed offsets are fetched after the assignment callback, so we
+	// wait until they are loaded.
+	wait(t, 10*time.Second, func() error {
+		if _, ok := consumer.CommittedOffsets()[topic][0]; !ok {
+			return errors.New("committed offset not yet fetched")
+		}
+		return nil
+	})
+	consumer.Close()
+
+	select {
+	case checkpoint := <-checkpoints:
+		if got, exp := checkpoint[topic][0], (EpochOffset{0, 0}); got != exp {
+			t.Errorf("got checkpoint %v != exp %v", got, exp)
+		}
+	default:
+		t.Fatal("checkpoint not called when leaving the group")
+	}
+}
+
+type offsetCommitHook struct {
+	mu      sync.Mutex
+	commits []map[string]map[int32]int64
+}
+
+func (h *offsetCommitHook) OnOffsetCommit(req *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error) {
+	if err != nil || resp == nil {
+		return
+	}
+	committed := make(map[string]map[int32]int64)
+	for i, t := range req.Topics {
+		for j, p := range t.Partitions {
+			if resp.Topics[i].Partitions[j].ErrorCode != 0 {
+				continue
+			}
+			if committed[t.Topic] == nil {
+				committed[t.Topic] = make(map[int32]int64)
+			}
+			committed[t.Topic][p.Partition] = p.Offset
+		}
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.commits = append(h.commits, committed)
+}
+
+func TestHookOffsetCommit(t *testing.T) {
+	t.Parallel()
+
+	topic, topicCleanup := tmpTopicPartitions(t, 1)
+	defer topicCleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient(DefaultProduceTopic(topic))
+	defer producer.Close()
+	for i := range 3 {
+		if err := producer.ProduceSync(ctx, StringRecord(strconv.Itoa(i))).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hook := new(offsetCommitHook)
+	cl, _ := newTestClient(
+		ConsumerGroup(group),
+		ConsumeTopics(topic),
+		DisableAutoCommit(),
+		WithHooks(hook),
+	)
+	defer cl.Close()
+
+	var rs []*Record
+	for len(rs) < 3 {
+		fs := cl.PollFetches(ctx)
+		if err := ctx.Err(); err != nil {
+			t.Fatal(err)
+		}
+		rs = append(rs, fs.Records()...)
+	}
+
+	// Each commit API calls the hook with exactly what was committed.
+	if err := cl.CommitRecords(ctx, rs[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.CommitRecords(ctx, rs[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := cl.CommitUncommittedOffsets(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	exp := []map[string]map[int32]int64{
+		{topic: {0: 1}},
+		{topic: {0: 2}},
+		{topic: {0: 3}},
+	}
+	if !reflect.DeepEqual(hook.commits, exp) {
+		t.Errorf("got hooked commits %v != exp %v", hook.commits, exp)
+	}
+}
+
+func TestPositionCommittedBeginningEnd(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+	group, groupCleanup := tmpGroup(t)
+	defer groupCleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	producer, _ := newTestClient()
+	defer producer.Close()
+	for i := range 5 {
+		if err := producer.ProduceSync(ctx, &Record{Topic: topic, Value: []byte(strconv.Itoa(i))}).FirstErr(); err != nil {
+			t.Fatalf("unable to produce: %v", err)
+		}
+	}
+	if _, err := producer.Committed(ctx, nil); !errors.Is(err, ErrNotGroup) {
+		t.Errorf("got Committed err %v != exp ErrNotGroup", err)
+	}
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumerGroup(group),
+		DisableAutoCommit(),
+		FetchMaxWait(250*time.Millisecond),
+	)
+	defer cl.Close()
+
+	tp := TopicPartition{Topic: topic, Partition: 0}
+	var polled []*Record
+	for len(polled) == 0 {
+		polled = cl.PollRecords(ctx, 2).Records()
+		if err := ctx.Err(); err != nil {
+			t.Fatalf("unable to consume: %v", err)
+		}
+	}
+	last := polled[len(polled)-1]
+	pos, ok := cl.Position(tp)
+	if !ok || pos.Offset != last.Offset+1 || pos.Epoch != last.LeaderEpoch {
+		t.Errorf("got position %v (ok? %v) != exp offset %d epoch %d", pos, ok, last.Offset+1, last.LeaderEpoch)
+	}
+	if _, ok := cl.Position(TopicPartition{Topic: topic, Partition: 1}); ok {
+		t.Error("unexpectedly got a position for an unconsumed partition")
+	}
+
+	committed, err := cl.Committed(ctx, map[string][]int32{topic: {0}})
+	if err != nil {
+		t.Fatalf("unable to fetch committed: %v", err)
+	}
+	if len(committed) != 0 {
+		t.Errorf("got committed %v before committing, expected none", committed)
+	}
+	if err := cl.CommitRecords(ctx, polled...); err != nil {
+		t.Fatalf("unable to commit: %v", err)
+	}
+	committed, err = cl.Committed(ctx, nil)
+	if err != nil {
+		t.Fatalf("unable to fetch committed: %v", err)
+	}
+	if got, exp := committed[topic][0].Offset, last.Offset+1; got != exp {
+		t.Errorf("got committed offset %d != exp %d", got, exp)
+	}
+
+	beginning, end, err := cl.BeginningEndOffsets(ctx, map[string][]int32{topic: {0}})
+	if err != nil {
+		t.Fatalf("unable to list offsets: %v", err)
+	}
+	if got := beginning[topic][0]; got != 0 {
+		t.Errorf("got beginning offset %d != exp 0", got)
+	}
+	if got := end[topic][0]; got != 5 {
+		t.Errorf("got end offset %d != exp 5", got)
+	}
+}
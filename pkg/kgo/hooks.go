@@ -3,6 +3,8 @@ package kgo
 import (
 	"net"
 	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 ////////////////////////////////////////////////////////////////
@@ -61,6 +63,18 @@ type HookBrokerConnect interface {
 	OnBrokerConnect(meta BrokerMetadata, initDur time.Duration, conn net.Conn, err error)
 }
 
+// HookBrokerResolve is called after the client resolves a broker's hostname
+// before dialing. This hook is only called if the client is using its default
+// dialer; implementing this hook opts into the client resolving hostnames
+// itself rather than deferring to the net package's dialer.
+type HookBrokerResolve interface {
+	// OnBrokerResolve is passed the broker metadata, the hostname that was
+	// resolved, the resolved addresses in the order they will be dialed,
+	// whether the addresses were cached, how long resolving took, and any
+	// resolution error.
+	OnBrokerResolve(meta BrokerMetadata, host string, addrs []string, cached bool, resolveDur time.Duration, err error)
+}
+
 // HookBrokerDisconnect is called when a connection to a broker is closed.
 type HookBrokerDisconnect interface {
 	// OnBrokerDisconnect is passed the broker metadata and the connection
@@ -204,6 +218,133 @@ type HookGroupManageError interface {
 	OnGroupManageError(error)
 }
 
+// GroupCommitFenced describes an offset commit that the group coordinator
+// rejected because the member ID or generation the commit was issued with is
+// no longer current. This happens if the group rebalances while a commit is in
+// flight, or if the member was kicked from the group (for example, by taking
+// too long between polls).
+type GroupCommitFenced struct {
+	// Group is the group the commit was for.
+	Group string
+
+	// Err is the first fencing error in the commit response:
+	// kerr.IllegalGeneration, kerr.UnknownMemberID,
+	// kerr.FencedInstanceID, kerr.RebalanceInProgress,
+	// kerr.StaleMemberEpoch, or kerr.FencedMemberEpoch.
+	Err error
+
+	// CommitMemberID and CommitGeneration are the member ID and
+	// generation the commit was issued with.
+	CommitMemberID   string
+	CommitGeneration int32
+
+	// MemberID and Generation are the client's member ID and generation
+	// when the commit response was received. If these match the commit
+	// fields, the client has not yet noticed that it needs to rejoin.
+	MemberID   string
+	Generation int32
+
+	// RebalanceInProgress is whether the coordinator replied
+	// RebalanceInProgress or the client is currently rejoining the group.
+	RebalanceInProgress bool
+
+	// Partitions are the partitions whose commit was fenced.
+	Partitions map[string][]int32
+
+	// Retrying is whether the commit will be retried once the client has
+	// rejoined the group; see RetryFencedCommits.
+	Retrying bool
+
+	// Retry is whether the fenced commit was itself a retry of an
+	// earlier fenced commit. Retries are never retried again.
+	Retry bool
+}
+
+// HookGroupCommitFenced is called when an offset commit is rejected because
+// the member or generation it was issued with is no longer current.
+type HookGroupCommitFenced interface {
+	// OnGroupCommitFenced is passed details about the fenced commit. This
+	// can be used to diagnose why commits are failing: whether the member
+	// was kicked, raced with a rebalance, or was fenced by another static
+	// member with the same instance ID.
+	OnGroupCommitFenced(GroupCommitFenced)
+}
+
+// HookOffsetCommit is called after every OffsetCommit request the client
+// issues for its group, regardless of which API triggered the commit
+// (autocommitting, CommitRecords, CommitUncommittedOffsets, committing
+// before a rebalance, etc.). This can be used to audit exactly which offsets
+// were committed and when.
+//
+// Transactional offset commits (TxnOffsetCommit) are not OffsetCommit
+// requests and do not call this hook, nor do commits that are canceled before
+// being issued (for example, by a PreCommitFnContext function).
+type HookOffsetCommit interface {
+	// OnOffsetCommit is passed the request that was issued, and either
+	// the response or the error from issuing the request. Partitions in
+	// the response may have individual errors.
+	//
+	// The request and response must not be modified. If a commit is
+	// retried (for example, after refreshing a stale member epoch), the
+	// same request is reissued with an updated generation and this hook
+	// is called again.
+	OnOffsetCommit(req *kmsg.OffsetCommitRequest, resp *kmsg.OffsetCommitResponse, err error)
+}
+
+// TopicMetadataChange describes how the client's metadata for a topic changed
+// on a metadata update, for topics the client is producing to or consuming.
+type TopicMetadataChange struct {
+	// Topic is the topic that changed.
+	Topic string
+
+	// Consuming is true if this change is for the client's consumed
+	// topics, and false if it is for the client's produced topics. The
+	// client tracks metadata for producing and consuming separately; a
+	// topic that is both produced to and consumed has two changes.
+	Consuming bool
+
+	// Added is true if the topic was loaded for the first time, or if the
+	// topic previously had no partitions.
+	Added bool
+
+	// Removed is true if the topic is no longer produced or consumed
+	// (i.e., it was purged or a regex consumer dropped it), or if the
+	// topic now has no partitions.
+	Removed bool
+
+	// PrevPartitions and Partitions are the number of partitions before
+	// and after the metadata update.
+	PrevPartitions int
+	Partitions     int
+
+	// LeaderChanges contains all partitions, existing both before and
+	// after the update, whose leader or leader epoch changed.
+	LeaderChanges []PartitionLeaderChange
+}
+
+// PartitionLeaderChange is a change of a partition's leader or leader epoch.
+type PartitionLeaderChange struct {
+	Partition       int32
+	PrevLeader      int32
+	Leader          int32
+	PrevLeaderEpoch int32
+	LeaderEpoch     int32
+}
+
+// HookTopicMetadataChanged is called after a metadata update that changed
+// the partition count or partition leaders of any topic being produced to or
+// consumed, or that changed which topics are produced or consumed.
+//
+// This can be used to react to metadata changes (for example, to reset custom
+// partitioner state or to scale workers when partitions are added) without
+// polling metadata yourself.
+type HookTopicMetadataChanged interface {
+	// OnTopicMetadataChanged is passed every change from a metadata
+	// update, sorted by topic. This is called in the client's metadata
+	// loop and must not block.
+	OnTopicMetadataChanged([]TopicMetadataChange)
+}
+
 ///////////////////////////////
 // PRODUCE & CONSUME BATCHES //
 ///////////////////////////////
@@ -250,6 +391,136 @@ type HookProduceBatchWritten interface {
 	OnProduceBatchWritten(meta BrokerMetadata, topic string, partition int32, metrics ProduceBatchMetrics)
 }
 
+// HookProduceBatchAcked is called once per record batch when the batch is
+// finished, either successfully or with an error. This hook is called serially
+// with record promises, before the promises of the records in the batch.
+//
+// Applications that only need batch level acknowledgement (for example,
+// shipping a write ahead log) can use this hook and produce records with a
+// nil promise, avoiding per record promise overhead.
+//
+// Records that fail before being buffered into a batch (for example, if the
+// topic does not exist or the record is too large) are not part of any batch
+// and do not trigger this hook.
+type HookProduceBatchAcked interface {
+	// OnProduceBatchAcked is passed the topic and partition of a
+	// finished batch, the base offset the batch was written at (or -1 if
+	// unknown or on error), the number of records in the batch, and any
+	// error the batch was failed with.
+	OnProduceBatchAcked(topic string, partition int32, baseOffset int64, numRecords int, err error)
+}
+
+// ProducerIDRecoveryAction is the action the client took when recovering the
+// idempotent producer ID, as reported to HookProducerIDRecovery.
+type ProducerIDRecoveryAction int8
+
+const (
+	// ProducerIDResetSafe is a producer ID reset that risks no data: the
+	// broker forgot our producer ID because the partition's log start
+	// offset moved past our last produce (for example, from retention),
+	// so the client bumped its epoch and reset sequence numbers.
+	ProducerIDResetSafe ProducerIDRecoveryAction = iota
+
+	// ProducerIDResetDataRisk is a producer ID reset after an
+	// OutOfOrderSequenceNumber or UnknownProducerID error that was not
+	// explained by log truncation. Records may have been lost or may be
+	// duplicated. The client bumped its epoch and reset sequence numbers,
+	// continuing to produce because StopProducerOnDataLossDetected is not
+	// used.
+	ProducerIDResetDataRisk
+
+	// ProducerIDFailed is a fatal producer ID error: the client failed its
+	// producer ID and the batch, because the client is transactional or
+	// is using StopProducerOnDataLossDetected. A non-transactional
+	// producer can recover with ResetProducerID.
+	ProducerIDFailed
+
+	// ProducerIDResetManual is a producer ID reset requested with
+	// ResetProducerID.
+	ProducerIDResetManual
+
+	// ProducerIDRetryStaleEpoch is a batch that failed with a producer ID
+	// or epoch error for a producer ID or epoch that the client already
+	// replaced (for example, after another partition caused a reset). The
+	// broker did not write the batch, so the client retries it with the
+	// current producer ID and epoch; nothing is reset.
+	ProducerIDRetryStaleEpoch
+)
+
+func (a ProducerIDRecoveryAction) String() string {
+	switch a {
+	case ProducerIDResetSafe:
+		return "RESET_SAFE"
+	case ProducerIDResetDataRisk:
+		return "RESET_DATA_RISK"
+	case ProducerIDFailed:
+		return "FAILED"
+	case ProducerIDResetManual:
+		return "RESET_MANUAL"
+	case ProducerIDRetryStaleEpoch:
+		return "RETRY_STALE_EPOCH"
+	}
+	return "UNKNOWN"
+}
+
+// ProducerIDRecovery describes a producer ID recovery action.
+type ProducerIDRecovery struct {
+	// Action is what the client did.
+	Action ProducerIDRecoveryAction
+
+	// Topic and Partition are the partition whose produce response
+	// triggered this action. These are empty and -1 for
+	// ProducerIDResetManual.
+	Topic     string
+	Partition int32
+
+	// ProducerID and ProducerEpoch are the producer ID and epoch that
+	// were in use when the action was taken.
+	ProducerID    int64
+	ProducerEpoch int16
+
+	// Err is the error that triggered this action, if any.
+	Err error
+
+	// DuplicatesPossible is whether records may be written twice because
+	// of this action. A reset restarts sequence numbers under a new epoch,
+	// so the broker cannot deduplicate a retry of a batch that was already
+	// written under the old epoch. This is true for
+	// ProducerIDResetDataRisk and ProducerIDResetManual, where the client
+	// cannot rule that out, and false otherwise.
+	DuplicatesPossible bool
+}
+
+// HookProducerIDRecovery is called whenever the client resets or fails its
+// producer ID in response to an OutOfOrderSequenceNumber, UnknownProducerID,
+// InvalidProducerIDMapping, or InvalidProducerEpoch produce error, when it
+// retries a batch that failed with one of these errors for an already
+// replaced producer ID or epoch, or when ResetProducerID is called. This
+// allows operators to distinguish normal, safe recovery from resets that risk
+// data.
+type HookProducerIDRecovery interface {
+	// OnProducerIDRecovery is called with the action taken. This may be
+	// called while handling a produce response and must not block.
+	OnProducerIDRecovery(ProducerIDRecovery)
+}
+
+// HookProduceTimestampSkew is called whenever a batch is known to be
+// successfully produced to a topic that uses LogAppendTime timestamps. This
+// can be used to validate record timestamps (see RecordTimestamps) against
+// the broker's clock.
+type HookProduceTimestampSkew interface {
+	// OnProduceTimestampSkew is called per successful batch written to a
+	// LogAppendTime topic partition. The skew is the broker's log append
+	// time minus the batch's max record timestamp.
+	//
+	// A large positive skew means records sat in the client a long time
+	// before being written, or that the client clock is behind the
+	// broker's. A negative skew means the client clock is ahead of the
+	// broker's. This is called in the client's produce response handling
+	// and must not block.
+	OnProduceTimestampSkew(meta BrokerMetadata, topic string, partition int32, skew time.Duration)
+}
+
 // FetchBatchMetrics tracks information about fetches of batches.
 type FetchBatchMetrics struct {
 	// NumRecords is the number of records that were fetched in this batch.
@@ -303,6 +574,50 @@ type HookFetchBatchRead interface {
 	OnFetchBatchRead(meta BrokerMetadata, topic string, partition int32, metrics FetchBatchMetrics)
 }
 
+// FetchReadCommittedMetrics contains read committed diagnostics for a
+// partition in a fetch response.
+type FetchReadCommittedMetrics struct {
+	// FetchOffset is the offset the partition was fetched from.
+	FetchOffset int64
+
+	// HighWatermark is the partition's high watermark.
+	HighWatermark int64
+
+	// LastStableOffset is the partition's last stable offset. Read
+	// committed consumers cannot consume at or past this offset.
+	LastStableOffset int64
+
+	// AbortedTransactions is the number of aborted transactions the
+	// broker returned for this partition.
+	AbortedTransactions int
+
+	// AbortedRecords is the number of records that were skipped because
+	// they were part of an aborted transaction. This does not include
+	// transaction markers.
+	AbortedRecords int
+
+	// NumRecords is the number of records returned from the fetch for
+	// this partition.
+	NumRecords int
+}
+
+// BlockedByOpenTransaction returns whether consuming is blocked by an open
+// transaction: the fetch offset is at the last stable offset, which is below
+// the high watermark. If this persists, a producer has a long running or
+// hanging transaction (see kadm's FindHangingTransactions).
+func (m FetchReadCommittedMetrics) BlockedByOpenTransaction() bool {
+	return m.FetchOffset >= m.LastStableOffset && m.LastStableOffset < m.HighWatermark
+}
+
+// HookFetchReadCommitted is called for every partition in a successful fetch
+// response when consuming with the ReadCommitted isolation level. This hook
+// can be used to debug read committed consumers that appear stuck below the
+// high watermark.
+type HookFetchReadCommitted interface {
+	// OnFetchReadCommitted is called per partition per fetch response.
+	OnFetchReadCommitted(meta BrokerMetadata, topic string, partition int32, metrics FetchReadCommittedMetrics)
+}
+
 ///////////////////////////////
 // PRODUCE & CONSUME RECORDS //
 ///////////////////////////////
@@ -361,6 +676,20 @@ type HookProduceRecordUnbuffered interface {
 	OnProduceRecordUnbuffered(*Record, error)
 }
 
+// HookProduceFlushProgress is called while Flush waits for buffered records
+// to be produced, allowing broker shutdowns to be coordinated with client
+// flushes. See BufferedProduceRecordsByBroker and SetBrokersShuttingDown.
+type HookProduceFlushProgress interface {
+	// OnProduceFlushProgress is called when Flush begins, every second
+	// while Flush waits, and when Flush returns, with the number of
+	// records still buffered for each broker by node ID. Brokers with no
+	// buffered records are not included, meaning the map is empty once
+	// everything has been flushed.
+	//
+	// If Flush is called concurrently, this is called for each Flush.
+	OnProduceFlushProgress(remaining map[int32]int64)
+}
+
 // HookFetchRecordBuffered is called when a record is internally buffered after
 // fetching, ready to be polled.
 //
@@ -394,6 +723,28 @@ type HookFetchRecordUnbuffered interface {
 	OnFetchRecordUnbuffered(r *Record, polled bool)
 }
 
+// HookFetchRecordEndToEnd is called when a record that has an end to end
+// latency header is polled, if the client is using [MeasureEndToEndLatency].
+//
+// This hook can be used to export produce to consume latency metrics, for
+// example to track an end to end latency SLO. See [RecordEndToEndTimestamps]
+// for how records are timestamped.
+type HookFetchRecordEndToEnd interface {
+	// OnFetchRecordEndToEnd is passed a polled record and the duration
+	// between when the record was passed to Produce and when the record
+	// was polled.
+	OnFetchRecordEndToEnd(r *Record, latency time.Duration)
+}
+
+// HookFetchErrorSuppressed is called for every transient fetch error that is
+// stripped from a poll if the client is using [SuppressTransientFetchErrors].
+type HookFetchErrorSuppressed interface {
+	// OnFetchErrorSuppressed is passed the topic, partition, and error of
+	// a partition error that was stripped from a poll. This is called
+	// while polling and must not block.
+	OnFetchErrorSuppressed(topic string, partition int32, err error)
+}
+
 /////////////
 // HELPERS //
 /////////////
@@ -404,19 +755,33 @@ func implementsAnyHook(h Hook) bool {
 	case HookNewClient,
 		HookClientClosed,
 		HookBrokerConnect,
+		HookBrokerResolve,
 		HookBrokerDisconnect,
 		HookBrokerWrite,
 		HookBrokerRead,
 		HookBrokerE2E,
 		HookBrokerThrottle,
+		HookClusterHealthCheck,
+		HookClusterSwitch,
 		HookGroupManageError,
+		HookGroupCommitFenced,
+		HookOffsetCommit,
+		HookTopicMetadataChanged,
 		HookProduceBatchWritten,
+		HookProduceBatchVerified,
+		HookProduceTimestampSkew,
+		HookProduceBatchAcked,
+		HookProducerIDRecovery,
 		HookFetchBatchRead,
+		HookFetchReadCommitted,
 		HookProduceRecordBuffered,
 		HookProduceRecordPartitioned,
 		HookProduceRecordUnbuffered,
+		HookProduceFlushProgress,
 		HookFetchRecordBuffered,
-		HookFetchRecordUnbuffered:
+		HookFetchRecordUnbuffered,
+		HookFetchRecordEndToEnd,
+		HookFetchErrorSuppressed:
 		return true
 	}
 	return false
@@ -0,0 +1,126 @@
+package kgo
+
+import (
+	"sync"
+	"time"
+)
+
+// idleTopics tracks when records were last polled for regex matched topics,
+// for ConsumeRegexDropIdleTopics.
+type idleTopics struct {
+	mu      sync.Mutex
+	last    map[string]time.Time // topic => last time records were polled, or when first tracked
+	dropped map[string]struct{}  // topics we dropped and that still exist
+}
+
+// observe tracks that records were polled for any topic in fetches. We only
+// update topics we are already tracking; new topics are tracked on the next
+// metadata update.
+func (i *idleTopics) observe(fetches []Fetch) {
+	now := time.Now()
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, f := range fetches {
+		for _, t := range f.Topics {
+			if _, ok := i.last[t.Topic]; !ok {
+				continue
+			}
+			for _, p := range t.Partitions {
+				if len(p.Records) > 0 {
+					i.last[t.Topic] = now
+					break
+				}
+			}
+		}
+	}
+}
+
+// dropIdleRegexTopics, called in a metadata update when regex consuming,
+// purges topics that have had no records polled for regexDropIdle. Topics we
+// previously dropped that are now missing from metadata are forgotten, so that
+// a recreated topic can be matched again.
+func (c *consumer) dropIdleRegexTopics(consuming map[string]*topicPartitions, latest map[string]*metadataTopic) {
+	cl := c.cl
+
+	var idle, forget []string
+	now := time.Now()
+
+	c.idle.mu.Lock()
+	if c.idle.last == nil {
+		c.idle.last = make(map[string]time.Time)
+		c.idle.dropped = make(map[string]struct{})
+	}
+	for topic := range c.idle.last {
+		if _, ok := consuming[topic]; !ok {
+			delete(c.idle.last, topic) // purged some other way
+		}
+	}
+	for topic := range consuming {
+		last, ok := c.idle.last[topic]
+		switch {
+		case !ok:
+			c.idle.last[topic] = now
+		case now.Sub(last) >= cl.cfg.regexDropIdle:
+			delete(c.idle.last, topic)
+			c.idle.dropped[topic] = struct{}{}
+			idle = append(idle, topic)
+		}
+	}
+	for topic := range c.idle.dropped {
+		if _, ok := latest[topic]; !ok {
+			delete(c.idle.dropped, topic)
+			forget = append(forget, topic)
+		}
+	}
+	c.idle.mu.Unlock()
+
+	if len(forget) > 0 {
+		c.mu.Lock()
+		reSeen := c.reSeen()
+		for _, topic := range forget {
+			delete(reSeen, topic)
+		}
+		c.mu.Unlock()
+	}
+
+	if len(idle) == 0 {
+		return
+	}
+
+	cl.cfg.logger.Log(LogLevelInfo, "regex consumer dropping idle topics", "topics", idle, "idle", cl.cfg.regexDropIdle)
+
+	// Purging issues a blocking metadata fn, so we must purge in a
+	// goroutine: this waits for our current metadata update to finish.
+	// After purging, we mark the topics as not wanted so that the regex
+	// does not match them again on the next metadata update.
+	go func() {
+		cl.blockingMetadataFn(func() {
+			c.purgeTopics(idle)
+			c.mu.Lock()
+			if reSeen := c.reSeen(); reSeen != nil {
+				for _, topic := range idle {
+					reSeen[topic] = false
+				}
+			}
+			c.mu.Unlock()
+		})
+		if cl.ctx.Err() != nil {
+			return // closed before we purged
+		}
+		if fn := cl.cfg.regexOnDropIdle; fn != nil {
+			fn(cl, idle)
+		}
+	}()
+}
+
+// reSeen returns the regex evaluation map for the direct or group consumer,
+// or nil if we are not consuming. This must be called with c.mu held.
+func (c *consumer) reSeen() map[string]bool {
+	switch {
+	case c.d != nil:
+		return c.d.reSeen
+	case c.g != nil:
+		return c.g.reSeen
+	}
+	return nil
+}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // LogLevel designates which level the logger should log at.
@@ -99,30 +102,152 @@ func (b *basicLogger) Log(level LogLevel, msg string, keyvals ...any) {
 	b.dst.Write(buf.Bytes())
 }
 
-// nopLogger, the default logger, drops everything.
-type nopLogger struct{}
+// wrappedLogger wraps the config logger for convenience at logging callsites.
+// The inner logger can be swapped with Reconfigure; if there is no inner
+// logger, everything is dropped.
+type wrappedLogger struct {
+	inner atomic.Pointer[Logger]
+	dedup *logDedup // set once in NewClient, if deduplicating
+}
 
-func (*nopLogger) Level() LogLevel { return LogLevelNone }
-func (*nopLogger) Log(LogLevel, string, ...any) {
+func newWrappedLogger(l Logger) *wrappedLogger {
+	w := new(wrappedLogger)
+	w.inner.Store(&l)
+	return w
 }
 
-// wrappedLogger wraps the config logger for convenience at logging callsites.
-type wrappedLogger struct {
-	inner Logger
+func (w *wrappedLogger) load() Logger {
+	if l := w.inner.Load(); l != nil {
+		return *l
+	}
+	return nil
 }
 
 func (w *wrappedLogger) Level() LogLevel {
-	if w.inner == nil {
+	l := w.load()
+	if l == nil {
 		return LogLevelNone
 	}
-	return w.inner.Level()
+	return l.Level()
 }
 
 func (w *wrappedLogger) Log(level LogLevel, msg string, keyvals ...any) {
-	if w.Level() < level {
+	l := w.load()
+	if l == nil || l.Level() < level {
 		return
 	}
-	w.inner.Log(level, msg, keyvals...)
+	if w.dedup != nil {
+		for _, sum := range w.dedup.sweep() {
+			l.Log(sum.level, "suppressed repeated log messages",
+				"msg", sum.msg,
+				"broker", sum.broker,
+				"topic", sum.topic,
+				"suppressed", sum.suppressed,
+			)
+		}
+		if level <= LogLevelWarn {
+			ok, suppressed := w.dedup.allow(level, msg, keyvals)
+			if !ok {
+				return
+			}
+			if suppressed > 0 {
+				keyvals = append(keyvals[:len(keyvals):len(keyvals)], "suppressed", suppressed)
+			}
+		}
+	}
+	l.Log(level, msg, keyvals...)
+}
+
+// logDedup drops warnings and errors that repeat within an interval; see
+// LogDedupInterval.
+type logDedup struct {
+	interval  time.Duration
+	nextSweep atomic.Int64
+
+	mu   sync.Mutex
+	seen map[logDedupKey]*logDedupState
+}
+
+type logDedupKey struct {
+	level  LogLevel
+	msg    string
+	broker string
+	topic  string
+}
+
+type logDedupState struct {
+	start      time.Time
+	suppressed int
+}
+
+type logDedupSummary struct {
+	logDedupKey
+	suppressed int
+}
+
+func newLogDedup(interval time.Duration) *logDedup {
+	d := &logDedup{
+		interval: interval,
+		seen:     make(map[logDedupKey]*logDedupState),
+	}
+	d.nextSweep.Store(time.Now().Add(interval).UnixNano())
+	return d
+}
+
+// allow returns whether a log should be emitted, and if so, how many identical
+// logs were suppressed since it was last emitted.
+func (d *logDedup) allow(level LogLevel, msg string, keyvals []any) (bool, int) {
+	k := logDedupKey{level: level, msg: msg}
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		switch keyvals[i] {
+		case "broker":
+			k.broker = fmt.Sprint(keyvals[i+1])
+		case "topic":
+			k.topic = fmt.Sprint(keyvals[i+1])
+		}
+	}
+
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	st := d.seen[k]
+	if st == nil {
+		d.seen[k] = &logDedupState{start: now}
+		return true, 0
+	}
+	if now.Sub(st.start) < d.interval {
+		st.suppressed++
+		return false, 0
+	}
+	suppressed := st.suppressed
+	*st = logDedupState{start: now}
+	return true, suppressed
+}
+
+// sweep drops state for logs that have not been seen for an interval,
+// returning a summary for any that were suppressed in their final interval.
+// Without this, a flood of errors that stops would never report how many
+// logs were dropped at the end of the flood.
+func (d *logDedup) sweep() []logDedupSummary {
+	now := time.Now()
+	next := d.nextSweep.Load()
+	if now.UnixNano() < next || !d.nextSweep.CompareAndSwap(next, now.Add(d.interval).UnixNano()) {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var sums []logDedupSummary
+	for k, st := range d.seen {
+		if now.Sub(st.start) < d.interval {
+			continue
+		}
+		if st.suppressed > 0 {
+			sums = append(sums, logDedupSummary{k, st.suppressed})
+		}
+		delete(d.seen, k)
+	}
+	return sums
 }
 
 // LoggerFn returns an anonymous function that can be used in other packages
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 type metawait struct {
@@ -76,6 +77,99 @@ func (cl *Client) PartitionLeader(topic string, partition int32) (leader, leader
 	return p.leader, p.leaderEpoch, p.loadErr
 }
 
+// metadataCache caches topic metadata by topic ID for MetadataTopicIDs. Only
+// topics that loaded without any topic or partition error are cached.
+type metadataCache struct {
+	topics map[[16]byte]*metadataTopic
+	ids    map[string][16]byte
+}
+
+// update caches successfully loaded topics from latest, drops topics that
+// failed to load, and drops any topic that is no longer tracked.
+func (c *metadataCache) update(latest map[string]*metadataTopic, tracked []string) {
+	if c.topics == nil {
+		c.topics = make(map[[16]byte]*metadataTopic)
+		c.ids = make(map[string][16]byte)
+	}
+	for topic, mt := range latest {
+		if id, ok := c.ids[topic]; ok {
+			delete(c.topics, id)
+			delete(c.ids, topic)
+		}
+		if !mt.cacheable() {
+			continue
+		}
+		c.topics[mt.id] = mt
+		c.ids[topic] = mt.id
+	}
+	keep := make(map[string]struct{}, len(tracked))
+	for _, t := range tracked {
+		keep[t] = struct{}{}
+	}
+	for topic, id := range c.ids {
+		if _, ok := keep[topic]; !ok {
+			delete(c.topics, id)
+			delete(c.ids, topic)
+		}
+	}
+}
+
+// split returns the topics that need to be requested for an incremental
+// update of the refresh topics, and cached metadata for everything else. If
+// nothing would be requested, this returns all topics and nothing cached.
+func (c *metadataCache) split(topics []string, refresh map[string]struct{}) ([]string, map[string]*metadataTopic) {
+	var (
+		fetch  []string
+		cached = make(map[string]*metadataTopic)
+	)
+	for _, topic := range topics {
+		_, isRefresh := refresh[topic]
+		id, isCached := c.ids[topic]
+		if isRefresh || !isCached {
+			fetch = append(fetch, topic)
+			continue
+		}
+		cached[topic] = c.topics[id]
+	}
+	if len(fetch) == 0 {
+		return topics, nil
+	}
+	return fetch, cached
+}
+
+// cacheable returns whether a topic loaded successfully and can be cached.
+func (mt *metadataTopic) cacheable() bool {
+	if mt.loadErr != nil || mt.id == [16]byte{} {
+		return false
+	}
+	for i := range mt.partitions {
+		if mt.partitions[i].loadErr != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// supportsMetadataTopicIDs returns whether every broker supports requesting
+// metadata by topic ID. We require every broker to support it, since
+// metadata requests can be issued to any broker.
+func (cl *Client) supportsMetadataTopicIDs() bool {
+	if max, ok := cl.cfg.maxVersions.LookupMaxKeyVersion(int16(kmsg.Metadata)); !ok || max < 12 {
+		return false
+	}
+	cl.brokersMu.RLock()
+	defer cl.brokersMu.RUnlock()
+	if len(cl.brokers) == 0 {
+		return false
+	}
+	for _, b := range cl.brokers {
+		if v := b.loadVersions(); v == nil || v.maxVers[kmsg.Metadata] < 12 {
+			return false
+		}
+	}
+	return true
+}
+
 var noid2t = make(map[[16]byte]string)
 
 func (cl *Client) id2tMap() map[[16]byte]string {
@@ -96,7 +190,7 @@ func (cl *Client) waitmeta(ctx context.Context, wait time.Duration, why string)
 	now := time.Now()
 
 	cl.metawait.mu.Lock()
-	if now.Sub(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge {
+	if now.Sub(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge.load() {
 		cl.metawait.mu.Unlock()
 		return
 	}
@@ -115,7 +209,7 @@ func (cl *Client) waitmeta(ctx context.Context, wait time.Duration, why string)
 		defer cl.metawait.mu.Unlock()
 
 		for !quit {
-			if now.Sub(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge {
+			if now.Sub(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge.load() {
 				return
 			}
 			cl.metawait.c.Wait()
@@ -140,7 +234,7 @@ func (cl *Client) triggerUpdateMetadata(must bool, why string) bool {
 	if !must {
 		cl.metawait.mu.Lock()
 		defer cl.metawait.mu.Unlock()
-		if time.Since(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge {
+		if time.Since(cl.metawait.lastUpdate) < cl.cfg.metadataMinAge.load() {
 			return false
 		}
 	}
@@ -180,10 +274,18 @@ func (cl *Client) updateMetadataLoop() {
 	var consecutiveErrors int
 	var lastAt time.Time
 
-	ticker := time.NewTicker(cl.cfg.metadataMaxAge)
+	tickerAge := cl.cfg.metadataMaxAge.load()
+	ticker := time.NewTicker(tickerAge)
 	defer ticker.Stop()
 loop:
 	for {
+		// The max age can be changed with Reconfigure, which wakes
+		// us with a no-op blocking function to pick up the change.
+		if age := cl.cfg.metadataMaxAge.load(); age != tickerAge {
+			tickerAge = age
+			ticker.Reset(age)
+		}
+
 		var now bool
 		select {
 		case <-cl.ctx.Done():
@@ -200,11 +302,14 @@ loop:
 			continue loop
 		}
 
-		var nowTries int
+		var (
+			nowTries int
+			refresh  map[string]struct{} // topics to re-request if incrementally updating
+		)
 	start:
 		nowTries++
 		if !now {
-			if wait := cl.cfg.metadataMinAge - time.Since(lastAt); wait > 0 {
+			if wait := cl.cfg.metadataMinAge.load() - time.Since(lastAt); wait > 0 {
 				timer := time.NewTimer(wait)
 			prewait:
 				select {
@@ -226,12 +331,15 @@ loop:
 		// potential pile on now triggers.
 		time.Sleep(time.Until(lastAt.Add(10 * time.Millisecond)))
 
-		// Drain any refires that occurred during our waiting.
+		// Drain any refires that occurred during our waiting. A refire
+		// may be for any topic, so we cannot update incrementally.
 	out:
 		for {
 			select {
 			case <-cl.updateMetadataCh:
+				refresh = nil
 			case <-cl.updateMetadataNowCh:
+				refresh = nil
 			case fn := <-cl.blockingMetadataFnCh:
 				fn()
 			default:
@@ -239,7 +347,7 @@ loop:
 			}
 		}
 
-		retryWhy, err := cl.updateMetadata()
+		retryWhy, err := cl.updateMetadata(refresh)
 		lastAt = time.Now()
 		if retryWhy != nil || err != nil {
 			// If err is non-nil, the metadata request failed
@@ -253,11 +361,14 @@ loop:
 			// still fail we will fall into the slower update below
 			// which waits (default) 5s between tries.
 			if now && err == nil && nowTries < 8 {
-				wait := min(cl.cfg.metadataMinAge, 250*time.Millisecond)
+				wait := min(cl.cfg.metadataMinAge.load(), 250*time.Millisecond)
 				cl.cfg.logger.Log(LogLevelDebug, "immediate metadata update had inner errors, re-updating",
 					"errors", retryWhy.reason(""),
 					"update_after", wait,
 				)
+				if cl.cfg.metadataTopicIDs {
+					refresh = retryWhy.topics()
+				}
 				timer := time.NewTimer(wait)
 			quickbackoff:
 				select {
@@ -287,7 +398,7 @@ loop:
 		consecutiveErrors++
 		// We sleep a bit in case the max metadata age is very small;
 		// typically this sleep is inconsequential.
-		after := time.NewTimer(cl.cfg.retryBackoff(consecutiveErrors))
+		after := time.NewTimer(cl.cfg.backoff(backoffMetadata, consecutiveErrors))
 	backoff:
 		select {
 		case <-cl.ctx.Done():
@@ -309,7 +420,11 @@ var errMissingTopic = errors.New("topic_missing")
 // The producer and consumer use different topic maps and underlying
 // topicPartitionsData pointers, but we update those underlying pointers
 // equally.
-func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
+//
+// If refresh is non-empty, this only requests metadata for the refresh topics
+// and topics that are not cached, and uses cached metadata for everything
+// else; see MetadataTopicIDs.
+func (cl *Client) updateMetadata(refresh map[string]struct{}) (retryWhy multiUpdateWhy, err error) {
 	var (
 		tpsProducerLoad = cl.producer.topics.load()
 		tpsConsumer     *topicsPartitions
@@ -359,7 +474,7 @@ func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
 				unknownTopics = append(unknownTopics, unknown)
 			}
 			var err error
-			unknownCreateResp, err = cl.fetchTopicMetadata(false, unknownTopics)
+			unknownCreateResp, err = cl.fetchTopicMetadata(false, unknownTopics, nil)
 			if err != nil {
 				// We bump all produce topics even though we
 				// only explicitly requested unknown ones; this
@@ -374,7 +489,11 @@ func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
 		cl.producer.unknownTopicsMu.Unlock()
 	}
 
-	latest, err := cl.fetchTopicMetadata(all, reqTopics)
+	fetchTopics, cached := reqTopics, map[string]*metadataTopic(nil)
+	if !all && len(refresh) > 0 {
+		fetchTopics, cached = cl.metaCache.split(reqTopics, refresh)
+	}
+	latest, err := cl.fetchTopicMetadata(all, fetchTopics, cached)
 	if err != nil {
 		cl.bumpMetadataFailForTopics( // bump load failures for all topics
 			tpsProducerLoad,
@@ -382,6 +501,9 @@ func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
 		)
 		return nil, err
 	}
+	if !all && cl.cfg.metadataTopicIDs {
+		cl.metaCache.update(latest, reqTopics)
+	}
 	groupExternal.updateLatest(latest)
 
 	// If regex consuming AND we issued a metadata request to forcefully
@@ -445,6 +567,10 @@ func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
 			cl.cfg.logger.Log(LogLevelInfo, "regex consumer purging topics that were previously consumed because they are missing in a metadata response, we are assuming they are deleted", "topics", purgeTopics)
 			go cl.PurgeTopicsFromClient(purgeTopics...)
 		}
+
+		if cl.cfg.regexDropIdle > 0 {
+			c.dropIdleRegexTopics(tpsConsumerLoad, latest)
+		}
 	}
 
 	css := &consumerSessionStopper{cl: cl}
@@ -505,6 +631,8 @@ func (cl *Client) updateMetadata() (retryWhy multiUpdateWhy, err error) {
 		}
 	}
 
+	cl.notifyTopicMetadataChanged(tpsProducerLoad, tpsConsumerLoad)
+
 	return retryWhy, nil
 }
 
@@ -584,34 +712,52 @@ func (mp metadataPartition) newPartition(cl *Client, isProduce bool) *topicParti
 			partition:          mp.partition,
 			keepControl:        cl.cfg.keepControl,
 			cursorsIdx:         -1,
-			source:             mp.sns.source,
+			source:             mp.sns.sourceFor(mp.topic, mp.partition),
 			topicPartitionData: td,
 			cursorOffset: cursorOffset{
-				offset:            -1, // required to not consume until needed
-				lastConsumedEpoch: -1, // required sentinel
+				offset:             -1, // required to not consume until needed
+				lastConsumedEpoch:  -1, // required sentinel
+				lastConsumedOffset: -1,
 			},
 		}
+		p.cursor.watermarks.position.Store(-1)
 	}
 	return p
 }
 
 // fetchTopicMetadata fetches metadata for all reqTopics and returns new
-// topicPartitionsData for each topic.
-func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*metadataTopic, error) {
-	_, meta, err := cl.fetchMetadataForTopics(cl.ctx, all, reqTopics, nil)
+// topicPartitionsData for each topic. Cached topics are not requested and are
+// returned as is.
+func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string, cached map[string]*metadataTopic) (map[string]*metadataTopic, error) {
+	var ids map[string][16]byte
+	if !all && cl.cfg.metadataTopicIDs && cl.supportsMetadataTopicIDs() {
+		ids = cl.metaCache.ids
+	}
+	_, meta, err := cl.fetchMetadataForTopicIDs(cl.ctx, all, reqTopics, ids, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	topics := make(map[string]*metadataTopic, len(meta.Topics))
-	id2t := make(map[[16]byte]string, len(meta.Topics))
+	topics := make(map[string]*metadataTopic, len(meta.Topics)+len(cached))
+	id2t := make(map[[16]byte]string, len(meta.Topics)+len(cached))
 	defer cl.id2t.Store(id2t)
+	for topic, mt := range cached {
+		topics[topic] = mt
+		id2t[mt.id] = topic
+	}
 
 	// Even if metadata returns a leader epoch, we do not use it unless we
 	// can validate it per OffsetForLeaderEpoch. Some brokers may have an
 	// odd set of support.
 	useLeaderEpoch := cl.supportsOffsetForLeaderEpoch()
 
+	// If regex consuming, we skip processing topics we already know we
+	// do not want. We still track their IDs.
+	var unmatched map[string]struct{}
+	if all && cl.cfg.regex {
+		unmatched = cl.consumer.unmatchedRegexTopics()
+	}
+
 	for i := range meta.Topics {
 		topicMeta := &meta.Topics[i]
 		if topicMeta.Topic == nil {
@@ -621,6 +767,10 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 		topic := *topicMeta.Topic
 
 		id2t[topicMeta.TopicID] = topic
+		if _, skip := unmatched[topic]; skip {
+			delete(unmatched, topic)
+			continue
+		}
 
 		mt := &metadataTopic{
 			loadErr:    kerr.ErrorForCode(topicMeta.ErrorCode),
@@ -679,10 +829,7 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 			cl.sinksAndSourcesMu.Lock()
 			sns, exists := cl.sinksAndSources[mp.leader]
 			if !exists {
-				sns = sinkAndSource{
-					sink:   cl.newSink(mp.leader),
-					source: cl.newSource(mp.leader),
-				}
+				sns = cl.newSinkAndSource(mp.leader)
 				cl.sinksAndSources[mp.leader] = sns
 			}
 			for _, replica := range partMeta.Replicas {
@@ -690,10 +837,7 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 					continue
 				}
 				if _, exists = cl.sinksAndSources[replica]; !exists {
-					cl.sinksAndSources[replica] = sinkAndSource{
-						sink:   cl.newSink(replica),
-						source: cl.newSource(replica),
-					}
+					cl.sinksAndSources[replica] = cl.newSinkAndSource(replica)
 				}
 			}
 			cl.sinksAndSourcesMu.Unlock()
@@ -702,6 +846,12 @@ func (cl *Client) fetchTopicMetadata(all bool, reqTopics []string) (map[string]*
 		}
 	}
 
+	// Anything left in unmatched was deleted; we forget it so that the
+	// topic is evaluated again if it is recreated.
+	if len(unmatched) > 0 {
+		cl.consumer.forgetUnmatchedRegexTopics(unmatched)
+	}
+
 	return topics, nil
 }
 
@@ -939,6 +1089,20 @@ var (
 
 type multiUpdateWhy map[kerrOrString]map[string]map[int32]struct{}
 
+// topics returns every topic that had an error.
+func (m *multiUpdateWhy) topics() map[string]struct{} {
+	if m == nil || *m == nil {
+		return nil
+	}
+	topics := make(map[string]struct{})
+	for _, ts := range *m {
+		for t := range ts {
+			topics[t] = struct{}{}
+		}
+	}
+	return topics
+}
+
 type kerrOrString struct {
 	k *kerr.Error
 	s string
@@ -0,0 +1,109 @@
+package kgo
+
+import "sort"
+
+// topicMetaSnap is a snapshot of the leader and leader epoch of every
+// partition of every topic the client is producing to or consuming, used to
+// detect changes for HookTopicMetadataChanged.
+type topicMetaSnap map[string][]partLeader
+
+type partLeader struct {
+	leader      int32
+	leaderEpoch int32
+}
+
+func snapTopics(tps map[string]*topicPartitions) topicMetaSnap {
+	snap := make(topicMetaSnap, len(tps))
+	for topic, parts := range tps {
+		td := parts.load()
+		if len(td.partitions) == 0 {
+			continue
+		}
+		ls := make([]partLeader, len(td.partitions))
+		for i, p := range td.partitions {
+			ls[i] = partLeader{p.leader, p.leaderEpoch}
+		}
+		snap[topic] = ls
+	}
+	return snap
+}
+
+// notifyTopicMetadataChanged, called at the end of every metadata update,
+// diffs the current producer and consumer topics against what we saw last
+// update and calls any HookTopicMetadataChanged with the changes.
+func (cl *Client) notifyTopicMetadataChanged(tpsProducer, tpsConsumer map[string]*topicPartitions) {
+	var hooks []HookTopicMetadataChanged
+	cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookTopicMetadataChanged); ok {
+			hooks = append(hooks, h)
+		}
+	})
+	if len(hooks) == 0 {
+		return
+	}
+
+	var changes []TopicMetadataChange
+	for i, tps := range []map[string]*topicPartitions{tpsProducer, tpsConsumer} {
+		prior, now := cl.metaSnaps[i], snapTopics(tps)
+		cl.metaSnaps[i] = now
+		consuming := i == 1
+
+		for topic, ls := range now {
+			pls, exists := prior[topic]
+			if !exists {
+				changes = append(changes, TopicMetadataChange{
+					Topic:      topic,
+					Consuming:  consuming,
+					Added:      true,
+					Partitions: len(ls),
+				})
+				continue
+			}
+			var leaderChanges []PartitionLeaderChange
+			for p := 0; p < len(ls) && p < len(pls); p++ {
+				if ls[p] != pls[p] {
+					leaderChanges = append(leaderChanges, PartitionLeaderChange{
+						Partition:       int32(p),
+						PrevLeader:      pls[p].leader,
+						Leader:          ls[p].leader,
+						PrevLeaderEpoch: pls[p].leaderEpoch,
+						LeaderEpoch:     ls[p].leaderEpoch,
+					})
+				}
+			}
+			if len(ls) != len(pls) || len(leaderChanges) > 0 {
+				changes = append(changes, TopicMetadataChange{
+					Topic:          topic,
+					Consuming:      consuming,
+					PrevPartitions: len(pls),
+					Partitions:     len(ls),
+					LeaderChanges:  leaderChanges,
+				})
+			}
+		}
+		for topic, pls := range prior {
+			if _, exists := now[topic]; !exists {
+				changes = append(changes, TopicMetadataChange{
+					Topic:          topic,
+					Consuming:      consuming,
+					Removed:        true,
+					PrevPartitions: len(pls),
+				})
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		l, r := &changes[i], &changes[j]
+		if l.Topic != r.Topic {
+			return l.Topic < r.Topic
+		}
+		return !l.Consuming && r.Consuming
+	})
+	for _, h := range hooks {
+		h.OnTopicMetadataChanged(changes)
+	}
+}
@@ -0,0 +1,273 @@
+package kgo
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MetricsSnapshot is a point in time copy of metrics the client tracks
+// internally, returned from Client.Metrics. All counters are totals since the
+// client was created.
+type MetricsSnapshot struct {
+	// Brokers contains metrics per broker node ID. Seed brokers use
+	// negative node IDs, see BrokerMetadata.
+	Brokers map[int32]BrokerMetrics
+
+	// Topics contains produce and consume metrics per topic.
+	Topics map[string]TopicMetrics
+
+	// Retries is the number of times the client retried a request or
+	// re-sent a produce batch.
+	Retries int64
+
+	// BufferedProduceRecords is the number of records currently buffered
+	// for producing, as in Client.BufferedProduceRecords.
+	BufferedProduceRecords int64
+	// BufferedProduceBytes is the number of bytes currently buffered for
+	// producing, as in Client.BufferedProduceBytes.
+	BufferedProduceBytes int64
+	// BufferedFetchRecords is the number of records currently buffered
+	// from fetches, as in Client.BufferedFetchRecords.
+	BufferedFetchRecords int64
+	// BufferedFetchBytes is the number of bytes currently buffered from
+	// fetches, as in Client.BufferedFetchBytes.
+	BufferedFetchBytes int64
+
+	// Group is the consumer group state, if the client is group
+	// consuming.
+	Group GroupMetrics
+}
+
+// BrokerMetrics contains metrics for a single broker.
+type BrokerMetrics struct {
+	// BytesWritten is the number of bytes written to the broker,
+	// including request headers.
+	BytesWritten int64
+	// BytesRead is the number of bytes read from the broker, including
+	// response headers.
+	BytesRead int64
+
+	// Requests is the number of requests issued to the broker, and
+	// RequestErrors is the number of those that failed to be written or
+	// whose response failed to be read.
+	Requests      int64
+	RequestErrors int64
+
+	// RequestLatency is the total time spent writing requests and reading
+	// responses, from when a request was queued to be written until its
+	// response was fully read. Divide by Requests for an average.
+	RequestLatency time.Duration
+	// MaxRequestLatency is the largest request latency seen.
+	MaxRequestLatency time.Duration
+
+	// ProducedBytes and ProducedRecords are the compressed bytes and
+	// number of records in batches written to this broker, including
+	// batches that are retried.
+	ProducedBytes   int64
+	ProducedRecords int64
+	// ConsumedBytes and ConsumedRecords are the compressed bytes and
+	// number of records in batches consumed from this broker.
+	ConsumedBytes   int64
+	ConsumedRecords int64
+}
+
+// TopicMetrics contains produce and consume metrics for a single topic.
+type TopicMetrics struct {
+	// ProducedBytes, ProducedUncompressedBytes, ProducedRecords, and
+	// ProducedBatches describe batches written to brokers, including
+	// batches that are retried.
+	ProducedBytes             int64
+	ProducedUncompressedBytes int64
+	ProducedRecords           int64
+	ProducedBatches           int64
+
+	// ConsumedBytes, ConsumedUncompressedBytes, ConsumedRecords, and
+	// ConsumedBatches describe batches read from brokers.
+	ConsumedBytes             int64
+	ConsumedUncompressedBytes int64
+	ConsumedRecords           int64
+	ConsumedBatches           int64
+}
+
+// GroupMetrics contains the state of a consumer group member.
+type GroupMetrics struct {
+	// Group is the group being consumed, or empty if the client is not
+	// group consuming.
+	Group string
+	// MemberID and Generation are as in Client.GroupMetadata.
+	MemberID   string
+	Generation int32
+	// Assigned is the number of partitions currently assigned.
+	Assigned int
+}
+
+// Metrics returns a snapshot of metrics the client tracks internally. This
+// exists so that applications can export basic client metrics by polling,
+// without using a metrics plugin or hooks.
+func (cl *Client) Metrics() MetricsSnapshot {
+	s := MetricsSnapshot{
+		Brokers: make(map[int32]BrokerMetrics),
+		Topics:  make(map[string]TopicMetrics),
+
+		Retries: cl.stats.retries.Load(),
+
+		BufferedProduceRecords: cl.BufferedProduceRecords(),
+		BufferedProduceBytes:   cl.BufferedProduceBytes(),
+		BufferedFetchRecords:   cl.BufferedFetchRecords(),
+		BufferedFetchBytes:     cl.BufferedFetchBytes(),
+	}
+
+	cl.stats.mu.RLock()
+	for id, b := range cl.stats.brokers {
+		s.Brokers[id] = BrokerMetrics{
+			BytesWritten:      b.written.Load(),
+			BytesRead:         b.read.Load(),
+			Requests:          b.reqs.Load(),
+			RequestErrors:     b.reqErrs.Load(),
+			RequestLatency:    time.Duration(b.latency.Load()),
+			MaxRequestLatency: time.Duration(b.maxLatency.Load()),
+			ProducedBytes:     b.pBytes.Load(),
+			ProducedRecords:   b.pRecs.Load(),
+			ConsumedBytes:     b.cBytes.Load(),
+			ConsumedRecords:   b.cRecs.Load(),
+		}
+	}
+	for topic, t := range cl.stats.topics {
+		s.Topics[topic] = TopicMetrics{
+			ProducedBytes:             t.pBytes.Load(),
+			ProducedUncompressedBytes: t.pUncompressed.Load(),
+			ProducedRecords:           t.pRecs.Load(),
+			ProducedBatches:           t.pBatches.Load(),
+			ConsumedBytes:             t.cBytes.Load(),
+			ConsumedUncompressedBytes: t.cUncompressed.Load(),
+			ConsumedRecords:           t.cRecs.Load(),
+			ConsumedBatches:           t.cBatches.Load(),
+		}
+	}
+	cl.stats.mu.RUnlock()
+
+	if g := cl.consumer.g; g != nil {
+		s.Group.Group = g.cfg.group
+		s.Group.MemberID, s.Group.Generation = g.memberGen.load()
+		for _, ps := range g.nowAssigned.read() {
+			s.Group.Assigned += len(ps)
+		}
+	} else {
+		s.Group.Generation = -1
+	}
+	return s
+}
+
+// clientStats backs Client.Metrics. Per broker and per topic stats are
+// created on first use and never removed, which is fine given that topics
+// and brokers are also never removed from the client's metadata cache.
+type clientStats struct {
+	retries atomic.Int64
+
+	mu      sync.RWMutex
+	brokers map[int32]*brokerStats
+	topics  map[string]*topicStats
+}
+
+type brokerStats struct {
+	written    atomic.Int64
+	read       atomic.Int64
+	reqs       atomic.Int64
+	reqErrs    atomic.Int64
+	latency    atomic.Int64
+	maxLatency atomic.Int64
+	pBytes     atomic.Int64
+	pRecs      atomic.Int64
+	cBytes     atomic.Int64
+	cRecs      atomic.Int64
+}
+
+type topicStats struct {
+	pBytes        atomic.Int64
+	pUncompressed atomic.Int64
+	pRecs         atomic.Int64
+	pBatches      atomic.Int64
+	cBytes        atomic.Int64
+	cUncompressed atomic.Int64
+	cRecs         atomic.Int64
+	cBatches      atomic.Int64
+}
+
+func (s *clientStats) broker(id int32) *brokerStats {
+	s.mu.RLock()
+	b := s.brokers[id]
+	s.mu.RUnlock()
+	if b != nil {
+		return b
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b = s.brokers[id]; b == nil {
+		if s.brokers == nil {
+			s.brokers = make(map[int32]*brokerStats)
+		}
+		b = new(brokerStats)
+		s.brokers[id] = b
+	}
+	return b
+}
+
+func (s *clientStats) topic(topic string) *topicStats {
+	s.mu.RLock()
+	t := s.topics[topic]
+	s.mu.RUnlock()
+	if t != nil {
+		return t
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t = s.topics[topic]; t == nil {
+		if s.topics == nil {
+			s.topics = make(map[string]*topicStats)
+		}
+		t = new(topicStats)
+		s.topics[topic] = t
+	}
+	return t
+}
+
+func (s *clientStats) observeE2E(node int32, e2e BrokerE2E) {
+	b := s.broker(node)
+	b.written.Add(int64(e2e.BytesWritten))
+	b.read.Add(int64(e2e.BytesRead))
+	b.reqs.Add(1)
+	if e2e.Err() != nil {
+		b.reqErrs.Add(1)
+	}
+	latency := int64(e2e.DurationE2E())
+	b.latency.Add(latency)
+	for {
+		prior := b.maxLatency.Load()
+		if latency <= prior || b.maxLatency.CompareAndSwap(prior, latency) {
+			break
+		}
+	}
+}
+
+func (s *clientStats) observeProduced(node int32, topic string, m ProduceBatchMetrics) {
+	b := s.broker(node)
+	b.pBytes.Add(int64(m.CompressedBytes))
+	b.pRecs.Add(int64(m.NumRecords))
+	t := s.topic(topic)
+	t.pBytes.Add(int64(m.CompressedBytes))
+	t.pUncompressed.Add(int64(m.UncompressedBytes))
+	t.pRecs.Add(int64(m.NumRecords))
+	t.pBatches.Add(1)
+}
+
+func (s *clientStats) observeConsumed(node int32, topic string, m FetchBatchMetrics) {
+	b := s.broker(node)
+	b.cBytes.Add(int64(m.CompressedBytes))
+	b.cRecs.Add(int64(m.NumRecords))
+	t := s.topic(topic)
+	t.cBytes.Add(int64(m.CompressedBytes))
+	t.cUncompressed.Add(int64(m.UncompressedBytes))
+	t.cRecs.Add(int64(m.NumRecords))
+	t.cBatches.Add(1)
+}
@@ -0,0 +1,513 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ClusterRole is the role of a cluster in a MultiCluster.
+type ClusterRole int8
+
+const (
+	// ClusterPrimary is the cluster a MultiCluster uses while healthy.
+	ClusterPrimary ClusterRole = iota
+	// ClusterStandby is the cluster a MultiCluster fails over to.
+	ClusterStandby
+)
+
+func (r ClusterRole) String() string {
+	switch r {
+	case ClusterPrimary:
+		return "primary"
+	case ClusterStandby:
+		return "standby"
+	default:
+		return fmt.Sprintf("ClusterRole(%d)", int8(r))
+	}
+}
+
+// HookClusterHealthCheck is called after every health check of a cluster in a
+// MultiCluster. The hook must be in the primary cluster's options.
+type HookClusterHealthCheck interface {
+	// OnClusterHealthCheck is passed the role of the cluster that was
+	// checked, how long the check took, and the error if the cluster was
+	// unhealthy.
+	OnClusterHealthCheck(role ClusterRole, dur time.Duration, err error)
+}
+
+// HookClusterSwitch is called when a MultiCluster switches which cluster
+// traffic is routed to. The hook must be in the primary cluster's options.
+type HookClusterSwitch interface {
+	// OnClusterSwitch is passed the role of the cluster that traffic was
+	// routed to, the role that traffic is now routed to, and why the switch
+	// happened.
+	OnClusterSwitch(from, to ClusterRole, why error)
+}
+
+// OffsetTranslator translates offsets consumed on the primary cluster to
+// offsets to resume consuming from on the standby cluster, for use in
+// MultiClusterFailoverConsuming. Offsets are the next offsets to consume in
+// each partition. Translating offsets usually requires data replicated by
+// your mirroring solution (i.e., MirrorMaker 2 checkpoints).
+type OffsetTranslator func(ctx context.Context, standby *Client, consumed map[string]map[int32]EpochOffset) (map[string]map[int32]EpochOffset, error)
+
+// MultiClusterOpt is an option to configure a MultiCluster.
+type MultiClusterOpt interface {
+	apply(*multiClusterCfg)
+}
+
+type multiClusterOpt struct{ fn func(*multiClusterCfg) }
+
+func (o multiClusterOpt) apply(cfg *multiClusterCfg) { o.fn(cfg) }
+
+type multiClusterCfg struct {
+	healthInterval time.Duration
+	failoverAfter  time.Duration
+	failbackAfter  time.Duration
+	translate      OffsetTranslator
+}
+
+// MultiClusterHealthInterval sets how often the primary and standby clusters
+// are health checked, overriding the default of 5s. A cluster is healthy if it
+// replies to a Ping within the interval.
+func MultiClusterHealthInterval(interval time.Duration) MultiClusterOpt {
+	return multiClusterOpt{func(cfg *multiClusterCfg) { cfg.healthInterval = interval }}
+}
+
+// MultiClusterFailoverAfter sets how long the primary cluster must be
+// continuously unhealthy before traffic fails over to the standby, overriding
+// the default of 30s. Traffic does not fail over if the standby is also
+// unhealthy.
+func MultiClusterFailoverAfter(after time.Duration) MultiClusterOpt {
+	return multiClusterOpt{func(cfg *multiClusterCfg) { cfg.failoverAfter = after }}
+}
+
+// MultiClusterFailbackAfter sets how long the primary cluster must be
+// continuously healthy after a failover before produce traffic fails back to
+// the primary, overriding the default of 5m. Using a long duration avoids
+// flapping between clusters. A non-positive duration disables failing back
+// automatically; you can always fail back manually with Failback.
+//
+// Consuming never fails back automatically: offsets consumed on the standby
+// cannot be translated back to the primary in general.
+func MultiClusterFailbackAfter(after time.Duration) MultiClusterOpt {
+	return multiClusterOpt{func(cfg *multiClusterCfg) { cfg.failbackAfter = after }}
+}
+
+// MultiClusterFailoverConsuming opts into failing over consuming in addition
+// to producing. When failing over, the offsets last polled from the primary
+// are translated with fn, and then a consumer for the standby cluster is
+// started from the translated offsets. Partitions that fn does not return an
+// offset for are consumed from the standby client's ConsumeResetOffset.
+//
+// If the standby client is a group consumer, the translated offsets are
+// committed to the standby cluster for the group before the standby consumer
+// joins the group; the group must be empty on the standby cluster. Otherwise,
+// the translated offsets are consumed directly with ConsumePartitions: only
+// the translated partitions of a translated topic are consumed.
+func MultiClusterFailoverConsuming(fn OffsetTranslator) MultiClusterOpt {
+	return multiClusterOpt{func(cfg *multiClusterCfg) { cfg.translate = fn }}
+}
+
+// MultiCluster routes producing and consuming to a primary cluster, and fails
+// over to a standby cluster if the primary is unhealthy for a sustained amount
+// of time.
+//
+// Producing always fails over. Records that are buffered in the primary
+// client when failing over are aborted and reproduced to the standby
+// cluster. Consuming only fails over if using MultiClusterFailoverConsuming.
+//
+// All consumer and group options in the standby options are only used when
+// consuming fails over; until then, the standby client only produces.
+type MultiCluster struct {
+	cfg multiClusterCfg
+
+	primary     *Client
+	standby     *Client
+	standbyOpts []Opt
+
+	active atomicI32 // ClusterRole
+
+	consumeMu       sync.Mutex
+	consumer        *Client // primary until consuming fails over
+	standbyConsumer *Client
+	positions       map[string]map[int32]EpochOffset
+
+	switchMu sync.Mutex
+
+	ctx    context.Context
+	cancel func()
+	done   chan struct{}
+}
+
+// NewMultiCluster returns a new MultiCluster that uses a client created with
+// primaryOpts until the primary cluster is unhealthy, at which point traffic
+// fails over to a client created with standbyOpts.
+func NewMultiCluster(primaryOpts, standbyOpts []Opt, opts ...MultiClusterOpt) (*MultiCluster, error) {
+	mc := &MultiCluster{
+		cfg: multiClusterCfg{
+			healthInterval: 5 * time.Second,
+			failoverAfter:  30 * time.Second,
+			failbackAfter:  5 * time.Minute,
+		},
+		standbyOpts: standbyOpts,
+		positions:   make(map[string]map[int32]EpochOffset),
+		done:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt.apply(&mc.cfg)
+	}
+	if mc.cfg.healthInterval <= 0 {
+		return nil, errors.New("invalid non-positive MultiClusterHealthInterval")
+	}
+
+	var err error
+	if mc.primary, err = NewClient(primaryOpts...); err != nil {
+		return nil, fmt.Errorf("unable to create primary client: %w", err)
+	}
+	var produceOpts []Opt
+	for _, opt := range standbyOpts {
+		switch opt.(type) {
+		case consumerOpt, dynConsumerOpt, groupOpt:
+		default:
+			produceOpts = append(produceOpts, opt)
+		}
+	}
+	if mc.standby, err = NewClient(produceOpts...); err != nil {
+		mc.primary.Close()
+		return nil, fmt.Errorf("unable to create standby client: %w", err)
+	}
+	mc.consumer = mc.primary
+
+	mc.ctx, mc.cancel = context.WithCancel(context.Background())
+	go mc.monitor()
+	return mc, nil
+}
+
+// Primary returns the client for the primary cluster.
+func (mc *MultiCluster) Primary() *Client { return mc.primary }
+
+// Standby returns the client for the standby cluster. This client does not
+// consume; see MultiClusterFailoverConsuming.
+func (mc *MultiCluster) Standby() *Client { return mc.standby }
+
+// Active returns the cluster that produce traffic is currently routed to.
+func (mc *MultiCluster) Active() ClusterRole { return ClusterRole(mc.active.Load()) }
+
+func (mc *MultiCluster) activeClient() (*Client, ClusterRole) {
+	if role := mc.Active(); role == ClusterStandby {
+		return mc.standby, role
+	}
+	return mc.primary, ClusterPrimary
+}
+
+// Produce produces a record to the active cluster. If the record fails on
+// the primary cluster after traffic fails over to the standby, the record is
+// reproduced to the standby and the promise is called with the standby's
+// result. See Client.Produce for more details.
+func (mc *MultiCluster) Produce(ctx context.Context, r *Record, promise func(*Record, error)) {
+	cl, role := mc.activeClient()
+	if role == ClusterStandby {
+		cl.Produce(ctx, r, promise)
+		return
+	}
+	cl.Produce(ctx, r, func(r *Record, err error) {
+		if err != nil && mc.Active() == ClusterStandby && r.Context.Err() == nil {
+			mc.standby.Produce(r.Context, r, promise)
+			return
+		}
+		if promise != nil {
+			promise(r, err)
+		}
+	})
+}
+
+// ProduceSync produces all records to the active cluster and waits for them
+// to be produced. See Produce for details on failover.
+func (mc *MultiCluster) ProduceSync(ctx context.Context, rs ...*Record) ProduceResults {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(ProduceResults, 0, len(rs))
+		promise = func(r *Record, err error) {
+			mu.Lock()
+			results = append(results, ProduceResult{r, err})
+			mu.Unlock()
+			wg.Done()
+		}
+	)
+	wg.Add(len(rs))
+	for _, r := range rs {
+		mc.Produce(ctx, r, promise)
+	}
+	wg.Wait()
+	return results
+}
+
+// Flush flushes both the primary and standby clients. See Client.Flush.
+func (mc *MultiCluster) Flush(ctx context.Context) error {
+	var wg sync.WaitGroup
+	var perr, serr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		perr = mc.primary.Flush(ctx)
+	}()
+	serr = mc.standby.Flush(ctx)
+	wg.Wait()
+	return errors.Join(perr, serr)
+}
+
+// PollFetches polls the cluster that consuming is currently routed to. See
+// Client.PollFetches.
+func (mc *MultiCluster) PollFetches(ctx context.Context) Fetches {
+	return mc.PollRecords(ctx, 0)
+}
+
+// PollRecords polls the cluster that consuming is currently routed to. See
+// Client.PollRecords.
+func (mc *MultiCluster) PollRecords(ctx context.Context, maxPollRecords int) Fetches {
+	mc.consumeMu.Lock()
+	cl := mc.consumer
+	track := cl == mc.primary && mc.cfg.translate != nil
+	mc.consumeMu.Unlock()
+
+	fs := cl.PollRecords(ctx, maxPollRecords)
+	if track {
+		mc.consumeMu.Lock()
+		defer mc.consumeMu.Unlock()
+		if mc.consumer == mc.primary { // consuming may have failed over while polling
+			fs.EachRecord(func(r *Record) {
+				ps := mc.positions[r.Topic]
+				if ps == nil {
+					ps = make(map[int32]EpochOffset)
+					mc.positions[r.Topic] = ps
+				}
+				ps[r.Partition] = EpochOffset{r.LeaderEpoch, r.Offset + 1}
+			})
+		}
+	}
+	return fs
+}
+
+// Failover forcefully fails over to the standby cluster.
+func (mc *MultiCluster) Failover(why error) {
+	mc.switchTo(ClusterStandby, why)
+}
+
+// Failback forcefully fails produce traffic back to the primary cluster.
+// Consuming remains on the standby cluster if it failed over.
+func (mc *MultiCluster) Failback(why error) {
+	mc.switchTo(ClusterPrimary, why)
+}
+
+// Close stops health checking and closes all clients.
+func (mc *MultiCluster) Close() {
+	mc.cancel()
+	<-mc.done
+	mc.consumeMu.Lock()
+	standbyConsumer := mc.standbyConsumer
+	mc.consumeMu.Unlock()
+	if standbyConsumer != nil {
+		standbyConsumer.Close()
+	}
+	mc.standby.Close()
+	mc.primary.Close()
+}
+
+func (mc *MultiCluster) switchTo(to ClusterRole, why error) {
+	mc.switchMu.Lock()
+	defer mc.switchMu.Unlock()
+
+	from := mc.Active()
+	if from == to {
+		return
+	}
+	mc.active.Store(int32(to))
+	mc.primary.cfg.logger.Log(LogLevelWarn, "multi cluster switching clusters", "from", from, "to", to, "why", why)
+	mc.primary.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookClusterSwitch); ok {
+			h.OnClusterSwitch(from, to, why)
+		}
+	})
+
+	if to == ClusterStandby {
+		// Anything buffered in the primary is likely stuck; we fail
+		// it so that our Produce promise reproduces it to the standby.
+		go func() {
+			if err := mc.primary.AbortBufferedRecords(mc.ctx); err != nil {
+				mc.primary.cfg.logger.Log(LogLevelWarn, "multi cluster unable to abort records buffered in the primary while failing over", "err", err)
+			}
+		}()
+		if mc.cfg.translate != nil {
+			if err := mc.failoverConsuming(); err != nil {
+				mc.primary.cfg.logger.Log(LogLevelError, "multi cluster unable to fail over consuming; continuing to consume from the primary", "err", err)
+			}
+		}
+	}
+}
+
+// failoverConsuming translates the primary's consumed positions and starts a
+// consumer on the standby.
+func (mc *MultiCluster) failoverConsuming() error {
+	mc.consumeMu.Lock()
+	if mc.standbyConsumer != nil {
+		mc.consumeMu.Unlock()
+		return nil
+	}
+	positions := mc.positions
+	mc.positions = make(map[string]map[int32]EpochOffset)
+	mc.consumeMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(mc.ctx, mc.cfg.failoverAfter+mc.cfg.healthInterval)
+	defer cancel()
+	translated, err := mc.cfg.translate(ctx, mc.standby, positions)
+	if err != nil {
+		return fmt.Errorf("unable to translate offsets: %w", err)
+	}
+
+	opts := mc.standbyOpts
+	probe := defaultCfg()
+	for _, opt := range opts {
+		opt.apply(&probe)
+	}
+	switch {
+	case probe.group != "":
+		if err := commitTranslated(ctx, mc.standby, probe.group, translated); err != nil {
+			return err
+		}
+	case probe.regex:
+		mc.primary.cfg.logger.Log(LogLevelWarn, "multi cluster cannot consume translated offsets directly when consuming regex topics; consuming from the standby's reset offset")
+	case len(translated) > 0:
+		// Direct consuming cannot consume a topic both as a topic and
+		// at specific partitions: we consume translated topics by
+		// partition and everything else as before.
+		var topics []string
+		for topic := range probe.topics {
+			if _, exists := translated[topic]; !exists {
+				topics = append(topics, topic)
+			}
+		}
+		partitions := make(map[string]map[int32]Offset, len(translated))
+		for t, ps := range translated {
+			partitions[t] = make(map[int32]Offset, len(ps))
+			for p, eo := range ps {
+				partitions[t][p] = NewOffset().At(eo.Offset).WithEpoch(eo.Epoch)
+			}
+		}
+		opts = append(opts[:len(opts):len(opts)], ConsumeTopics(topics...), ConsumePartitions(partitions))
+	}
+
+	standbyConsumer, err := NewClient(opts...)
+	if err != nil {
+		return fmt.Errorf("unable to create standby consumer: %w", err)
+	}
+	mc.primary.PauseFetchTopics(mc.primary.GetConsumeTopics()...)
+
+	mc.consumeMu.Lock()
+	mc.standbyConsumer = standbyConsumer
+	mc.consumer = standbyConsumer
+	mc.consumeMu.Unlock()
+	return nil
+}
+
+// commitTranslated commits translated offsets for an empty group.
+func commitTranslated(ctx context.Context, cl *Client, group string, translated map[string]map[int32]EpochOffset) error {
+	if len(translated) == 0 {
+		return nil
+	}
+	req := kmsg.NewPtrOffsetCommitRequest()
+	req.Group = group
+	req.Generation = -1
+	for t, ps := range translated {
+		rt := kmsg.NewOffsetCommitRequestTopic()
+		rt.Topic = t
+		for p, eo := range ps {
+			rp := kmsg.NewOffsetCommitRequestTopicPartition()
+			rp.Partition = p
+			rp.Offset = eo.Offset
+			rp.LeaderEpoch = eo.Epoch
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		req.Topics = append(req.Topics, rt)
+	}
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return fmt.Errorf("unable to commit translated offsets: %w", err)
+	}
+	for _, t := range resp.Topics {
+		for _, p := range t.Partitions {
+			if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+				return fmt.Errorf("unable to commit translated offset for %s[%d]: %w", t.Topic, p.Partition, err)
+			}
+		}
+	}
+	return nil
+}
+
+// monitor health checks the clusters and switches between them with
+// hysteresis: the primary must be unhealthy for failoverAfter to fail over,
+// and healthy for failbackAfter to fail back.
+func (mc *MultiCluster) monitor() {
+	defer close(mc.done)
+
+	ticker := time.NewTicker(mc.cfg.healthInterval)
+	defer ticker.Stop()
+
+	var unhealthySince, healthySince time.Time
+	for {
+		select {
+		case <-mc.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := time.Now()
+		if err := mc.check(ClusterPrimary); err != nil {
+			healthySince = time.Time{}
+			if unhealthySince.IsZero() {
+				unhealthySince = now
+			}
+			if mc.Active() == ClusterPrimary && now.Sub(unhealthySince) >= mc.cfg.failoverAfter {
+				if serr := mc.check(ClusterStandby); serr != nil {
+					mc.primary.cfg.logger.Log(LogLevelWarn, "multi cluster primary is unhealthy, but not failing over because the standby is also unhealthy", "primary_err", err, "standby_err", serr)
+					continue
+				}
+				mc.Failover(fmt.Errorf("primary unhealthy for %v: %w", now.Sub(unhealthySince), err))
+			}
+			continue
+		}
+
+		unhealthySince = time.Time{}
+		if healthySince.IsZero() {
+			healthySince = now
+		}
+		if mc.Active() == ClusterStandby && mc.cfg.failbackAfter > 0 && now.Sub(healthySince) >= mc.cfg.failbackAfter {
+			mc.Failback(fmt.Errorf("primary healthy for %v", now.Sub(healthySince)))
+		}
+	}
+}
+
+func (mc *MultiCluster) check(role ClusterRole) error {
+	cl := mc.primary
+	if role == ClusterStandby {
+		cl = mc.standby
+	}
+	ctx, cancel := context.WithTimeout(mc.ctx, mc.cfg.healthInterval)
+	defer cancel()
+	start := time.Now()
+	err := cl.Ping(ctx)
+	dur := time.Since(start)
+	mc.primary.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookClusterHealthCheck); ok {
+			h.OnClusterHealthCheck(role, dur, err)
+		}
+	})
+	return err
+}
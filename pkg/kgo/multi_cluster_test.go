@@ -0,0 +1,105 @@
+package kgo
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type clusterSwitchHook struct{ switched atomic.Int32 }
+
+func (h *clusterSwitchHook) OnClusterSwitch(from, to ClusterRole, _ error) {
+	if from == ClusterPrimary && to == ClusterStandby {
+		h.switched.Add(1)
+	}
+}
+
+func TestMultiClusterFailover(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	// Seed the topic so that a failed over consumer has something to
+	// consume from a translated offset.
+	{
+		cl, _ := newTestClient()
+		defer cl.Close()
+		for i := range 5 {
+			if err := cl.ProduceSync(context.Background(), &Record{Topic: topic, Value: []byte{byte(i)}}).FirstErr(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	hook := new(clusterSwitchHook)
+	mc, err := NewMultiCluster(
+		[]Opt{SeedBrokers("127.0.0.1:1"), ConsumeTopics(topic), WithHooks(hook)},
+		testClientOpts(ConsumeTopics(topic)),
+		MultiClusterHealthInterval(50*time.Millisecond),
+		MultiClusterFailoverAfter(200*time.Millisecond),
+		MultiClusterFailoverConsuming(func(context.Context, *Client, map[string]map[int32]EpochOffset) (map[string]map[int32]EpochOffset, error) {
+			return map[string]map[int32]EpochOffset{topic: {0: {-1, 2}}}, nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+
+	// Our produce is stuck on the unreachable primary until we fail over,
+	// at which point it is reproduced to the standby.
+	r, err := mc.ProduceSync(ctx, &Record{Topic: topic, Value: []byte{5}}).First()
+	if err != nil {
+		t.Fatalf("unable to produce through failover: %v", err)
+	}
+	if r.Offset != 5 {
+		t.Errorf("got produced offset %d != exp 5", r.Offset)
+	}
+	if mc.Active() != ClusterStandby || hook.switched.Load() != 1 {
+		t.Errorf("expected one failover to the standby, active is %s and switched %d times", mc.Active(), hook.switched.Load())
+	}
+
+	var offsets []int64
+	for len(offsets) < 4 && ctx.Err() == nil {
+		mc.PollFetches(ctx).EachRecord(func(r *Record) { offsets = append(offsets, r.Offset) })
+	}
+	if len(offsets) == 0 || offsets[0] != 2 {
+		t.Errorf("expected to consume from translated offset 2, got offsets %v", offsets)
+	}
+}
+
+func TestMultiClusterStandbyOpts(t *testing.T) {
+	t.Parallel()
+
+	// The standby does not consume, so consumer options, including the
+	// dynamic fetch options, must not be applied to it.
+	mc, err := NewMultiCluster(
+		[]Opt{SeedBrokers("127.0.0.1:1")},
+		[]Opt{
+			SeedBrokers("127.0.0.1:2"),
+			ConsumeTopics("foo"),
+			FetchMaxWait(time.Second),
+			FetchMaxBytes(1 << 20),
+			FetchMaxPartitionBytes(1 << 10),
+			ProducerLinger(time.Second),
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mc.Close()
+
+	cfg := &mc.Standby().cfg
+	if len(cfg.topics) != 0 || cfg.maxWait.load() != 5000 || cfg.maxBytes.load() != 50<<20 || cfg.maxPartBytes.load() != 1<<20 {
+		t.Errorf("standby got consumer options: topics %v, max wait %d, max bytes %d, max partition bytes %d",
+			cfg.topics, cfg.maxWait.load(), cfg.maxBytes.load(), cfg.maxPartBytes.load())
+	}
+	if cfg.linger.load() != time.Second {
+		t.Errorf("standby got linger %v != exp 1s", cfg.linger.load())
+	}
+}
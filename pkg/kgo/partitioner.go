@@ -72,6 +72,18 @@ type TopicBackupIter interface {
 	Rem() int
 }
 
+// TopicBackupLatencyIter is an optional extension interface to
+// TopicBackupIter. The iterator the client passes to PartitionByBackup
+// implements this interface.
+type TopicBackupLatencyIter interface {
+	TopicBackupIter
+	// NextWithLatency is Next, but also returns a moving average of
+	// recent produce request latency for the partition. The latency is 0
+	// if nothing has been produced to the partition yet. Next and
+	// NextWithLatency advance the same iterator.
+	NextWithLatency() (int, int64, time.Duration)
+}
+
 ////////////
 // SIMPLE // - BasicConsistent, Manual, RoundRobin
 ////////////
@@ -204,6 +216,13 @@ func (i *leastBackupInput) Next() (int, int64) {
 	return last, buffered
 }
 
+func (i *leastBackupInput) NextWithLatency() (int, int64, time.Duration) {
+	last := len(i.mapping) - 1
+	latency := i.mapping[last].records.loadLatency(time.Now())
+	pick, buffered := i.Next()
+	return pick, buffered, latency
+}
+
 func (i *leastBackupInput) Rem() int {
 	return len(i.mapping)
 }
@@ -240,6 +259,140 @@ func (p *leastBackupTopicPartitioner) PartitionByBackup(_ *Record, n int, backup
 	return p.onPart
 }
 
+//////////////////
+// LEAST LOADED //
+//////////////////
+
+// LeastLoadedPartitioner partitions keyless records to the least loaded
+// partition, where load is the number of records buffered in the client for a
+// partition. If latency is true, the load of a partition is additionally
+// multiplied by the recent produce latency to the partition, which roughly
+// estimates how long a new record would wait before being produced. This
+// steers records away from partitions whose leader is slow, improving tail
+// latency at the cost of uneven partitioning. Failed and timed out produce
+// requests count towards latency, and latency decays by half every ten
+// seconds that nothing is produced to a partition, so that partitions that
+// were slow are tried again.
+//
+// If weighted is false, this picks the least loaded partition, choosing
+// randomly between equally loaded partitions. If weighted is true, this
+// picks a random partition weighted by the inverse of each partition's load,
+// which spreads records more evenly while still favoring less loaded
+// partitions.
+//
+// Like the LeastBackupPartitioner, this sticks to a partition until a new
+// batch is created, so that batches remain large. Records with non-nil keys
+// are always hashed with hasher so that keyed records are consistently
+// partitioned. hasher is optional; if nil, this uses Kafka's default murmur2
+// hasher.
+func LeastLoadedPartitioner(weighted, latency bool, hasher PartitionerHasher) Partitioner {
+	if hasher == nil {
+		hasher = KafkaHasher(murmur2)
+	}
+	return &leastLoadedPartitioner{weighted, latency, hasher}
+}
+
+type (
+	leastLoadedPartitioner struct {
+		weighted bool
+		latency  bool
+		hasher   PartitionerHasher
+	}
+
+	leastLoadedTopicPartitioner struct {
+		l      leastLoadedPartitioner
+		onPart int
+		rng    *rand.Rand
+
+		calc []struct {
+			f float64
+			n int
+		}
+	}
+)
+
+func (l *leastLoadedPartitioner) ForTopic(string) TopicPartitioner {
+	return &leastLoadedTopicPartitioner{
+		l:      *l,
+		onPart: -1,
+		rng:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+func (p *leastLoadedTopicPartitioner) OnNewBatch()                      { p.onPart = -1 }
+func (*leastLoadedTopicPartitioner) RequiresConsistency(r *Record) bool { return r.Key != nil }
+func (*leastLoadedTopicPartitioner) Partition(*Record, int) int         { panic("unreachable") }
+
+func (p *leastLoadedTopicPartitioner) PartitionByBackup(r *Record, n int, backup TopicBackupIter) int {
+	if r.Key != nil {
+		return p.l.hasher(r.Key, n)
+	}
+	if p.onPart >= 0 && p.onPart < n {
+		return p.onPart
+	}
+
+	// Our load is the number of records buffered, plus one so that an
+	// empty partition with a slow leader still has load. If we are
+	// considering latency, we multiply by the latency in milliseconds,
+	// with a minimum of one millisecond.
+	latencyIter, _ := backup.(TopicBackupLatencyIter)
+	next := func() (int, float64) {
+		if !p.l.latency || latencyIter == nil {
+			pick, buffered := backup.Next()
+			return pick, float64(buffered + 1)
+		}
+		pick, buffered, latency := latencyIter.NextWithLatency()
+		ms := max(float64(latency)/float64(time.Millisecond), 1)
+		return pick, float64(buffered+1) * ms
+	}
+
+	if !p.l.weighted {
+		least := math.Inf(1)
+		npicked := 0
+		for ; n > 0; n-- {
+			pick, load := next()
+			if load < least {
+				least = load
+				p.onPart = pick
+				npicked = 1
+			} else if load == least {
+				npicked++ // reservoir sampling with k = 1
+				if p.rng.Intn(npicked) == 0 {
+					p.onPart = pick
+				}
+			}
+		}
+		return p.onPart
+	}
+
+	// Weighted: same as the adaptive UniformBytesPartitioner, we pick
+	// according to the inverse of each partition's load, falling back to
+	// the last partition in case of floating point rounding.
+	p.calc = p.calc[:0]
+	var t float64
+	for ; n > 0; n-- {
+		pick, load := next()
+		f := 1 / load
+		t += f
+		p.calc = append(p.calc, struct {
+			f float64
+			n int
+		}{f, pick})
+	}
+	pick := p.rng.Float64() * t
+	for _, c := range p.calc {
+		pick -= c.f
+		if pick <= 0 {
+			p.onPart = c.n
+			break
+		}
+	}
+	if p.onPart == -1 {
+		p.onPart = p.calc[len(p.calc)-1].n
+	}
+	return p.onPart
+}
+
 ///////////////////
 // UNIFORM BYTES //
 ///////////////////
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"hash/crc32"
 	"math/rand"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -13,6 +16,7 @@ import (
 	"time"
 
 	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kerr"
 	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
@@ -150,8 +154,8 @@ func TestIssue769(t *testing.T) {
 		case <-timer.C:
 			t.Fatal("expected record to fail within 3s")
 		}
-		if pe := (*errProducerIDLoadFail)(nil); !errors.As(rerr, &pe) || !(errors.Is(pe.err, context.Canceled) || strings.Contains(pe.err.Error(), "canceled")) {
-			t.Errorf("got %v != exp errProducerIDLoadFail{context.Canceled}", rerr)
+		if pe := (*ErrProducerIDLoadFail)(nil); !errors.As(rerr, &pe) || !(errors.Is(pe.Err, context.Canceled) || strings.Contains(pe.Err.Error(), "canceled")) {
+			t.Errorf("got %v != exp ErrProducerIDLoadFail{context.Canceled}", rerr)
 		}
 	}
 
@@ -186,8 +190,8 @@ func TestIssue769(t *testing.T) {
 		case <-timer.C:
 			t.Fatal("expected record to fail within 3s")
 		}
-		if pe := (*errProducerIDLoadFail)(nil); errors.As(rerr, &pe) {
-			t.Error("unexpectedly got errProducerIDLoadFail")
+		if pe := (*ErrProducerIDLoadFail)(nil); errors.As(rerr, &pe) {
+			t.Error("unexpectedly got ErrProducerIDLoadFail")
 		}
 		if !errors.Is(rerr, context.Canceled) {
 			t.Errorf("got %v != context.Canceled", rerr)
@@ -232,6 +236,206 @@ func TestIssue831(t *testing.T) {
 	}
 }
 
+func TestFlushTopic(t *testing.T) {
+	t.Parallel()
+
+	t1, cleanup1 := tmpTopicPartitions(t, 1)
+	defer cleanup1()
+	t2, cleanup2 := tmpTopicPartitions(t, 1)
+	defer cleanup2()
+
+	cl, _ := newTestClient(
+		UnknownTopicRetries(-1),
+		ManualFlushing(),
+	)
+	defer cl.Close()
+
+	var done1, done2 atomic.Int64
+	for range 10 {
+		cl.Produce(context.Background(), &Record{Topic: t1, Value: []byte("v")}, func(_ *Record, err error) {
+			if err != nil {
+				t.Errorf("unexpected produce err: %v", err)
+			}
+			done1.Add(1)
+		})
+		cl.Produce(context.Background(), &Record{Topic: t2, Value: []byte("v")}, func(_ *Record, err error) {
+			if err != nil {
+				t.Errorf("unexpected produce err: %v", err)
+			}
+			done2.Add(1)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := cl.FlushTopic(ctx, t1); err != nil {
+		t.Fatalf("unable to flush topic: %v", err)
+	}
+	if n := done1.Load(); n != 10 {
+		t.Errorf("got %d finished records for flushed topic, exp 10", n)
+	}
+	if n := done2.Load(); n != 0 {
+		t.Errorf("got %d finished records for unflushed topic, exp 0", n)
+	}
+
+	if err := cl.Flush(ctx); err != nil {
+		t.Fatalf("unable to flush: %v", err)
+	}
+	if n := done2.Load(); n != 10 {
+		t.Errorf("got %d finished records after flushing, exp 10", n)
+	}
+}
+
+type flushProgressHook struct {
+	mu    sync.Mutex
+	calls []map[int32]int64
+}
+
+func (h *flushProgressHook) OnProduceFlushProgress(remaining map[int32]int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, remaining)
+}
+
+func TestBrokerDrainProgress(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+
+	hook := new(flushProgressHook)
+	cl, _ := newTestClient(
+		UnknownTopicRetries(-1),
+		ManualFlushing(),
+		WithHooks(hook),
+	)
+	defer cl.Close()
+
+	var done atomic.Int64
+	produce := func() {
+		for range 10 {
+			cl.Produce(context.Background(), &Record{Topic: topic, Value: []byte("v")}, func(_ *Record, err error) {
+				if err != nil {
+					t.Errorf("unexpected produce err: %v", err)
+				}
+				done.Add(1)
+			})
+		}
+	}
+	// Records are only assigned a broker once the topic is loaded.
+	leader := func() (leader int32) {
+		wait(t, 15*time.Second, func() error {
+			buffered := cl.BufferedProduceRecordsByBroker()
+			if len(buffered) != 1 {
+				return fmt.Errorf("got %d brokers with buffered records, exp 1", len(buffered))
+			}
+			for node, n := range buffered {
+				if n != 10 {
+					return fmt.Errorf("got %d buffered records for broker %d, exp 10", n, node)
+				}
+				leader = node
+			}
+			return nil
+		})
+		return leader
+	}
+
+	// Flagging the leader as shutting down drains it even though we are
+	// manually flushing.
+	produce()
+	cl.SetBrokersShuttingDown(leader())
+	wait(t, 15*time.Second, func() error {
+		if n := done.Load(); n != 10 {
+			return fmt.Errorf("got %d finished records after flagging the leader, exp 10", n)
+		}
+		return nil
+	})
+	if buffered := cl.BufferedProduceRecordsByBroker(); len(buffered) != 0 {
+		t.Errorf("got buffered records %v after draining, exp none", buffered)
+	}
+
+	// With the flag cleared, records wait for Flush, which reports its
+	// progress.
+	cl.SetBrokersShuttingDown()
+	produce()
+	node := leader()
+	if n := done.Load(); n != 10 {
+		t.Errorf("got %d finished records before flushing, exp 10", n)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := cl.Flush(ctx); err != nil {
+		t.Fatalf("unable to flush: %v", err)
+	}
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.calls) < 2 {
+		t.Fatalf("got %d flush progress calls, exp at least 2", len(hook.calls))
+	}
+	if first := hook.calls[0]; first[node] != 10 {
+		t.Errorf("got first flush progress %v, exp 10 records for broker %d", first, node)
+	}
+	if last := hook.calls[len(hook.calls)-1]; len(last) != 0 {
+		t.Errorf("got last flush progress %v, exp nothing remaining", last)
+	}
+}
+
+func TestAbortBufferedTopicRecords(t *testing.T) {
+	t.Parallel()
+
+	t1, cleanup1 := tmpTopicPartitions(t, 2)
+	defer cleanup1()
+	t2, cleanup2 := tmpTopicPartitions(t, 1)
+	defer cleanup2()
+
+	cl, _ := newTestClient(
+		UnknownTopicRetries(-1),
+		ManualFlushing(),
+		RecordPartitioner(ManualPartitioner()),
+	)
+	defer cl.Close()
+
+	var aborted, produced atomic.Int64
+	promise := func(_ *Record, err error) {
+		switch {
+		case err == nil:
+			produced.Add(1)
+		case errors.Is(err, ErrAborting):
+			aborted.Add(1)
+		default:
+			t.Errorf("unexpected produce err: %v", err)
+		}
+	}
+	for range 10 {
+		cl.Produce(context.Background(), &Record{Topic: t1, Partition: 0}, promise)
+		cl.Produce(context.Background(), &Record{Topic: t1, Partition: 1}, promise)
+		cl.Produce(context.Background(), &Record{Topic: t2, Partition: 0}, promise)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	counts, err := cl.AbortBufferedTopicRecords(ctx, map[string][]int32{t1: {1}})
+	if err != nil {
+		t.Fatalf("unable to abort: %v", err)
+	}
+	if exp := map[string]map[int32]int64{t1: {1: 10}}; !reflect.DeepEqual(counts, exp) {
+		t.Errorf("got aborted counts %v, exp %v", counts, exp)
+	}
+	if n := aborted.Load(); n != 10 {
+		t.Errorf("got %d aborted records, exp 10", n)
+	}
+	if n := produced.Load(); n != 10 {
+		t.Errorf("got %d produced records after aborting, exp 10 (the unaborted partition of the aborted topic)", n)
+	}
+
+	if err := cl.Flush(ctx); err != nil {
+		t.Fatalf("unable to flush: %v", err)
+	}
+	if n := produced.Load(); n != 20 {
+		t.Errorf("got %d produced records after flushing, exp 20", n)
+	}
+}
+
 // This file contains golden tests against kmsg AppendTo's to ensure our custom
 // encoding is correct.
 
@@ -430,6 +634,170 @@ func TestRecBatchAppendTo(t *testing.T) {
 	}
 }
 
+func TestRecBufPruneCanceled(t *testing.T) {
+	t.Parallel()
+	cl, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	recBuf := &recBuf{cl: cl, maxRecordBatchBytes: 1 << 20}
+	batch := recBuf.newRecordBatch()
+	recBuf.batches = append(recBuf.batches, batch)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var wg sync.WaitGroup
+	var gotErr error
+	now := time.Now()
+	for i, ctx := range []context.Context{canceled, context.Background(), canceled, context.Background()} {
+		pr := promisedRec{
+			ctx: context.Background(),
+			Record: &Record{
+				Value:     []byte(strings.Repeat("v", i+1)),
+				Timestamp: now.Add(time.Duration(i) * time.Millisecond),
+				Context:   ctx,
+			},
+		}
+		if ctx == canceled {
+			wg.Add(1)
+			pr.promise = func(_ *Record, err error) { gotErr = err; wg.Done() }
+		} else {
+			pr.promise = func(*Record, error) { t.Error("kept record was unexpectedly promised") }
+		}
+		if appended, _ := batch.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false); !appended {
+			t.Fatal("unable to buffer record")
+		}
+		recBuf.buffered.Add(1)
+	}
+
+	if removed := recBuf.pruneCanceled(0); removed {
+		t.Fatal("batch was unexpectedly removed")
+	}
+	wg.Wait()
+	if !errors.Is(gotErr, context.Canceled) {
+		t.Errorf("got promise err %v, exp context.Canceled", gotErr)
+	}
+	if n := recBuf.buffered.Load(); n != 2 {
+		t.Errorf("got %d buffered, exp 2", n)
+	}
+
+	// The pruned batch should be exactly as if only the kept records were
+	// buffered.
+	exp := recBuf.newRecordBatch()
+	for _, pr := range batch.records {
+		exp.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false)
+	}
+	if batch.wireLength != exp.wireLength ||
+		batch.v1wireLength != exp.v1wireLength ||
+		batch.firstTimestamp != exp.firstTimestamp ||
+		batch.maxTimestampDelta != exp.maxTimestampDelta {
+		t.Errorf("pruned batch numbers mismatch expected")
+	}
+	if len(batch.records) != 2 || string(batch.records[0].Value) != "vv" || string(batch.records[1].Value) != "vvvv" {
+		t.Errorf("unexpected kept records")
+	}
+
+	// Failing all remaining records removes the batch entirely.
+	for i := range batch.records {
+		batch.records[i].Context = canceled
+		wg.Add(1)
+		batch.records[i].promise = func(*Record, error) { wg.Done() }
+	}
+	if removed := recBuf.pruneCanceled(0); !removed || len(recBuf.batches) != 0 {
+		t.Error("expected emptied batch to be removed")
+	}
+	wg.Wait()
+}
+
+func TestRecBufSplitAndFailFirstBatch(t *testing.T) {
+	t.Parallel()
+	cl, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	recBuf := &recBuf{cl: cl, maxRecordBatchBytes: 1 << 20}
+	first, second := recBuf.newRecordBatch(), recBuf.newRecordBatch()
+	recBuf.batches = append(recBuf.batches, first, second)
+
+	var (
+		mu       sync.Mutex
+		promised = make(map[string]error)
+		now      = time.Now()
+	)
+	buffer := func(batch *recBatch, v string, i int) {
+		pr := promisedRec{
+			ctx: context.Background(),
+			Record: &Record{
+				Value:     []byte(v),
+				Timestamp: now.Add(time.Duration(i) * time.Millisecond),
+			},
+			promise: func(r *Record, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				promised[string(r.Value)] = err
+			},
+		}
+		if appended, _ := batch.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false); !appended {
+			t.Fatal("unable to buffer record")
+		}
+		recBuf.buffered.Add(1)
+	}
+	for i, v := range []string{"a", "bb", "ccc"} {
+		buffer(first, v, i)
+	}
+	buffer(second, "d", 3)
+	first.frozen = true
+	first.tries = 1
+	recBuf.batchDrainIdx = 2
+
+	// Splitting a three record batch gives one then two records, with
+	// numbers exactly as if they were buffered into their own batches.
+	recBuf.splitFirstBatch()
+	if len(recBuf.batches) != 3 || recBuf.batches[2] != second || recBuf.batchDrainIdx != 0 {
+		t.Fatalf("unexpected batches after split: %d batches, drain idx %d", len(recBuf.batches), recBuf.batchDrainIdx)
+	}
+	for i, exp := range [][]string{{"a"}, {"bb", "ccc"}} {
+		batch := recBuf.batches[i]
+		if len(batch.records) != len(exp) || !batch.frozen || batch.tries != 1 {
+			t.Fatalf("batch %d: got %d records (frozen %v, tries %d), exp %d frozen with 1 try", i, len(batch.records), batch.frozen, batch.tries, len(exp))
+		}
+		check := recBuf.newRecordBatch()
+		for j, pr := range batch.records {
+			if string(pr.Value) != exp[j] {
+				t.Errorf("batch %d record %d: got %q != exp %q", i, j, pr.Value, exp[j])
+			}
+			check.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false)
+		}
+		if batch.wireLength != check.wireLength ||
+			batch.firstTimestamp != check.firstTimestamp ||
+			batch.maxTimestampDelta != check.maxTimestampDelta {
+			t.Errorf("batch %d: split batch numbers mismatch expected", i)
+		}
+	}
+	if n := recBuf.buffered.Load(); n != 4 {
+		t.Errorf("got %d buffered after split, exp 4", n)
+	}
+
+	// Failing the (single record) first batch fails only that record.
+	recBuf.failFirstBatch(kerr.MessageTooLarge)
+	if len(recBuf.batches) != 2 || recBuf.buffered.Load() != 3 {
+		t.Errorf("got %d batches, %d buffered, exp 2 and 3", len(recBuf.batches), recBuf.buffered.Load())
+	}
+	wait(t, 5*time.Second, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(promised) != 1 || promised["a"] != kerr.MessageTooLarge {
+			return fmt.Errorf("got promised %v, exp only a with MESSAGE_TOO_LARGE", promised)
+		}
+		return nil
+	})
+}
+
 func TestMessageSetAppendTo(t *testing.T) {
 	t.Parallel()
 	// golden v0, uncompressed
@@ -646,6 +1014,7 @@ func BenchmarkAppendBatch(b *testing.B) {
 		{"zstd", CodecZstd},
 	} {
 		b.Run(pair.name, func(b *testing.B) {
+			b.ReportAllocs()
 			compressor, _ := DefaultCompressor(CompressionCodec{codec: pair.codec})
 			ourReq.compressor = compressor
 			for i := 0; i < b.N; i++ {
@@ -654,4 +1023,386 @@ func BenchmarkAppendBatch(b *testing.B) {
 			b.Log(len(buf))
 		})
 	}
+
+	// Without a reused buffer, uncompressed batches are appended into a
+	// buffer grown once to the batch size.
+	b.Run("no compression unbuffered", func(b *testing.B) {
+		b.ReportAllocs()
+		ourReq.compressor, _ = DefaultCompressor(NoCompression())
+		ourReq.wireLength = int32(len(ourReq.AppendTo(nil))) // normally tracked as batches are added
+		for i := 0; i < b.N; i++ {
+			buf = ourReq.AppendTo(nil)
+		}
+		b.Log(len(buf))
+	})
+}
+
+type batchWrittenHook struct {
+	mu      sync.Mutex
+	batches map[int32][]ProduceBatchMetrics
+}
+
+func (h *batchWrittenHook) OnProduceBatchWritten(_ BrokerMetadata, _ string, partition int32, m ProduceBatchMetrics) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.batches == nil {
+		h.batches = make(map[int32][]ProduceBatchMetrics)
+	}
+	h.batches[partition] = append(h.batches[partition], m)
+}
+
+func TestProduceDryRun(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 2)
+	defer cleanup()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	keys := new(keyCountHook)
+	batches := new(batchWrittenHook)
+	cl, _ := newTestClient(
+		DefaultProduceTopic(topic),
+		RecordPartitioner(ManualPartitioner()),
+		ProduceDryRun(),
+		WithHooks(keys, batches),
+	)
+	defer cl.Close()
+
+	var rs []*Record
+	for i := range 6 {
+		rs = append(rs, &Record{Partition: int32(i % 2), Value: bytes.Repeat([]byte("v"), 100)})
+	}
+	if err := cl.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+		t.Fatalf("unable to dry run produce: %v", err)
+	}
+	next := make(map[int32]int64)
+	for _, r := range rs {
+		if r.Offset != next[r.Partition] {
+			t.Errorf("partition %d: got offset %d != exp %d", r.Partition, r.Offset, next[r.Partition])
+		}
+		next[r.Partition]++
+	}
+
+	// The hook is called after promises are finished.
+	wait(t, 5*time.Second, func() error {
+		batches.mu.Lock()
+		defer batches.mu.Unlock()
+		var nrecs int
+		for p, ms := range batches.batches {
+			for _, m := range ms {
+				nrecs += m.NumRecords
+				if m.UncompressedBytes <= 0 || m.CompressedBytes <= 0 {
+					t.Errorf("partition %d: got unexpected batch sizes %+v", p, m)
+				}
+			}
+		}
+		if nrecs != len(rs) {
+			return fmt.Errorf("got %d records in written batches != exp %d", nrecs, len(rs))
+		}
+		return nil
+	})
+	if n := keys.count(int16(kmsg.Produce)); n != 0 {
+		t.Errorf("got %d produce requests written in dry run mode, exp 0", n)
+	}
+	if n := keys.count(int16(kmsg.InitProducerID)); n != 0 {
+		t.Errorf("got %d InitProducerID requests written in dry run mode, exp 0", n)
+	}
+
+	// Nothing was written, so a real produce is the first record.
+	producer, _ := newTestClient(DefaultProduceTopic(topic), RecordPartitioner(ManualPartitioner()))
+	defer producer.Close()
+	r := &Record{Partition: 0}
+	if err := producer.ProduceSync(ctx, r).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if r.Offset != 0 {
+		t.Errorf("got real produce offset %d != exp 0 (dry run records were written)", r.Offset)
+	}
+}
+
+type fakeBackupIter struct {
+	buffered []int64
+	latency  []time.Duration
+}
+
+func (i *fakeBackupIter) Next() (int, int64) {
+	pick, buffered, _ := i.NextWithLatency()
+	return pick, buffered
+}
+
+func (i *fakeBackupIter) NextWithLatency() (int, int64, time.Duration) {
+	last := len(i.buffered) - 1
+	buffered, latency := i.buffered[last], i.latency[last]
+	i.buffered, i.latency = i.buffered[:last], i.latency[:last]
+	return last, buffered, latency
+}
+
+func (i *fakeBackupIter) Rem() int { return len(i.buffered) }
+
+func TestLeastLoadedPartitioner(t *testing.T) {
+	t.Parallel()
+
+	buffered := []int64{5, 2, 3}
+	latency := []time.Duration{time.Millisecond, 100 * time.Millisecond, 10 * time.Millisecond}
+	iter := func() *fakeBackupIter {
+		return &fakeBackupIter{slices.Clone(buffered), slices.Clone(latency)}
+	}
+
+	// By backlog alone, partition 1 is least loaded; considering latency,
+	// partition 0 is (6*1 < 4*10 < 3*100).
+	for _, test := range []struct {
+		latency bool
+		exp     int
+	}{
+		{false, 1},
+		{true, 0},
+	} {
+		p := LeastLoadedPartitioner(false, test.latency, nil).ForTopic("t").(TopicBackupPartitioner)
+		if got := p.PartitionByBackup(&Record{}, 3, iter()); got != test.exp {
+			t.Errorf("latency %v: got partition %d != exp %d", test.latency, got, test.exp)
+		}
+		// Sticky until a new batch.
+		if got := p.PartitionByBackup(&Record{}, 3, &fakeBackupIter{}); got != test.exp {
+			t.Errorf("latency %v: got sticky partition %d != exp %d", test.latency, got, test.exp)
+		}
+		p.(TopicPartitionerOnNewBatch).OnNewBatch()
+		if got := p.PartitionByBackup(&Record{}, 3, iter()); got != test.exp {
+			t.Errorf("latency %v: got partition %d after new batch != exp %d", test.latency, got, test.exp)
+		}
+	}
+
+	// Keyed records are hashed regardless of load.
+	p := LeastLoadedPartitioner(true, true, nil).ForTopic("t").(TopicBackupPartitioner)
+	r := &Record{Key: []byte("key")}
+	if !p.RequiresConsistency(r) {
+		t.Error("keyed record does not require consistency")
+	}
+	if got, exp := p.PartitionByBackup(r, 3, iter()), KafkaHasher(murmur2)(r.Key, 3); got != exp {
+		t.Errorf("got keyed partition %d != exp %d", got, exp)
+	}
+
+	// Weighted picking favors the least loaded partition.
+	counts := make([]int, 3)
+	for range 3000 {
+		p.(TopicPartitionerOnNewBatch).OnNewBatch()
+		counts[p.PartitionByBackup(&Record{}, 3, iter())]++
+	}
+	if counts[0] <= counts[2] || counts[2] <= counts[1] {
+		t.Errorf("got weighted pick counts %v, expected partition 0 most and partition 1 least", counts)
+	}
+}
+
+func TestRecBufLatency(t *testing.T) {
+	t.Parallel()
+
+	cl, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+	s := cl.newSink(1)
+	recBuf := &recBuf{cl: cl, sink: s, topic: "t", maxRecordBatchBytes: 1 << 20}
+
+	// A request that fails without a response still counts.
+	req := &produceRequest{start: time.Now().Add(-100 * time.Millisecond)}
+	req.batches.addSeqBatch("t", [16]byte{}, 0, seqRecBatch{recBatch: recBuf.newRecordBatch()})
+	s.handleReqClientErr(req, ErrClientClosed)
+
+	now := time.Now()
+	if got := recBuf.loadLatency(now); got < 100*time.Millisecond || got > time.Second {
+		t.Fatalf("got latency %v after a failed request, exp about 100ms", got)
+	}
+
+	// Latency halves every half life that nothing is observed.
+	latency := recBuf.loadLatency(now)
+	decayed := recBuf.loadLatency(now.Add(2 * latencyHalfLife))
+	if exp := latency / 4; decayed < exp-time.Millisecond || decayed > exp+time.Millisecond {
+		t.Errorf("got decayed latency %v != exp %v", decayed, exp)
+	}
+
+	// A new observation after a long idle period mostly forgets the old
+	// latency.
+	recBuf.latencyAt.Store(now.Add(-10 * latencyHalfLife).UnixNano())
+	recBuf.observeLatency(10 * time.Millisecond)
+	if got := recBuf.loadLatency(time.Now()); got > 10*time.Millisecond {
+		t.Errorf("got latency %v after idling, exp at most 10ms", got)
+	}
+}
+
+type recoveryHook struct {
+	mu sync.Mutex
+	rs []ProducerIDRecovery
+}
+
+func (h *recoveryHook) OnProducerIDRecovery(r ProducerIDRecovery) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rs = append(h.rs, r)
+}
+
+func TestTooLargeBatchWithInflight(t *testing.T) {
+	t.Parallel()
+	hook := new(recoveryHook)
+	cl, err := NewClient(WithHooks(hook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	s := cl.newSink(1)
+	recBuf := &recBuf{cl: cl, sink: s, topic: "t", maxRecordBatchBytes: 1 << 20}
+
+	var (
+		mu       sync.Mutex
+		promised = make(map[string]error)
+	)
+	for _, v := range []string{"a", "b", "c"} {
+		batch := recBuf.newRecordBatch()
+		pr := promisedRec{
+			ctx:    context.Background(),
+			Record: &Record{Topic: "t", Value: []byte(v), Timestamp: time.Now()},
+			promise: func(r *Record, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				promised[string(r.Value)] = err
+			},
+		}
+		if appended, _ := batch.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false); !appended {
+			t.Fatal("unable to buffer record")
+		}
+		batch.frozen = true
+		recBuf.batches = append(recBuf.batches, batch)
+		recBuf.buffered.Add(1)
+	}
+
+	// All three batches are inflight in separate requests.
+	recBuf.batchDrainIdx = 3
+	recBuf.seq = 3
+	recBuf.inflight = 3
+	recBuf.inflightOnSink = s
+	recBuf.okOnSink = true
+	first, second, third := recBuf.batches[0], recBuf.batches[1], recBuf.batches[2]
+
+	respond := func(batch *recBatch, seq int32, code int16) {
+		rp := &kmsg.ProduceResponseTopicPartition{ErrorCode: code}
+		if retry, _ := s.handleReqRespBatch(nil, nil, new(kip951move), new(kmsg.ProduceResponse), "t", rp, seqRecBatch{seq, batch}, 1, 0); retry {
+			t.Errorf("seq %d: unexpected retry", seq)
+		}
+		recBuf.mu.Lock()
+		batch.decInflight()
+		recBuf.mu.Unlock()
+	}
+
+	// The first batch is too large. The broker did not write it, so
+	// the later inflight batches are rejected as out of order; these must
+	// be skipped rather than treated as data loss.
+	respond(first, 0, kerr.MessageTooLarge.Code)
+	respond(second, 1, kerr.OutOfOrderSequenceNumber.Code)
+	recBuf.mu.Lock()
+	if len(recBuf.batches) != 3 || recBuf.batches[0] != first {
+		t.Error("too large batch was removed while requests were still inflight")
+	}
+	recBuf.mu.Unlock()
+	respond(third, 2, kerr.OutOfOrderSequenceNumber.Code)
+
+	recBuf.mu.Lock()
+	if len(recBuf.batches) != 2 || recBuf.batches[0] != second || recBuf.batches[1] != third ||
+		recBuf.batchDrainIdx != 0 || recBuf.seq != 0 || recBuf.buffered.Load() != 2 {
+		t.Errorf("got %d batches, drain idx %d, seq %d, %d buffered; exp the later two batches to be drained again from seq 0",
+			len(recBuf.batches), recBuf.batchDrainIdx, recBuf.seq, recBuf.buffered.Load())
+	}
+	recBuf.mu.Unlock()
+
+	hook.mu.Lock()
+	if len(hook.rs) != 0 {
+		t.Errorf("got unexpected producer ID recoveries %v", hook.rs)
+	}
+	hook.mu.Unlock()
+
+	wait(t, 5*time.Second, func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		if len(promised) != 1 || promised["a"] != kerr.MessageTooLarge {
+			return fmt.Errorf("got promised %v, exp only a with MESSAGE_TOO_LARGE", promised)
+		}
+		return nil
+	})
+}
+
+func TestProduceRetryStaleEpoch(t *testing.T) {
+	t.Parallel()
+	for _, code := range []int16{
+		kerr.OutOfOrderSequenceNumber.Code,
+		kerr.UnknownProducerID.Code,
+		kerr.InvalidProducerIDMapping.Code,
+		kerr.InvalidProducerEpoch.Code,
+	} {
+		t.Run(kerr.ErrorForCode(code).(*kerr.Error).Message, func(t *testing.T) {
+			hook := new(recoveryHook)
+			cl, err := NewClient(WithHooks(hook))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cl.Close()
+
+			// The batch was produced with epoch 0, but another
+			// partition has since bumped the epoch to 1.
+			cl.producer.id.Store(&producerID{id: 1, epoch: 1})
+
+			s := cl.newSink(1)
+			recBuf := &recBuf{cl: cl, sink: s, topic: "t", maxRecordBatchBytes: 1 << 20}
+			var promised atomicI64
+			batch := recBuf.newRecordBatch()
+			pr := promisedRec{
+				ctx:     context.Background(),
+				Record:  &Record{Topic: "t", Value: []byte("v"), Timestamp: time.Now()},
+				promise: func(*Record, error) { promised.Add(1) },
+			}
+			if appended, _ := batch.tryBuffer(pr, -1, recBuf.maxRecordBatchBytes, false); !appended {
+				t.Fatal("unable to buffer record")
+			}
+			batch.frozen = true
+			recBuf.batches = append(recBuf.batches, batch)
+			recBuf.buffered.Add(1)
+			recBuf.batchDrainIdx = 1
+			recBuf.seq = 1
+			recBuf.inflight = 1
+			recBuf.inflightOnSink = s
+			recBuf.okOnSink = true
+
+			rp := &kmsg.ProduceResponseTopicPartition{Partition: 2, ErrorCode: code}
+			retry, didProduce := s.handleReqRespBatch(nil, nil, new(kip951move), new(kmsg.ProduceResponse), "t", rp, seqRecBatch{0, batch}, 1, 0)
+			if !retry || didProduce {
+				t.Errorf("got retry %v, produced %v, exp a retry without producing", retry, didProduce)
+			}
+
+			recBuf.mu.Lock()
+			if len(recBuf.batches) != 1 || recBuf.batches[0] != batch || recBuf.failing {
+				t.Errorf("got %d batches, failing %v; exp the batch to be kept for the retry", len(recBuf.batches), recBuf.failing)
+			}
+			recBuf.mu.Unlock()
+			if id := cl.producer.id.Load().(*producerID); id.id != 1 || id.epoch != 1 || id.err != nil {
+				t.Errorf("got producer id %d, epoch %d, err %v; exp the current id to be unchanged", id.id, id.epoch, id.err)
+			}
+			if n := promised.Load(); n != 0 {
+				t.Errorf("got %d promised records, exp 0", n)
+			}
+
+			hook.mu.Lock()
+			defer hook.mu.Unlock()
+			exp := []ProducerIDRecovery{{
+				Action:        ProducerIDRetryStaleEpoch,
+				Topic:         "t",
+				Partition:     2,
+				ProducerID:    1,
+				ProducerEpoch: 0,
+				Err:           kerr.ErrorForCode(code),
+			}}
+			if !reflect.DeepEqual(hook.rs, exp) {
+				t.Errorf("got recoveries %+v != exp %+v", hook.rs, exp)
+			}
+		})
+	}
 }
@@ -0,0 +1,285 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kbin"
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// ProduceBatchVerification is the result of verifying whether a batch landed
+// after an ambiguous produce error, as passed to
+// [HookProduceBatchVerified]. See [VerifyAmbiguousProduces] for more details.
+type ProduceBatchVerification struct {
+	Topic         string // Topic is the topic the batch was produced to.
+	Partition     int32  // Partition is the partition the batch was produced to.
+	ProducerID    int64  // ProducerID is the producer ID the batch was written with.
+	ProducerEpoch int16  // ProducerEpoch is the producer epoch the batch was written with.
+	FirstSequence int32  // FirstSequence is the sequence number of the first record in the batch.
+	NumRecords    int    // NumRecords is the number of records in the batch.
+
+	// Err is the ambiguous error that the produce request failed with.
+	Err error
+
+	// Landed is whether the batch was found in the partition, meaning the
+	// broker processed the produce request before it failed.
+	Landed bool
+
+	// Offset is the base offset of the batch if it landed, otherwise -1.
+	Offset int64
+
+	// DuplicatesPossible is whether retrying the batch may write the
+	// batch's records twice. If the client is idempotent and the producer
+	// ID and epoch have not changed, the broker deduplicates the retry
+	// and duplicates are avoided. Duplicates are always possible if the
+	// client is not idempotent.
+	DuplicatesPossible bool
+
+	// VerifyErr, if non-nil, is why the client could not determine if the
+	// batch landed.
+	VerifyErr error
+}
+
+// HookProduceBatchVerified is called with the result of verifying whether a
+// batch landed after an ambiguous produce error, if the client is using
+// [VerifyAmbiguousProduces].
+type HookProduceBatchVerified interface {
+	// OnProduceBatchVerified is called once per batch per ambiguous
+	// produce error.
+	OnProduceBatchVerified(ProduceBatchVerification)
+}
+
+const (
+	verifyTimeout    = 30 * time.Second
+	verifyMaxFetches = 20
+)
+
+var errVerifyNotIdempotent = errors.New("batches produced without idempotency cannot be identified")
+
+// isAmbiguousProduceErr returns whether a client error for a produce request
+// leaves it unknown if the broker processed the request. Dial errors and
+// unknown broker errors mean the request was never written.
+func isAmbiguousProduceErr(err error) bool {
+	return !errors.Is(err, errUnknownBroker) &&
+		!errors.Is(err, ErrClientClosed) &&
+		!isAnyDialErr(err)
+}
+
+// maybeVerifyAmbiguous begins verifying all batches in a produce request that
+// failed with an ambiguous error, if verification is enabled.
+//
+// A retry of a batch is written with the same producer ID, epoch, and
+// sequence number, so verifying concurrently with a retry could find the
+// retry's own write. We block draining every owner with a batch being
+// verified until verification finishes; see the recBuf verifying field. We
+// copy everything we need out of the batches before returning: the batches
+// can still be failed concurrently with verification.
+func (s *sink) maybeVerifyAmbiguous(req *produceRequest, err error) {
+	if !s.cl.cfg.verifyAmbiguous || req.acks == 0 || !isAmbiguousProduceErr(err) {
+		return
+	}
+	var vs []ProduceBatchVerification
+	var owners []*recBuf
+	var firstTimestamps []int64
+	req.batches.eachOwnerLocked(func(batch seqRecBatch) {
+		batch.mu.Lock()
+		defer batch.mu.Unlock()
+		if len(batch.records) == 0 {
+			return // concurrently failed
+		}
+		batch.owner.verifying++
+		v := ProduceBatchVerification{
+			Topic:         batch.owner.topic,
+			Partition:     batch.owner.partition,
+			ProducerID:    req.producerID,
+			ProducerEpoch: req.producerEpoch,
+			FirstSequence: batch.seq,
+			NumRecords:    len(batch.records),
+			Err:           err,
+			Offset:        -1,
+		}
+		if !req.idempotent() {
+			v.FirstSequence = -1
+		}
+		vs = append(vs, v)
+		owners = append(owners, batch.owner)
+		firstTimestamps = append(firstTimestamps, batch.firstTimestamp)
+	})
+	for i := range vs {
+		go s.cl.verifyBatch(vs[i], owners[i], firstTimestamps[i])
+	}
+}
+
+// verifyBatch determines whether a batch landed by searching the partition
+// from the batch's first timestamp for a batch with the same producer ID,
+// epoch, and sequence number.
+func (cl *Client) verifyBatch(v ProduceBatchVerification, owner *recBuf, firstTimestamp int64) {
+	if v.ProducerID < 0 {
+		v.DuplicatesPossible = true
+		v.VerifyErr = errVerifyNotIdempotent
+	} else {
+		ctx, cancel := context.WithTimeout(cl.ctx, verifyTimeout)
+		v.Offset, v.VerifyErr = cl.searchBatch(ctx, owner, v.ProducerID, v.ProducerEpoch, v.FirstSequence, firstTimestamp)
+		cancel()
+		v.Landed = v.Offset >= 0
+
+		// The broker only deduplicates a retry if it is sent with the
+		// same producer ID and epoch.
+		current := cl.producer.id.Load().(*producerID)
+		v.DuplicatesPossible = (v.Landed || v.VerifyErr != nil) && (current.id != v.ProducerID || current.epoch != v.ProducerEpoch)
+	}
+
+	cl.cfg.logger.Log(LogLevelInfo, "verified batch after ambiguous produce error",
+		"topic", v.Topic,
+		"partition", v.Partition,
+		"producer_id", v.ProducerID,
+		"producer_epoch", v.ProducerEpoch,
+		"first_sequence", v.FirstSequence,
+		"num_records", v.NumRecords,
+		"err", v.Err,
+		"landed", v.Landed,
+		"offset", v.Offset,
+		"duplicates_possible", v.DuplicatesPossible,
+		"verify_err", v.VerifyErr,
+	)
+	cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookProduceBatchVerified); ok {
+			h.OnProduceBatchVerified(v)
+		}
+	})
+
+	owner.mu.Lock()
+	defer owner.mu.Unlock()
+	owner.verifying--
+	if owner.verifying == 0 && len(owner.batches) != owner.batchDrainIdx {
+		owner.sink.maybeDrain()
+	}
+}
+
+// searchBatch returns the base offset of the batch in the partition with the
+// given producer ID, epoch, and sequence, or -1 if no such batch exists up to
+// the partition's high watermark.
+func (cl *Client) searchBatch(ctx context.Context, owner *recBuf, id int64, epoch int16, seq int32, firstTimestamp int64) (int64, error) {
+	start, err := cl.verifyListOffset(ctx, owner.topic, owner.partition, firstTimestamp)
+	if err != nil {
+		return -1, err
+	}
+	end, err := cl.verifyListOffset(ctx, owner.topic, owner.partition, -1)
+	if err != nil {
+		return -1, err
+	}
+	if start < 0 { // no record at or after our timestamp
+		return -1, nil
+	}
+
+	for range verifyMaxFetches {
+		if start >= end {
+			return -1, nil
+		}
+		owner.mu.Lock()
+		leader, topicID := owner.sink.nodeID, owner.topicID
+		owner.mu.Unlock()
+
+		req := kmsg.NewPtrFetchRequest()
+		req.MaxWaitMillis = 0
+		req.MaxBytes = 1 << 20
+		rt := kmsg.NewFetchRequestTopic()
+		rt.Topic = owner.topic
+		rt.TopicID = topicID
+		rp := kmsg.NewFetchRequestTopicPartition()
+		rp.Partition = owner.partition
+		rp.FetchOffset = start
+		rp.PartitionMaxBytes = 1 << 20
+		rt.Partitions = append(rt.Partitions, rp)
+		req.Topics = append(req.Topics, rt)
+
+		resp, err := req.RequestWith(ctx, cl.Broker(int(leader)))
+		if err != nil {
+			return -1, err
+		}
+		if err := kerr.ErrorForCode(resp.ErrorCode); err != nil {
+			return -1, err
+		}
+		if len(resp.Topics) != 1 || len(resp.Topics[0].Partitions) != 1 {
+			return -1, errors.New("fetch response did not contain the requested partition")
+		}
+		rp2 := &resp.Topics[0].Partitions[0]
+		if err := kerr.ErrorForCode(rp2.ErrorCode); err != nil {
+			return -1, err
+		}
+		offset, next := findProducerBatch(rp2.RecordBatches, id, epoch, seq)
+		if offset >= 0 {
+			return offset, nil
+		}
+		if next <= start {
+			return -1, errors.New("fetch response did not advance the search")
+		}
+		start = next
+	}
+	return -1, fmt.Errorf("batch not found after %d fetches", verifyMaxFetches)
+}
+
+// verifyListOffset returns the offset for a timestamp (or -1 for the end
+// offset) in a partition.
+func (cl *Client) verifyListOffset(ctx context.Context, topic string, partition int32, timestamp int64) (int64, error) {
+	req := kmsg.NewPtrListOffsetsRequest()
+	rt := kmsg.NewListOffsetsRequestTopic()
+	rt.Topic = topic
+	rp := kmsg.NewListOffsetsRequestTopicPartition()
+	rp.Partition = partition
+	rp.Timestamp = timestamp
+	rt.Partitions = append(rt.Partitions, rp)
+	req.Topics = append(req.Topics, rt)
+
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return -1, err
+	}
+	if len(resp.Topics) != 1 || len(resp.Topics[0].Partitions) != 1 {
+		return -1, errors.New("list offsets response did not contain the requested partition")
+	}
+	p := &resp.Topics[0].Partitions[0]
+	if err := kerr.ErrorForCode(p.ErrorCode); err != nil {
+		return -1, err
+	}
+	return p.Offset, nil
+}
+
+// findProducerBatch searches raw record batches for a batch with the given
+// producer ID, epoch, and first sequence, returning the batch's base offset (or
+// -1 if not found) and the offset after the last complete batch.
+func findProducerBatch(raw []byte, id int64, epoch int16, seq int32) (offset, next int64) {
+	offset, next = -1, -1
+	for len(raw) >= 61 { // baseOffset through the record count
+		b := kbin.Reader{Src: raw}
+		baseOffset := b.Int64()
+		length := b.Int32()
+		if length < 49 || int(length) > len(raw)-12 {
+			return offset, next // partial batch
+		}
+		b.Int32() // partition leader epoch
+		magic := b.Int8()
+		if magic != 2 {
+			return offset, next
+		}
+		b.Int32() // crc
+		b.Int16() // attributes
+		lastOffsetDelta := b.Int32()
+		b.Int64() // first timestamp
+		b.Int64() // max timestamp
+		batchID := b.Int64()
+		batchEpoch := b.Int16()
+		batchSeq := b.Int32()
+
+		if batchID == id && batchEpoch == epoch && batchSeq == seq {
+			return baseOffset, next
+		}
+		next = baseOffset + int64(lastOffsetDelta) + 1
+		raw = raw[12+length:]
+	}
+	return offset, next
+}
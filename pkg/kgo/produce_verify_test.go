@@ -0,0 +1,158 @@
+package kgo
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func TestFindProducerBatch(t *testing.T) {
+	t.Parallel()
+
+	var raw []byte
+	for i, b := range []struct {
+		id    int64
+		epoch int16
+		seq   int32
+		n     int32
+	}{
+		{-1, -1, -1, 3},
+		{5, 0, 0, 2},
+		{5, 0, 2, 4},
+		{5, 1, 0, 1},
+	} {
+		batch := kmsg.RecordBatch{
+			FirstOffset:     int64(i * 10),
+			Magic:           2,
+			LastOffsetDelta: b.n - 1,
+			ProducerID:      b.id,
+			ProducerEpoch:   b.epoch,
+			FirstSequence:   b.seq,
+			NumRecords:      b.n,
+			Records:         make([]byte, 5),
+		}
+		batch.Length = int32(len(batch.AppendTo(nil)) - 12)
+		raw = batch.AppendTo(raw)
+	}
+
+	for _, test := range []struct {
+		id        int64
+		epoch     int16
+		seq       int32
+		raw       []byte
+		expOffset int64
+		expNext   int64
+	}{
+		{5, 0, 2, raw, 20, 12},
+		{5, 1, 0, raw, 30, 24},
+		{5, 0, 1, raw, -1, 31}, // sequence in the middle of a batch is not a match
+		{6, 0, 0, raw, -1, 31},
+		{5, 1, 0, raw[:len(raw)-1], -1, 24}, // partial final batch
+	} {
+		offset, next := findProducerBatch(test.raw, test.id, test.epoch, test.seq)
+		if offset != test.expOffset || next != test.expNext {
+			t.Errorf("find %d/%d/%d: got offset %d next %d, exp %d %d", test.id, test.epoch, test.seq, offset, next, test.expOffset, test.expNext)
+		}
+	}
+}
+
+type verifyHook struct {
+	mu sync.Mutex
+	vs []ProduceBatchVerification
+}
+
+func (h *verifyHook) OnProduceBatchVerified(v ProduceBatchVerification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.vs = append(h.vs, v)
+}
+
+// cutProduceConn closes the connection after the first produce request is
+// written, before the response can be read. If drop is true, the request is
+// not written at all before the connection is closed.
+type cutProduceConn struct {
+	net.Conn
+	cut  *atomic.Bool
+	drop bool
+}
+
+func (c *cutProduceConn) Write(b []byte) (int, error) {
+	if len(b) > 6 && binary.BigEndian.Uint16(b[4:]) == 0 && c.drop && c.cut.CompareAndSwap(false, true) {
+		c.Conn.Close()
+		return 0, net.ErrClosed
+	}
+	n, err := c.Conn.Write(b)
+	if err == nil && len(b) > 6 && binary.BigEndian.Uint16(b[4:]) == 0 && c.cut.CompareAndSwap(false, true) {
+		time.Sleep(100 * time.Millisecond) // give the broker time to handle the request
+		c.Conn.Close()
+	}
+	return n, err
+}
+
+func TestVerifyAmbiguousProduces(t *testing.T) {
+	t.Parallel()
+
+	for _, drop := range []bool{false, true} {
+		t.Run(fmt.Sprintf("drop_%v", drop), func(t *testing.T) {
+			topic, cleanup := tmpTopicPartitions(t, 1)
+			defer cleanup()
+
+			var (
+				cut  atomic.Bool
+				hook verifyHook
+				d    net.Dialer
+			)
+			cl, _ := newTestClient(
+				DefaultProduceTopic(topic),
+				UnknownTopicRetries(-1),
+				VerifyAmbiguousProduces(),
+				WithHooks(&hook),
+				Dialer(func(ctx context.Context, network, host string) (net.Conn, error) {
+					c, err := d.DialContext(ctx, network, host)
+					if err != nil {
+						return nil, err
+					}
+					return &cutProduceConn{c, &cut, drop}, nil
+				}),
+			)
+			defer cl.Close()
+
+			r, err := cl.ProduceSync(context.Background(), &Record{Value: []byte("v")}).First()
+			if err != nil {
+				t.Fatalf("unable to produce: %v", err)
+			}
+
+			// The batch is not retried until it is verified, so
+			// the verification must have finished already.
+			hook.mu.Lock()
+			vs := hook.vs
+			hook.mu.Unlock()
+			if len(vs) != 1 {
+				t.Fatalf("got %d verifications once the produce finished, exp 1", len(vs))
+			}
+			v := vs[0]
+			if v.Topic != topic || v.Partition != 0 || v.NumRecords != 1 || v.ProducerID < 0 {
+				t.Fatalf("unexpected verification %+v", v)
+			}
+			if v.VerifyErr != nil {
+				t.Fatalf("unexpected verify err: %v", v.VerifyErr)
+			}
+			if v.DuplicatesPossible {
+				t.Error("unexpectedly possible duplicates with an unchanged producer ID")
+			}
+			if v.Landed == drop {
+				t.Errorf("got landed %v for a request that was dropped? %v", v.Landed, drop)
+			}
+			if v.Landed && v.Offset != r.Offset {
+				t.Errorf("batch landed at %d, but the retry was produced at %d", v.Offset, r.Offset)
+			}
+		})
+	}
+}
@@ -21,6 +21,7 @@ type producer struct {
 
 	bufferedRecords int64
 	bufferedBytes   int64
+	topicBuffered   topicBuffered // buffered records per topic, for FlushTopic
 
 	cl *Client
 
@@ -33,9 +34,20 @@ type producer struct {
 		buffered    []HookProduceRecordBuffered
 		partitioned []HookProduceRecordPartitioned
 		unbuffered  []HookProduceRecordUnbuffered
+		skew        []HookProduceTimestampSkew
+		acked       []HookProduceBatchAcked
 	}
 
-	hasHookBatchWritten bool
+	hasHookBatchWritten  bool
+	hasHookFlushProgress bool
+
+	// tsAnchor is the wall clock time, with a monotonic reading, that
+	// TimestampMonotonic timestamps are derived from.
+	tsAnchor time.Time
+
+	tee teeStats // counters for TeeProduce
+
+	topicMaxBytes topicMaxBytes // learned max.message.bytes for ValidateTopicMaxMessageBytes
 
 	// unknownTopics buffers all records for topics that are not loaded.
 	// The map is to a pointer to a slice for reasons documented in
@@ -53,16 +65,38 @@ type producer struct {
 	blocked      atomicI32 // >0 if over max recs or bytes
 	blockedBytes int64
 
+	// flushingTopics tracks topics being flushed with FlushTopic; the
+	// atomic allows skipping the lock if nothing is being flushed.
+	flushingTopicsMu sync.Mutex
+	flushingTopics   map[string]int32
+	flushingTopicsN  atomicI32
+
+	// shuttingDown is a map[int32]struct{} of brokers flagged with
+	// SetBrokersShuttingDown; records for these brokers never linger.
+	shuttingDown atomic.Value
+
 	aborting atomicI32 // >0 if aborting, can abort many times concurrently
 
+	// abortScopes tracks AbortBufferedTopicRecords calls; the atomic
+	// allows skipping the lock if nothing is being aborted.
+	abortScopesMu sync.Mutex
+	abortScopes   []*abortScope
+	abortScopesN  atomicI32
+
 	idMu      sync.Mutex
 	idVersion int16
 
 	batchPromises ring[batchPromise] // we never call die() on it
 
-	txnMu   sync.Mutex
-	inTxn   bool
-	tx890p2 bool
+	// txnID is the current transactional ID, which can change with
+	// TransactionalIDPerGroupMember. Changes only happen in
+	// BeginTransaction, under txnMu.
+	txnID atomic.Pointer[string]
+
+	txnMu      sync.Mutex
+	inTxn      bool
+	tx890p2    bool
+	lastTxnEnd *TransactionEndResult
 }
 
 // BufferedProduceRecords returns the number of records currently buffered for
@@ -77,6 +111,61 @@ func (cl *Client) BufferedProduceRecords() int64 {
 	return cl.producer.bufferedRecords + int64(cl.producer.blocked.Load())
 }
 
+// BufferedProduceRecordsByBroker returns the number of records currently
+// buffered for producing to each broker, by node ID. Brokers with no buffered
+// records are not included. Records that are waiting for their topic to be
+// loaded or that are blocked in Produce are not yet assigned to a broker and
+// are not included; see BufferedProduceRecords for the total.
+//
+// This can be used to track per broker drain progress while flushing, for
+// example to hold off restarting a broker until the client has nothing left
+// to produce to it. See also HookProduceFlushProgress.
+func (cl *Client) BufferedProduceRecordsByBroker() map[int32]int64 {
+	buffered := make(map[int32]int64)
+	cl.allSinksAndSources(func(sns sinkAndSource) {
+		if n := sns.sink.buffered(); n > 0 {
+			buffered[sns.sink.nodeID] = n
+		}
+	})
+	return buffered
+}
+
+// SetBrokersShuttingDown flags the given brokers as shutting down, replacing
+// any prior flagged brokers. Calling this with no brokers clears the flags.
+//
+// Records for partitions led by a flagged broker are never lingered (and are
+// drained even when using ManualFlushing), and Flush begins draining flagged
+// brokers before others. This can be used to coordinate rolling broker
+// restarts with the client: flag a broker that is about to be restarted and
+// wait for BufferedProduceRecordsByBroker to no longer include it.
+//
+// Flagging a broker does not stop the client from producing to it; if a
+// partition's leadership moves off the broker, its records move with it.
+func (cl *Client) SetBrokersShuttingDown(nodeIDs ...int32) {
+	flagged := make(map[int32]struct{}, len(nodeIDs))
+	for _, id := range nodeIDs {
+		flagged[id] = struct{}{}
+	}
+	cl.producer.shuttingDown.Store(flagged)
+
+	if len(flagged) > 0 {
+		cl.cfg.logger.Log(LogLevelInfo, "flagged brokers as shutting down, draining their buffered records", "brokers", nodeIDs)
+	}
+	cl.allSinksAndSources(func(sns sinkAndSource) {
+		if _, ok := flagged[sns.sink.nodeID]; ok {
+			sns.sink.unlingerAndDrain()
+		}
+	})
+}
+
+// isShuttingDown returns whether the broker was flagged with
+// SetBrokersShuttingDown.
+func (p *producer) isShuttingDown(nodeID int32) bool {
+	flagged, _ := p.shuttingDown.Load().(map[int32]struct{})
+	_, ok := flagged[nodeID]
+	return ok
+}
+
 // BufferedProduceBytes returns the number of bytes currently buffered for
 // producing within the client. This is the sum of all keys, values, and header
 // keys/values. See the related [BufferedProduceRecords] for more information.
@@ -180,6 +269,8 @@ func (p *producer) init(cl *Client) {
 		err:   errReloadProducerID,
 	})
 	p.c = sync.NewCond(&p.mu)
+	p.tsAnchor = time.Now()
+	p.txnID.Store(cl.cfg.txnID)
 
 	inithooks := func() {
 		if p.hooks == nil {
@@ -187,6 +278,8 @@ func (p *producer) init(cl *Client) {
 				buffered    []HookProduceRecordBuffered
 				partitioned []HookProduceRecordPartitioned
 				unbuffered  []HookProduceRecordUnbuffered
+				skew        []HookProduceTimestampSkew
+				acked       []HookProduceBatchAcked
 			}{}
 		}
 	}
@@ -207,9 +300,33 @@ func (p *producer) init(cl *Client) {
 		if _, ok := h.(HookProduceBatchWritten); ok {
 			p.hasHookBatchWritten = true
 		}
+		if _, ok := h.(HookProduceFlushProgress); ok {
+			p.hasHookFlushProgress = true
+		}
+		if h, ok := h.(HookProduceTimestampSkew); ok {
+			inithooks()
+			p.hooks.skew = append(p.hooks.skew, h)
+		}
+		if h, ok := h.(HookProduceBatchAcked); ok {
+			inithooks()
+			p.hooks.acked = append(p.hooks.acked, h)
+		}
 	})
 }
 
+// hookTimestampSkew calls HookProduceTimestampSkew hooks for a batch written
+// to a LogAppendTime topic.
+func (p *producer) hookTimestampSkew(meta BrokerMetadata, topic string, partition int32, logAppendTime int64, batch *recBatch) {
+	if p.hooks == nil || len(p.hooks.skew) == 0 {
+		return
+	}
+	maxTimestamp := batch.firstTimestamp + batch.maxTimestampDelta
+	skew := time.Duration(logAppendTime-maxTimestamp) * time.Millisecond
+	for _, h := range p.hooks.skew {
+		h.OnProduceTimestampSkew(meta, topic, partition, skew)
+	}
+}
+
 func (p *producer) purgeTopics(topics []string) {
 	p.topicsMu.Lock()
 	defer p.topicsMu.Unlock()
@@ -221,7 +338,7 @@ func (p *producer) purgeTopics(topics []string) {
 			close(unknown.wait)
 			p.promiseBatch(batchPromise{
 				recs: unknown.buffered,
-				err:  errPurged,
+				err:  ErrPurged,
 			})
 		}
 	}
@@ -259,7 +376,7 @@ func (p *producer) purgeTopics(topics []string) {
 			go func() {
 				r.mu.Lock()
 				defer r.mu.Unlock()
-				r.failAllRecords(errPurged)
+				r.failAllRecords(ErrPurged)
 			}()
 		}
 	}
@@ -421,14 +538,21 @@ func (cl *Client) TryProduce(
 //
 // Once a record is buffered into a batch, it can be canceled in three ways:
 // canceling the context, the record timing out, or hitting the maximum
-// retries. If any of these conditions are hit and it is currently safe to fail
-// records, all buffered records for the relevant partition are failed. Only
-// the first record's context in a batch is considered when determining whether
-// the batch should be canceled. A record is not safe to fail if the client
-// is idempotently producing and a request has been sent; in this case, the
-// client cannot know if the broker actually processed the request (if so, then
-// removing the records from the client will create errors the next time you
-// produce).
+// retries. The context used is both the context passed to Produce and the
+// record's Context field (which defaults to the Produce context); canceling
+// either cancels the record. Before a batch is first sent, any records in it
+// with a canceled context are individually failed with the context's error and
+// removed from the batch; other records are unaffected. This allows
+// request-scoped produces to not outlive the request that triggered them.
+//
+// Once a batch has been sent, its records can no longer be removed
+// individually. If the first record in the first batch for a partition times
+// out or is canceled, or the batch hits the maximum retries, and it is
+// currently safe to fail records, all buffered records for the partition are
+// failed. A record is not safe to fail if the client is idempotently producing
+// and a request has been sent; in this case, the client cannot know if the
+// broker actually processed the request (if so, then removing the records from
+// the client will create errors the next time you produce).
 //
 // If the client is transactional and a transaction has not been begun, the
 // promise is immediately called with an error corresponding to not being in a
@@ -460,6 +584,19 @@ func (cl *Client) produce(
 		r.Topic = cl.cfg.defaultProduceTopic
 	}
 
+	if r.Timestamp.IsZero() {
+		switch cl.cfg.timestampSource {
+		case TimestampAtProduce():
+			r.Timestamp = time.Now()
+		case TimestampMonotonic():
+			r.Timestamp = cl.producer.tsAnchor.Add(time.Since(cl.producer.tsAnchor))
+		}
+	}
+
+	if cl.cfg.e2eTimestamps {
+		setEndToEndHeader(r, time.Now())
+	}
+
 	p := &cl.producer
 	if p.hooks != nil && len(p.hooks.buffered) > 0 {
 		for _, h := range p.hooks.buffered {
@@ -469,17 +606,38 @@ func (cl *Client) produce(
 
 	// We can now fail the rec after the buffered hook.
 	if r.Topic == "" {
-		p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, errNoTopic)
+		p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, ErrNoTopic)
+		return
+	}
+	if !cl.cfg.canProduceTo(r.Topic) {
+		p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, ErrTopicNotAllowed)
+		return
+	}
+	if r.Timestamp.IsZero() && cl.cfg.timestampSource == TimestampUserOnly() {
+		p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, ErrMissingTimestamp)
 		return
 	}
 	if cl.cfg.txnID != nil && !p.producingTxn.Load() {
-		p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, errNotInTransaction)
+		p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, ErrNotInTransaction)
 		return
 	}
+	if cl.cfg.offloader != nil && cl.cfg.offloadThreshold > 0 && len(r.Value) > cl.cfg.offloadThreshold {
+		var err error
+		if promise, err = cl.offloadPayload(r, promise); err != nil {
+			p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, err)
+			return
+		}
+	}
+	if cl.cfg.validateTopicMaxBytes {
+		if err := cl.checkTopicMaxBytes(r); err != nil {
+			p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, err)
+			return
+		}
+	}
 
 	userSize := r.userSize()
 	if cl.cfg.maxBufferedBytes > 0 && userSize > cl.cfg.maxBufferedBytes {
-		p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, kerr.MessageTooLarge)
+		p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, kerr.MessageTooLarge)
 		return
 	}
 
@@ -502,7 +660,7 @@ func (cl *Client) produce(
 	if overMaxRecs || overMaxBytes {
 		if !block || cl.cfg.manualFlushing {
 			p.mu.Unlock()
-			p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, ErrMaxBuffered)
+			p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, ErrMaxBuffered)
 			return
 		}
 
@@ -560,7 +718,7 @@ func (cl *Client) produce(
 			}()
 			<-wait // we wait for the goroutine to exit, then unlock again (since the goroutine leaves the mutex locked)
 			p.mu.Unlock()
-			p.promiseRecordBeforeBuf(promisedRec{ctx, promise, r}, err)
+			p.promiseRecordBeforeBuf(promisedRec{ctx: ctx, promise: promise, Record: r}, err)
 		}
 
 		select {
@@ -579,8 +737,15 @@ func (cl *Client) produce(
 	p.bufferedRecords = nextBufRecs
 	p.bufferedBytes = nextBufBytes
 	p.mu.Unlock()
+	p.topicBuffered.loadOrCreate(r.Topic).Add(1)
+
+	// We only tee once the record passed every local check and is
+	// buffered, so that records we reject are not copied.
+	if cl.cfg.shouldTee(r.Topic) {
+		promise = cl.tee(r, promise)
+	}
 
-	cl.loadPartsAndPartition(promisedRec{ctx, promise, r})
+	cl.loadPartsAndPartition(promisedRec{ctx: ctx, promise: promise, Record: r})
 }
 
 type batchPromise struct {
@@ -589,6 +754,7 @@ type batchPromise struct {
 	epoch      int16
 	attrs      RecordAttrs
 	beforeBuf  bool
+	batch      bool // whether recs are an entire record batch, for HookProduceBatchAcked
 	partition  int32
 	recs       []promisedRec
 	err        error
@@ -618,6 +784,18 @@ func (p *producer) finishPromises(b batchPromise) {
 		}
 	}()
 start:
+	// We call batch hooks before record promises, so that a Flush
+	// waiting for all promises does not return before the hooks are
+	// called.
+	if b.batch && len(b.recs) > 0 && p.hooks != nil && len(p.hooks.acked) > 0 {
+		baseOffset := b.baseOffset
+		if b.err != nil {
+			baseOffset = -1
+		}
+		for _, h := range p.hooks.acked {
+			h.OnProduceBatchAcked(b.recs[0].Topic, b.partition, baseOffset, len(b.recs), b.err)
+		}
+	}
 	for i, pr := range b.recs {
 		pr.LeaderEpoch = 0
 		if b.baseOffset == -1 {
@@ -631,7 +809,12 @@ start:
 		pr.ProducerID = b.pid
 		pr.ProducerEpoch = b.epoch
 		pr.Attrs = b.attrs
-		recBroadcast := cl.finishRecordPromise(pr, b.err, b.beforeBuf)
+		var recBroadcast bool
+		if pr.order != nil {
+			recBroadcast = cl.finishOrderedRecordPromise(pr, b.err, b.beforeBuf)
+		} else {
+			recBroadcast = cl.finishRecordPromise(pr, b.err, b.beforeBuf)
+		}
 		broadcast = broadcast || recBroadcast
 		b.recs[i] = promisedRec{}
 	}
@@ -660,6 +843,7 @@ func (cl *Client) finishRecordPromise(pr promisedRec, err error, beforeBuffering
 	// allowing users of Flush to know all buf recs are done by the
 	// time we notify flush below.
 	userSize := pr.userSize()
+	topic := pr.Topic
 	pr.promise(pr.Record, err)
 
 	// If this record was never buffered, it's size was never accounted
@@ -672,12 +856,77 @@ func (cl *Client) finishRecordPromise(pr promisedRec, err error, beforeBuffering
 	p.mu.Lock()
 	p.bufferedBytes -= userSize
 	p.bufferedRecords--
-	broadcast = p.blocked.Load() > 0 || p.bufferedRecords == 0 && p.flushing.Load() > 0
+	topicBuffered := p.topicBuffered.loadOrCreate(topic).Add(-1)
+	broadcast = p.blocked.Load() > 0 ||
+		p.bufferedRecords == 0 && p.flushing.Load() > 0 ||
+		topicBuffered <= 0 && p.flushingTopicsN.Load() > 0
 	p.mu.Unlock()
 
 	return broadcast
 }
 
+// holdBuffered keeps a record counted as buffered after its promise returns,
+// until releaseBuffered. TeeProduceSync uses this when the original record
+// finishes before the tee, so that Flush waits for the user promise.
+func (p *producer) holdBuffered(topic string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.bufferedRecords++
+	p.topicBuffered.loadOrCreate(topic).Add(1)
+}
+
+func (p *producer) releaseBuffered(topic string) {
+	p.mu.Lock()
+	p.bufferedRecords--
+	topicBuffered := p.topicBuffered.loadOrCreate(topic).Add(-1)
+	broadcast := p.bufferedRecords == 0 && p.flushing.Load() > 0 ||
+		topicBuffered <= 0 && p.flushingTopicsN.Load() > 0
+	p.mu.Unlock()
+	if broadcast {
+		p.c.Broadcast()
+	}
+}
+
+// promiseOrder holds promises for OrderedProducePromises until every promise
+// before them in a partition has been called. This is only accessed in
+// finishPromises, which is never run concurrently.
+type promiseOrder struct {
+	done uint64 // seq of the last promise called
+	held map[uint64]heldPromise
+}
+
+type heldPromise struct {
+	pr        promisedRec
+	err       error
+	beforeBuf bool
+}
+
+// finishOrderedRecordPromise calls the promise for pr if it is next in its
+// partition, along with any held promises that are next after it. If pr is not
+// next, it is held.
+func (cl *Client) finishOrderedRecordPromise(pr promisedRec, err error, beforeBuffering bool) (broadcast bool) {
+	o := pr.order
+	if pr.seq != o.done+1 {
+		if o.held == nil {
+			o.held = make(map[uint64]heldPromise)
+		}
+		o.held[pr.seq] = heldPromise{pr, err, beforeBuffering}
+		return false
+	}
+	broadcast = cl.finishRecordPromise(pr, err, beforeBuffering)
+	o.done++
+	for {
+		h, ok := o.held[o.done+1]
+		if !ok {
+			return broadcast
+		}
+		delete(o.held, o.done+1)
+		recBroadcast := cl.finishRecordPromise(h.pr, h.err, h.beforeBuf)
+		broadcast = broadcast || recBroadcast
+		o.done++
+	}
+}
+
 // loadPartsAndPartition loads the partitions for a topic and produce to them.
 // If the topic does not currently exist, the record is buffered in
 // unknownTopics for a metadata update to deal with.
@@ -795,8 +1044,8 @@ func (cl *Client) producerID(ctxFn func() context.Context) (int64, int16, error)
 		defer p.idMu.Unlock()
 
 		if id = p.id.Load().(*producerID); errors.Is(id.err, errReloadProducerID) {
-			if cl.cfg.disableIdempotency {
-				cl.cfg.logger.Log(LogLevelInfo, "skipping producer id initialization because the client was configured to disable idempotent writes")
+			if cl.cfg.disableIdempotency || cl.cfg.produceDryRun {
+				cl.cfg.logger.Log(LogLevelInfo, "skipping producer id initialization because the client was configured to disable idempotent writes or is in produce dry run mode")
 				id = &producerID{
 					id:    -1,
 					epoch: -1,
@@ -833,7 +1082,7 @@ func (cl *Client) producerID(ctxFn func() context.Context) (int64, int16, error)
 					id = &producerID{
 						id:    id.id,
 						epoch: id.epoch,
-						err:   &errProducerIDLoadFail{newID.err},
+						err:   &ErrProducerIDLoadFail{newID.err},
 					}
 				}
 			}
@@ -862,6 +1111,13 @@ func (cl *Client) resetAllProducerSequences() {
 	}
 }
 
+// idReplaced returns the current producer ID and epoch, and whether they
+// differ from the given ID and epoch.
+func (p *producer) idReplaced(id int64, epoch int16) (int64, int16, bool) {
+	current := p.id.Load().(*producerID)
+	return current.id, current.epoch, current.id != id || current.epoch != epoch
+}
+
 func (cl *Client) failProducerID(id int64, epoch int16, err error) {
 	p := &cl.producer
 
@@ -908,12 +1164,64 @@ func (cl *Client) failProducerID(id int64, epoch int16, err error) {
 	}
 }
 
+// ResetProducerID resets the idempotent producer ID, even if it has fatally
+// failed, and then loads a new one: the client bumps the epoch of the current
+// ID if possible, and otherwise initializes a new ID. Sequence numbers for all
+// partitions are reset.
+//
+// A producer ID fatally fails if the client uses StopProducerOnDataLossDetected
+// and detects data loss. Resetting allows you to continue producing once you
+// have decided how to handle the loss, without recreating the client. This
+// cannot be used with a transactional producer; transactional producers
+// recover when ending a transaction.
+//
+// This should be called when no records are being produced, and returns the
+// error from loading the new producer ID, if any.
+func (cl *Client) ResetProducerID(ctx context.Context) error {
+	if cl.cfg.txnID != nil {
+		return errors.New("ResetProducerID cannot be used with a transactional producer")
+	}
+
+	p := &cl.producer
+	p.idMu.Lock()
+	id := p.id.Load().(*producerID)
+	p.id.Store(&producerID{
+		id:    id.id,
+		epoch: id.epoch,
+		err:   errReloadProducerID,
+	})
+	p.idMu.Unlock()
+
+	cl.cfg.logger.Log(LogLevelInfo, "resetting producer id as requested", "producer_id", id.id, "producer_epoch", id.epoch, "current_err", id.err)
+	cl.hookProducerIDRecovery(ProducerIDResetManual, "", -1, id.id, id.epoch, id.err)
+
+	_, _, err := cl.ProducerID(ctx)
+	return err
+}
+
+func (cl *Client) hookProducerIDRecovery(action ProducerIDRecoveryAction, topic string, partition int32, id int64, epoch int16, err error) {
+	cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookProducerIDRecovery); ok {
+			h.OnProducerIDRecovery(ProducerIDRecovery{
+				Action:        action,
+				Topic:         topic,
+				Partition:     partition,
+				ProducerID:    id,
+				ProducerEpoch: epoch,
+				Err:           err,
+
+				DuplicatesPossible: action == ProducerIDResetDataRisk || action == ProducerIDResetManual,
+			})
+		}
+	})
+}
+
 // doInitProducerID inits the idempotent ID and potentially the transactional
 // producer epoch, returning whether to keep the result.
 func (cl *Client) doInitProducerID(ctxFn func() context.Context, lastID int64, lastEpoch int16) (*producerID, bool) {
 	cl.cfg.logger.Log(LogLevelInfo, "initializing producer id")
 	req := kmsg.NewPtrInitProducerIDRequest()
-	req.TransactionalID = cl.cfg.txnID
+	req.TransactionalID = cl.producer.txnID.Load()
 	req.ProducerID = lastID
 	req.ProducerEpoch = lastEpoch
 	if cl.cfg.txnID != nil {
@@ -1063,7 +1371,7 @@ func (cl *Client) waitUnknownTopic(
 	// Ordering: aborting is set first, then unknown topics are manually
 	// canceled in a lock. New unknown topics after that lock will see
 	// aborting here and immediately cancel themselves.
-	if cl.producer.isAborting() {
+	if cl.producer.isAborting() || cl.producer.isAbortingTopic(topic) {
 		err = ErrAborting
 	}
 
@@ -1115,6 +1423,7 @@ func (cl *Client) waitUnknownTopic(
 	cl.cfg.logger.Log(LogLevelInfo, "new topic metadata wait failed, done retrying, failing all records", "topic", topic, "err", err)
 
 	delete(p.unknownTopics, topic)
+	p.countAborted(topic, -1, len(unknown.buffered), err)
 	p.promiseBatch(batchPromise{
 		recs: unknown.buffered,
 		err:  err,
@@ -1122,7 +1431,7 @@ func (cl *Client) waitUnknownTopic(
 }
 
 func (cl *Client) unlingerDueToMaxRecsBuffered() {
-	if cl.cfg.linger <= 0 {
+	if cl.cfg.linger.load() <= 0 {
 		return
 	}
 	for _, parts := range cl.producer.topics.load() {
@@ -1136,6 +1445,9 @@ func (cl *Client) unlingerDueToMaxRecsBuffered() {
 // Flush hangs waiting for all buffered records to be flushed, stopping all
 // lingers if necessary.
 //
+// Brokers flagged with SetBrokersShuttingDown are drained first, and the
+// per broker drain progress is reported to any HookProduceFlushProgress hooks.
+//
 // If the context finishes (Done), this returns the context's error.
 //
 // This function is safe to call multiple times concurrently, and safe to call
@@ -1155,7 +1467,15 @@ func (cl *Client) Flush(ctx context.Context) error {
 	// linger because the producer's flushing atomic int32 is nonzero. We
 	// must wake anything that could be lingering up, after which all sinks
 	// will loop draining.
-	if cl.cfg.linger > 0 || cl.cfg.manualFlushing {
+	//
+	// Brokers flagged as shutting down are not lingering, but we drain
+	// them first to begin their produce requests first.
+	if cl.cfg.linger.load() > 0 || cl.cfg.manualFlushing {
+		cl.allSinksAndSources(func(sns sinkAndSource) {
+			if p.isShuttingDown(sns.sink.nodeID) {
+				sns.sink.unlingerAndDrain()
+			}
+		})
 		for _, parts := range p.topics.load() {
 			for _, part := range parts.load().partitions {
 				part.records.unlingerAndManuallyDrain()
@@ -1175,6 +1495,105 @@ func (cl *Client) Flush(ctx context.Context) error {
 		}
 	}()
 
+	var progress <-chan time.Time
+	if p.hasHookFlushProgress {
+		cl.hookFlushProgress()
+		defer cl.hookFlushProgress()
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		progress = ticker.C
+	}
+
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-progress:
+			cl.hookFlushProgress()
+		case <-ctx.Done():
+			p.mu.Lock()
+			quit = true
+			p.mu.Unlock()
+			p.c.Broadcast()
+			return ctx.Err()
+		}
+	}
+}
+
+// hookFlushProgress calls HookProduceFlushProgress hooks with the records
+// currently buffered per broker.
+func (cl *Client) hookFlushProgress() {
+	remaining := cl.BufferedProduceRecordsByBroker()
+	cl.cfg.hooks.each(func(h Hook) {
+		if h, ok := h.(HookProduceFlushProgress); ok {
+			h.OnProduceFlushProgress(remaining)
+		}
+	})
+}
+
+// FlushTopic hangs waiting for all buffered records for the given topics to be
+// flushed, stopping lingers for partitions of those topics if necessary. Unlike
+// Flush, this does not wait for records to other topics, which can be useful
+// as a barrier for one tenant in a producer shared across many tenants. If no
+// topics are given, this returns immediately.
+//
+// Records that are blocked in Produce waiting for space to be buffered (see
+// MaxBufferedRecords and MaxBufferedBytes) are not waited on. If the client
+// uses ManualFlushing, only partitions for the given topics are drained while
+// this is flushing.
+//
+// If the context finishes (Done), this returns the context's error.
+//
+// This function is safe to call multiple times concurrently, and safe to call
+// concurrent with Flush.
+func (cl *Client) FlushTopic(ctx context.Context, topics ...string) error {
+	if len(topics) == 0 {
+		return nil
+	}
+	p := &cl.producer
+
+	// Same as Flush, we mark the topics as flushing before unlingering so
+	// that nothing for these topics starts lingering again.
+	p.addFlushingTopics(topics)
+	defer p.removeFlushingTopics(topics)
+
+	cl.cfg.logger.Log(LogLevelInfo, "flushing topics", "topics", topics)
+	defer cl.cfg.logger.Log(LogLevelDebug, "flushed topics", "topics", topics)
+
+	if cl.cfg.linger.load() > 0 || cl.cfg.manualFlushing {
+		tps := p.topics.load()
+		for _, topic := range topics {
+			parts, exists := tps[topic]
+			if !exists {
+				continue
+			}
+			for _, part := range parts.load().partitions {
+				part.records.unlingerAndManuallyDrain()
+			}
+		}
+	}
+
+	buffered := func() (n int64) {
+		for _, topic := range topics {
+			if buffered := p.topicBuffered.load(topic); buffered != nil {
+				n += buffered.Load()
+			}
+		}
+		return n
+	}
+
+	quit := false
+	done := make(chan struct{})
+	go func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		defer close(done)
+
+		for !quit && buffered() > 0 {
+			p.c.Wait()
+		}
+	}()
+
 	select {
 	case <-done:
 		return nil
@@ -1187,6 +1606,76 @@ func (cl *Client) Flush(ctx context.Context) error {
 	}
 }
 
+// topicBuffered counts buffered records per topic. Counts are atomic and the
+// map is copy-on-write, so that producing only takes a lock the first time a
+// topic is produced to.
+type topicBuffered struct {
+	mu sync.Mutex
+	v  atomic.Value // map[string]*atomicI64
+}
+
+func (t *topicBuffered) load(topic string) *atomicI64 {
+	m, _ := t.v.Load().(map[string]*atomicI64)
+	return m[topic]
+}
+
+func (t *topicBuffered) loadOrCreate(topic string) *atomicI64 {
+	if n := t.load(topic); n != nil {
+		return n
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m, _ := t.v.Load().(map[string]*atomicI64)
+	if n := m[topic]; n != nil {
+		return n
+	}
+	next := make(map[string]*atomicI64, len(m)+1)
+	for topic, n := range m {
+		next[topic] = n
+	}
+	n := new(atomicI64)
+	next[topic] = n
+	t.v.Store(next)
+	return n
+}
+
+func (p *producer) addFlushingTopics(topics []string) {
+	p.flushingTopicsMu.Lock()
+	defer p.flushingTopicsMu.Unlock()
+	if p.flushingTopics == nil {
+		p.flushingTopics = make(map[string]int32)
+	}
+	for _, topic := range topics {
+		p.flushingTopics[topic]++
+	}
+	p.flushingTopicsN.Add(1)
+}
+
+func (p *producer) removeFlushingTopics(topics []string) {
+	p.flushingTopicsMu.Lock()
+	defer p.flushingTopicsMu.Unlock()
+	for _, topic := range topics {
+		if p.flushingTopics[topic]--; p.flushingTopics[topic] == 0 {
+			delete(p.flushingTopics, topic)
+		}
+	}
+	p.flushingTopicsN.Add(-1)
+}
+
+// isFlushing returns whether records for the topic are being flushed, either
+// through Flush or FlushTopic.
+func (p *producer) isFlushing(topic string) bool {
+	if p.flushing.Load() > 0 {
+		return true
+	}
+	if p.flushingTopicsN.Load() == 0 {
+		return false
+	}
+	p.flushingTopicsMu.Lock()
+	defer p.flushingTopicsMu.Unlock()
+	return p.flushingTopics[topic] > 0
+}
+
 // Bumps the tries for all buffered records in the client.
 //
 // This is called whenever there is a problematic error that would affect the
@@ -1,12 +1,17 @@
 package kgo
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
 	"errors"
 	"iter"
 	"reflect"
 	"time"
 	"unsafe"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
 )
 
 // RecordHeader contains extra information that can be sent with Records.
@@ -185,6 +190,78 @@ func (r *Record) AppendFormat(b []byte, layout string) ([]byte, error) {
 	return f.AppendRecord(b, r), nil
 }
 
+// ControlRecord is a decoded control record. Control records are only
+// returned from fetches when using the KeepControlRecords option.
+type ControlRecord struct {
+	// Version is the version of the control record key.
+	Version int16
+
+	// Type is the type of control record: ABORT and COMMIT mark the end
+	// of a transaction for the record's ProducerID. Other types are used
+	// internally in KRaft and should not be seen in normal topics.
+	Type kmsg.ControlRecordKeyType
+
+	// CoordinatorEpoch is the epoch of the transaction coordinator that
+	// wrote an ABORT or COMMIT marker, or -1 for other types.
+	CoordinatorEpoch int32
+}
+
+// IsCommit returns whether this is a transaction commit marker.
+func (c ControlRecord) IsCommit() bool { return c.Type == kmsg.ControlRecordKeyTypeCommit }
+
+// IsAbort returns whether this is a transaction abort marker.
+func (c ControlRecord) IsAbort() bool { return c.Type == kmsg.ControlRecordKeyTypeAbort }
+
+// ControlRecord decodes the record as a control record, returning false if
+// the record is not a control record or the key is malformed. This can be
+// used with KeepControlRecords to reconstruct transaction boundaries: an
+// ABORT or COMMIT marker ends the open transaction for the record's
+// ProducerID and ProducerEpoch in the record's partition.
+func (r *Record) ControlRecord() (ControlRecord, bool) {
+	if !r.Attrs.IsControl() || len(r.Key) < 4 {
+		return ControlRecord{}, false
+	}
+	c := ControlRecord{
+		Version:          int16(binary.BigEndian.Uint16(r.Key)),
+		Type:             kmsg.ControlRecordKeyType(int16(binary.BigEndian.Uint16(r.Key[2:]))),
+		CoordinatorEpoch: -1,
+	}
+	if (c.IsCommit() || c.IsAbort()) && len(r.Value) >= 6 {
+		c.CoordinatorEpoch = int32(binary.BigEndian.Uint32(r.Value[2:]))
+	}
+	return c, true
+}
+
+// Clone returns a deep copy of the record: the key, value, and headers are
+// copied into new memory that is not shared with the client or with any pool.
+// The clone is safe to retain and modify indefinitely, regardless of whether
+// the original is recycled or reused.
+//
+// If the record was taken from a pool (see [WithPools]), the clone is not
+// attached to the pool and calling Recycle on the clone is a no-op; the
+// original must still be recycled separately. Otherwise, the clone keeps the
+// record's Context.
+func (r *Record) Clone() *Record {
+	c := *r
+	c.Key = bytes.Clone(r.Key)
+	c.Value = bytes.Clone(r.Value)
+	if r.Headers != nil {
+		c.Headers = make([]RecordHeader, len(r.Headers))
+		for i, h := range r.Headers {
+			c.Headers[i] = RecordHeader{Key: h.Key, Value: bytes.Clone(h.Value)}
+		}
+	}
+	if r.isPooled() {
+		c.Context = nil
+	}
+	return &c
+}
+
+// isPooled returns whether the record is attached to any pool.
+func (r *Record) isPooled() bool {
+	return r.Context != nil && r.Context.Value(ctxRecRecycle) != nil
+}
+
 // StringRecord returns a Record with the Value field set to the input value
 // string. For producing, this function is useful in tandem with the
 // client-level DefaultProduceTopic option.
@@ -261,6 +338,15 @@ type FetchPartition struct {
 	// LogStartOffset is the low watermark of this partition, otherwise
 	// known as the earliest offset in the partition.
 	LogStartOffset int64
+	// AbortedTransactions is the number of aborted transactions the broker
+	// returned for this partition in the fetch response. This is only set
+	// when consuming with the ReadCommitted isolation level.
+	AbortedTransactions int
+	// AbortedRecords is the number of records in this fetch that were
+	// skipped because they were part of an aborted transaction. This does
+	// not include transaction markers, and is only set when consuming with
+	// the ReadCommitted isolation level.
+	AbortedRecords int
 	// Records contains feched records for this partition.
 	Records []*Record
 }
@@ -377,6 +463,76 @@ func (fs Fetches) Errors() []FetchError {
 	return errs
 }
 
+// TransientErrors returns all errors in a fetch that the client recovers from
+// automatically: retryable Kafka errors (if using KeepRetryableFetchErrors)
+// and *ErrDataLoss. These errors are worth logging, but do not require any
+// action.
+func (fs Fetches) TransientErrors() []FetchError {
+	var errs []FetchError
+	fs.EachError(func(t string, p int32, err error) {
+		if isTransientFetchErr(err) {
+			errs = append(errs, FetchError{t, p, err})
+		}
+	})
+	return errs
+}
+
+// FatalErrors returns all errors in a fetch that are not transient (see
+// TransientErrors), such as non-retryable Kafka errors, batch parse failures,
+// ErrClientClosed, context errors, and an ErrGroupSession wrapping a
+// non-retryable error. These errors likely require action, such as fixing
+// ACLs or exiting a poll loop.
+func (fs Fetches) FatalErrors() []FetchError {
+	var errs []FetchError
+	fs.EachError(func(t string, p int32, err error) {
+		if !isTransientFetchErr(err) {
+			errs = append(errs, FetchError{t, p, err})
+		}
+	})
+	return errs
+}
+
+func isTransientFetchErr(err error) bool {
+	var dl *ErrDataLoss
+	return kerr.IsRetriable(err) || errors.As(err, &dl)
+}
+
+// stripTransientErrors removes transient errors from fetches for
+// SuppressTransientFetchErrors, calling HookFetchErrorSuppressed for each
+// stripped error. Partitions and topics left empty are removed.
+func (cl *Client) stripTransientErrors(fs Fetches) Fetches {
+	keepFetches := fs[:0]
+	for _, f := range fs {
+		keepTopics := f.Topics[:0]
+		for _, t := range f.Topics {
+			keepParts := t.Partitions[:0]
+			for _, p := range t.Partitions {
+				if p.Err != nil && isTransientFetchErr(p.Err) {
+					cl.cfg.hooks.each(func(h Hook) {
+						if h, ok := h.(HookFetchErrorSuppressed); ok {
+							h.OnFetchErrorSuppressed(t.Topic, p.Partition, p.Err)
+						}
+					})
+					p.Err = nil
+					if len(p.Records) == 0 {
+						continue
+					}
+				}
+				keepParts = append(keepParts, p)
+			}
+			if len(keepParts) > 0 {
+				t.Partitions = keepParts
+				keepTopics = append(keepTopics, t)
+			}
+		}
+		if len(keepTopics) > 0 {
+			f.Topics = keepTopics
+			keepFetches = append(keepFetches, f)
+		}
+	}
+	return keepFetches
+}
+
 // When we fetch, it is possible for Kafka to reply with topics / partitions
 // that have no records and no errors. This will definitely happen outside of
 // fetch sessions, but may also happen at other times (for some reason).
@@ -609,6 +765,42 @@ func (fs Fetches) Records() []*Record {
 	return rs
 }
 
+// Detach returns a deep copy of the fetches where every record is cloned with
+// [Record.Clone], and then recycles every original record that was taken
+// from a pool (see [WithPools]). The returned fetches do not share any memory
+// with the client or with pools, and are safe to retain beyond the next poll.
+//
+// Detach transfers ownership: after calling Detach, the original fetches and
+// records must not be used. If you are not using pools, Detach is still safe
+// to use but only costs a copy: records from a client without pools already
+// do not share memory with the client once they are polled.
+func (fs Fetches) Detach() Fetches {
+	detached := make(Fetches, len(fs))
+	for i, f := range fs {
+		topics := make([]FetchTopic, len(f.Topics))
+		for j, t := range f.Topics {
+			partitions := make([]FetchPartition, len(t.Partitions))
+			for k, p := range t.Partitions {
+				rs := make([]*Record, len(p.Records))
+				for l, r := range p.Records {
+					rs[l] = r.Clone()
+				}
+				p.Records = rs
+				partitions[k] = p
+			}
+			t.Partitions = partitions
+			topics[j] = t
+		}
+		detached[i] = Fetch{Topics: topics}
+	}
+	fs.EachRecord(func(r *Record) {
+		if r.isPooled() {
+			r.Recycle()
+		}
+	})
+	return detached
+}
+
 // NumRecords returns the total number of records across all fetched partitions.
 func (fs Fetches) NumRecords() (n int) {
 	fs.EachPartition(func(p FetchTopicPartition) {
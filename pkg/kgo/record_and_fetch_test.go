@@ -0,0 +1,289 @@
+package kgo
+
+import (
+	"bytes"
+	"errors"
+	"hash/crc32"
+	"reflect"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+type suppressedHook func(string, int32, error)
+
+func (h suppressedHook) OnFetchErrorSuppressed(t string, p int32, err error) { h(t, p, err) }
+
+func TestFetchesErrorSeverity(t *testing.T) {
+	t.Parallel()
+
+	fatal := errors.New("batch parse failure")
+	dataLoss := &ErrDataLoss{Topic: "a", Partition: 1}
+	mk := func() Fetches {
+		return Fetches{
+			{Topics: []FetchTopic{
+				{Topic: "a", Partitions: []FetchPartition{
+					{Partition: 0, Err: kerr.NotLeaderForPartition},
+					{Partition: 1, Err: dataLoss, Records: []*Record{{Value: []byte("v")}}},
+					{Partition: 2, Err: kerr.TopicAuthorizationFailed},
+				}},
+				{Topic: "b", Partitions: []FetchPartition{
+					{Partition: 0, Err: &ErrGroupSession{Err: kerr.CoordinatorNotAvailable}},
+				}},
+			}},
+			{Topics: []FetchTopic{
+				{Topic: "c", Partitions: []FetchPartition{
+					{Partition: 0, Err: fatal},
+				}},
+			}},
+		}
+	}
+
+	fs := mk()
+	if got, exp := fs.TransientErrors(), []FetchError{
+		{"a", 0, kerr.NotLeaderForPartition},
+		{"a", 1, dataLoss},
+		{"b", 0, fs[0].Topics[1].Partitions[0].Err},
+	}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("transient: got %v != exp %v", got, exp)
+	}
+	if got, exp := fs.FatalErrors(), []FetchError{
+		{"a", 2, kerr.TopicAuthorizationFailed},
+		{"c", 0, fatal},
+	}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("fatal: got %v != exp %v", got, exp)
+	}
+
+	var suppressed int
+	cl := &Client{cfg: cfg{hooks: hooks{suppressedHook(func(string, int32, error) { suppressed++ })}}}
+	stripped := cl.stripTransientErrors(mk())
+	if suppressed != 3 {
+		t.Errorf("got %d suppressed errors != exp 3", suppressed)
+	}
+	if len(stripped.TransientErrors()) != 0 || len(stripped.FatalErrors()) != 2 {
+		t.Errorf("stripped fetches still has transient errors or lost fatal errors: %v", stripped.Errors())
+	}
+	if n := stripped.NumRecords(); n != 1 {
+		t.Errorf("got %d records after stripping != exp 1", n)
+	}
+	stripped.EachTopic(func(ft FetchTopic) {
+		if ft.Topic == "b" {
+			t.Error("topic with only a transient error was not stripped")
+		}
+	})
+}
+
+func TestProcessFetchPartitionReadCommitted(t *testing.T) {
+	t.Parallel()
+
+	batch := func(first int64, pid int64, attrs int16, keys ...[]byte) []byte {
+		var records []byte
+		for i, k := range keys {
+			r := kmsg.Record{OffsetDelta: int32(i), Key: k}
+			r.Length = int32(len(r.AppendTo(nil)) - 1)
+			records = r.AppendTo(records)
+		}
+		b := kmsg.RecordBatch{
+			FirstOffset:     first,
+			Magic:           2,
+			Attributes:      attrs,
+			LastOffsetDelta: int32(len(keys) - 1),
+			ProducerID:      pid,
+			NumRecords:      int32(len(keys)),
+			Records:         records,
+		}
+		b.Length = int32(len(b.AppendTo(nil)) - 12)
+		return b.AppendTo(nil)
+	}
+
+	var raw []byte
+	raw = append(raw, batch(0, 1, 0b0001_0000, []byte("a"), []byte("b"))...) // aborted txn
+	raw = append(raw, batch(2, 1, 0b0011_0000, []byte{0, 0, 0, 0})...)       // abort marker
+	raw = append(raw, batch(3, 2, 0b0001_0000, []byte("c"))...)              // committed txn
+	raw = append(raw, batch(4, 2, 0b0011_0000, []byte{0, 0, 0, 1})...)       // commit marker
+	raw = append(raw, batch(5, -1, 0, []byte("d"))...)                       // non txn
+
+	rp := kmsg.NewFetchResponseTopicPartition()
+	rp.HighWatermark = 6
+	rp.LastStableOffset = 6
+	rp.RecordBatches = raw
+	abort := kmsg.NewFetchResponseTopicPartitionAbortedTransaction()
+	abort.ProducerID = 1
+	abort.FirstOffset = 0
+	rp.AbortedTransactions = append(rp.AbortedTransactions, abort)
+
+	for _, test := range []struct {
+		level      IsolationLevel
+		expKeys    string
+		expTxns    int
+		expAborted int
+	}{
+		{ReadUncommitted(), "abcd", 0, 0},
+		{ReadCommitted(), "cd", 1, 2},
+	} {
+		fp, next := ProcessFetchPartition(ProcessFetchPartitionOpts{
+			IsolationLevel:       test.level,
+			DisableCRCValidation: true,
+		}, &rp, DefaultDecompressor(), nil)
+		if fp.Err != nil {
+			t.Fatalf("unexpected err: %v", fp.Err)
+		}
+		var keys string
+		for _, r := range fp.Records {
+			keys += string(r.Key)
+		}
+		if keys != test.expKeys || next != 6 {
+			t.Errorf("got keys %q next %d, exp %q 6", keys, next, test.expKeys)
+		}
+		if fp.AbortedTransactions != test.expTxns || fp.AbortedRecords != test.expAborted {
+			t.Errorf("got aborted txns %d records %d, exp %d %d", fp.AbortedTransactions, fp.AbortedRecords, test.expTxns, test.expAborted)
+		}
+	}
+
+	m := FetchReadCommittedMetrics{FetchOffset: 3, LastStableOffset: 3, HighWatermark: 10}
+	if !m.BlockedByOpenTransaction() {
+		t.Error("expected fetch at the LSO below the HWM to be blocked")
+	}
+	m.LastStableOffset = 10
+	if m.BlockedByOpenTransaction() {
+		t.Error("expected fetch with the LSO at the HWM to not be blocked")
+	}
+}
+
+func TestRecordControlRecord(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		r      Record
+		exp    ControlRecord
+		expOk  bool
+		commit bool
+		abort  bool
+	}{
+		{Record{Key: []byte{0, 0, 0, 1}, Value: []byte{0, 0, 0, 0, 0, 5}, Attrs: RecordAttrs{0b0011_0000}}, ControlRecord{0, kmsg.ControlRecordKeyTypeCommit, 5}, true, true, false},
+		{Record{Key: []byte{0, 0, 0, 0}, Value: []byte{0, 0, 0, 0, 0, 7}, Attrs: RecordAttrs{0b0011_0000}}, ControlRecord{0, kmsg.ControlRecordKeyTypeAbort, 7}, true, false, true},
+		{Record{Key: []byte{0, 0, 0, 3}, Attrs: RecordAttrs{0b0010_0000}}, ControlRecord{0, kmsg.ControlRecordKeyTypeSnapshotHeader, -1}, true, false, false},
+		{Record{Key: []byte{0, 0, 0, 1}}, ControlRecord{}, false, false, false},                         // not control
+		{Record{Key: []byte{0}, Attrs: RecordAttrs{0b0010_0000}}, ControlRecord{}, false, false, false}, // malformed
+	} {
+		got, ok := test.r.ControlRecord()
+		if ok != test.expOk || got != test.exp {
+			t.Errorf("got %v %v, exp %v %v", got, ok, test.exp, test.expOk)
+		}
+		if ok && (got.IsCommit() != test.commit || got.IsAbort() != test.abort) {
+			t.Errorf("%v: got commit %v abort %v, exp %v %v", got, got.IsCommit(), got.IsAbort(), test.commit, test.abort)
+		}
+	}
+}
+
+func TestCRC32CTable(t *testing.T) {
+	t.Parallel()
+
+	// hash/crc32 only uses SSE4.2 or the arm64 CRC instructions if the
+	// table is the package's own Castagnoli table.
+	if crc32c != crc32.MakeTable(crc32.Castagnoli) {
+		t.Error("crc32c is not the standard library Castagnoli table, checksums will not be hardware accelerated")
+	}
+	if got := crc32.Checksum([]byte("123456789"), crc32c); got != 0xe3069283 {
+		t.Errorf("got crc %x != exp e3069283", got)
+	}
+}
+
+func BenchmarkProcessFetchPartitionCRC(b *testing.B) {
+	const nrecs = 100
+	var records []byte
+	for i := range nrecs {
+		r := kmsg.Record{OffsetDelta: int32(i), Value: bytes.Repeat([]byte("v"), 1000)}
+		r.Length = int32(len(r.AppendTo(nil)) - 1)
+		records = r.AppendTo(records)
+	}
+	batch := kmsg.RecordBatch{
+		Magic:           2,
+		LastOffsetDelta: nrecs - 1,
+		ProducerID:      -1,
+		NumRecords:      nrecs,
+		Records:         records,
+	}
+	batch.Length = int32(len(batch.AppendTo(nil)) - 12)
+	batch.CRC = int32(crc32.Checksum(batch.AppendTo(nil)[21:], crc32c)) // crc covers attributes onward
+
+	rp := kmsg.NewFetchResponseTopicPartition()
+	rp.HighWatermark = nrecs
+	rp.RecordBatches = batch.AppendTo(nil)
+
+	for _, test := range []struct {
+		name    string
+		disable bool
+	}{
+		{"validate", false},
+		{"skip", true},
+	} {
+		b.Run(test.name, func(b *testing.B) {
+			b.SetBytes(int64(len(rp.RecordBatches)))
+			for i := 0; i < b.N; i++ {
+				fp, _ := ProcessFetchPartition(ProcessFetchPartitionOpts{
+					DisableCRCValidation: test.disable,
+				}, &rp, DefaultDecompressor(), nil)
+				if fp.Err != nil || len(fp.Records) != nrecs {
+					b.Fatalf("got %d records, err %v", len(fp.Records), fp.Err)
+				}
+			}
+		})
+	}
+}
+
+type putRecordsPool struct{ puts int }
+
+func (*putRecordsPool) GetRecords(n int) []Record { return make([]Record, n) }
+func (p *putRecordsPool) PutRecords([]Record)     { p.puts++ }
+
+func TestFetchesDetach(t *testing.T) {
+	t.Parallel()
+
+	pool := new(putRecordsPool)
+	recs := pool.GetRecords(2)
+	ps, ctx := recordPoolsCtx([]Pool{pool}, nil, recs)
+	ps.n.Add(2)
+	shared := []byte("k0v0hv0k1v1")
+	recs[0] = Record{Key: shared[0:2], Value: shared[2:4], Headers: []RecordHeader{{"h", shared[4:7]}}, Offset: 0, Context: ctx}
+	recs[1] = Record{Key: shared[7:9], Value: shared[9:11], Offset: 1, Context: ctx}
+
+	fs := Fetches{{Topics: []FetchTopic{{
+		Topic: "t",
+		Partitions: []FetchPartition{{
+			Partition:     3,
+			HighWatermark: 2,
+			Records:       []*Record{&recs[0], &recs[1]},
+		}},
+	}}}}
+	exp := []Record{
+		{Key: []byte("k0"), Value: []byte("v0"), Headers: []RecordHeader{{"h", []byte("hv0")}}, Offset: 0},
+		{Key: []byte("k1"), Value: []byte("v1"), Offset: 1},
+	}
+
+	detached := fs.Detach()
+	if pool.puts != 1 {
+		t.Errorf("got %d pool puts after detaching != exp 1", pool.puts)
+	}
+	for i := range shared {
+		shared[i] = 0 // the clones must not share memory with the original buffer
+	}
+
+	p := detached[0].Topics[0].Partitions[0]
+	if p.Partition != 3 || p.HighWatermark != 2 || detached[0].Topics[0].Topic != "t" {
+		t.Errorf("got unexpected detached partition metadata %+v", p)
+	}
+	if len(p.Records) != len(exp) {
+		t.Fatalf("got %d detached records != exp %d", len(p.Records), len(exp))
+	}
+	for i, r := range p.Records {
+		if !reflect.DeepEqual(*r, exp[i]) {
+			t.Errorf("record %d: got %+v != exp %+v", i, *r, exp[i])
+		}
+		r.Recycle() // no-op: clones are not pooled
+	}
+	if pool.puts != 1 {
+		t.Errorf("got %d pool puts after recycling clones != exp 1", pool.puts)
+	}
+}
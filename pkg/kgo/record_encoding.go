@@ -0,0 +1,137 @@
+package kgo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONRecord returns a Record with the Value field set to the JSON encoding
+// of v. For producing, this function is useful in tandem with the
+// client-level DefaultProduceTopic option.
+func JSONRecord(v any) (*Record, error) {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Record{Value: value}, nil
+}
+
+// KeyJSONRecord returns a Record with the Key field set to key and the Value
+// field set to the JSON encoding of v.
+func KeyJSONRecord(key []byte, v any) (*Record, error) {
+	r, err := JSONRecord(v)
+	if err != nil {
+		return nil, err
+	}
+	r.Key = key
+	return r, nil
+}
+
+// CSVRecord returns a Record with the Value field set to fields encoded as a
+// single CSV line, without a trailing newline. Fields containing commas,
+// quotes, or newlines are quoted.
+func CSVRecord(fields ...string) *Record {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Write(fields) //nolint:errcheck // writing to a bytes.Buffer cannot fail
+	w.Flush()
+	return &Record{Value: bytes.TrimSuffix(buf.Bytes(), []byte("\n"))}
+}
+
+// RecordDecodeError is an error decoding a record in DecodeJSONEach,
+// DecodeCSVEach, or TypedClient.PollTyped.
+type RecordDecodeError struct {
+	Record *Record // Record is the record that failed to decode.
+	Err    error   // Err is the decode error.
+}
+
+func (e *RecordDecodeError) Error() string {
+	return fmt.Sprintf("unable to decode record at %s[%d] offset %d: %v", e.Record.Topic, e.Record.Partition, e.Record.Offset, e.Err)
+}
+
+func (e *RecordDecodeError) Unwrap() error { return e.Err }
+
+// RecordDecodeErrors is every record that failed to decode in one call to
+// DecodeJSONEach or DecodeCSVEach, in the order the records were fetched.
+type RecordDecodeErrors []*RecordDecodeError
+
+func (es RecordDecodeErrors) Error() string {
+	if len(es) == 1 {
+		return es[0].Error()
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "unable to decode %d records: ", len(es))
+	for i, e := range es {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(e.Error())
+	}
+	return sb.String()
+}
+
+// Unwrap returns the individual decode errors, allowing errors.Is and
+// errors.As to inspect every failed record.
+func (es RecordDecodeErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
+// DecodeJSONEach unmarshals the value of every record in fs into a new T and
+// calls fn with the record and the decoded value. Records that fail to
+// unmarshal are skipped and collected into the returned RecordDecodeErrors;
+// one bad record does not stop the remaining records from being processed.
+// If every record decodes, this returns nil.
+//
+//	err := kgo.DecodeJSONEach(fetches, func(r *kgo.Record, e Event) {
+//	        handle(e)
+//	})
+func DecodeJSONEach[T any](fs Fetches, fn func(*Record, T)) error {
+	var errs RecordDecodeErrors
+	fs.EachRecord(func(r *Record) {
+		var v T
+		if err := json.Unmarshal(r.Value, &v); err != nil {
+			errs = append(errs, &RecordDecodeError{r, err})
+			return
+		}
+		fn(r, v)
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// DecodeCSVEach parses the value of every record in fs as a single CSV line
+// and calls fn with the record and the fields. Records that fail to parse, or
+// that contain more than one line, are skipped and collected into the
+// returned RecordDecodeErrors. If every record decodes, this returns nil.
+func DecodeCSVEach(fs Fetches, fn func(*Record, []string)) error {
+	var errs RecordDecodeErrors
+	fs.EachRecord(func(r *Record) {
+		cr := csv.NewReader(bytes.NewReader(r.Value))
+		cr.FieldsPerRecord = -1
+		fields, err := cr.Read()
+		if err == nil {
+			if _, rerr := cr.Read(); rerr == nil {
+				err = errors.New("value contains more than one CSV line")
+			}
+		}
+		if err != nil {
+			errs = append(errs, &RecordDecodeError{r, err})
+			return
+		}
+		fn(r, fields)
+	})
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
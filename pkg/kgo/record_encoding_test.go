@@ -0,0 +1,86 @@
+package kgo
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeJSONEach(t *testing.T) {
+	t.Parallel()
+
+	type event struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	good, err := JSONRecord(event{1, "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	good2, err := KeyJSONRecord([]byte("k"), event{2, "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(good2.Key) != "k" || string(good2.Value) != `{"id":2,"name":"b"}` {
+		t.Errorf("got unexpected keyed record %q => %q", good2.Key, good2.Value)
+	}
+	if _, err := JSONRecord(make(chan int)); err == nil {
+		t.Error("expected error marshaling a channel")
+	}
+
+	bad := &Record{Topic: "t", Partition: 1, Offset: 7, Value: []byte("{")}
+	fs := Fetches{{Topics: []FetchTopic{{Topic: "t", Partitions: []FetchPartition{
+		{Partition: 0, Records: []*Record{good, bad, good2}},
+	}}}}}
+
+	var got []event
+	err = DecodeJSONEach(fs, func(_ *Record, e event) { got = append(got, e) })
+	if exp := []event{{1, "a"}, {2, "b"}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+	var des RecordDecodeErrors
+	if !errors.As(err, &des) || len(des) != 1 || des[0].Record != bad {
+		t.Fatalf("got unexpected error %v", err)
+	}
+	var syntax *json.SyntaxError
+	if !errors.As(err, &syntax) {
+		t.Errorf("expected to unwrap to the json syntax error, got %v", err)
+	}
+	if exp := "unable to decode record at t[1] offset 7: unexpected end of JSON input"; err.Error() != exp {
+		t.Errorf("got error string %q != exp %q", err.Error(), exp)
+	}
+
+	if err := DecodeJSONEach(Fetches{}, func(*Record, event) {}); err != nil {
+		t.Errorf("got unexpected error on no records: %v", err)
+	}
+}
+
+func TestDecodeCSVEach(t *testing.T) {
+	t.Parallel()
+
+	r := CSVRecord("a", "b,c", `d"e`)
+	if exp := `a,"b,c","d""e"`; string(r.Value) != exp {
+		t.Errorf("got %q != exp %q", r.Value, exp)
+	}
+
+	fs := Fetches{{Topics: []FetchTopic{{Topic: "t", Partitions: []FetchPartition{
+		{Partition: 0, Records: []*Record{
+			r,
+			{Value: []byte("x\ny")},
+			{Value: []byte(`"unterminated`)},
+			CSVRecord("z"),
+		}},
+	}}}}}
+
+	var got [][]string
+	err := DecodeCSVEach(fs, func(_ *Record, fields []string) { got = append(got, fields) })
+	if exp := [][]string{{"a", "b,c", `d"e`}, {"z"}}; !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+	var des RecordDecodeErrors
+	if !errors.As(err, &des) || len(des) != 2 {
+		t.Errorf("got unexpected error %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+type routeKind uint8
+
+const (
+	routeController routeKind = iota
+	routeCoordinator
+	routeBroker
+	routeAllBrokers
+)
+
+// RequestRoute is a routing hint for RequestRouted, overriding where the
+// client would otherwise send a request. This is useful for custom admin
+// tooling that issues requests the client does not know how to route, or that
+// needs a request to go somewhere specific (for example, describing configs on
+// every broker).
+type RequestRoute struct {
+	kind   routeKind
+	typ    int8   // coordinator type, for routeCoordinator
+	key    string // group or transactional ID, for routeCoordinator
+	broker int32  // for routeBroker
+	merge  func([]ResponseShard) (kmsg.Response, error)
+}
+
+// RouteController routes a request to the cluster controller. If the broker
+// replies NOT_CONTROLLER, the client reloads the controller and retries.
+func RouteController() RequestRoute {
+	return RequestRoute{kind: routeController}
+}
+
+// RouteGroupCoordinator routes a request to the coordinator for the given
+// group. If the response has a top level error code indicating the coordinator
+// moved, the client reloads the coordinator and retries.
+func RouteGroupCoordinator(group string) RequestRoute {
+	return RequestRoute{kind: routeCoordinator, typ: coordinatorTypeGroup, key: group}
+}
+
+// RouteTxnCoordinator routes a request to the coordinator for the given
+// transactional ID. If the response has a top level error code indicating the
+// coordinator moved, the client reloads the coordinator and retries.
+func RouteTxnCoordinator(txnID string) RequestRoute {
+	return RequestRoute{kind: routeCoordinator, typ: coordinatorTypeTxn, key: txnID}
+}
+
+// RouteBroker routes a request to the given broker, retrying on retryable
+// connection errors. This is similar to Broker(id).RetriableRequest.
+func RouteBroker(id int32) RequestRoute {
+	return RequestRoute{kind: routeBroker, broker: id}
+}
+
+// RouteAllBrokers routes a copy of a request to every broker in the cluster,
+// using merge to merge all response shards into one response for
+// RequestRouted. If merge is nil, RequestRouted returns the first successful
+// response by broker ID, or the first error if no request succeeded. See
+// RequestAll to receive every shard directly.
+func RouteAllBrokers(merge func([]ResponseShard) (kmsg.Response, error)) RequestRoute {
+	return RequestRoute{kind: routeAllBrokers, merge: merge}
+}
+
+// RequestRouted issues a request to where route specifies, rather than where
+// Request would send it. All requests are retried according to the client's
+// retry options, and the passed context can be used to cancel a request.
+//
+// Unlike Request, this does not split requests that must be split across
+// brokers; the request is issued as is (or, for RouteAllBrokers, copied as
+// is). The response is returned as is, meaning any error codes within the
+// response must be checked by the caller.
+func (cl *Client) RequestRouted(ctx context.Context, route RequestRoute, req kmsg.Request) (kmsg.Response, error) {
+	if route.kind == routeAllBrokers {
+		shards := cl.RequestAll(ctx, req)
+		if route.merge != nil {
+			return route.merge(shards)
+		}
+		var firstErr error
+		for _, shard := range shards {
+			if shard.Err == nil {
+				return shard.Resp, nil
+			}
+			if firstErr == nil {
+				firstErr = shard.Err
+			}
+		}
+		return nil, firstErr
+	}
+
+	ctx, cancel := cl.requestCtx(ctx)
+	defer cancel()
+
+	var rs ResponseShard
+	switch route.kind {
+	case routeController:
+		rs = cl.handleAdminReq(ctx, req)
+	case routeCoordinator:
+		rs = cl.handleCoordinatorReqSimple(ctx, route.typ, route.key, req)
+	case routeBroker:
+		r := cl.retryableBrokerFn(func() (*broker, error) {
+			return cl.brokerOrErr(ctx, route.broker, errUnknownBroker)
+		})
+		resp, err := r.Request(ctx, req)
+		rs = shard(r.last, req, resp, err)
+	default:
+		return nil, errors.New("unknown request route")
+	}
+	return rs.Resp, rs.Err
+}
+
+// RequestAll issues a copy of the request to every broker in the cluster and
+// returns every response shard, sorted by broker ID. Each broker is sent a
+// shallow copy of the request, so that each copy can be sent at the version
+// the broker supports; the request's fields must not be modified until this
+// function returns.
+//
+// This is useful for custom admin tooling that needs to query every broker,
+// for example to describe broker configs or log dirs on every broker
+// individually. Requests to a broker are retried on retryable connection
+// errors, but are not retried if a response is received.
+func (cl *Client) RequestAll(ctx context.Context, req kmsg.Request) []ResponseShard {
+	ctx, cancel := cl.requestCtx(ctx)
+	defer cancel()
+
+	shards, _ := cl.issueShardedReq(ctx, req, &allBrokersSharder{cl})
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Meta.NodeID < shards[j].Meta.NodeID })
+	return shards
+}
+
+// requestCtx returns a context that is canceled when either the input context
+// or the client is closed.
+func (cl *Client) requestCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(cl.ctx, cancel)
+	return ctx, func() { stop(); cancel() }
+}
+
+// allBrokersSharder issues a shallow copy of a request to every broker.
+type allBrokersSharder struct{ *Client }
+
+func (cl *allBrokersSharder) shard(ctx context.Context, kreq kmsg.Request, _ error) ([]issueShard, bool, error) {
+	return cl.allBrokersShardedReq(ctx, func() kmsg.Request { return shallowCopyRequest(kreq) })
+}
+
+func (*allBrokersSharder) onResp(kmsg.Request, kmsg.Response) error { return nil }
+
+func (*allBrokersSharder) merge([]ResponseShard) (kmsg.Response, error) {
+	return nil, errors.New("unable to merge responses from all brokers")
+}
+
+// shallowCopyRequest returns a shallow copy of a request, such that setting
+// the version on the copy does not modify the original.
+func shallowCopyRequest(req kmsg.Request) kmsg.Request {
+	v := reflect.ValueOf(req)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return req
+	}
+	dup := reflect.New(v.Elem().Type())
+	dup.Elem().Set(v.Elem())
+	return dup.Interface().(kmsg.Request)
+}
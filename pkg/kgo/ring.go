@@ -65,6 +65,13 @@ func (r *ring[T]) die() {
 	r.dead = true
 }
 
+func (r *ring[T]) empty() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.l == 0 && len(r.overflow) == 0
+}
+
 func (r *ring[T]) push(elem T) (first, dead bool) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"math"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -81,7 +82,7 @@ func (s *sink) createReq(id int64, epoch int16) (*produceRequest, *kmsg.AddParti
 
 	req := &produceRequest{
 		can12:   s.cl.cfg.txnID == nil || tx890p2,
-		txnID:   s.cl.cfg.txnID,
+		txnID:   s.cl.producer.txnID.Load(),
 		acks:    s.cl.cfg.acks.val,
 		timeout: int32(s.cl.cfg.produceTimeout.Milliseconds()),
 
@@ -89,7 +90,9 @@ func (s *sink) createReq(id int64, epoch int16) (*produceRequest, *kmsg.AddParti
 		producerEpoch: epoch,
 
 		hasHook:    s.cl.producer.hasHookBatchWritten,
-		compressor: s.cl.cfg.compressor,
+		nodeID:     s.nodeID,
+		stats:      &s.cl.stats,
+		compressor: s.cl.compressor(),
 
 		wireLength:      s.cl.baseProduceRequestLength(), // start length with no topics
 		wireLengthLimit: s.cl.cfg.maxBrokerWriteBytes,
@@ -112,7 +115,16 @@ func (s *sink) createReq(id int64, epoch int16) (*produceRequest, *kmsg.AddParti
 		recBufsIdx = (recBufsIdx + 1) % len(s.recBufs)
 
 		recBuf.mu.Lock()
-		if recBuf.failing || len(recBuf.batches) == recBuf.batchDrainIdx || recBuf.inflightOnSink != nil && recBuf.inflightOnSink != s || recBuf.inflight != 0 && !recBuf.okOnSink {
+		if s.cl.cfg.manualFlushing && !s.cl.producer.isFlushing(recBuf.topic) && !s.cl.producer.isShuttingDown(s.nodeID) {
+			recBuf.mu.Unlock()
+			continue
+		}
+		if recBuf.failing || recBuf.verifying > 0 || len(recBuf.batches) == recBuf.batchDrainIdx || recBuf.inflightOnSink != nil && recBuf.inflightOnSink != s || recBuf.inflight != 0 && !recBuf.okOnSink {
+			recBuf.mu.Unlock()
+			continue
+		}
+
+		if recBuf.pruneCanceled(recBuf.batchDrainIdx) && len(recBuf.batches) == recBuf.batchDrainIdx {
 			recBuf.mu.Unlock()
 			continue
 		}
@@ -201,8 +213,31 @@ func (t *txnReqBuilder) add(rb *recBuf) {
 	t.req.Topics[idx].Partitions = append(t.req.Topics[idx].Partitions, rb.partition)
 }
 
+// buffered returns the number of records buffered for this sink.
+func (s *sink) buffered() (n int64) {
+	s.recBufsMu.Lock()
+	defer s.recBufsMu.Unlock()
+	for _, recBuf := range s.recBufs {
+		n += recBuf.buffered.Load()
+	}
+	return n
+}
+
+// unlingerAndDrain stops all lingers for this sink's recBufs and begins
+// draining.
+func (s *sink) unlingerAndDrain() {
+	s.recBufsMu.Lock()
+	for _, recBuf := range s.recBufs {
+		recBuf.mu.Lock()
+		recBuf.lockedStopLinger()
+		recBuf.mu.Unlock()
+	}
+	s.recBufsMu.Unlock()
+	s.maybeDrain()
+}
+
 func (s *sink) maybeDrain() {
-	if s.cl.cfg.manualFlushing && s.cl.producer.flushing.Load() == 0 {
+	if s.cl.cfg.manualFlushing && s.cl.producer.flushing.Load() == 0 && s.cl.producer.flushingTopicsN.Load() == 0 && !s.cl.producer.isShuttingDown(s.nodeID) {
 		return
 	}
 	if s.drainState.maybeBegin() {
@@ -223,7 +258,7 @@ func (s *sink) maybeBackoff() {
 	s.cl.triggerUpdateMetadata(false, "opportunistic load during sink backoff") // as good a time as any
 
 	tries := int(s.consecutiveFailures.Add(1))
-	after := time.NewTimer(s.cl.cfg.retryBackoff(tries))
+	after := time.NewTimer(s.cl.cfg.backoff(backoffProduce, tries))
 	defer after.Stop()
 
 	select {
@@ -357,10 +392,10 @@ func (s *sink) produce(sem <-chan struct{}) bool {
 
 	id, epoch, err := s.cl.producerID(ctxFn)
 	if err != nil {
-		var pe *errProducerIDLoadFail
+		var pe *ErrProducerIDLoadFail
 		switch {
 		case errors.As(err, &pe):
-			if errors.Is(pe.err, context.Canceled) && isHolCtxDone() {
+			if errors.Is(pe.Err, context.Canceled) && isHolCtxDone() {
 				// Some head-of-line record in a partition had a context cancelation.
 				// We look for any partition with HOL cancelations and fail them all.
 				s.cl.cfg.logger.Log(LogLevelInfo, "the first record in some partition(s) had a context cancelation; failing all relevant partitions", "broker", logID(s.nodeID))
@@ -499,10 +534,15 @@ func (s *sink) doSequenced(
 	if ctx == nil {
 		ctx = s.cl.ctx
 	}
+	req.start = time.Now()
 	br, err := s.cl.brokerOrErr(ctx, s.nodeID, errUnknownBroker)
 	if err != nil {
 		wait.err = err
 		close(wait.done)
+	} else if s.cl.cfg.produceDryRun {
+		wait.resp = s.dryRunResp(req)
+		wait.br = br
+		close(wait.done)
 	} else {
 		br.do(ctx, req, func(resp kmsg.Response, err error) {
 			wait.resp = resp
@@ -517,6 +557,41 @@ func (s *sink) doSequenced(
 	}
 }
 
+// dryRunResp encodes a produce request as if it were being written, which
+// compresses every batch and computes batch metrics, and returns a successful
+// response without writing the request. Batches are given contiguous offsets
+// per partition starting from 0.
+func (s *sink) dryRunResp(req *produceRequest) kmsg.Response {
+	// We never connect, so we do not know the broker's max version. We
+	// encode with the highest version we can use, respecting MaxVersions.
+	version := req.MaxVersion()
+	if max, ok := s.cl.cfg.maxVersions.LookupMaxKeyVersion(req.Key()); ok && max < version {
+		version = max
+	}
+	req.SetVersion(version)
+	req.AppendTo(nil)
+
+	resp := kmsg.NewPtrProduceResponse()
+	resp.Version = version
+	for topic, partitions := range req.batches.bs {
+		rt := kmsg.NewProduceResponseTopic()
+		rt.Topic = topic
+		rt.TopicID = req.batches.t2id[topic]
+		for partition, batch := range partitions {
+			rp := kmsg.NewProduceResponseTopicPartition()
+			rp.Partition = partition
+			batch.owner.mu.Lock()
+			rp.BaseOffset = batch.owner.dryRunOffset
+			batch.owner.dryRunOffset += int64(len(batch.records))
+			batch.owner.mu.Unlock()
+			rp.LogAppendTime = -1
+			rt.Partitions = append(rt.Partitions, rp)
+		}
+		resp.Topics = append(resp.Topics, rt)
+	}
+	return resp
+}
+
 // Ensures that all request responses are processed in order.
 func (s *sink) handleSeqResps(wait *seqResp) {
 	var more bool
@@ -645,6 +720,13 @@ func (s *sink) firstRespCheck(idempotent bool, version int16) {
 // handleReqClientErr is called when the client errors before receiving a
 // produce response.
 func (s *sink) handleReqClientErr(req *produceRequest, err error) {
+	s.maybeVerifyAmbiguous(req, err)
+
+	// A request that failed or timed out still took this long for every
+	// partition in it.
+	elapsed := time.Since(req.start)
+	req.batches.each(func(b seqRecBatch) { b.owner.observeLatency(elapsed) })
+
 	switch {
 	default:
 		s.cl.cfg.logger.Log(LogLevelWarn, "random error while producing, requeueing unattempted request", "broker", logID(s.nodeID), "err", err)
@@ -763,6 +845,7 @@ func (s *sink) handleReqResp(br *broker, req *produceRequest, resp kmsg.Response
 
 			retry, didProduce := s.handleReqRespBatch(
 				b,
+				br,
 				&kmove,
 				kresp,
 				topic,
@@ -774,6 +857,10 @@ func (s *sink) handleReqResp(br *broker, req *produceRequest, resp kmsg.Response
 			if retry {
 				reqRetry.addSeqBatch(topic, tid, partition, batch)
 			}
+			// Failed produces are as slow for records waiting on
+			// the partition as successful ones, so we observe
+			// latency either way.
+			batch.owner.observeLatency(time.Since(req.start))
 			if !didProduce {
 				delete(tmetrics, partition)
 			}
@@ -802,6 +889,7 @@ func (s *sink) handleReqResp(br *broker, req *produceRequest, resp kmsg.Response
 
 func (s *sink) handleReqRespBatch(
 	b *bytes.Buffer,
+	br *broker,
 	kmove *kip951move,
 	resp *kmsg.ProduceResponse,
 	topic string,
@@ -936,6 +1024,30 @@ func (s *sink) handleReqRespBatch(
 		// txn coordinator requests, which have PRODUCER_FENCED vs
 		// TRANSACTION_TIMED_OUT.
 
+		// If we are idempotent-only and this batch was produced with
+		// a producer ID or epoch we already replaced (another
+		// partition failed the ID, or the ID was reset manually), the
+		// error is a stale rejection: the broker did not write the
+		// batch, and our sequence numbers were already reset. We just
+		// retry with the current ID.
+		if currentID, currentEpoch, replaced := s.cl.producer.idReplaced(producerID, producerEpoch); replaced && s.cl.cfg.txnID == nil {
+			s.cl.cfg.logger.Log(LogLevelInfo, "batch errored for a producer id or epoch we already replaced, retrying with the current producer id",
+				"broker", logID(s.nodeID),
+				"topic", topic,
+				"partition", rp.Partition,
+				"producer_id", producerID,
+				"producer_epoch", producerEpoch,
+				"current_producer_id", currentID,
+				"current_producer_epoch", currentEpoch,
+				"err", err,
+			)
+			s.cl.hookProducerIDRecovery(ProducerIDRetryStaleEpoch, topic, rp.Partition, producerID, producerEpoch, err)
+			if debug {
+				fmt.Fprintf(b, "stale@%d,%d(%s)}, ", rp.BaseOffset, nrec, err)
+			}
+			return true, false
+		}
+
 		if batch.owner.lastAckedOffset >= 0 && rp.LogStartOffset > batch.owner.lastAckedOffset {
 			s.cl.cfg.logger.Log(LogLevelInfo, "partition prefix truncation to after our last produce caused the broker to forget us; no loss occurred, bumping producer epoch and resetting sequence numbers",
 				"broker", logID(s.nodeID),
@@ -946,6 +1058,7 @@ func (s *sink) handleReqRespBatch(
 				"err", err,
 			)
 			s.cl.failProducerID(producerID, producerEpoch, errReloadProducerID)
+			s.cl.hookProducerIDRecovery(ProducerIDResetSafe, topic, rp.Partition, producerID, producerEpoch, err)
 			if debug {
 				fmt.Fprintf(b, "resetting@%d,%d(%s)}, ", rp.BaseOffset, nrec, err)
 			}
@@ -962,6 +1075,7 @@ func (s *sink) handleReqRespBatch(
 				"err", err,
 			)
 			s.cl.failProducerID(producerID, producerEpoch, err)
+			s.cl.hookProducerIDRecovery(ProducerIDFailed, topic, rp.Partition, producerID, producerEpoch, err)
 
 			s.cl.finishBatch(batch.recBatch, producerID, producerEpoch, rp.Partition, rp.BaseOffset, err)
 			if debug {
@@ -997,11 +1111,53 @@ func (s *sink) handleReqRespBatch(
 		// a new epoch-bumped producer ID and all first-batches
 		// will reset sequence numbers appropriately.
 		s.cl.failProducerID(producerID, producerEpoch, errReloadProducerID)
+		s.cl.hookProducerIDRecovery(ProducerIDResetDataRisk, topic, rp.Partition, producerID, producerEpoch, err)
 		if debug {
 			fmt.Fprintf(b, "resetting@%d,%d(%s)}, ", rp.BaseOffset, nrec, err)
 		}
 		return true, false
 
+	case (err == kerr.MessageTooLarge || err == kerr.RecordListTooLarge) && nrec > 1:
+		// The broker did not write this batch, so we can split it in
+		// half and retry both halves, similar to the Java client. The
+		// halves are drained again from our first sequence number.
+		// Splitting repeats until the batch is small enough or it is a
+		// single record, which is handled just below.
+		s.cl.cfg.logger.Log(LogLevelInfo, "batch in a produce request was too large, splitting the batch in half and retrying",
+			"broker", logID(s.nodeID),
+			"topic", topic,
+			"partition", rp.Partition,
+			"num_records", nrec,
+			"err", err,
+		)
+		batch.owner.splitFirstBatch()
+		if debug {
+			fmt.Fprintf(b, "splitting@%d,%d(%s)}, ", rp.BaseOffset, nrec, err)
+		}
+		return false, false
+
+	case err == kerr.MessageTooLarge || err == kerr.RecordListTooLarge:
+		// A single record is too large. We fail only this record; the
+		// broker did not write it, so records after it are unaffected.
+		//
+		// Later batches may still be inflight, and the broker will
+		// reject them with OOOSN since it never wrote this batch's
+		// sequence numbers. We keep this batch first until all
+		// inflight requests finish so that those responses are
+		// skipped, and fail it in decInflight.
+		s.cl.cfg.logger.Log(LogLevelInfo, "record in a produce request was too large, failing only this record once inflight requests finish",
+			"broker", logID(s.nodeID),
+			"topic", topic,
+			"partition", rp.Partition,
+			"err", err,
+		)
+		batch.owner.failFirst = batch.recBatch
+		batch.owner.failFirstErr = err
+		if debug {
+			fmt.Fprintf(b, "err@%d,%d(%s)}, ", rp.BaseOffset, nrec, err)
+		}
+		return false, false
+
 	case err == kerr.DuplicateSequenceNumber: // ignorable, but we should not get
 		s.cl.cfg.logger.Log(LogLevelInfo, "received unexpected duplicate sequence number, ignoring and treating batch as successful",
 			"broker", logID(s.nodeID),
@@ -1026,6 +1182,9 @@ func (s *sink) handleReqRespBatch(
 			if resp.Version >= 12 && s.cl.cfg.txnID != nil {
 				batch.owner.addedToTxn.Swap(true)
 			}
+			if rp.LogAppendTime >= 0 {
+				s.cl.producer.hookTimestampSkew(br.meta, topic, rp.Partition, rp.LogAppendTime, batch.recBatch)
+			}
 		}
 		s.cl.finishBatch(batch.recBatch, producerID, producerEpoch, rp.Partition, rp.BaseOffset, err)
 		didProduce = err == nil
@@ -1080,6 +1239,7 @@ func (cl *Client) finishBatch(batch *recBatch, producerID int64, producerEpoch i
 		// timestamp type. Thus, we can directly convert the batch
 		// attrs to our own RecordAttrs.
 		attrs:     RecordAttrs{uint8(attrs)},
+		batch:     true,
 		partition: partition,
 		recs:      records,
 	})
@@ -1257,6 +1417,12 @@ type recBuf struct {
 	// of records buffered in total on this recBuf.
 	buffered atomicI64
 
+	// For LeastLoadedPartitioner; an exponentially weighted moving average
+	// of the nanosecond latency of produce requests for this partition,
+	// and the unix nanosecond time it was last observed at.
+	latency   atomicI64
+	latencyAt atomicI64
+
 	mu sync.Mutex // guards r/w access to all fields below
 
 	// sink is who is currently draining us. This can be modified
@@ -1298,7 +1464,27 @@ type recBuf struct {
 	// to drain.
 	inflight uint8
 
+	// verifying is the number of batches from this recBuf that are being
+	// checked for whether they landed after an ambiguous produce error.
+	// We do not drain while verifying, so that a retry with the same
+	// sequence numbers cannot be mistaken for the original write.
+	verifying uint8
+
+	// failFirst, if non-nil, is a batch that the broker rejected as too
+	// large and that is failed with failFirstErr once no requests are
+	// inflight. Until then, it remains the first batch so that responses
+	// for later inflight batches are not handled as the first batch.
+	failFirst    *recBatch
+	failFirstErr error
+
 	lastAckedOffset int64 // last ProduceResponse's BaseOffset + how many records we produced
+	dryRunOffset    int64 // with ProduceDryRun, the next offset to simulate
+
+	// If using OrderedProducePromises, promiseSeq is the sequence number
+	// of the last record buffered, and order holds promises until it is
+	// their turn. order is only accessed when finishing promises.
+	promiseSeq uint64
+	order      promiseOrder
 
 	topicPartitionData // updated in metadata migrateProductionTo (same spot sink is updated)
 
@@ -1371,15 +1557,22 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 	defer recBuf.mu.Unlock()
 
 	// We truncate to milliseconds to avoid some accumulated rounding error
-	// problems (see IBM/sarama#1455)
+	// problems (see IBM/sarama#1455). The timestamp is only zero here if
+	// using TimestampAtFlush, in which case this timestamp is used for the
+	// delivery timeout until the batch is written.
 	if pr.Timestamp.IsZero() {
 		pr.Timestamp = time.Now()
 	}
 	pr.Timestamp = pr.Timestamp.Truncate(time.Millisecond)
 	pr.Partition = recBuf.partition // set now, for the hook below
 
+	if recBuf.cl.cfg.orderedPromises {
+		recBuf.promiseSeq++
+		pr.order, pr.seq = &recBuf.order, recBuf.promiseSeq
+	}
+
 	if recBuf.purged {
-		recBuf.cl.producer.promiseRecord(pr, errPurged)
+		recBuf.cl.producer.promiseRecord(pr, ErrPurged)
 		return true
 	}
 
@@ -1402,6 +1595,9 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 		switch {
 		case aborted: // not processed
 			recBuf.cl.prsPool.put(newBatch.records)
+			if pr.order != nil {
+				recBuf.promiseSeq-- // we will be buffered elsewhere
+			}
 			return false
 		case appended: // we return true below
 		default: // processed as failure
@@ -1413,7 +1609,7 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 		recBuf.batches = append(recBuf.batches, newBatch)
 	}
 
-	if recBuf.cl.cfg.linger == 0 {
+	if recBuf.cl.cfg.linger.load() == 0 {
 		if onDrainBatch {
 			recBuf.sink.maybeDrain()
 		}
@@ -1448,7 +1644,7 @@ func (recBuf *recBuf) bufferRecord(pr promisedRec, abortOnNewBatch bool) bool {
 // lingering, then we are flushing and also indicate there is more to drain.
 func (recBuf *recBuf) tryStopLingerForDraining() bool {
 	recBuf.lockedStopLinger()
-	canLinger := recBuf.cl.cfg.linger != 0
+	canLinger := recBuf.cl.cfg.linger.load() != 0
 	moreToDrain := !canLinger && len(recBuf.batches) > recBuf.batchDrainIdx ||
 		canLinger && (len(recBuf.batches) > recBuf.batchDrainIdx+1 ||
 			len(recBuf.batches) == recBuf.batchDrainIdx+1 && !recBuf.lockedMaybeLinger())
@@ -1457,11 +1653,11 @@ func (recBuf *recBuf) tryStopLingerForDraining() bool {
 
 // Begins a linger timer unless the producer is being flushed.
 func (recBuf *recBuf) lockedMaybeLinger() bool {
-	if recBuf.cl.producer.flushing.Load() > 0 || recBuf.cl.producer.blocked.Load() > 0 {
+	if recBuf.cl.producer.blocked.Load() > 0 || recBuf.cl.producer.isFlushing(recBuf.topic) || recBuf.cl.producer.isShuttingDown(recBuf.sink.nodeID) {
 		return false
 	}
 	if recBuf.lingering == nil {
-		recBuf.lingering = time.AfterFunc(recBuf.cl.cfg.linger, func() {
+		recBuf.lingering = time.AfterFunc(recBuf.cl.cfg.linger.load(), func() {
 			recBuf.sink.maybeDrain()
 		})
 	}
@@ -1492,6 +1688,11 @@ func (recBuf *recBuf) unlingerAndManuallyDrain() {
 func (recBuf *recBuf) bumpRepeatedLoadErr(err error) {
 	recBuf.mu.Lock()
 	defer recBuf.mu.Unlock()
+	for i := recBuf.batchDrainIdx; i < len(recBuf.batches); {
+		if !recBuf.pruneCanceled(i) {
+			i++
+		}
+	}
 	if len(recBuf.batches) == 0 {
 		return
 	}
@@ -1566,9 +1767,12 @@ func (recBuf *recBuf) failAllRecords(err error) {
 		batch.records = nil
 		batch.mu.Unlock()
 
+		recBuf.cl.producer.countAborted(recBuf.topic, recBuf.partition, len(records), err)
 		recBuf.cl.producer.promiseBatch(batchPromise{
-			recs: records,
-			err:  err,
+			batch:     true,
+			partition: recBuf.partition,
+			recs:      records,
+			err:       err,
 		})
 	}
 	recBuf.resetBatchDrainIdx()
@@ -1576,6 +1780,65 @@ func (recBuf *recBuf) failAllRecords(err error) {
 	recBuf.batches = nil
 }
 
+// splitFirstBatch replaces the first batch with two batches, each containing
+// half of the records, after the broker rejected the batch for being too
+// large. The batch was not written, so the split batches are drained again
+// starting at batch0Seq.
+//
+// This is called under the recBuf's mu with the first batch not inflight.
+func (recBuf *recBuf) splitFirstBatch() {
+	old := recBuf.batches[0]
+	old.mu.Lock()
+	records := old.records
+	old.records = nil
+	old.mu.Unlock()
+
+	half := len(records) / 2
+	split := make([]*recBatch, 0, len(recBuf.batches)+1)
+	for _, prs := range [][]promisedRec{records[:half], records[half:]} {
+		batch := recBuf.newRecordBatch()
+		batch.tries = old.tries
+		// Timestamps were stamped (if necessary) when the original
+		// batch was frozen, and we do not want new records appended
+		// to a batch that is already known to be too large.
+		batch.stampOnFreeze = false
+		for _, pr := range prs {
+			nums := batch.calculateRecordNumbers(pr.Record)
+			batch.appendRecord(pr, nums)
+			pr.setLengthAndTimestampDelta(nums.lengthField, nums.tsDelta)
+		}
+		batch.frozen = true
+		split = append(split, batch)
+	}
+	recBuf.batches = append(split, recBuf.batches[1:]...)
+	recBuf.resetBatchDrainIdx()
+}
+
+// failFirstBatch fails only the first batch, which the broker did not write,
+// and leaves all other batches to be drained again starting at batch0Seq.
+//
+// This is called under the recBuf's mu with no batches inflight; see the
+// failFirst field.
+func (recBuf *recBuf) failFirstBatch(err error) {
+	batch := recBuf.batches[0]
+	batch.mu.Lock()
+	records := batch.records
+	batch.records = nil
+	batch.mu.Unlock()
+
+	recBuf.buffered.Add(-int64(len(records)))
+	recBuf.batches[0] = nil
+	recBuf.batches = recBuf.batches[1:]
+	recBuf.resetBatchDrainIdx()
+
+	recBuf.cl.producer.promiseBatch(batchPromise{
+		batch:     true,
+		partition: recBuf.partition,
+		recs:      records,
+		err:       err,
+	})
+}
+
 // clearFailing clears a buffer's failing state if it is failing.
 //
 // This is called when a buffer is added to a sink (to clear a failing state
@@ -1602,6 +1865,12 @@ type promisedRec struct {
 	ctx     context.Context
 	promise func(*Record, error)
 	*Record
+
+	// If using OrderedProducePromises, order is the order state of the
+	// recBuf this record was buffered to, and seq is the record's
+	// position in that order.
+	order *promiseOrder
+	seq   uint64
 }
 
 func (pr promisedRec) cancelingCtx() context.Context {
@@ -1614,6 +1883,19 @@ func (pr promisedRec) cancelingCtx() context.Context {
 	return nil
 }
 
+// canceledErr returns the error of the context passed to Produce or the
+// record's context if either is canceled.
+func (pr promisedRec) canceledErr() error {
+	select {
+	case <-pr.ctx.Done():
+		return pr.ctx.Err()
+	case <-pr.Context.Done():
+		return pr.Context.Err()
+	default:
+		return nil
+	}
+}
+
 // recBatch is the type used for buffering records before they are written.
 type recBatch struct {
 	owner *recBuf // who owns us
@@ -1641,6 +1923,7 @@ type recBatch struct {
 	attrs             int16 // updated during apending; read and converted to RecordAttrs on success
 	firstTimestamp    int64 // since unix epoch, in millis
 	maxTimestampDelta int64
+	stampOnFreeze     bool // if using TimestampAtFlush: all timestamp deltas are 0, and firstTimestamp is set when first frozen
 
 	mu      sync.Mutex    // guards appendTo's reading of records against failAllRecords emptying it
 	records []promisedRec // record w/ length, ts calculated
@@ -1649,13 +1932,8 @@ type recBatch struct {
 // Returns an error if the batch should fail.
 func (b *recBatch) maybeFailErr(cfg *cfg) error {
 	if len(b.records) > 0 {
-		r0 := &b.records[0]
-		select {
-		case <-r0.ctx.Done():
-			return r0.ctx.Err()
-		case <-r0.Context.Done():
-			return r0.Context.Err()
-		default:
+		if err := b.records[0].canceledErr(); err != nil {
+			return err
 		}
 	}
 	switch {
@@ -1663,7 +1941,8 @@ func (b *recBatch) maybeFailErr(cfg *cfg) error {
 		return ErrRecordTimeout
 	case b.tries > cfg.recordRetries:
 		return ErrRecordRetries
-	case b.owner.cl.producer.isAborting():
+	case b.owner.cl.producer.isAborting(),
+		b.owner.cl.producer.isAbortingPartition(b.owner.topic, b.owner.partition):
 		return ErrAborting
 	}
 	return nil
@@ -1692,31 +1971,99 @@ func (b *recBatch) appendRecord(pr promisedRec, nums recordNumbers) {
 	b.records = append(b.records, pr)
 }
 
+const recordBatchOverhead = 4 + // array len
+	8 + // firstOffset
+	4 + // batchLength
+	4 + // partitionLeaderEpoch
+	1 + // magic
+	4 + // crc
+	2 + // attributes
+	4 + // lastOffsetDelta
+	8 + // firstTimestamp
+	8 + // maxTimestamp
+	8 + // producerID
+	2 + // producerEpoch
+	4 + // seq
+	4 // record array length
+
 // newRecordBatch returns a new record batch for a topic and partition.
 func (recBuf *recBuf) newRecordBatch() *recBatch {
-	const recordBatchOverhead = 4 + // array len
-		8 + // firstOffset
-		4 + // batchLength
-		4 + // partitionLeaderEpoch
-		1 + // magic
-		4 + // crc
-		2 + // attributes
-		4 + // lastOffsetDelta
-		8 + // firstTimestamp
-		8 + // maxTimestamp
-		8 + // producerID
-		2 + // producerEpoch
-		4 + // seq
-		4 // record array length
 	return &recBatch{
 		owner:      recBuf,
 		records:    recBuf.cl.prsPool.get()[:0],
 		wireLength: recordBatchOverhead,
 
 		canFailFromLoadErrs: true, // until we send this batch, we can fail it
+		stampOnFreeze:       recBuf.cl.cfg.timestampSource == TimestampAtFlush(),
 	}
 }
 
+// stampTimestamps sets the timestamp of every record in a TimestampAtFlush
+// batch. This is called once, when the batch is first frozen into a request;
+// all records were buffered with a zero timestamp delta, so the batch's wire
+// length does not change.
+func (b *recBatch) stampTimestamps() {
+	now := time.Now().Truncate(time.Millisecond)
+	b.firstTimestamp = now.UnixMilli()
+	for _, pr := range b.records {
+		pr.Timestamp = now
+	}
+}
+
+// pruneCanceled fails and removes all records whose context is canceled from
+// the batch at idx, if the batch has never been sent. If the batch is emptied,
+// it is removed from the buffer. This returns whether the batch was removed.
+//
+// This is called under the recBuf's mu. Unsent batches do not have sequence
+// numbers assigned yet, so removing records does not affect idempotency.
+func (recBuf *recBuf) pruneCanceled(idx int) bool {
+	batch := recBuf.batches[idx]
+	if batch.frozen {
+		return false
+	}
+
+	var (
+		canceled []promisedRec
+		errs     []error
+		keep     = batch.records[:0]
+	)
+	for _, pr := range batch.records {
+		if err := pr.canceledErr(); err != nil {
+			canceled = append(canceled, pr)
+			errs = append(errs, err)
+			continue
+		}
+		keep = append(keep, pr)
+	}
+	if len(canceled) == 0 {
+		return false
+	}
+
+	// The kept records may have shifted in the batch, so we recompute
+	// the batch from scratch.
+	batch.wireLength = recordBatchOverhead
+	batch.v1wireLength = 0
+	batch.firstTimestamp = 0
+	batch.maxTimestampDelta = 0
+	batch.records = batch.records[:0]
+	for _, pr := range keep {
+		nums := batch.calculateRecordNumbers(pr.Record)
+		batch.appendRecord(pr, nums)
+		pr.setLengthAndTimestampDelta(nums.lengthField, nums.tsDelta)
+	}
+
+	recBuf.buffered.Add(-int64(len(canceled)))
+	for i, pr := range canceled {
+		recBuf.cl.producer.promiseRecord(pr, errs[i])
+	}
+
+	if len(batch.records) > 0 {
+		return false
+	}
+	recBuf.batches = append(recBuf.batches[:idx], recBuf.batches[idx+1:]...)
+	return true
+}
+
 // prsPool is the one pool we have internally that is hard to expose an
 // interface for. That said, ideally batch size is relatively consistent
 // over time and using our own internal pool is fine enough.
@@ -1766,8 +2113,15 @@ func (b *recBatch) decInflight() {
 	}
 	recBuf.inflightOnSink = nil
 
+	if recBuf.failFirst != nil {
+		if recBuf.failFirst.isOwnersFirstBatch() {
+			recBuf.failFirstBatch(recBuf.failFirstErr)
+		}
+		recBuf.failFirst, recBuf.failFirstErr = nil, nil
+	}
+
 	nbufBatches := len(recBuf.batches) - recBuf.batchDrainIdx
-	if recBuf.cl.cfg.linger == 0 && nbufBatches > 0 ||
+	if recBuf.cl.cfg.linger.load() == 0 && nbufBatches > 0 ||
 		nbufBatches > 1 ||
 		nbufBatches == 1 && !recBuf.lockedMaybeLinger() {
 		recBuf.lockedStopLinger()
@@ -1788,6 +2142,7 @@ type produceRequest struct {
 	can12   bool // we can send v12+ if we aren't using txns OR the broker has feature transaction.version >= 2
 
 	backoffSeq uint32
+	start      time.Time // when the request was handed to the broker, for recBuf latency
 
 	txnID   *string
 	acks    int16
@@ -1805,6 +2160,8 @@ type produceRequest struct {
 	// We use this in handleReqResp for the OnProduceHook.
 	metrics produceMetrics
 	hasHook bool
+	nodeID  int32        // for Client.Metrics, which tracks written batches regardless of hooks
+	stats   *clientStats // nil in tests
 
 	compressor Compressor
 
@@ -1889,6 +2246,9 @@ func (p *produceRequest) tryAddBatch(produceVersion int32, recBuf *recBuf, batch
 		}
 	}
 
+	if !batch.frozen && batch.stampOnFreeze {
+		batch.stampTimestamps()
+	}
 	batch.frozen = true
 	p.wireLength += batchWireLength
 	p.batches.addBatch(
@@ -2005,11 +2365,14 @@ func (cl *Client) baseProduceRequestLength() int32 {
 		// empty tag section skipped; see below
 
 	baseLength := messageRequestOverhead + produceRequestBaseOverhead
-	if cl.cfg.id != nil {
-		baseLength += int32(len(*cl.cfg.id))
+	if id := cl.clientIDFor(0); id != nil {
+		baseLength += int32(len(*id))
+	}
+	if cl.cfg.requestTagsFn != nil {
+		baseLength += maxRequestTagsLen
 	}
 	if cl.cfg.txnID != nil {
-		baseLength += int32(len(*cl.cfg.txnID))
+		baseLength += int32(len(*cl.producer.txnID.Load()))
 	}
 	return baseLength
 }
@@ -2076,8 +2439,8 @@ func (b *recBatch) calculateRecordNumbers(r *Record) recordNumbers {
 	tsDelta := tsMillis - b.firstTimestamp
 
 	// If this is to be the first record in the batch, then our timestamp
-	// delta is actually 0.
-	if len(b.records) == 0 {
+	// delta is actually 0. With TimestampAtFlush, all deltas are 0.
+	if len(b.records) == 0 || b.stampOnFreeze {
 		tsDelta = 0
 	}
 
@@ -2108,6 +2471,38 @@ func (b *recBatch) calculateRecordNumbers(r *Record) recordNumbers {
 func uvar32(l int32) uint32 { return 1 + uint32(l) }
 func uvarlen(l int) int32   { return int32(kbin.UvarintLen(uvar32(int32(l)))) }
 
+// latencyHalfLife is how long it takes for a partition's produce latency to
+// decay by half while nothing is produced to it, so that a partition that was
+// slow is eventually tried again.
+const latencyHalfLife = 10 * time.Second
+
+// loadLatency returns the recBuf's moving average produce latency, decayed by
+// the time since it was last observed.
+func (recBuf *recBuf) loadLatency(now time.Time) time.Duration {
+	latency := recBuf.latency.Load()
+	if latency == 0 {
+		return 0
+	}
+	idle := now.Sub(time.Unix(0, recBuf.latencyAt.Load()))
+	if idle <= 0 {
+		return time.Duration(latency)
+	}
+	return time.Duration(float64(latency) * math.Exp2(-float64(idle)/float64(latencyHalfLife)))
+}
+
+// observeLatency folds a produce request latency into the recBuf's decayed
+// moving average, weighting the new observation at 1/8.
+func (recBuf *recBuf) observeLatency(d time.Duration) {
+	now := time.Now()
+	prior := int64(recBuf.loadLatency(now))
+	if prior == 0 {
+		recBuf.latency.Store(int64(d))
+	} else {
+		recBuf.latency.Store(prior + (int64(d)-prior)/8)
+	}
+	recBuf.latencyAt.Store(now.UnixNano())
+}
+
 // recordNumbers tracks a few numbers for a record that is buffered.
 type recordNumbers struct {
 	lengthField int32 // the length field prefix of a record encoded on the wire
@@ -2189,6 +2584,11 @@ func (p *produceRequest) IsFlexible() bool   { return p.version >= 9 }
 func (p *produceRequest) AppendTo(dst []byte) []byte {
 	flexible := p.IsFlexible()
 
+	// Batches are appended uncompressed before being compressed in place,
+	// so the uncompressed request size we track is all the space we need:
+	// we grow once up front rather than repeatedly while appending.
+	dst = slices.Grow(dst, int(p.wireLength))
+
 	if p.hasHook {
 		p.metrics = make(map[string]map[int32]ProduceBatchMetrics)
 	}
@@ -2242,6 +2642,9 @@ func (p *produceRequest) AppendTo(dst []byte) []byte {
 			}
 			batch.canFailFromLoadErrs = false // we are going to write this batch: the response status is now unknown
 			batch.tries++
+			if batch.tries > 1 && p.stats != nil {
+				p.stats.retries.Add(1)
+			}
 			var pmetrics ProduceBatchMetrics
 			if p.version < 3 {
 				dst, pmetrics = batch.appendToAsMessageSet(dst, uint8(p.version), p.compressor)
@@ -2249,6 +2652,9 @@ func (p *produceRequest) AppendTo(dst []byte) []byte {
 				dst, pmetrics = batch.appendTo(dst, p.version, p.producerID, p.producerEpoch, p.txnID != nil, p.compressor)
 			}
 			batch.mu.Unlock()
+			if p.stats != nil {
+				p.stats.observeProduced(p.nodeID, topic, pmetrics)
+			}
 			if p.hasHook {
 				tmetrics[partition] = pmetrics
 			}
@@ -2367,7 +2773,7 @@ func (b seqRecBatch) appendTo(
 	m.UncompressedBytes = len(toCompress)
 	m.CompressedBytes = m.UncompressedBytes
 
-	if compressor != nil {
+	if compresses(compressor) {
 		w := byteBuffers.Get().(*bytes.Buffer)
 		defer byteBuffers.Put(w)
 		w.Reset()
@@ -2449,7 +2855,7 @@ func (b seqRecBatch) appendToAsMessageSet(dst []byte, version uint8, compressor
 	m.UncompressedBytes = len(toCompress)
 	m.CompressedBytes = m.UncompressedBytes
 
-	if compressor != nil {
+	if compresses(compressor) {
 		w := byteBuffers.Get().(*bytes.Buffer)
 		defer byteBuffers.Put(w)
 		w.Reset()
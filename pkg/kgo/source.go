@@ -28,6 +28,7 @@ type readerFrom interface {
 type source struct {
 	cl     *Client // our owning client, for cfg, metadata triggering, context, etc.
 	nodeID int32   // the node ID of the broker this sink belongs to
+	shard  int     // which of the broker's sources this is, see ConcurrentFetchesPerBroker
 
 	// Tracks how many _failed_ fetch requests we have in a row (unable to
 	// receive a response). Any response, even responses with an ErrorCode
@@ -45,10 +46,11 @@ type source struct {
 	cursorsStart int       // incremented every fetch req to ensure all partitions are fetched
 }
 
-func (cl *Client) newSource(nodeID int32) *source {
+func (cl *Client) newSource(nodeID int32, shard int) *source {
 	s := &source{
 		cl:     cl,
 		nodeID: nodeID,
+		shard:  shard,
 		sem:    make(chan struct{}),
 	}
 	if cl.cfg.disableFetchSessions {
@@ -63,6 +65,7 @@ func (s *source) addCursor(add *cursor) {
 	add.cursorsIdx = len(s.cursors)
 	s.cursors = append(s.cursors, add)
 	s.cursorsMu.Unlock()
+	s.cl.consumer.numCursors.Add(1)
 
 	// Adding a new cursor may allow a new partition to be fetched.
 	// We do not need to cancel any current fetch nor kill the session,
@@ -90,6 +93,7 @@ func (s *source) removeCursor(rm *cursor) {
 	if s.cursorsStart == len(s.cursors) {
 		s.cursorsStart = 0
 	}
+	s.cl.consumer.numCursors.Add(-1)
 }
 
 // cursor is where we are consuming from for an individual partition.
@@ -142,6 +146,26 @@ type cursor struct {
 	// leader epoch (see cursorOffsetNext for why the leader epoch). When a
 	// buffered fetch is taken, we update the cursor.
 	cursorOffset
+
+	// watermarks are the offsets we last observed for this cursor, read
+	// in PartitionWatermarks and Position.
+	watermarks cursorWatermarks
+}
+
+// cursorWatermarks contains the offsets last observed for a cursor. These are
+// updated while processing fetch responses and when fetches are taken, and are
+// read concurrently outside of the consumer session.
+type cursorWatermarks struct {
+	seen         atomicBool // whether we have received a fetch response since the cursor was set
+	lastConsumed atomicI64
+	hwm          atomicI64
+	lso          atomicI64
+	logStart     atomicI64
+
+	// position and positionEpoch mirror the cursorOffset offset and last
+	// consumed epoch, i.e. what the next poll returns.
+	position      atomicI64
+	positionEpoch atomicI32
 }
 
 // cursorOffset tracks offsets/epochs for a cursor.
@@ -169,6 +193,10 @@ type cursorOffset struct {
 	// The current high watermark of the partition. Uninitialized (0) means
 	// we do not know the HWM, or there is no lag.
 	hwm int64
+
+	// The offset of the last record we consumed, or -1 if we have not
+	// consumed anything since the offset was last set.
+	lastConsumedOffset int64
 }
 
 // use, for fetch requests, freezes a view of the cursorOffset.
@@ -190,10 +218,12 @@ func (c *cursor) use() *cursorOffsetNext {
 func (c *cursor) unset() {
 	c.useState.Store(false)
 	c.setOffset(cursorOffset{
-		offset:            -1,
-		lastConsumedEpoch: -1,
-		hwm:               0,
+		offset:             -1,
+		lastConsumedEpoch:  -1,
+		hwm:                0,
+		lastConsumedOffset: -1,
 	})
+	c.watermarks.seen.Store(false)
 }
 
 // usable returns whether a cursor can be used for building a fetch request.
@@ -215,6 +245,9 @@ func (c *cursor) allowUsable() {
 // after.
 func (c *cursor) setOffset(o cursorOffset) {
 	c.cursorOffset = o
+	c.watermarks.lastConsumed.Store(o.lastConsumedOffset)
+	c.watermarks.positionEpoch.Store(o.lastConsumedEpoch)
+	c.watermarks.position.Store(o.offset)
 }
 
 // cursorOffsetNext is updated while processing a fetch response.
@@ -267,7 +300,7 @@ func (p *cursorOffsetPreferred) move() {
 	// we will not have a buffered fetch since moving replicas is called
 	// before buffering a fetch.
 	c.source.removeCursor(c)
-	c.source = sns.source
+	c.source = sns.sourceFor(c.topic, c.partition)
 	c.source.addCursor(c)
 	c.moveAt = time.Now().UnixNano()
 }
@@ -425,6 +458,9 @@ func (s *source) hook(f *Fetch, buffered, polled bool) {
 	} else {
 		s.cl.consumer.bufferedRecords.Add(-int64(nrecs))
 		s.cl.consumer.bufferedBytes.Add(-nbytes)
+		if nbytes > 0 {
+			s.cl.consumer.maybeResumeTargetFetches()
+		}
 	}
 }
 
@@ -506,13 +542,15 @@ func (s *source) discardBuffered() {
 }
 
 // takeNBuffered takes a limited amount of records from a buffered fetch,
-// updating offsets in each partition per records taken.
+// updating offsets in each partition per records taken. If quotas is non-nil,
+// no more than the partition's quota is taken from any partition; partitions
+// with records remaining are kept buffered, in order, for the next poll.
 //
 // This only allows a new fetch once every buffered record has been taken.
 //
 // This returns the number of records taken and whether the source has been
 // completely drained.
-func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
+func (s *source) takeNBuffered(paused pausedTopics, n int, quotas map[string]map[int32]int) (Fetch, int, bool) {
 	var (
 		r      Fetch
 		rstrip Fetch
@@ -521,14 +559,18 @@ func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
 
 	b := &s.buffered
 	bf := &b.fetch
-	for len(bf.Topics) > 0 && n > 0 {
-		t := &bf.Topics[0]
+	keepTopics := bf.Topics[:0]
+	for i := range bf.Topics {
+		t := &bf.Topics[i]
+		if n <= 0 {
+			keepTopics = append(keepTopics, *t)
+			continue
+		}
 
 		// If the topic is outright paused, we allowUsable all
 		// partitions in the topic and skip the topic entirely.
 		if paused.has(t.Topic, -1) {
 			rstrip.Topics = append(rstrip.Topics, *t)
-			bf.Topics = bf.Topics[1:]
 			for _, pCursor := range b.usedOffsets[t.Topic] {
 				pCursor.from.allowUsable()
 			}
@@ -556,14 +598,19 @@ func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
 		}
 
 		tCursors := b.usedOffsets[t.Topic]
+		tQuotas := quotas[t.Topic]
 
-		for len(t.Partitions) > 0 && n > 0 {
-			p := &t.Partitions[0]
+		keepPartitions := t.Partitions[:0]
+		for j := range t.Partitions {
+			p := t.Partitions[j]
+			if n <= 0 {
+				keepPartitions = append(keepPartitions, p)
+				continue
+			}
 
 			if paused.has(t.Topic, p.Partition) {
 				ensureTopicStripped()
-				rtstrip.Partitions = append(rtstrip.Partitions, *p)
-				t.Partitions = t.Partitions[1:]
+				rtstrip.Partitions = append(rtstrip.Partitions, p)
 				pCursor := tCursors[p.Partition]
 				pCursor.from.allowUsable()
 				delete(tCursors, p.Partition)
@@ -573,12 +620,19 @@ func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
 				continue
 			}
 
+			take := min(n, len(p.Records))
+			if quotas != nil {
+				take = min(take, tQuotas[p.Partition])
+			}
+			if take == 0 && len(p.Records) > 0 {
+				keepPartitions = append(keepPartitions, p)
+				continue
+			}
+
 			ensureTopicAdded()
-			rt.Partitions = append(rt.Partitions, *p)
+			rt.Partitions = append(rt.Partitions, p)
 			rp := &rt.Partitions[len(rt.Partitions)-1]
 
-			take := min(n, len(p.Records))
-
 			rp.Records = p.Records[:take:take]
 			p.Records = p.Records[take:]
 
@@ -588,8 +642,6 @@ func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
 			pCursor := tCursors[p.Partition]
 
 			if len(p.Records) == 0 {
-				t.Partitions = t.Partitions[1:]
-
 				pCursor.from.setOffset(pCursor.cursorOffset)
 				pCursor.from.allowUsable()
 				delete(tCursors, p.Partition)
@@ -601,17 +653,21 @@ func (s *source) takeNBuffered(paused pausedTopics, n int) (Fetch, int, bool) {
 
 			lastReturnedRecord := rp.Records[len(rp.Records)-1]
 			pCursor.from.setOffset(cursorOffset{
-				offset:            lastReturnedRecord.Offset + 1,
-				lastConsumedEpoch: lastReturnedRecord.LeaderEpoch,
-				lastConsumedTime:  lastReturnedRecord.Timestamp,
-				hwm:               p.HighWatermark,
+				offset:             lastReturnedRecord.Offset + 1,
+				lastConsumedEpoch:  lastReturnedRecord.LeaderEpoch,
+				lastConsumedTime:   lastReturnedRecord.Timestamp,
+				hwm:                p.HighWatermark,
+				lastConsumedOffset: lastReturnedRecord.Offset,
 			})
+			keepPartitions = append(keepPartitions, p)
 		}
 
-		if len(t.Partitions) == 0 {
-			bf.Topics = bf.Topics[1:]
+		if len(keepPartitions) > 0 {
+			t.Partitions = keepPartitions
+			keepTopics = append(keepTopics, *t)
 		}
 	}
+	bf.Topics = keepTopics
 
 	if len(rstrip.Topics) > 0 {
 		s.hook(&rstrip, false, true)
@@ -640,13 +696,14 @@ func (s *source) takeBufferedFn(polled bool, offsetFn func(usedOffsets)) Fetch {
 // createReq actually creates a fetch request.
 func (s *source) createReq() *fetchRequest {
 	req := &fetchRequest{
-		maxWait:        s.cl.cfg.maxWait,
+		maxWait:        s.cl.cfg.maxWait.load(),
 		minBytes:       s.cl.cfg.minBytes,
 		maxBytes:       s.cl.cfg.maxBytes.load(),
 		maxPartBytes:   s.cl.cfg.maxPartBytes.load(),
 		rack:           s.cl.cfg.rack,
 		isolationLevel: s.cl.cfg.isolationLevel,
 		preferLagFn:    s.cl.cfg.preferLagFn,
+		shard:          s.shard,
 
 		// We copy a view of the session for the request, which allows
 		// modify source while the request may be reading its copy.
@@ -655,6 +712,18 @@ func (s *source) createReq() *fetchRequest {
 
 	paused := s.cl.consumer.loadPaused()
 
+	// If we have a buffer target, each partition is requested with at
+	// most the target and we only add partitions while the client wide
+	// budget has room.
+	var avail, per int64
+	target := int64(s.cl.cfg.fetchTargetBufferedBytes)
+	if target > 0 {
+		req.maxPartBytes = int32(min(int64(req.maxPartBytes), target))
+		per = int64(req.maxPartBytes)
+		budget, used := s.cl.consumer.fetchTargetBudget()
+		avail = budget - used
+	}
+
 	s.cursorsMu.Lock()
 	defer s.cursorsMu.Unlock()
 
@@ -665,8 +734,18 @@ func (s *source) createReq() *fetchRequest {
 		if !c.usable() || paused.has(c.topic, c.partition) {
 			continue
 		}
+		if target > 0 {
+			if avail < per {
+				break
+			}
+			avail -= per
+			req.reserved += per
+		}
 		req.addCursor(c)
 	}
+	if req.reserved > 0 {
+		s.cl.consumer.fetchReserved.Add(req.reserved)
+	}
 
 	// We could have lost our only record buffer just before we grabbed the
 	// source lock above.
@@ -677,6 +756,30 @@ func (s *source) createReq() *fetchRequest {
 	return req
 }
 
+// fetchTargetBudget returns the total bytes we allow to be buffered or
+// requested with FetchTargetBufferedBytes, and how much is currently used.
+func (c *consumer) fetchTargetBudget() (budget, used int64) {
+	budget = int64(c.cl.cfg.fetchTargetBufferedBytes) * c.numCursors.Load()
+	used = c.bufferedBytes.Load() + c.fetchReserved.Load()
+	return budget, used
+}
+
+// maybeResumeTargetFetches is called whenever buffered or reserved bytes
+// drop. If we have a buffer target and we have drained to our low watermark
+// of half the budget, we trigger every source to fetch again. Sources that
+// are already fetching are unaffected.
+func (c *consumer) maybeResumeTargetFetches() {
+	if c.cl.cfg.fetchTargetBufferedBytes <= 0 {
+		return
+	}
+	if budget, used := c.fetchTargetBudget(); used*2 > budget {
+		return
+	}
+	c.cl.allSinksAndSources(func(sns sinkAndSource) {
+		sns.eachSource(func(s *source) { s.maybeConsume() })
+	})
+}
+
 func (s *source) maybeConsume() {
 	if s.fetchState.maybeBegin() {
 		go s.loopFetch()
@@ -767,6 +870,7 @@ func (s *source) killSessionOnClose(ctx context.Context) {
 		maxPartBytes:   1,
 		rack:           s.cl.cfg.rack,
 		isolationLevel: s.cl.cfg.isolationLevel,
+		shard:          s.shard,
 		session:        s.session,
 	}
 	ch := make(chan struct{})
@@ -816,7 +920,17 @@ func (s *source) fetch(consumerSession *consumerSession, doneFetch chan<- struct
 		}
 	}()
 
-	if req.numOffsets == 0 { // cursors could have been set unusable
+	// Any bytes we reserved for FetchTargetBufferedBytes are released once
+	// we are done, at which point whatever we buffered is tracked in
+	// bufferedBytes.
+	defer func() {
+		if req.reserved > 0 {
+			s.cl.consumer.fetchReserved.Add(-req.reserved)
+			s.cl.consumer.maybeResumeTargetFetches()
+		}
+	}()
+
+	if req.numOffsets == 0 { // cursors could have been set unusable, or we are over our buffer target
 		return fetched
 	}
 
@@ -857,7 +971,7 @@ func (s *source) fetch(consumerSession *consumerSession, doneFetch chan<- struct
 
 		s.cl.triggerUpdateMetadata(false, fmt.Sprintf("opportunistic load during source backoff: %v", why)) // as good a time as any
 		s.consecutiveFailures++
-		after := time.NewTimer(s.cl.cfg.retryBackoff(s.consecutiveFailures))
+		after := time.NewTimer(s.cl.cfg.backoff(backoffFetch, s.consecutiveFailures))
 		defer after.Stop()
 		select {
 		case <-after.C:
@@ -1168,7 +1282,8 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 				// no reset offset was configured. If so, we ignore
 				// trying to reset and instead keep our failed partition.
 				addList := func(replica int32, log bool) {
-					if s.cl.cfg.resetOffset.noReset {
+					resetOffset := s.cl.cfg.resetOffsetFor(topic, partition)
+					if resetOffset.noReset {
 						keep = true
 					} else if !c.lastConsumedTime.IsZero() {
 						reloadOffsets.addLoad(topic, partition, loadTypeList, offsetLoad{
@@ -1186,7 +1301,7 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 					} else {
 						reloadOffsets.addLoad(topic, partition, loadTypeList, offsetLoad{
 							replica: replica,
-							Offset:  s.cl.cfg.resetOffset,
+							Offset:  resetOffset,
 						})
 						if log {
 							s.cl.cfg.logger.Log(LogLevelInfo, "received OFFSET_OUT_OF_RANGE on the first fetch, resetting to the configured ConsumeResetOffset",
@@ -1289,6 +1404,11 @@ func (s *source) handleReqResp(br *broker, req *fetchRequest, resp *kmsg.FetchRe
 func (o *cursorOffsetNext) processRespPartition(br *broker, rp *kmsg.FetchResponseTopicPartition, decompressor Decompressor, hooks hooks) (fp FetchPartition) {
 	if rp.ErrorCode == 0 {
 		o.hwm = rp.HighWatermark
+		wm := &o.from.watermarks
+		wm.hwm.Store(rp.HighWatermark)
+		wm.lso.Store(rp.LastStableOffset)
+		wm.logStart.Store(rp.LogStartOffset)
+		wm.seen.Store(true)
 	}
 	opts := ProcessFetchPartitionOpts{
 		KeepControlRecords:   br.cl.cfg.keepControl,
@@ -1299,17 +1419,35 @@ func (o *cursorOffsetNext) processRespPartition(br *broker, rp *kmsg.FetchRespon
 		Partition:            o.from.partition,
 		Pools:                br.cl.cfg.pools,
 	}
+	fetchOffset := o.offset
 	fp, o.offset = ProcessFetchPartition(opts, rp, decompressor, func(m FetchBatchMetrics) {
+		br.cl.stats.observeConsumed(br.meta.NodeID, o.from.topic, m)
 		hooks.each(func(h Hook) {
 			if h, ok := h.(HookFetchBatchRead); ok {
 				h.OnFetchBatchRead(br.meta, o.from.topic, o.from.partition, m)
 			}
 		})
 	})
+	if opts.IsolationLevel.level == 1 && rp.ErrorCode == 0 {
+		m := FetchReadCommittedMetrics{
+			FetchOffset:         fetchOffset,
+			HighWatermark:       rp.HighWatermark,
+			LastStableOffset:    rp.LastStableOffset,
+			AbortedTransactions: fp.AbortedTransactions,
+			AbortedRecords:      fp.AbortedRecords,
+			NumRecords:          len(fp.Records),
+		}
+		hooks.each(func(h Hook) {
+			if h, ok := h.(HookFetchReadCommitted); ok {
+				h.OnFetchReadCommitted(br.meta, o.from.topic, o.from.partition, m)
+			}
+		})
+	}
 	if len(fp.Records) > 0 {
 		lastRecord := fp.Records[len(fp.Records)-1]
 		o.lastConsumedEpoch = lastRecord.LeaderEpoch
 		o.lastConsumedTime = lastRecord.Timestamp
+		o.lastConsumedOffset = lastRecord.Offset
 	}
 
 	return fp
@@ -1367,6 +1505,7 @@ func ProcessFetchPartition(o ProcessFetchPartitionOpts, rp *kmsg.FetchResponseTo
 	var aborter aborter
 	if o.IsolationLevel.level == 1 {
 		aborter = buildAborter(rp)
+		fp.AbortedTransactions = len(rp.AbortedTransactions)
 	}
 
 	// A response could contain any of message v0, message v1, or record
@@ -1918,6 +2057,8 @@ func (o *ProcessFetchPartitionOpts) maybeKeepRecord(fp *FetchPartition, record *
 	// We only keep control records if specifically requested.
 	if record.Attrs.IsControl() {
 		abort = !o.KeepControlRecords
+	} else if abort {
+		fp.AbortedRecords++
 	}
 	if !abort {
 		fp.Records = append(fp.Records, record)
@@ -2031,10 +2172,12 @@ type fetchRequest struct {
 	minBytes     int32
 	maxBytes     int32
 	maxPartBytes int32
+	reserved     int64 // bytes reserved against FetchTargetBufferedBytes
 	rack         string
 
 	isolationLevel int8
 	preferLagFn    PreferLagFn
+	shard          int // the source shard, which picks the fetch connection
 
 	numOffsets  int
 	usedOffsets usedOffsets
@@ -0,0 +1,41 @@
+package kgo
+
+import (
+	"crypto/tls"
+	"errors"
+	"net"
+)
+
+// tuneTCPConn applies any TCP options to a newly dialed connection. Options
+// are only applied if the connection is (or wraps) a *net.TCPConn; custom
+// dialers returning anything else are left alone.
+func tuneTCPConn(cfg *cfg, conn net.Conn) error {
+	if cfg.tcpKeepAlive == nil && cfg.tcpUserTimeout <= 0 && cfg.tcpReadBuffer <= 0 && cfg.tcpWriteBuffer <= 0 && !cfg.setTCPNoDelay {
+		return nil
+	}
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	tcp, ok := conn.(*net.TCPConn)
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	if ka := cfg.tcpKeepAlive; ka != nil {
+		errs = append(errs, tcp.SetKeepAliveConfig(*ka))
+	}
+	if cfg.tcpUserTimeout > 0 {
+		errs = append(errs, setTCPUserTimeout(tcp, cfg.tcpUserTimeout))
+	}
+	if cfg.tcpReadBuffer > 0 {
+		errs = append(errs, tcp.SetReadBuffer(cfg.tcpReadBuffer))
+	}
+	if cfg.tcpWriteBuffer > 0 {
+		errs = append(errs, tcp.SetWriteBuffer(cfg.tcpWriteBuffer))
+	}
+	if cfg.setTCPNoDelay {
+		errs = append(errs, tcp.SetNoDelay(cfg.tcpNoDelay))
+	}
+	return errors.Join(errs...)
+}
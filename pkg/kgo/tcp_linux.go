@@ -0,0 +1,24 @@
+package kgo
+
+import (
+	"net"
+	"syscall"
+	"time"
+)
+
+// The syscall package does not export TCP_USER_TIMEOUT.
+const tcpUserTimeout = 0x12
+
+func setTCPUserTimeout(conn *net.TCPConn, timeout time.Duration) error {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var serr error
+	if err := raw.Control(func(fd uintptr) {
+		serr = syscall.SetsockoptInt(int(fd), syscall.IPPROTO_TCP, tcpUserTimeout, int(timeout.Milliseconds()))
+	}); err != nil {
+		return err
+	}
+	return serr
+}
@@ -0,0 +1,68 @@
+package kgo
+
+import (
+	"net"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestTuneTCPConn(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			defer c.Close()
+			var b [1]byte
+			c.Read(b[:])
+		}
+	}()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	cfg := defaultCfg()
+	for _, opt := range []Opt{
+		TCPKeepAlive(net.KeepAliveConfig{Enable: true, Idle: 5 * time.Second, Interval: 2 * time.Second, Count: 3}),
+		TCPUserTimeout(30 * time.Second),
+		TCPNoDelay(false),
+	} {
+		opt.apply(&cfg)
+	}
+	if err := tuneTCPConn(&cfg, conn); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := conn.(*net.TCPConn).SyscallConn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct {
+		name  string
+		level int
+		opt   int
+		exp   int
+	}{
+		{"keepalive", syscall.SOL_SOCKET, syscall.SO_KEEPALIVE, 1},
+		{"keepalive idle", syscall.IPPROTO_TCP, syscall.TCP_KEEPIDLE, 5},
+		{"keepalive interval", syscall.IPPROTO_TCP, syscall.TCP_KEEPINTVL, 2},
+		{"keepalive count", syscall.IPPROTO_TCP, syscall.TCP_KEEPCNT, 3},
+		{"user timeout", syscall.IPPROTO_TCP, tcpUserTimeout, 30000},
+		{"nodelay", syscall.IPPROTO_TCP, syscall.TCP_NODELAY, 0},
+	} {
+		var got int
+		var gerr error
+		raw.Control(func(fd uintptr) { got, gerr = syscall.GetsockoptInt(int(fd), test.level, test.opt) })
+		if gerr != nil {
+			t.Errorf("%s: %v", test.name, gerr)
+		} else if got != test.exp {
+			t.Errorf("%s: got %d != exp %d", test.name, got, test.exp)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package kgo
+
+import (
+	"net"
+	"time"
+)
+
+func setTCPUserTimeout(*net.TCPConn, time.Duration) error { return nil }
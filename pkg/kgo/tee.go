@@ -0,0 +1,179 @@
+package kgo
+
+import (
+	"errors"
+	"slices"
+	"sync"
+)
+
+// TeeStats contains counters for records that were teed to a secondary
+// client with TeeProduce. Divergence is when a record succeeds on one client
+// and fails on the other.
+type TeeStats struct {
+	// Teed is the number of records that were copied to and finished
+	// producing on the tee client, successfully or not.
+	Teed int64
+
+	// Dropped is the number of records that were not copied because the
+	// tee client had the maximum amount of records or bytes buffered.
+	// Records are only dropped when teeing asynchronously.
+	Dropped int64
+
+	// BothSucceeded is the number of teed records that were produced
+	// successfully to both clients.
+	BothSucceeded int64
+
+	// BothFailed is the number of teed records that failed on both
+	// clients.
+	BothFailed int64
+
+	// PrimaryOnlyFailed is the number of teed records that failed on the
+	// producing client but succeeded on the tee client.
+	PrimaryOnlyFailed int64
+
+	// TeeOnlyFailed is the number of teed records that succeeded on the
+	// producing client but failed on the tee client.
+	TeeOnlyFailed int64
+}
+
+// Diverged returns the number of teed records that succeeded on one client
+// and failed on the other.
+func (s TeeStats) Diverged() int64 { return s.PrimaryOnlyFailed + s.TeeOnlyFailed }
+
+type teeStats struct {
+	teed              atomicI64
+	dropped           atomicI64
+	bothSucceeded     atomicI64
+	bothFailed        atomicI64
+	primaryOnlyFailed atomicI64
+	teeOnlyFailed     atomicI64
+}
+
+// TeeStats returns counters for records teed to the client configured with
+// TeeProduce. All counters are zero if the client is not teeing.
+func (cl *Client) TeeStats() TeeStats {
+	s := &cl.producer.tee
+	return TeeStats{
+		Teed:              s.teed.Load(),
+		Dropped:           s.dropped.Load(),
+		BothSucceeded:     s.bothSucceeded.Load(),
+		BothFailed:        s.bothFailed.Load(),
+		PrimaryOnlyFailed: s.primaryOnlyFailed.Load(),
+		TeeOnlyFailed:     s.teeOnlyFailed.Load(),
+	}
+}
+
+// teeResult tracks the results of a record produced to both clients; whichever
+// finishes second counts the result.
+type teeResult struct {
+	mu         sync.Mutex
+	done       int
+	dropped    bool
+	primaryErr error
+	teeErr     error
+}
+
+// finish records a client's result and returns whether this was the second
+// client to finish.
+func (t *teeResult) finish(s *teeStats, primary, dropped bool, err error) (second bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if primary {
+		t.primaryErr = err
+	} else {
+		t.teeErr, t.dropped = err, dropped
+	}
+	if t.done++; t.done < 2 {
+		return false
+	}
+	if t.dropped {
+		return true
+	}
+	switch {
+	case t.primaryErr == nil && t.teeErr == nil:
+		s.bothSucceeded.Add(1)
+	case t.primaryErr != nil && t.teeErr != nil:
+		s.bothFailed.Add(1)
+	case t.primaryErr != nil:
+		s.primaryOnlyFailed.Add(1)
+	default:
+		s.teeOnlyFailed.Add(1)
+	}
+	return true
+}
+
+// shouldTee returns whether a record for the topic should be teed.
+func (cfg *cfg) shouldTee(topic string) bool {
+	if cfg.teeClient == nil {
+		return false
+	}
+	if len(cfg.teeTopics) == 0 {
+		return true
+	}
+	_, ok := cfg.teeTopics[topic]
+	return ok
+}
+
+// tee produces a copy of the record to the tee client and returns the promise
+// to use when producing the original record.
+func (cl *Client) tee(r *Record, promise func(*Record, error)) func(*Record, error) {
+	var (
+		s   = &cl.producer.tee
+		res = new(teeResult)
+
+		// With synchronous teeing, if the original record finishes
+		// first, it is held here until the tee finishes. We do not
+		// block the producer's promise goroutine waiting.
+		heldR   *Record
+		heldErr error
+		cp      = &Record{
+			Key:       slices.Clone(r.Key),
+			Value:     slices.Clone(r.Value),
+			Headers:   slices.Clone(r.Headers),
+			Timestamp: r.Timestamp,
+			Topic:     r.Topic,
+			Partition: r.Partition,
+			Context:   r.Context,
+		}
+		teePromise = func(_ *Record, err error) {
+			if errors.Is(err, ErrMaxBuffered) && !cl.cfg.teeSync {
+				s.dropped.Add(1)
+				res.finish(s, false, true, err)
+				return
+			}
+			s.teed.Add(1)
+			if err != nil {
+				cl.cfg.logger.Log(LogLevelDebug, "teed record failed", "topic", cp.Topic, "err", err)
+			}
+			if res.finish(s, false, false, err) && cl.cfg.teeSync {
+				promise(heldR, heldErr)
+				cl.producer.releaseBuffered(cp.Topic)
+			}
+		}
+	)
+
+	// Synchronous teeing blocks while the tee client is full;
+	// asynchronous teeing is best effort and drops the copy instead.
+	if cl.cfg.teeSync {
+		cl.cfg.teeClient.Produce(cp.Context, cp, teePromise)
+	} else {
+		cl.cfg.teeClient.TryProduce(cp.Context, cp, teePromise)
+	}
+
+	if !cl.cfg.teeSync {
+		return func(r *Record, err error) {
+			res.finish(s, true, false, err)
+			promise(r, err)
+		}
+	}
+	return func(r *Record, err error) {
+		// We hold the record as buffered before finishing, since the
+		// tee can finish and release the record as soon as we do.
+		heldR, heldErr = r, err
+		cl.producer.holdBuffered(r.Topic)
+		if res.finish(s, true, false, err) {
+			cl.producer.releaseBuffered(r.Topic)
+			promise(r, err)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// describeTopicMaxBytesTimeout bounds how long we wait to describe a topic's
+// max.message.bytes, so that a hung request does not leave the topic loading
+// until the client is closed.
+const describeTopicMaxBytesTimeout = 30 * time.Second
+
+// topicMaxBytes tracks max.message.bytes per topic for
+// ValidateTopicMaxMessageBytes.
+type topicMaxBytes struct {
+	mu sync.Mutex
+	m  map[string]*topicMaxBytesEntry
+}
+
+type topicMaxBytesEntry struct {
+	max     int32     // 0 if unknown
+	loaded  time.Time // when we last attempted to load; zero while loading
+	loading bool
+}
+
+// singleRecordBatchBytes returns the size of a record if it were encoded
+// alone in an uncompressed record batch, which is what the broker compares
+// against max.message.bytes in the worst case.
+func singleRecordBatchBytes(r *Record) int32 {
+	nums := new(recBatch).calculateRecordNumbers(r)
+	return recordBatchOverhead - 4 + nums.wireLength() // -4: the batch array length is not part of the batch
+}
+
+// compresses returns whether c may compress batches. We only know that our
+// own compressor with none as its first preference never compresses.
+func compresses(c Compressor) bool {
+	if c == nil {
+		return false
+	}
+	cc, ok := c.(*compressor)
+	return !ok || len(cc.options) > 0 && cc.options[0] != CodecNone
+}
+
+// checkTopicMaxBytes returns an error if the record is larger than the
+// learned max.message.bytes for its topic. If the limit for the topic is not
+// yet known (or is stale), this triggers an asynchronous load and the record
+// passes. Records are not checked if batches may be compressed, since a
+// record over the limit may compress under it.
+func (cl *Client) checkTopicMaxBytes(r *Record) error {
+	if compresses(cl.compressor()) {
+		return nil
+	}
+	t := &cl.producer.topicMaxBytes
+	t.mu.Lock()
+	if t.m == nil {
+		t.m = make(map[string]*topicMaxBytesEntry)
+	}
+	e := t.m[r.Topic]
+	if e == nil {
+		e = new(topicMaxBytesEntry)
+		t.m[r.Topic] = e
+	}
+	if !e.loading && time.Since(e.loaded) > cl.cfg.metadataMaxAge.load() {
+		e.loading = true
+		go cl.loadTopicMaxBytes(r.Topic, e)
+	}
+	max := e.max
+	t.mu.Unlock()
+
+	if max <= 0 {
+		return nil
+	}
+	if size := singleRecordBatchBytes(r); size > max {
+		return &ErrRecordTooLarge{Topic: r.Topic, Size: size, MaxMessageBytes: max}
+	}
+	return nil
+}
+
+// loadTopicMaxBytes describes max.message.bytes for a topic. Failures are
+// logged and the load is retried once the metadata max age passes.
+func (cl *Client) loadTopicMaxBytes(topic string, e *topicMaxBytesEntry) {
+	max, err := cl.describeTopicMaxBytes(topic)
+
+	t := &cl.producer.topicMaxBytes
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	e.loading = false
+	e.loaded = time.Now()
+	if err != nil {
+		cl.cfg.logger.Log(LogLevelWarn, "unable to describe topic max.message.bytes, records to this topic will not be validated until the next attempt", "topic", topic, "err", err)
+		return
+	}
+	e.max = max
+	cl.cfg.logger.Log(LogLevelDebug, "learned topic max.message.bytes", "topic", topic, "max_message_bytes", max)
+}
+
+func (cl *Client) describeTopicMaxBytes(topic string) (int32, error) {
+	req := kmsg.NewPtrDescribeConfigsRequest()
+	rr := kmsg.NewDescribeConfigsRequestResource()
+	rr.ResourceType = kmsg.ConfigResourceTypeTopic
+	rr.ResourceName = topic
+	rr.ConfigNames = []string{"max.message.bytes"}
+	req.Resources = append(req.Resources, rr)
+
+	ctx, cancel := context.WithTimeout(cl.ctx, describeTopicMaxBytesTimeout)
+	defer cancel()
+	resp, err := req.RequestWith(ctx, cl)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range resp.Resources {
+		if err := errCodeMessage(r.ErrorCode, r.ErrorMessage); err != nil {
+			return 0, err
+		}
+		for _, c := range r.Configs {
+			if c.Name != "max.message.bytes" || c.Value == nil {
+				continue
+			}
+			max, err := strconv.ParseInt(*c.Value, 10, 32)
+			if err != nil {
+				return 0, err
+			}
+			return int32(max), nil
+		}
+	}
+	return 0, errors.New("max.message.bytes missing from the DescribeConfigs response")
+}
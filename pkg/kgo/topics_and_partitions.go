@@ -734,10 +734,7 @@ func (k *kip951move) ensureSinksAndSources(cl *Client) {
 		if _, exists := cl.sinksAndSources[leader]; exists {
 			return
 		}
-		cl.sinksAndSources[leader] = sinkAndSource{
-			sink:   cl.newSink(leader),
-			source: cl.newSource(leader),
-		}
+		cl.sinksAndSources[leader] = cl.newSinkAndSource(leader)
 	}
 
 	for _, td := range k.recBufs {
@@ -851,7 +848,7 @@ func (k *kip951move) doMove(cl *Client) {
 			}
 		} else {
 			new.cursor = &cursor{
-				source:             sns.source,
+				source:             sns.sourceFor(d.topic, partition),
 				topicPartitionData: new.topicPartitionData,
 			}
 		}
@@ -223,6 +223,36 @@ func (s *GroupTransactSession) failed() bool {
 	return s.revoked || s.lost
 }
 
+// GroupTransactSessionStatus is the status of a GroupTransactSession, as
+// returned from Status.
+type GroupTransactSessionStatus struct {
+	TransactionState
+
+	// Revoked and Lost are whether partitions were revoked or lost since
+	// the transaction began. If either is true, End aborts.
+	Revoked bool
+	Lost    bool
+
+	// PendingOffsets are the uncommitted offsets that End will commit in
+	// the transaction, if committing.
+	PendingOffsets map[string]map[int32]EpochOffset
+}
+
+// Status returns the current status of the session: the client's transaction
+// state, whether the session will abort on End due to a rebalance, and the
+// offsets End will commit. This waits for any concurrent Begin or End.
+func (s *GroupTransactSession) Status() GroupTransactSessionStatus {
+	ts, _ := s.cl.TransactionState() // a session is always transactional
+	st := GroupTransactSessionStatus{
+		TransactionState: ts,
+		PendingOffsets:   s.cl.UncommittedOffsets(),
+	}
+	s.failMu.Lock()
+	st.Revoked, st.Lost = s.revoked, s.lost
+	s.failMu.Unlock()
+	return st
+}
+
 // End ends a transaction, committing if commit is true, if the group did not
 // rebalance since the transaction began, and if committing offsets is
 // successful. If any of these conditions are false, this aborts. This flushes
@@ -428,7 +458,7 @@ retry:
 
 		case errors.Is(endTxnErr, kerr.UnknownServerError):
 			s.cl.cfg.logger.Log(LogLevelInfo, "end transaction with commit unknown server error; retrying")
-			after := time.NewTimer(s.cl.cfg.retryBackoff(tries))
+			after := time.NewTimer(s.cl.cfg.backoff(backoffProduce, tries))
 			select {
 			case <-after.C: // context canceled; we will see when we retry
 			case <-s.cl.ctx.Done():
@@ -478,7 +508,7 @@ retry:
 // This must not be called concurrently with other client functions.
 func (cl *Client) BeginTransaction() error {
 	if cl.cfg.txnID == nil {
-		return errNotTransactional
+		return ErrNotTransactional
 	}
 
 	cl.producer.txnMu.Lock()
@@ -488,6 +518,12 @@ func (cl *Client) BeginTransaction() error {
 		return errors.New("invalid attempt to begin a transaction while already in a transaction")
 	}
 
+	if cl.cfg.txnIDPerMember {
+		if err := cl.maybeRotateTxnID(); err != nil {
+			return err
+		}
+	}
+
 	needRecover, didRecover, err := cl.maybeRecoverProducerID(context.Background())
 	if needRecover && !didRecover {
 		cl.cfg.logger.Log(LogLevelInfo, "unable to begin transaction due to unrecoverable producer id error", "err", err)
@@ -499,11 +535,43 @@ func (cl *Client) BeginTransaction() error {
 		cl.producer.tx890p2 = cl.supportsKIP890p2()
 	}
 	cl.producer.producingTxn.Store(true) // allow produces for txns now
-	cl.cfg.logger.Log(LogLevelInfo, "beginning transaction", "transactional_id", *cl.cfg.txnID)
+	cl.cfg.logger.Log(LogLevelInfo, "beginning transaction", "transactional_id", *cl.producer.txnID.Load())
 
 	return nil
 }
 
+// maybeRotateTxnID, for TransactionalIDPerGroupMember, switches the client's
+// transactional ID to one based on the current group member if the member
+// changed since the last transaction. This must be called under txnMu and not
+// within a transaction, when nothing is being produced.
+func (cl *Client) maybeRotateTxnID() error {
+	member := cl.cfg.instanceID
+	if member == nil {
+		memberID, _ := cl.GroupMetadata()
+		if memberID == "" {
+			return errors.New("unable to begin transaction: TransactionalIDPerGroupMember requires the client to join the group first")
+		}
+		member = &memberID
+	}
+	id := *cl.cfg.txnID + "-" + *member
+
+	p := &cl.producer
+	prior := p.txnID.Load()
+	if *prior == id {
+		return nil
+	}
+	p.idMu.Lock()
+	p.txnID.Store(&id)
+	p.id.Store(&producerID{
+		id:    -1,
+		epoch: -1,
+		err:   errReloadProducerID,
+	})
+	p.idMu.Unlock()
+	cl.cfg.logger.Log(LogLevelInfo, "switching transactional id to the id for our current group member", "prior_transactional_id", *prior, "transactional_id", id)
+	return nil
+}
+
 // EndBeginTxnHow controls the safety of how EndAndBeginTransaction executes.
 type EndBeginTxnHow uint8
 
@@ -595,6 +663,154 @@ func (cl *Client) AbortBufferedRecords(ctx context.Context) error {
 	return cl.Flush(ctx)
 }
 
+// AbortBufferedTopicRecords is a scoped version of AbortBufferedRecords: this
+// fails unflushed records with ErrAborting only for the given topics and
+// partitions, and waits for there to be no buffered records for the given
+// topics. If a topic maps to no partitions, all partitions of the topic are
+// aborted, including records that are waiting for the topic to be loaded.
+// Records for other topics and partitions are unaffected, which allows
+// selectively shedding load without failing unrelated records.
+//
+// This returns the number of records that were failed with ErrAborting while
+// aborting per topic and partition. Records that were waiting for the topic to
+// be loaded have no partition yet and are counted under partition -1. If
+// multiple calls to abort concurrently cover the same partition, records
+// failed in that partition are counted in each call.
+//
+// Like AbortBufferedRecords, records are only aborted at safe points, and
+// quitting the wait early with the context may lead to an invalid state. If
+// only some partitions of a topic are aborted, this still waits for buffered
+// records in the other partitions of the topic to be produced.
+func (cl *Client) AbortBufferedTopicRecords(ctx context.Context, topics map[string][]int32) (map[string]map[int32]int64, error) {
+	if len(topics) == 0 {
+		return nil, nil
+	}
+	s := &abortScope{
+		topics:  make(map[string]map[int32]bool, len(topics)),
+		aborted: make(map[string]map[int32]int64),
+	}
+	flush := make([]string, 0, len(topics))
+	for topic, partitions := range topics {
+		var ps map[int32]bool
+		if len(partitions) > 0 {
+			ps = make(map[int32]bool, len(partitions))
+			for _, partition := range partitions {
+				ps[partition] = true
+			}
+		}
+		s.topics[topic] = ps
+		flush = append(flush, topic)
+	}
+
+	p := &cl.producer
+	p.addAbortScope(s)
+	defer p.removeAbortScope(s)
+
+	cl.cfg.logger.Log(LogLevelInfo, "aborting buffered records for topics; continuing to wait via flushing topics", "topics", topics)
+	defer cl.cfg.logger.Log(LogLevelDebug, "aborted buffered records for topics", "topics", topics)
+
+	// Same as AbortBufferedRecords, we clear unknown topics ourselves, but
+	// only if the entire topic is being aborted.
+	p.unknownTopicsMu.Lock()
+	for topic, unknown := range p.unknownTopics {
+		if ps, exists := s.topics[topic]; exists && ps == nil {
+			select {
+			case unknown.fatal <- ErrAborting:
+			default:
+			}
+		}
+	}
+	p.unknownTopicsMu.Unlock()
+
+	err := cl.FlushTopic(ctx, flush...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	aborted := s.aborted
+	s.aborted = nil // counting after we return is pointless
+	return aborted, err
+}
+
+// abortScope is an in progress AbortBufferedTopicRecords.
+type abortScope struct {
+	topics map[string]map[int32]bool // nil partitions => all partitions
+
+	mu      sync.Mutex
+	aborted map[string]map[int32]int64
+}
+
+func (s *abortScope) covers(topic string, partition int32) bool {
+	ps, exists := s.topics[topic]
+	return exists && (ps == nil || partition >= 0 && ps[partition])
+}
+
+func (p *producer) addAbortScope(s *abortScope) {
+	p.abortScopesMu.Lock()
+	defer p.abortScopesMu.Unlock()
+	p.abortScopes = append(p.abortScopes, s)
+	p.abortScopesN.Add(1)
+}
+
+func (p *producer) removeAbortScope(s *abortScope) {
+	p.abortScopesMu.Lock()
+	defer p.abortScopesMu.Unlock()
+	for i, exist := range p.abortScopes {
+		if exist == s {
+			p.abortScopes = append(p.abortScopes[:i], p.abortScopes[i+1:]...)
+			break
+		}
+	}
+	p.abortScopesN.Add(-1)
+}
+
+// isAbortingPartition returns whether AbortBufferedTopicRecords is aborting
+// the given partition.
+func (p *producer) isAbortingPartition(topic string, partition int32) bool {
+	if p.abortScopesN.Load() == 0 {
+		return false
+	}
+	p.abortScopesMu.Lock()
+	defer p.abortScopesMu.Unlock()
+	for _, s := range p.abortScopes {
+		if s.covers(topic, partition) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAbortingTopic returns whether AbortBufferedTopicRecords is aborting all
+// partitions of the given topic.
+func (p *producer) isAbortingTopic(topic string) bool {
+	return p.isAbortingPartition(topic, -1)
+}
+
+// countAborted counts n records failed in a partition (or -1 for records in
+// an unknown topic) for all AbortBufferedTopicRecords covering the partition,
+// if the records are failing due to aborting.
+func (p *producer) countAborted(topic string, partition int32, n int, err error) {
+	if n == 0 || p.abortScopesN.Load() == 0 || !errors.Is(err, ErrAborting) {
+		return
+	}
+	p.abortScopesMu.Lock()
+	defer p.abortScopesMu.Unlock()
+	for _, s := range p.abortScopes {
+		if !s.covers(topic, partition) {
+			continue
+		}
+		s.mu.Lock()
+		if s.aborted != nil {
+			ps := s.aborted[topic]
+			if ps == nil {
+				ps = make(map[int32]int64)
+				s.aborted[topic] = ps
+			}
+			ps[partition] += int64(n)
+		}
+		s.mu.Unlock()
+	}
+}
+
 // UnsafeAbortBufferedRecords fails all unflushed records with ErrAborted and
 // waits for there to be no buffered records. This function does NOT wait for
 // any inflight produce requests to finish, meaning topics in the client may be
@@ -708,7 +924,7 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 	}
 
 	cl.cfg.logger.Log(LogLevelInfo, "ending transaction",
-		"transactional_id", *cl.cfg.txnID,
+		"transactional_id", *cl.producer.txnID.Load(),
 		"commit", commit,
 		"producer_id", id,
 		"epoch", epoch,
@@ -716,7 +932,7 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 
 	err = cl.doWithConcurrentTransactions(ctx, "EndTxn", func() error {
 		req := kmsg.NewPtrEndTxnRequest()
-		req.TransactionalID = *cl.cfg.txnID
+		req.TransactionalID = *cl.producer.txnID.Load()
 		req.ProducerID = id
 		req.ProducerEpoch = epoch
 		req.Commit = bool(commit)
@@ -738,7 +954,7 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 			})
 			cl.resetAllProducerSequences()
 			cl.cfg.logger.Log(LogLevelInfo, "end transaction response successfully received",
-				"transactional_id", *cl.cfg.txnID,
+				"transactional_id", *cl.producer.txnID.Load(),
 				"commit", commit,
 				"prior_id", id,
 				"prior_epoch", epoch,
@@ -747,14 +963,14 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 			)
 		} else {
 			cl.cfg.logger.Log(LogLevelInfo, "end transaction response successfully received",
-				"transactional_id", *cl.cfg.txnID,
+				"transactional_id", *cl.producer.txnID.Load(),
 				"commit", commit,
 				"producer_id", id,
 				"epoch", epoch,
 			)
 			if !cl.producer.tx890p2 && cl.supportsKIP890p2() {
 				cl.cfg.logger.Log(LogLevelInfo, "end transaction noticed the cluster now supports KIP-890p2, reloading the producer ID and opting in",
-					"transactional_id", *cl.cfg.txnID,
+					"transactional_id", *cl.producer.txnID.Load(),
 					"producer_id", id,
 					"epoch", epoch,
 				)
@@ -768,6 +984,14 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 		return nil
 	})
 
+	cl.producer.lastTxnEnd = &TransactionEndResult{
+		Commit:        bool(commit),
+		ProducerID:    id,
+		ProducerEpoch: epoch,
+		Err:           err,
+		At:            time.Now(),
+	}
+
 	// If the returned error is still a Kafka error, this is fatal and we
 	// need to fail our producer ID we loaded above.
 	//
@@ -784,6 +1008,91 @@ func (cl *Client) EndTransaction(ctx context.Context, commit TransactionEndTry)
 	return err
 }
 
+// TransactionEndResult is the result of the last EndTxn request issued when
+// ending a transaction.
+type TransactionEndResult struct {
+	// Commit is whether the transaction was being committed or aborted.
+	Commit bool
+	// ProducerID and ProducerEpoch are what the transaction was ended
+	// with.
+	ProducerID    int64
+	ProducerEpoch int16
+	// Err is the error ending the transaction, if any.
+	Err error
+	// At is when the transaction finished ending.
+	At time.Time
+}
+
+// TransactionState is a snapshot of a transactional client's state, as
+// returned from TransactionState.
+type TransactionState struct {
+	// TransactionalID is the client's transactional ID.
+	TransactionalID string
+
+	// InTransaction is whether BeginTransaction has been called without
+	// a following EndTransaction.
+	InTransaction bool
+
+	// ProducerID and ProducerEpoch are the client's current producer ID
+	// and epoch, or -1 if not yet loaded. ProducerIDErr is non-nil if the
+	// producer ID is failed or must be reloaded.
+	ProducerID    int64
+	ProducerEpoch int16
+	ProducerIDErr error
+
+	// AddedPartitions are the partitions added to the current
+	// transaction, i.e., partitions that have been produced to.
+	AddedPartitions map[string][]int32
+
+	// OffsetsAdded is whether group offsets have been added to the
+	// current transaction, which happens when committing offsets in a
+	// transaction.
+	OffsetsAdded bool
+
+	// LastEnd is the result of the last transaction ended with an EndTxn
+	// request, or nil if no transaction has been ended. Ending a
+	// transaction that produced nothing does not issue a request.
+	LastEnd *TransactionEndResult
+}
+
+// TransactionState returns a snapshot of the client's transactional state,
+// which can be used to assert on transactional invariants in applications or
+// tests. This waits for any concurrent BeginTransaction or EndTransaction and
+// returns ErrNotTransactional if the client is not transactional.
+func (cl *Client) TransactionState() (TransactionState, error) {
+	if cl.cfg.txnID == nil {
+		return TransactionState{}, ErrNotTransactional
+	}
+
+	p := &cl.producer
+	p.txnMu.Lock()
+	defer p.txnMu.Unlock()
+
+	id := p.id.Load().(*producerID)
+	st := TransactionState{
+		TransactionalID: *cl.producer.txnID.Load(),
+		InTransaction:   p.inTxn,
+		ProducerID:      id.id,
+		ProducerEpoch:   id.epoch,
+		ProducerIDErr:   id.err,
+		LastEnd:         p.lastTxnEnd,
+	}
+	for topic, parts := range p.topics.load() {
+		for _, part := range parts.load().partitions {
+			if part.records.addedToTxn.Load() {
+				if st.AddedPartitions == nil {
+					st.AddedPartitions = make(map[string][]int32)
+				}
+				st.AddedPartitions[topic] = append(st.AddedPartitions[topic], part.records.partition)
+			}
+		}
+	}
+	if g := cl.consumer.g; g != nil {
+		st.OffsetsAdded = g.offsetsAddedToTxn
+	}
+	return st, nil
+}
+
 // This returns if it is necessary to recover the producer ID (it has an
 // error), whether it is possible to recover, and, if not, the error.
 //
@@ -900,7 +1209,7 @@ func (cl *Client) commitTransactionOffsets(
 	defer cl.cfg.logger.Log(LogLevelDebug, "left commitTransactionOffsets")
 
 	if cl.cfg.txnID == nil {
-		onDone(nil, nil, errNotTransactional)
+		onDone(nil, nil, ErrNotTransactional)
 		return nil
 	}
 
@@ -917,13 +1226,13 @@ func (cl *Client) commitTransactionOffsets(
 	}
 	defer unlockTxn()
 	if !cl.producer.inTxn {
-		onDone(nil, nil, errNotInTransaction)
+		onDone(nil, nil, ErrNotInTransaction)
 		return nil
 	}
 
 	g := cl.consumer.g
 	if g == nil {
-		onDone(kmsg.NewPtrTxnOffsetCommitRequest(), kmsg.NewPtrTxnOffsetCommitResponse(), errNotGroup)
+		onDone(kmsg.NewPtrTxnOffsetCommitRequest(), kmsg.NewPtrTxnOffsetCommitResponse(), ErrNotGroup)
 		return nil
 	}
 
@@ -982,13 +1291,13 @@ func (cl *Client) addOffsetsToTxn(ctx context.Context, group string) error {
 
 	err = cl.doWithConcurrentTransactions(ctx, "AddOffsetsToTxn", func() error { // committing offsets without producing causes a transaction to begin within Kafka
 		cl.cfg.logger.Log(LogLevelInfo, "issuing AddOffsetsToTxn",
-			"txn", *cl.cfg.txnID,
+			"txn", *cl.producer.txnID.Load(),
 			"producerID", id,
 			"producerEpoch", epoch,
 			"group", group,
 		)
 		req := kmsg.NewPtrAddOffsetsToTxnRequest()
-		req.TransactionalID = *cl.cfg.txnID
+		req.TransactionalID = *cl.producer.txnID.Load()
 		req.ProducerID = id
 		req.ProducerEpoch = epoch
 		req.Group = group
@@ -1090,7 +1399,7 @@ func (g *groupConsumer) prepareTxnOffsetCommit(ctx context.Context, uncommitted
 		return req, err
 	}
 
-	req.TransactionalID = *g.cl.cfg.txnID
+	req.TransactionalID = *g.cl.producer.txnID.Load()
 	req.Group = g.cfg.group
 	req.ProducerID = id
 	req.ProducerEpoch = epoch
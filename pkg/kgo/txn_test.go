@@ -265,3 +265,93 @@ func (c *testConsumer) transact(txnsBeforeQuit int) {
 		c.mu.Unlock()
 	}
 }
+
+func TestTransactionState(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	t.Cleanup(cleanup)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	nonTxn, _ := newTestClient()
+	defer nonTxn.Close()
+	if _, err := nonTxn.TransactionState(); !errors.Is(err, ErrNotTransactional) {
+		t.Errorf("got err %v != exp ErrNotTransactional", err)
+	}
+
+	txnID := "p" + randsha()
+	cl, _ := newTestClient(TransactionalID(txnID), DefaultProduceTopic(topic))
+	defer cl.Close()
+
+	st, err := cl.TransactionState()
+	if err != nil || st.TransactionalID != txnID || st.InTransaction || st.LastEnd != nil {
+		t.Fatalf("unexpected initial state %+v, err %v", st, err)
+	}
+
+	if err := cl.BeginTransaction(); err != nil {
+		t.Fatalf("unable to begin transaction: %v", err)
+	}
+	if err := cl.ProduceSync(ctx, StringRecord("v")).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	st, _ = cl.TransactionState()
+	if !st.InTransaction || len(st.AddedPartitions[topic]) != 1 || st.ProducerID < 0 || st.ProducerIDErr != nil {
+		t.Errorf("unexpected in transaction state %+v", st)
+	}
+
+	if err := cl.EndTransaction(ctx, TryCommit); err != nil {
+		t.Fatalf("unable to end transaction: %v", err)
+	}
+	st, _ = cl.TransactionState()
+	if st.InTransaction || len(st.AddedPartitions) != 0 || st.LastEnd == nil || !st.LastEnd.Commit || st.LastEnd.Err != nil {
+		t.Errorf("unexpected ended state %+v", st)
+	}
+}
+
+func TestTransactionalIDPerGroupMember(t *testing.T) {
+	t.Parallel()
+
+	if err := ValidateOpts(TransactionalID("p"), TransactionalIDPerGroupMember()); err == nil {
+		t.Error("unexpected success validating TransactionalIDPerGroupMember without a group")
+	}
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	t.Cleanup(cleanup)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	prefix := "p" + randsha()
+	sess, err := NewGroupTransactSession(testClientOpts(
+		TransactionalID(prefix),
+		TransactionalIDPerGroupMember(),
+		ConsumerGroup(randsha()),
+		ConsumeTopics(topic),
+		DefaultProduceTopic(topic),
+	)...)
+	if err != nil {
+		t.Fatalf("unable to create session: %v", err)
+	}
+	defer sess.Close()
+	cl := sess.Client()
+
+	// We produce a record to consume, ensuring we join the group.
+	prod, _ := newTestClient(DefaultProduceTopic(topic))
+	defer prod.Close()
+	if err := prod.ProduceSync(ctx, StringRecord("v")).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if fs := sess.PollFetches(ctx); fs.Err0() != nil {
+		t.Fatalf("unable to poll: %v", fs.Err0())
+	}
+
+	if err := sess.Begin(); err != nil {
+		t.Fatalf("unable to begin: %v", err)
+	}
+	memberID, _ := cl.GroupMetadata()
+	if st := sess.Status(); st.TransactionalID != prefix+"-"+memberID || !st.InTransaction {
+		t.Errorf("got transactional id %q (in txn? %v) != exp %q", st.TransactionalID, st.InTransaction, prefix+"-"+memberID)
+	}
+}
@@ -0,0 +1,240 @@
+package kgo
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+)
+
+// Headers added to records produced to a TypedClient's dead letter topic; see
+// [TypedDeadLetterTopic].
+const (
+	// DeadLetterTopicHeader is the topic the record was consumed from.
+	DeadLetterTopicHeader = "kgo-dlq-topic"
+
+	// DeadLetterPartitionHeader is the partition the record was consumed
+	// from, as a base 10 string.
+	DeadLetterPartitionHeader = "kgo-dlq-partition"
+
+	// DeadLetterOffsetHeader is the offset the record was consumed from,
+	// as a base 10 string.
+	DeadLetterOffsetHeader = "kgo-dlq-offset"
+
+	// DeadLetterErrorHeader is the error that caused the record to be
+	// dead lettered.
+	DeadLetterErrorHeader = "kgo-dlq-error"
+)
+
+// Codec encodes and decodes typed record keys or values for a TypedClient.
+type Codec[T any] interface {
+	// Encode returns the serialized form of v.
+	Encode(v T) ([]byte, error)
+	// Decode returns the value deserialized from b.
+	Decode(b []byte) (T, error)
+}
+
+type jsonCodec[T any] struct{}
+
+func (jsonCodec[T]) Encode(v T) ([]byte, error) { return json.Marshal(v) }
+
+func (jsonCodec[T]) Decode(b []byte) (T, error) {
+	var v T
+	err := json.Unmarshal(b, &v)
+	return v, err
+}
+
+// JSONCodec returns a Codec that encodes and decodes T with encoding/json.
+func JSONCodec[T any]() Codec[T] { return jsonCodec[T]{} }
+
+type stringCodec struct{}
+
+func (stringCodec) Encode(v string) ([]byte, error) { return []byte(v), nil }
+func (stringCodec) Decode(b []byte) (string, error) { return string(b), nil }
+
+// StringCodec returns a Codec that converts between strings and bytes.
+func StringCodec() Codec[string] { return stringCodec{} }
+
+type bytesCodec struct{}
+
+func (bytesCodec) Encode(v []byte) ([]byte, error) { return v, nil }
+func (bytesCodec) Decode(b []byte) ([]byte, error) { return b, nil }
+
+// BytesCodec returns a Codec that passes bytes through unchanged. This is
+// useful for keys when only values are typed, or to produce nil keys.
+func BytesCodec() Codec[[]byte] { return bytesCodec{} }
+
+// TypedOpt is an option to configure a TypedClient.
+type TypedOpt interface {
+	apply(*typedCfg)
+}
+
+type typedOpt struct{ fn func(*typedCfg) }
+
+func (o typedOpt) apply(cfg *typedCfg) { o.fn(cfg) }
+
+type typedCfg struct {
+	topic string
+	dlq   string
+}
+
+// TypedProduceTopic sets the topic ProduceValue produces to, overriding the
+// default of the client's DefaultProduceTopic.
+func TypedProduceTopic(topic string) TypedOpt {
+	return typedOpt{func(cfg *typedCfg) { cfg.topic = topic }}
+}
+
+// TypedDeadLetterTopic sets a topic that records which fail to decode in
+// PollTyped are produced to, unchanged, with headers describing where the
+// record came from and why it failed (see DeadLetterTopicHeader and the
+// related headers). Records are dead lettered before PollTyped returns, so
+// committing offsets after processing a poll does not lose failed records.
+//
+// Without a dead letter topic, a record that fails to decode is skipped and
+// its partition's Err field is set, if not already set.
+func TypedDeadLetterTopic(topic string) TypedOpt {
+	return typedOpt{func(cfg *typedCfg) { cfg.dlq = topic }}
+}
+
+// TypedRecord is a record with its key and value decoded.
+type TypedRecord[K, V any] struct {
+	Key    K       // Key is the decoded record key.
+	Value  V       // Value is the decoded record value.
+	Record *Record // Record is the underlying record.
+}
+
+// TypedClient wraps a Client to produce and consume records with typed keys
+// and values, encoding and decoding them with the configured codecs. The
+// wrapped client is still used directly for everything else, such as
+// committing and closing.
+type TypedClient[K, V any] struct {
+	cl    *Client
+	cfg   typedCfg
+	key   Codec[K]
+	value Codec[V]
+}
+
+// NewTypedClient returns a TypedClient that uses cl with the given key and
+// value codecs.
+func NewTypedClient[K, V any](cl *Client, key Codec[K], value Codec[V], opts ...TypedOpt) *TypedClient[K, V] {
+	tc := &TypedClient[K, V]{cl: cl, key: key, value: value}
+	for _, opt := range opts {
+		opt.apply(&tc.cfg)
+	}
+	return tc
+}
+
+// Client returns the wrapped client.
+func (tc *TypedClient[K, V]) Client() *Client { return tc.cl }
+
+// ProduceValue encodes key and value, produces them with any headers, and
+// waits for the record to be produced, returning the produced record.
+func (tc *TypedClient[K, V]) ProduceValue(ctx context.Context, key K, value V, headers ...RecordHeader) (*Record, error) {
+	kb, err := tc.key.Encode(key)
+	if err != nil {
+		return nil, &RecordEncodeError{Key: true, Err: err}
+	}
+	vb, err := tc.value.Encode(value)
+	if err != nil {
+		return nil, &RecordEncodeError{Err: err}
+	}
+	r := &Record{Topic: tc.cfg.topic, Key: kb, Value: vb, Headers: headers}
+	return r, tc.cl.ProduceSync(ctx, r).FirstErr()
+}
+
+// PollTyped polls fetches and decodes every record, returning the decoded
+// records in order along with the fetches for error handling. Records that
+// fail to decode are either dead lettered, if using TypedDeadLetterTopic, or
+// are skipped and set their partition's Err field. If dead lettering fails,
+// the partition's Err field is set as well.
+func (tc *TypedClient[K, V]) PollTyped(ctx context.Context) ([]TypedRecord[K, V], Fetches) {
+	fetches := tc.cl.PollFetches(ctx)
+
+	var (
+		recs  = make([]TypedRecord[K, V], 0, fetches.NumRecords())
+		dead  []*Record
+		deadp []*FetchPartition
+	)
+	for i := range fetches {
+		for j := range fetches[i].Topics {
+			t := &fetches[i].Topics[j]
+			for k := range t.Partitions {
+				p := &t.Partitions[k]
+				for _, r := range p.Records {
+					tr, err := tc.decode(r)
+					if err == nil {
+						recs = append(recs, tr)
+						continue
+					}
+					if tc.cfg.dlq == "" {
+						if p.Err == nil {
+							p.Err = err
+						}
+						continue
+					}
+					dead = append(dead, tc.deadLetter(r, err))
+					deadp = append(deadp, p)
+				}
+			}
+		}
+	}
+
+	if len(dead) > 0 {
+		for i, res := range tc.cl.ProduceSync(ctx, dead...) {
+			if res.Err == nil {
+				continue
+			}
+			tc.cl.cfg.logger.Log(LogLevelWarn, "unable to produce undecodable record to dead letter topic", "topic", string(dead[i].Headers[0].Value), "dlq", tc.cfg.dlq, "err", res.Err)
+			if p := deadp[i]; p.Err == nil {
+				p.Err = res.Err
+			}
+		}
+	}
+	return recs, fetches
+}
+
+func (tc *TypedClient[K, V]) decode(r *Record) (TypedRecord[K, V], error) {
+	tr := TypedRecord[K, V]{Record: r}
+	var err error
+	if tr.Key, err = tc.key.Decode(r.Key); err != nil {
+		return tr, &RecordDecodeError{r, err}
+	}
+	if tr.Value, err = tc.value.Decode(r.Value); err != nil {
+		return tr, &RecordDecodeError{r, err}
+	}
+	return tr, nil
+}
+
+// deadLetter returns a copy of r to be produced to the dead letter topic.
+func (tc *TypedClient[K, V]) deadLetter(r *Record, err error) *Record {
+	headers := make([]RecordHeader, 0, len(r.Headers)+4)
+	headers = append(headers,
+		RecordHeader{DeadLetterTopicHeader, []byte(r.Topic)},
+		RecordHeader{DeadLetterPartitionHeader, []byte(strconv.FormatInt(int64(r.Partition), 10))},
+		RecordHeader{DeadLetterOffsetHeader, []byte(strconv.FormatInt(r.Offset, 10))},
+		RecordHeader{DeadLetterErrorHeader, []byte(err.Error())},
+	)
+	headers = append(headers, r.Headers...)
+	return &Record{
+		Topic:     tc.cfg.dlq,
+		Key:       r.Key,
+		Value:     r.Value,
+		Headers:   headers,
+		Timestamp: r.Timestamp,
+	}
+}
+
+// RecordEncodeError is an error encoding a record key or value in
+// TypedClient.ProduceValue.
+type RecordEncodeError struct {
+	Key bool  // Key is true if the key failed to encode, false if the value failed.
+	Err error // Err is the encode error.
+}
+
+func (e *RecordEncodeError) Error() string {
+	if e.Key {
+		return "unable to encode record key: " + e.Err.Error()
+	}
+	return "unable to encode record value: " + e.Err.Error()
+}
+
+func (e *RecordEncodeError) Unwrap() error { return e.Err }
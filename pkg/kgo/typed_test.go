@@ -0,0 +1,109 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTypedClient(t *testing.T) {
+	t.Parallel()
+
+	topic, cleanup := tmpTopicPartitions(t, 1)
+	defer cleanup()
+	dlq, dlqCleanup := tmpTopicPartitions(t, 1)
+	defer dlqCleanup()
+
+	type event struct {
+		ID int `json:"id"`
+	}
+
+	cl, _ := newTestClient(
+		ConsumeTopics(topic),
+		ConsumeResetOffset(NewOffset().AtStart()),
+	)
+	defer cl.Close()
+	tc := NewTypedClient(cl, StringCodec(), JSONCodec[event](), TypedProduceTopic(topic), TypedDeadLetterTopic(dlq))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := tc.ProduceValue(ctx, "a", event{1}, RecordHeader{"h", []byte("v")}); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if err := cl.ProduceSync(ctx, &Record{Topic: topic, Key: []byte("bad"), Value: []byte("{")}).FirstErr(); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+	if _, err := tc.ProduceValue(ctx, "b", event{2}); err != nil {
+		t.Fatalf("unable to produce: %v", err)
+	}
+
+	var recs []TypedRecord[string, event]
+	for len(recs) < 2 {
+		polled, fetches := tc.PollTyped(ctx)
+		if err := fetches.Err0(); err != nil {
+			t.Fatalf("unexpected fetch error: %v", err)
+		}
+		recs = append(recs, polled...)
+	}
+	if len(recs) != 2 || recs[0].Key != "a" || recs[0].Value.ID != 1 || recs[1].Key != "b" || recs[1].Value.ID != 2 {
+		t.Fatalf("got unexpected typed records %+v", recs)
+	}
+	if h := recs[0].Record.Headers; len(h) != 1 || h[0].Key != "h" {
+		t.Errorf("got unexpected headers %v", h)
+	}
+
+	dcl, _ := newTestClient(
+		ConsumeTopics(dlq),
+		ConsumeResetOffset(NewOffset().AtStart()),
+	)
+	defer dcl.Close()
+	var dead *Record
+	for dead == nil {
+		fetches := dcl.PollFetches(ctx)
+		if err := fetches.Err0(); err != nil {
+			t.Fatalf("unexpected dlq fetch error: %v", err)
+		}
+		fetches.EachRecord(func(r *Record) { dead = r })
+	}
+	if string(dead.Key) != "bad" || string(dead.Value) != "{" {
+		t.Errorf("got unexpected dead letter %q => %q", dead.Key, dead.Value)
+	}
+	exp := map[string]string{
+		DeadLetterTopicHeader:     topic,
+		DeadLetterPartitionHeader: "0",
+		DeadLetterOffsetHeader:    "1",
+	}
+	for _, h := range dead.Headers {
+		if v, ok := exp[h.Key]; ok {
+			if string(h.Value) != v {
+				t.Errorf("header %s: got %q != exp %q", h.Key, h.Value, v)
+			}
+			delete(exp, h.Key)
+		}
+	}
+	if len(exp) != 0 {
+		t.Errorf("missing dead letter headers %v", exp)
+	}
+
+	var encErr *RecordEncodeError
+	bad := NewTypedClient(cl, StringCodec(), JSONCodec[chan int](), TypedProduceTopic(topic))
+	if _, err := bad.ProduceValue(ctx, "k", make(chan int)); !errors.As(err, &encErr) || encErr.Key {
+		t.Errorf("got unexpected encode error %v", err)
+	}
+}
+
+func TestTypedClientDecode(t *testing.T) {
+	t.Parallel()
+
+	tc := NewTypedClient(nil, BytesCodec(), JSONCodec[map[string]int]())
+	if tr, err := tc.decode(&Record{Key: []byte("k"), Value: []byte(`{"id":1}`)}); err != nil || string(tr.Key) != "k" || tr.Value["id"] != 1 {
+		t.Errorf("got unexpected decode %+v, %v", tr, err)
+	}
+	r := &Record{Value: []byte("{")}
+	var de *RecordDecodeError
+	if _, err := tc.decode(r); !errors.As(err, &de) || de.Record != r {
+		t.Errorf("got unexpected decode error %v", err)
+	}
+}
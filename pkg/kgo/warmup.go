@@ -0,0 +1,185 @@
+package kgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// connectReq is an internal request that is never written: issuing it to a
+// broker only opens the connection that the wrapped request would use.
+type connectReq struct{ kmsg.Request }
+
+// Warmup prepares the client so that the first produce and poll after
+// startup does not pay multiple round trips of latency. This:
+//
+//   - loads metadata for the input topics (which are then tracked as topics
+//     being produced to) and for any topics being consumed,
+//   - opens produce connections to the leaders of every partition of the
+//     input topics and fetch connections to the leaders of every partition
+//     being consumed,
+//   - initializes the producer ID, if the client is idempotent or
+//     transactional, and
+//   - waits for the first group assignment, if consuming as a group and the
+//     client is using WarmupJoinsGroup.
+//
+// Warmup waits until every topic has loaded, or returns the first topic load
+// error that is not retryable. Topics that do not exist (and are not auto
+// created) are retried until the context is canceled, so you likely want to
+// use a context with a deadline. Warmup returns all connection errors joined
+// together.
+//
+// Calling this function is optional; everything it does is otherwise done
+// lazily as the client is used.
+func (cl *Client) Warmup(ctx context.Context, topics ...string) error {
+	if len(topics) > 0 {
+		p := &cl.producer
+		p.topicsMu.Lock()
+		var missing []string
+		loaded := p.topics.load()
+		for _, topic := range topics {
+			if _, exists := loaded[topic]; !exists {
+				missing = append(missing, topic)
+			}
+		}
+		if len(missing) > 0 {
+			p.topics.storeTopics(missing)
+		}
+		p.topicsMu.Unlock()
+	}
+
+	produceLeaders, fetchLeaders, err := cl.warmupMetadata(ctx, topics)
+	if err != nil {
+		return err
+	}
+
+	var (
+		wg    sync.WaitGroup
+		errMu sync.Mutex
+		errs  []error
+	)
+	connect := func(leaders map[int32]struct{}, req kmsg.Request) {
+		for leader := range leaders {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				br, err := cl.brokerOrErr(ctx, leader, errUnknownBroker)
+				if err == nil {
+					_, err = br.waitResp(ctx, &connectReq{req})
+				}
+				if err != nil {
+					errMu.Lock()
+					errs = append(errs, fmt.Errorf("unable to connect to broker %d: %w", leader, err))
+					errMu.Unlock()
+				}
+			}()
+		}
+	}
+	connect(produceLeaders, kmsg.NewPtrProduceRequest())
+	connect(fetchLeaders, kmsg.NewPtrFetchRequest())
+
+	if cl.idempotent() && len(topics) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, _, err := cl.producerID(func() context.Context { return ctx }); err != nil {
+				errMu.Lock()
+				errs = append(errs, err)
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	if g := cl.consumer.g; g != nil && cl.cfg.warmupJoinGroup {
+		select {
+		case <-g.firstAssigned:
+		case <-g.manageDone:
+			return ErrClientClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-cl.ctx.Done():
+			return ErrClientClosed
+		}
+	}
+	return nil
+}
+
+// warmupMetadata waits until all input produce topics and all consumed topics
+// have loaded, returning the leaders for produce and consume partitions.
+func (cl *Client) warmupMetadata(ctx context.Context, topics []string) (produceLeaders, fetchLeaders map[int32]struct{}, err error) {
+	for tries := 0; ; tries++ {
+		produceLeaders, fetchLeaders = make(map[int32]struct{}), make(map[int32]struct{})
+		pending := false
+		gather := func(tps topicsPartitionsData, only []string, leaders map[int32]struct{}) error {
+			check := func(topic string, parts *topicPartitions) error {
+				if parts == nil {
+					pending = true
+					return nil
+				}
+				v := parts.load()
+				if len(v.partitions) == 0 {
+					if v.loadErr != nil && !kerr.IsRetriable(v.loadErr) && !isRetryableBrokerErr(v.loadErr) {
+						return fmt.Errorf("unable to load topic %s: %w", topic, v.loadErr)
+					}
+					pending = true
+					return nil
+				}
+				for _, p := range v.partitions {
+					if p.loadErr != nil || p.leader < 0 {
+						pending = true
+						continue
+					}
+					leaders[p.leader] = struct{}{}
+				}
+				return nil
+			}
+			if only != nil {
+				for _, topic := range only {
+					if err := check(topic, tps[topic]); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			for topic, parts := range tps {
+				if err := check(topic, parts); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if len(topics) > 0 {
+			if err := gather(cl.producer.topics.load(), topics, produceLeaders); err != nil {
+				return nil, nil, err
+			}
+		}
+		if c := &cl.consumer; c.g != nil {
+			err = gather(c.g.tps.load(), nil, fetchLeaders)
+		} else if c.d != nil {
+			err = gather(c.d.tps.load(), nil, fetchLeaders)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if !pending {
+			return produceLeaders, fetchLeaders, nil
+		}
+
+		cl.triggerUpdateMetadataNow("warming up the client")
+		if !cl.waitTries(ctx, cl.cfg.backoff(backoffMetadata, tries)) {
+			if err := ctx.Err(); err != nil {
+				return nil, nil, err
+			}
+			return nil, nil, ErrClientClosed
+		}
+	}
+}
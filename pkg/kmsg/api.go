@@ -28,6 +28,9 @@ package kmsg
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"sort"
 
 	"github.com/twmb/franz-go/pkg/kmsg/internal/kbin"
@@ -253,17 +256,16 @@ func StringPtr(in string) *string {
 // point of this type is that it does not contain a version number inside it,
 // but it is versioned: if decoding v1 fails, this falls back to v0.
 func (s *StickyMemberMetadata) ReadFrom(src []byte) error {
-	return s.readFrom(src, false)
+	return s.readFrom(kbin.Reader{Src: src}, false)
 }
 
 // UnsafeReadFrom is the same as ReadFrom, but uses unsafe slice to string
 // conversions to reduce garbage.
 func (s *StickyMemberMetadata) UnsafeReadFrom(src []byte) error {
-	return s.readFrom(src, true)
+	return s.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (s *StickyMemberMetadata) readFrom(src []byte, unsafe bool) error {
-	b := kbin.Reader{Src: src}
+func (s *StickyMemberMetadata) readFrom(b kbin.Reader, unsafe bool) error {
 	numAssignments := b.ArrayLen()
 	if numAssignments < 0 {
 		numAssignments = 0
@@ -322,6 +324,47 @@ func (s *StickyMemberMetadata) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// DecodeLimits bounds what is decoded in ReadFromLimited and
+// UnsafeReadFromLimited, allowing untrusted input to be decoded without a
+// malicious length prefix causing large allocations. A zero value for any
+// field means no limit.
+//
+// MaxNesting limits the depth of tagged fields within tagged fields.
+type DecodeLimits = kbin.Limits
+
+// DecodeLimitError is returned from ReadFromLimited and UnsafeReadFromLimited
+// if decoding stopped because a length exceeded one of the limits.
+type DecodeLimitError = kbin.LimitError
+
+// limitedReader is implemented by every type in this package that has
+// ReadFrom.
+type limitedReader interface {
+	readFrom(kbin.Reader, bool) error
+}
+
+var errNoDecodeLimits = errors.New("type does not support decoding with limits")
+
+// ReadFromLimited is the same as v.ReadFrom(src), but fails with a
+// *DecodeLimitError if any string, bytes, or array length exceeds limits.
+// This only supports types in this package.
+func ReadFromLimited(v interface{ ReadFrom([]byte) error }, src []byte, limits DecodeLimits) error {
+	return readFromLimited(v, src, limits, false)
+}
+
+// UnsafeReadFromLimited is the same as ReadFromLimited, but uses unsafe slice
+// to string conversions to reduce garbage, as in UnsafeReadFrom.
+func UnsafeReadFromLimited(v interface{ ReadFrom([]byte) error }, src []byte, limits DecodeLimits) error {
+	return readFromLimited(v, src, limits, true)
+}
+
+func readFromLimited(v any, src []byte, limits DecodeLimits, unsafe bool) error {
+	r, ok := v.(limitedReader)
+	if !ok {
+		return errNoDecodeLimits
+	}
+	return r.readFrom(kbin.Reader{Src: src, Limits: &limits}, unsafe)
+}
+
 // TagReader has is a type that has the ability to skip tags.
 //
 // This is effectively a trimmed version of the kbin.Reader, with the purpose
@@ -365,17 +408,23 @@ func ReadTags(b TagReader) Tags {
 }
 
 // internalReadTags reads tags in a reader and returns the tags from a
-// duplicated inner kbin.Reader.
-func internalReadTags(b *kbin.Reader) Tags {
+// duplicated inner kbin.Reader. If not unsafe, tag values are copied.
+func internalReadTags(b *kbin.Reader, unsafe bool) Tags {
 	var t Tags
 	for num := b.Uvarint(); num > 0; num-- {
 		key, size := b.Uvarint(), b.Uvarint()
-		t.Set(key, b.Span(int(size)))
+		t.setRead(key, b.Span(int(size)), unsafe)
 	}
 	return t
 }
 
 // Tags is an opaque structure capturing unparsed tags.
+//
+// Any tag Kafka sends that this package does not know about is kept as is
+// when reading and is written back unchanged when appending. This allows
+// proxies built on this package to forward fields added in newer Kafka
+// versions without stripping them. To find every unknown tag in a message,
+// see FindUnknownTags.
 type Tags struct {
 	keyvals map[uint32][]byte
 }
@@ -383,6 +432,25 @@ type Tags struct {
 // Len returns the number of keyvals in Tags.
 func (t *Tags) Len() int { return len(t.keyvals) }
 
+// Get returns the value for a tag's key and whether the key exists.
+func (t *Tags) Get(key uint32) ([]byte, bool) {
+	val, ok := t.keyvals[key]
+	return val, ok
+}
+
+// Delete deletes a tag's key, if it exists.
+func (t *Tags) Delete(key uint32) { delete(t.keyvals, key) }
+
+// Keys returns all keys in the tags, in sorted order.
+func (t *Tags) Keys() []uint32 {
+	keys := make([]uint32, 0, len(t.keyvals))
+	for key := range t.keyvals {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
+
 // Each calls fn for each key and val in the tags.
 func (t *Tags) Each(fn func(uint32, []byte)) {
 	if len(t.keyvals) == 0 {
@@ -412,6 +480,15 @@ func (t *Tags) Set(key uint32, val []byte) {
 	t.keyvals[key] = val
 }
 
+// setRead sets a tag's key and val while decoding, copying the val if not
+// decoding unsafely so that the tags do not alias the decode input.
+func (t *Tags) setRead(key uint32, val []byte, unsafe bool) {
+	if !unsafe {
+		val = append([]byte(nil), val...)
+	}
+	t.Set(key, val)
+}
+
 // AppendEach appends each keyval in tags to dst and returns the updated dst.
 func (t *Tags) AppendEach(dst []byte) []byte {
 	t.Each(func(key uint32, val []byte) {
@@ -422,6 +499,72 @@ func (t *Tags) AppendEach(dst []byte) []byte {
 	return dst
 }
 
+// UnknownTag is an unknown tag found in a message by FindUnknownTags.
+type UnknownTag struct {
+	// Path is the path to the struct containing the tag from the top
+	// level message, e.g. "Topics[0].Partitions[2]". Tags in the top
+	// level message have an empty path.
+	Path string
+
+	Key uint32 // Key is the tag's key.
+	Val []byte // Val is the tag's raw, unparsed value.
+}
+
+// FindUnknownTags returns every unknown tag in v, which can be any struct or
+// pointer to a struct in this package. This recurses through all nested
+// structs and arrays. Tags are returned in the order they are found: parent
+// struct tags before nested tags, array elements in order, and keys within a
+// struct in ascending order.
+//
+// This is useful for proxies to detect fields added in newer Kafka versions.
+// Unknown tags are always preserved on round trip, so this is purely
+// informational.
+func FindUnknownTags(v any) []UnknownTag {
+	var found []UnknownTag
+	findUnknownTags(reflect.ValueOf(v), "", &found)
+	return found
+}
+
+var tagsType = reflect.TypeOf(Tags{})
+
+func findUnknownTags(v reflect.Value, path string, found *[]UnknownTag) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			findUnknownTags(v.Elem(), path, found)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return // []byte fields cannot contain tags
+		}
+		for i := 0; i < v.Len(); i++ {
+			findUnknownTags(v.Index(i), fmt.Sprintf("%s[%d]", path, i), found)
+		}
+	case reflect.Struct:
+		if v.Type() == tagsType {
+			return
+		}
+		t := v.Type()
+		if f, ok := t.FieldByName("UnknownTags"); ok && f.Type == tagsType {
+			tags := v.FieldByIndex(f.Index).Interface().(Tags)
+			tags.Each(func(key uint32, val []byte) {
+				*found = append(*found, UnknownTag{Path: path, Key: key, Val: val})
+			})
+		}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Type == tagsType {
+				continue
+			}
+			fpath := f.Name
+			if path != "" {
+				fpath = path + "." + f.Name
+			}
+			findUnknownTags(v.Field(i), fpath, found)
+		}
+	}
+}
+
 ////////////////////////
 // DEPRECATED RENAMES //
 ////////////////////////
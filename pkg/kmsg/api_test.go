@@ -0,0 +1,157 @@
+package kmsg
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+// unknownTagsResponse returns a flexible MetadataResponse with unknown tags at
+// the top level and within nested structs.
+func unknownTagsResponse() *MetadataResponse {
+	resp := NewPtrMetadataResponse()
+	resp.Version = 12
+	resp.UnknownTags.Set(100, []byte("top"))
+	resp.UnknownTags.Set(99, []byte{})
+
+	b := NewMetadataResponseBroker()
+	b.Host = "localhost"
+	b.UnknownTags.Set(7, []byte("broker"))
+	resp.Brokers = append(resp.Brokers, b)
+
+	for i := 0; i < 2; i++ {
+		t := NewMetadataResponseTopic()
+		t.Topic = StringPtr("foo")
+		for p := int32(0); p < 2; p++ {
+			rp := NewMetadataResponseTopicPartition()
+			rp.Partition = p
+			if i == 1 && p == 1 {
+				rp.UnknownTags.Set(201, []byte("p1b"))
+				rp.UnknownTags.Set(200, []byte("p1a"))
+			}
+			t.Partitions = append(t.Partitions, rp)
+		}
+		resp.Topics = append(resp.Topics, t)
+	}
+	return resp
+}
+
+func TestUnknownTagsRoundTrip(t *testing.T) {
+	orig := unknownTagsResponse()
+	encoded := orig.AppendTo(nil)
+
+	read := NewPtrMetadataResponse()
+	read.Version = orig.Version
+	if err := read.ReadFrom(append([]byte(nil), encoded...)); err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []UnknownTag{
+		{Path: "", Key: 99, Val: []byte{}},
+		{Path: "", Key: 100, Val: []byte("top")},
+		{Path: "Brokers[0]", Key: 7, Val: []byte("broker")},
+		{Path: "Topics[1].Partitions[1]", Key: 200, Val: []byte("p1a")},
+		{Path: "Topics[1].Partitions[1]", Key: 201, Val: []byte("p1b")},
+	}
+	for _, v := range []any{orig, read, *read} {
+		got := FindUnknownTags(v)
+		if len(got) != len(exp) {
+			t.Fatalf("%T: got %d unknown tags %v != exp %d", v, len(got), got, len(exp))
+		}
+		for i := range exp {
+			if got[i].Path != exp[i].Path || got[i].Key != exp[i].Key || !bytes.Equal(got[i].Val, exp[i].Val) {
+				t.Errorf("%T: tag %d: got %+v != exp %+v", v, i, got[i], exp[i])
+			}
+		}
+	}
+
+	// Unknown tags are written back unchanged.
+	if reencoded := read.AppendTo(nil); !bytes.Equal(reencoded, encoded) {
+		t.Errorf("round trip changed the encoding:\ngot %x\nexp %x", reencoded, encoded)
+	}
+
+	if got := FindUnknownTags(NewPtrMetadataResponse()); len(got) != 0 {
+		t.Errorf("got unknown tags %v in a message without any", got)
+	}
+}
+
+func TestUnknownTagsReadCopies(t *testing.T) {
+	encoded := unknownTagsResponse().AppendTo(nil)
+
+	safe := NewPtrMetadataResponse()
+	safe.Version = 12
+	unsafe := NewPtrMetadataResponse()
+	unsafe.Version = 12
+	safeIn := append([]byte(nil), encoded...)
+	unsafeIn := append([]byte(nil), encoded...)
+	if err := safe.ReadFrom(safeIn); err != nil {
+		t.Fatal(err)
+	}
+	if err := unsafe.UnsafeReadFrom(unsafeIn); err != nil {
+		t.Fatal(err)
+	}
+
+	// Safe reads copy tag values, so modifying the input must not modify
+	// the tags. Unsafe reads alias the input.
+	for i := range safeIn {
+		safeIn[i] = 0
+		unsafeIn[i] = 0
+	}
+	if v, _ := safe.UnknownTags.Get(100); string(v) != "top" {
+		t.Errorf("safe read tag aliased the input, got %q", v)
+	}
+	if v, _ := safe.Topics[1].Partitions[1].UnknownTags.Get(200); string(v) != "p1a" {
+		t.Errorf("safe read nested tag aliased the input, got %q", v)
+	}
+	if v, _ := unsafe.UnknownTags.Get(100); string(v) == "top" {
+		t.Error("unsafe read tag unexpectedly copied the input")
+	}
+}
+
+func TestTagsAccessors(t *testing.T) {
+	var tags Tags
+	if tags.Len() != 0 || len(tags.Keys()) != 0 {
+		t.Error("zero Tags is not empty")
+	}
+	if _, ok := tags.Get(1); ok {
+		t.Error("Get on zero Tags found a key")
+	}
+	tags.Delete(1) // must not panic on nil keyvals
+
+	tags.Set(3, []byte("c"))
+	tags.Set(1, []byte("a"))
+	tags.Set(2, nil)
+	if got := tags.Keys(); !reflect.DeepEqual(got, []uint32{1, 2, 3}) {
+		t.Errorf("got keys %v != exp [1 2 3]", got)
+	}
+	if v, ok := tags.Get(2); !ok || v != nil {
+		t.Errorf("got %v, %v for key with nil value, exp nil, true", v, ok)
+	}
+	if v, ok := tags.Get(3); !ok || string(v) != "c" {
+		t.Errorf("got %q, %v != exp c, true", v, ok)
+	}
+
+	tags.Delete(2)
+	if got := tags.Keys(); !reflect.DeepEqual(got, []uint32{1, 3}) || tags.Len() != 2 {
+		t.Errorf("got keys %v after delete != exp [1 3]", got)
+	}
+	if _, ok := tags.Get(2); ok {
+		t.Error("deleted key still exists")
+	}
+
+	// Deleting an unknown tag drops it from the encoding.
+	resp := unknownTagsResponse()
+	resp.UnknownTags.Delete(100)
+	resp.Topics[1].Partitions[1].UnknownTags.Delete(201)
+	read := NewPtrMetadataResponse()
+	read.Version = resp.Version
+	if err := read.ReadFrom(resp.AppendTo(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if got := read.UnknownTags.Keys(); !reflect.DeepEqual(got, []uint32{99}) {
+		t.Errorf("got top level keys %v != exp [99]", got)
+	}
+	if got := read.Topics[1].Partitions[1].UnknownTags.Keys(); !reflect.DeepEqual(got, []uint32{200}) {
+		t.Errorf("got nested keys %v != exp [200]", got)
+	}
+}
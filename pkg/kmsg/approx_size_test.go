@@ -0,0 +1,119 @@
+package kmsg
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// fillRandom fills v with random values, including unknown tags, nulls, and
+// arrays of up to three elements.
+func fillRandom(rng *rand.Rand, v reflect.Value, depth int) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(rng.Intn(2) == 0)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(rng.Int63() - rng.Int63())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(rng.Uint64())
+	case reflect.Float64:
+		v.SetFloat(rng.NormFloat64())
+	case reflect.String:
+		v.SetString(randomString(rng))
+	case reflect.Ptr:
+		if rng.Intn(4) == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		fillRandom(rng, p.Elem(), depth)
+		v.Set(p)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			if rng.Intn(4) == 0 {
+				v.SetBytes(nil)
+			} else {
+				b := make([]byte, rng.Intn(200))
+				rng.Read(b)
+				v.SetBytes(b)
+			}
+			return
+		}
+		if depth > 4 || rng.Intn(4) == 0 {
+			v.Set(reflect.Zero(v.Type()))
+			return
+		}
+		s := reflect.MakeSlice(v.Type(), rng.Intn(4), 4)
+		for i := 0; i < s.Len(); i++ {
+			fillRandom(rng, s.Index(i), depth+1)
+		}
+		v.Set(s)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fillRandom(rng, v.Index(i), depth)
+		}
+	case reflect.Struct:
+		if v.Type() == tagsType {
+			var tags Tags
+			for i := rng.Intn(3); i > 0; i-- {
+				b := make([]byte, rng.Intn(300))
+				rng.Read(b)
+				tags.Set(uint32(1000+rng.Intn(1<<20)), b)
+			}
+			v.Set(reflect.ValueOf(tags))
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fillRandom(rng, v.Field(i), depth)
+			}
+		}
+	}
+}
+
+func randomString(rng *rand.Rand) string {
+	b := make([]byte, rng.Intn(200))
+	for i := range b {
+		b[i] = byte('a' + rng.Intn(26))
+	}
+	return string(b)
+}
+
+func TestApproxSize(t *testing.T) {
+	type message interface {
+		MaxVersion() int16
+		SetVersion(int16)
+		AppendTo([]byte) []byte
+		ApproxSize(int16) int
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	for key := int16(0); key <= MaxKey; key++ {
+		req := RequestForKey(key)
+		if req == nil {
+			continue
+		}
+		for _, kind := range []any{req, req.ResponseKind()} {
+			typ := reflect.TypeOf(kind).Elem()
+			msg, ok := kind.(message)
+			if !ok {
+				t.Errorf("%s does not implement ApproxSize", typ)
+				continue
+			}
+			for version := int16(0); version <= msg.MaxVersion(); version++ {
+				for i := 0; i < 20; i++ {
+					v := reflect.New(typ)
+					fillRandom(rng, v.Elem(), 0)
+					m := v.Interface().(message)
+					m.SetVersion(version)
+
+					approx := m.ApproxSize(version)
+					if actual := len(m.AppendTo(nil)); approx < actual {
+						t.Errorf("%s v%d: ApproxSize %d < encoded size %d", typ, version, approx, actual)
+						break
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package kmsg
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuilders(t *testing.T) {
+	txnID := "txn"
+	b := NewProduceRequestBuilder().
+		Version(9).
+		TransactionID(&txnID).
+		Acks(-1).
+		Topics([]ProduceRequestTopic{
+			NewProduceRequestTopicBuilder().Topic("foo").Build(),
+		})
+
+	got := b.Build()
+	exp := NewPtrProduceRequest()
+	exp.Version = 9
+	exp.TransactionID = &txnID
+	exp.Acks = -1
+	exp.Topics = []ProduceRequestTopic{{Topic: "foo"}}
+	if !reflect.DeepEqual(got, exp) {
+		t.Errorf("got %#v != exp %#v", got, exp)
+	}
+	if got.TimeoutMillis != 15000 {
+		t.Errorf("builder did not set defaults, got TimeoutMillis %d", got.TimeoutMillis)
+	}
+
+	// Build returns a copy: further building must not modify what
+	// was already built.
+	b.Acks(1)
+	if got.Acks != -1 {
+		t.Errorf("modifying builder after Build changed built request, got Acks %d", got.Acks)
+	}
+	if again := b.Build(); again == got || again.Acks != 1 {
+		t.Errorf("second Build did not return a new request with the new Acks")
+	}
+}
+
+func TestBuilderSettersCoverFields(t *testing.T) {
+	for _, test := range []struct {
+		builder any
+		v       any
+	}{
+		{NewProduceRequestBuilder(), ProduceRequest{}},
+		{NewProduceRequestTopicBuilder(), ProduceRequestTopic{}},
+		{NewFetchResponseBuilder(), FetchResponse{}},
+		{NewMetadataRequestBuilder(), MetadataRequest{}},
+	} {
+		bt := reflect.TypeOf(test.builder)
+		vt := reflect.TypeOf(test.v)
+		for i := 0; i < vt.NumField(); i++ {
+			f := vt.Field(i)
+			if f.Name == "UnknownTags" || !f.IsExported() {
+				continue
+			}
+			m, ok := bt.MethodByName(f.Name)
+			if !ok {
+				t.Errorf("%s: missing setter for %s", bt, f.Name)
+				continue
+			}
+			if m.Type.NumIn() != 2 || m.Type.In(1) != f.Type || m.Type.NumOut() != 1 || m.Type.Out(0) != bt {
+				t.Errorf("%s: setter %s has unexpected signature %s", bt, f.Name, m.Type)
+			}
+		}
+	}
+}
+
+func TestGettersNilSafe(t *testing.T) {
+	var req *ProduceRequest
+	if req.GetAcks() != 0 || req.GetTransactionID() != nil || req.GetTopics() != nil {
+		t.Error("getters on a nil request did not return zero values")
+	}
+
+	req = NewPtrProduceRequest()
+	req.Acks = -1
+	if req.GetAcks() != -1 || req.GetTimeoutMillis() != 15000 {
+		t.Errorf("getters returned %d and %d, exp -1 and 15000", req.GetAcks(), req.GetTimeoutMillis())
+	}
+}
@@ -114,16 +114,15 @@ func (v *MessageV0) AppendTo(dst []byte) []byte {
 }
 
 func (v *MessageV0) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *MessageV0) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *MessageV0) readFrom(src []byte, unsafe bool) error {
+func (v *MessageV0) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	s := v
 	{
 		v := b.Int64()
@@ -260,16 +259,15 @@ func (v *MessageV1) AppendTo(dst []byte) []byte {
 }
 
 func (v *MessageV1) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *MessageV1) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *MessageV1) readFrom(src []byte, unsafe bool) error {
+func (v *MessageV1) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	s := v
 	{
 		v := b.Int64()
@@ -340,16 +338,15 @@ func (v *Header) AppendTo(dst []byte) []byte {
 }
 
 func (v *Header) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *Header) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *Header) readFrom(src []byte, unsafe bool) error {
+func (v *Header) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	s := v
 	{
 		var v string
@@ -545,16 +542,15 @@ func (v *RecordBatch) AppendTo(dst []byte) []byte {
 }
 
 func (v *RecordBatch) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *RecordBatch) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *RecordBatch) readFrom(src []byte, unsafe bool) error {
+func (v *RecordBatch) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	s := v
 	{
 		v := b.Int64()
@@ -670,16 +666,15 @@ func (v *OffsetCommitKey) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetCommitKey) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetCommitKey) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetCommitKey) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetCommitKey) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -819,16 +814,15 @@ func (v *OffsetCommitValue) AppendTo(dst []byte) []byte {
 }
 
 func (v *OffsetCommitValue) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetCommitValue) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetCommitValue) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetCommitValue) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -872,9 +866,9 @@ func (v *OffsetCommitValue) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := b.Uuid()
 				s.TopicID = v
 				if err := b.Complete(); err != nil {
@@ -928,16 +922,15 @@ func (v *GroupMetadataKey) AppendTo(dst []byte) []byte {
 }
 
 func (v *GroupMetadataKey) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *GroupMetadataKey) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *GroupMetadataKey) readFrom(src []byte, unsafe bool) error {
+func (v *GroupMetadataKey) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -1176,16 +1169,15 @@ func (v *GroupMetadataValue) AppendTo(dst []byte) []byte {
 }
 
 func (v *GroupMetadataValue) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *GroupMetadataValue) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *GroupMetadataValue) readFrom(src []byte, unsafe bool) error {
+func (v *GroupMetadataValue) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -1366,14 +1358,14 @@ func (v *GroupMetadataValue) readFrom(src []byte, unsafe bool) error {
 				s.Assignment = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Members = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -1418,16 +1410,15 @@ func (v *TxnMetadataKey) AppendTo(dst []byte) []byte {
 }
 
 func (v *TxnMetadataKey) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *TxnMetadataKey) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *TxnMetadataKey) readFrom(src []byte, unsafe bool) error {
+func (v *TxnMetadataKey) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -1566,16 +1557,15 @@ func (v *TxnMetadataValue) AppendTo(dst []byte) []byte {
 }
 
 func (v *TxnMetadataValue) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *TxnMetadataValue) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *TxnMetadataValue) readFrom(src []byte, unsafe bool) error {
+func (v *TxnMetadataValue) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -1826,16 +1816,15 @@ func (v *ConsumerMemberMetadata) AppendTo(dst []byte) []byte {
 }
 
 func (v *ConsumerMemberMetadata) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerMemberMetadata) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerMemberMetadata) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerMemberMetadata) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2015,16 +2004,15 @@ func (v *ConsumerMemberAssignment) AppendTo(dst []byte) []byte {
 }
 
 func (v *ConsumerMemberAssignment) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerMemberAssignment) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerMemberAssignment) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerMemberAssignment) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2136,16 +2124,15 @@ func (v *ConnectMemberMetadata) AppendTo(dst []byte) []byte {
 }
 
 func (v *ConnectMemberMetadata) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConnectMemberMetadata) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConnectMemberMetadata) readFrom(src []byte, unsafe bool) error {
+func (v *ConnectMemberMetadata) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2311,16 +2298,15 @@ func (v *ConnectMemberAssignment) AppendTo(dst []byte) []byte {
 }
 
 func (v *ConnectMemberAssignment) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConnectMemberAssignment) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConnectMemberAssignment) readFrom(src []byte, unsafe bool) error {
+func (v *ConnectMemberAssignment) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2522,16 +2508,15 @@ func (v *DefaultPrincipalData) AppendTo(dst []byte) []byte {
 }
 
 func (v *DefaultPrincipalData) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DefaultPrincipalData) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DefaultPrincipalData) readFrom(src []byte, unsafe bool) error {
+func (v *DefaultPrincipalData) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2577,7 +2562,7 @@ func (v *DefaultPrincipalData) readFrom(src []byte, unsafe bool) error {
 		s.TokenAuthenticated = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -2621,16 +2606,15 @@ func (v *ControlRecordKey) AppendTo(dst []byte) []byte {
 }
 
 func (v *ControlRecordKey) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ControlRecordKey) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ControlRecordKey) readFrom(src []byte, unsafe bool) error {
+func (v *ControlRecordKey) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2682,16 +2666,15 @@ func (v *EndTxnMarker) AppendTo(dst []byte) []byte {
 }
 
 func (v *EndTxnMarker) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EndTxnMarker) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EndTxnMarker) readFrom(src []byte, unsafe bool) error {
+func (v *EndTxnMarker) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2812,16 +2795,15 @@ func (v *LeaderChangeMessage) AppendTo(dst []byte) []byte {
 }
 
 func (v *LeaderChangeMessage) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *LeaderChangeMessage) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *LeaderChangeMessage) readFrom(src []byte, unsafe bool) error {
+func (v *LeaderChangeMessage) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	v.Version = b.Int16()
 	version := v.Version
 	_ = version
@@ -2857,7 +2839,7 @@ func (v *LeaderChangeMessage) readFrom(src []byte, unsafe bool) error {
 				s.VoterID = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -2888,14 +2870,14 @@ func (v *LeaderChangeMessage) readFrom(src []byte, unsafe bool) error {
 				s.VoterID = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.GrantingVoters = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -3113,17 +3095,114 @@ func (v *ProduceRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ProduceRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 9
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		v := v.TransactionID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 2
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 12 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 13 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.Records
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ProduceRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ProduceRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ProduceRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ProduceRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 9
@@ -3229,21 +3308,21 @@ func (v *ProduceRequest) readFrom(src []byte, unsafe bool) error {
 						s.Records = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -3761,17 +3840,215 @@ func (v *ProduceResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ProduceResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 9
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 12 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 13 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 8
+					}
+					if version >= 2 {
+						n += 8
+					}
+					if version >= 5 {
+						n += 8
+					}
+					if version >= 8 {
+						v := v.ErrorRecords
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								v := v.ErrorMessage
+								{
+									var vv string
+									if v != nil {
+										vv = *v
+									}
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(vv) + 1))
+									} else {
+										n += 2
+									}
+									n += len(vv)
+								}
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if version >= 8 {
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+						{
+							v := v.CurrentLeader
+							start := n
+							{
+								n += 4
+							}
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+							n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+						}
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.Brokers
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				{
+					n += 4
+				}
+				{
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				{
+					n += 4
+				}
+				{
+					v := v.Rack
+					{
+						var vv string
+						if v != nil {
+							vv = *v
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(vv) + 1))
+						} else {
+							n += 2
+						}
+						n += len(vv)
+					}
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *ProduceResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ProduceResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ProduceResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 9
@@ -3900,7 +4177,7 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 								s.ErrorMessage = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
@@ -3927,9 +4204,9 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 						for i := b.Uvarint(); i > 0; i-- {
 							switch key := b.Uvarint(); key {
 							default:
-								s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+								s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 							case 0:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := &s.CurrentLeader
 								v.Default()
 								s := v
@@ -3942,7 +4219,7 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 									s.LeaderEpoch = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 								if err := b.Complete(); err != nil {
 									return err
@@ -3955,7 +4232,7 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -3969,9 +4246,9 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.Brokers
 				a := v
 				var l int32
@@ -4034,7 +4311,7 @@ func (v *ProduceResponse) readFrom(src []byte, unsafe bool) error {
 						s.Rack = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -4565,17 +4842,203 @@ func (v *FetchRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FetchRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 12
+	_ = isFlexible
+	var n int
+	if version >= 0 && version <= 14 {
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	if version >= 3 {
+		n += 4
+	}
+	if version >= 4 {
+		n += 1
+	}
+	if version >= 7 {
+		n += 4
+	}
+	if version >= 7 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 12 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 13 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if version >= 9 {
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 12 {
+						n += 4
+					}
+					if version >= 5 {
+						n += 8
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(2 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+						n += 18
+						n += 10
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 7 {
+		v := v.ForgottenTopics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 7 && version <= 12 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 13 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 11 {
+		v := v.Rack
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(2 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.ClusterID
+			start := n
+			{
+				var vv string
+				if v != nil {
+					vv = *v
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(vv) + 1))
+				} else {
+					n += 2
+				}
+				n += len(vv)
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+		{
+			v := v.ReplicaState
+			start := n
+			{
+				n += 4
+			}
+			{
+				n += 8
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+			n += kbin.UvarintLen(1) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *FetchRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FetchRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
+func (v *FetchRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 12
@@ -4698,16 +5161,16 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 						for i := b.Uvarint(); i > 0; i-- {
 							switch key := b.Uvarint(); key {
 							default:
-								s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+								s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 							case 0:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := b.Uuid()
 								s.ReplicaDirectoryID = v
 								if err := b.Complete(); err != nil {
 									return err
 								}
 							case 1:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := b.Int64()
 								s.HighWatermark = v
 								if err := b.Complete(); err != nil {
@@ -4721,7 +5184,7 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -4792,7 +5255,7 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -4819,9 +5282,9 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				var v *string
 				if isFlexible {
 					if unsafe {
@@ -4841,7 +5304,7 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 					return err
 				}
 			case 1:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := &s.ReplicaState
 				v.Default()
 				s := v
@@ -4854,7 +5317,7 @@ func (v *FetchRequest) readFrom(src []byte, unsafe bool) error {
 					s.Epoch = v
 				}
 				if isFlexible {
-					s.UnknownTags = internalReadTags(&b)
+					s.UnknownTags = internalReadTags(&b, unsafe)
 				}
 				if err := b.Complete(); err != nil {
 					return err
@@ -5533,17 +5996,240 @@ func (v *FetchResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FetchResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 12
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	if version >= 7 {
+		n += 2
+	}
+	if version >= 7 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 12 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 13 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 8
+					}
+					if version >= 4 {
+						n += 8
+					}
+					if version >= 5 {
+						n += 8
+					}
+					if version >= 4 {
+						v := v.AbortedTransactions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if version >= 11 {
+						n += 4
+					}
+					{
+						v := v.RecordBatches
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(3 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+						{
+							v := v.DivergingEpoch
+							start := n
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+							n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+						}
+						{
+							v := v.CurrentLeader
+							start := n
+							{
+								n += 4
+							}
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+							n += kbin.UvarintLen(1) + kbin.UvarintLen(uint32(n-start))
+						}
+						{
+							v := v.SnapshotID
+							start := n
+							{
+								n += 8
+							}
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+							n += kbin.UvarintLen(2) + kbin.UvarintLen(uint32(n-start))
+						}
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.Brokers
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				{
+					n += 4
+				}
+				{
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				{
+					n += 4
+				}
+				{
+					v := v.Rack
+					{
+						var vv string
+						if v != nil {
+							vv = *v
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(vv) + 1))
+						} else {
+							n += 2
+						}
+						n += len(vv)
+					}
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *FetchResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FetchResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FetchResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 12
@@ -5674,7 +6360,7 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 								s.FirstOffset = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
@@ -5697,9 +6383,9 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 						for i := b.Uvarint(); i > 0; i-- {
 							switch key := b.Uvarint(); key {
 							default:
-								s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+								s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 							case 0:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := &s.DivergingEpoch
 								v.Default()
 								s := v
@@ -5712,13 +6398,13 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 									s.EndOffset = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 								if err := b.Complete(); err != nil {
 									return err
 								}
 							case 1:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := &s.CurrentLeader
 								v.Default()
 								s := v
@@ -5731,13 +6417,13 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 									s.LeaderEpoch = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 								if err := b.Complete(); err != nil {
 									return err
 								}
 							case 2:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := &s.SnapshotID
 								v.Default()
 								s := v
@@ -5750,7 +6436,7 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 									s.Epoch = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 								if err := b.Complete(); err != nil {
 									return err
@@ -5763,7 +6449,7 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -5773,9 +6459,9 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.Brokers
 				a := v
 				var l int32
@@ -5838,7 +6524,7 @@ func (v *FetchResponse) readFrom(src []byte, unsafe bool) error {
 						s.Rack = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -6114,17 +6800,99 @@ func (v *ListOffsetsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListOffsetsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 2 {
+		n += 1
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if version >= 4 {
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 0 && version <= 0 {
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 10 {
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListOffsetsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListOffsetsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListOffsetsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ListOffsetsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -6212,14 +6980,14 @@ func (v *ListOffsetsRequest) readFrom(src []byte, unsafe bool) error {
 						s.MaxNumOffsets = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -6230,7 +6998,7 @@ func (v *ListOffsetsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TimeoutMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -6485,17 +7253,105 @@ func (v *ListOffsetsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListOffsetsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	if version >= 2 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if version >= 0 && version <= 0 {
+						v := v.OldStyleOffsets
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 8 * len(v)
+					}
+					if version >= 1 {
+						n += 8
+					}
+					if version >= 1 {
+						n += 8
+					}
+					if version >= 4 {
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListOffsetsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListOffsetsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListOffsetsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -6606,21 +7462,21 @@ func (v *ListOffsetsResponse) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -6797,17 +7653,79 @@ func (v *MetadataRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *MetadataRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 9
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 10 {
+				n += 16
+			}
+			{
+				v := v.Topic
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 4 {
+		n += 1
+	}
+	if version >= 8 && version <= 10 {
+		n += 1
+	}
+	if version >= 8 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *MetadataRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *MetadataRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *MetadataRequest) readFrom(src []byte, unsafe bool) error {
+func (v *MetadataRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 9
@@ -6876,7 +7794,7 @@ func (v *MetadataRequest) readFrom(src []byte, unsafe bool) error {
 				s.Topic = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -6895,7 +7813,7 @@ func (v *MetadataRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeTopicAuthorizedOperations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -7311,17 +8229,209 @@ func (v *MetadataResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *MetadataResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 9
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		n += 4
+	}
+	{
+		v := v.Brokers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if version >= 1 {
+				v := v.Rack
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 2 {
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.Topic
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 10 {
+				n += 16
+			}
+			if version >= 1 {
+				n += 1
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if version >= 7 {
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 5 {
+						v := v.OfflineReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if version >= 8 {
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 8 && version <= 10 {
+		n += 4
+	}
+	if version >= 13 {
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *MetadataResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *MetadataResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
+func (v *MetadataResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 9
@@ -7394,7 +8504,7 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 				s.Rack = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -7594,7 +8704,7 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 						s.OfflineReplicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -7605,7 +8715,7 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 				s.AuthorizedOperations = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -7620,7 +8730,7 @@ func (v *MetadataResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -8111,17 +9221,277 @@ func (v *LeaderAndISRRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *LeaderAndISRRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 7 {
+		n += 1
+	}
+	{
+		n += 4
+	}
+	if version >= 2 {
+		n += 8
+	}
+	if version >= 5 {
+		n += 1
+	}
+	if version >= 0 && version <= 1 {
+		v := v.PartitionStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 1 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.ISR
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Replicas
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if version >= 3 {
+				v := v.AddingReplicas
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if version >= 3 {
+				v := v.RemovingReplicas
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if version >= 1 {
+				n += 1
+			}
+			if version >= 6 {
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 2 {
+		v := v.TopicStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 5 {
+				n += 16
+			}
+			{
+				v := v.PartitionStates
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					if version >= 0 && version <= 1 {
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 3 {
+						v := v.AddingReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 3 {
+						v := v.RemovingReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 1 {
+						n += 1
+					}
+					if version >= 6 {
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.LiveLeaders
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *LeaderAndISRRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *LeaderAndISRRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *LeaderAndISRRequest) readFrom(src []byte, unsafe bool) error {
+func (v *LeaderAndISRRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -8305,7 +9675,7 @@ func (v *LeaderAndISRRequest) readFrom(src []byte, unsafe bool) error {
 				s.LeaderRecoveryState = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -8510,14 +9880,14 @@ func (v *LeaderAndISRRequest) readFrom(src []byte, unsafe bool) error {
 						s.LeaderRecoveryState = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.PartitionStates = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -8569,14 +9939,14 @@ func (v *LeaderAndISRRequest) readFrom(src []byte, unsafe bool) error {
 				s.Port = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.LiveLeaders = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -8744,17 +10114,122 @@ func (v *LeaderAndISRResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *LeaderAndISRResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	if version >= 0 && version <= 4 {
+		v := v.Partitions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 4 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 5 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					if version >= 0 && version <= 4 {
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *LeaderAndISRResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *LeaderAndISRResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
+func (v *LeaderAndISRResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -8810,7 +10285,7 @@ func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorCode = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -8886,21 +10361,21 @@ func (v *LeaderAndISRResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -9129,17 +10604,114 @@ func (v *StopReplicaRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *StopReplicaRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	if version >= 4 {
+		n += 1
+	}
+	if version >= 1 {
+		n += 8
+	}
+	if version >= 0 && version <= 2 {
+		n += 1
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 0 && version <= 0 {
+				n += 4
+			}
+			if version >= 1 && version <= 2 {
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if version >= 3 {
+				v := v.PartitionStates
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *StopReplicaRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *StopReplicaRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *StopReplicaRequest) readFrom(src []byte, unsafe bool) error {
+func (v *StopReplicaRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -9262,21 +10834,21 @@ func (v *StopReplicaRequest) readFrom(src []byte, unsafe bool) error {
 						s.Delete = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.PartitionStates = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -9395,17 +10967,70 @@ func (v *StopReplicaResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *StopReplicaResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.Partitions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *StopReplicaResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *StopReplicaResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool) error {
+func (v *StopReplicaResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -9461,14 +11086,14 @@ func (v *StopReplicaResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorCode = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Partitions = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -9954,17 +11579,300 @@ func (v *UpdateMetadataRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateMetadataRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 8 {
+		n += 1
+	}
+	{
+		n += 4
+	}
+	if version >= 5 {
+		n += 8
+	}
+	if version >= 0 && version <= 4 {
+		v := v.PartitionStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 4 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.ISR
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Replicas
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if version >= 4 {
+				v := v.OfflineReplicas
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 5 {
+		v := v.TopicStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 7 {
+				n += 16
+			}
+			{
+				v := v.PartitionStates
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					if version >= 0 && version <= 4 {
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 4 {
+						v := v.OfflineReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.LiveBrokers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			if version >= 0 && version <= 0 {
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 0 && version <= 0 {
+				n += 4
+			}
+			if version >= 1 {
+				v := v.Endpoints
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					if version >= 3 {
+						v := v.ListenerName
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if version >= 2 {
+				v := v.Rack
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UpdateMetadataRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateMetadataRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateMetadataRequest) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateMetadataRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -10113,7 +12021,7 @@ func (v *UpdateMetadataRequest) readFrom(src []byte, unsafe bool) error {
 				s.OfflineReplicas = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -10287,14 +12195,14 @@ func (v *UpdateMetadataRequest) readFrom(src []byte, unsafe bool) error {
 						s.OfflineReplicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.PartitionStates = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -10408,7 +12316,7 @@ func (v *UpdateMetadataRequest) readFrom(src []byte, unsafe bool) error {
 						s.SecurityProtocol = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -10432,14 +12340,14 @@ func (v *UpdateMetadataRequest) readFrom(src []byte, unsafe bool) error {
 				s.Rack = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.LiveBrokers = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -10502,17 +12410,38 @@ func (v *UpdateMetadataResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateMetadataResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UpdateMetadataResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateMetadataResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateMetadataResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateMetadataResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -10523,7 +12452,7 @@ func (v *UpdateMetadataResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -10613,17 +12542,41 @@ func (v *ControlledShutdownRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ControlledShutdownRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 2 {
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ControlledShutdownRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ControlledShutdownRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ControlledShutdownRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ControlledShutdownRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -10638,7 +12591,7 @@ func (v *ControlledShutdownRequest) readFrom(src []byte, unsafe bool) error {
 		s.BrokerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -10752,17 +12705,67 @@ func (v *ControlledShutdownResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ControlledShutdownResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.PartitionsRemaining
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ControlledShutdownResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ControlledShutdownResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ControlledShutdownResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -10814,14 +12817,14 @@ func (v *ControlledShutdownResponse) readFrom(src []byte, unsafe bool) error {
 				s.Partition = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.PartitionsRemaining = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -11090,17 +13093,144 @@ func (v *OffsetCommitRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetCommitRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 8
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 1 {
+		n += 4
+	}
+	if version >= 1 {
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 7 {
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 2 && version <= 4 {
+		n += 8
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 1 && version <= 1 {
+						n += 8
+					}
+					if version >= 6 {
+						n += 4
+					}
+					{
+						v := v.Metadata
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetCommitRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetCommitRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetCommitRequest) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetCommitRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 8
@@ -11256,21 +13386,21 @@ func (v *OffsetCommitRequest) readFrom(src []byte, unsafe bool) error {
 						s.Metadata = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -11470,17 +13600,87 @@ func (v *OffsetCommitResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetCommitResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 8
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetCommitResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetCommitResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetCommitResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 8
@@ -11556,21 +13756,21 @@ func (v *OffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -11861,17 +14061,161 @@ func (v *OffsetFetchRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetFetchRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	if version >= 0 && version <= 7 {
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 0 && version <= 7 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 8 {
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 9 {
+				v := v.MemberID
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 9 {
+				n += 4
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 7 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetFetchRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetFetchRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetFetchRequest) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetFetchRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -11958,7 +14302,7 @@ func (v *OffsetFetchRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -12086,14 +14430,14 @@ func (v *OffsetFetchRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -12104,7 +14448,7 @@ func (v *OffsetFetchRequest) readFrom(src []byte, unsafe bool) error {
 		s.RequireStable = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -12500,17 +14844,210 @@ func (v *OffsetFetchResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetFetchResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		n += 4
+	}
+	if version >= 0 && version <= 7 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 5 {
+						n += 4
+					}
+					{
+						v := v.Metadata
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 2 && version <= 7 {
+		n += 2
+	}
+	if version >= 8 {
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							{
+								n += 4
+							}
+							{
+								v := v.Metadata
+								{
+									var vv string
+									if v != nil {
+										vv = *v
+									}
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(vv) + 1))
+									} else {
+										n += 2
+									}
+									n += len(vv)
+								}
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetFetchResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetFetchResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetFetchResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -12611,14 +15148,14 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -12756,14 +15293,14 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 								s.ErrorCode = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -12774,14 +15311,14 @@ func (v *OffsetFetchResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorCode = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -12895,17 +15432,64 @@ func (v *FindCoordinatorRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FindCoordinatorRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 0 && version <= 3 {
+		v := v.CoordinatorKey
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if version >= 4 {
+		v := v.CoordinatorKeys
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *FindCoordinatorRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FindCoordinatorRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FindCoordinatorRequest) readFrom(src []byte, unsafe bool) error {
+func (v *FindCoordinatorRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -12969,7 +15553,7 @@ func (v *FindCoordinatorRequest) readFrom(src []byte, unsafe bool) error {
 		s.CoordinatorKeys = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -13184,17 +15768,130 @@ func (v *FindCoordinatorResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FindCoordinatorResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	if version >= 0 && version <= 3 {
+		n += 2
+	}
+	if version >= 1 && version <= 3 {
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 0 && version <= 3 {
+		n += 4
+	}
+	if version >= 0 && version <= 3 {
+		v := v.Host
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 0 && version <= 3 {
+		n += 4
+	}
+	if version >= 4 {
+		v := v.Coordinators
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Key
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *FindCoordinatorResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FindCoordinatorResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FindCoordinatorResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -13334,14 +16031,14 @@ func (v *FindCoordinatorResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Coordinators = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -13586,17 +16283,133 @@ func (v *JoinGroupRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *JoinGroupRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 5 {
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.ProtocolType
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Protocols
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Metadata
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 8 {
+		v := v.Reason
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *JoinGroupRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *JoinGroupRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *JoinGroupRequest) readFrom(src []byte, unsafe bool) error {
+func (v *JoinGroupRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -13725,7 +16538,7 @@ func (v *JoinGroupRequest) readFrom(src []byte, unsafe bool) error {
 				s.Metadata = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -13749,7 +16562,7 @@ func (v *JoinGroupRequest) readFrom(src []byte, unsafe bool) error {
 		s.Reason = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -14012,17 +16825,145 @@ func (v *JoinGroupResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *JoinGroupResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 6
+	_ = isFlexible
+	var n int
+	if version >= 2 {
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 4
+	}
+	if version >= 7 {
+		v := v.ProtocolType
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Protocol
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.LeaderID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 9 {
+		n += 1
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Members
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.MemberID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 5 {
+				v := v.InstanceID
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.ProtocolMetadata
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *JoinGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *JoinGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *JoinGroupResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 6
@@ -14194,14 +17135,14 @@ func (v *JoinGroupResponse) readFrom(src []byte, unsafe bool) error {
 				s.ProtocolMetadata = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Members = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -14311,17 +17252,71 @@ func (v *HeartbeatRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *HeartbeatRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *HeartbeatRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *HeartbeatRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *HeartbeatRequest) readFrom(src []byte, unsafe bool) error {
+func (v *HeartbeatRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -14383,7 +17378,7 @@ func (v *HeartbeatRequest) readFrom(src []byte, unsafe bool) error {
 		s.InstanceID = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -14476,17 +17471,41 @@ func (v *HeartbeatResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *HeartbeatResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *HeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *HeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *HeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *HeartbeatResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -14501,7 +17520,7 @@ func (v *HeartbeatResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -14663,17 +17682,109 @@ func (v *LeaveGroupRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *LeaveGroupRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 0 && version <= 2 {
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.Members
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.MemberID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.InstanceID
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 5 {
+				v := v.Reason
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *LeaveGroupRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *LeaveGroupRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *LeaveGroupRequest) readFrom(src []byte, unsafe bool) error {
+func (v *LeaveGroupRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -14785,14 +17896,14 @@ func (v *LeaveGroupRequest) readFrom(src []byte, unsafe bool) error {
 				s.Reason = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Members = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -14946,17 +18057,85 @@ func (v *LeaveGroupResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *LeaveGroupResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if version >= 3 {
+		v := v.Members
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.MemberID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.InstanceID
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *LeaveGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *LeaveGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *LeaveGroupResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -15029,14 +18208,14 @@ func (v *LeaveGroupResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorCode = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Members = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -15230,17 +18409,136 @@ func (v *SyncGroupRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SyncGroupRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 5 {
+		v := v.ProtocolType
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 5 {
+		v := v.Protocol
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.GroupAssignment
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.MemberID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.MemberAssignment
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *SyncGroupRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SyncGroupRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SyncGroupRequest) readFrom(src []byte, unsafe bool) error {
+func (v *SyncGroupRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -15382,14 +18680,14 @@ func (v *SyncGroupRequest) readFrom(src []byte, unsafe bool) error {
 				s.MemberAssignment = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.GroupAssignment = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -15518,17 +18816,80 @@ func (v *SyncGroupResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SyncGroupResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if version >= 5 {
+		v := v.ProtocolType
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 5 {
+		v := v.Protocol
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.MemberAssignment
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *SyncGroupResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SyncGroupResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SyncGroupResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -15586,7 +18947,7 @@ func (v *SyncGroupResponse) readFrom(src []byte, unsafe bool) error {
 		s.MemberAssignment = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -15682,17 +19043,55 @@ func (v *DescribeGroupsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeGroupsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 5
+	_ = isFlexible
+	var n int
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if version >= 3 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeGroupsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeGroupsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeGroupsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeGroupsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 5
@@ -15739,7 +19138,7 @@ func (v *DescribeGroupsRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeAuthorizedOperations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -16040,17 +19439,189 @@ func (v *DescribeGroupsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeGroupsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 5
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			if version >= 6 {
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.State
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.ProtocolType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Protocol
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Members
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.MemberID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					if version >= 4 {
+						v := v.InstanceID
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := v.ClientID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.ClientHost
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.ProtocolMetadata
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					{
+						v := v.MemberAssignment
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if version >= 3 {
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeGroupsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 5
@@ -16276,7 +19847,7 @@ func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
 						s.MemberAssignment = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -16287,14 +19858,14 @@ func (v *DescribeGroupsResponse) readFrom(src []byte, unsafe bool) error {
 				s.AuthorizedOperations = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -16405,17 +19976,69 @@ func (v *ListGroupsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListGroupsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 4 {
+		v := v.StatesFilter
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if version >= 5 {
+		v := v.TypesFilter
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListGroupsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListGroupsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListGroupsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ListGroupsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -16494,7 +20117,7 @@ func (v *ListGroupsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TypesFilter = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -16653,17 +20276,94 @@ func (v *ListGroupsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListGroupsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.ProtocolType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 4 {
+				v := v.GroupState
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 5 {
+				v := v.GroupType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListGroupsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -16766,14 +20466,14 @@ func (v *ListGroupsResponse) readFrom(src []byte, unsafe bool) error {
 				s.GroupType = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -16846,17 +20546,32 @@ func (v *SASLHandshakeRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SASLHandshakeRequest) ApproxSize(version int16) int {
+	_ = version
+	var n int
+	{
+		v := v.Mechanism
+		n += 2
+		n += len(v)
+	}
+	return n
+}
+
 func (v *SASLHandshakeRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SASLHandshakeRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SASLHandshakeRequest) readFrom(src []byte, unsafe bool) error {
+func (v *SASLHandshakeRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	s := v
@@ -16935,17 +20650,39 @@ func (v *SASLHandshakeResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SASLHandshakeResponse) ApproxSize(version int16) int {
+	_ = version
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.SupportedMechanisms
+		n += 4
+		for i := range v {
+			v := v[i]
+			n += 2
+			n += len(v)
+		}
+	}
+	return n
+}
+
 func (v *SASLHandshakeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SASLHandshakeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SASLHandshakeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SASLHandshakeResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	s := v
@@ -17084,17 +20821,53 @@ func (v *ApiVersionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ApiVersionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		v := v.ClientSoftwareName
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.ClientSoftwareVersion
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ApiVersionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ApiVersionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ApiVersionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ApiVersionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -17135,7 +20908,7 @@ func (v *ApiVersionsRequest) readFrom(src []byte, unsafe bool) error {
 		s.ClientSoftwareVersion = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -17457,17 +21230,137 @@ func (v *ApiVersionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ApiVersionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.ApiKeys
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(4 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.SupportedFeatures
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				{
+					v := v.Name
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				{
+					n += 2
+				}
+				{
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+		n += 10
+		{
+			v := v.FinalizedFeatures
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				{
+					v := v.Name
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				{
+					n += 2
+				}
+				{
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(2) + kbin.UvarintLen(uint32(n-start))
+		}
+		n += 3
+	}
+	return n
+}
+
 func (v *ApiVersionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ApiVersionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ApiVersionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -17510,7 +21403,7 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 				s.MaxVersion = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -17524,9 +21417,9 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.SupportedFeatures
 				a := v
 				var l int32
@@ -17572,7 +21465,7 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 						s.MaxVersion = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -17581,14 +21474,14 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 					return err
 				}
 			case 1:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := b.Int64()
 				s.FinalizedFeaturesEpoch = v
 				if err := b.Complete(); err != nil {
 					return err
 				}
 			case 2:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.FinalizedFeatures
 				a := v
 				var l int32
@@ -17634,7 +21527,7 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 						s.MinVersionLevel = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -17643,7 +21536,7 @@ func (v *ApiVersionsResponse) readFrom(src []byte, unsafe bool) error {
 					return err
 				}
 			case 3:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := b.Bool()
 				s.ZkMigrationReady = v
 				if err := b.Complete(); err != nil {
@@ -17930,17 +21823,143 @@ func (v *CreateTopicsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateTopicsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 5
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ReplicaAssignment
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				v := v.Configs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Value
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateTopicsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateTopicsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateTopicsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *CreateTopicsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 5
@@ -18039,7 +22058,7 @@ func (v *CreateTopicsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Replicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -18100,14 +22119,14 @@ func (v *CreateTopicsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Value = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Configs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -18122,7 +22141,7 @@ func (v *CreateTopicsRequest) readFrom(src []byte, unsafe bool) error {
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -18419,17 +22438,142 @@ func (v *CreateTopicsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateTopicsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 5
+	_ = isFlexible
+	var n int
+	if version >= 2 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 7 {
+				n += 16
+			}
+			{
+				n += 2
+			}
+			if version >= 1 {
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 5 {
+				n += 4
+			}
+			if version >= 5 {
+				n += 2
+			}
+			if version >= 5 {
+				v := v.Configs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Value
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 1
+					}
+					{
+						n += 1
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+				n += 4
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateTopicsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateTopicsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateTopicsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 5
@@ -18579,7 +22723,7 @@ func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
 						s.IsSensitive = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -18589,9 +22733,9 @@ func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
 				for i := b.Uvarint(); i > 0; i-- {
 					switch key := b.Uvarint(); key {
 					default:
-						s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+						s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 					case 0:
-						b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+						b := b.Nested(b.Span(int(b.Uvarint())))
 						v := b.Int16()
 						s.ConfigErrorCode = v
 						if err := b.Complete(); err != nil {
@@ -18605,7 +22749,7 @@ func (v *CreateTopicsResponse) readFrom(src []byte, unsafe bool) error {
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -18758,17 +22902,90 @@ func (v *DeleteTopicsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteTopicsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 0 && version <= 5 {
+		v := v.TopicNames
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if version >= 6 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 16
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteTopicsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteTopicsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteTopicsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteTopicsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -18852,7 +23069,7 @@ func (v *DeleteTopicsRequest) readFrom(src []byte, unsafe bool) error {
 				s.TopicID = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -18863,7 +23080,7 @@ func (v *DeleteTopicsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TimeoutMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -19042,17 +23259,91 @@ func (v *DeleteTopicsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteTopicsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 6 {
+				n += 16
+			}
+			{
+				n += 2
+			}
+			if version >= 5 {
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteTopicsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteTopicsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteTopicsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -19143,14 +23434,14 @@ func (v *DeleteTopicsResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -19342,17 +23633,87 @@ func (v *DeleteRecordsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteRecordsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteRecordsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteRecordsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteRecordsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteRecordsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -19424,14 +23785,14 @@ func (v *DeleteRecordsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Offset = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -19442,7 +23803,7 @@ func (v *DeleteRecordsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TimeoutMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -19637,17 +23998,90 @@ func (v *DeleteRecordsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteRecordsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteRecordsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteRecordsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteRecordsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -19727,21 +24161,21 @@ func (v *DeleteRecordsResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -19857,17 +24291,59 @@ func (v *InitProducerIDRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *InitProducerIDRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.TransactionalID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	if version >= 3 {
+		n += 8
+	}
+	if version >= 3 {
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *InitProducerIDRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *InitProducerIDRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *InitProducerIDRequest) readFrom(src []byte, unsafe bool) error {
+func (v *InitProducerIDRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -19903,7 +24379,7 @@ func (v *InitProducerIDRequest) readFrom(src []byte, unsafe bool) error {
 		s.ProducerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -20018,17 +24494,47 @@ func (v *InitProducerIDResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *InitProducerIDResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 8
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *InitProducerIDResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *InitProducerIDResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *InitProducerIDResponse) readFrom(src []byte, unsafe bool) error {
+func (v *InitProducerIDResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -20051,7 +24557,7 @@ func (v *InitProducerIDResponse) readFrom(src []byte, unsafe bool) error {
 		s.ProducerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -20245,17 +24751,90 @@ func (v *OffsetForLeaderEpochRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetForLeaderEpochRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if version >= 2 {
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetForLeaderEpochRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetForLeaderEpochRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetForLeaderEpochRequest) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetForLeaderEpochRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -20335,21 +24914,21 @@ func (v *OffsetForLeaderEpochRequest) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -20575,17 +25154,93 @@ func (v *OffsetForLeaderEpochResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetForLeaderEpochResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	if version >= 2 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					if version >= 1 {
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *OffsetForLeaderEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetForLeaderEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetForLeaderEpochResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -20669,21 +25324,21 @@ func (v *OffsetForLeaderEpochResponse) readFrom(src []byte, unsafe bool) error {
 						s.EndOffset = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -20977,17 +25632,155 @@ func (v *AddPartitionsToTxnRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddPartitionsToTxnRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	if version >= 0 && version <= 3 {
+		v := v.TransactionalID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 0 && version <= 3 {
+		n += 8
+	}
+	if version >= 0 && version <= 3 {
+		n += 2
+	}
+	if version >= 0 && version <= 3 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 4 {
+		v := v.Transactions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TransactionalID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 8
+			}
+			{
+				n += 2
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddPartitionsToTxnRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddPartitionsToTxnRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddPartitionsToTxnRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AddPartitionsToTxnRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -21079,7 +25872,7 @@ func (v *AddPartitionsToTxnRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -21195,21 +25988,21 @@ func (v *AddPartitionsToTxnRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Transactions = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -21561,17 +26354,165 @@ func (v *AddPartitionsToTxnResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddPartitionsToTxnResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 4 {
+		n += 2
+	}
+	if version >= 4 {
+		v := v.Transactions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TransactionalID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 0 && version <= 3 {
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddPartitionsToTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddPartitionsToTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AddPartitionsToTxnResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -21688,21 +26629,21 @@ func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 								s.ErrorCode = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -21774,21 +26715,21 @@ func (v *AddPartitionsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -21901,17 +26842,59 @@ func (v *AddOffsetsToTxnRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddOffsetsToTxnRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		v := v.TransactionalID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddOffsetsToTxnRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddOffsetsToTxnRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddOffsetsToTxnRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AddOffsetsToTxnRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -21960,7 +26943,7 @@ func (v *AddOffsetsToTxnRequest) readFrom(src []byte, unsafe bool) error {
 		s.Group = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -22049,17 +27032,41 @@ func (v *AddOffsetsToTxnResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddOffsetsToTxnResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddOffsetsToTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddOffsetsToTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddOffsetsToTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AddOffsetsToTxnResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -22074,7 +27081,7 @@ func (v *AddOffsetsToTxnResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -22177,17 +27184,53 @@ func (v *EndTxnRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EndTxnRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		v := v.TransactionalID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	{
+		n += 2
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *EndTxnRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EndTxnRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EndTxnRequest) readFrom(src []byte, unsafe bool) error {
+func (v *EndTxnRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -22223,7 +27266,7 @@ func (v *EndTxnRequest) readFrom(src []byte, unsafe bool) error {
 		s.Commit = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -22338,17 +27381,47 @@ func (v *EndTxnResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EndTxnResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if version >= 5 {
+		n += 8
+	}
+	if version >= 5 {
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *EndTxnResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EndTxnResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EndTxnResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EndTxnResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -22371,7 +27444,7 @@ func (v *EndTxnResponse) readFrom(src []byte, unsafe bool) error {
 		s.ProducerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -22570,17 +27643,99 @@ func (v *WriteTxnMarkersRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *WriteTxnMarkersRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.Markers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 8
+			}
+			{
+				n += 2
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *WriteTxnMarkersRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *WriteTxnMarkersRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *WriteTxnMarkersRequest) readFrom(src []byte, unsafe bool) error {
+func (v *WriteTxnMarkersRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -22679,7 +27834,7 @@ func (v *WriteTxnMarkersRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -22690,14 +27845,14 @@ func (v *WriteTxnMarkersRequest) readFrom(src []byte, unsafe bool) error {
 				s.CoordinatorEpoch = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Markers = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -22908,17 +28063,104 @@ func (v *WriteTxnMarkersResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *WriteTxnMarkersResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.Markers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 8
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *WriteTxnMarkersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *WriteTxnMarkersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *WriteTxnMarkersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *WriteTxnMarkersResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -23014,28 +28256,28 @@ func (v *WriteTxnMarkersResponse) readFrom(src []byte, unsafe bool) error {
 								s.ErrorCode = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Markers = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -23300,17 +28542,153 @@ func (v *TxnOffsetCommitRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *TxnOffsetCommitRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		v := v.TransactionalID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	{
+		n += 2
+	}
+	if version >= 3 {
+		n += 4
+	}
+	if version >= 3 {
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 2 {
+						n += 4
+					}
+					{
+						v := v.Metadata
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *TxnOffsetCommitRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *TxnOffsetCommitRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *TxnOffsetCommitRequest) readFrom(src []byte, unsafe bool) error {
+func (v *TxnOffsetCommitRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -23483,21 +28861,21 @@ func (v *TxnOffsetCommitRequest) readFrom(src []byte, unsafe bool) error {
 						s.Metadata = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -23706,17 +29084,87 @@ func (v *TxnOffsetCommitResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *TxnOffsetCommitResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 3
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *TxnOffsetCommitResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *TxnOffsetCommitResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
+func (v *TxnOffsetCommitResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 3
@@ -23792,21 +29240,21 @@ func (v *TxnOffsetCommitResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -23959,17 +29407,92 @@ func (v *DescribeACLsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeACLsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 1
+	}
+	{
+		v := v.ResourceName
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 1
+	}
+	{
+		v := v.Principal
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Host
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 1
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeACLsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeACLsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeACLsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeACLsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -24063,7 +29586,7 @@ func (v *DescribeACLsRequest) readFrom(src []byte, unsafe bool) error {
 		s.PermissionType = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -24303,17 +29826,129 @@ func (v *DescribeACLsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeACLsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 1 {
+				n += 1
+			}
+			{
+				v := v.ACLs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Principal
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 1
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeACLsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -24472,21 +30107,21 @@ func (v *DescribeACLsResponse) readFrom(src []byte, unsafe bool) error {
 						s.PermissionType = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.ACLs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Resources = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -24677,17 +30312,91 @@ func (v *CreateACLsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateACLsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Creations
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 1 {
+				n += 1
+			}
+			{
+				v := v.Principal
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 1
+			}
+			{
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateACLsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateACLsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateACLsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *CreateACLsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -24801,14 +30510,14 @@ func (v *CreateACLsRequest) readFrom(src []byte, unsafe bool) error {
 				s.PermissionType = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Creations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -24930,17 +30639,73 @@ func (v *CreateACLsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateACLsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Results
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateACLsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -24992,14 +30757,14 @@ func (v *CreateACLsResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Results = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -25172,17 +30937,109 @@ func (v *DeleteACLsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteACLsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Filters
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 1 {
+				n += 1
+			}
+			{
+				v := v.Principal
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.Host
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 1
+			}
+			{
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteACLsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteACLsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteACLsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteACLsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -25296,14 +31153,14 @@ func (v *DeleteACLsRequest) readFrom(src []byte, unsafe bool) error {
 				s.PermissionType = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Filters = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -25547,17 +31404,147 @@ func (v *DeleteACLsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteACLsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Results
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.MatchingACLs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 1
+					}
+					{
+						v := v.ResourceName
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					if version >= 1 {
+						n += 1
+					}
+					{
+						v := v.Principal
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 1
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteACLsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteACLsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteACLsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -25737,21 +31724,21 @@ func (v *DeleteACLsResponse) readFrom(src []byte, unsafe bool) error {
 						s.PermissionType = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.MatchingACLs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Results = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -25919,17 +31906,87 @@ func (v *DescribeConfigsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeConfigsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.ConfigNames
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := v[i]
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if version >= 3 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeConfigsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeConfigsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeConfigsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeConfigsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -26021,7 +32078,7 @@ func (v *DescribeConfigsRequest) readFrom(src []byte, unsafe bool) error {
 				s.ConfigNames = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -26036,7 +32093,7 @@ func (v *DescribeConfigsRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeDocumentation = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -26386,17 +32443,200 @@ func (v *DescribeConfigsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeConfigsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 4
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Configs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Value
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 1
+					}
+					if version >= 0 && version <= 0 {
+						n += 1
+					}
+					if version >= 1 {
+						n += 1
+					}
+					{
+						n += 1
+					}
+					if version >= 1 {
+						v := v.ConfigSynonyms
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								v := v.Name
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(len(v) + 1))
+								} else {
+									n += 2
+								}
+								n += len(v)
+							}
+							{
+								v := v.Value
+								{
+									var vv string
+									if v != nil {
+										vv = *v
+									}
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(vv) + 1))
+									} else {
+										n += 2
+									}
+									n += len(vv)
+								}
+							}
+							{
+								n += 1
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if version >= 3 {
+						n += 1
+					}
+					if version >= 3 {
+						v := v.Documentation
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeConfigsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 4
@@ -26612,7 +32852,7 @@ func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
 								s.Source = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
@@ -26645,21 +32885,21 @@ func (v *DescribeConfigsResponse) readFrom(src []byte, unsafe bool) error {
 						s.Documentation = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Configs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Resources = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -26872,17 +33112,108 @@ func (v *AlterConfigsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterConfigsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Configs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Value
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterConfigsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterConfigsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterConfigsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterConfigsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -26989,14 +33320,14 @@ func (v *AlterConfigsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Value = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Configs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -27007,7 +33338,7 @@ func (v *AlterConfigsRequest) readFrom(src []byte, unsafe bool) error {
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -27164,17 +33495,85 @@ func (v *AlterConfigsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterConfigsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterConfigsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -27252,14 +33651,14 @@ func (v *AlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
 				s.ResourceName = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Resources = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -27439,17 +33838,96 @@ func (v *AlterReplicaLogDirsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterReplicaLogDirsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Dirs
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Dir
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterReplicaLogDirsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterReplicaLogDirsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterReplicaLogDirsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterReplicaLogDirsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -27553,21 +34031,21 @@ func (v *AlterReplicaLogDirsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Dirs = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -27752,17 +34230,87 @@ func (v *AlterReplicaLogDirsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterReplicaLogDirsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterReplicaLogDirsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterReplicaLogDirsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterReplicaLogDirsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -27838,21 +34386,21 @@ func (v *AlterReplicaLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorCode = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -27983,17 +34531,70 @@ func (v *DescribeLogDirsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeLogDirsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeLogDirsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeLogDirsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeLogDirsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeLogDirsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -28063,14 +34664,14 @@ func (v *DescribeLogDirsRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -28352,17 +34953,131 @@ func (v *DescribeLogDirsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeLogDirsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 3 {
+		n += 2
+	}
+	{
+		v := v.Dirs
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.Dir
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								n += 1
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if version >= 4 {
+				n += 8
+			}
+			if version >= 4 {
+				n += 8
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeLogDirsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeLogDirsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeLogDirsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -28491,14 +35206,14 @@ func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 								s.IsFuture = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -28513,14 +35228,14 @@ func (v *DescribeLogDirsResponse) readFrom(src []byte, unsafe bool) error {
 				s.UsableBytes = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Dirs = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -28603,17 +35318,44 @@ func (v *SASLAuthenticateRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SASLAuthenticateRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.SASLAuthBytes
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *SASLAuthenticateRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SASLAuthenticateRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SASLAuthenticateRequest) readFrom(src []byte, unsafe bool) error {
+func (v *SASLAuthenticateRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -28629,7 +35371,7 @@ func (v *SASLAuthenticateRequest) readFrom(src []byte, unsafe bool) error {
 		s.SASLAuthBytes = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -28724,17 +35466,65 @@ func (v *SASLAuthenticateResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *SASLAuthenticateResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.SASLAuthBytes
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	if version >= 1 {
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *SASLAuthenticateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *SASLAuthenticateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *SASLAuthenticateResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -28775,7 +35565,7 @@ func (v *SASLAuthenticateResponse) readFrom(src []byte, unsafe bool) error {
 		s.SessionLifetimeMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -28981,17 +35771,96 @@ func (v *CreatePartitionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreatePartitionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Assignment
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreatePartitionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreatePartitionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreatePartitionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *CreatePartitionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -29085,14 +35954,14 @@ func (v *CreatePartitionsRequest) readFrom(src []byte, unsafe bool) error {
 						s.Replicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Assignment = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -29107,7 +35976,7 @@ func (v *CreatePartitionsRequest) readFrom(src []byte, unsafe bool) error {
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -29268,17 +36137,82 @@ func (v *CreatePartitionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreatePartitionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreatePartitionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreatePartitionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreatePartitionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -29347,14 +36281,14 @@ func (v *CreatePartitionsResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -29524,17 +36458,103 @@ func (v *CreateDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateDelegationTokenRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	if version >= 3 {
+		v := v.OwnerPrincipalType
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 3 {
+		v := v.OwnerPrincipalName
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Renewers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.PrincipalType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.PrincipalName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateDelegationTokenRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateDelegationTokenRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
+func (v *CreateDelegationTokenRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -29629,7 +36649,7 @@ func (v *CreateDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
 				s.PrincipalName = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -29640,7 +36660,7 @@ func (v *CreateDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
 		s.MaxLifetimeMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -29818,17 +36838,104 @@ func (v *CreateDelegationTokenResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *CreateDelegationTokenResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.PrincipalType
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.PrincipalName
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.TokenRequesterPrincipalType
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	if version >= 3 {
+		v := v.TokenRequesterPrincipalName
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	{
+		n += 8
+	}
+	{
+		n += 8
+	}
+	{
+		v := v.TokenID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.HMAC
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *CreateDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *CreateDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *CreateDelegationTokenResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -29949,7 +37056,7 @@ func (v *CreateDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 		s.ThrottleMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30037,17 +37144,47 @@ func (v *RenewDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *RenewDelegationTokenRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.HMAC
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *RenewDelegationTokenRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *RenewDelegationTokenRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *RenewDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
+func (v *RenewDelegationTokenRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -30067,7 +37204,7 @@ func (v *RenewDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
 		s.RenewTimeMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30159,17 +37296,44 @@ func (v *RenewDelegationTokenResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *RenewDelegationTokenResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		n += 8
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *RenewDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *RenewDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *RenewDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *RenewDelegationTokenResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -30188,7 +37352,7 @@ func (v *RenewDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
 		s.ThrottleMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30278,17 +37442,47 @@ func (v *ExpireDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ExpireDelegationTokenRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.HMAC
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ExpireDelegationTokenRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ExpireDelegationTokenRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ExpireDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ExpireDelegationTokenRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -30308,7 +37502,7 @@ func (v *ExpireDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
 		s.ExpiryPeriodMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30399,17 +37593,44 @@ func (v *ExpireDelegationTokenResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ExpireDelegationTokenResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		n += 8
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ExpireDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ExpireDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ExpireDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ExpireDelegationTokenResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -30428,7 +37649,7 @@ func (v *ExpireDelegationTokenResponse) readFrom(src []byte, unsafe bool) error
 		s.ThrottleMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30557,17 +37778,70 @@ func (v *DescribeDelegationTokenRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeDelegationTokenRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Owners
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.PrincipalType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.PrincipalName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeDelegationTokenRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeDelegationTokenRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeDelegationTokenRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeDelegationTokenRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -30631,14 +37905,14 @@ func (v *DescribeDelegationTokenRequest) readFrom(src []byte, unsafe bool) error
 				s.PrincipalName = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Owners = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -30902,17 +38176,156 @@ func (v *DescribeDelegationTokenResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeDelegationTokenResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.TokenDetails
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.PrincipalType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.PrincipalName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 3 {
+				v := v.TokenRequesterPrincipalType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 3 {
+				v := v.TokenRequesterPrincipalName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 8
+			}
+			{
+				n += 8
+			}
+			{
+				n += 8
+			}
+			{
+				v := v.TokenID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.HMAC
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			{
+				v := v.Renewers
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.PrincipalType
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.PrincipalName
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeDelegationTokenResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeDelegationTokenResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeDelegationTokenResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -31103,14 +38516,14 @@ func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) erro
 						s.PrincipalName = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Renewers = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -31121,7 +38534,7 @@ func (v *DescribeDelegationTokenResponse) readFrom(src []byte, unsafe bool) erro
 		s.ThrottleMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -31210,17 +38623,52 @@ func (v *DeleteGroupsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteGroupsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteGroupsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteGroupsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteGroupsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteGroupsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -31263,7 +38711,7 @@ func (v *DeleteGroupsRequest) readFrom(src []byte, unsafe bool) error {
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -31398,17 +38846,67 @@ func (v *DeleteGroupsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteGroupsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteGroupsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteGroupsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteGroupsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -31460,14 +38958,14 @@ func (v *DeleteGroupsResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorCode = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -31626,17 +39124,76 @@ func (v *ElectLeadersRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ElectLeadersRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		n += 1
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ElectLeadersRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ElectLeadersRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ElectLeadersRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ElectLeadersRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -31710,7 +39267,7 @@ func (v *ElectLeadersRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -31721,7 +39278,7 @@ func (v *ElectLeadersRequest) readFrom(src []byte, unsafe bool) error {
 		s.TimeoutMillis = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -31920,17 +39477,105 @@ func (v *ElectLeadersResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ElectLeadersResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 2
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ElectLeadersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ElectLeadersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ElectLeadersResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 2
@@ -32027,21 +39672,21 @@ func (v *ElectLeadersResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -32269,17 +39914,111 @@ func (v *IncrementalAlterConfigsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *IncrementalAlterConfigsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Configs
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 1
+					}
+					{
+						v := v.Value
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *IncrementalAlterConfigsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *IncrementalAlterConfigsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *IncrementalAlterConfigsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *IncrementalAlterConfigsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -32395,14 +40134,14 @@ func (v *IncrementalAlterConfigsRequest) readFrom(src []byte, unsafe bool) error
 						s.Value = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Configs = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -32413,7 +40152,7 @@ func (v *IncrementalAlterConfigsRequest) readFrom(src []byte, unsafe bool) error
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -32575,17 +40314,85 @@ func (v *IncrementalAlterConfigsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *IncrementalAlterConfigsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Resources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.ResourceName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *IncrementalAlterConfigsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *IncrementalAlterConfigsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *IncrementalAlterConfigsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -32663,14 +40470,14 @@ func (v *IncrementalAlterConfigsResponse) readFrom(src []byte, unsafe bool) erro
 				s.ResourceName = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Resources = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -32873,17 +40680,96 @@ func (v *AlterPartitionAssignmentsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterPartitionAssignmentsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 1
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterPartitionAssignmentsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterPartitionAssignmentsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterPartitionAssignmentsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionAssignmentsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -32985,21 +40871,21 @@ func (v *AlterPartitionAssignmentsRequest) readFrom(src []byte, unsafe bool) err
 						s.Replicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -33223,17 +41109,123 @@ func (v *AlterPartitionAssignmentsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterPartitionAssignmentsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 1
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterPartitionAssignmentsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterPartitionAssignmentsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionAssignmentsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -33351,21 +41343,21 @@ func (v *AlterPartitionAssignmentsResponse) readFrom(src []byte, unsafe bool) er
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -33516,17 +41508,73 @@ func (v *ListPartitionReassignmentsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListPartitionReassignmentsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListPartitionReassignmentsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListPartitionReassignmentsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListPartitionReassignmentsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ListPartitionReassignmentsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -33600,14 +41648,14 @@ func (v *ListPartitionReassignmentsRequest) readFrom(src []byte, unsafe bool) er
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -33841,17 +41889,129 @@ func (v *ListPartitionReassignmentsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListPartitionReassignmentsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.AddingReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.RemovingReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListPartitionReassignmentsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListPartitionReassignmentsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListPartitionReassignmentsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -34013,21 +42173,21 @@ func (v *ListPartitionReassignmentsResponse) readFrom(src []byte, unsafe bool) e
 						s.RemovingReplicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -34161,17 +42321,55 @@ func (v *OffsetDeleteRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetDeleteRequest) ApproxSize(version int16) int {
+	_ = version
+	var n int
+	{
+		v := v.Group
+		n += 2
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		n += 4
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				n += 2
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				n += 4
+				for i := range v {
+					v := &v[i]
+					_ = v
+					{
+						n += 4
+					}
+				}
+			}
+		}
+	}
+	return n
+}
+
 func (v *OffsetDeleteRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetDeleteRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetDeleteRequest) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetDeleteRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	s := v
@@ -34390,17 +42588,59 @@ func (v *OffsetDeleteResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *OffsetDeleteResponse) ApproxSize(version int16) int {
+	_ = version
+	var n int
+	{
+		n += 2
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		n += 4
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				n += 2
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				n += 4
+				for i := range v {
+					v := &v[i]
+					_ = v
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+				}
+			}
+		}
+	}
+	return n
+}
+
 func (v *OffsetDeleteResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *OffsetDeleteResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *OffsetDeleteResponse) readFrom(src []byte, unsafe bool) error {
+func (v *OffsetDeleteResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	s := v
@@ -34616,17 +42856,82 @@ func (v *DescribeClientQuotasRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeClientQuotasRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.Components
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.EntityType
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.Match
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeClientQuotasRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeClientQuotasRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClientQuotasRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -34696,7 +43001,7 @@ func (v *DescribeClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
 				s.Match = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -34707,7 +43012,7 @@ func (v *DescribeClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
 		s.Strict = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -34945,17 +43250,143 @@ func (v *DescribeClientQuotasResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeClientQuotasResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Entries
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Entity
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Type
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Name
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				v := v.Values
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Key
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeClientQuotasResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeClientQuotasResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClientQuotasResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -35064,7 +43495,7 @@ func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 						s.Name = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -35112,21 +43543,21 @@ func (v *DescribeClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 						s.Value = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Values = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Entries = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -35357,17 +43788,128 @@ func (v *AlterClientQuotasRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterClientQuotasRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.Entries
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Entity
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Type
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Name
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				v := v.Ops
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Key
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 8
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterClientQuotasRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterClientQuotasRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterClientQuotasRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -35448,7 +43990,7 @@ func (v *AlterClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
 						s.Name = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -35500,14 +44042,14 @@ func (v *AlterClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
 						s.Remove = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Ops = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -35518,7 +44060,7 @@ func (v *AlterClientQuotasRequest) readFrom(src []byte, unsafe bool) error {
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -35699,17 +44241,114 @@ func (v *AlterClientQuotasResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterClientQuotasResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Entries
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.Entity
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Type
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Name
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterClientQuotasResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterClientQuotasResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterClientQuotasResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -35815,21 +44454,21 @@ func (v *AlterClientQuotasResponse) readFrom(src []byte, unsafe bool) error {
 						s.Name = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Entity = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Entries = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -35949,17 +44588,61 @@ func (v *DescribeUserSCRAMCredentialsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeUserSCRAMCredentialsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Users
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeUserSCRAMCredentialsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeUserSCRAMCredentialsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeUserSCRAMCredentialsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeUserSCRAMCredentialsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -36006,14 +44689,14 @@ func (v *DescribeUserSCRAMCredentialsRequest) readFrom(src []byte, unsafe bool)
 				s.Name = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Users = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -36226,17 +44909,123 @@ func (v *DescribeUserSCRAMCredentialsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeUserSCRAMCredentialsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Results
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.User
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.CredentialInfos
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 1
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeUserSCRAMCredentialsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeUserSCRAMCredentialsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeUserSCRAMCredentialsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -36354,21 +45143,21 @@ func (v *DescribeUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool)
 						s.Iterations = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.CredentialInfos = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Results = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -36581,17 +45370,114 @@ func (v *AlterUserSCRAMCredentialsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterUserSCRAMCredentialsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Deletions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.Upsertions
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 1
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Salt
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			{
+				v := v.SaltedPassword
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterUserSCRAMCredentialsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterUserSCRAMCredentialsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterUserSCRAMCredentialsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterUserSCRAMCredentialsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -36639,7 +45525,7 @@ func (v *AlterUserSCRAMCredentialsRequest) readFrom(src []byte, unsafe bool) err
 				s.Mechanism = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -36709,14 +45595,14 @@ func (v *AlterUserSCRAMCredentialsRequest) readFrom(src []byte, unsafe bool) err
 				s.SaltedPassword = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Upsertions = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -36854,17 +45740,82 @@ func (v *AlterUserSCRAMCredentialsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterUserSCRAMCredentialsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Results
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.User
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterUserSCRAMCredentialsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterUserSCRAMCredentialsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterUserSCRAMCredentialsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -36933,14 +45884,14 @@ func (v *AlterUserSCRAMCredentialsResponse) readFrom(src []byte, unsafe bool) er
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Results = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -37164,17 +46115,120 @@ func (v *VoteRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *VoteRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if version >= 1 {
+						n += 16
+					}
+					if version >= 1 {
+						n += 16
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if version >= 2 {
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *VoteRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *VoteRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *VoteRequest) readFrom(src []byte, unsafe bool) error {
+func (v *VoteRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -37291,21 +46345,21 @@ func (v *VoteRequest) readFrom(src []byte, unsafe bool) error {
 						s.PreVote = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -37554,17 +46608,130 @@ func (v *VoteResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *VoteResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.NodeEndpoints
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				if version >= 1 {
+					n += 4
+				}
+				if version >= 1 {
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				if version >= 1 {
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *VoteResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *VoteResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
+func (v *VoteResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -37652,14 +46819,14 @@ func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
 						s.VoteGranted = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -37669,9 +46836,9 @@ func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.NodeEndpoints
 				a := v
 				var l int32
@@ -37717,7 +46884,7 @@ func (v *VoteResponse) readFrom(src []byte, unsafe bool) error {
 						s.Port = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -37987,17 +47154,146 @@ func (v *BeginQuorumEpochRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BeginQuorumEpochRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if version >= 1 {
+						n += 16
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		v := v.LeaderEndpoints
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *BeginQuorumEpochRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BeginQuorumEpochRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BeginQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
+func (v *BeginQuorumEpochRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -38098,14 +47394,14 @@ func (v *BeginQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -38170,14 +47466,14 @@ func (v *BeginQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
 				s.Port = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.LeaderEndpoints = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -38421,17 +47717,127 @@ func (v *BeginQuorumEpochResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BeginQuorumEpochResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.NodeEndpoints
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				if version >= 1 {
+					n += 4
+				}
+				if version >= 1 {
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				if version >= 1 {
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *BeginQuorumEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BeginQuorumEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BeginQuorumEpochResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -38515,14 +47921,14 @@ func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -38532,9 +47938,9 @@ func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.NodeEndpoints
 				a := v
 				var l int32
@@ -38580,7 +47986,7 @@ func (v *BeginQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 						s.Port = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -38897,17 +48303,172 @@ func (v *EndQuorumEpochRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EndQuorumEpochRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.PreferredSuccessors
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 1 {
+						v := v.PreferredCandidates
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 16
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		v := v.LeaderEndpoints
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *EndQuorumEpochRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EndQuorumEpochRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EndQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
+func (v *EndQuorumEpochRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -39051,21 +48612,21 @@ func (v *EndQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
 								s.CandidateDirectoryID = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.PreferredCandidates = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -39130,14 +48691,14 @@ func (v *EndQuorumEpochRequest) readFrom(src []byte, unsafe bool) error {
 				s.Port = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.LeaderEndpoints = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -39380,17 +48941,127 @@ func (v *EndQuorumEpochResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EndQuorumEpochResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 1
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.NodeEndpoints
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				if version >= 1 {
+					n += 4
+				}
+				if version >= 1 {
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				if version >= 1 {
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *EndQuorumEpochResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EndQuorumEpochResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EndQuorumEpochResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 1
@@ -39474,14 +49145,14 @@ func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -39491,9 +49162,9 @@ func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.NodeEndpoints
 				a := v
 				var l int32
@@ -39539,7 +49210,7 @@ func (v *EndQuorumEpochResponse) readFrom(src []byte, unsafe bool) error {
 						s.Port = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -39745,17 +49416,81 @@ func (v *DescribeQuorumRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeQuorumRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeQuorumRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeQuorumRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeQuorumRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeQuorumRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -39823,21 +49558,21 @@ func (v *DescribeQuorumRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partition = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -40206,17 +49941,248 @@ func (v *DescribeQuorumResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeQuorumResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 2
+	}
+	if version >= 2 {
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					if version >= 2 {
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					{
+						v := v.CurrentVoters
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							if version >= 2 {
+								n += 16
+							}
+							{
+								n += 8
+							}
+							if version >= 1 {
+								n += 8
+							}
+							if version >= 1 {
+								n += 8
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					{
+						v := v.Observers
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							if version >= 2 {
+								n += 16
+							}
+							{
+								n += 8
+							}
+							if version >= 1 {
+								n += 8
+							}
+							if version >= 1 {
+								n += 8
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 2 {
+		v := v.Nodes
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Listeners
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Name
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Host
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 2
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeQuorumResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeQuorumResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeQuorumResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -40378,7 +50344,7 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 								s.LastCaughtUpTimestamp = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
@@ -40425,21 +50391,21 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 								s.LastCaughtUpTimestamp = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Observers = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -40528,21 +50494,21 @@ func (v *DescribeQuorumResponse) readFrom(src []byte, unsafe bool) error {
 						s.Port = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Listeners = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Nodes = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -40810,17 +50776,131 @@ func (v *AlterPartitionRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterPartitionRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 8
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 1 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 2 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if version >= 0 && version <= 2 {
+						v := v.NewISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 3 {
+						v := v.NewEpochISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if version >= 1 {
+						n += 1
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterPartitionRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterPartitionRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterPartitionRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -40955,7 +51035,7 @@ func (v *AlterPartitionRequest) readFrom(src []byte, unsafe bool) error {
 								s.BrokerEpoch = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
@@ -40970,21 +51050,21 @@ func (v *AlterPartitionRequest) readFrom(src []byte, unsafe bool) error {
 						s.PartitionEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -41198,17 +51278,114 @@ func (v *AlterPartitionResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterPartitionResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			if version >= 0 && version <= 1 {
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 2 {
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if version >= 1 {
+						n += 1
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterPartitionResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterPartitionResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterPartitionResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -41331,21 +51508,21 @@ func (v *AlterPartitionResponse) readFrom(src []byte, unsafe bool) error {
 						s.PartitionEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -41512,17 +51689,76 @@ func (v *UpdateFeaturesRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateFeaturesRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.FeatureUpdates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Feature
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if version >= 0 && version <= 0 {
+				n += 1
+			}
+			if version >= 1 {
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UpdateFeaturesRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateFeaturesRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateFeaturesRequest) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateFeaturesRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -41582,7 +51818,7 @@ func (v *UpdateFeaturesRequest) readFrom(src []byte, unsafe bool) error {
 				s.UpgradeType = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -41593,7 +51829,7 @@ func (v *UpdateFeaturesRequest) readFrom(src []byte, unsafe bool) error {
 		s.ValidateOnly = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -41742,17 +51978,100 @@ func (v *UpdateFeaturesResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateFeaturesResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Results
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Feature
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UpdateFeaturesResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateFeaturesResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateFeaturesResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -41842,14 +52161,14 @@ func (v *UpdateFeaturesResponse) readFrom(src []byte, unsafe bool) error {
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Results = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -41951,17 +52270,62 @@ func (v *EnvelopeRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EnvelopeRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.RequestData
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		v := v.RequestPrincipal
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		v := v.ClientHostAddress
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *EnvelopeRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EnvelopeRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EnvelopeRequest) readFrom(src []byte, unsafe bool) error {
+func (v *EnvelopeRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -41995,7 +52359,7 @@ func (v *EnvelopeRequest) readFrom(src []byte, unsafe bool) error {
 		s.ClientHostAddress = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -42070,17 +52434,47 @@ func (v *EnvelopeResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *EnvelopeResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.ResponseData
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *EnvelopeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *EnvelopeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *EnvelopeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *EnvelopeResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -42100,7 +52494,7 @@ func (v *EnvelopeResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -42382,17 +52776,126 @@ func (v *FetchSnapshotRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FetchSnapshotRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := &v.SnapshotID
+						{
+							n += 8
+						}
+						{
+							n += 4
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+						n += 18
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.ClusterID
+			start := n
+			{
+				var vv string
+				if v != nil {
+					vv = *v
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(vv) + 1))
+				} else {
+					n += 2
+				}
+				n += len(vv)
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *FetchSnapshotRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FetchSnapshotRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FetchSnapshotRequest) readFrom(src []byte, unsafe bool) error {
+func (v *FetchSnapshotRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -42484,7 +52987,7 @@ func (v *FetchSnapshotRequest) readFrom(src []byte, unsafe bool) error {
 							s.Epoch = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					{
@@ -42495,9 +52998,9 @@ func (v *FetchSnapshotRequest) readFrom(src []byte, unsafe bool) error {
 						for i := b.Uvarint(); i > 0; i-- {
 							switch key := b.Uvarint(); key {
 							default:
-								s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+								s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 							case 0:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := b.Uuid()
 								s.ReplicaDirectoryID = v
 								if err := b.Complete(); err != nil {
@@ -42511,7 +53014,7 @@ func (v *FetchSnapshotRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -42521,9 +53024,9 @@ func (v *FetchSnapshotRequest) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				var v *string
 				if isFlexible {
 					if unsafe {
@@ -42928,17 +53431,171 @@ func (v *FetchSnapshotResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *FetchSnapshotResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := &v.SnapshotID
+						{
+							n += 8
+						}
+						{
+							n += 4
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					{
+						n += 8
+					}
+					{
+						n += 8
+					}
+					{
+						v := v.Bytes
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+						{
+							v := v.CurrentLeader
+							start := n
+							{
+								n += 4
+							}
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+							n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+						}
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.NodeEndpoints
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			for i := range v {
+				v := &v[i]
+				if version >= 1 {
+					n += 4
+				}
+				if version >= 1 {
+					v := v.Host
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(v) + 1))
+					} else {
+						n += 2
+					}
+					n += len(v)
+				}
+				if version >= 1 {
+					n += 2
+				}
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+					v.UnknownTags.Each(func(key uint32, val []byte) {
+						n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+					})
+				}
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *FetchSnapshotResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *FetchSnapshotResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
+func (v *FetchSnapshotResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -43030,7 +53687,7 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 							s.Epoch = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					{
@@ -43054,9 +53711,9 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 						for i := b.Uvarint(); i > 0; i-- {
 							switch key := b.Uvarint(); key {
 							default:
-								s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+								s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 							case 0:
-								b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+								b := b.Nested(b.Span(int(b.Uvarint())))
 								v := &s.CurrentLeader
 								v.Default()
 								s := v
@@ -43069,7 +53726,7 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 									s.LeaderEpoch = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 								if err := b.Complete(); err != nil {
 									return err
@@ -43082,7 +53739,7 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -43092,9 +53749,9 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.NodeEndpoints
 				a := v
 				var l int32
@@ -43140,7 +53797,7 @@ func (v *FetchSnapshotResponse) readFrom(src []byte, unsafe bool) error {
 						s.Port = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -43242,17 +53899,44 @@ func (v *DescribeClusterRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeClusterRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 1
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if version >= 2 {
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeClusterRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeClusterRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeClusterRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClusterRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -43271,7 +53955,7 @@ func (v *DescribeClusterRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeFencedBrokers = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -43476,17 +54160,124 @@ func (v *DescribeClusterResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeClusterResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 1
+	}
+	{
+		v := v.ClusterID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Brokers
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Rack
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if version >= 2 {
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeClusterResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeClusterResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeClusterResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -43609,7 +54400,7 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 				s.IsFenced = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -43620,7 +54411,7 @@ func (v *DescribeClusterResponse) readFrom(src []byte, unsafe bool) error {
 		s.ClusterAuthorizedOperations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -43756,17 +54547,70 @@ func (v *DescribeProducersRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeProducersRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeProducersRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeProducersRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeProducersRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeProducersRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -43833,14 +54677,14 @@ func (v *DescribeProducersRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -44109,17 +54953,137 @@ func (v *DescribeProducersResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeProducersResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := v.ActiveProducers
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 4
+							}
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeProducersResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeProducersResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeProducersResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -44256,28 +55220,28 @@ func (v *DescribeProducersResponse) readFrom(src []byte, unsafe bool) error {
 								s.CurrentTxnStartOffset = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.ActiveProducers = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -44546,17 +55510,153 @@ func (v *BrokerRegistrationRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BrokerRegistrationRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.ClusterID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 16
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.Features
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.Rack
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 1
+	}
+	if version >= 2 {
+		v := v.LogDirs
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += 16 * len(v)
+	}
+	if version >= 3 {
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *BrokerRegistrationRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BrokerRegistrationRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BrokerRegistrationRequest) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerRegistrationRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -44650,7 +55750,7 @@ func (v *BrokerRegistrationRequest) readFrom(src []byte, unsafe bool) error {
 				s.SecurityProtocol = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -44702,7 +55802,7 @@ func (v *BrokerRegistrationRequest) readFrom(src []byte, unsafe bool) error {
 				s.MaxSupportedVersion = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -44757,7 +55857,7 @@ func (v *BrokerRegistrationRequest) readFrom(src []byte, unsafe bool) error {
 		s.PreviousBrokerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -44846,17 +55946,44 @@ func (v *BrokerRegistrationResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BrokerRegistrationResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *BrokerRegistrationResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BrokerRegistrationResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BrokerRegistrationResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerRegistrationResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -44875,7 +56002,7 @@ func (v *BrokerRegistrationResponse) readFrom(src []byte, unsafe bool) error {
 		s.BrokerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -45014,17 +56141,61 @@ func (v *BrokerHeartbeatRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BrokerHeartbeatRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 8
+	}
+	{
+		n += 8
+	}
+	{
+		n += 1
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.OfflineLogDirs
+			start := n
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 4
+			}
+			n += 16 * len(v)
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *BrokerHeartbeatRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BrokerHeartbeatRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BrokerHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerHeartbeatRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45054,9 +56225,9 @@ func (v *BrokerHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := s.OfflineLogDirs
 				a := v
 				var l int32
@@ -45182,17 +56353,50 @@ func (v *BrokerHeartbeatResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *BrokerHeartbeatResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 1
+	}
+	{
+		n += 1
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *BrokerHeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *BrokerHeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *BrokerHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *BrokerHeartbeatResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45219,7 +56423,7 @@ func (v *BrokerHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
 		s.ShouldShutdown = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -45295,17 +56499,38 @@ func (v *UnregisterBrokerRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UnregisterBrokerRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UnregisterBrokerRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UnregisterBrokerRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UnregisterBrokerRequest) readFrom(src []byte, unsafe bool) error {
+func (v *UnregisterBrokerRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45316,7 +56541,7 @@ func (v *UnregisterBrokerRequest) readFrom(src []byte, unsafe bool) error {
 		s.BrokerID = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -45403,17 +56628,56 @@ func (v *UnregisterBrokerResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UnregisterBrokerResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UnregisterBrokerResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UnregisterBrokerResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UnregisterBrokerResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45445,7 +56709,7 @@ func (v *UnregisterBrokerResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorMessage = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -45532,17 +56796,52 @@ func (v *DescribeTransactionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeTransactionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.TransactionalIDs
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeTransactionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeTransactionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeTransactionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeTransactionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45585,7 +56884,7 @@ func (v *DescribeTransactionsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TransactionalIDs = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -45825,17 +57124,123 @@ func (v *DescribeTransactionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeTransactionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.TransactionStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.TransactionalID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.State
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 8
+			}
+			{
+				n += 8
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeTransactionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeTransactionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeTransactionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -45980,21 +57385,21 @@ func (v *DescribeTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.TransactionStates = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -46126,17 +57531,79 @@ func (v *ListTransactionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListTransactionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.StateFilters
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	{
+		v := v.ProducerIDFilters
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += 8 * len(v)
+	}
+	if version >= 1 {
+		n += 8
+	}
+	if version >= 2 {
+		v := v.TransactionalIDPattern
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListTransactionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListTransactionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListTransactionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ListTransactionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -46223,7 +57690,7 @@ func (v *ListTransactionsRequest) readFrom(src []byte, unsafe bool) error {
 		s.TransactionalIDPattern = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -46395,17 +57862,96 @@ func (v *ListTransactionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListTransactionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.UnknownStateFilters
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	{
+		v := v.TransactionStates
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.TransactionalID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 8
+			}
+			{
+				v := v.TransactionState
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListTransactionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListTransactionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListTransactionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -46514,14 +58060,14 @@ func (v *ListTransactionsResponse) readFrom(src []byte, unsafe bool) error {
 				s.TransactionState = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.TransactionStates = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -46607,17 +58153,41 @@ func (v *AllocateProducerIDsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AllocateProducerIDsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 8
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AllocateProducerIDsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AllocateProducerIDsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AllocateProducerIDsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AllocateProducerIDsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -46632,7 +58202,7 @@ func (v *AllocateProducerIDsRequest) readFrom(src []byte, unsafe bool) error {
 		s.BrokerEpoch = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -46726,17 +58296,47 @@ func (v *AllocateProducerIDsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AllocateProducerIDsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 8
+	}
+	{
+		n += 4
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AllocateProducerIDsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AllocateProducerIDsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AllocateProducerIDsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AllocateProducerIDsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -46759,7 +58359,7 @@ func (v *AllocateProducerIDsResponse) readFrom(src []byte, unsafe bool) error {
 		s.ProducerIDLen = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -46990,17 +58590,165 @@ func (v *ConsumerGroupHeartbeatRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ConsumerGroupHeartbeatRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Group
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.InstanceID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.RackID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.SubscribedTopicNames
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if version >= 1 {
+		v := v.SubscribedTopicRegex
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.ServerAssignor
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ConsumerGroupHeartbeatRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerGroupHeartbeatRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerGroupHeartbeatRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -47206,14 +58954,14 @@ func (v *ConsumerGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -47432,17 +59180,118 @@ func (v *ConsumerGroupHeartbeatResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ConsumerGroupHeartbeatResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.MemberID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Assignment
+		n += 1
+		if v != nil {
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 16
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ConsumerGroupHeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerGroupHeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerGroupHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerGroupHeartbeatResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -47552,19 +59401,19 @@ func (v *ConsumerGroupHeartbeatResponse) readFrom(src []byte, unsafe bool) error
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -47684,17 +59533,55 @@ func (v *ConsumerGroupDescribeRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ConsumerGroupDescribeRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ConsumerGroupDescribeRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerGroupDescribeRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerGroupDescribeRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerGroupDescribeRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -47741,7 +59628,7 @@ func (v *ConsumerGroupDescribeRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeAuthorizedOperations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -48176,17 +60063,315 @@ func (v *ConsumerGroupDescribeResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ConsumerGroupDescribeResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.Group
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.State
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.AssignorName
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Members
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.MemberID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.InstanceID
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := v.RackID
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.ClientID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.ClientHost
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.SubscribedTopics
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := v[i]
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(v) + 1))
+							} else {
+								n += 2
+							}
+							n += len(v)
+						}
+					}
+					{
+						v := v.SubscribedTopicRegex
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := &v.Assignment
+						{
+							v := v.TopicPartitions
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(v) + 1))
+							} else {
+								n += 4
+							}
+							for i := range v {
+								v := &v[i]
+								{
+									n += 16
+								}
+								{
+									v := v.Topic
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 2
+									}
+									n += len(v)
+								}
+								{
+									v := v.Partitions
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 4
+									}
+									n += 4 * len(v)
+								}
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+									v.UnknownTags.Each(func(key uint32, val []byte) {
+										n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+									})
+								}
+							}
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					{
+						v := &v.TargetAssignment
+						{
+							v := v.TopicPartitions
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(v) + 1))
+							} else {
+								n += 4
+							}
+							for i := range v {
+								v := &v[i]
+								{
+									n += 16
+								}
+								{
+									v := v.Topic
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 2
+									}
+									n += len(v)
+								}
+								{
+									v := v.Partitions
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 4
+									}
+									n += 4 * len(v)
+								}
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+									v.UnknownTags.Each(func(key uint32, val []byte) {
+										n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+									})
+								}
+							}
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					if version >= 1 {
+						n += 1
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ConsumerGroupDescribeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ConsumerGroupDescribeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ConsumerGroupDescribeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ConsumerGroupDescribeResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -48527,14 +60712,14 @@ func (v *ConsumerGroupDescribeResponse) readFrom(src []byte, unsafe bool) error
 									s.Partitions = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 							}
 							v = a
 							s.TopicPartitions = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					{
@@ -48606,14 +60791,14 @@ func (v *ConsumerGroupDescribeResponse) readFrom(src []byte, unsafe bool) error
 									s.Partitions = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 							}
 							v = a
 							s.TopicPartitions = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					if version >= 1 {
@@ -48621,7 +60806,7 @@ func (v *ConsumerGroupDescribeResponse) readFrom(src []byte, unsafe bool) error
 						s.MemberType = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -48632,14 +60817,14 @@ func (v *ConsumerGroupDescribeResponse) readFrom(src []byte, unsafe bool) error
 				s.AuthorizedOperations = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -48856,17 +61041,117 @@ func (v *ControllerRegistrationRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ControllerRegistrationRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 16
+	}
+	{
+		n += 1
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.Features
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ControllerRegistrationRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ControllerRegistrationRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ControllerRegistrationRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ControllerRegistrationRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -48947,7 +61232,7 @@ func (v *ControllerRegistrationRequest) readFrom(src []byte, unsafe bool) error
 				s.SecurityProtocol = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -48999,14 +61284,14 @@ func (v *ControllerRegistrationRequest) readFrom(src []byte, unsafe bool) error
 				s.MaxSupportedVersion = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Features = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49093,17 +61378,56 @@ func (v *ControllerRegistrationResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ControllerRegistrationResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ControllerRegistrationResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ControllerRegistrationResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ControllerRegistrationResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ControllerRegistrationResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -49135,7 +61459,7 @@ func (v *ControllerRegistrationResponse) readFrom(src []byte, unsafe bool) error
 		s.ErrorMessage = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49212,17 +61536,38 @@ func (v *GetTelemetrySubscriptionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *GetTelemetrySubscriptionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 16
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *GetTelemetrySubscriptionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *GetTelemetrySubscriptionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *GetTelemetrySubscriptionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *GetTelemetrySubscriptionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -49233,7 +61578,7 @@ func (v *GetTelemetrySubscriptionsRequest) readFrom(src []byte, unsafe bool) err
 		s.ClientInstanceID = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49380,17 +61725,82 @@ func (v *GetTelemetrySubscriptionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *GetTelemetrySubscriptionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		n += 16
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.AcceptedCompressionTypes
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += 1 * len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 1
+	}
+	{
+		v := v.RequestedMetrics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *GetTelemetrySubscriptionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *GetTelemetrySubscriptionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *GetTelemetrySubscriptionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *GetTelemetrySubscriptionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -49484,7 +61894,7 @@ func (v *GetTelemetrySubscriptionsResponse) readFrom(src []byte, unsafe bool) er
 		s.RequestedMetrics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49590,17 +62000,56 @@ func (v *PushTelemetryRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *PushTelemetryRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 16
+	}
+	{
+		n += 4
+	}
+	{
+		n += 1
+	}
+	{
+		n += 1
+	}
+	{
+		v := v.Metrics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *PushTelemetryRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *PushTelemetryRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *PushTelemetryRequest) readFrom(src []byte, unsafe bool) error {
+func (v *PushTelemetryRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -49632,7 +62081,7 @@ func (v *PushTelemetryRequest) readFrom(src []byte, unsafe bool) error {
 		s.Metrics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49703,17 +62152,41 @@ func (v *PushTelemetryResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *PushTelemetryResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *PushTelemetryResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *PushTelemetryResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *PushTelemetryResponse) readFrom(src []byte, unsafe bool) error {
+func (v *PushTelemetryResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -49728,7 +62201,7 @@ func (v *PushTelemetryResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorCode = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -49942,17 +62415,101 @@ func (v *AssignReplicasToDirsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AssignReplicasToDirsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 8
+	}
+	{
+		v := v.Directories
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 16
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AssignReplicasToDirsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AssignReplicasToDirsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AssignReplicasToDirsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AssignReplicasToDirsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -50039,28 +62596,28 @@ func (v *AssignReplicasToDirsRequest) readFrom(src []byte, unsafe bool) error {
 								s.Partition = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Directories = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -50269,17 +62826,104 @@ func (v *AssignReplicasToDirsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AssignReplicasToDirsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.Directories
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 16
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AssignReplicasToDirsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AssignReplicasToDirsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AssignReplicasToDirsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AssignReplicasToDirsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -50370,28 +63014,28 @@ func (v *AssignReplicasToDirsResponse) readFrom(src []byte, unsafe bool) error {
 								s.ErrorCode = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Directories = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -50477,17 +63121,44 @@ func (v *ListConfigResourcesRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListConfigResourcesRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	if version >= 1 {
+		v := v.ResourceTypes
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		n += 1 * len(v)
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListConfigResourcesRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListConfigResourcesRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListConfigResourcesRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ListConfigResourcesRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -50517,7 +63188,7 @@ func (v *ListConfigResourcesRequest) readFrom(src []byte, unsafe bool) error {
 		s.ResourceTypes = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -50651,17 +63322,70 @@ func (v *ListConfigResourcesResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ListConfigResourcesResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ConfigResources
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if version >= 1 {
+				n += 1
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ListConfigResourcesResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ListConfigResourcesResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ListConfigResourcesResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ListConfigResourcesResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -50717,14 +63441,14 @@ func (v *ListConfigResourcesResponse) readFrom(src []byte, unsafe bool) error {
 				s.Type = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.ConfigResources = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -50902,17 +63626,88 @@ func (v *DescribeTopicPartitionsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeTopicPartitionsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Cursor
+		n += 1
+		if v != nil {
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeTopicPartitionsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeTopicPartitionsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeTopicPartitionsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeTopicPartitionsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -50956,7 +63751,7 @@ func (v *DescribeTopicPartitionsRequest) readFrom(src []byte, unsafe bool) error
 				s.Topic = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -50994,12 +63789,12 @@ func (v *DescribeTopicPartitionsRequest) readFrom(src []byte, unsafe bool) error
 				s.Partition = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -51345,17 +64140,180 @@ func (v *DescribeTopicPartitionsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeTopicPartitionsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Topic
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				n += 16
+			}
+			{
+				n += 1
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.Replicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.ISR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.EligibleLeaderReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.LastKnownELR
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					{
+						v := v.OfflineReplicas
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.NextCursor
+		n += 1
+		if v != nil {
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeTopicPartitionsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeTopicPartitionsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeTopicPartitionsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeTopicPartitionsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -51572,7 +64530,7 @@ func (v *DescribeTopicPartitionsResponse) readFrom(src []byte, unsafe bool) erro
 						s.OfflineReplicas = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -51583,7 +64541,7 @@ func (v *DescribeTopicPartitionsResponse) readFrom(src []byte, unsafe bool) erro
 				s.AuthorizedOperations = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -51617,12 +64575,12 @@ func (v *DescribeTopicPartitionsResponse) readFrom(src []byte, unsafe bool) erro
 				s.Partition = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -51756,17 +64714,88 @@ func (v *ShareGroupHeartbeatRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareGroupHeartbeatRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.MemberID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.RackID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.SubscribedTopicNames
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareGroupHeartbeatRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareGroupHeartbeatRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ShareGroupHeartbeatRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -51867,7 +64896,7 @@ func (v *ShareGroupHeartbeatRequest) readFrom(src []byte, unsafe bool) error {
 		s.SubscribedTopicNames = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -52087,17 +65116,118 @@ func (v *ShareGroupHeartbeatResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareGroupHeartbeatResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.MemberID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Assignment
+		n += 1
+		if v != nil {
+			{
+				v := v.TopicPartitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 16
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareGroupHeartbeatResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareGroupHeartbeatResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareGroupHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ShareGroupHeartbeatResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -52207,19 +65337,19 @@ func (v *ShareGroupHeartbeatResponse) readFrom(src []byte, unsafe bool) error {
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.TopicPartitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -52321,17 +65451,55 @@ func (v *ShareGroupDescribeRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareGroupDescribeRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupIDs
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := v[i]
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(v) + 1))
+			} else {
+				n += 2
+			}
+			n += len(v)
+		}
+	}
+	{
+		n += 1
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareGroupDescribeRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareGroupDescribeRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareGroupDescribeRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ShareGroupDescribeRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -52378,7 +65546,7 @@ func (v *ShareGroupDescribeRequest) readFrom(src []byte, unsafe bool) error {
 		s.IncludeAuthorizedOperations = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -52779,17 +65947,235 @@ func (v *ShareGroupDescribeResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareGroupDescribeResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			{
+				v := v.GroupID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.GroupState
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Assignor
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Members
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.MemberID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.RackID
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.ClientID
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.ClientHost
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.SubscribedTopicNames
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := v[i]
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(v) + 1))
+							} else {
+								n += 2
+							}
+							n += len(v)
+						}
+					}
+					{
+						v := &v.Assignment
+						{
+							v := v.TopicPartitions
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(v) + 1))
+							} else {
+								n += 4
+							}
+							for i := range v {
+								v := &v[i]
+								{
+									n += 16
+								}
+								{
+									v := v.Topic
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 2
+									}
+									n += len(v)
+								}
+								{
+									v := v.Partitions
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(v) + 1))
+									} else {
+										n += 4
+									}
+									n += 4 * len(v)
+								}
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+									v.UnknownTags.Each(func(key uint32, val []byte) {
+										n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+									})
+								}
+							}
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareGroupDescribeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareGroupDescribeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareGroupDescribeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ShareGroupDescribeResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -53096,18 +66482,18 @@ func (v *ShareGroupDescribeResponse) readFrom(src []byte, unsafe bool) error {
 									s.Partitions = v
 								}
 								if isFlexible {
-									s.UnknownTags = internalReadTags(&b)
+									s.UnknownTags = internalReadTags(&b, unsafe)
 								}
 							}
 							v = a
 							s.TopicPartitions = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -53118,14 +66504,14 @@ func (v *ShareGroupDescribeResponse) readFrom(src []byte, unsafe bool) error {
 				s.AuthorizedOperations = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -53488,17 +66874,187 @@ func (v *ShareFetchRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareFetchRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.MemberID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	if version >= 1 {
+		n += 4
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						v := v.AcknowledgementBatches
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								v := v.AcknowledgeTypes
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(len(v) + 1))
+								} else {
+									n += 4
+								}
+								n += 1 * len(v)
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.ForgottenTopicsData
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				n += 4 * len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareFetchRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareFetchRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareFetchRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ShareFetchRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -53666,21 +67222,21 @@ func (v *ShareFetchRequest) readFrom(src []byte, unsafe bool) error {
 								s.AcknowledgeTypes = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.AcknowledgementBatches = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -53734,14 +67290,14 @@ func (v *ShareFetchRequest) readFrom(src []byte, unsafe bool) error {
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.ForgottenTopicsData = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -54163,17 +67719,232 @@ func (v *ShareFetchResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareFetchResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if version >= 1 {
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.AcknowledgeErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := &v.CurrentLeader
+						{
+							n += 4
+						}
+						{
+							n += 4
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					{
+						v := v.Records
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += len(v)
+					}
+					{
+						v := v.AcquiredRecords
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.NodeEndpoints
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Rack
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareFetchResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareFetchResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareFetchResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ShareFetchResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -54311,7 +68082,7 @@ func (v *ShareFetchResponse) readFrom(src []byte, unsafe bool) error {
 							s.LeaderEpoch = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					{
@@ -54356,21 +68127,21 @@ func (v *ShareFetchResponse) readFrom(src []byte, unsafe bool) error {
 								s.DeliveryCount = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.AcquiredRecords = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -54439,14 +68210,14 @@ func (v *ShareFetchResponse) readFrom(src []byte, unsafe bool) error {
 				s.Rack = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.NodeEndpoints = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -54701,17 +68472,140 @@ func (v *ShareAcknowledgeRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareAcknowledgeRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.MemberID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						v := v.AcknowledgementBatches
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								v := v.AcknowledgeTypes
+								if isFlexible {
+									n += kbin.UvarintLen(uint32(len(v) + 1))
+								} else {
+									n += 4
+								}
+								n += 1 * len(v)
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareAcknowledgeRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareAcknowledgeRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareAcknowledgeRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ShareAcknowledgeRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -54855,28 +68749,28 @@ func (v *ShareAcknowledgeRequest) readFrom(src []byte, unsafe bool) error {
 								s.AcknowledgeTypes = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.AcknowledgementBatches = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -55202,17 +69096,176 @@ func (v *ShareAcknowledgeResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ShareAcknowledgeResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						v := &v.CurrentLeader
+						{
+							n += 4
+						}
+						{
+							n += 4
+						}
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+							v.UnknownTags.Each(func(key uint32, val []byte) {
+								n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+							})
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := v.NodeEndpoints
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Rack
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ShareAcknowledgeResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ShareAcknowledgeResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ShareAcknowledgeResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ShareAcknowledgeResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -55325,18 +69378,18 @@ func (v *ShareAcknowledgeResponse) readFrom(src []byte, unsafe bool) error {
 							s.LeaderEpoch = v
 						}
 						if isFlexible {
-							s.UnknownTags = internalReadTags(&b)
+							s.UnknownTags = internalReadTags(&b, unsafe)
 						}
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -55405,14 +69458,14 @@ func (v *ShareAcknowledgeResponse) readFrom(src []byte, unsafe bool) error {
 				s.Rack = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.NodeEndpoints = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -55584,17 +69637,97 @@ func (v *AddRaftVoterRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddRaftVoterRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 16
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddRaftVoterRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddRaftVoterRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AddRaftVoterRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -55688,14 +69821,14 @@ func (v *AddRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
 				s.Port = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Listeners = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -55775,17 +69908,56 @@ func (v *AddRaftVoterResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AddRaftVoterResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AddRaftVoterResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AddRaftVoterResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AddRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AddRaftVoterResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -55817,7 +69989,7 @@ func (v *AddRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorMessage = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -55910,17 +70082,56 @@ func (v *RemoveRaftVoterRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *RemoveRaftVoterRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 16
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *RemoveRaftVoterRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *RemoveRaftVoterRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *RemoveRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+func (v *RemoveRaftVoterRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -55952,7 +70163,7 @@ func (v *RemoveRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
 		s.VoterDirectoryID = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -56036,17 +70247,56 @@ func (v *RemoveRaftVoterResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *RemoveRaftVoterResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *RemoveRaftVoterResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *RemoveRaftVoterResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *RemoveRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+func (v *RemoveRaftVoterResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -56078,7 +70328,7 @@ func (v *RemoveRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
 		s.ErrorMessage = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -56285,17 +70535,112 @@ func (v *UpdateRaftVoterRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateRaftVoterRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.ClusterID
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		n += 4
+	}
+	{
+		n += 4
+	}
+	{
+		n += 16
+	}
+	{
+		v := v.Listeners
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Name
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 2
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	{
+		v := &v.KRaftVersionFeature
+		{
+			n += 2
+		}
+		{
+			n += 2
+		}
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+			v.UnknownTags.Each(func(key uint32, val []byte) {
+				n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+			})
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *UpdateRaftVoterRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateRaftVoterRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateRaftVoterRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -56389,7 +70734,7 @@ func (v *UpdateRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
 				s.Port = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
@@ -56408,11 +70753,11 @@ func (v *UpdateRaftVoterRequest) readFrom(src []byte, unsafe bool) error {
 			s.MaxSupportedVersion = v
 		}
 		if isFlexible {
-			s.UnknownTags = internalReadTags(&b)
+			s.UnknownTags = internalReadTags(&b, unsafe)
 		}
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -56578,17 +70923,70 @@ func (v *UpdateRaftVoterResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *UpdateRaftVoterResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(1 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+		{
+			v := v.CurrentLeader
+			start := n
+			{
+				n += 4
+			}
+			{
+				n += 4
+			}
+			{
+				v := v.Host
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 4
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+			n += kbin.UvarintLen(0) + kbin.UvarintLen(uint32(n-start))
+		}
+	}
+	return n
+}
+
 func (v *UpdateRaftVoterResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *UpdateRaftVoterResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *UpdateRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
+func (v *UpdateRaftVoterResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -56606,9 +71004,9 @@ func (v *UpdateRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
 		for i := b.Uvarint(); i > 0; i-- {
 			switch key := b.Uvarint(); key {
 			default:
-				s.UnknownTags.Set(key, b.Span(int(b.Uvarint())))
+				s.UnknownTags.setRead(key, b.Span(int(b.Uvarint())), unsafe)
 			case 0:
-				b := kbin.Reader{Src: b.Span(int(b.Uvarint()))}
+				b := b.Nested(b.Span(int(b.Uvarint())))
 				v := &s.CurrentLeader
 				v.Default()
 				s := v
@@ -56642,7 +71040,7 @@ func (v *UpdateRaftVoterResponse) readFrom(src []byte, unsafe bool) error {
 					s.Port = v
 				}
 				if isFlexible {
-					s.UnknownTags = internalReadTags(&b)
+					s.UnknownTags = internalReadTags(&b, unsafe)
 				}
 				if err := b.Complete(); err != nil {
 					return err
@@ -56833,17 +71231,90 @@ func (v *InitializeShareGroupStateRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *InitializeShareGroupStateRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *InitializeShareGroupStateRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *InitializeShareGroupStateRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *InitializeShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
+func (v *InitializeShareGroupStateRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -56923,21 +71394,21 @@ func (v *InitializeShareGroupStateRequest) readFrom(src []byte, unsafe bool) err
 						s.StartOffset = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -57104,17 +71575,93 @@ func (v *InitializeShareGroupStateResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *InitializeShareGroupStateResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *InitializeShareGroupStateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *InitializeShareGroupStateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *InitializeShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *InitializeShareGroupStateResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -57190,21 +71737,21 @@ func (v *InitializeShareGroupStateResponse) readFrom(src []byte, unsafe bool) er
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -57375,17 +71922,87 @@ func (v *ReadShareGroupStateRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ReadShareGroupStateRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ReadShareGroupStateRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ReadShareGroupStateRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ReadShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ReadShareGroupStateRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -57461,21 +72078,21 @@ func (v *ReadShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -57724,17 +72341,128 @@ func (v *ReadShareGroupStateResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ReadShareGroupStateResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					{
+						v := v.StateBatches
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								n += 1
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ReadShareGroupStateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ReadShareGroupStateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ReadShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ReadShareGroupStateResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -57854,28 +72582,28 @@ func (v *ReadShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
 								s.DeliveryCount = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.StateBatches = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -58126,17 +72854,122 @@ func (v *WriteShareGroupStateRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *WriteShareGroupStateRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					{
+						v := v.StateBatches
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 8
+							}
+							{
+								n += 8
+							}
+							{
+								n += 1
+							}
+							{
+								n += 2
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *WriteShareGroupStateRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *WriteShareGroupStateRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *WriteShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
+func (v *WriteShareGroupStateRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -58256,28 +73089,28 @@ func (v *WriteShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
 								s.DeliveryCount = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.StateBatches = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -58447,17 +73280,93 @@ func (v *WriteShareGroupStateResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *WriteShareGroupStateResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *WriteShareGroupStateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *WriteShareGroupStateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *WriteShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *WriteShareGroupStateResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -58533,21 +73442,21 @@ func (v *WriteShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -58711,17 +73620,84 @@ func (v *DeleteShareGroupStateRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteShareGroupStateRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteShareGroupStateRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteShareGroupStateRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteShareGroupStateRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -58793,21 +73769,21 @@ func (v *DeleteShareGroupStateRequest) readFrom(src []byte, unsafe bool) error {
 						s.Partition = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -58976,17 +73952,93 @@ func (v *DeleteShareGroupStateResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteShareGroupStateResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteShareGroupStateResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteShareGroupStateResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteShareGroupStateResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteShareGroupStateResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -59062,21 +74114,21 @@ func (v *DeleteShareGroupStateResponse) readFrom(src []byte, unsafe bool) error
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -59247,17 +74299,87 @@ func (v *ReadShareGroupStateSummaryRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ReadShareGroupStateSummaryRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ReadShareGroupStateSummaryRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ReadShareGroupStateSummaryRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ReadShareGroupStateSummaryRequest) readFrom(src []byte, unsafe bool) error {
+func (v *ReadShareGroupStateSummaryRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -59333,21 +74455,21 @@ func (v *ReadShareGroupStateSummaryRequest) readFrom(src []byte, unsafe bool) er
 						s.LeaderEpoch = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -59537,17 +74659,102 @@ func (v *ReadShareGroupStateSummaryResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *ReadShareGroupStateSummaryResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					{
+						n += 4
+					}
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *ReadShareGroupStateSummaryResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *ReadShareGroupStateSummaryResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *ReadShareGroupStateSummaryResponse) readFrom(src []byte, unsafe bool) error {
+func (v *ReadShareGroupStateSummaryResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -59635,21 +74842,21 @@ func (v *ReadShareGroupStateSummaryResponse) readFrom(src []byte, unsafe bool) e
 						s.StartOffset = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -59826,17 +75033,96 @@ func (v *DescribeShareGroupOffsetsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeShareGroupOffsetsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.GroupID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						n += 4 * len(v)
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeShareGroupOffsetsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeShareGroupOffsetsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeShareGroupOffsetsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -59943,21 +75229,21 @@ func (v *DescribeShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) err
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Topics = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -60236,17 +75522,155 @@ func (v *DescribeShareGroupOffsetsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DescribeShareGroupOffsetsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		v := v.Groups
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.GroupID
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Topics
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						v := v.Topic
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 2
+						}
+						n += len(v)
+					}
+					{
+						n += 16
+					}
+					{
+						v := v.Partitions
+						if isFlexible {
+							n += kbin.UvarintLen(uint32(len(v) + 1))
+						} else {
+							n += 4
+						}
+						for i := range v {
+							v := &v[i]
+							{
+								n += 4
+							}
+							{
+								n += 8
+							}
+							{
+								n += 4
+							}
+							{
+								n += 2
+							}
+							{
+								v := v.ErrorMessage
+								{
+									var vv string
+									if v != nil {
+										vv = *v
+									}
+									if isFlexible {
+										n += kbin.UvarintLen(uint32(len(vv) + 1))
+									} else {
+										n += 2
+									}
+									n += len(vv)
+								}
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+								v.UnknownTags.Each(func(key uint32, val []byte) {
+									n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+								})
+							}
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DescribeShareGroupOffsetsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DescribeShareGroupOffsetsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DescribeShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DescribeShareGroupOffsetsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -60388,14 +75812,14 @@ func (v *DescribeShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) er
 								s.ErrorMessage = v
 							}
 							if isFlexible {
-								s.UnknownTags = internalReadTags(&b)
+								s.UnknownTags = internalReadTags(&b, unsafe)
 							}
 						}
 						v = a
 						s.Partitions = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
@@ -60423,14 +75847,14 @@ func (v *DescribeShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) er
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Groups = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -60605,17 +76029,93 @@ func (v *AlterShareGroupOffsetsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterShareGroupOffsetsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 8
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterShareGroupOffsetsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterShareGroupOffsetsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *AlterShareGroupOffsetsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -60704,21 +76204,21 @@ func (v *AlterShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) error
 						s.StartOffset = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -60936,17 +76436,123 @@ func (v *AlterShareGroupOffsetsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *AlterShareGroupOffsetsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 16
+			}
+			{
+				v := v.Partitions
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 4
+				}
+				for i := range v {
+					v := &v[i]
+					{
+						n += 4
+					}
+					{
+						n += 2
+					}
+					{
+						v := v.ErrorMessage
+						{
+							var vv string
+							if v != nil {
+								vv = *v
+							}
+							if isFlexible {
+								n += kbin.UvarintLen(uint32(len(vv) + 1))
+							} else {
+								n += 2
+							}
+							n += len(vv)
+						}
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+						v.UnknownTags.Each(func(key uint32, val []byte) {
+							n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+						})
+					}
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *AlterShareGroupOffsetsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *AlterShareGroupOffsetsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *AlterShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *AlterShareGroupOffsetsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -61064,21 +76670,21 @@ func (v *AlterShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) error
 						s.ErrorMessage = v
 					}
 					if isFlexible {
-						s.UnknownTags = internalReadTags(&b)
+						s.UnknownTags = internalReadTags(&b, unsafe)
 					}
 				}
 				v = a
 				s.Partitions = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -61203,17 +76809,70 @@ func (v *DeleteShareGroupOffsetsRequest) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteShareGroupOffsetsRequest) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		v := v.GroupID
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 2
+		}
+		n += len(v)
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteShareGroupOffsetsRequest) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteShareGroupOffsetsRequest) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteShareGroupOffsetsRequest) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -61274,14 +76933,14 @@ func (v *DeleteShareGroupOffsetsRequest) readFrom(src []byte, unsafe bool) error
 				s.Topic = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
@@ -61458,17 +77117,103 @@ func (v *DeleteShareGroupOffsetsResponse) AppendTo(dst []byte) []byte {
 	return dst
 }
 
+// ApproxSize returns the approximate size of v encoded at the given
+// version, not including the request or response header. This does not
+// serialize v. The size is exact except for tagged fields: every tagged
+// field valid at the version is counted even if it would be skipped for
+// being its default, making the size a slight overestimate at worst.
+func (v *DeleteShareGroupOffsetsResponse) ApproxSize(version int16) int {
+	_ = version
+	isFlexible := version >= 0
+	_ = isFlexible
+	var n int
+	{
+		n += 4
+	}
+	{
+		n += 2
+	}
+	{
+		v := v.ErrorMessage
+		{
+			var vv string
+			if v != nil {
+				vv = *v
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(len(vv) + 1))
+			} else {
+				n += 2
+			}
+			n += len(vv)
+		}
+	}
+	{
+		v := v.Topics
+		if isFlexible {
+			n += kbin.UvarintLen(uint32(len(v) + 1))
+		} else {
+			n += 4
+		}
+		for i := range v {
+			v := &v[i]
+			{
+				v := v.Topic
+				if isFlexible {
+					n += kbin.UvarintLen(uint32(len(v) + 1))
+				} else {
+					n += 2
+				}
+				n += len(v)
+			}
+			{
+				n += 16
+			}
+			{
+				n += 2
+			}
+			{
+				v := v.ErrorMessage
+				{
+					var vv string
+					if v != nil {
+						vv = *v
+					}
+					if isFlexible {
+						n += kbin.UvarintLen(uint32(len(vv) + 1))
+					} else {
+						n += 2
+					}
+					n += len(vv)
+				}
+			}
+			if isFlexible {
+				n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+				v.UnknownTags.Each(func(key uint32, val []byte) {
+					n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+				})
+			}
+		}
+	}
+	if isFlexible {
+		n += kbin.UvarintLen(uint32(0 + v.UnknownTags.Len()))
+		v.UnknownTags.Each(func(key uint32, val []byte) {
+			n += kbin.UvarintLen(key) + kbin.UvarintLen(uint32(len(val))) + len(val)
+		})
+	}
+	return n
+}
+
 func (v *DeleteShareGroupOffsetsResponse) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *DeleteShareGroupOffsetsResponse) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *DeleteShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) error {
+func (v *DeleteShareGroupOffsetsResponse) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	version := v.Version
 	_ = version
 	isFlexible := version >= 0
@@ -61562,14 +77307,14 @@ func (v *DeleteShareGroupOffsetsResponse) readFrom(src []byte, unsafe bool) erro
 				s.ErrorMessage = v
 			}
 			if isFlexible {
-				s.UnknownTags = internalReadTags(&b)
+				s.UnknownTags = internalReadTags(&b, unsafe)
 			}
 		}
 		v = a
 		s.Topics = v
 	}
 	if isFlexible {
-		s.UnknownTags = internalReadTags(&b)
+		s.UnknownTags = internalReadTags(&b, unsafe)
 	}
 	return b.Complete()
 }
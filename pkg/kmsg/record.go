@@ -88,16 +88,15 @@ func (v *Record) AppendTo(dst []byte) []byte {
 }
 
 func (v *Record) ReadFrom(src []byte) error {
-	return v.readFrom(src, false)
+	return v.readFrom(kbin.Reader{Src: src}, false)
 }
 
 func (v *Record) UnsafeReadFrom(src []byte) error {
-	return v.readFrom(src, true)
+	return v.readFrom(kbin.Reader{Src: src}, true)
 }
 
-func (v *Record) readFrom(src []byte, unsafe bool) error {
+func (v *Record) readFrom(b kbin.Reader, unsafe bool) error {
 	v.Default()
-	b := kbin.Reader{Src: src}
 	s := v
 	{
 		v := b.Varint()
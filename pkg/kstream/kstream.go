@@ -0,0 +1,375 @@
+// Package kstream runs simple topic to topic record pipelines on top of a
+// group consuming kgo.Client.
+//
+// A pipeline is built from one or more source topics with a Builder, and
+// records flow through Map, Filter, and Branch steps before being written to
+// output topics with To. Records can also be counted or aggregated over
+// tumbling windows, with state backed by a changelog topic. This covers the
+// common "read, transform, write" services that otherwise require a full
+// streams framework; there is no joining.
+//
+//	b := kstream.NewBuilder()
+//	in := b.Stream("orders")
+//	valid := in.Filter(func(r *kgo.Record) bool { return len(r.Value) > 0 })
+//	branches := valid.Branch(isDomestic, isInternational)
+//	branches[0].To("orders-domestic")
+//	branches[1].Map(convertCurrency).To("orders-international")
+//
+//	p, err := kstream.New(b, kstream.Config{Guarantee: kstream.ExactlyOnce},
+//	        kgo.SeedBrokers("localhost:9092"),
+//	        kgo.ConsumerGroup("orders-router"),
+//	        kgo.TransactionalID("orders-router"),
+//	)
+//	if err != nil {
+//	        return err
+//	}
+//	defer p.Close()
+//	return p.Run(ctx)
+//
+// The processor owns a few client options: ConsumeTopics is always set to
+// the builder's source topics. With AtLeastOnce, DisableAutoCommit and
+// BlockRebalanceOnPoll are also set. With ExactlyOnce, the pipeline runs in a
+// kgo.GroupTransactSession, which requires a TransactionalID, and
+// FetchIsolationLevel is set to read committed. If the builder has windowed
+// aggregations, RecordPartitioner, OnPartitionsRestore, OnPartitionsRevoked,
+// and OnPartitionsLost are set as well (see Config.Partitioner).
+package kstream
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Guarantee is the processing guarantee a Processor runs a pipeline with.
+type Guarantee int8
+
+const (
+	// AtLeastOnce produces the output of every poll and commits the
+	// polled offsets once all output is acknowledged. If the processor
+	// fails before committing, the records are processed again by
+	// whichever member next consumes their partitions, producing
+	// duplicate output.
+	AtLeastOnce Guarantee = iota
+
+	// ExactlyOnce produces the output of every poll and commits the polled
+	// offsets in one transaction. Consumers reading the output topics with
+	// a read committed isolation level see every output exactly once.
+	ExactlyOnce
+)
+
+// Builder builds the pipelines a Processor runs.
+type Builder struct {
+	sources map[string]*Stream
+	windows []*window
+}
+
+// NewBuilder returns a new, empty builder.
+func NewBuilder() *Builder {
+	return &Builder{sources: make(map[string]*Stream)}
+}
+
+// Stream returns the stream of records consumed from a source topic. Calling
+// Stream multiple times with the same topic returns the same stream.
+func (b *Builder) Stream(topic string) *Stream {
+	s := b.sources[topic]
+	if s == nil {
+		s = &Stream{b: b, topic: topic}
+		b.sources[topic] = s
+	}
+	return s
+}
+
+// Stream is a step in a pipeline. Every step a stream is passed to receives
+// its own shallow copy of each record: steps can modify record fields (such
+// as setting a new Key or Value) without affecting other steps, but must not
+// modify the bytes of a Key, Value, or header in place.
+type Stream struct {
+	b     *Builder
+	topic string // the source topic this stream descends from
+
+	apply    func(*kgo.Record) *kgo.Record // nil for sources, branches, and windows
+	window   *window                       // non-nil for windowed aggregations
+	branches []func(*kgo.Record) bool      // non-nil if children are exclusive branches
+	children []*Stream
+	sinks    []string
+}
+
+func (s *Stream) child(c *Stream) *Stream {
+	c.b, c.topic = s.b, s.topic
+	s.children = append(s.children, c)
+	return c
+}
+
+// Map returns a stream of records transformed by fn. If fn returns nil, the
+// record is dropped.
+func (s *Stream) Map(fn func(*kgo.Record) *kgo.Record) *Stream {
+	return s.child(&Stream{apply: fn})
+}
+
+// Filter returns a stream of only the records that fn returns true for.
+func (s *Stream) Filter(fn func(*kgo.Record) bool) *Stream {
+	return s.child(&Stream{apply: func(r *kgo.Record) *kgo.Record {
+		if fn(r) {
+			return r
+		}
+		return nil
+	}})
+}
+
+// Branch splits the stream with the given predicates, returning one stream
+// per predicate. Each record is sent to the stream of the first predicate that
+// returns true for it; records that no predicate matches are dropped.
+func (s *Stream) Branch(preds ...func(*kgo.Record) bool) []*Stream {
+	b := s.child(&Stream{branches: preds})
+	streams := make([]*Stream, len(preds))
+	for i := range preds {
+		streams[i] = b.child(new(Stream))
+	}
+	return streams
+}
+
+// To writes every record in the stream to topic. The output record keeps the
+// key, value, headers, and timestamp of the stream's record and is
+// partitioned with the client's partitioner. To can be called multiple times
+// to write to multiple topics, and the stream can still be used for further
+// steps.
+func (s *Stream) To(topic string) {
+	s.sinks = append(s.sinks, topic)
+}
+
+// process runs r, consumed from partition part of the stream's source topic,
+// through s and its children, appending output records to out.
+func (s *Stream) process(part int32, r *kgo.Record, out []*kgo.Record) []*kgo.Record {
+	if s.apply != nil {
+		if r = s.apply(r); r == nil {
+			return out
+		}
+	}
+	if s.window != nil {
+		if r, out = s.window.add(part, r, out); r == nil {
+			return out
+		}
+	}
+	for _, topic := range s.sinks {
+		out = append(out, &kgo.Record{
+			Key:       r.Key,
+			Value:     r.Value,
+			Headers:   slices.Clone(r.Headers),
+			Timestamp: r.Timestamp,
+			Topic:     topic,
+		})
+	}
+	if s.branches != nil {
+		for i, pred := range s.branches {
+			if pred(r) {
+				return s.children[i].process(part, copyRecord(r), out)
+			}
+		}
+		return out
+	}
+	for _, c := range s.children {
+		out = c.process(part, copyRecord(r), out)
+	}
+	return out
+}
+
+func copyRecord(r *kgo.Record) *kgo.Record {
+	cp := *r
+	cp.Headers = slices.Clone(r.Headers)
+	return &cp
+}
+
+// Config configures a Processor.
+type Config struct {
+	// Guarantee is the processing guarantee, defaulting to AtLeastOnce.
+	Guarantee Guarantee
+
+	// Partitioner is the partitioner for records written with To when the
+	// builder has windowed aggregations, defaulting to the kgo default
+	// partitioner. Changelog records must be written to the partition
+	// they were aggregated from, so the processor owns the client's
+	// RecordPartitioner option when windows are used. Without windows,
+	// this field is unused and RecordPartitioner can be passed to New.
+	Partitioner kgo.Partitioner
+}
+
+// Processor consumes the source topics of a Builder and runs every record
+// through the builder's pipelines.
+type Processor struct {
+	b    *Builder
+	cl   *kgo.Client
+	sess *kgo.GroupTransactSession // non-nil if ExactlyOnce
+}
+
+// New returns a processor running b's pipelines with a client created from
+// opts, which must configure a consumer group. The builder must not be
+// modified after calling New.
+func New(b *Builder, cfg Config, opts ...kgo.Opt) (*Processor, error) {
+	if len(b.sources) == 0 {
+		return nil, errors.New("kstream: the builder has no source streams")
+	}
+	topics := make([]string, 0, len(b.sources))
+	for t := range b.sources {
+		topics = append(topics, t)
+	}
+	slices.Sort(topics)
+
+	p := &Processor{b: b}
+	opts = append(opts[:len(opts):len(opts)], kgo.ConsumeTopics(topics...))
+	if len(b.windows) > 0 {
+		wopts, err := p.windowOpts(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, wopts...)
+	}
+
+	switch cfg.Guarantee {
+	case AtLeastOnce:
+		cl, err := kgo.NewClient(append(opts, kgo.DisableAutoCommit(), kgo.BlockRebalanceOnPoll())...)
+		if err != nil {
+			return nil, err
+		}
+		p.cl = cl
+	case ExactlyOnce:
+		sess, err := kgo.NewGroupTransactSession(append(opts, kgo.FetchIsolationLevel(kgo.ReadCommitted()))...)
+		if err != nil {
+			return nil, err
+		}
+		if set, _ := sess.Client().OptValues(kgo.TransactionalID)[1].(bool); !set {
+			sess.Close()
+			return nil, errors.New("kstream: ExactlyOnce requires a TransactionalID")
+		}
+		p.sess, p.cl = sess, sess.Client()
+	default:
+		return nil, errors.New("kstream: unknown Guarantee")
+	}
+
+	if g, _ := p.cl.OptValue(kgo.ConsumerGroup).(string); g == "" {
+		p.Close()
+		return nil, errors.New("kstream: the client must be configured with a consumer group")
+	}
+	return p, nil
+}
+
+// Client returns the processor's underlying client.
+func (p *Processor) Client() *kgo.Client { return p.cl }
+
+// Close closes the underlying client, leaving the group. This must be called
+// after Run returns.
+func (p *Processor) Close() {
+	if p.sess != nil {
+		p.sess.Close()
+		return
+	}
+	p.cl.Close()
+}
+
+// Run polls, processes, and produces until the context is canceled, the
+// client is closed, or producing fails. With AtLeastOnce, a failed commit is
+// logged and the records are processed again after the next rebalance. With
+// ExactlyOnce, a transaction that is aborted due to a rebalance is retried
+// from the last committed offsets.
+//
+// Run returns the context error, kgo.ErrClientClosed, or the first produce or
+// transaction error.
+func (p *Processor) Run(ctx context.Context) error {
+	for {
+		var fs kgo.Fetches
+		if p.sess != nil {
+			fs = p.sess.PollFetches(ctx)
+		} else {
+			fs = p.cl.PollFetches(ctx)
+		}
+		if fs.IsClientClosed() {
+			return kgo.ErrClientClosed
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		fs.EachError(func(t string, part int32, err error) {
+			if !errors.Is(err, context.Canceled) {
+				p.log(kgo.LogLevelWarn, "kstream skipping fetch error", "topic", t, "partition", part, "err", err)
+			}
+		})
+
+		// With ExactlyOnce, an aborted transaction is processed again
+		// from the committed offsets, so the window aggregates must be
+		// rolled back to match.
+		if p.sess != nil {
+			for _, w := range p.b.windows {
+				w.track()
+			}
+		}
+
+		var out []*kgo.Record
+		fs.EachRecord(func(r *kgo.Record) {
+			if s := p.b.sources[r.Topic]; s != nil {
+				out = s.process(r.Partition, copyRecord(r), out)
+			}
+		})
+
+		var err error
+		if p.sess != nil {
+			var committed bool
+			committed, err = p.runTxn(ctx, fs, out)
+			for _, w := range p.b.windows {
+				if committed {
+					w.commit()
+				} else {
+					w.rollback()
+				}
+			}
+		} else {
+			err = p.runAtLeastOnce(ctx, fs, out)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (p *Processor) runAtLeastOnce(ctx context.Context, fs kgo.Fetches, out []*kgo.Record) error {
+	defer p.cl.AllowRebalance()
+	if fs.NumRecords() == 0 {
+		return nil
+	}
+	if err := p.cl.ProduceSync(ctx, out...).FirstErr(); err != nil {
+		return err
+	}
+	if err := p.cl.CommitUncommittedOffsets(ctx); err != nil {
+		p.log(kgo.LogLevelWarn, "kstream unable to commit processed offsets, records will be processed again", "err", err)
+	}
+	return nil
+}
+
+// runTxn produces out and commits the polled offsets in one transaction,
+// returning whether the transaction committed.
+func (p *Processor) runTxn(ctx context.Context, fs kgo.Fetches, out []*kgo.Record) (bool, error) {
+	if fs.NumRecords() == 0 {
+		return true, nil
+	}
+	if err := p.sess.Begin(); err != nil {
+		return false, err
+	}
+	produceErr := p.sess.ProduceSync(ctx, out...).FirstErr()
+	committed, err := p.sess.End(ctx, kgo.TransactionEndTry(produceErr == nil))
+	switch {
+	case produceErr != nil:
+		return false, produceErr
+	case err != nil:
+		return false, err
+	case !committed:
+		p.log(kgo.LogLevelInfo, "kstream transaction aborted due to a rebalance, records will be processed again")
+	}
+	return committed, nil
+}
+
+func (p *Processor) log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if l, _ := p.cl.OptValue(kgo.WithLogger).(kgo.Logger); l != nil && l.Level() >= level {
+		l.Log(level, msg, keyvals...)
+	}
+}
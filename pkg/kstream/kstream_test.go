@@ -0,0 +1,214 @@
+package kstream
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func seeds() kgo.Opt {
+	s := os.Getenv("KGO_SEEDS")
+	if s == "" {
+		s = "127.0.0.1:9092"
+	}
+	return kgo.SeedBrokers(strings.Split(s, ",")...)
+}
+
+func randName() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func isEven(r *kgo.Record) bool {
+	n, err := strconv.Atoi(string(r.Value))
+	return err == nil && n%2 == 0
+}
+
+func upper(r *kgo.Record) *kgo.Record {
+	r.Value = bytes.ToUpper(r.Value)
+	return r
+}
+
+func TestPipeline(t *testing.T) {
+	t.Parallel()
+
+	b := NewBuilder()
+	in := b.Stream("in")
+	if b.Stream("in") != in {
+		t.Fatal("Stream returned a new stream for an existing source")
+	}
+	in.To("all")
+	branches := in.Branch(isEven, func(r *kgo.Record) bool { return string(r.Value) != "x" })
+	branches[0].To("even")
+	branches[1].Map(upper).To("other")
+	in.Filter(func(r *kgo.Record) bool { return r.Key != nil }).To("keyed")
+
+	var out []*kgo.Record
+	for _, r := range []*kgo.Record{
+		{Topic: "in", Value: []byte("2")},
+		{Topic: "in", Value: []byte("a"), Key: []byte("k")},
+		{Topic: "in", Value: []byte("x")},
+	} {
+		out = in.process(0, copyRecord(r), out)
+	}
+
+	var got []string
+	for _, r := range out {
+		got = append(got, r.Topic+"="+string(r.Value))
+	}
+	exp := []string{
+		"all=2", "even=2",
+		"all=a", "other=A", "keyed=a",
+		"all=x",
+	}
+	if !slices.Equal(got, exp) {
+		t.Errorf("got %v != exp %v", got, exp)
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(NewBuilder(), Config{}); err == nil {
+		t.Error("expected error with no source streams")
+	}
+	b := NewBuilder()
+	b.Stream("t").To("u")
+	if _, err := New(b, Config{}, seeds()); err == nil {
+		t.Error("expected error with no consumer group")
+	}
+	if _, err := New(b, Config{Guarantee: ExactlyOnce}, seeds(), kgo.ConsumerGroup("g")); err == nil {
+		t.Error("expected error with ExactlyOnce and no transactional ID")
+	}
+}
+
+func TestProcessor(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name string
+		g    Guarantee
+	}{
+		{"at_least_once", AtLeastOnce},
+		{"exactly_once", ExactlyOnce},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			testProcessor(t, test.g)
+		})
+	}
+}
+
+func testProcessor(t *testing.T, g Guarantee) {
+	const n = 100
+	in, out, group := randName(), randName(), randName()
+
+	adm, err := kgo.NewClient(seeds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer adm.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	create := kmsg.NewPtrCreateTopicsRequest()
+	for _, topic := range []string{in, out} {
+		ct := kmsg.NewCreateTopicsRequestTopic()
+		ct.Topic = topic
+		ct.NumPartitions = 2
+		ct.ReplicationFactor = 1
+		if rf, _ := strconv.Atoi(os.Getenv("KGO_TEST_RF")); rf > 0 {
+			ct.ReplicationFactor = int16(rf)
+		}
+		create.Topics = append(create.Topics, ct)
+	}
+	resp, err := create.RequestWith(ctx, adm)
+	if err == nil {
+		err = errors.Join(kerr.ErrorForCode(resp.Topics[0].ErrorCode), kerr.ErrorForCode(resp.Topics[1].ErrorCode))
+	}
+	if err != nil {
+		t.Skipf("unable to create topics, skipping: %v", err)
+	}
+
+	var rs []*kgo.Record
+	for i := range n {
+		rs = append(rs, &kgo.Record{Topic: in, Value: []byte(strconv.Itoa(i))})
+	}
+	if err := adm.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewBuilder()
+	b.Stream(in).Filter(isEven).Map(upper).To(out)
+
+	opts := []kgo.Opt{seeds(), kgo.ConsumerGroup(group), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart())}
+	if g == ExactlyOnce {
+		opts = append(opts, kgo.TransactionalID(group))
+	}
+	p, err := New(b, Config{Guarantee: g}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runCtx, runCancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- p.Run(runCtx) }()
+
+	consumer, err := kgo.NewClient(seeds(),
+		kgo.ConsumeTopics(out),
+		kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()),
+		kgo.FetchIsolationLevel(kgo.ReadCommitted()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	seen := make(map[string]bool)
+	for len(seen) < n/2 && ctx.Err() == nil {
+		select {
+		case err := <-done:
+			// Some test clusters (kfake) do not support transactions.
+			if g == ExactlyOnce && errors.Is(err, kerr.UnknownServerError) {
+				p.Close()
+				t.Skipf("unable to run transactions, skipping: %v", err)
+			}
+			t.Fatalf("Run returned early: %v", err)
+		default:
+		}
+		pollCtx, pollCancel := context.WithTimeout(ctx, time.Second)
+		fs := consumer.PollFetches(pollCtx)
+		pollCancel()
+		fs.EachRecord(func(r *kgo.Record) {
+			if !isEven(r) {
+				t.Errorf("unexpected odd output %s", r.Value)
+			}
+			if g == ExactlyOnce && seen[string(r.Value)] {
+				t.Errorf("duplicate output %s", r.Value)
+			}
+			seen[string(r.Value)] = true
+		})
+	}
+	if len(seen) != n/2 {
+		t.Errorf("got %d distinct outputs != exp %d", len(seen), n/2)
+	}
+
+	runCancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+	p.Close()
+}
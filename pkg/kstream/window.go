@@ -0,0 +1,434 @@
+package kstream
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+// Records emitted from a windowed aggregation have headers with the bounds of
+// their window, as unix milliseconds. The start is inclusive and the end is
+// exclusive.
+const (
+	WindowStartHeader = "kstream-window-start"
+	WindowEndHeader   = "kstream-window-end"
+)
+
+// TumblingWindow configures a windowed aggregation over fixed size,
+// non-overlapping windows of record timestamps.
+type TumblingWindow struct {
+	// Size is the size of every window and is required. Windows are
+	// aligned to the unix epoch.
+	Size time.Duration
+
+	// Grace is how long after a window ends that late records are still
+	// aggregated into it. Time is the largest record timestamp seen in a
+	// partition: once that passes a window's end plus Grace, the window
+	// is closed, dropped from memory, and deleted from the changelog, and
+	// later records for it are dropped.
+	Grace time.Duration
+
+	// Changelog is the topic that aggregates are written to and restored
+	// from, and is required. The topic should be compacted and must have
+	// the same number of partitions as the source topic: the aggregate for
+	// a record consumed from partition N is written to partition N.
+	Changelog string
+}
+
+// Aggregate returns a stream of aggregates over tumbling windows of record
+// timestamps, grouped by record key. For every record, fn is called with the
+// current aggregate for the record's key and window (nil for the first record
+// in a window) and returns the new aggregate. The new aggregate is written to
+// the window's changelog and emitted downstream as the record's value, with
+// WindowStartHeader and WindowEndHeader added to its headers.
+//
+// Aggregates are kept in memory per source partition. When a partition is
+// assigned, its aggregates are restored from the changelog before any record
+// is processed; when a partition is revoked, its aggregates are dropped.
+// With ExactlyOnce, changelog writes are part of each transaction, so a
+// restored aggregate always matches the committed source offsets, and
+// aggregates are rolled back if a transaction aborts. With
+// AtLeastOnce, records processed again after a failure are aggregated again.
+func (s *Stream) Aggregate(w TumblingWindow, fn func(agg []byte, r *kgo.Record) []byte) *Stream {
+	win := &window{
+		TumblingWindow: w,
+		topic:          s.topic,
+		agg:            fn,
+		parts:          make(map[int32]*windowPartition),
+	}
+	s.b.windows = append(s.b.windows, win)
+	return s.child(&Stream{window: win})
+}
+
+// Count returns a stream of counts over tumbling windows of record
+// timestamps, grouped by record key. Counts are encoded as base 10 strings.
+// See Aggregate for more details.
+func (s *Stream) Count(w TumblingWindow) *Stream {
+	return s.Aggregate(w, func(agg []byte, _ *kgo.Record) []byte {
+		n, _ := strconv.ParseInt(string(agg), 10, 64)
+		return strconv.AppendInt(nil, n+1, 10)
+	})
+}
+
+type window struct {
+	TumblingWindow
+	topic string // source topic
+	agg   func([]byte, *kgo.Record) []byte
+
+	mu    sync.Mutex
+	parts map[int32]*windowPartition
+	undo  map[int32]windowUndo // non-nil while tracking changes for a transaction
+}
+
+// windowUndo is how to undo the changes to a partition in a transaction.
+type windowUndo struct {
+	live  *windowPartition // the partition modified in the transaction
+	prior *windowPartition // a copy from before the transaction, nil if new
+}
+
+type windowPartition struct {
+	aggs    map[string][]byte // changelog key => aggregate
+	time    int64             // largest timestamp seen, in millis
+	nextEnd int64             // smallest window end in aggs, or 0 if none
+}
+
+func newWindowPartition() *windowPartition {
+	return &windowPartition{aggs: make(map[string][]byte)}
+}
+
+func (wp *windowPartition) clone() *windowPartition {
+	if wp == nil {
+		return nil
+	}
+	return &windowPartition{aggs: maps.Clone(wp.aggs), time: wp.time, nextEnd: wp.nextEnd}
+}
+
+// The changelog key is the window start in unix milliseconds, followed by the
+// record key.
+func changelogKey(start int64, key []byte) []byte {
+	return append(binary.BigEndian.AppendUint64(nil, uint64(start)), key...)
+}
+
+// add aggregates r, appending changelog records to out, and returns the
+// record to emit downstream, or nil if r is late and dropped.
+func (w *window) add(part int32, r *kgo.Record, out []*kgo.Record) (*kgo.Record, []*kgo.Record) {
+	size, grace := w.Size.Milliseconds(), w.Grace.Milliseconds()
+	ts := r.Timestamp.UnixMilli()
+	start := ts - ts%size
+	if ts < 0 && ts%size != 0 {
+		start -= size
+	}
+	end := start + size
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	wp := w.parts[part]
+	prior := wp
+	if wp == nil {
+		wp = newWindowPartition()
+		w.parts[part] = wp
+	}
+	if _, tracked := w.undo[part]; w.undo != nil && !tracked {
+		w.undo[part] = windowUndo{live: wp, prior: prior.clone()}
+	}
+
+	if end+grace <= wp.time {
+		return nil, out // window already closed
+	}
+	wp.time = max(wp.time, ts)
+	if wp.nextEnd == 0 || end < wp.nextEnd {
+		wp.nextEnd = end
+	}
+
+	key := changelogKey(start, r.Key)
+	agg := w.agg(wp.aggs[string(key)], r)
+	wp.aggs[string(key)] = agg
+	out = append(out, &kgo.Record{
+		Topic:     w.Changelog,
+		Partition: part,
+		Key:       key,
+		Value:     agg,
+		Timestamp: r.Timestamp,
+	})
+
+	// If time moved past the earliest window end, close every window that
+	// is done and delete it from the changelog.
+	if wp.nextEnd+grace <= wp.time {
+		wp.nextEnd = 0
+		for k := range wp.aggs {
+			kend := int64(binary.BigEndian.Uint64([]byte(k[:8]))) + size
+			if kend+grace <= wp.time {
+				delete(wp.aggs, k)
+				out = append(out, &kgo.Record{
+					Topic:     w.Changelog,
+					Partition: part,
+					Key:       []byte(k),
+					Timestamp: r.Timestamp,
+				})
+			} else if wp.nextEnd == 0 || kend < wp.nextEnd {
+				wp.nextEnd = kend
+			}
+		}
+	}
+
+	r.Value = agg
+	r.Headers = append(r.Headers,
+		kgo.RecordHeader{Key: WindowStartHeader, Value: strconv.AppendInt(nil, start, 10)},
+		kgo.RecordHeader{Key: WindowEndHeader, Value: strconv.AppendInt(nil, end, 10)},
+	)
+	return r, out
+}
+
+// restore replaces the aggregates of a partition with what is read from the
+// changelog.
+func (w *window) restore(part int32, rs []*kgo.Record) {
+	wp := newWindowPartition()
+	size := w.Size.Milliseconds()
+	for _, r := range rs {
+		if len(r.Key) < 8 {
+			continue
+		}
+		if r.Value == nil {
+			delete(wp.aggs, string(r.Key))
+			continue
+		}
+		wp.aggs[string(r.Key)] = r.Value
+	}
+	for k := range wp.aggs {
+		start := int64(binary.BigEndian.Uint64([]byte(k[:8])))
+		wp.time = max(wp.time, start) // conservative: never closes a window early
+		if end := start + size; wp.nextEnd == 0 || end < wp.nextEnd {
+			wp.nextEnd = end
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.parts[part] = wp
+}
+
+// track begins tracking changes so that they can be undone with rollback if
+// the transaction they are part of is aborted.
+func (w *window) track() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.undo = make(map[int32]windowUndo)
+}
+
+// rollback undoes every change since track. Partitions that were restored
+// or dropped since are left alone: they no longer hold the changes.
+func (w *window) rollback() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for part, u := range w.undo {
+		if w.parts[part] != u.live {
+			continue
+		}
+		if u.prior == nil {
+			delete(w.parts, part)
+		} else {
+			w.parts[part] = u.prior
+		}
+	}
+	w.undo = nil
+}
+
+// commit stops tracking changes, keeping them.
+func (w *window) commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.undo = nil
+}
+
+func (w *window) drop(part int32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.parts, part)
+}
+
+// changelogPartitioner writes changelog records to the partition they were
+// aggregated from, and partitions everything else with the wrapped
+// partitioner.
+type changelogPartitioner struct {
+	kgo.Partitioner
+	changelogs map[string]bool
+}
+
+func (p *changelogPartitioner) ForTopic(t string) kgo.TopicPartitioner {
+	if p.changelogs[t] {
+		return kgo.ManualPartitioner().ForTopic(t)
+	}
+	return p.Partitioner.ForTopic(t)
+}
+
+// windowOpts returns the client options that windowed aggregations require.
+func (p *Processor) windowOpts(cfg Config) ([]kgo.Opt, error) {
+	partitioner := &changelogPartitioner{
+		Partitioner: cfg.Partitioner,
+		changelogs:  make(map[string]bool),
+	}
+	if partitioner.Partitioner == nil {
+		partitioner.Partitioner = kgo.UniformBytesPartitioner(64<<10, true, true, nil)
+	}
+	for _, w := range p.b.windows {
+		if w.Size < time.Millisecond || w.Changelog == "" {
+			return nil, errors.New("kstream: windows require a Size of at least 1ms and a Changelog")
+		}
+		if partitioner.changelogs[w.Changelog] || p.b.sources[w.Changelog] != nil {
+			return nil, fmt.Errorf("kstream: changelog %s is used by multiple windows or is a source topic", w.Changelog)
+		}
+		partitioner.changelogs[w.Changelog] = true
+	}
+
+	drop := func(_ context.Context, _ *kgo.Client, revoked map[string][]int32) {
+		for _, w := range p.b.windows {
+			for _, part := range revoked[w.topic] {
+				w.drop(part)
+			}
+		}
+	}
+	return []kgo.Opt{
+		kgo.RecordPartitioner(partitioner),
+		kgo.OnPartitionsRestore(p.restore),
+		kgo.OnPartitionsRevoked(drop),
+		kgo.OnPartitionsLost(drop),
+	}, nil
+}
+
+// restore restores the aggregates of every newly assigned partition from
+// the changelogs.
+func (p *Processor) restore(ctx context.Context, _ *kgo.Client, assigned map[string]map[int32]kgo.Offset) error {
+	for _, w := range p.b.windows {
+		for part := range assigned[w.topic] {
+			rs, err := p.readChangelog(ctx, w.Changelog, part)
+			if err != nil {
+				return fmt.Errorf("kstream: unable to restore %s partition %d from changelog %s: %w", w.topic, part, w.Changelog, err)
+			}
+			w.restore(part, rs)
+			p.log(kgo.LogLevelInfo, "kstream restored window aggregates", "topic", w.topic, "partition", part, "changelog", w.Changelog, "records", len(rs))
+		}
+	}
+	return nil
+}
+
+// readChangelog reads a changelog partition from its start to its end (or, if
+// exactly once, its last stable offset) with the processor's own client.
+func (p *Processor) readChangelog(ctx context.Context, topic string, part int32) ([]*kgo.Record, error) {
+	isolation, level := kgo.ReadUncommitted(), int8(0)
+	if p.sess != nil {
+		isolation, level = kgo.ReadCommitted(), 1
+	}
+
+	mreq := kmsg.NewPtrMetadataRequest()
+	mt := kmsg.NewMetadataRequestTopic()
+	mt.Topic = kmsg.StringPtr(topic)
+	mreq.Topics = append(mreq.Topics, mt)
+	mresp, err := mreq.RequestWith(ctx, p.cl)
+	if err != nil {
+		return nil, err
+	}
+	if len(mresp.Topics) != 1 {
+		return nil, errors.New("metadata response missing the changelog topic")
+	}
+	mtopic := mresp.Topics[0]
+	if err := kerr.ErrorForCode(mtopic.ErrorCode); err != nil {
+		return nil, err
+	}
+	leader := int32(-1)
+	for _, mp := range mtopic.Partitions {
+		if mp.Partition == part {
+			if err := kerr.ErrorForCode(mp.ErrorCode); err != nil {
+				return nil, err
+			}
+			leader = mp.Leader
+		}
+	}
+	if leader < 0 {
+		return nil, errors.New("changelog partition does not exist or has no leader")
+	}
+
+	bounds := func(timestamp int64) (int64, error) {
+		lreq := kmsg.NewPtrListOffsetsRequest()
+		lreq.IsolationLevel = level
+		lt := kmsg.NewListOffsetsRequestTopic()
+		lt.Topic = topic
+		lp := kmsg.NewListOffsetsRequestTopicPartition()
+		lp.Partition = part
+		lp.Timestamp = timestamp
+		lt.Partitions = append(lt.Partitions, lp)
+		lreq.Topics = append(lreq.Topics, lt)
+		lresp, err := lreq.RequestWith(ctx, p.cl)
+		if err != nil {
+			return 0, err
+		}
+		if len(lresp.Topics) != 1 || len(lresp.Topics[0].Partitions) != 1 {
+			return 0, errors.New("list offsets response missing the changelog partition")
+		}
+		lrp := lresp.Topics[0].Partitions[0]
+		return lrp.Offset, kerr.ErrorForCode(lrp.ErrorCode)
+	}
+	at, err := bounds(-2)
+	if err != nil {
+		return nil, err
+	}
+	end, err := bounds(-1)
+	if err != nil {
+		return nil, err
+	}
+
+	var rs []*kgo.Record
+	for at < end {
+		freq := kmsg.NewPtrFetchRequest()
+		freq.MaxBytes = 50 << 20
+		freq.IsolationLevel = level
+		ft := kmsg.NewFetchRequestTopic()
+		ft.Topic = topic
+		ft.TopicID = mtopic.TopicID
+		fp := kmsg.NewFetchRequestTopicPartition()
+		fp.Partition = part
+		fp.FetchOffset = at
+		fp.PartitionMaxBytes = 10 << 20
+		ft.Partitions = append(ft.Partitions, fp)
+		freq.Topics = append(freq.Topics, ft)
+
+		kresp, err := p.cl.RequestRouted(ctx, kgo.RouteBroker(leader), freq)
+		if err != nil {
+			return nil, err
+		}
+		fresp := kresp.(*kmsg.FetchResponse)
+		if err := kerr.ErrorForCode(fresp.ErrorCode); err != nil {
+			return nil, err
+		}
+		if len(fresp.Topics) != 1 || len(fresp.Topics[0].Partitions) != 1 {
+			return nil, errors.New("fetch response missing the changelog partition")
+		}
+		rp := &fresp.Topics[0].Partitions[0]
+		if err := kerr.ErrorForCode(rp.ErrorCode); err != nil {
+			return nil, err
+		}
+		processed, next := kgo.ProcessFetchPartition(kgo.ProcessFetchPartitionOpts{
+			Offset:         at,
+			IsolationLevel: isolation,
+			Topic:          topic,
+			Partition:      part,
+		}, rp, kgo.DefaultDecompressor(), nil)
+		if processed.Err != nil {
+			return nil, processed.Err
+		}
+		if next <= at {
+			return nil, fmt.Errorf("no progress reading the changelog at offset %d before end offset %d", at, end)
+		}
+		rs = append(rs, processed.Records...)
+		at = next
+	}
+	return rs, nil
+}
@@ -0,0 +1,253 @@
+package kstream
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func header(r *kgo.Record, key string) string {
+	for _, h := range r.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+func TestWindowCount(t *testing.T) {
+	t.Parallel()
+
+	b := NewBuilder()
+	b.Stream("in").Count(TumblingWindow{Size: 10 * time.Second, Grace: 5 * time.Second, Changelog: "cl"}).To("out")
+	in, w := b.sources["in"], b.windows[0]
+
+	at := func(sec int64, key string) *kgo.Record {
+		return &kgo.Record{Topic: "in", Key: []byte(key), Timestamp: time.UnixMilli(sec * 1000)}
+	}
+	var out []*kgo.Record
+	for _, r := range []*kgo.Record{
+		at(1, "a"),  // window 0: a=1
+		at(2, "a"),  // window 0: a=2
+		at(3, "b"),  // window 0: b=1
+		at(12, "a"), // window 10: a=1
+		at(14, "a"), // window 10: a=2; late for window 0 is past 15
+		at(4, "a"),  // window 0: still open, a=3
+		at(21, "b"), // window 20: b=1; closes window 0
+		at(5, "a"),  // late, dropped
+	} {
+		out = in.process(0, copyRecord(r), out)
+	}
+
+	var (
+		outputs    []string
+		changelogs int
+		tombstones int
+	)
+	for _, r := range out {
+		switch r.Topic {
+		case "out":
+			outputs = append(outputs, string(r.Key)+"@"+header(r, WindowStartHeader)+"="+string(r.Value))
+		case "cl":
+			if r.Partition != 0 {
+				t.Errorf("changelog record written to partition %d != exp 0", r.Partition)
+			}
+			if r.Value == nil {
+				tombstones++
+			} else {
+				changelogs++
+			}
+		}
+	}
+	exp := []string{"a@0=1", "a@0=2", "b@0=1", "a@10000=1", "a@10000=2", "a@0=3", "b@20000=1"}
+	if len(outputs) != len(exp) {
+		t.Fatalf("got outputs %v != exp %v", outputs, exp)
+	}
+	for i := range exp {
+		if outputs[i] != exp[i] {
+			t.Errorf("output %d: got %s != exp %s", i, outputs[i], exp[i])
+		}
+	}
+	if changelogs != 7 || tombstones != 2 {
+		t.Errorf("got %d changelog updates and %d tombstones, exp 7 and 2", changelogs, tombstones)
+	}
+
+	// Restoring from the changelog resumes the open windows.
+	var cl []*kgo.Record
+	for _, r := range out {
+		if r.Topic == "cl" {
+			cl = append(cl, r)
+		}
+	}
+	w.drop(0)
+	w.restore(0, cl)
+	if n := len(w.parts[0].aggs); n != 2 {
+		t.Errorf("got %d restored aggregates != exp 2", n)
+	}
+	out = in.process(0, at(15, "a"), nil)
+	if got := string(out[1].Value); out[1].Topic != "out" || got != "3" {
+		t.Errorf("got restored count %s != exp 3", got)
+	}
+}
+
+func TestWindowRollback(t *testing.T) {
+	t.Parallel()
+
+	b := NewBuilder()
+	b.Stream("in").Count(TumblingWindow{Size: 10 * time.Second, Changelog: "cl"}).To("out")
+	in, w := b.sources["in"], b.windows[0]
+
+	at := func(part int32, sec int64) *kgo.Record {
+		return &kgo.Record{Topic: "in", Partition: part, Key: []byte("a"), Timestamp: time.UnixMilli(sec * 1000)}
+	}
+	count := func(out []*kgo.Record) string {
+		return string(out[len(out)-1].Value)
+	}
+
+	// The first transaction commits a count of 1 for partition 0.
+	w.track()
+	in.process(0, at(0, 1), nil)
+	w.commit()
+
+	// The second transaction aborts: partition 0 goes back to a count of
+	// 1, and partition 1, new in the transaction, is removed.
+	w.track()
+	in.process(0, at(0, 2), nil)
+	in.process(0, at(0, 12), nil)
+	in.process(1, at(1, 1), nil)
+	w.rollback()
+	if _, ok := w.parts[1]; ok {
+		t.Error("partition 1 still exists after rollback")
+	}
+
+	// Processing the aborted records again must not double count.
+	w.track()
+	if got := count(in.process(0, at(0, 2), nil)); got != "2" {
+		t.Errorf("got count %s after reprocessing != exp 2", got)
+	}
+	if got := count(in.process(1, at(1, 1), nil)); got != "1" {
+		t.Errorf("got partition 1 count %s after reprocessing != exp 1", got)
+	}
+
+	// A partition restored during the transaction (i.e., reassigned while
+	// ending it) is not rolled back over.
+	w.restore(1, nil)
+	w.rollback()
+	if got := len(w.parts[1].aggs); got != 0 {
+		t.Errorf("got %d partition 1 aggregates after restore and rollback != exp 0", got)
+	}
+	if got := count(in.process(0, at(0, 3), nil)); got != "2" {
+		t.Errorf("got count %s after second rollback != exp 2", got)
+	}
+}
+
+func TestWindowValidation(t *testing.T) {
+	t.Parallel()
+
+	for _, w := range []TumblingWindow{
+		{Changelog: "cl"},
+		{Size: time.Second},
+		{Size: time.Second, Changelog: "in"},
+	} {
+		b := NewBuilder()
+		b.Stream("in").Count(w)
+		if _, err := New(b, Config{}, seeds(), kgo.ConsumerGroup("g")); err == nil {
+			t.Errorf("%v: expected error", w)
+		}
+	}
+}
+
+func TestWindowRestore(t *testing.T) {
+	t.Parallel()
+
+	in, out, changelog, group := randName(), randName(), randName(), randName()
+
+	adm, err := kgo.NewClient(seeds(), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer adm.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	create := kmsg.NewPtrCreateTopicsRequest()
+	for _, topic := range []string{in, out, changelog} {
+		ct := kmsg.NewCreateTopicsRequestTopic()
+		ct.Topic = topic
+		ct.NumPartitions = 2
+		ct.ReplicationFactor = 1
+		if rf, _ := strconv.Atoi(os.Getenv("KGO_TEST_RF")); rf > 0 {
+			ct.ReplicationFactor = int16(rf)
+		}
+		create.Topics = append(create.Topics, ct)
+	}
+	resp, err := create.RequestWith(ctx, adm)
+	if err == nil {
+		for _, rt := range resp.Topics {
+			err = errors.Join(err, kerr.ErrorForCode(rt.ErrorCode))
+		}
+	}
+	if err != nil {
+		t.Skipf("unable to create topics, skipping: %v", err)
+	}
+
+	consumer, err := kgo.NewClient(seeds(), kgo.ConsumeTopics(out), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer consumer.Close()
+
+	// run produces n records for one key and window to both
+	// partitions, runs a processor until both partitions output count
+	// exp, and closes the processor.
+	now := time.Now()
+	run := func(n, exp int) {
+		var rs []*kgo.Record
+		for part := range int32(2) {
+			for range n {
+				rs = append(rs, &kgo.Record{Topic: in, Partition: part, Key: []byte("k"), Timestamp: now})
+			}
+		}
+		if err := adm.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+			t.Fatal(err)
+		}
+
+		b := NewBuilder()
+		b.Stream(in).Count(TumblingWindow{Size: time.Hour, Changelog: changelog}).To(out)
+		p, err := New(b, Config{}, seeds(), kgo.ConsumerGroup(group), kgo.ConsumeResetOffset(kgo.NewOffset().AtStart()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		runCtx, runCancel := context.WithCancel(ctx)
+		done := make(chan error, 1)
+		go func() { done <- p.Run(runCtx) }()
+
+		// Outputs use the default partitioner, so we track the
+		// highest count seen rather than outputs per partition.
+		counts := make(map[string]int)
+		for counts[strconv.Itoa(exp)] < 2 && ctx.Err() == nil {
+			consumer.PollFetches(ctx).EachRecord(func(r *kgo.Record) {
+				counts[string(r.Value)]++
+			})
+		}
+		if ctx.Err() != nil {
+			t.Fatalf("timed out waiting for counts of %d, have %v", exp, counts)
+		}
+		runCancel()
+		if err := <-done; !errors.Is(err, context.Canceled) {
+			t.Errorf("got Run err %v, exp context.Canceled", err)
+		}
+		p.Close()
+	}
+
+	run(3, 3)
+	run(2, 5) // restored from the changelog
+}
@@ -0,0 +1,338 @@
+// Package kworker runs one worker goroutine per assigned partition on top of a
+// group consuming kgo.Client, committing only offsets that have been fully
+// processed.
+//
+// This is the pattern most group consumers end up building around
+// PollFetches: records are polled in one goroutine, handed off to a long
+// lived worker per partition, processed in order, and marked for commit once
+// a worker finishes with them. When partitions are revoked, their workers are
+// drained and the completed offsets are committed before the partitions are
+// given to another member.
+//
+//	p, err := kworker.New(kworker.Config{
+//	        Handler: func(ctx context.Context, tp kgo.TopicPartition, rs []*kgo.Record) error {
+//	                return process(ctx, rs)
+//	        },
+//	}, kgo.SeedBrokers("localhost:9092"), kgo.ConsumerGroup("g"), kgo.ConsumeTopics("t"))
+//	if err != nil {
+//	        return err
+//	}
+//	defer p.Close()
+//	return p.Run(ctx)
+//
+// The pool owns a few client options: AutoCommitMarks, BlockRebalanceOnPoll,
+// OnPartitionsRevoked, and OnPartitionsLost are always set and override any
+// values passed to New.
+package kworker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// Config configures a Pool.
+type Config struct {
+	// Handler processes records for a single partition and is required.
+	// Records are passed in offset order, and Handler is never called
+	// concurrently for the same partition. The context is canceled if the
+	// partition is lost or if Run returns.
+	//
+	// If Handler returns nil, the last record in rs is marked for commit.
+	Handler func(ctx context.Context, tp kgo.TopicPartition, rs []*kgo.Record) error
+
+	// OnError, if non-nil, is called when Handler returns an error. If
+	// OnError returns nil, the same records are retried after RetryBackoff.
+	// If OnError returns an error, Run stops and returns that error.
+	//
+	// If OnError is nil, failed records are retried until they succeed or
+	// the partition is revoked.
+	OnError func(tp kgo.TopicPartition, rs []*kgo.Record, err error) error
+
+	// RetryBackoff is how long to wait before retrying records that failed
+	// processing, overriding the default 1s.
+	RetryBackoff time.Duration
+
+	// QueueBatches is how many polled batches can be queued for a single
+	// partition before polling blocks, overriding the default 4.
+	//
+	// While polling is blocked, rebalances are blocked as well, so the
+	// time to process a full queue should stay well within the group's
+	// rebalance timeout.
+	QueueBatches int
+}
+
+// Pool feeds records from a group consuming client to per-partition workers.
+type Pool struct {
+	cfg Config
+	cl  *kgo.Client
+
+	running atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelCauseFunc
+
+	mu      sync.Mutex
+	workers map[kgo.TopicPartition]*worker
+}
+
+type worker struct {
+	p      *Pool
+	tp     kgo.TopicPartition
+	in     chan []*kgo.Record
+	quit   chan struct{} // closed on revoke
+	done   chan struct{} // closed when run exits
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	watermark atomic.Int64
+}
+
+// New returns a new pool using a client created from opts, which must
+// configure a consumer group.
+func New(cfg Config, opts ...kgo.Opt) (*Pool, error) {
+	if cfg.Handler == nil {
+		return nil, errors.New("kworker: missing Handler")
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = time.Second
+	}
+	if cfg.QueueBatches <= 0 {
+		cfg.QueueBatches = 4
+	}
+
+	p := &Pool{
+		cfg:     cfg,
+		workers: make(map[kgo.TopicPartition]*worker),
+	}
+	opts = append(opts[:len(opts):len(opts)],
+		kgo.AutoCommitMarks(),
+		kgo.BlockRebalanceOnPoll(),
+		kgo.OnPartitionsRevoked(func(ctx context.Context, cl *kgo.Client, revoked map[string][]int32) {
+			p.revoke(ctx, cl, revoked, false)
+		}),
+		kgo.OnPartitionsLost(func(ctx context.Context, cl *kgo.Client, lost map[string][]int32) {
+			p.revoke(ctx, cl, lost, true)
+		}),
+	)
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if g, _ := cl.OptValue(kgo.ConsumerGroup).(string); g == "" {
+		cl.Close()
+		return nil, errors.New("kworker: the client must be configured with a consumer group")
+	}
+	p.cl = cl
+	return p, nil
+}
+
+// Client returns the pool's underlying client.
+func (p *Pool) Client() *kgo.Client { return p.cl }
+
+// Close closes the underlying client, which commits all completed offsets
+// and leaves the group. This must be called after Run returns.
+func (p *Pool) Close() { p.cl.Close() }
+
+// Watermarks returns the next offset to process for every partition that
+// currently has a worker, which is also the offset that will be committed for
+// the partition. Partitions that have not finished processing any record are
+// not included.
+func (p *Pool) Watermarks() map[kgo.TopicPartition]int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	m := make(map[kgo.TopicPartition]int64, len(p.workers))
+	for tp, w := range p.workers {
+		if at := w.watermark.Load(); at >= 0 {
+			m[tp] = at
+		}
+	}
+	return m
+}
+
+// Run polls and feeds workers until the context is canceled, the client is
+// closed, or OnError returns an error. Before returning, Run cancels and waits
+// for all workers; any offsets they completed are committed when the pool is
+// closed.
+//
+// Run returns the context error, kgo.ErrClientClosed, or the error from
+// OnError. Run can only be called once.
+func (p *Pool) Run(ctx context.Context) error {
+	if !p.running.CompareAndSwap(false, true) {
+		return errors.New("kworker: Run called more than once")
+	}
+	p.ctx, p.cancel = context.WithCancelCause(ctx)
+	defer p.stopAll()
+
+	for {
+		fs := p.cl.PollFetches(p.ctx)
+		if fs.IsClientClosed() {
+			return kgo.ErrClientClosed
+		}
+		if p.ctx.Err() != nil {
+			return context.Cause(p.ctx)
+		}
+		fs.EachError(func(t string, part int32, err error) {
+			if !errors.Is(err, context.Canceled) {
+				p.log(kgo.LogLevelWarn, "kworker skipping fetch error", "topic", t, "partition", part, "err", err)
+			}
+		})
+		fs.EachPartition(func(ftp kgo.FetchTopicPartition) {
+			if len(ftp.Records) == 0 || p.ctx.Err() != nil {
+				return
+			}
+			w := p.worker(kgo.TopicPartition{Topic: ftp.Topic, Partition: ftp.Partition})
+			select {
+			case w.in <- ftp.Records:
+			case <-w.done:
+			case <-p.ctx.Done():
+			}
+		})
+		p.cl.AllowRebalance()
+	}
+}
+
+func (p *Pool) log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if l, _ := p.cl.OptValue(kgo.WithLogger).(kgo.Logger); l != nil && l.Level() >= level {
+		l.Log(level, msg, keyvals...)
+	}
+}
+
+func (p *Pool) worker(tp kgo.TopicPartition) *worker {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if w := p.workers[tp]; w != nil {
+		return w
+	}
+	w := &worker{
+		p:    p,
+		tp:   tp,
+		in:   make(chan []*kgo.Record, p.cfg.QueueBatches),
+		quit: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	w.ctx, w.cancel = context.WithCancel(p.ctx)
+	w.watermark.Store(-1)
+	p.workers[tp] = w
+	go w.run()
+	return w
+}
+
+// revoke drains the workers for revoked partitions and then commits what they
+// completed. Lost partitions cannot be committed, so their workers are
+// canceled rather than drained.
+func (p *Pool) revoke(ctx context.Context, cl *kgo.Client, m map[string][]int32, lost bool) {
+	p.mu.Lock()
+	var ws []*worker
+	for t, ps := range m {
+		for _, part := range ps {
+			tp := kgo.TopicPartition{Topic: t, Partition: part}
+			if w := p.workers[tp]; w != nil {
+				ws = append(ws, w)
+				delete(p.workers, tp)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	for _, w := range ws {
+		if lost {
+			w.cancel()
+		}
+		close(w.quit)
+	}
+	for _, w := range ws {
+		<-w.done
+		w.cancel()
+	}
+	if lost {
+		return
+	}
+	if err := cl.CommitMarkedOffsets(ctx); err != nil {
+		p.log(kgo.LogLevelError, "kworker unable to commit completed offsets on revoke", "err", err)
+	}
+}
+
+func (p *Pool) stopAll() {
+	p.mu.Lock()
+	ws := p.workers
+	p.workers = make(map[kgo.TopicPartition]*worker)
+	p.mu.Unlock()
+
+	for _, w := range ws {
+		w.cancel()
+	}
+	for _, w := range ws {
+		<-w.done
+	}
+	p.cancel(nil)
+}
+
+func (w *worker) run() {
+	defer close(w.done)
+	for {
+		select {
+		case rs := <-w.in:
+			if !w.process(rs) {
+				return
+			}
+		case <-w.ctx.Done():
+			return
+		case <-w.quit:
+			// We finish everything that was already queued
+			// before the partition is handed off.
+			for {
+				select {
+				case rs := <-w.in:
+					if !w.process(rs) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// process runs the handler until it succeeds, returning false if the
+// records could not be processed and the worker must stop.
+func (w *worker) process(rs []*kgo.Record) bool {
+	for {
+		if w.ctx.Err() != nil {
+			return false
+		}
+		err := w.p.cfg.Handler(w.ctx, w.tp, rs)
+		if err == nil {
+			last := rs[len(rs)-1]
+			w.p.cl.MarkCommitRecords(last)
+			w.watermark.Store(last.Offset + 1)
+			return true
+		}
+		if w.p.cfg.OnError != nil {
+			if ferr := w.p.cfg.OnError(w.tp, rs, err); ferr != nil {
+				w.p.cancel(ferr)
+				return false
+			}
+		}
+		w.p.log(kgo.LogLevelWarn, "kworker handler failed, retrying after backoff",
+			"topic", w.tp.Topic,
+			"partition", w.tp.Partition,
+			"first_offset", rs[0].Offset,
+			"err", err,
+		)
+
+		// If the partition is being revoked, we do not retry: the
+		// records are left uncommitted for the next owner.
+		select {
+		case <-w.quit:
+			return false
+		case <-w.ctx.Done():
+			return false
+		case <-time.After(w.p.cfg.RetryBackoff):
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package kworker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kerr"
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/kmsg"
+)
+
+func seeds() kgo.Opt {
+	s := os.Getenv("KGO_SEEDS")
+	if s == "" {
+		s = "127.0.0.1:9092"
+	}
+	return kgo.SeedBrokers(strings.Split(s, ",")...)
+}
+
+func randName() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+func TestPool(t *testing.T) {
+	t.Parallel()
+
+	const (
+		partitions = 3
+		perPart    = 100
+	)
+	topic, group := randName(), randName()
+
+	adm, err := kgo.NewClient(seeds())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer adm.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+
+	create := kmsg.NewPtrCreateTopicsRequest()
+	ct := kmsg.NewCreateTopicsRequestTopic()
+	ct.Topic = topic
+	ct.NumPartitions = partitions
+	ct.ReplicationFactor = 1
+	if rf, _ := strconv.Atoi(os.Getenv("KGO_TEST_RF")); rf > 0 {
+		ct.ReplicationFactor = int16(rf)
+	}
+	create.Topics = append(create.Topics, ct)
+	resp, err := create.RequestWith(ctx, adm)
+	if err == nil {
+		err = kerr.ErrorForCode(resp.Topics[0].ErrorCode)
+	}
+	if err != nil {
+		t.Skipf("unable to create topic, skipping: %v", err)
+	}
+
+	var rs []*kgo.Record
+	for p := range int32(partitions) {
+		for i := range perPart {
+			rs = append(rs, &kgo.Record{Topic: topic, Partition: p, Value: []byte(strconv.Itoa(i))})
+		}
+	}
+	prod, err := kgo.NewClient(seeds(), kgo.RecordPartitioner(kgo.ManualPartitioner()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer prod.Close()
+	if err := prod.ProduceSync(ctx, rs...).FirstErr(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu     sync.Mutex
+		seen   = make(map[kgo.TopicPartition]int)
+		total  int
+		failed bool
+		errs   int
+	)
+	runCtx, runCancel := context.WithCancel(ctx)
+	defer runCancel()
+	p, err := New(Config{
+		Handler: func(_ context.Context, tp kgo.TopicPartition, rs []*kgo.Record) error {
+			mu.Lock()
+			defer mu.Unlock()
+			// Fail the first batch for partition 1 once, which
+			// must be retried without skipping records.
+			if tp.Partition == 1 && !failed {
+				failed = true
+				return errors.New("injected")
+			}
+			for _, r := range rs {
+				if exp := strconv.Itoa(seen[tp]); string(r.Value) != exp {
+					t.Errorf("%v: got out of order value %s != exp %s", tp, r.Value, exp)
+				}
+				seen[tp]++
+				total++
+			}
+			if total == partitions*perPart {
+				runCancel()
+			}
+			return nil
+		},
+		OnError: func(kgo.TopicPartition, []*kgo.Record, error) error {
+			mu.Lock()
+			defer mu.Unlock()
+			errs++
+			return nil
+		},
+		RetryBackoff: 10 * time.Millisecond,
+	}, seeds(), kgo.ConsumerGroup(group), kgo.ConsumeTopics(topic))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := p.Run(runCtx); !errors.Is(err, context.Canceled) {
+		t.Errorf("got Run err %v, exp context.Canceled", err)
+	}
+	if err := p.Run(runCtx); err == nil {
+		t.Error("expected error calling Run twice")
+	}
+	p.Close()
+
+	if total != partitions*perPart || errs != 1 {
+		t.Errorf("got %d total records and %d errors, exp %d and 1", total, errs, partitions*perPart)
+	}
+
+	fetch := kmsg.NewPtrOffsetFetchRequest()
+	fetch.Group = group
+	fresp, err := fetch.RequestWith(ctx, adm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	committed := make(map[int32]int64)
+	for _, rt := range fresp.Topics {
+		for _, rp := range rt.Partitions {
+			committed[rp.Partition] = rp.Offset
+		}
+	}
+	for p := range int32(partitions) {
+		if committed[p] != perPart {
+			t.Errorf("partition %d: got committed offset %d != exp %d", p, committed[p], perPart)
+		}
+	}
+}
+
+func TestNewValidation(t *testing.T) {
+	t.Parallel()
+
+	if _, err := New(Config{}); err == nil {
+		t.Error("expected error with no Handler")
+	}
+	noop := func(context.Context, kgo.TopicPartition, []*kgo.Record) error { return nil }
+	if _, err := New(Config{Handler: noop}, kgo.ConsumeTopics("t")); err == nil {
+		t.Error("expected error with no consumer group")
+	}
+}
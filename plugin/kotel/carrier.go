@@ -51,3 +51,32 @@ func (c RecordCarrier) Keys() []string {
 	}
 	return out
 }
+
+// renamedCarrier wraps a RecordCarrier, mapping propagation keys to
+// different header names.
+type renamedCarrier struct {
+	RecordCarrier
+	names map[string]string // propagation key => header name
+}
+
+func (c renamedCarrier) header(key string) string {
+	if name, ok := c.names[key]; ok {
+		return name
+	}
+	return key
+}
+
+func (c renamedCarrier) Get(key string) string { return c.RecordCarrier.Get(c.header(key)) }
+func (c renamedCarrier) Set(key, val string)   { c.RecordCarrier.Set(c.header(key), val) }
+func (c renamedCarrier) Keys() []string {
+	keys := c.RecordCarrier.Keys()
+	for i, k := range keys {
+		for key, name := range c.names {
+			if name == k {
+				keys[i] = key
+				break
+			}
+		}
+	}
+	return keys
+}
@@ -29,6 +29,8 @@ type Tracer struct {
 	consumerGroup  string
 	keyFormatter   func(*kgo.Record) (string, error)
 	linkSpans      bool
+	headerNames    map[string]string
+	sampleTopic    func(string) bool
 }
 
 // TracerOpt interface used for setting optional config properties.
@@ -52,6 +54,37 @@ func TracerPropagator(propagator propagation.TextMapPropagator) TracerOpt {
 	return tracerOptFunc(func(t *Tracer) { t.propagators = propagator })
 }
 
+// TraceContextPropagation uses the W3C Trace Context and W3C Baggage
+// propagators to inject and extract the traceparent, tracestate, and baggage
+// record headers, overriding the global propagator. This is useful if your
+// application does not otherwise configure a global propagator, which by
+// default propagates nothing.
+func TraceContextPropagation() TracerOpt {
+	return tracerOptFunc(func(t *Tracer) {
+		t.propagators = propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		)
+	})
+}
+
+// HeaderNames maps propagation keys (i.e. "traceparent", "tracestate", or
+// "baggage") to the record header names to use for them. Keys not in the map
+// use their propagation key as the header name. This can be used to interop
+// with systems that propagate context under different header names.
+func HeaderNames(names map[string]string) TracerOpt {
+	return tracerOptFunc(func(t *Tracer) { t.headerNames = names })
+}
+
+// TopicSampler sets a function that returns whether records for a topic
+// should be traced. If the function returns false, no publish or receive
+// spans are started for records in the topic, but context is still injected
+// into produced records and extracted from consumed records, such that traces
+// are not broken for downstream services. By default, all topics are traced.
+func TopicSampler(fn func(topic string) bool) TracerOpt {
+	return tracerOptFunc(func(t *Tracer) { t.sampleTopic = fn })
+}
+
 // ClientID sets the optional client_id attribute value.
 func ClientID(id string) TracerOpt {
 	return tracerOptFunc(func(t *Tracer) { t.clientID = id })
@@ -99,6 +132,33 @@ func NewTracer(opts ...TracerOpt) *Tracer {
 	return t
 }
 
+func (t *Tracer) carrier(r *kgo.Record) propagation.TextMapCarrier {
+	c := NewRecordCarrier(r)
+	if len(t.headerNames) == 0 {
+		return c
+	}
+	return renamedCarrier{c, t.headerNames}
+}
+
+func (t *Tracer) sampled(topic string) bool {
+	return t.sampleTopic == nil || t.sampleTopic(topic)
+}
+
+// Inject injects the span context and baggage in ctx into the record's
+// headers. The record hooks do this automatically; this is only needed if you
+// produce records without the hooks, or want to propagate a different context
+// than the record's.
+func (t *Tracer) Inject(ctx context.Context, r *kgo.Record) {
+	t.propagators.Inject(ctx, t.carrier(r))
+}
+
+// Extract returns a copy of ctx with the span context and baggage extracted
+// from the record's headers. The record hooks do this automatically; this is
+// only needed if you consume records without the hooks.
+func (t *Tracer) Extract(ctx context.Context, r *kgo.Record) context.Context {
+	return t.propagators.Extract(ctx, t.carrier(r))
+}
+
 func (t *Tracer) maybeKeyAttr(attrs []attribute.KeyValue, r *kgo.Record) []attribute.KeyValue {
 	if r.Key == nil {
 		return attrs
@@ -177,6 +237,13 @@ func (t *Tracer) WithProcessSpan(r *kgo.Record) (context.Context, trace.Span) {
 // the record's context, so it can be ended in the OnProduceRecordUnbuffered
 // hook.
 func (t *Tracer) OnProduceRecordBuffered(r *kgo.Record) {
+	if r.Context == nil {
+		r.Context = context.Background()
+	}
+	if !t.sampled(r.Topic) {
+		t.Inject(r.Context, r)
+		return
+	}
 	// Set up span options.
 	attrs := []attribute.KeyValue{
 		semconv.MessagingSystemKey.String("kafka"),
@@ -198,7 +265,7 @@ func (t *Tracer) OnProduceRecordBuffered(r *kgo.Record) {
 	// Start the "publish" span.
 	ctx, _ := t.tracer.Start(r.Context, r.Topic+" publish", opts...)
 	// Inject the span context into the record.
-	t.propagators.Inject(ctx, NewRecordCarrier(r))
+	t.Inject(ctx, r)
 	// Update the record context.
 	r.Context = ctx
 }
@@ -209,6 +276,9 @@ func (t *Tracer) OnProduceRecordBuffered(r *kgo.Record) {
 // It sets attributes with values unset when producing and records any error
 // that occurred during the publish operation.
 func (t *Tracer) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
+	if !t.sampled(r.Topic) {
+		return
+	}
 	span := trace.SpanFromContext(r.Context)
 	defer span.End()
 	span.SetAttributes(
@@ -228,6 +298,13 @@ func (t *Tracer) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
 // OnFetchRecordUnbuffered hook and can be used in downstream consumer
 // processing.
 func (t *Tracer) OnFetchRecordBuffered(r *kgo.Record) {
+	if r.Context == nil {
+		r.Context = context.Background()
+	}
+	if !t.sampled(r.Topic) {
+		r.Context = t.Extract(r.Context, r)
+		return
+	}
 	// Set up the span options.
 	attrs := []attribute.KeyValue{
 		semconv.MessagingSystemKey.String("kafka"),
@@ -251,11 +328,8 @@ func (t *Tracer) OnFetchRecordBuffered(r *kgo.Record) {
 		trace.WithSpanKind(trace.SpanKindConsumer),
 	}
 
-	if r.Context == nil {
-		r.Context = context.Background()
-	}
 	// Extract the span context from the record.
-	ctx := t.propagators.Extract(r.Context, NewRecordCarrier(r))
+	ctx := t.Extract(r.Context, r)
 
 	if t.linkSpans {
 		opts = append(opts, trace.WithNewRoot())
@@ -273,6 +347,9 @@ func (t *Tracer) OnFetchRecordBuffered(r *kgo.Record) {
 // OnFetchRecordUnbuffered continues and ends the "receive" span for an
 // unbuffered record.
 func (t *Tracer) OnFetchRecordUnbuffered(r *kgo.Record, _ bool) {
+	if !t.sampled(r.Topic) {
+		return
+	}
 	span := trace.SpanFromContext(r.Context)
 	defer span.End()
 }
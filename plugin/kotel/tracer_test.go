@@ -1,13 +1,17 @@
 package kotel
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/propagation"
 	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
 	"go.opentelemetry.io/otel/trace"
+
+	"github.com/twmb/franz-go/pkg/kgo"
 )
 
 func TestNewTracer(t *testing.T) {
@@ -59,3 +63,38 @@ func TestNewTracer(t *testing.T) {
 		})
 	}
 }
+
+func TestTracerPropagation(t *testing.T) {
+	tracer := NewTracer(
+		TraceContextPropagation(),
+		HeaderNames(map[string]string{"traceparent": "x-traceparent"}),
+		TopicSampler(func(topic string) bool { return topic != "unsampled" }),
+	)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	member, _ := baggage.NewMember("tenant", "t1")
+	bag, _ := baggage.New(member)
+	ctx := baggage.ContextWithBaggage(trace.ContextWithSpanContext(context.Background(), sc), bag)
+
+	for _, topic := range []string{"sampled", "unsampled"} {
+		produced := &kgo.Record{Topic: topic, Context: ctx}
+		tracer.OnProduceRecordBuffered(produced)
+		tracer.OnProduceRecordUnbuffered(produced, nil)
+
+		var keys []string
+		for _, h := range produced.Headers {
+			keys = append(keys, h.Key)
+		}
+		assert.ElementsMatch(t, []string{"x-traceparent", "baggage"}, keys, topic)
+
+		consumed := &kgo.Record{Topic: topic, Headers: produced.Headers}
+		tracer.OnFetchRecordBuffered(consumed)
+		assert.Equal(t, sc.TraceID(), trace.SpanContextFromContext(consumed.Context).TraceID(), topic)
+		assert.Equal(t, "t1", baggage.FromContext(consumed.Context).Member("tenant").Value(), topic)
+		tracer.OnFetchRecordUnbuffered(consumed, true)
+	}
+}
@@ -1,8 +1,10 @@
 package kprom
 
 import (
+	"context"
 	"maps"
 	"reflect"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
@@ -20,6 +22,8 @@ type cfg struct {
 	withConstLabels  prometheus.Labels
 	histograms       map[Histogram][]float64
 	defBuckets       []float64
+	nativeHistograms *NativeHistogramOpts
+	exemplars        func(context.Context) prometheus.Labels
 	fetchProduceOpts fetchProduceOpts
 
 	handlerOpts  promhttp.HandlerOpts
@@ -148,8 +152,75 @@ const (
 	WriteTime                           // Enables {ns}_{ss}_write_time_seconds.
 	RequestDurationE2E                  // Enables {ns}_{ss}_request_durationE2E_seconds.
 	RequestThrottled                    // Enables {ns}_{ss}_request_throttled_seconds.
+
+	// The record latency histograms additionally require the hooks from
+	// Metrics.RecordLatencyHooks.
+
+	ProduceRecordLatency // Enables {ns}_{ss}_produce_record_latency_seconds{topic}, the time from a record's timestamp to when it is acknowledged.
+	ConsumeRecordLatency // Enables {ns}_{ss}_consume_record_latency_seconds{topic}, the time from a record's timestamp to when it is polled.
 )
 
+// NativeHistogramOpts configures Prometheus native histograms. The fields
+// correspond to the prometheus.HistogramOpts fields of the same name prefixed
+// with NativeHistogram.
+type NativeHistogramOpts struct {
+	// BucketFactor is the growth factor between native buckets, which
+	// must be greater than one. If zero, this defaults to 1.1.
+	BucketFactor float64
+
+	// MaxBucketNumber is the maximum number of native buckets. If zero,
+	// this defaults to 160.
+	MaxBucketNumber uint32
+
+	// MinResetDuration is the minimum duration before a histogram with
+	// more than MaxBucketNumber buckets is reset. If zero, this defaults
+	// to one hour.
+	MinResetDuration time.Duration
+
+	// NoClassicBuckets drops the classic buckets, exposing only native
+	// buckets. By default, both are exposed, which allows migrating
+	// dashboards and scrapers that do not yet support native histograms.
+	NoClassicBuckets bool
+}
+
+// NativeHistograms enables Prometheus native histograms for all enabled
+// histograms. Native histograms are only ingested by a Prometheus server
+// with native histograms enabled, scraping with the protobuf format.
+func NativeHistograms(opts NativeHistogramOpts) Opt {
+	if opts.BucketFactor == 0 {
+		opts.BucketFactor = 1.1
+	}
+	if opts.MaxBucketNumber == 0 {
+		opts.MaxBucketNumber = 160
+	}
+	if opts.MinResetDuration == 0 {
+		opts.MinResetDuration = time.Hour
+	}
+	return opt{func(c *cfg) { c.nativeHistograms = &opts }}
+}
+
+// WithExemplars attaches OpenMetrics exemplars to observations in the
+// ProduceRecordLatency and ConsumeRecordLatency histograms. The function is
+// called with each record's context, which is where tracing plugins such as
+// kotel store a record's span, and returns the exemplar labels to attach, or
+// nil to attach none. For example, with OpenTelemetry:
+//
+//	kprom.WithExemplars(func(ctx context.Context) prometheus.Labels {
+//		sc := trace.SpanContextFromContext(ctx)
+//		if !sc.IsSampled() {
+//			return nil
+//		}
+//		return prometheus.Labels{"trace_id": sc.TraceID().String()}
+//	})
+//
+// Exemplars are only exposed when scraping with the OpenMetrics format, which
+// requires enabling EnableOpenMetrics in HandlerOpts if you use Handler. The
+// broker level histograms are not associated with any record and never have
+// exemplars.
+func WithExemplars(fn func(context.Context) prometheus.Labels) Opt {
+	return opt{func(c *cfg) { c.exemplars = fn }}
+}
+
 // HistogramOpts allows histograms to be enabled with custom buckets
 type HistogramOpts struct {
 	Enable  Histogram
@@ -37,6 +37,7 @@
 package kprom
 
 import (
+	"context"
 	"net"
 	"net/http"
 	"time"
@@ -59,6 +60,9 @@ var ( // interface checks to ensure we implement the hooks properly
 	_ kgo.HookBrokerThrottle      = new(Metrics)
 	_ kgo.HookNewClient           = new(Metrics)
 	_ kgo.HookClientClosed        = new(Metrics)
+
+	_ kgo.HookProduceRecordUnbuffered = produceLatencyHook{}
+	_ kgo.HookFetchRecordUnbuffered   = consumeLatencyHook{}
 )
 
 // Metrics provides prometheus metrics
@@ -86,6 +90,10 @@ type Metrics struct {
 	requestDurationE2ESeconds *prometheus.HistogramVec
 	requestThrottledSeconds   *prometheus.HistogramVec
 
+	// Record latency
+	produceRecordLatencySeconds *prometheus.HistogramVec
+	consumeRecordLatencySeconds *prometheus.HistogramVec
+
 	// Produce
 	produceCompressedBytes   *prometheus.CounterVec
 	produceUncompressedBytes *prometheus.CounterVec
@@ -144,12 +152,22 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		constLabels["client_id"] = client.OptValue(kgo.ClientID).(string)
 	}
 
-	// returns Hist buckets if set, otherwise defBucket
-	getHistogramBuckets := func(h Histogram) []float64 {
+	// sets Hist buckets if set, otherwise defBucket, and native
+	// histogram options if enabled
+	histogramOpts := func(h Histogram, opts prometheus.HistogramOpts) prometheus.HistogramOpts {
+		opts.Buckets = m.cfg.defBuckets
 		if buckets, ok := m.cfg.histograms[h]; ok && len(buckets) != 0 {
-			return buckets
+			opts.Buckets = buckets
+		}
+		if native := m.cfg.nativeHistograms; native != nil {
+			opts.NativeHistogramBucketFactor = native.BucketFactor
+			opts.NativeHistogramMaxBucketNumber = native.MaxBucketNumber
+			opts.NativeHistogramMinResetDuration = native.MinResetDuration
+			if native.NoClassicBuckets {
+				opts.Buckets = nil
+			}
 		}
-		return m.cfg.defBuckets
+		return opts
 	}
 
 	// Connection
@@ -196,23 +214,21 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Help:        "Total number of write errors",
 	}, []string{"node_id"})
 
-	m.writeWaitSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.writeWaitSeconds = factory.NewHistogramVec(histogramOpts(WriteWait, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "write_wait_seconds",
 		Help:        "Time spent waiting to write to Kafka",
-		Buckets:     getHistogramBuckets(WriteWait),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
 
-	m.writeTimeSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.writeTimeSeconds = factory.NewHistogramVec(histogramOpts(WriteTime, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "write_time_seconds",
 		Help:        "Time spent writing to Kafka",
-		Buckets:     getHistogramBuckets(WriteTime),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
 
 	// Read
 
@@ -232,43 +248,57 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		Help:        "Total number of read errors",
 	}, []string{"node_id"})
 
-	m.readWaitSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.readWaitSeconds = factory.NewHistogramVec(histogramOpts(ReadWait, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "read_wait_seconds",
 		Help:        "Time spent waiting to read from Kafka",
-		Buckets:     getHistogramBuckets(ReadWait),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
 
-	m.readTimeSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.readTimeSeconds = factory.NewHistogramVec(histogramOpts(ReadTime, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "read_time_seconds",
 		Help:        "Time spent reading from Kafka",
-		Buckets:     getHistogramBuckets(ReadTime),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
 
 	// Request E2E duration & Throttle
 
-	m.requestDurationE2ESeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.requestDurationE2ESeconds = factory.NewHistogramVec(histogramOpts(RequestDurationE2E, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "request_duration_e2e_seconds",
 		Help:        "Time from the start of when a request is written to the end of when the response for that request was fully read",
-		Buckets:     getHistogramBuckets(RequestDurationE2E),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
 
-	m.requestThrottledSeconds = factory.NewHistogramVec(prometheus.HistogramOpts{
+	m.requestThrottledSeconds = factory.NewHistogramVec(histogramOpts(RequestThrottled, prometheus.HistogramOpts{
 		Namespace:   namespace,
 		Subsystem:   subsystem,
 		ConstLabels: constLabels,
 		Name:        "request_throttled_seconds",
 		Help:        "Time the request was throttled",
-		Buckets:     getHistogramBuckets(RequestThrottled),
-	}, []string{"node_id"})
+	}), []string{"node_id"})
+
+	// Record latency
+
+	m.produceRecordLatencySeconds = factory.NewHistogramVec(histogramOpts(ProduceRecordLatency, prometheus.HistogramOpts{
+		Namespace:   namespace,
+		Subsystem:   subsystem,
+		ConstLabels: constLabels,
+		Name:        "produce_record_latency_seconds",
+		Help:        "Time from when a record was produced to when it was acknowledged",
+	}), []string{"topic"})
+
+	m.consumeRecordLatencySeconds = factory.NewHistogramVec(histogramOpts(ConsumeRecordLatency, prometheus.HistogramOpts{
+		Namespace:   namespace,
+		Subsystem:   subsystem,
+		ConstLabels: constLabels,
+		Name:        "consume_record_latency_seconds",
+		Help:        "Time from a record's timestamp to when it was polled",
+	}), []string{"topic"})
 
 	// Produce
 
@@ -406,6 +436,8 @@ func (m *Metrics) OnNewClient(client *kgo.Client) {
 		m.readTimeSeconds,
 		m.requestDurationE2ESeconds,
 		m.requestThrottledSeconds,
+		m.produceRecordLatencySeconds,
+		m.consumeRecordLatencySeconds,
 		m.produceCompressedBytes,
 		m.produceUncompressedBytes,
 		m.produceBatchesTotal,
@@ -498,6 +530,63 @@ func (m *Metrics) OnFetchBatchRead(meta kgo.BrokerMetadata, topic string, _ int3
 	}
 }
 
+// RecordLatencyHooks returns the hooks that observe the ProduceRecordLatency
+// and ConsumeRecordLatency histograms, which must be passed to kgo.WithHooks
+// alongside the Metrics for those histograms to be recorded:
+//
+//	m := kprom.NewMetrics("namespace", kprom.Histograms(kprom.ProduceRecordLatency))
+//	cl, err := kgo.NewClient(
+//	        kgo.WithHooks(m),
+//	        kgo.WithHooks(m.RecordLatencyHooks()...),
+//	        // ...other opts
+//	)
+//
+// These hooks are called for every produced and consumed record, so they are
+// kept separate from the Metrics hook and are only returned for the record
+// latency histograms that are enabled.
+func (m *Metrics) RecordLatencyHooks() []kgo.Hook {
+	var hooks []kgo.Hook
+	if _, ok := m.cfg.histograms[ProduceRecordLatency]; ok {
+		hooks = append(hooks, produceLatencyHook{m})
+	}
+	if _, ok := m.cfg.histograms[ConsumeRecordLatency]; ok {
+		hooks = append(hooks, consumeLatencyHook{m})
+	}
+	return hooks
+}
+
+type (
+	produceLatencyHook struct{ m *Metrics }
+	consumeLatencyHook struct{ m *Metrics }
+)
+
+func (h produceLatencyHook) OnProduceRecordUnbuffered(r *kgo.Record, err error) {
+	if err != nil {
+		return
+	}
+	h.m.observe(h.m.produceRecordLatencySeconds.WithLabelValues(r.Topic), r.Context, time.Since(r.Timestamp).Seconds())
+}
+
+func (h consumeLatencyHook) OnFetchRecordUnbuffered(r *kgo.Record, polled bool) {
+	if !polled {
+		return
+	}
+	h.m.observe(h.m.consumeRecordLatencySeconds.WithLabelValues(r.Topic), r.Context, time.Since(r.Timestamp).Seconds())
+}
+
+// observe observes v, attaching an exemplar if the context has one.
+func (m *Metrics) observe(o prometheus.Observer, ctx context.Context, v float64) {
+	if m.cfg.exemplars != nil && ctx != nil {
+		if eo, ok := o.(prometheus.ExemplarObserver); ok {
+			if labels := m.cfg.exemplars(ctx); len(labels) > 0 {
+				eo.ObserveWithExemplar(v, labels)
+				return
+			}
+		}
+	}
+	o.Observe(v)
+}
+
 // // Nop hook for compat, logic moved to OnBrokerE2E
 func (m *Metrics) OnBrokerRead(meta kgo.BrokerMetadata, _ int16, bytesRead int, _, _ time.Duration, err error) {
 }
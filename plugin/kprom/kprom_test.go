@@ -0,0 +1,139 @@
+package kprom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestRecordLatencyHooks(t *testing.T) {
+	for _, test := range []struct {
+		name        string
+		histograms  []Histogram
+		expProduce  bool
+		expConsume  bool
+		expNumHooks int
+	}{
+		{"none", nil, false, false, 0},
+		{"broker_only", []Histogram{ReadTime, WriteTime}, false, false, 0},
+		{"produce", []Histogram{ProduceRecordLatency}, true, false, 1},
+		{"consume", []Histogram{ConsumeRecordLatency}, false, true, 1},
+		{"both", []Histogram{ProduceRecordLatency, ConsumeRecordLatency}, true, true, 2},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			m := NewMetrics("test", Histograms(test.histograms...))
+			hooks := m.RecordLatencyHooks()
+			if len(hooks) != test.expNumHooks {
+				t.Errorf("got %d hooks != exp %d", len(hooks), test.expNumHooks)
+			}
+			var gotProduce, gotConsume bool
+			for _, h := range hooks {
+				_, isProduce := h.(kgo.HookProduceRecordUnbuffered)
+				_, isConsume := h.(kgo.HookFetchRecordUnbuffered)
+				gotProduce = gotProduce || isProduce
+				gotConsume = gotConsume || isConsume
+			}
+			if gotProduce != test.expProduce || gotConsume != test.expConsume {
+				t.Errorf("got produce %v, consume %v != exp %v, %v", gotProduce, gotConsume, test.expProduce, test.expConsume)
+			}
+		})
+	}
+
+	// Metrics itself must not implement the per-record hooks, otherwise
+	// every client using kprom pays for a hook call per record.
+	var m any = new(Metrics)
+	if _, ok := m.(kgo.HookProduceRecordUnbuffered); ok {
+		t.Error("Metrics unexpectedly implements HookProduceRecordUnbuffered")
+	}
+	if _, ok := m.(kgo.HookFetchRecordUnbuffered); ok {
+		t.Error("Metrics unexpectedly implements HookFetchRecordUnbuffered")
+	}
+}
+
+type ctxKey struct{}
+
+func TestRecordLatencyHistograms(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	m := NewMetrics("test",
+		Registry(reg),
+		Histograms(ProduceRecordLatency, ConsumeRecordLatency),
+		WithExemplars(func(ctx context.Context) prometheus.Labels {
+			if id, _ := ctx.Value(ctxKey{}).(string); id != "" {
+				return prometheus.Labels{"trace_id": id}
+			}
+			return nil
+		}),
+	)
+	cl, err := kgo.NewClient(kgo.WithHooks(m), kgo.WithHooks(m.RecordLatencyHooks()...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cl.Close()
+
+	ts := time.Now().Add(-time.Second)
+	traced := context.WithValue(context.Background(), ctxKey{}, "abc")
+	for _, h := range m.RecordLatencyHooks() {
+		switch h := h.(type) {
+		case kgo.HookProduceRecordUnbuffered:
+			h.OnProduceRecordUnbuffered(&kgo.Record{Topic: "foo", Timestamp: ts, Context: traced}, nil)
+			h.OnProduceRecordUnbuffered(&kgo.Record{Topic: "foo", Timestamp: ts, Context: context.Background()}, nil)
+			h.OnProduceRecordUnbuffered(&kgo.Record{Topic: "foo", Timestamp: ts}, kgo.ErrRecordTimeout) // failed: not observed
+		case kgo.HookFetchRecordUnbuffered:
+			h.OnFetchRecordUnbuffered(&kgo.Record{Topic: "bar", Timestamp: ts}, true)
+			h.OnFetchRecordUnbuffered(&kgo.Record{Topic: "bar", Timestamp: ts}, false) // not polled: not observed
+		}
+	}
+
+	mfs, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found int
+	for _, mf := range mfs {
+		var exp uint64
+		var expTopic string
+		switch mf.GetName() {
+		case "test_produce_record_latency_seconds":
+			exp, expTopic = 2, "foo"
+		case "test_consume_record_latency_seconds":
+			exp, expTopic = 1, "bar"
+		default:
+			continue
+		}
+		found++
+		ms := mf.GetMetric()
+		if len(ms) != 1 {
+			t.Fatalf("%s: got %d metrics != exp 1", mf.GetName(), len(ms))
+		}
+		if got := ms[0].GetLabel()[0].GetValue(); got != expTopic {
+			t.Errorf("%s: got topic %q != exp %q", mf.GetName(), got, expTopic)
+		}
+		h := ms[0].GetHistogram()
+		if got := h.GetSampleCount(); got != exp {
+			t.Errorf("%s: got %d samples != exp %d", mf.GetName(), got, exp)
+		}
+		if h.GetSampleSum() < float64(exp) {
+			t.Errorf("%s: got sample sum %v, exp at least %d seconds", mf.GetName(), h.GetSampleSum(), exp)
+		}
+
+		var exemplars int
+		for _, b := range h.GetBucket() {
+			if e := b.GetExemplar(); e != nil {
+				exemplars++
+				if l := e.GetLabel(); len(l) != 1 || l[0].GetName() != "trace_id" || l[0].GetValue() != "abc" {
+					t.Errorf("%s: unexpected exemplar labels %v", mf.GetName(), l)
+				}
+			}
+		}
+		if expExemplars := map[string]int{"foo": 1, "bar": 0}[expTopic]; exemplars != expExemplars {
+			t.Errorf("%s: got %d exemplars != exp %d", mf.GetName(), exemplars, expExemplars)
+		}
+	}
+	if found != 2 {
+		t.Errorf("found %d record latency histograms != exp 2", found)
+	}
+}
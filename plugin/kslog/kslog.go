@@ -7,6 +7,10 @@
 //	        kgo.WithLogger(kslog.New(slog.Default())),
 //	        // ...other opts
 //	)
+//
+// To change levels at runtime, or to debug a single part of the client without
+// debug logs from everything else, use the WithLevels option with a Levels
+// handle.
 package kslog
 
 import (
@@ -20,15 +24,40 @@ import (
 // initializing a client.
 type Logger struct {
 	sl *slog.Logger
+
+	levels *Levels
 }
 
 // New returns a new kgo.Logger that wraps an slog.Logger.
-func New(sl *slog.Logger) *Logger {
-	return &Logger{sl}
+func New(sl *slog.Logger, opts ...Opt) *Logger {
+	l := &Logger{sl: sl}
+	for _, opt := range opts {
+		opt.apply(l)
+	}
+	return l
+}
+
+// Opt applies options to the logger.
+type Opt interface {
+	apply(*Logger)
+}
+
+type opt struct{ fn func(*Logger) }
+
+func (o opt) apply(l *Logger) { o.fn(l) }
+
+// WithLevels uses levels to decide what to log, rather than the levels enabled
+// on the slog.Logger. The slog handler still filters what it receives, so it
+// must be enabled at the most verbose level you intend to set in levels.
+func WithLevels(levels *Levels) Opt {
+	return opt{func(l *Logger) { l.levels = levels }}
 }
 
 // Level is for the kgo.Logger interface.
 func (l *Logger) Level() kgo.LogLevel {
+	if l.levels != nil {
+		return l.levels.max()
+	}
 	ctx := context.Background()
 	switch {
 	case l.sl.Enabled(ctx, slog.LevelDebug):
@@ -46,6 +75,9 @@ func (l *Logger) Level() kgo.LogLevel {
 
 // Log is for the kgo.Logger interface.
 func (l *Logger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if l.levels != nil && !l.levels.enabled(level, msg, keyvals) {
+		return
+	}
 	l.sl.Log(context.Background(), kgoToSlogLevel(level), msg, keyvals...)
 }
 
@@ -1,7 +1,10 @@
 package kslog_test
 
 import (
+	"bytes"
 	"log/slog"
+	"strings"
+	"testing"
 
 	"github.com/twmb/franz-go/pkg/kgo"
 	"github.com/twmb/franz-go/plugin/kslog"
@@ -13,3 +16,64 @@ func ExampleNew() {
 	l.Log(kgo.LogLevelInfo, "test message", "test-key", "test-val")
 	// Output:
 }
+
+func ExampleWithLevels() {
+	levels := kslog.NewLevels(kgo.LogLevelInfo)
+	l := kslog.New(slog.Default(), kslog.WithLevels(levels))
+
+	// Debug only the group, leaving everything else at info.
+	levels.SetSubsystemLevel(kslog.SubsystemGroup, kgo.LogLevelDebug)
+
+	l.Log(kgo.LogLevelDebug, "heartbeat finished", "group", "g")
+	// Output:
+}
+
+func TestLevels(t *testing.T) {
+	var buf bytes.Buffer
+	sl := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	levels := kslog.NewLevels(kgo.LogLevelInfo)
+	l := kslog.New(sl, kslog.WithLevels(levels))
+
+	if got := l.Level(); got != kgo.LogLevelInfo {
+		t.Errorf("got level %v != exp info", got)
+	}
+	levels.SetSubsystemLevel(kslog.SubsystemFetch, kgo.LogLevelDebug)
+	levels.SetSubsystemLevel(kslog.SubsystemMetadata, kgo.LogLevelError)
+	if got := l.Level(); got != kgo.LogLevelDebug {
+		t.Errorf("got level %v != exp debug", got)
+	}
+
+	for _, log := range []struct {
+		level kgo.LogLevel
+		msg   string
+		kvs   []any
+	}{
+		{kgo.LogLevelDebug, "updating fetch offsets", nil},                 // fetch at debug: logged
+		{kgo.LogLevelDebug, "heartbeating", []any{"group", "g"}},           // group at base info: dropped
+		{kgo.LogLevelInfo, "metadata update triggered", nil},               // metadata at error: dropped
+		{kgo.LogLevelInfo, "producing to a new partition", nil},            // produce at base info: logged
+		{kgo.LogLevelDebug, "opening connection to broker", nil},           // unclassified at base info: dropped
+		{kgo.LogLevelInfo, "assigning partitions", []any{"how", "assign"}}, // group at base info: logged
+	} {
+		l.Log(log.level, log.msg, log.kvs...)
+	}
+
+	out := buf.String()
+	for _, exp := range []string{"updating fetch offsets", "producing to a new partition", "assigning partitions"} {
+		if !strings.Contains(out, exp) {
+			t.Errorf("missing expected log %q in %s", exp, out)
+		}
+	}
+	for _, unexp := range []string{"heartbeating", "metadata update", "opening connection"} {
+		if strings.Contains(out, unexp) {
+			t.Errorf("unexpected log %q in %s", unexp, out)
+		}
+	}
+
+	buf.Reset()
+	levels.ClearSubsystemLevel(kslog.SubsystemFetch)
+	l.Log(kgo.LogLevelDebug, "updating fetch offsets")
+	if buf.Len() != 0 {
+		t.Errorf("unexpected log after clearing fetch level: %s", buf.String())
+	}
+}
@@ -0,0 +1,39 @@
+package kslog
+
+import "testing"
+
+func TestSubsystemOf(t *testing.T) {
+	for _, test := range []struct {
+		msg string
+		kvs []any
+		exp Subsystem
+	}{
+		// Messages the client logs, and where they belong.
+		{"metadata update triggered", []any{"why", "x"}, SubsystemMetadata},
+		{"immediate metadata update had inner errors, re-updating", nil, SubsystemMetadata},
+		{"metadata leader epoch went backwards, ignoring update", nil, SubsystemMetadata},
+		{"produce request failed, triggering metadata update", []any{"broker", "1"}, SubsystemProduce},
+		{"producing to a new topic for the first time, fetching metadata to learn its partitions", nil, SubsystemProduce},
+		{"in commitTransactionOffsets", []any{"with", nil}, SubsystemProduce},
+		{"end transaction with commit unknown server error; retrying", nil, SubsystemProduce},
+		{"heartbeating", []any{"group", "g"}, SubsystemGroup},
+		{"synced", []any{"group", "g"}, SubsystemGroup},
+		{"grabbed join/sync mu on first try", nil, SubsystemGroup},
+		{"blocking commits from join&sync", nil, SubsystemGroup},
+		{"group members indicated interest in topics the leader is not assigned, fetching metadata for all group topics", nil, SubsystemGroup},
+		{"metadata resp in balance for topic has error, skipping...", []any{"topic", "t"}, SubsystemGroup},
+		{"unable to commit offset for topic partition", nil, SubsystemGroup},
+		{"updating fetch offsets", nil, SubsystemFetch},
+		{"fetch offsets failed with UnstableOffsetCommit, waiting 1s and retrying", nil, SubsystemGroup},
+		{"listing offsets", nil, SubsystemFetch},
+
+		// Substrings inside other words do not classify.
+		{"asynchronous close", nil, -1},
+		{"disjoint brokers", nil, -1},
+		{"opening connection to broker", nil, -1},
+	} {
+		if got := subsystemOf(test.msg, test.kvs); got != test.exp {
+			t.Errorf("%q: got subsystem %d != exp %d", test.msg, got, test.exp)
+		}
+	}
+}
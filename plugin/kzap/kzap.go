@@ -12,6 +12,9 @@
 // the zap logger, and then sticks with that level forever. A variable level
 // can be chosen by specifying the LevelFn option. See the documentation on
 // Level or LevelFn for more info.
+//
+// To set different levels for different parts of the client, such as debug
+// logs for only the consumer group, use the WithLevels option.
 package kzap
 
 import (
@@ -20,12 +23,15 @@ import (
 	"github.com/twmb/franz-go/pkg/kgo"
 )
 
+//go:generate sh -c "sed 's/^package kslog$/package kzap/' ../kslog/levels.go > levels.go"
+
 // Logger provides the kgo.Logger interface for usage in kgo.WithLogger when
 // initializing a client.
 type Logger struct {
 	zl *zap.Logger
 
 	levelFn func() kgo.LogLevel
+	levels  *Levels
 }
 
 // New returns a new logger that checks the enabled log level on every log.
@@ -93,13 +99,28 @@ func Level(level kgo.LogLevel) Opt {
 	return LevelFn(func() kgo.LogLevel { return level })
 }
 
+// WithLevels uses levels for the kgo.Logger Level function and additionally
+// filters each log by the level of the subsystem it belongs to. This overrides
+// LevelFn, AtomicLevel, and Level. The zap core still filters what it
+// receives, so it must be enabled at the most verbose level you intend to set
+// in levels.
+func WithLevels(levels *Levels) Opt {
+	return opt{func(l *Logger) { l.levels = levels }}
+}
+
 // Level is for the kgo.Logger interface.
 func (l *Logger) Level() kgo.LogLevel {
+	if l.levels != nil {
+		return l.levels.max()
+	}
 	return l.levelFn()
 }
 
 // Log is for the kgo.Logger interface.
 func (l *Logger) Log(level kgo.LogLevel, msg string, keyvals ...any) {
+	if l.levels != nil && !l.levels.enabled(level, msg, keyvals) {
+		return
+	}
 	fields := make([]zap.Field, 0, len(keyvals)/2)
 	for i := 0; i < len(keyvals); i += 2 {
 		k, v := keyvals[i], keyvals[i+1]
@@ -0,0 +1,59 @@
+package kzap
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// levels.go is generated from kslog's levels.go, which holds the tests for
+// classifying logs; this ensures the copy is not stale.
+func TestLevelsGenerated(t *testing.T) {
+	src, err := os.ReadFile("../kslog/levels.go")
+	if err != nil {
+		t.Skipf("unable to read kslog's levels.go: %v", err)
+	}
+	dst, err := os.ReadFile("levels.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src = bytes.Replace(src, []byte("package kslog\n"), []byte("package kzap\n"), 1)
+	if !bytes.Equal(src, dst) {
+		t.Error("levels.go is stale, run go generate")
+	}
+}
+
+func TestLevels(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	levels := NewLevels(kgo.LogLevelInfo)
+	l := New(zap.New(core), WithLevels(levels))
+
+	levels.SetSubsystemLevel(SubsystemGroup, kgo.LogLevelDebug)
+	if got := l.Level(); got != kgo.LogLevelDebug {
+		t.Errorf("got level %v != exp debug", got)
+	}
+
+	l.Log(kgo.LogLevelDebug, "heartbeating", "group", "g")     // group at debug: logged
+	l.Log(kgo.LogLevelDebug, "updating fetch offsets")         // fetch at base info: dropped
+	l.Log(kgo.LogLevelInfo, "metadata update triggered")       // metadata at base info: logged
+	l.Log(kgo.LogLevelDebug, "asynchronous close of a broker") // unclassified at base info: dropped
+
+	var got []string
+	for _, e := range logs.All() {
+		got = append(got, e.Message)
+	}
+	if len(got) != 2 || got[0] != "heartbeating" || got[1] != "metadata update triggered" {
+		t.Errorf("got logs %q, exp heartbeating and metadata update triggered", got)
+	}
+
+	levels.ClearSubsystemLevel(SubsystemGroup)
+	if got := l.Level(); got != kgo.LogLevelInfo {
+		t.Errorf("got level %v != exp info after clearing the group level", got)
+	}
+}
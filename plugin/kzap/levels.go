@@ -0,0 +1,161 @@
+package kzap
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// This file is shared by kslog and kzap: kslog's copy is the source, and
+// kzap's copy is generated from it with go generate.
+
+// Subsystem is a part of the client that logs, used to set log levels for
+// only that part of the client.
+type Subsystem int8
+
+const (
+	// SubsystemMetadata is metadata loading and broker discovery.
+	SubsystemMetadata Subsystem = iota
+	// SubsystemProduce is producing, including transactions.
+	SubsystemProduce
+	// SubsystemFetch is fetching and consuming outside of a group.
+	SubsystemFetch
+	// SubsystemGroup is joining, heartbeating, and committing in a
+	// consumer group.
+	SubsystemGroup
+
+	numSubsystems
+)
+
+// Levels is an atomic handle for log levels that can be changed at any time,
+// including while a client is running. Levels has a base level that applies
+// to everything, and optional per-subsystem levels that override the base.
+//
+// For example, to debug only group management, set the base level to
+// kgo.LogLevelInfo and the SubsystemGroup level to kgo.LogLevelDebug.
+type Levels struct {
+	base atomic.Int32
+	subs [numSubsystems]atomic.Int32 // level+1; 0 means unset
+}
+
+// NewLevels returns a new Levels using level as the base level.
+func NewLevels(level kgo.LogLevel) *Levels {
+	l := new(Levels)
+	l.SetLevel(level)
+	return l
+}
+
+// SetLevel sets the base level.
+func (l *Levels) SetLevel(level kgo.LogLevel) {
+	l.base.Store(int32(level))
+}
+
+// SetSubsystemLevel sets the level for a subsystem, overriding the base level.
+func (l *Levels) SetSubsystemLevel(s Subsystem, level kgo.LogLevel) {
+	if s >= 0 && s < numSubsystems {
+		l.subs[s].Store(int32(level) + 1)
+	}
+}
+
+// ClearSubsystemLevel removes a subsystem level, returning the subsystem to
+// the base level.
+func (l *Levels) ClearSubsystemLevel(s Subsystem) {
+	if s >= 0 && s < numSubsystems {
+		l.subs[s].Store(0)
+	}
+}
+
+// Level returns the level in use for a subsystem.
+func (l *Levels) Level(s Subsystem) kgo.LogLevel {
+	if s >= 0 && s < numSubsystems {
+		if v := l.subs[s].Load(); v > 0 {
+			return kgo.LogLevel(v - 1)
+		}
+	}
+	return kgo.LogLevel(l.base.Load())
+}
+
+// max returns the highest level across the base and all subsystems, which is
+// what the client must log at for every subsystem to receive its logs.
+func (l *Levels) max() kgo.LogLevel {
+	m := kgo.LogLevel(l.base.Load())
+	for i := range l.subs {
+		if v := kgo.LogLevel(l.subs[i].Load() - 1); v > m {
+			m = v
+		}
+	}
+	return m
+}
+
+// enabled returns whether a message at the given level should be logged,
+// classifying the message into a subsystem.
+func (l *Levels) enabled(level kgo.LogLevel, msg string, keyvals []any) bool {
+	return level <= l.Level(subsystemOf(msg, keyvals))
+}
+
+// subsystemOf classifies a client log message. The client does not tag logs
+// with a subsystem, so this relies on the message and its keys; messages that
+// do not fit anywhere return -1 and use the base level.
+//
+// Words in the message are matched by prefix, so that "sync" matches
+// "syncing" but not "async"; camel cased function names are split into
+// words. Subsystems are checked in order: producing
+// (which includes transactional offset commits), then the group (a "group"
+// key or group words win over incidental mentions of metadata), then
+// metadata, then fetching.
+func subsystemOf(msg string, keyvals []any) Subsystem {
+	words := logWords(msg)
+	has := func(prefixes ...string) bool {
+		for _, w := range words {
+			for _, prefix := range prefixes {
+				if strings.HasPrefix(w, prefix) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	if has("produc", "txn", "transact", "idempoten", "linger", "buffered") {
+		return SubsystemProduce
+	}
+	for i := 0; i < len(keyvals); i += 2 {
+		if k, _ := keyvals[i].(string); k == "group" {
+			return SubsystemGroup
+		}
+	}
+	switch {
+	case has("group", "heartbeat", "rebalanc", "join", "rejoin", "sync", "leav", "commit", "autocommit", "uncommitted", "assign", "revok", "balanc"):
+		return SubsystemGroup
+	case has("metadata"):
+		return SubsystemMetadata
+	case has("fetch", "consum", "cursor", "epoch", "list"):
+		return SubsystemFetch
+	}
+	return -1
+}
+
+// logWords splits msg into lowercase words, splitting on anything that is not
+// a letter or digit and between a lowercase and uppercase letter.
+func logWords(msg string) []string {
+	var (
+		words []string
+		start = -1
+		prev  rune
+	)
+	for i, r := range msg {
+		alnum := 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'
+		if start >= 0 && (!alnum || 'a' <= prev && prev <= 'z' && 'A' <= r && r <= 'Z') {
+			words = append(words, strings.ToLower(msg[start:i]))
+			start = -1
+		}
+		if alnum && start < 0 {
+			start = i
+		}
+		prev = r
+	}
+	if start >= 0 {
+		words = append(words, strings.ToLower(msg[start:]))
+	}
+	return words
+}